@@ -23,6 +23,41 @@ import (
 
 const ConditionReady = "Ready"
 
+// ConditionDegraded is true when the device can be read but writing the desired settings to it fails,
+// the status continues to be refreshed while the device is read-only.
+const ConditionDegraded = "Degraded"
+
+// ConditionConfigSchemaChanged is true when the config sections and keys reported by the device differ from the
+// ones previously observed, e.g. after a firmware update performed out-of-band. It remains true until the change is
+// acknowledged by setting the ConfigSchemaAcknowledgedAnnotation to the new status.configSchemaHash.
+const ConditionConfigSchemaChanged = "ConfigSchemaChanged"
+
+// ConditionQuarantined is true once reconciling the device failed too many times in a row. A quarantined device
+// is only probed at a slow interval, or when its spec changes, until a reconcile succeeds again.
+const ConditionQuarantined = "Quarantined"
+
+// ConditionSynced is true when the state observed on the device matches the spec, expanded with its profile. It is
+// false with the DriftDetected reason while settings differ, the differences are listed in status.drift.
+const ConditionSynced = "Synced"
+
+// ReasonDriftDetected is the reason of the Synced condition while the device state differs from the spec.
+const ReasonDriftDetected = "DriftDetected"
+
+// ConfigSchemaAcknowledgedAnnotation acknowledges a config schema change when set to the reported hash.
+const ConfigSchemaAcknowledgedAnnotation = "jumperless.detiber.us/config-schema-acknowledged"
+
+// TakeoverAnnotation hands the device over to the manager whose identity it is set to, even if the lease in
+// status.heldBy is held by another manager and has not expired.
+const TakeoverAnnotation = "jumperless.detiber.us/takeover"
+
+// MeasureAnnotation takes the measurements of spec.measurements again when set to a new value, e.g. a timestamp.
+// The value the measurements were taken for is recorded in status.measurements.
+const MeasureAnnotation = "jumperless.detiber.us/measure"
+
+// CaptureLabel allows the captures of a Jumperless to write to an existing ConfigMap that it doesn't own when set
+// on the ConfigMap to the name of the Jumperless.
+const CaptureLabel = "jumperless.detiber.us/capture"
+
 // DACChannel represents the available DAC channels.
 //
 //go:generate stringer -type=DACChannel
@@ -37,6 +72,17 @@ const (
 
 var DACChannels = []DACChannel{DAC0, DAC1, TOP_RAIL, BOTTOM_RAIL} //nolint:gochecknoglobals
 
+// ParseDACChannel returns the DAC channel with the given name, e.g. "DAC0" or "TOP_RAIL".
+func ParseDACChannel(name string) (DACChannel, bool) {
+	for _, channel := range DACChannels {
+		if channel.String() == name {
+			return channel, true
+		}
+	}
+
+	return 0, false
+}
+
 // DAC represents a single DAC channel configuration.
 type DAC struct {
 	// Channel is the DAC channel to set.
@@ -49,7 +95,7 @@ type DAC struct {
 	// The value is a string representing a quantity, e.g. "3.3V", "0.5V", "-1.2V".
 	// Valid range is from -8V to +8V.
 	// Examples of valid values: "0V", "3.3V", "-1.5V", "7.8V"
-	// Examples of invalid values: "10V", "-9V", "3.33V", "abc"
+	// Examples of invalid values: "10V", "-9V", "3.333V", "abc"
 	// +kubebuilder:validation:Pattern=`^(-?([0-7](\.[0-9]{1,2})?|8(\.0{1,2})?))V$`
 	// +required
 	Voltage string `json:"voltage"`
@@ -62,17 +108,120 @@ type DAC struct {
 	Save *bool `json:"save,omitempty"`
 }
 
+// Display defines the settings for the top OLED display.
+type Display struct {
+	// Enabled indicates whether the top OLED display is enabled.
+	// +optional
+	Enabled *bool `json:"enabled,omitempty"`
+
+	// Text is the text to show on the top OLED display.
+	// +kubebuilder:validation:MaxLength=64
+	// +optional
+	Text *string `json:"text,omitempty"`
+
+	// Font is the name of the font to use for the top OLED display, e.g. "jokerman".
+	// +kubebuilder:validation:MaxLength=32
+	// +kubebuilder:validation:Pattern=`^[a-z_ ]+$`
+	// +optional
+	Font *string `json:"font,omitempty"`
+
+	// Brightness is the brightness of the LEDs and display, from 0 to 100.
+	// +kubebuilder:validation:Minimum=0
+	// +kubebuilder:validation:Maximum=100
+	// +optional
+	Brightness *int32 `json:"brightness,omitempty"`
+}
+
+// Probe defines the settings for the probe.
+type Probe struct {
+	// PowerDAC is the DAC channel used to power the probe.
+	// Valid values are "DAC0", "DAC1".
+	// +kubebuilder:validation:Enum=DAC0;DAC1
+	// +optional
+	PowerDAC *string `json:"powerDAC,omitempty"`
+
+	// SwitchThreshold is the voltage threshold used to detect the probe switch position.
+	// The value is a string representing a quantity, e.g. "0.5V".
+	// Examples of valid values: "0.5V", "1.25V"
+	// +kubebuilder:validation:Pattern=`^([0-7](\.[0-9]{1,2})?|8(\.0{1,2})?)V$`
+	// +optional
+	SwitchThreshold *string `json:"switchThreshold,omitempty"`
+}
+
+// UARTBridge configures the USB-UART passthrough of the device, which bridges the second USB serial
+// port to the UART_TX and UART_RX nodes.
+type UARTBridge struct {
+	// Enabled indicates whether the USB-UART passthrough is enabled.
+	// +optional
+	Enabled *bool `json:"enabled,omitempty"`
+
+	// BaudRate is the baud rate of the UART.
+	// Common values are 9600, 19200, 38400, 57600, 115200.
+	// +kubebuilder:validation:Minimum=300
+	// +kubebuilder:validation:Maximum=4000000
+	// +optional
+	BaudRate *int32 `json:"baudRate,omitempty"`
+
+	// TXNode is the breadboard node to connect to UART_TX, e.g. "D1" or "25".
+	// +kubebuilder:validation:MaxLength=16
+	// +kubebuilder:validation:Pattern=`^[A-Za-z0-9_]+$`
+	// +optional
+	TXNode *string `json:"txNode,omitempty"`
+
+	// RXNode is the breadboard node to connect to UART_RX, e.g. "D0" or "26".
+	// +kubebuilder:validation:MaxLength=16
+	// +kubebuilder:validation:Pattern=`^[A-Za-z0-9_]+$`
+	// +optional
+	RXNode *string `json:"rxNode,omitempty"`
+}
+
+// Connection connects two nodes of the breadboard.
+type Connection struct {
+	// From is the first node to connect, e.g. "D2", "15" or "GPIO_1".
+	// +kubebuilder:validation:MinLength=1
+	// +required
+	From string `json:"from"`
+
+	// To is the second node to connect.
+	// +kubebuilder:validation:MinLength=1
+	// +required
+	To string `json:"to"`
+}
+
 // JumperlessHost represents a host that is connected to the Jumperless device.
 type JumperlessHost struct {
 	// Local specifies that the Jumperless device is connected via a local serial port.
-	// Exactly one of Local or SSH must be specified.
+	// Exactly one of Local, SSH or Agent must be specified.
 	// +optional
 	Local *JumperlessHostLocal `json:"local,omitempty"`
 
 	// SSH specifies that the Jumperless device is connected via SSH to a remote host.
-	// Exactly one of Local or SSH must be specified.
+	// Exactly one of Local, SSH or Agent must be specified.
 	// +optional
 	SSH *JumperlessHostSSH `json:"ssh,omitempty"`
+
+	// Agent specifies that the Jumperless device is driven through the Remote gRPC service of an agent or
+	// proxy, e.g. the proxy of jumperless-utils running on the host the device is attached to.
+	// Exactly one of Local, SSH or Agent must be specified.
+	// +optional
+	Agent *JumperlessHostAgent `json:"agent,omitempty"`
+}
+
+type JumperlessHostAgent struct {
+	// Endpoint is the address of the Remote gRPC service, e.g. "jumperless-proxy.lab:9445".
+	// +kubebuilder:validation:MinLength=1
+	// +required
+	Endpoint string `json:"endpoint"`
+
+	// Port is the serial port of the device as reported by the service.
+	// If Port is not specified, the service must report exactly one device.
+	// +optional
+	Port *string `json:"port,omitempty"`
+
+	// BaudRate is the baud rate the service opens the serial port with.
+	// +default=115200
+	// +optional
+	BaudRate *int32 `json:"baudRate,omitempty"`
 }
 
 type JumperlessHostSSH struct {
@@ -98,14 +247,197 @@ type JumperlessHostSSH struct {
 
 type JumperlessHostLocal struct {
 	// Port is the local serial port that is connected to the Jumperless device.
+	// Prefer a stable path such as /dev/serial/by-id/..., since /dev/ttyACM* numbering
+	// may change across reboots.
+	// If Port is not specified, the serial ports matching SerialNumber, VID and PID are probed.
 	// +optional
 	Port *string `json:"port,omitempty"`
 
+	// SerialNumber is the USB serial number of the Jumperless device.
+	// Only used when Port is not specified.
+	// +optional
+	SerialNumber *string `json:"serialNumber,omitempty"`
+
+	// VID is the USB vendor ID of the Jumperless device as a hexadecimal string, e.g. "2E8A".
+	// Only used when Port is not specified.
+	// +kubebuilder:validation:Pattern=`^[0-9a-fA-F]{4}$`
+	// +optional
+	VID *string `json:"vid,omitempty"`
+
+	// PID is the USB product ID of the Jumperless device as a hexadecimal string.
+	// Only used when Port is not specified.
+	// +kubebuilder:validation:Pattern=`^[0-9a-fA-F]{4}$`
+	// +optional
+	PID *string `json:"pid,omitempty"`
+
 	// BaudRate is the baud rate to use when connecting to the local serial port.
 	// Common values are 9600, 19200, 38400, 57600, 115200.
 	// +default=115200
 	// +optional
 	BaudRate *int32 `json:"baudRate,omitempty"`
+
+	// NodeName is the node the Jumperless device is attached to. If set, the serial port is driven by the
+	// agent running on that node on behalf of the manager, and Port, SerialNumber, VID and PID refer to the
+	// serial ports of that node. If not set, the device is attached to the node the manager runs on.
+	// +optional
+	NodeName *string `json:"nodeName,omitempty"`
+}
+
+// BootstrapResult is the outcome of bootstrapping a device.
+// +kubebuilder:validation:Enum=Applied;Skipped
+type BootstrapResult string
+
+const (
+	// BootstrapApplied means the device was factory-fresh and the baseline was applied.
+	BootstrapApplied BootstrapResult = "Applied"
+
+	// BootstrapSkipped means the device was not factory-fresh, so the baseline was not applied.
+	BootstrapSkipped BootstrapResult = "Skipped"
+)
+
+// Bootstrap defines a baseline applied once to a factory-fresh device, e.g. when provisioning new boards.
+type Bootstrap struct {
+	// Profile is the name of the baseline. A device is bootstrapped once per profile, changing the name
+	// bootstraps the device again if it is still factory-fresh.
+	// +kubebuilder:validation:MinLength=1
+	// +kubebuilder:validation:MaxLength=63
+	// +required
+	Profile string `json:"profile"`
+
+	// DACS are the rail and DAC voltages of the baseline, they are saved to the device config unless Save is false.
+	// +listType=map
+	// +listMapKey=channel
+	// +optional
+	DACS []DAC `json:"dacs,omitempty"`
+
+	// Display defines the display settings of the baseline.
+	// +optional
+	Display *Display `json:"display,omitempty"`
+
+	// FactoryConfig are the config entries identifying a factory-fresh device, the baseline is only applied if
+	// the device config matches all of them.
+	// Defaults to the rail, DAC and display settings of the firmware default config.
+	// +listType=map
+	// +listMapKey=name
+	// +optional
+	FactoryConfig []JumperLessConfigSection `json:"factoryConfig,omitempty"`
+}
+
+// ProfileReference references a JumperlessProfile in the same namespace.
+type ProfileReference struct {
+	// Name is the name of the JumperlessProfile.
+	// +kubebuilder:validation:MinLength=1
+	// +required
+	Name string `json:"name"`
+}
+
+// ReconcilePolicy defines how the controller handles differences between the spec and the device.
+// +kubebuilder:validation:Enum=Enforce;DetectOnly
+type ReconcilePolicy string
+
+const (
+	// ReconcilePolicyEnforce writes the settings of the spec to the device whenever they differ.
+	ReconcilePolicyEnforce ReconcilePolicy = "Enforce"
+
+	// ReconcilePolicyDetectOnly only reports the differences in status.drift, nothing is written to the device.
+	ReconcilePolicyDetectOnly ReconcilePolicy = "DetectOnly"
+)
+
+// Measurement requests a burst of samples of an ADC channel.
+// +kubebuilder:validation:XValidation:rule="!has(self.interval) || duration(self.interval) <= duration('1s')",message="interval must not be longer than 1s"
+type Measurement struct {
+	// Name identifies the measurement in status.measurements.
+	// +kubebuilder:validation:MinLength=1
+	// +kubebuilder:validation:MaxLength=63
+	// +required
+	Name string `json:"name"`
+
+	// Channel is the ADC channel to sample, from 0 to 7.
+	// +kubebuilder:validation:Minimum=0
+	// +kubebuilder:validation:Maximum=7
+	// +required
+	Channel int32 `json:"channel"`
+
+	// Samples is the number of samples to take.
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Maximum=100
+	// +default=10
+	// +optional
+	Samples int32 `json:"samples,omitempty"`
+
+	// Interval is the time between two samples, up to a second. Defaults to 100ms.
+	// +optional
+	Interval *metav1.Duration `json:"interval,omitempty"`
+}
+
+// CaptureTarget is where the samples of a capture are written. Exactly one of ConfigMap or File must be set.
+// +kubebuilder:validation:XValidation:rule="has(self.configMap) != has(self.file)",message="exactly one of configMap or file must be set"
+type CaptureTarget struct {
+	// ConfigMap writes the samples to a ConfigMap in the namespace of the Jumperless, which is created owned by
+	// the Jumperless if it doesn't exist. An existing ConfigMap must be owned by the Jumperless or carry the
+	// jumperless.detiber.us/capture label set to its name, the capture fails otherwise. Starting a capture
+	// replaces the samples of its key. A ConfigMap holds up to 1MiB, the capture fails once the samples don't fit.
+	// +optional
+	ConfigMap *ConfigMapCaptureTarget `json:"configMap,omitempty"`
+
+	// File writes the samples to a file on the host of the manager, below the directory set with --capture-dir.
+	// +optional
+	File *FileCaptureTarget `json:"file,omitempty"`
+}
+
+// ConfigMapCaptureTarget writes the samples of a capture to a key of a ConfigMap.
+type ConfigMapCaptureTarget struct {
+	// Name is the name of the ConfigMap.
+	// +kubebuilder:validation:MinLength=1
+	// +kubebuilder:validation:MaxLength=253
+	// +required
+	Name string `json:"name"`
+
+	// Key is the key of the samples in the ConfigMap. Defaults to the name of the capture with a ".csv" suffix.
+	// +kubebuilder:validation:MaxLength=253
+	// +kubebuilder:validation:Pattern=`^[-._a-zA-Z0-9]+$`
+	// +optional
+	Key string `json:"key,omitempty"`
+}
+
+// FileCaptureTarget writes the samples of a capture to a file.
+type FileCaptureTarget struct {
+	// Path is the path of the file, relative to the directory of the Jumperless in the capture directory of the
+	// manager, i.e. <capture-dir>/<namespace>/<name>/<path>.
+	// +kubebuilder:validation:MinLength=1
+	// +kubebuilder:validation:MaxLength=255
+	// +kubebuilder:validation:XValidation:rule="!self.startsWith('/') && !self.split('/').exists(p, p == '..')",message="path must be relative and must not contain '..'"
+	// +required
+	Path string `json:"path"`
+}
+
+// Capture requests continuous sampling of an ADC channel for a duration, streaming the samples to a target.
+// +kubebuilder:validation:XValidation:rule="!has(self.interval) || duration(self.interval) >= duration('10ms')",message="interval must be at least 10ms"
+// +kubebuilder:validation:XValidation:rule="duration(self.duration) <= duration('1h')",message="duration must not be longer than 1h"
+type Capture struct {
+	// Name identifies the capture. A capture is taken once, changing the name starts a new capture.
+	// +kubebuilder:validation:MinLength=1
+	// +kubebuilder:validation:MaxLength=63
+	// +required
+	Name string `json:"name"`
+
+	// Channel is the ADC channel to sample, from 0 to 7.
+	// +kubebuilder:validation:Minimum=0
+	// +kubebuilder:validation:Maximum=7
+	// +required
+	Channel int32 `json:"channel"`
+
+	// Interval is the time between two samples, at least 10ms. Defaults to 10ms.
+	// +optional
+	Interval *metav1.Duration `json:"interval,omitempty"`
+
+	// Duration is how long the channel is sampled, up to an hour.
+	// +required
+	Duration metav1.Duration `json:"duration"`
+
+	// Target is where the samples are written.
+	// +required
+	Target CaptureTarget `json:"target"`
 }
 
 // JumperlessSpec defines the desired state of Jumperless
@@ -126,6 +458,203 @@ type JumperlessSpec struct {
 	// +patchMergeKey=channel
 	// +optional
 	DACS []DAC `json:"dacs,omitempty" patchMergeKey:"channel" patchStrategy:"merge"`
+
+	// Display defines the settings for the top OLED display.
+	// Settings are written to the device config and reflected in status.config.
+	// +optional
+	Display *Display `json:"display,omitempty"`
+
+	// Probe defines the settings for the probe.
+	// Settings are written to the device config and reflected in status.config.
+	// +optional
+	Probe *Probe `json:"probe,omitempty"`
+
+	// UARTBridge defines the settings for the USB-UART passthrough.
+	// Settings are written to the device config, the nodes are connected to UART_TX and UART_RX
+	// and the resulting state is reflected in status.uartBridge.
+	// +optional
+	UARTBridge *UARTBridge `json:"uartBridge,omitempty"`
+
+	// Connections is a list of node pairs to connect.
+	// Nodes that are not connected are connected and the resulting nets are reflected in status.nets.
+	// Removing a connection from the list does not disconnect the nodes.
+	// +listType=atomic
+	// +optional
+	Connections []Connection `json:"connections,omitempty"`
+
+	// GPIO is a list of GPIO pins to drive.
+	// Pins that are not at the desired level are set to it.
+	// +listType=map
+	// +listMapKey=pin
+	// +optional
+	GPIO []GPIO `json:"gpio,omitempty"`
+
+	// ProfileRef references a JumperlessProfile in the same namespace whose DACs, GPIO levels and connections are
+	// applied along with the spec. Settings of the spec take precedence over the ones of the profile for the same
+	// DAC channel or GPIO pin, connections of both are made. The applied profile is recorded in status.profile.
+	// +optional
+	ProfileRef *ProfileReference `json:"profileRef,omitempty"`
+
+	// Bootstrap defines a baseline applied once to the device if it is factory-fresh, before the other settings
+	// of the spec are applied. The result is recorded in status.bootstrap.
+	// +optional
+	Bootstrap *Bootstrap `json:"bootstrap,omitempty"`
+
+	// ReconcilePolicy defines how differences between the spec and the device are handled.
+	// With Enforce the settings of the spec are written to the device, with DetectOnly the differences are only
+	// reported in status.drift and the Synced condition, and the device is not written to, including bootstrap.
+	// +default="Enforce"
+	// +optional
+	ReconcilePolicy ReconcilePolicy `json:"reconcilePolicy,omitempty"`
+
+	// Measurements are ADC sample bursts taken by the controller, the samples and their statistics are recorded
+	// in status.measurements. A measurement is taken when it is added or changed, and again whenever the
+	// MeasureAnnotation is set to a new value.
+	// +listType=map
+	// +listMapKey=name
+	// +kubebuilder:validation:MaxItems=8
+	// +optional
+	Measurements []Measurement `json:"measurements,omitempty"`
+
+	// Capture samples an ADC channel continuously for a duration, streaming the samples to a ConfigMap or a file.
+	// The progress of the capture is recorded in status.capture.
+	// +optional
+	Capture *Capture `json:"capture,omitempty"`
+}
+
+// BootstrapStatus records the bootstrap of a device.
+type BootstrapStatus struct {
+	// Profile is the name of the baseline the device was bootstrapped with.
+	// +required
+	Profile string `json:"profile"`
+
+	// Result is Applied if the baseline was applied, or Skipped if the device was not factory-fresh.
+	// +required
+	Result BootstrapResult `json:"result"`
+
+	// CompletionTime is the time the bootstrap completed.
+	// +required
+	CompletionTime metav1.Time `json:"completionTime"`
+}
+
+// ProfileStatus records the JumperlessProfile applied to a device.
+type ProfileStatus struct {
+	// Name is the name of the JumperlessProfile.
+	// +required
+	Name string `json:"name"`
+
+	// Generation is the metadata.generation of the JumperlessProfile that was applied, it identifies the
+	// revision of the profile that is active on the device.
+	// +required
+	Generation int64 `json:"generation"`
+}
+
+// DriftEntry is a setting of the spec that differs from the state observed on the device.
+type DriftEntry struct {
+	// Field identifies the setting, e.g. "dacs[DAC0]", "gpio[1]" or "config.top_oled.font".
+	// +required
+	Field string `json:"field"`
+
+	// Expected is the value of the setting in the spec, e.g. "3.30V".
+	// +required
+	Expected string `json:"expected"`
+
+	// Observed is the value observed on the device, e.g. "2.50V", or "unknown" if it couldn't be observed.
+	// +required
+	Observed string `json:"observed"`
+}
+
+// MeasurementResult records the samples taken for a measurement of the spec.
+type MeasurementResult struct {
+	// Name is the name of the measurement in the spec.
+	// +required
+	Name string `json:"name"`
+
+	// Channel is the ADC channel that was sampled.
+	// +required
+	Channel int32 `json:"channel"`
+
+	// Interval is the time between two samples.
+	// +required
+	Interval metav1.Duration `json:"interval"`
+
+	// Samples are the voltages that were read, in the order they were read, e.g. "3.30V".
+	// +listType=atomic
+	// +required
+	Samples []string `json:"samples"`
+
+	// Min is the lowest voltage that was read.
+	// +required
+	Min string `json:"min"`
+
+	// Max is the highest voltage that was read.
+	// +required
+	Max string `json:"max"`
+
+	// Average is the mean of the voltages that were read.
+	// +required
+	Average string `json:"average"`
+
+	// Trigger is the value of the MeasureAnnotation the measurement was taken for, empty if it wasn't set.
+	// +optional
+	Trigger string `json:"trigger,omitempty"`
+
+	// CompletionTime is the time the last sample was read.
+	// +required
+	CompletionTime metav1.Time `json:"completionTime"`
+}
+
+// CapturePhase is the phase of a capture.
+// +kubebuilder:validation:Enum=Running;Completed;Failed
+type CapturePhase string
+
+const (
+	// CaptureRunning means the channel is being sampled.
+	CaptureRunning CapturePhase = "Running"
+
+	// CaptureCompleted means the channel was sampled for the duration of the capture.
+	CaptureCompleted CapturePhase = "Completed"
+
+	// CaptureFailed means the samples couldn't be written to the target, the capture is not retried.
+	CaptureFailed CapturePhase = "Failed"
+)
+
+// CaptureStatus records the progress of a capture.
+type CaptureStatus struct {
+	// Name is the name of the capture in the spec.
+	// +required
+	Name string `json:"name"`
+
+	// Phase is Running while the channel is sampled, and Completed or Failed once the capture ended.
+	// +required
+	Phase CapturePhase `json:"phase"`
+
+	// Location is where the samples are written, e.g. "configmap://default/captures/startup.csv" or
+	// "file:///var/lib/jumperless/captures/default/bench-1/startup.csv".
+	// +optional
+	Location string `json:"location,omitempty"`
+
+	// Samples is the number of samples written to the target.
+	// +required
+	Samples int64 `json:"samples"`
+
+	// Progress is the percentage of the duration of the capture that has passed.
+	// +kubebuilder:validation:Minimum=0
+	// +kubebuilder:validation:Maximum=100
+	// +required
+	Progress int32 `json:"progress"`
+
+	// StartTime is the time the capture started.
+	// +required
+	StartTime metav1.Time `json:"startTime"`
+
+	// CompletionTime is the time the capture completed or failed.
+	// +optional
+	CompletionTime *metav1.Time `json:"completionTime,omitempty"`
+
+	// Message describes why the capture failed.
+	// +optional
+	Message string `json:"message,omitempty"`
 }
 
 // DACStatus defines the status of a single DAC channel.
@@ -136,14 +665,18 @@ type DACStatus struct {
 	// +required
 	Channel string `json:"channel"`
 
-	// Voltage is the desired voltage to set the DAC channel to.
-	// The value is a string representing a quantity, e.g. "3.3V", "0.5V", "-1.2V".
-	// Valid range is from -8V to +8V.
-	// Examples of valid values: "0V", "3.3V", "-1.5V", "7.8V"
-	// Examples of invalid values: "10V", "-9V", "3.33V", "abc"
+	// Voltage is the current voltage of the DAC channel as reported by the device.
+	// The value is normalized to two decimal places regardless of the firmware output format,
+	// e.g. "3.30V", "0.00V", "-1.20V".
 	// +kubebuilder:validation:Pattern=`^(-?([0-7](\.[0-9]{1,2})?|8(\.0{1,2})?))V$`
 	// +required
 	Voltage string `json:"voltage"`
+
+	// Millivolts is the current voltage of the DAC channel in millivolts, e.g. 3300 for "3.30V".
+	// +kubebuilder:validation:Minimum=-8000
+	// +kubebuilder:validation:Maximum=8000
+	// +optional
+	Millivolts *int32 `json:"millivolts,omitempty"`
 }
 
 type Net struct {
@@ -156,10 +689,8 @@ type Net struct {
 	Name string `json:"name"`
 
 	// Voltage is the voltage of the net.
-	// The value is a string representing a quantity, e.g. "3.3V", "0.5V", "-1.2V".
-	// Valid range is from -8V to +8V.
-	// Examples of valid values: "0V", "3.3V", "-1.5V", "7.8V"
-	// Examples of invalid values: "10V", "-9V", "3.33V", "abc"
+	// The value is normalized to two decimal places regardless of the firmware output format,
+	// e.g. "3.30V", "0.00V", "-1.20V".
 	// +kubebuilder:validation:Pattern=`^(-?([0-7](\.[0-9]{1,2})?|8(\.0{1,2})?))V$`
 	// +optional
 	Voltage *string `json:"voltage,omitempty"`
@@ -170,6 +701,7 @@ type Net struct {
 	Color *string `json:"color,omitempty"`
 
 	// Data includes any additional data associated with the net.
+	// Voltages measured on ADC nets are normalized like the net voltage, e.g. "-2.78V".
 	// This field is optional and may be empty.
 	// +optional
 	Data *string `json:"data,omitempty"`
@@ -197,6 +729,17 @@ type JumperLessConfigSection struct {
 	Entries []JumperlessConfigEntry `json:"entries,omitempty" patchMergeKey:"key" patchStrategy:"merge"`
 }
 
+// GetEntry returns the value of a configuration entry by key.
+func (j *JumperLessConfigSection) GetEntry(key string) (string, bool) {
+	for _, entry := range j.Entries {
+		if entry.Key == key {
+			return entry.Value, true
+		}
+	}
+
+	return "", false
+}
+
 // SetEntry sets or updates a configuration entry by key.
 // If the entry does not exist, it is added.
 func (j *JumperLessConfigSection) SetEntry(key, value string) {
@@ -223,6 +766,112 @@ type JumperlessConfigEntry struct {
 	Value string `json:"value"`
 }
 
+// DeviceIdentity describes the identity of the connected Jumperless hardware.
+type DeviceIdentity struct {
+	// SerialNumber is the USB serial number of the device.
+	// +optional
+	SerialNumber *string `json:"serialNumber,omitempty"`
+
+	// Generation is the hardware generation of the board.
+	// +optional
+	Generation *string `json:"generation,omitempty"`
+
+	// Revision is the hardware revision of the board.
+	// +optional
+	Revision *string `json:"revision,omitempty"`
+
+	// ProbeRevision is the hardware revision of the probe.
+	// +optional
+	ProbeRevision *string `json:"probeRevision,omitempty"`
+
+	// BootTime is the time the device was last started, derived from the device uptime.
+	// A boot time is reported rather than the uptime itself so the status remains stable between reconciliations.
+	// +optional
+	BootTime *metav1.Time `json:"bootTime,omitempty"`
+}
+
+// DeviceLease records the manager driving a device, so only one manager sends commands to it at a time.
+type DeviceLease struct {
+	// HolderIdentity is the identity of the manager driving the device, e.g. its hostname.
+	// +required
+	HolderIdentity string `json:"holderIdentity"`
+
+	// AcquireTime is the time the holder acquired the lease.
+	// +required
+	AcquireTime metav1.Time `json:"acquireTime"`
+
+	// RenewTime is the time the holder last renewed the lease.
+	// +required
+	RenewTime metav1.Time `json:"renewTime"`
+
+	// LeaseDurationSeconds is the time after RenewTime that the lease expires, once it expired another manager
+	// may take over the device.
+	// +kubebuilder:validation:Minimum=1
+	// +required
+	LeaseDurationSeconds int32 `json:"leaseDurationSeconds"`
+}
+
+// FailureHistory records the reconciles of a device that failed in a row.
+type FailureHistory struct {
+	// Consecutive is the number of reconciles that failed in a row.
+	// +kubebuilder:validation:Minimum=1
+	// +required
+	Consecutive int32 `json:"consecutive"`
+
+	// LastFailureTime is the time of the most recent failure.
+	// +required
+	LastFailureTime metav1.Time `json:"lastFailureTime"`
+
+	// Errors are the distinct errors of the failures, the most recent last.
+	// +kubebuilder:validation:MaxItems=10
+	// +listType=atomic
+	// +optional
+	Errors []FailureRecord `json:"errors,omitempty"`
+}
+
+// FailureRecord is an error reconciling a device and how often it occurred.
+type FailureRecord struct {
+	// Message is the error message.
+	// +required
+	Message string `json:"message"`
+
+	// Count is the number of failures with this error.
+	// +kubebuilder:validation:Minimum=1
+	// +required
+	Count int32 `json:"count"`
+
+	// LastTime is the time of the most recent failure with this error.
+	// +required
+	LastTime metav1.Time `json:"lastTime"`
+}
+
+// UARTBridgeStatus describes the observed state of the USB-UART passthrough.
+type UARTBridgeStatus struct {
+	// Enabled indicates whether the USB-UART passthrough is enabled in the device config.
+	// +required
+	Enabled bool `json:"enabled"`
+
+	// BaudRate is the baud rate of the UART from the device config.
+	// +optional
+	BaudRate *int32 `json:"baudRate,omitempty"`
+
+	// TXNodes are the nodes connected to UART_TX.
+	// +listType=set
+	// +optional
+	TXNodes []string `json:"txNodes,omitempty"`
+
+	// RXNodes are the nodes connected to UART_RX.
+	// +listType=set
+	// +optional
+	RXNodes []string `json:"rxNodes,omitempty"`
+
+	// PassthroughPort is the serial port of the USB-UART passthrough on the host of the manager holding the
+	// device, e.g. "/dev/ttyACM1", for other pods on that host to talk to the device under test. Only set while
+	// the passthrough is enabled and the port could be determined.
+	// +optional
+	PassthroughPort string `json:"passthroughPort,omitempty"`
+}
+
 // JumperlessStatus defines the observed state of Jumperless.
 type JumperlessStatus struct {
 	// For Kubernetes API conventions, see:
@@ -233,11 +882,26 @@ type JumperlessStatus struct {
 	// +optional
 	FirmwareVersion *string `json:"firmwareVersion,omitempty"`
 
+	// ControllerVersion is the build version of the manager that last reconciled the device.
+	// Together with ProtocolVersion it allows auditing the managers of a fleet running mixed versions.
+	// +optional
+	ControllerVersion *string `json:"controllerVersion,omitempty"`
+
+	// ProtocolVersion is the version of the serial protocol the manager that last reconciled the device uses to
+	// talk to it.
+	// +optional
+	ProtocolVersion *string `json:"protocolVersion,omitempty"`
+
 	// LocalPort is the name of the local serial port that is connected to the Jumperless device.
 	// This field is populated by the controller after successfully discovering the device.
 	// +optional
 	LocalPort *string `json:"localPort,omitempty"`
 
+	// Device is the identity of the connected Jumperless hardware.
+	// This field is populated by the controller after successfully connecting to the device.
+	// +optional
+	Device *DeviceIdentity `json:"device,omitempty"`
+
 	// DACS is a list of DAC channel statuses.
 	// Each entry reflects the current voltage setting for a specific channel.
 	// If multiple entries specify the same channel, the last one takes precedence.
@@ -257,6 +921,28 @@ type JumperlessStatus struct {
 	// +optional
 	Nets []Net `json:"nets,omitempty" patchMergeKey:"index" patchStrategy:"merge"`
 
+	// ConnectedNets is the number of nets connecting two or more nodes.
+	// This field is populated by the controller after successfully connecting to the device.
+	// +optional
+	ConnectedNets *int32 `json:"connectedNets,omitempty"`
+
+	// ObservedGeneration is the generation of the spec that was last applied to the device. It isn't advanced while
+	// the device or its profile is missing or writes fail, so the device reflects the latest spec once it matches
+	// metadata.generation.
+	// +optional
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+
+	// LastSyncTime is the time the settings of the spec were last written to the device, or the device was first
+	// found to match them. It isn't updated by reconciles finding the device in sync, so the status remains stable
+	// between reconciliations.
+	// +optional
+	LastSyncTime *metav1.Time `json:"lastSyncTime,omitempty"`
+
+	// LastError is the error of the last reconcile, or of writing the spec to a degraded device. It is cleared once
+	// the spec is applied.
+	// +optional
+	LastError *string `json:"lastError,omitempty"`
+
 	// Config is a list of configuration sections on the Jumperless device.
 	// This field is populated by the controller after successfully retrieving the configuration from the device.
 	// +listType=map
@@ -266,6 +952,59 @@ type JumperlessStatus struct {
 	// +optional
 	Config []JumperLessConfigSection `json:"config,omitempty" patchMergeKey:"name" patchStrategy:"merge"`
 
+	// ConfigSchemaHash is a hash of the configuration sections and keys reported by the device, ignoring their values.
+	// A change of the hash is reported by the ConfigSchemaChanged condition and an event.
+	// +optional
+	ConfigSchemaHash *string `json:"configSchemaHash,omitempty"`
+
+	// DisplayText is the text most recently written to the top OLED display.
+	// The device does not report the displayed text, so this reflects the last applied value.
+	// +optional
+	DisplayText *string `json:"displayText,omitempty"`
+
+	// UARTBridge is the state of the USB-UART passthrough.
+	// This field is populated by the controller after successfully retrieving the configuration and nets from the device.
+	// +optional
+	UARTBridge *UARTBridgeStatus `json:"uartBridge,omitempty"`
+
+	// Bootstrap records the bootstrap of the device with the baseline in spec.bootstrap.
+	// +optional
+	Bootstrap *BootstrapStatus `json:"bootstrap,omitempty"`
+
+	// Profile is the JumperlessProfile referenced by spec.profileRef that was last applied to the device.
+	// +optional
+	Profile *ProfileStatus `json:"profile,omitempty"`
+
+	// Drift lists the settings of the spec that differ from the state observed on the device. With the Enforce
+	// reconcile policy it is cleared once the settings were written, with DetectOnly it is kept until the device
+	// or the spec changes.
+	// +listType=map
+	// +listMapKey=field
+	// +optional
+	Drift []DriftEntry `json:"drift,omitempty"`
+
+	// Measurements are the results of the measurements in spec.measurements, results of measurements that were
+	// removed from the spec are dropped.
+	// +listType=map
+	// +listMapKey=name
+	// +optional
+	Measurements []MeasurementResult `json:"measurements,omitempty"`
+
+	// Capture is the progress of the capture in spec.capture, it is kept once the capture ended.
+	// +optional
+	Capture *CaptureStatus `json:"capture,omitempty"`
+
+	// HeldBy is the lease of the manager driving the device.
+	// Managers don't drive a device while another manager holds an unexpired lease, unless the TakeoverAnnotation
+	// hands the device over to them.
+	// +optional
+	HeldBy *DeviceLease `json:"heldBy,omitempty"`
+
+	// Failures records the reconciles that failed in a row, it is cleared once a reconcile succeeds.
+	// A device is quarantined once the number of failures reaches the threshold of the manager.
+	// +optional
+	Failures *FailureHistory `json:"failures,omitempty"`
+
 	// conditions represent the current state of the Jumperless resource.
 	// Each condition has a unique type and reflects the status of a specific aspect of the resource.
 	//
@@ -286,9 +1025,9 @@ type JumperlessStatus struct {
 // SetConfigEntry sets or updates a configuration entry in a specific section.
 // If the section does not exist, it is created.
 func (s *JumperlessStatus) SetConfigEntry(sectionName, key, value string) {
-	for _, sec := range s.Config {
-		if sec.Name == sectionName {
-			sec.SetEntry(key, value)
+	for i := range s.Config {
+		if s.Config[i].Name == sectionName {
+			s.Config[i].SetEntry(key, value)
 			return
 		}
 	}
@@ -304,6 +1043,17 @@ func (s *JumperlessStatus) SetConfigEntry(sectionName, key, value string) {
 	})
 }
 
+// GetConfigEntry returns the value of a configuration entry in a specific section.
+func (s *JumperlessStatus) GetConfigEntry(sectionName, key string) (string, bool) {
+	for i := range s.Config {
+		if s.Config[i].Name == sectionName {
+			return s.Config[i].GetEntry(key)
+		}
+	}
+
+	return "", false
+}
+
 // UpsertConfig merges or adds configuration sections to the status.
 // Existing sections are updated with new entries, and new sections are appended.
 func (s *JumperlessStatus) UpsertConfig(config []JumperLessConfigSection) {
@@ -329,6 +1079,13 @@ func (s *JumperlessStatus) UpsertConfig(config []JumperLessConfigSection) {
 
 // +kubebuilder:object:root=true
 // +kubebuilder:subresource:status
+// +kubebuilder:storageversion
+// +kubebuilder:printcolumn:name="Ready",type=string,JSONPath=`.status.conditions[?(@.type=="Ready")].status`
+// +kubebuilder:printcolumn:name="Firmware",type=string,JSONPath=`.status.firmwareVersion`
+// +kubebuilder:printcolumn:name="Port",type=string,JSONPath=`.status.localPort`
+// +kubebuilder:printcolumn:name="Nets",type=integer,JSONPath=`.status.connectedNets`
+// +kubebuilder:printcolumn:name="Last Sync",type=date,JSONPath=`.status.lastSyncTime`
+// +kubebuilder:printcolumn:name="Age",type=date,JSONPath=`.metadata.creationTimestamp`
 
 // Jumperless is the Schema for the jumperlesses API
 type Jumperless struct {
@@ -0,0 +1,152 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v5alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// ConditionComplete is true once a JumperlessCommand ran successfully, and false once it failed.
+// Commands are never retried, since device commands are not necessarily idempotent.
+const ConditionComplete = "Complete"
+
+// I2CScan scans an I2C bus routed to two nodes of the breadboard for devices.
+type I2CScan struct {
+	// SDA is the node the data line of the bus is connected to, e.g. "20" or "D4".
+	// +kubebuilder:validation:MaxLength=16
+	// +kubebuilder:validation:Pattern=`^[A-Za-z0-9_]+$`
+	// +required
+	SDA string `json:"sda"`
+
+	// SCL is the node the clock line of the bus is connected to, e.g. "21" or "D5".
+	// +kubebuilder:validation:MaxLength=16
+	// +kubebuilder:validation:Pattern=`^[A-Za-z0-9_]+$`
+	// +required
+	SCL string `json:"scl"`
+}
+
+// JumperlessCommandSpec defines the command to run on a Jumperless device.
+// +kubebuilder:validation:XValidation:rule="self == oldSelf",message="spec is immutable"
+// +kubebuilder:validation:XValidation:rule="has(self.command) != has(self.i2cScan)",message="exactly one of command or i2cScan must be set"
+type JumperlessCommandSpec struct {
+	// JumperlessName is the name of the Jumperless resource, in the same namespace, to run the command on.
+	// +kubebuilder:validation:MinLength=1
+	// +required
+	JumperlessName string `json:"jumperlessName"`
+
+	// Command is the command to run, e.g. "dac_get(0)".
+	// It is sent as a Python command unless Raw is true.
+	// +kubebuilder:validation:MinLength=1
+	// +kubebuilder:validation:MaxLength=4096
+	// +optional
+	Command string `json:"command,omitempty"`
+
+	// I2CScan scans an I2C bus for devices instead of running a command, the addresses of the devices found
+	// are recorded in status.i2cDevices.
+	// +optional
+	I2CScan *I2CScan `json:"i2cScan,omitempty"`
+
+	// Raw sends the command as it is instead of as a Python command, e.g. for the firmware menu.
+	// +optional
+	Raw bool `json:"raw,omitempty"`
+
+	// Wait is how long to wait before reading the response.
+	// +optional
+	Wait *metav1.Duration `json:"wait,omitempty"`
+
+	// TTLSecondsAfterFinished is how long the JumperlessCommand is kept once it has completed,
+	// after which it is deleted. Defaults to an hour.
+	// +kubebuilder:validation:Minimum=0
+	// +default=3600
+	// +optional
+	TTLSecondsAfterFinished *int32 `json:"ttlSecondsAfterFinished,omitempty"`
+}
+
+// JumperlessCommandStatus defines the observed state of JumperlessCommand.
+type JumperlessCommandStatus struct {
+	// Output is the response of the device to the command.
+	// +optional
+	Output *string `json:"output,omitempty"`
+
+	// OutputTruncated is true if the response was too long to be stored in full.
+	// +optional
+	OutputTruncated bool `json:"outputTruncated,omitempty"`
+
+	// I2CDevices are the 7-bit addresses of the devices found by an I2C scan, e.g. "0x3C".
+	// +listType=atomic
+	// +optional
+	I2CDevices []string `json:"i2cDevices,omitempty"`
+
+	// Port is the local serial port the command ran on.
+	// +optional
+	Port *string `json:"port,omitempty"`
+
+	// StartTime is the time the controller started running the command.
+	// +optional
+	StartTime *metav1.Time `json:"startTime,omitempty"`
+
+	// CompletionTime is the time the command completed or failed.
+	// +optional
+	CompletionTime *metav1.Time `json:"completionTime,omitempty"`
+
+	// conditions represent the current state of the JumperlessCommand resource.
+	// +listType=map
+	// +listMapKey=type
+	// +patchStrategy=merge
+	// +patchMergeKey=type
+	// +optional
+	Conditions []metav1.Condition `json:"conditions,omitempty" patchMergeKey:"type" patchStrategy:"merge"`
+}
+
+// +kubebuilder:object:root=true
+// +kubebuilder:subresource:status
+// +kubebuilder:printcolumn:name="Jumperless",type=string,JSONPath=`.spec.jumperlessName`
+// +kubebuilder:printcolumn:name="Command",type=string,JSONPath=`.spec.command`
+// +kubebuilder:printcolumn:name="Complete",type=string,JSONPath=`.status.conditions[?(@.type=="Complete")].status`
+// +kubebuilder:printcolumn:name="Age",type=date,JSONPath=`.metadata.creationTimestamp`
+
+// JumperlessCommand is the Schema for the jumperlesscommands API.
+// A JumperlessCommand runs a single ad-hoc command on a Jumperless device through the controller,
+// so users don't need access to the serial port on the host and every command is recorded in the API.
+type JumperlessCommand struct {
+	metav1.TypeMeta `json:",inline"`
+
+	// metadata is a standard object metadata
+	// +optional
+	metav1.ObjectMeta `json:"metadata,omitempty,omitzero"`
+
+	// spec defines the command to run
+	// +required
+	Spec JumperlessCommandSpec `json:"spec"`
+
+	// status defines the observed state of JumperlessCommand
+	// +optional
+	Status JumperlessCommandStatus `json:"status,omitempty,omitzero"`
+}
+
+// +kubebuilder:object:root=true
+
+// JumperlessCommandList contains a list of JumperlessCommand
+type JumperlessCommandList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []JumperlessCommand `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&JumperlessCommand{}, &JumperlessCommandList{})
+}
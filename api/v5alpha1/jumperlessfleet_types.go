@@ -0,0 +1,141 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v5alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	// FleetLabel is set on Jumperless resources created or adopted by a JumperlessFleet.
+	// The value is the name of the JumperlessFleet.
+	FleetLabel = "jumperless.detiber.us/fleet"
+
+	// SerialNumberLabel is set on Jumperless resources created or adopted by a JumperlessFleet,
+	// and by the Jumperless controller once the device has been probed.
+	// The value is the USB serial number of the device, if known.
+	SerialNumberLabel = "jumperless.detiber.us/serial-number"
+
+	// FirmwareVersionLabel is set on Jumperless resources once the device has been probed.
+	// The value is the firmware version reported by the device.
+	FirmwareVersionLabel = "jumperless.detiber.us/firmware-version"
+
+	// GenerationLabel is set on Jumperless resources once the device has been probed.
+	// The value is the hardware generation reported by the device, if known.
+	GenerationLabel = "jumperless.detiber.us/generation"
+)
+
+// JumperlessFleetSpec defines the desired state of JumperlessFleet
+type JumperlessFleetSpec struct {
+	// TargetNamespace is the namespace in which Jumperless resources are created for discovered devices.
+	// +kubebuilder:validation:MinLength=1
+	// +required
+	TargetNamespace string `json:"targetNamespace"`
+
+	// BaudRate is the baud rate to use when probing serial ports.
+	// Common values are 9600, 19200, 38400, 57600, 115200.
+	// +default=115200
+	// +optional
+	BaudRate *int32 `json:"baudRate,omitempty"`
+
+	// DiscoveryInterval is how often the serial ports are enumerated to discover devices.
+	// +default="1m"
+	// +optional
+	DiscoveryInterval *metav1.Duration `json:"discoveryInterval,omitempty"`
+
+	// Labels are additional labels applied to the Jumperless resources created for discovered devices.
+	// +optional
+	Labels map[string]string `json:"labels,omitempty"`
+}
+
+// DiscoveredDevice describes a Jumperless device discovered by a JumperlessFleet.
+type DiscoveredDevice struct {
+	// Name is the name of the Jumperless resource for the device.
+	// +required
+	Name string `json:"name"`
+
+	// Port is the local serial port the device was discovered on.
+	// +required
+	Port string `json:"port"`
+
+	// SerialNumber is the USB serial number of the device, if known.
+	// +optional
+	SerialNumber *string `json:"serialNumber,omitempty"`
+
+	// FirmwareVersion is the firmware version reported by the device.
+	// +optional
+	FirmwareVersion *string `json:"firmwareVersion,omitempty"`
+}
+
+// JumperlessFleetStatus defines the observed state of JumperlessFleet.
+type JumperlessFleetStatus struct {
+	// Devices is the list of devices found during the most recent discovery.
+	// +listType=map
+	// +listMapKey=name
+	// +patchStrategy=merge
+	// +patchMergeKey=name
+	// +optional
+	Devices []DiscoveredDevice `json:"devices,omitempty" patchMergeKey:"name" patchStrategy:"merge"`
+
+	// LastDiscoveryTime is the time of the most recent discovery.
+	// +optional
+	LastDiscoveryTime *metav1.Time `json:"lastDiscoveryTime,omitempty"`
+
+	// conditions represent the current state of the JumperlessFleet resource.
+	// +listType=map
+	// +listMapKey=type
+	// +patchStrategy=merge
+	// +patchMergeKey=type
+	// +optional
+	Conditions []metav1.Condition `json:"conditions,omitempty" patchMergeKey:"type" patchStrategy:"merge"`
+}
+
+// +kubebuilder:object:root=true
+// +kubebuilder:subresource:status
+// +kubebuilder:resource:scope=Cluster
+
+// JumperlessFleet is the Schema for the jumperlessfleets API.
+// A JumperlessFleet discovers Jumperless devices attached to the node the controller runs on
+// and creates or adopts a Jumperless resource for each of them.
+type JumperlessFleet struct {
+	metav1.TypeMeta `json:",inline"`
+
+	// metadata is a standard object metadata
+	// +optional
+	metav1.ObjectMeta `json:"metadata,omitempty,omitzero"`
+
+	// spec defines the desired state of JumperlessFleet
+	// +required
+	Spec JumperlessFleetSpec `json:"spec"`
+
+	// status defines the observed state of JumperlessFleet
+	// +optional
+	Status JumperlessFleetStatus `json:"status,omitempty,omitzero"`
+}
+
+// +kubebuilder:object:root=true
+
+// JumperlessFleetList contains a list of JumperlessFleet
+type JumperlessFleetList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []JumperlessFleet `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&JumperlessFleet{}, &JumperlessFleetList{})
+}
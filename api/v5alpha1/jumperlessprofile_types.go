@@ -0,0 +1,95 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v5alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// GPIOLevel is the level a GPIO pin is driven to.
+// +kubebuilder:validation:Enum=High;Low
+type GPIOLevel string
+
+const (
+	GPIOHigh GPIOLevel = "High"
+	GPIOLow  GPIOLevel = "Low"
+)
+
+// GPIO drives a single GPIO pin of the device.
+type GPIO struct {
+	// Pin is the number of the GPIO pin, e.g. 1 for GPIO_1.
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Maximum=10
+	// +required
+	Pin int32 `json:"pin"`
+
+	// Level is the level the pin is driven to.
+	// Valid values are "High", "Low".
+	// +required
+	Level GPIOLevel `json:"level"`
+}
+
+// JumperlessProfileSpec defines a named set of settings that Jumperless resources can reference.
+type JumperlessProfileSpec struct {
+	// DACS are the DAC channel configurations of the profile.
+	// +listType=map
+	// +listMapKey=channel
+	// +optional
+	DACS []DAC `json:"dacs,omitempty"`
+
+	// GPIO are the levels the GPIO pins of the profile are driven to.
+	// +listType=map
+	// +listMapKey=pin
+	// +optional
+	GPIO []GPIO `json:"gpio,omitempty"`
+
+	// Connections are the node pairs the profile connects.
+	// +listType=atomic
+	// +optional
+	Connections []Connection `json:"connections,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+// +kubebuilder:printcolumn:name="Age",type=date,JSONPath=`.metadata.creationTimestamp`
+
+// JumperlessProfile is the Schema for the jumperlessprofiles API.
+// A JumperlessProfile holds DAC, GPIO and connection settings shared by the Jumperless resources referencing it
+// with spec.profileRef, e.g. the wiring of a test fixture used by several boards.
+type JumperlessProfile struct {
+	metav1.TypeMeta `json:",inline"`
+
+	// metadata is a standard object metadata
+	// +optional
+	metav1.ObjectMeta `json:"metadata,omitempty,omitzero"`
+
+	// spec defines the settings of the profile
+	// +required
+	Spec JumperlessProfileSpec `json:"spec"`
+}
+
+// +kubebuilder:object:root=true
+
+// JumperlessProfileList contains a list of JumperlessProfile
+type JumperlessProfileList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []JumperlessProfile `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&JumperlessProfile{}, &JumperlessProfileList{})
+}
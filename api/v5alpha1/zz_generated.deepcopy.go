@@ -25,6 +25,154 @@ import (
 	runtime "k8s.io/apimachinery/pkg/runtime"
 )
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Bootstrap) DeepCopyInto(out *Bootstrap) {
+	*out = *in
+	if in.DACS != nil {
+		in, out := &in.DACS, &out.DACS
+		*out = make([]DAC, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Display != nil {
+		in, out := &in.Display, &out.Display
+		*out = new(Display)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.FactoryConfig != nil {
+		in, out := &in.FactoryConfig, &out.FactoryConfig
+		*out = make([]JumperLessConfigSection, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Bootstrap.
+func (in *Bootstrap) DeepCopy() *Bootstrap {
+	if in == nil {
+		return nil
+	}
+	out := new(Bootstrap)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *BootstrapStatus) DeepCopyInto(out *BootstrapStatus) {
+	*out = *in
+	in.CompletionTime.DeepCopyInto(&out.CompletionTime)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BootstrapStatus.
+func (in *BootstrapStatus) DeepCopy() *BootstrapStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(BootstrapStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Capture) DeepCopyInto(out *Capture) {
+	*out = *in
+	if in.Interval != nil {
+		in, out := &in.Interval, &out.Interval
+		*out = new(v1.Duration)
+		**out = **in
+	}
+	out.Duration = in.Duration
+	in.Target.DeepCopyInto(&out.Target)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Capture.
+func (in *Capture) DeepCopy() *Capture {
+	if in == nil {
+		return nil
+	}
+	out := new(Capture)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CaptureStatus) DeepCopyInto(out *CaptureStatus) {
+	*out = *in
+	in.StartTime.DeepCopyInto(&out.StartTime)
+	if in.CompletionTime != nil {
+		in, out := &in.CompletionTime, &out.CompletionTime
+		*out = new(v1.Time)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CaptureStatus.
+func (in *CaptureStatus) DeepCopy() *CaptureStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(CaptureStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CaptureTarget) DeepCopyInto(out *CaptureTarget) {
+	*out = *in
+	if in.ConfigMap != nil {
+		in, out := &in.ConfigMap, &out.ConfigMap
+		*out = new(ConfigMapCaptureTarget)
+		**out = **in
+	}
+	if in.File != nil {
+		in, out := &in.File, &out.File
+		*out = new(FileCaptureTarget)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CaptureTarget.
+func (in *CaptureTarget) DeepCopy() *CaptureTarget {
+	if in == nil {
+		return nil
+	}
+	out := new(CaptureTarget)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ConfigMapCaptureTarget) DeepCopyInto(out *ConfigMapCaptureTarget) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ConfigMapCaptureTarget.
+func (in *ConfigMapCaptureTarget) DeepCopy() *ConfigMapCaptureTarget {
+	if in == nil {
+		return nil
+	}
+	out := new(ConfigMapCaptureTarget)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Connection) DeepCopyInto(out *Connection) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Connection.
+func (in *Connection) DeepCopy() *Connection {
+	if in == nil {
+		return nil
+	}
+	out := new(Connection)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *DAC) DeepCopyInto(out *DAC) {
 	*out = *in
@@ -48,6 +196,11 @@ func (in *DAC) DeepCopy() *DAC {
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *DACStatus) DeepCopyInto(out *DACStatus) {
 	*out = *in
+	if in.Millivolts != nil {
+		in, out := &in.Millivolts, &out.Millivolts
+		*out = new(int32)
+		**out = **in
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DACStatus.
@@ -60,6 +213,221 @@ func (in *DACStatus) DeepCopy() *DACStatus {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DeviceIdentity) DeepCopyInto(out *DeviceIdentity) {
+	*out = *in
+	if in.SerialNumber != nil {
+		in, out := &in.SerialNumber, &out.SerialNumber
+		*out = new(string)
+		**out = **in
+	}
+	if in.Generation != nil {
+		in, out := &in.Generation, &out.Generation
+		*out = new(string)
+		**out = **in
+	}
+	if in.Revision != nil {
+		in, out := &in.Revision, &out.Revision
+		*out = new(string)
+		**out = **in
+	}
+	if in.ProbeRevision != nil {
+		in, out := &in.ProbeRevision, &out.ProbeRevision
+		*out = new(string)
+		**out = **in
+	}
+	if in.BootTime != nil {
+		in, out := &in.BootTime, &out.BootTime
+		*out = (*in).DeepCopy()
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DeviceIdentity.
+func (in *DeviceIdentity) DeepCopy() *DeviceIdentity {
+	if in == nil {
+		return nil
+	}
+	out := new(DeviceIdentity)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DeviceLease) DeepCopyInto(out *DeviceLease) {
+	*out = *in
+	in.AcquireTime.DeepCopyInto(&out.AcquireTime)
+	in.RenewTime.DeepCopyInto(&out.RenewTime)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DeviceLease.
+func (in *DeviceLease) DeepCopy() *DeviceLease {
+	if in == nil {
+		return nil
+	}
+	out := new(DeviceLease)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DiscoveredDevice) DeepCopyInto(out *DiscoveredDevice) {
+	*out = *in
+	if in.SerialNumber != nil {
+		in, out := &in.SerialNumber, &out.SerialNumber
+		*out = new(string)
+		**out = **in
+	}
+	if in.FirmwareVersion != nil {
+		in, out := &in.FirmwareVersion, &out.FirmwareVersion
+		*out = new(string)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DiscoveredDevice.
+func (in *DiscoveredDevice) DeepCopy() *DiscoveredDevice {
+	if in == nil {
+		return nil
+	}
+	out := new(DiscoveredDevice)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Display) DeepCopyInto(out *Display) {
+	*out = *in
+	if in.Enabled != nil {
+		in, out := &in.Enabled, &out.Enabled
+		*out = new(bool)
+		**out = **in
+	}
+	if in.Text != nil {
+		in, out := &in.Text, &out.Text
+		*out = new(string)
+		**out = **in
+	}
+	if in.Font != nil {
+		in, out := &in.Font, &out.Font
+		*out = new(string)
+		**out = **in
+	}
+	if in.Brightness != nil {
+		in, out := &in.Brightness, &out.Brightness
+		*out = new(int32)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Display.
+func (in *Display) DeepCopy() *Display {
+	if in == nil {
+		return nil
+	}
+	out := new(Display)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DriftEntry) DeepCopyInto(out *DriftEntry) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DriftEntry.
+func (in *DriftEntry) DeepCopy() *DriftEntry {
+	if in == nil {
+		return nil
+	}
+	out := new(DriftEntry)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *FailureHistory) DeepCopyInto(out *FailureHistory) {
+	*out = *in
+	in.LastFailureTime.DeepCopyInto(&out.LastFailureTime)
+	if in.Errors != nil {
+		in, out := &in.Errors, &out.Errors
+		*out = make([]FailureRecord, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new FailureHistory.
+func (in *FailureHistory) DeepCopy() *FailureHistory {
+	if in == nil {
+		return nil
+	}
+	out := new(FailureHistory)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *FailureRecord) DeepCopyInto(out *FailureRecord) {
+	*out = *in
+	in.LastTime.DeepCopyInto(&out.LastTime)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new FailureRecord.
+func (in *FailureRecord) DeepCopy() *FailureRecord {
+	if in == nil {
+		return nil
+	}
+	out := new(FailureRecord)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *FileCaptureTarget) DeepCopyInto(out *FileCaptureTarget) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new FileCaptureTarget.
+func (in *FileCaptureTarget) DeepCopy() *FileCaptureTarget {
+	if in == nil {
+		return nil
+	}
+	out := new(FileCaptureTarget)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *GPIO) DeepCopyInto(out *GPIO) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GPIO.
+func (in *GPIO) DeepCopy() *GPIO {
+	if in == nil {
+		return nil
+	}
+	out := new(GPIO)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *I2CScan) DeepCopyInto(out *I2CScan) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new I2CScan.
+func (in *I2CScan) DeepCopy() *I2CScan {
+	if in == nil {
+		return nil
+	}
+	out := new(I2CScan)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *JumperLessConfigSection) DeepCopyInto(out *JumperLessConfigSection) {
 	*out = *in
@@ -107,6 +475,140 @@ func (in *Jumperless) DeepCopyObject() runtime.Object {
 	return nil
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *JumperlessCommand) DeepCopyInto(out *JumperlessCommand) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new JumperlessCommand.
+func (in *JumperlessCommand) DeepCopy() *JumperlessCommand {
+	if in == nil {
+		return nil
+	}
+	out := new(JumperlessCommand)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *JumperlessCommand) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *JumperlessCommandList) DeepCopyInto(out *JumperlessCommandList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]JumperlessCommand, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new JumperlessCommandList.
+func (in *JumperlessCommandList) DeepCopy() *JumperlessCommandList {
+	if in == nil {
+		return nil
+	}
+	out := new(JumperlessCommandList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *JumperlessCommandList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *JumperlessCommandSpec) DeepCopyInto(out *JumperlessCommandSpec) {
+	*out = *in
+	if in.I2CScan != nil {
+		in, out := &in.I2CScan, &out.I2CScan
+		*out = new(I2CScan)
+		**out = **in
+	}
+	if in.Wait != nil {
+		in, out := &in.Wait, &out.Wait
+		*out = new(v1.Duration)
+		**out = **in
+	}
+	if in.TTLSecondsAfterFinished != nil {
+		in, out := &in.TTLSecondsAfterFinished, &out.TTLSecondsAfterFinished
+		*out = new(int32)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new JumperlessCommandSpec.
+func (in *JumperlessCommandSpec) DeepCopy() *JumperlessCommandSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(JumperlessCommandSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *JumperlessCommandStatus) DeepCopyInto(out *JumperlessCommandStatus) {
+	*out = *in
+	if in.Output != nil {
+		in, out := &in.Output, &out.Output
+		*out = new(string)
+		**out = **in
+	}
+	if in.I2CDevices != nil {
+		in, out := &in.I2CDevices, &out.I2CDevices
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Port != nil {
+		in, out := &in.Port, &out.Port
+		*out = new(string)
+		**out = **in
+	}
+	if in.StartTime != nil {
+		in, out := &in.StartTime, &out.StartTime
+		*out = (*in).DeepCopy()
+	}
+	if in.CompletionTime != nil {
+		in, out := &in.CompletionTime, &out.CompletionTime
+		*out = (*in).DeepCopy()
+	}
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]v1.Condition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new JumperlessCommandStatus.
+func (in *JumperlessCommandStatus) DeepCopy() *JumperlessCommandStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(JumperlessCommandStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *JumperlessConfigEntry) DeepCopyInto(out *JumperlessConfigEntry) {
 	*out = *in
@@ -122,6 +624,130 @@ func (in *JumperlessConfigEntry) DeepCopy() *JumperlessConfigEntry {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *JumperlessFleet) DeepCopyInto(out *JumperlessFleet) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new JumperlessFleet.
+func (in *JumperlessFleet) DeepCopy() *JumperlessFleet {
+	if in == nil {
+		return nil
+	}
+	out := new(JumperlessFleet)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *JumperlessFleet) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *JumperlessFleetList) DeepCopyInto(out *JumperlessFleetList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]JumperlessFleet, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new JumperlessFleetList.
+func (in *JumperlessFleetList) DeepCopy() *JumperlessFleetList {
+	if in == nil {
+		return nil
+	}
+	out := new(JumperlessFleetList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *JumperlessFleetList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *JumperlessFleetSpec) DeepCopyInto(out *JumperlessFleetSpec) {
+	*out = *in
+	if in.BaudRate != nil {
+		in, out := &in.BaudRate, &out.BaudRate
+		*out = new(int32)
+		**out = **in
+	}
+	if in.DiscoveryInterval != nil {
+		in, out := &in.DiscoveryInterval, &out.DiscoveryInterval
+		*out = new(v1.Duration)
+		**out = **in
+	}
+	if in.Labels != nil {
+		in, out := &in.Labels, &out.Labels
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new JumperlessFleetSpec.
+func (in *JumperlessFleetSpec) DeepCopy() *JumperlessFleetSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(JumperlessFleetSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *JumperlessFleetStatus) DeepCopyInto(out *JumperlessFleetStatus) {
+	*out = *in
+	if in.Devices != nil {
+		in, out := &in.Devices, &out.Devices
+		*out = make([]DiscoveredDevice, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.LastDiscoveryTime != nil {
+		in, out := &in.LastDiscoveryTime, &out.LastDiscoveryTime
+		*out = (*in).DeepCopy()
+	}
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]v1.Condition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new JumperlessFleetStatus.
+func (in *JumperlessFleetStatus) DeepCopy() *JumperlessFleetStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(JumperlessFleetStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *JumperlessHost) DeepCopyInto(out *JumperlessHost) {
 	*out = *in
@@ -135,6 +761,11 @@ func (in *JumperlessHost) DeepCopyInto(out *JumperlessHost) {
 		*out = new(JumperlessHostSSH)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.Agent != nil {
+		in, out := &in.Agent, &out.Agent
+		*out = new(JumperlessHostAgent)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new JumperlessHost.
@@ -147,6 +778,31 @@ func (in *JumperlessHost) DeepCopy() *JumperlessHost {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *JumperlessHostAgent) DeepCopyInto(out *JumperlessHostAgent) {
+	*out = *in
+	if in.Port != nil {
+		in, out := &in.Port, &out.Port
+		*out = new(string)
+		**out = **in
+	}
+	if in.BaudRate != nil {
+		in, out := &in.BaudRate, &out.BaudRate
+		*out = new(int32)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new JumperlessHostAgent.
+func (in *JumperlessHostAgent) DeepCopy() *JumperlessHostAgent {
+	if in == nil {
+		return nil
+	}
+	out := new(JumperlessHostAgent)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *JumperlessHostLocal) DeepCopyInto(out *JumperlessHostLocal) {
 	*out = *in
@@ -155,11 +811,31 @@ func (in *JumperlessHostLocal) DeepCopyInto(out *JumperlessHostLocal) {
 		*out = new(string)
 		**out = **in
 	}
+	if in.SerialNumber != nil {
+		in, out := &in.SerialNumber, &out.SerialNumber
+		*out = new(string)
+		**out = **in
+	}
+	if in.VID != nil {
+		in, out := &in.VID, &out.VID
+		*out = new(string)
+		**out = **in
+	}
+	if in.PID != nil {
+		in, out := &in.PID, &out.PID
+		*out = new(string)
+		**out = **in
+	}
 	if in.BaudRate != nil {
 		in, out := &in.BaudRate, &out.BaudRate
 		*out = new(int32)
 		**out = **in
 	}
+	if in.NodeName != nil {
+		in, out := &in.NodeName, &out.NodeName
+		*out = new(string)
+		**out = **in
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new JumperlessHostLocal.
@@ -225,6 +901,96 @@ func (in *JumperlessList) DeepCopyObject() runtime.Object {
 	return nil
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *JumperlessProfile) DeepCopyInto(out *JumperlessProfile) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new JumperlessProfile.
+func (in *JumperlessProfile) DeepCopy() *JumperlessProfile {
+	if in == nil {
+		return nil
+	}
+	out := new(JumperlessProfile)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *JumperlessProfile) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *JumperlessProfileList) DeepCopyInto(out *JumperlessProfileList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]JumperlessProfile, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new JumperlessProfileList.
+func (in *JumperlessProfileList) DeepCopy() *JumperlessProfileList {
+	if in == nil {
+		return nil
+	}
+	out := new(JumperlessProfileList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *JumperlessProfileList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *JumperlessProfileSpec) DeepCopyInto(out *JumperlessProfileSpec) {
+	*out = *in
+	if in.DACS != nil {
+		in, out := &in.DACS, &out.DACS
+		*out = make([]DAC, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.GPIO != nil {
+		in, out := &in.GPIO, &out.GPIO
+		*out = make([]GPIO, len(*in))
+		copy(*out, *in)
+	}
+	if in.Connections != nil {
+		in, out := &in.Connections, &out.Connections
+		*out = make([]Connection, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new JumperlessProfileSpec.
+func (in *JumperlessProfileSpec) DeepCopy() *JumperlessProfileSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(JumperlessProfileSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *JumperlessSpec) DeepCopyInto(out *JumperlessSpec) {
 	*out = *in
@@ -236,6 +1002,53 @@ func (in *JumperlessSpec) DeepCopyInto(out *JumperlessSpec) {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
+	if in.Display != nil {
+		in, out := &in.Display, &out.Display
+		*out = new(Display)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Probe != nil {
+		in, out := &in.Probe, &out.Probe
+		*out = new(Probe)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.UARTBridge != nil {
+		in, out := &in.UARTBridge, &out.UARTBridge
+		*out = new(UARTBridge)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Connections != nil {
+		in, out := &in.Connections, &out.Connections
+		*out = make([]Connection, len(*in))
+		copy(*out, *in)
+	}
+	if in.GPIO != nil {
+		in, out := &in.GPIO, &out.GPIO
+		*out = make([]GPIO, len(*in))
+		copy(*out, *in)
+	}
+	if in.ProfileRef != nil {
+		in, out := &in.ProfileRef, &out.ProfileRef
+		*out = new(ProfileReference)
+		**out = **in
+	}
+	if in.Bootstrap != nil {
+		in, out := &in.Bootstrap, &out.Bootstrap
+		*out = new(Bootstrap)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Measurements != nil {
+		in, out := &in.Measurements, &out.Measurements
+		*out = make([]Measurement, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Capture != nil {
+		in, out := &in.Capture, &out.Capture
+		*out = new(Capture)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new JumperlessSpec.
@@ -256,15 +1069,32 @@ func (in *JumperlessStatus) DeepCopyInto(out *JumperlessStatus) {
 		*out = new(string)
 		**out = **in
 	}
+	if in.ControllerVersion != nil {
+		in, out := &in.ControllerVersion, &out.ControllerVersion
+		*out = new(string)
+		**out = **in
+	}
+	if in.ProtocolVersion != nil {
+		in, out := &in.ProtocolVersion, &out.ProtocolVersion
+		*out = new(string)
+		**out = **in
+	}
 	if in.LocalPort != nil {
 		in, out := &in.LocalPort, &out.LocalPort
 		*out = new(string)
 		**out = **in
 	}
+	if in.Device != nil {
+		in, out := &in.Device, &out.Device
+		*out = new(DeviceIdentity)
+		(*in).DeepCopyInto(*out)
+	}
 	if in.DACS != nil {
 		in, out := &in.DACS, &out.DACS
 		*out = make([]DACStatus, len(*in))
-		copy(*out, *in)
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
 	}
 	if in.Nets != nil {
 		in, out := &in.Nets, &out.Nets
@@ -273,6 +1103,20 @@ func (in *JumperlessStatus) DeepCopyInto(out *JumperlessStatus) {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
+	if in.ConnectedNets != nil {
+		in, out := &in.ConnectedNets, &out.ConnectedNets
+		*out = new(int32)
+		**out = **in
+	}
+	if in.LastSyncTime != nil {
+		in, out := &in.LastSyncTime, &out.LastSyncTime
+		*out = (*in).DeepCopy()
+	}
+	if in.LastError != nil {
+		in, out := &in.LastError, &out.LastError
+		*out = new(string)
+		**out = **in
+	}
 	if in.Config != nil {
 		in, out := &in.Config, &out.Config
 		*out = make([]JumperLessConfigSection, len(*in))
@@ -280,6 +1124,58 @@ func (in *JumperlessStatus) DeepCopyInto(out *JumperlessStatus) {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
+	if in.ConfigSchemaHash != nil {
+		in, out := &in.ConfigSchemaHash, &out.ConfigSchemaHash
+		*out = new(string)
+		**out = **in
+	}
+	if in.DisplayText != nil {
+		in, out := &in.DisplayText, &out.DisplayText
+		*out = new(string)
+		**out = **in
+	}
+	if in.UARTBridge != nil {
+		in, out := &in.UARTBridge, &out.UARTBridge
+		*out = new(UARTBridgeStatus)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Bootstrap != nil {
+		in, out := &in.Bootstrap, &out.Bootstrap
+		*out = new(BootstrapStatus)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Profile != nil {
+		in, out := &in.Profile, &out.Profile
+		*out = new(ProfileStatus)
+		**out = **in
+	}
+	if in.Drift != nil {
+		in, out := &in.Drift, &out.Drift
+		*out = make([]DriftEntry, len(*in))
+		copy(*out, *in)
+	}
+	if in.Measurements != nil {
+		in, out := &in.Measurements, &out.Measurements
+		*out = make([]MeasurementResult, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Capture != nil {
+		in, out := &in.Capture, &out.Capture
+		*out = new(CaptureStatus)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.HeldBy != nil {
+		in, out := &in.HeldBy, &out.HeldBy
+		*out = new(DeviceLease)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Failures != nil {
+		in, out := &in.Failures, &out.Failures
+		*out = new(FailureHistory)
+		(*in).DeepCopyInto(*out)
+	}
 	if in.Conditions != nil {
 		in, out := &in.Conditions, &out.Conditions
 		*out = make([]v1.Condition, len(*in))
@@ -299,6 +1195,48 @@ func (in *JumperlessStatus) DeepCopy() *JumperlessStatus {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Measurement) DeepCopyInto(out *Measurement) {
+	*out = *in
+	if in.Interval != nil {
+		in, out := &in.Interval, &out.Interval
+		*out = new(v1.Duration)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Measurement.
+func (in *Measurement) DeepCopy() *Measurement {
+	if in == nil {
+		return nil
+	}
+	out := new(Measurement)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MeasurementResult) DeepCopyInto(out *MeasurementResult) {
+	*out = *in
+	out.Interval = in.Interval
+	if in.Samples != nil {
+		in, out := &in.Samples, &out.Samples
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	in.CompletionTime.DeepCopyInto(&out.CompletionTime)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MeasurementResult.
+func (in *MeasurementResult) DeepCopy() *MeasurementResult {
+	if in == nil {
+		return nil
+	}
+	out := new(MeasurementResult)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *Net) DeepCopyInto(out *Net) {
 	*out = *in
@@ -333,3 +1271,123 @@ func (in *Net) DeepCopy() *Net {
 	in.DeepCopyInto(out)
 	return out
 }
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Probe) DeepCopyInto(out *Probe) {
+	*out = *in
+	if in.PowerDAC != nil {
+		in, out := &in.PowerDAC, &out.PowerDAC
+		*out = new(string)
+		**out = **in
+	}
+	if in.SwitchThreshold != nil {
+		in, out := &in.SwitchThreshold, &out.SwitchThreshold
+		*out = new(string)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Probe.
+func (in *Probe) DeepCopy() *Probe {
+	if in == nil {
+		return nil
+	}
+	out := new(Probe)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ProfileReference) DeepCopyInto(out *ProfileReference) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ProfileReference.
+func (in *ProfileReference) DeepCopy() *ProfileReference {
+	if in == nil {
+		return nil
+	}
+	out := new(ProfileReference)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ProfileStatus) DeepCopyInto(out *ProfileStatus) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ProfileStatus.
+func (in *ProfileStatus) DeepCopy() *ProfileStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(ProfileStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *UARTBridge) DeepCopyInto(out *UARTBridge) {
+	*out = *in
+	if in.Enabled != nil {
+		in, out := &in.Enabled, &out.Enabled
+		*out = new(bool)
+		**out = **in
+	}
+	if in.BaudRate != nil {
+		in, out := &in.BaudRate, &out.BaudRate
+		*out = new(int32)
+		**out = **in
+	}
+	if in.TXNode != nil {
+		in, out := &in.TXNode, &out.TXNode
+		*out = new(string)
+		**out = **in
+	}
+	if in.RXNode != nil {
+		in, out := &in.RXNode, &out.RXNode
+		*out = new(string)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new UARTBridge.
+func (in *UARTBridge) DeepCopy() *UARTBridge {
+	if in == nil {
+		return nil
+	}
+	out := new(UARTBridge)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *UARTBridgeStatus) DeepCopyInto(out *UARTBridgeStatus) {
+	*out = *in
+	if in.BaudRate != nil {
+		in, out := &in.BaudRate, &out.BaudRate
+		*out = new(int32)
+		**out = **in
+	}
+	if in.TXNodes != nil {
+		in, out := &in.TXNodes, &out.TXNodes
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.RXNodes != nil {
+		in, out := &in.RXNodes, &out.RXNodes
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new UARTBridgeStatus.
+func (in *UARTBridgeStatus) DeepCopy() *UARTBridgeStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(UARTBridgeStatus)
+	in.DeepCopyInto(out)
+	return out
+}
@@ -0,0 +1,376 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v5alpha2
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/conversion"
+
+	"github.com/detiber/k8s-jumperless/api/v5alpha1"
+	"github.com/detiber/k8s-jumperless/jumperless/voltage"
+)
+
+var ErrUnsupportedHub = errors.New("unsupported conversion hub")
+
+var _ conversion.Convertible = &Jumperless{}
+
+// ConvertTo converts the Jumperless to the v5alpha1 hub, formatting the voltages as strings.
+func (src *Jumperless) ConvertTo(dstRaw conversion.Hub) error {
+	dst, ok := dstRaw.(*v5alpha1.Jumperless)
+	if !ok {
+		return fmt.Errorf("%w: %T", ErrUnsupportedHub, dstRaw)
+	}
+
+	dst.ObjectMeta = *src.ObjectMeta.DeepCopy()
+
+	dst.Spec = v5alpha1.JumperlessSpec{
+		Host:            *src.Spec.Host.DeepCopy(),
+		DACS:            convertDACsTo(src.Spec.DACS),
+		Display:         src.Spec.Display.DeepCopy(),
+		UARTBridge:      src.Spec.UARTBridge.DeepCopy(),
+		Connections:     slices.Clone(src.Spec.Connections),
+		GPIO:            slices.Clone(src.Spec.GPIO),
+		ProfileRef:      src.Spec.ProfileRef.DeepCopy(),
+		ReconcilePolicy: src.Spec.ReconcilePolicy,
+		Measurements:    deepCopyMeasurements(src.Spec.Measurements),
+		Capture:         src.Spec.Capture.DeepCopy(),
+	}
+
+	if probe := src.Spec.Probe; probe != nil {
+		dst.Spec.Probe = &v5alpha1.Probe{PowerDAC: copyString(probe.PowerDAC)}
+		if probe.SwitchThreshold != nil {
+			threshold := probe.SwitchThreshold.String()
+			dst.Spec.Probe.SwitchThreshold = &threshold
+		}
+	}
+
+	if bootstrap := src.Spec.Bootstrap; bootstrap != nil {
+		dst.Spec.Bootstrap = &v5alpha1.Bootstrap{
+			Profile:       bootstrap.Profile,
+			DACS:          convertDACsTo(bootstrap.DACS),
+			Display:       bootstrap.Display.DeepCopy(),
+			FactoryConfig: deepCopyConfig(bootstrap.FactoryConfig),
+		}
+	}
+
+	status := src.Status.DeepCopy()
+	dst.Status = v5alpha1.JumperlessStatus{
+		FirmwareVersion:    status.FirmwareVersion,
+		ControllerVersion:  status.ControllerVersion,
+		ProtocolVersion:    status.ProtocolVersion,
+		LocalPort:          status.LocalPort,
+		Device:             status.Device,
+		ConnectedNets:      status.ConnectedNets,
+		ObservedGeneration: status.ObservedGeneration,
+		LastSyncTime:       status.LastSyncTime,
+		LastError:          status.LastError,
+		Config:             status.Config,
+		ConfigSchemaHash:   status.ConfigSchemaHash,
+		DisplayText:        status.DisplayText,
+		UARTBridge:         status.UARTBridge,
+		Bootstrap:          status.Bootstrap,
+		Profile:            status.Profile,
+		Drift:              status.Drift,
+		Capture:            status.Capture,
+		HeldBy:             status.HeldBy,
+		Failures:           status.Failures,
+		Conditions:         status.Conditions,
+	}
+
+	for _, dac := range status.DACS {
+		dst.Status.DACS = append(dst.Status.DACS, v5alpha1.DACStatus{
+			Channel:    dac.Channel,
+			Voltage:    dac.Voltage.String(),
+			Millivolts: ptr.To(dac.Voltage.Millivolts),
+		})
+	}
+
+	for _, net := range status.Nets {
+		converted := v5alpha1.Net{
+			Index: net.Index,
+			Name:  net.Name,
+			Color: net.Color,
+			Data:  net.Data,
+			Nodes: net.Nodes,
+		}
+		if net.Voltage != nil {
+			netVoltage := net.Voltage.String()
+			converted.Voltage = &netVoltage
+		}
+		dst.Status.Nets = append(dst.Status.Nets, converted)
+	}
+
+	for _, result := range status.Measurements {
+		converted := v5alpha1.MeasurementResult{
+			Name:           result.Name,
+			Channel:        result.Channel,
+			Interval:       result.Interval,
+			Samples:        make([]string, 0, len(result.Samples)),
+			Min:            result.Min.String(),
+			Max:            result.Max.String(),
+			Average:        result.Average.String(),
+			Trigger:        result.Trigger,
+			CompletionTime: result.CompletionTime,
+		}
+		for _, sample := range result.Samples {
+			converted.Samples = append(converted.Samples, sample.String())
+		}
+		dst.Status.Measurements = append(dst.Status.Measurements, converted)
+	}
+
+	return nil
+}
+
+// ConvertFrom converts the v5alpha1 hub to the Jumperless, parsing the voltage strings.
+func (dst *Jumperless) ConvertFrom(srcRaw conversion.Hub) error {
+	src, ok := srcRaw.(*v5alpha1.Jumperless)
+	if !ok {
+		return fmt.Errorf("%w: %T", ErrUnsupportedHub, srcRaw)
+	}
+
+	dst.ObjectMeta = *src.ObjectMeta.DeepCopy()
+
+	dacs, err := convertDACsFrom(src.Spec.DACS)
+	if err != nil {
+		return err
+	}
+
+	dst.Spec = JumperlessSpec{
+		Host:            *src.Spec.Host.DeepCopy(),
+		DACS:            dacs,
+		Display:         src.Spec.Display.DeepCopy(),
+		UARTBridge:      src.Spec.UARTBridge.DeepCopy(),
+		Connections:     slices.Clone(src.Spec.Connections),
+		GPIO:            slices.Clone(src.Spec.GPIO),
+		ProfileRef:      src.Spec.ProfileRef.DeepCopy(),
+		ReconcilePolicy: src.Spec.ReconcilePolicy,
+		Measurements:    deepCopyMeasurements(src.Spec.Measurements),
+		Capture:         src.Spec.Capture.DeepCopy(),
+	}
+
+	if probe := src.Spec.Probe; probe != nil {
+		dst.Spec.Probe = &Probe{PowerDAC: copyString(probe.PowerDAC)}
+		if probe.SwitchThreshold != nil {
+			threshold, err := parseVoltage(*probe.SwitchThreshold)
+			if err != nil {
+				return fmt.Errorf("unable to convert probe switch threshold: %w", err)
+			}
+			dst.Spec.Probe.SwitchThreshold = &threshold
+		}
+	}
+
+	if bootstrap := src.Spec.Bootstrap; bootstrap != nil {
+		dacs, err := convertDACsFrom(bootstrap.DACS)
+		if err != nil {
+			return fmt.Errorf("unable to convert bootstrap: %w", err)
+		}
+
+		dst.Spec.Bootstrap = &Bootstrap{
+			Profile:       bootstrap.Profile,
+			DACS:          dacs,
+			Display:       bootstrap.Display.DeepCopy(),
+			FactoryConfig: deepCopyConfig(bootstrap.FactoryConfig),
+		}
+	}
+
+	status := src.Status.DeepCopy()
+	dst.Status = JumperlessStatus{
+		FirmwareVersion:    status.FirmwareVersion,
+		ControllerVersion:  status.ControllerVersion,
+		ProtocolVersion:    status.ProtocolVersion,
+		LocalPort:          status.LocalPort,
+		Device:             status.Device,
+		ConnectedNets:      status.ConnectedNets,
+		ObservedGeneration: status.ObservedGeneration,
+		LastSyncTime:       status.LastSyncTime,
+		LastError:          status.LastError,
+		Config:             status.Config,
+		ConfigSchemaHash:   status.ConfigSchemaHash,
+		DisplayText:        status.DisplayText,
+		UARTBridge:         status.UARTBridge,
+		Bootstrap:          status.Bootstrap,
+		Profile:            status.Profile,
+		Drift:              status.Drift,
+		Capture:            status.Capture,
+		HeldBy:             status.HeldBy,
+		Failures:           status.Failures,
+		Conditions:         status.Conditions,
+	}
+
+	for _, dac := range status.DACS {
+		dacVoltage, err := parseVoltage(dac.Voltage)
+		if err != nil {
+			return fmt.Errorf("unable to convert voltage of DAC %s: %w", dac.Channel, err)
+		}
+		dst.Status.DACS = append(dst.Status.DACS, DACStatus{Channel: dac.Channel, Voltage: dacVoltage})
+	}
+
+	for _, net := range status.Nets {
+		converted := Net{
+			Index: net.Index,
+			Name:  net.Name,
+			Color: net.Color,
+			Data:  net.Data,
+			Nodes: net.Nodes,
+		}
+		if net.Voltage != nil {
+			netVoltage, err := parseVoltage(*net.Voltage)
+			if err != nil {
+				return fmt.Errorf("unable to convert voltage of net %d: %w", net.Index, err)
+			}
+			converted.Voltage = &netVoltage
+		}
+		dst.Status.Nets = append(dst.Status.Nets, converted)
+	}
+
+	for _, result := range status.Measurements {
+		converted, err := convertMeasurementResultFrom(result)
+		if err != nil {
+			return fmt.Errorf("unable to convert measurement %s: %w", result.Name, err)
+		}
+		dst.Status.Measurements = append(dst.Status.Measurements, converted)
+	}
+
+	return nil
+}
+
+func convertMeasurementResultFrom(result v5alpha1.MeasurementResult) (MeasurementResult, error) {
+	converted := MeasurementResult{
+		Name:           result.Name,
+		Channel:        result.Channel,
+		Interval:       result.Interval,
+		Samples:        make([]Voltage, 0, len(result.Samples)),
+		Trigger:        result.Trigger,
+		CompletionTime: result.CompletionTime,
+	}
+
+	for _, sample := range result.Samples {
+		sampleVoltage, err := parseVoltage(sample)
+		if err != nil {
+			return MeasurementResult{}, err
+		}
+		converted.Samples = append(converted.Samples, sampleVoltage)
+	}
+
+	var err error
+	if converted.Min, err = parseVoltage(result.Min); err != nil {
+		return MeasurementResult{}, err
+	}
+	if converted.Max, err = parseVoltage(result.Max); err != nil {
+		return MeasurementResult{}, err
+	}
+	if converted.Average, err = parseVoltage(result.Average); err != nil {
+		return MeasurementResult{}, err
+	}
+
+	return converted, nil
+}
+
+// parseVoltage parses a v5alpha1 voltage string, rounding it to the 10mV resolution of the device.
+func parseVoltage(s string) (Voltage, error) {
+	v, err := voltage.Parse(s)
+	if err != nil {
+		return Voltage{}, err //nolint:wrapcheck
+	}
+
+	return Voltage{Millivolts: voltage.Millivolts(v)}, nil
+}
+
+func convertDACsTo(dacs []DAC) []v5alpha1.DAC {
+	if dacs == nil {
+		return nil
+	}
+
+	converted := make([]v5alpha1.DAC, 0, len(dacs))
+	for _, dac := range dacs {
+		converted = append(converted, v5alpha1.DAC{
+			Channel: dac.Channel,
+			Voltage: dac.Voltage.String(),
+			Save:    copyBool(dac.Save),
+		})
+	}
+
+	return converted
+}
+
+func convertDACsFrom(dacs []v5alpha1.DAC) ([]DAC, error) {
+	if dacs == nil {
+		return nil, nil
+	}
+
+	converted := make([]DAC, 0, len(dacs))
+	for _, dac := range dacs {
+		dacVoltage, err := parseVoltage(dac.Voltage)
+		if err != nil {
+			return nil, fmt.Errorf("unable to convert voltage of DAC %s: %w", dac.Channel, err)
+		}
+
+		converted = append(converted, DAC{Channel: dac.Channel, Voltage: dacVoltage, Save: copyBool(dac.Save)})
+	}
+
+	return converted, nil
+}
+
+func deepCopyConfig(config []v5alpha1.JumperLessConfigSection) []v5alpha1.JumperLessConfigSection {
+	if config == nil {
+		return nil
+	}
+
+	copied := make([]v5alpha1.JumperLessConfigSection, len(config))
+	for i := range config {
+		config[i].DeepCopyInto(&copied[i])
+	}
+
+	return copied
+}
+
+func deepCopyMeasurements(measurements []v5alpha1.Measurement) []v5alpha1.Measurement {
+	if measurements == nil {
+		return nil
+	}
+
+	copied := make([]v5alpha1.Measurement, len(measurements))
+	for i := range measurements {
+		measurements[i].DeepCopyInto(&copied[i])
+	}
+
+	return copied
+}
+
+func copyString(s *string) *string {
+	if s == nil {
+		return nil
+	}
+
+	copied := *s
+
+	return &copied
+}
+
+func copyBool(b *bool) *bool {
+	if b == nil {
+		return nil
+	}
+
+	copied := *b
+
+	return &copied
+}
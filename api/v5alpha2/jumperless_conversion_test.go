@@ -0,0 +1,166 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v5alpha2_test
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	. "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/ptr"
+
+	"github.com/detiber/k8s-jumperless/api/v5alpha1"
+	"github.com/detiber/k8s-jumperless/api/v5alpha2"
+	"github.com/detiber/k8s-jumperless/jumperless/voltage"
+)
+
+func TestVoltageString(t *testing.T) {
+	tests := []struct {
+		millivolts int32
+		want       string
+	}{
+		{millivolts: 3300, want: "3.3V"},
+		{millivolts: -1250, want: "-1.25V"},
+		{millivolts: 0, want: "0V"},
+		{millivolts: 8000, want: "8V"},
+		{millivolts: 50, want: "0.05V"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.want, func(t *testing.T) {
+			g := NewWithT(t)
+
+			g.Expect(v5alpha2.Voltage{Millivolts: tt.millivolts}.String()).To(Equal(tt.want))
+		})
+	}
+}
+
+func TestConvertFrom(t *testing.T) {
+	tests := []struct {
+		name    string
+		voltage string
+		want    int32
+		err     error
+	}{
+		{name: "volts", voltage: "3.3V", want: 3300},
+		{name: "padded", voltage: "3.30V", want: 3300},
+		{name: "negative", voltage: "-0.5V", want: -500},
+		{name: "rounded", voltage: "1.006V", want: 1010},
+		{name: "invalid", voltage: "abc", err: voltage.ErrInvalidVoltage},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			src := &v5alpha1.Jumperless{
+				ObjectMeta: metav1.ObjectMeta{Name: "jumperless", Namespace: "default"},
+				Spec: v5alpha1.JumperlessSpec{
+					DACS: []v5alpha1.DAC{{Channel: "DAC0", Voltage: tt.voltage}},
+				},
+			}
+
+			dst := &v5alpha2.Jumperless{}
+			err := dst.ConvertFrom(src)
+			if tt.err != nil {
+				g.Expect(errors.Is(err, tt.err)).To(BeTrue())
+				return
+			}
+
+			g.Expect(err).NotTo(HaveOccurred())
+			g.Expect(dst.Name).To(Equal("jumperless"))
+			g.Expect(dst.Spec.DACS).To(ConsistOf(v5alpha2.DAC{Channel: "DAC0", Voltage: v5alpha2.Voltage{Millivolts: tt.want}}))
+		})
+	}
+}
+
+func TestConvertRoundTrip(t *testing.T) {
+	g := NewWithT(t)
+
+	src := &v5alpha2.Jumperless{
+		ObjectMeta: metav1.ObjectMeta{Name: "jumperless", Namespace: "default", Generation: 3},
+		Spec: v5alpha2.JumperlessSpec{
+			Host: v5alpha1.JumperlessHost{Local: &v5alpha1.JumperlessHostLocal{Port: ptr.To("/dev/ttyACM0")}},
+			DACS: []v5alpha2.DAC{
+				{Channel: "DAC0", Voltage: v5alpha2.Voltage{Millivolts: 3300}, Save: ptr.To(true)},
+				{Channel: "TOP_RAIL", Voltage: v5alpha2.Voltage{Millivolts: -1250}},
+			},
+			Probe: &v5alpha2.Probe{
+				PowerDAC:        ptr.To("DAC1"),
+				SwitchThreshold: &v5alpha2.Voltage{Millivolts: 1500},
+			},
+			Connections: []v5alpha1.Connection{{From: "1", To: "2"}},
+			Bootstrap: &v5alpha2.Bootstrap{
+				Profile: "lab",
+				DACS:    []v5alpha2.DAC{{Channel: "DAC1", Voltage: v5alpha2.Voltage{Millivolts: 0}}},
+			},
+			ReconcilePolicy: v5alpha1.ReconcilePolicyDetectOnly,
+			Measurements:    []v5alpha1.Measurement{{Name: "rail", Channel: 2, Samples: 2}},
+			Capture: &v5alpha1.Capture{
+				Name:     "startup",
+				Channel:  2,
+				Duration: metav1.Duration{Duration: time.Minute},
+				Target:   v5alpha1.CaptureTarget{ConfigMap: &v5alpha1.ConfigMapCaptureTarget{Name: "captures"}},
+			},
+		},
+		Status: v5alpha2.JumperlessStatus{
+			FirmwareVersion:    ptr.To("5.3.1.0"),
+			ObservedGeneration: 3,
+			LastError:          ptr.To("unable to reconcile Jumperless locally: timeout"),
+			DACS:               []v5alpha2.DACStatus{{Channel: "DAC0", Voltage: v5alpha2.Voltage{Millivolts: 3300}}},
+			Nets: []v5alpha2.Net{
+				{Index: 1, Name: "GND", Nodes: []string{"GND"}},
+				{Index: 8, Name: "Net 8", Voltage: &v5alpha2.Voltage{Millivolts: 5000}, Nodes: []string{"1", "2"}},
+			},
+			Measurements: []v5alpha2.MeasurementResult{{
+				Name:     "rail",
+				Channel:  2,
+				Interval: metav1.Duration{Duration: 100 * time.Millisecond},
+				Samples:  []v5alpha2.Voltage{{Millivolts: 3280}, {Millivolts: 3320}},
+				Min:      v5alpha2.Voltage{Millivolts: 3280},
+				Max:      v5alpha2.Voltage{Millivolts: 3320},
+				Average:  v5alpha2.Voltage{Millivolts: 3300},
+			}},
+			Capture: &v5alpha1.CaptureStatus{
+				Name:     "startup",
+				Phase:    v5alpha1.CaptureRunning,
+				Location: "configmap://default/captures/startup.csv",
+				Samples:  100,
+				Progress: 2,
+			},
+		},
+	}
+
+	hub := &v5alpha1.Jumperless{}
+	g.Expect(src.ConvertTo(hub)).To(Succeed())
+	g.Expect(hub.Spec.DACS).To(Equal([]v5alpha1.DAC{
+		{Channel: "DAC0", Voltage: "3.3V", Save: ptr.To(true)},
+		{Channel: "TOP_RAIL", Voltage: "-1.25V"},
+	}))
+	g.Expect(hub.Spec.Probe.SwitchThreshold).To(Equal(ptr.To("1.5V")))
+	g.Expect(hub.Status.DACS).To(Equal([]v5alpha1.DACStatus{
+		{Channel: "DAC0", Voltage: "3.3V", Millivolts: ptr.To(int32(3300))},
+	}))
+	g.Expect(hub.Status.Nets[1].Voltage).To(Equal(ptr.To("5V")))
+	g.Expect(hub.Status.Measurements[0].Samples).To(Equal([]string{"3.28V", "3.32V"}))
+
+	dst := &v5alpha2.Jumperless{}
+	g.Expect(dst.ConvertFrom(hub)).To(Succeed())
+	g.Expect(dst).To(Equal(src))
+}
@@ -0,0 +1,458 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v5alpha2
+
+import (
+	"strconv"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/detiber/k8s-jumperless/api/v5alpha1"
+)
+
+// Voltage is a voltage as an integer number of millivolts, so clients don't need to parse or format strings such
+// as "3.3V". Voltages have a resolution of 10mV, matching the device.
+type Voltage struct {
+	// Millivolts is the voltage in millivolts, e.g. 3300 for 3.3V.
+	// Valid range is from -8000 to 8000, in steps of 10.
+	// +kubebuilder:validation:Minimum=-8000
+	// +kubebuilder:validation:Maximum=8000
+	// +kubebuilder:validation:MultipleOf=10
+	// +required
+	Millivolts int32 `json:"millivolts"`
+}
+
+// Volts returns the voltage in volts.
+func (v Voltage) Volts() float64 {
+	return float64(v.Millivolts) / 1000
+}
+
+// String formats the voltage in volts with as few decimal places as needed, e.g. "3.3V" or "-1.25V".
+func (v Voltage) String() string {
+	return strconv.FormatFloat(v.Volts(), 'f', -1, 64) + "V"
+}
+
+// DAC represents a single DAC channel configuration.
+type DAC struct {
+	// Channel is the DAC channel to set.
+	// Valid values are "DAC0", "DAC1", "TOP_RAIL", "BOTTOM_RAIL".
+	// +kubebuilder:validation:Enum=DAC0;DAC1;TOP_RAIL;BOTTOM_RAIL
+	// +required
+	Channel string `json:"channel"`
+
+	// Voltage is the desired voltage to set the DAC channel to.
+	// +required
+	Voltage Voltage `json:"voltage"`
+
+	// Save indicates whether the voltage setting should be saved to config.
+	// If true, the setting will persist across power cycles.
+	// If false, the setting will be lost when power is removed.
+	// +default=true
+	// +optional
+	Save *bool `json:"save,omitempty"`
+}
+
+// Probe defines the settings for the probe.
+// +kubebuilder:validation:XValidation:rule="!has(self.switchThreshold) || self.switchThreshold.millivolts >= 0",message="switchThreshold must not be negative"
+type Probe struct {
+	// PowerDAC is the DAC channel used to power the probe.
+	// Valid values are "DAC0", "DAC1".
+	// +kubebuilder:validation:Enum=DAC0;DAC1
+	// +optional
+	PowerDAC *string `json:"powerDAC,omitempty"`
+
+	// SwitchThreshold is the voltage threshold used to detect the probe switch position.
+	// +optional
+	SwitchThreshold *Voltage `json:"switchThreshold,omitempty"`
+}
+
+// Bootstrap defines a baseline applied once to a factory-fresh device, e.g. when provisioning new boards.
+type Bootstrap struct {
+	// Profile is the name of the baseline. A device is bootstrapped once per profile, changing the name
+	// bootstraps the device again if it is still factory-fresh.
+	// +kubebuilder:validation:MinLength=1
+	// +kubebuilder:validation:MaxLength=63
+	// +required
+	Profile string `json:"profile"`
+
+	// DACS are the rail and DAC voltages of the baseline, they are saved to the device config unless Save is false.
+	// +listType=map
+	// +listMapKey=channel
+	// +optional
+	DACS []DAC `json:"dacs,omitempty"`
+
+	// Display defines the display settings of the baseline.
+	// +optional
+	Display *v5alpha1.Display `json:"display,omitempty"`
+
+	// FactoryConfig are the config entries identifying a factory-fresh device, the baseline is only applied if
+	// the device config matches all of them.
+	// Defaults to the rail, DAC and display settings of the firmware default config.
+	// +listType=map
+	// +listMapKey=name
+	// +optional
+	FactoryConfig []v5alpha1.JumperLessConfigSection `json:"factoryConfig,omitempty"`
+}
+
+// JumperlessSpec defines the desired state of Jumperless
+type JumperlessSpec struct {
+	// Host defines the host that is connected to the Jumperless device.
+	// +required
+	Host v5alpha1.JumperlessHost `json:"host"`
+
+	// DACS is a list of DAC channel configurations to apply.
+	// Each entry specifies a channel, the desired voltage, and whether to save the setting.
+	// +listType=map
+	// +listMapKey=channel
+	// +patchStrategy=merge
+	// +patchMergeKey=channel
+	// +optional
+	DACS []DAC `json:"dacs,omitempty" patchMergeKey:"channel" patchStrategy:"merge"`
+
+	// Display defines the settings for the top OLED display.
+	// Settings are written to the device config and reflected in status.config.
+	// +optional
+	Display *v5alpha1.Display `json:"display,omitempty"`
+
+	// Probe defines the settings for the probe.
+	// Settings are written to the device config and reflected in status.config.
+	// +optional
+	Probe *Probe `json:"probe,omitempty"`
+
+	// UARTBridge defines the settings for the USB-UART passthrough.
+	// Settings are written to the device config, the nodes are connected to UART_TX and UART_RX
+	// and the resulting state is reflected in status.uartBridge.
+	// +optional
+	UARTBridge *v5alpha1.UARTBridge `json:"uartBridge,omitempty"`
+
+	// Connections is a list of node pairs to connect.
+	// Nodes that are not connected are connected and the resulting nets are reflected in status.nets.
+	// Removing a connection from the list does not disconnect the nodes.
+	// +listType=atomic
+	// +optional
+	Connections []v5alpha1.Connection `json:"connections,omitempty"`
+
+	// GPIO is a list of GPIO pins to drive.
+	// Pins that are not at the desired level are set to it.
+	// +listType=map
+	// +listMapKey=pin
+	// +optional
+	GPIO []v5alpha1.GPIO `json:"gpio,omitempty"`
+
+	// ProfileRef references a JumperlessProfile in the same namespace whose DACs, GPIO levels and connections are
+	// applied along with the spec. Settings of the spec take precedence over the ones of the profile for the same
+	// DAC channel or GPIO pin, connections of both are made. The applied profile is recorded in status.profile.
+	// +optional
+	ProfileRef *v5alpha1.ProfileReference `json:"profileRef,omitempty"`
+
+	// Bootstrap defines a baseline applied once to the device if it is factory-fresh, before the other settings
+	// of the spec are applied. The result is recorded in status.bootstrap.
+	// +optional
+	Bootstrap *Bootstrap `json:"bootstrap,omitempty"`
+
+	// ReconcilePolicy defines how differences between the spec and the device are handled.
+	// With Enforce the settings of the spec are written to the device, with DetectOnly the differences are only
+	// reported in status.drift and the Synced condition, and the device is not written to, including bootstrap.
+	// +default="Enforce"
+	// +optional
+	ReconcilePolicy v5alpha1.ReconcilePolicy `json:"reconcilePolicy,omitempty"`
+
+	// Measurements are ADC sample bursts taken by the controller, the samples and their statistics are recorded
+	// in status.measurements. A measurement is taken when it is added or changed, and again whenever the
+	// MeasureAnnotation is set to a new value.
+	// +listType=map
+	// +listMapKey=name
+	// +kubebuilder:validation:MaxItems=8
+	// +optional
+	Measurements []v5alpha1.Measurement `json:"measurements,omitempty"`
+
+	// Capture samples an ADC channel continuously for a duration, streaming the samples to a ConfigMap or a file.
+	// The progress of the capture is recorded in status.capture.
+	// +optional
+	Capture *v5alpha1.Capture `json:"capture,omitempty"`
+}
+
+// DACStatus defines the status of a single DAC channel.
+type DACStatus struct {
+	// Channel is the DAC channel.
+	// Valid values are "DAC0", "DAC1", "TOP_RAIL", "BOTTOM_RAIL".
+	// +kubebuilder:validation:Enum=DAC0;DAC1;TOP_RAIL;BOTTOM_RAIL
+	// +required
+	Channel string `json:"channel"`
+
+	// Voltage is the current voltage of the DAC channel as reported by the device.
+	// +required
+	Voltage Voltage `json:"voltage"`
+}
+
+// MeasurementResult records the samples taken for a measurement of the spec.
+type MeasurementResult struct {
+	// Name is the name of the measurement in the spec.
+	// +required
+	Name string `json:"name"`
+
+	// Channel is the ADC channel that was sampled.
+	// +required
+	Channel int32 `json:"channel"`
+
+	// Interval is the time between two samples.
+	// +required
+	Interval metav1.Duration `json:"interval"`
+
+	// Samples are the voltages that were read, in the order they were read.
+	// +listType=atomic
+	// +required
+	Samples []Voltage `json:"samples"`
+
+	// Min is the lowest voltage that was read.
+	// +required
+	Min Voltage `json:"min"`
+
+	// Max is the highest voltage that was read.
+	// +required
+	Max Voltage `json:"max"`
+
+	// Average is the mean of the voltages that were read.
+	// +required
+	Average Voltage `json:"average"`
+
+	// Trigger is the value of the MeasureAnnotation the measurement was taken for, empty if it wasn't set.
+	// +optional
+	Trigger string `json:"trigger,omitempty"`
+
+	// CompletionTime is the time the last sample was read.
+	// +required
+	CompletionTime metav1.Time `json:"completionTime"`
+}
+
+// Net is a net configured on the device.
+type Net struct {
+	// Index is the index of the net.
+	// +required
+	Index int32 `json:"index"`
+
+	// Name is the name of the net.
+	// +required
+	Name string `json:"name"`
+
+	// Voltage is the voltage of the net.
+	// +optional
+	Voltage *Voltage `json:"voltage,omitempty"`
+
+	// Color is the color of the net.
+	// Valid values are standard color names like "red", "green", "blue", etc.
+	// +optional
+	Color *string `json:"color,omitempty"`
+
+	// Data includes any additional data associated with the net.
+	// Voltages measured on ADC nets are normalized like in v5alpha1, e.g. "-2.78V".
+	// This field is optional and may be empty.
+	// +optional
+	Data *string `json:"data,omitempty"`
+
+	// Nodes is a list of node identifiers that are part of this net.
+	// Each node identifier is a string that uniquely identifies a node on the Jumperless device.
+	// +listType=set
+	// +patchStrategy=merge
+	// +required
+	Nodes []string `json:"nodes" patchStrategy:"merge"`
+}
+
+// JumperlessStatus defines the observed state of Jumperless.
+type JumperlessStatus struct {
+	// FirmwareVersion is the version of the Jumperless firmware currently running on the device.
+	// This field is populated by the controller after successfully connecting to the device.
+	// +optional
+	FirmwareVersion *string `json:"firmwareVersion,omitempty"`
+
+	// ControllerVersion is the build version of the manager that last reconciled the device.
+	// Together with ProtocolVersion it allows auditing the managers of a fleet running mixed versions.
+	// +optional
+	ControllerVersion *string `json:"controllerVersion,omitempty"`
+
+	// ProtocolVersion is the version of the serial protocol the manager that last reconciled the device uses to
+	// talk to it.
+	// +optional
+	ProtocolVersion *string `json:"protocolVersion,omitempty"`
+
+	// LocalPort is the name of the local serial port that is connected to the Jumperless device.
+	// This field is populated by the controller after successfully discovering the device.
+	// +optional
+	LocalPort *string `json:"localPort,omitempty"`
+
+	// Device is the identity of the connected Jumperless hardware.
+	// This field is populated by the controller after successfully connecting to the device.
+	// +optional
+	Device *v5alpha1.DeviceIdentity `json:"device,omitempty"`
+
+	// DACS is a list of DAC channel statuses.
+	// Each entry reflects the current voltage setting for a specific channel.
+	// +listType=map
+	// +listMapKey=channel
+	// +patchStrategy=merge
+	// +patchMergeKey=channel
+	// +optional
+	DACS []DACStatus `json:"dacs,omitempty" patchMergeKey:"channel" patchStrategy:"merge"`
+
+	// Nets is a list of nets currently configured on the Jumperless device.
+	// This field is populated by the controller after successfully connecting to the device.
+	// +listType=map
+	// +listMapKey=index
+	// +patchStrategy=merge
+	// +patchMergeKey=index
+	// +optional
+	Nets []Net `json:"nets,omitempty" patchMergeKey:"index" patchStrategy:"merge"`
+
+	// ConnectedNets is the number of nets connecting two or more nodes.
+	// This field is populated by the controller after successfully connecting to the device.
+	// +optional
+	ConnectedNets *int32 `json:"connectedNets,omitempty"`
+
+	// ObservedGeneration is the generation of the spec that was last applied to the device. It isn't advanced while
+	// the device or its profile is missing or writes fail, so the device reflects the latest spec once it matches
+	// metadata.generation.
+	// +optional
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+
+	// LastSyncTime is the time the settings of the spec were last written to the device, or the device was first
+	// found to match them. It isn't updated by reconciles finding the device in sync, so the status remains stable
+	// between reconciliations.
+	// +optional
+	LastSyncTime *metav1.Time `json:"lastSyncTime,omitempty"`
+
+	// LastError is the error of the last reconcile, or of writing the spec to a degraded device. It is cleared once
+	// the spec is applied.
+	// +optional
+	LastError *string `json:"lastError,omitempty"`
+
+	// Config is a list of configuration sections on the Jumperless device.
+	// This field is populated by the controller after successfully retrieving the configuration from the device.
+	// +listType=map
+	// +listMapKey=name
+	// +patchStrategy=merge
+	// +patchMergeKey=name
+	// +optional
+	Config []v5alpha1.JumperLessConfigSection `json:"config,omitempty" patchMergeKey:"name" patchStrategy:"merge"`
+
+	// ConfigSchemaHash is a hash of the configuration sections and keys reported by the device, ignoring their values.
+	// A change of the hash is reported by the ConfigSchemaChanged condition and an event.
+	// +optional
+	ConfigSchemaHash *string `json:"configSchemaHash,omitempty"`
+
+	// DisplayText is the text most recently written to the top OLED display.
+	// The device does not report the displayed text, so this reflects the last applied value.
+	// +optional
+	DisplayText *string `json:"displayText,omitempty"`
+
+	// UARTBridge is the state of the USB-UART passthrough.
+	// This field is populated by the controller after successfully retrieving the configuration and nets from the device.
+	// +optional
+	UARTBridge *v5alpha1.UARTBridgeStatus `json:"uartBridge,omitempty"`
+
+	// Bootstrap records the bootstrap of the device with the baseline in spec.bootstrap.
+	// +optional
+	Bootstrap *v5alpha1.BootstrapStatus `json:"bootstrap,omitempty"`
+
+	// Profile is the JumperlessProfile referenced by spec.profileRef that was last applied to the device.
+	// +optional
+	Profile *v5alpha1.ProfileStatus `json:"profile,omitempty"`
+
+	// Drift lists the settings of the spec that differ from the state observed on the device. With the Enforce
+	// reconcile policy it is cleared once the settings were written, with DetectOnly it is kept until the device
+	// or the spec changes.
+	// +listType=map
+	// +listMapKey=field
+	// +optional
+	Drift []v5alpha1.DriftEntry `json:"drift,omitempty"`
+
+	// Measurements are the results of the measurements in spec.measurements, results of measurements that were
+	// removed from the spec are dropped.
+	// +listType=map
+	// +listMapKey=name
+	// +optional
+	Measurements []MeasurementResult `json:"measurements,omitempty"`
+
+	// Capture is the progress of the capture in spec.capture, it is kept once the capture ended.
+	// +optional
+	Capture *v5alpha1.CaptureStatus `json:"capture,omitempty"`
+
+	// HeldBy is the lease of the manager driving the device.
+	// Managers don't drive a device while another manager holds an unexpired lease, unless the TakeoverAnnotation
+	// hands the device over to them.
+	// +optional
+	HeldBy *v5alpha1.DeviceLease `json:"heldBy,omitempty"`
+
+	// Failures records the reconciles that failed in a row, it is cleared once a reconcile succeeds.
+	// A device is quarantined once the number of failures reaches the threshold of the manager.
+	// +optional
+	Failures *v5alpha1.FailureHistory `json:"failures,omitempty"`
+
+	// conditions represent the current state of the Jumperless resource.
+	// Each condition has a unique type and reflects the status of a specific aspect of the resource.
+	//
+	// Standard condition types include:
+	// - "Available": the resource is fully functional.
+	// - "Progressing": the resource is being created or updated.
+	// - "Degraded": the resource failed to reach or maintain its desired state.
+	//
+	// The status of each condition is one of True, False, or Unknown.
+	// +listType=map
+	// +listMapKey=type
+	// +patchStrategy=merge
+	// +patchMergeKey=type
+	// +optional
+	Conditions []metav1.Condition `json:"conditions,omitempty" patchMergeKey:"type" patchStrategy:"merge"`
+}
+
+// +kubebuilder:object:root=true
+// +kubebuilder:subresource:status
+// +kubebuilder:printcolumn:name="Ready",type=string,JSONPath=`.status.conditions[?(@.type=="Ready")].status`
+// +kubebuilder:printcolumn:name="Firmware",type=string,JSONPath=`.status.firmwareVersion`
+// +kubebuilder:printcolumn:name="Port",type=string,JSONPath=`.status.localPort`
+// +kubebuilder:printcolumn:name="Nets",type=integer,JSONPath=`.status.connectedNets`
+// +kubebuilder:printcolumn:name="Last Sync",type=date,JSONPath=`.status.lastSyncTime`
+// +kubebuilder:printcolumn:name="Age",type=date,JSONPath=`.metadata.creationTimestamp`
+
+// Jumperless is the Schema for the jumperlesses API
+type Jumperless struct {
+	metav1.TypeMeta `json:",inline"`
+
+	// metadata is a standard object metadata
+	// +optional
+	metav1.ObjectMeta `json:"metadata,omitempty,omitzero"`
+
+	// spec defines the desired state of Jumperless
+	// +required
+	Spec JumperlessSpec `json:"spec"`
+
+	// status defines the observed state of Jumperless
+	// +optional
+	Status JumperlessStatus `json:"status,omitempty,omitzero"`
+}
+
+// +kubebuilder:object:root=true
+
+// JumperlessList contains a list of Jumperless
+type JumperlessList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []Jumperless `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&Jumperless{}, &JumperlessList{})
+}
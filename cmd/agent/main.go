@@ -0,0 +1,177 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Command agent drives the Jumperless devices attached to a node on behalf of the manager. It runs as a DaemonSet
+// on the nodes with attached hardware, registers the devices it discovers on its Node and serves their serial
+// ports to the manager over gRPC.
+package main
+
+import (
+	"context"
+	"flag"
+	"net"
+	"os"
+	"strconv"
+	"time"
+
+	// Import all Kubernetes client auth plugins (e.g. Azure, GCP, OIDC, etc.)
+	// to ensure that exec-entrypoint and run can make use of them.
+	_ "k8s.io/client-go/plugin/pkg/client/auth"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/log/zap"
+
+	"github.com/detiber/k8s-jumperless/internal/agent"
+	"github.com/detiber/k8s-jumperless/internal/transport"
+	"github.com/detiber/k8s-jumperless/internal/version"
+)
+
+// unregisterTimeout is the time the agent takes to remove its devices from its Node when it stops
+const unregisterTimeout = 10 * time.Second
+
+var (
+	scheme   = runtime.NewScheme()        //nolint:gochecknoglobals
+	setupLog = ctrl.Log.WithName("setup") //nolint:gochecknoglobals
+)
+
+func init() {
+	utilruntime.Must(clientgoscheme.AddToScheme(scheme))
+}
+
+func main() {
+	var listenAddr string
+	var advertiseAddr string
+	var nodeName string
+	var discoveryInterval time.Duration
+	var baudRate int
+	var sessionIdleTimeout time.Duration
+	var security transport.Config
+	flag.StringVar(&listenAddr, "listen-address", ":"+strconv.Itoa(agent.DefaultPort),
+		"The address the gRPC server serving the serial ports to the manager binds to.")
+	flag.StringVar(&advertiseAddr, "advertise-address", "",
+		"The address the manager reaches the gRPC server at, registered on the Node. Defaults to the POD_IP "+
+			"environment variable and the port of --listen-address.")
+	flag.StringVar(&nodeName, "node-name", os.Getenv("NODE_NAME"),
+		"The name of the Node the agent runs on, defaults to the NODE_NAME environment variable.")
+	flag.DurationVar(&discoveryInterval, "discovery-interval", time.Minute,
+		"The interval between discoveries of the devices attached to the node.")
+	flag.IntVar(&baudRate, "baud-rate", 0, "The baud rate devices are probed with, 115200 if 0.")
+	flag.DurationVar(&sessionIdleTimeout, "session-idle-timeout", agent.DefaultSessionIdleTimeout,
+		"The time after which a serial port opened by the manager is closed if it wasn't used.")
+	flag.StringVar(&security.CertFile, "tls-cert-file", "",
+		"The certificate the gRPC server serves TLS with, reloaded when it changes.")
+	flag.StringVar(&security.KeyFile, "tls-key-file", "", "The key of --tls-cert-file.")
+	flag.StringVar(&security.CAFile, "tls-ca-file", "",
+		"The CA bundle client certificates of the manager have to be signed by, reloaded when it changes.")
+	flag.StringVar(&security.TokenFile, "token-file", "",
+		"A file containing the bearer token the manager has to send, reloaded when it changes.")
+	flag.BoolVar(&security.AllowUnauthenticated, "allow-unauthenticated", false,
+		"Serve the serial ports on a non-loopback address without client certificates or a bearer token.")
+	opts := zap.Options{
+		Development: true,
+	}
+	opts.BindFlags(flag.CommandLine)
+	flag.Parse()
+
+	ctrl.SetLogger(zap.New(zap.UseFlagOptions(&opts)))
+	setupLog.Info("starting agent", "version", version.Get())
+
+	if nodeName == "" {
+		setupLog.Info("--node-name or the NODE_NAME environment variable is required")
+		os.Exit(1)
+	}
+
+	_, listenPort, err := net.SplitHostPort(listenAddr)
+	if err != nil {
+		setupLog.Error(err, "invalid --listen-address")
+		os.Exit(1)
+	}
+
+	if advertiseAddr == "" {
+		podIP := os.Getenv("POD_IP")
+		if podIP == "" {
+			setupLog.Info("--advertise-address or the POD_IP environment variable is required")
+			os.Exit(1)
+		}
+
+		advertiseAddr = net.JoinHostPort(podIP, listenPort)
+	}
+
+	if err := security.CheckExposure(listenAddr, true); err != nil {
+		setupLog.Error(err, "configure --tls-ca-file or --token-file, or pass --allow-unauthenticated")
+		os.Exit(1)
+	}
+
+	creds, err := security.Load()
+	if err != nil {
+		setupLog.Error(err, "unable to load the TLS certificates or token")
+		os.Exit(1)
+	}
+
+	c, err := client.New(ctrl.GetConfigOrDie(), client.Options{Scheme: scheme})
+	if err != nil {
+		setupLog.Error(err, "unable to create client")
+		os.Exit(1)
+	}
+
+	listener, err := net.Listen("tcp", listenAddr)
+	if err != nil {
+		setupLog.Error(err, "unable to listen", "address", listenAddr)
+		os.Exit(1)
+	}
+
+	server := &agent.Server{
+		Node:               nodeName,
+		BaudRate:           baudRate,
+		SessionIdleTimeout: sessionIdleTimeout,
+	}
+
+	// The gRPC health service is served without a bearer token, e.g. to load balancers
+	grpcServer := grpc.NewServer(creds.ServerOptions()...)
+	server.Register(grpcServer)
+	healthServer := health.NewServer()
+	healthpb.RegisterHealthServer(grpcServer, healthServer)
+
+	go func() {
+		setupLog.Info("serving serial ports", "address", listener.Addr().String(), "advertise", advertiseAddr)
+		if err := grpcServer.Serve(listener); err != nil {
+			setupLog.Error(err, "problem serving serial ports")
+			os.Exit(1)
+		}
+	}()
+
+	registrar := &agent.NodeRegistrar{Client: c, NodeName: nodeName, Endpoint: advertiseAddr}
+
+	ctx := ctrl.LoggerInto(ctrl.SetupSignalHandler(), ctrl.Log.WithName("agent"))
+	server.Run(ctx, discoveryInterval, registrar.Register)
+
+	healthServer.Shutdown()
+	grpcServer.GracefulStop()
+
+	unregisterCtx, cancel := context.WithTimeout(context.Background(), unregisterTimeout)
+	defer cancel()
+
+	if err := registrar.Unregister(unregisterCtx); err != nil {
+		setupLog.Error(err, "unable to unregister the devices")
+	}
+}
@@ -0,0 +1,156 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// kubectl-jumperless is a kubectl plugin for working with Jumperless resources.
+package main
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/spf13/cobra"
+	"sigs.k8s.io/yaml"
+
+	jumperlessv5alpha1 "github.com/detiber/k8s-jumperless/api/v5alpha1"
+	jumperlessv5alpha2 "github.com/detiber/k8s-jumperless/api/v5alpha2"
+	"github.com/detiber/k8s-jumperless/internal/ksm"
+	"github.com/detiber/k8s-jumperless/internal/topology"
+)
+
+var ErrUnsupportedResource = errors.New("unsupported resource")
+
+func main() {
+	if err := newRootCommand().Execute(); err != nil {
+		os.Exit(1)
+	}
+}
+
+func newRootCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "kubectl-jumperless",
+		Short: "kubectl plugin for Jumperless resources",
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			return cmd.Help()
+		},
+	}
+
+	cmd.AddCommand(newKSMConfigCommand())
+	cmd.AddCommand(newTopologyCommand())
+
+	return cmd
+}
+
+func newKSMConfigCommand() *cobra.Command {
+	return &cobra.Command{
+		Use:   "ksm-config",
+		Short: "Print a kube-state-metrics CustomResourceStateMetrics config for the Jumperless resources",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			out, err := ksm.NewConfig().Marshal()
+			if err != nil {
+				return err //nolint:wrapcheck
+			}
+
+			if _, err := cmd.OutOrStdout().Write(out); err != nil {
+				return fmt.Errorf("unable to write kube-state-metrics config: %w", err)
+			}
+
+			return nil
+		},
+	}
+}
+
+func newTopologyCommand() *cobra.Command {
+	var filename string
+
+	cmd := &cobra.Command{
+		Use:   "topology",
+		Short: "Print the nets of a Jumperless as a Graphviz DOT graph",
+		Long: "Print the nets in the status of a Jumperless as a Graphviz DOT graph, reading the resource as " +
+			"YAML or JSON, e.g.\n\n" +
+			"  kubectl get jumperless bench-1 -o yaml | kubectl jumperless topology | dot -Tsvg > bench-1.svg",
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			in := cmd.InOrStdin()
+			if filename != "-" {
+				f, err := os.Open(filename)
+				if err != nil {
+					return fmt.Errorf("unable to open Jumperless: %w", err)
+				}
+				defer func() { _ = f.Close() }()
+
+				in = f
+			}
+
+			instance, err := readJumperless(in)
+			if err != nil {
+				return err
+			}
+
+			if _, err := cmd.OutOrStdout().Write(topology.DOT(instance)); err != nil {
+				return fmt.Errorf("unable to write topology: %w", err)
+			}
+
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVarP(&filename, "filename", "f", "-", "The file to read the Jumperless from, - for stdin")
+
+	return cmd
+}
+
+// readJumperless reads a Jumperless of any served version as YAML or JSON, converting it to v5alpha1
+func readJumperless(r io.Reader) (*jumperlessv5alpha1.Jumperless, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, fmt.Errorf("unable to read Jumperless: %w", err)
+	}
+
+	var typeMeta struct {
+		APIVersion string `json:"apiVersion"`
+		Kind       string `json:"kind"`
+	}
+	if err := yaml.Unmarshal(data, &typeMeta); err != nil {
+		return nil, fmt.Errorf("unable to read Jumperless: %w", err)
+	}
+
+	if typeMeta.Kind != "Jumperless" {
+		return nil, fmt.Errorf("%w: expected a Jumperless, got kind %q", ErrUnsupportedResource, typeMeta.Kind)
+	}
+
+	instance := &jumperlessv5alpha1.Jumperless{}
+	switch typeMeta.APIVersion {
+	case jumperlessv5alpha1.GroupVersion.String():
+		if err := yaml.Unmarshal(data, instance); err != nil {
+			return nil, fmt.Errorf("unable to read Jumperless: %w", err)
+		}
+	case jumperlessv5alpha2.GroupVersion.String():
+		spoke := &jumperlessv5alpha2.Jumperless{}
+		if err := yaml.Unmarshal(data, spoke); err != nil {
+			return nil, fmt.Errorf("unable to read Jumperless: %w", err)
+		}
+		if err := spoke.ConvertTo(instance); err != nil {
+			return nil, fmt.Errorf("unable to convert Jumperless: %w", err)
+		}
+	default:
+		return nil, fmt.Errorf("%w: unsupported apiVersion %q", ErrUnsupportedResource, typeMeta.APIVersion)
+	}
+
+	return instance, nil
+}
@@ -19,8 +19,11 @@ package main
 import (
 	"crypto/tls"
 	"flag"
+	"net/http"
+	"net/http/pprof" //nolint:gosec // The handlers are only served by the metrics server when --enable-pprof is set
 	"os"
 	"path/filepath"
+	"time"
 
 	// Import all Kubernetes client auth plugins (e.g. Azure, GCP, OIDC, etc.)
 	// to ensure that exec-entrypoint and run can make use of them.
@@ -38,7 +41,13 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/webhook"
 
 	jumperlessv5alpha1 "github.com/detiber/k8s-jumperless/api/v5alpha1"
+	jumperlessv5alpha2 "github.com/detiber/k8s-jumperless/api/v5alpha2"
+	"github.com/detiber/k8s-jumperless/internal/agent"
 	"github.com/detiber/k8s-jumperless/internal/controller"
+	"github.com/detiber/k8s-jumperless/internal/transport"
+	"github.com/detiber/k8s-jumperless/internal/version"
+	webhookv5alpha1 "github.com/detiber/k8s-jumperless/internal/webhook/v5alpha1"
+	"github.com/detiber/k8s-jumperless/jumperless"
 	// +kubebuilder:scaffold:imports
 )
 
@@ -51,6 +60,7 @@ func init() {
 	utilruntime.Must(clientgoscheme.AddToScheme(scheme))
 
 	utilruntime.Must(jumperlessv5alpha1.AddToScheme(scheme))
+	utilruntime.Must(jumperlessv5alpha2.AddToScheme(scheme))
 	// +kubebuilder:scaffold:scheme
 }
 
@@ -59,16 +69,38 @@ func main() {
 	var metricsCertPath, metricsCertName, metricsCertKey string
 	var webhookCertPath, webhookCertName, webhookCertKey string
 	var enableLeaderElection bool
+	var leaderElectionLeaseDuration, leaderElectionRenewDeadline, leaderElectionRetryPeriod time.Duration
 	var probeAddr string
 	var secureMetrics bool
 	var enableHTTP2 bool
+	var enablePprof bool
+	var enableConsole bool
+	var enableTopology bool
+	var serialRetryAttempts int
+	var serialRetryBackoff time.Duration
+	var serialDTRPulse time.Duration
+	var holderIdentity string
+	var leaseDuration time.Duration
+	var quarantineThreshold int
+	var quarantineProbeInterval time.Duration
+	var captureDir string
+	var workqueue controller.WorkqueueOptions
+	var agentSecurity transport.Config
 	var tlsOpts []func(*tls.Config)
 	flag.StringVar(&metricsAddr, "metrics-bind-address", "0", "The address the metrics endpoint binds to. "+
 		"Use :8443 for HTTPS or :8080 for HTTP, or leave as 0 to disable the metrics service.")
 	flag.StringVar(&probeAddr, "health-probe-bind-address", ":8081", "The address the probe endpoint binds to.")
 	flag.BoolVar(&enableLeaderElection, "leader-elect", false,
 		"Enable leader election for controller manager. "+
-			"Enabling this will ensure there is only one active controller manager.")
+			"Enabling this will ensure there is only one active controller manager. The controllers driving "+
+			"devices run on every manager with a --holder-identity, each device is driven by the manager "+
+			"holding its lease.")
+	flag.DurationVar(&leaderElectionLeaseDuration, "leader-elect-lease-duration", 15*time.Second,
+		"The duration non-leader managers wait before taking over leadership once the leader stopped renewing it.")
+	flag.DurationVar(&leaderElectionRenewDeadline, "leader-elect-renew-deadline", 10*time.Second,
+		"The duration the leader retries renewing leadership before giving it up.")
+	flag.DurationVar(&leaderElectionRetryPeriod, "leader-elect-retry-period", 2*time.Second,
+		"The duration between attempts of the managers to acquire or renew leadership.")
 	flag.BoolVar(&secureMetrics, "metrics-secure", true,
 		"If set, the metrics endpoint is served securely via HTTPS. Use --metrics-secure=false to use HTTP instead.")
 	flag.StringVar(&webhookCertPath, "webhook-cert-path", "", "The directory that contains the webhook certificate.")
@@ -80,6 +112,60 @@ func main() {
 	flag.StringVar(&metricsCertKey, "metrics-cert-key", "tls.key", "The name of the metrics server key file.")
 	flag.BoolVar(&enableHTTP2, "enable-http2", false,
 		"If set, HTTP/2 will be enabled for the metrics and webhook servers")
+	flag.BoolVar(&enablePprof, "enable-pprof", false,
+		"If set, pprof and the runtime state of the devices are served below /debug on the metrics endpoint. "+
+			"Requires the metrics service.")
+	flag.BoolVar(&enableConsole, "enable-console", false,
+		"If set, the serial consoles of the devices are served as websockets below /console on the metrics "+
+			"endpoint. Requires the secure metrics service.")
+	flag.BoolVar(&enableTopology, "enable-topology", false,
+		"If set, the nets of the devices are served as Graphviz DOT graphs below /topology on the metrics "+
+			"endpoint. Requires the metrics service.")
+	flag.IntVar(&serialRetryAttempts, "serial-retry-attempts", jumperless.DefaultRetryPolicy().Attempts,
+		"The number of times idempotent serial commands are executed when their output is truncated or can't be "+
+			"read. Use 1 to disable retries.")
+	flag.DurationVar(&serialRetryBackoff, "serial-retry-backoff", jumperless.DefaultRetryPolicy().Backoff.Duration,
+		"The delay before retrying a serial command, doubled for every further attempt.")
+	flag.DurationVar(&serialDTRPulse, "serial-dtr-pulse", 0,
+		"If set, the DTR line is dropped for this duration after opening a serial port, for host setups that "+
+			"require toggling DTR to wake or reset the device.")
+	hostname, _ := os.Hostname()
+	flag.StringVar(&holderIdentity, "holder-identity", hostname,
+		"The identity of the manager in the leases of the devices it drives, see status.heldBy. "+
+			"Defaults to the hostname, an empty identity drives devices without holding a lease and only on "+
+			"the leader of the managers.")
+	flag.DurationVar(&leaseDuration, "lease-duration", controller.DefaultLeaseDuration,
+		"The duration of the device leases held by the manager. Another manager takes over a device once "+
+			"its lease was not renewed for this long.")
+	flag.IntVar(&quarantineThreshold, "quarantine-threshold", controller.DefaultQuarantineThreshold,
+		"The number of reconciles of a device failing in a row after which the device is quarantined.")
+	flag.DurationVar(&quarantineProbeInterval, "quarantine-probe-interval", controller.DefaultQuarantineProbeInterval,
+		"The interval between probes of a quarantined device.")
+	flag.IntVar(&workqueue.MaxConcurrentReconciles, "max-concurrent-reconciles",
+		controller.DefaultMaxConcurrentReconciles,
+		"The number of resources each controller reconciles at the same time. Reconciles of the same device "+
+			"are serialized regardless.")
+	flag.DurationVar(&workqueue.RequeueBaseDelay, "requeue-base-delay", controller.DefaultRequeueBaseDelay,
+		"The delay before retrying a resource whose reconcile failed, doubled for every further failure in a row.")
+	flag.DurationVar(&workqueue.RequeueMaxDelay, "requeue-max-delay", controller.DefaultRequeueMaxDelay,
+		"The maximum delay before retrying a resource whose reconcile failed.")
+	flag.Float64Var(&workqueue.RequeueQPS, "requeue-qps", controller.DefaultRequeueQPS,
+		"The rate at which each controller requeues resources altogether, in requeues per second.")
+	flag.IntVar(&workqueue.RequeueBurst, "requeue-burst", controller.DefaultRequeueBurst,
+		"The number of requeues each controller may burst above --requeue-qps.")
+	flag.StringVar(&captureDir, "capture-dir", "",
+		"The directory captures with a file target are written to, below a directory per namespace and "+
+			"Jumperless. If empty, captures with a file target fail.")
+	flag.StringVar(&agentSecurity.CertFile, "agent-tls-cert-file", "",
+		"The client certificate presented to the node agents, reloaded when it changes.")
+	flag.StringVar(&agentSecurity.KeyFile, "agent-tls-key-file", "", "The key of --agent-tls-cert-file.")
+	flag.StringVar(&agentSecurity.CAFile, "agent-tls-ca-file", "",
+		"The CA bundle the certificates of the node agents are verified with. If set, agents are connected "+
+			"to with TLS.")
+	flag.StringVar(&agentSecurity.ServerName, "agent-tls-server-name", "",
+		"The name the certificates of the node agents are verified for, instead of their addresses.")
+	flag.StringVar(&agentSecurity.TokenFile, "agent-token-file", "",
+		"A file containing the bearer token sent to the node agents, reloaded when it changes.")
 	opts := zap.Options{
 		Development: true,
 	}
@@ -177,6 +263,70 @@ func main() {
 		})
 	}
 
+	// The controllers share a single owner of the device ports, so discovery never probes a port
+	// while it is being reconciled
+	ports := jumperless.NewPortManager()
+
+	retryPolicy := jumperless.DefaultRetryPolicy()
+	retryPolicy.Attempts = serialRetryAttempts
+	retryPolicy.Backoff.Duration = serialRetryBackoff
+	ports.SetRetryPolicy(retryPolicy)
+	ports.SetConnectDTRPulse(serialDTRPulse)
+
+	// The diagnostics endpoints are served by the metrics server, so they are protected by the same authn/authz.
+	// The RBAC is configured in 'config/rbac/diagnostics_reader_role.yaml'.
+	if enablePprof {
+		if metricsAddr == "0" {
+			setupLog.Error(nil, "--enable-pprof requires the metrics service, set --metrics-bind-address")
+			os.Exit(1)
+		}
+
+		metricsServerOptions.ExtraHandlers = map[string]http.Handler{
+			"/debug/pprof/":        http.HandlerFunc(pprof.Index),
+			"/debug/pprof/cmdline": http.HandlerFunc(pprof.Cmdline),
+			"/debug/pprof/profile": http.HandlerFunc(pprof.Profile),
+			"/debug/pprof/symbol":  http.HandlerFunc(pprof.Symbol),
+			"/debug/pprof/trace":   http.HandlerFunc(pprof.Trace),
+			"/debug/jumperless":    &controller.Diagnostics{Ports: ports},
+		}
+	}
+
+	// The consoles are served by the metrics server as well, access to the console of a device is granted
+	// through the nonResourceURLs of its path. The RBAC is configured in 'config/rbac/console_attacher_role.yaml'.
+	// The client reading the devices is set once the manager is created.
+	var console *controller.Console
+	if enableConsole {
+		if metricsAddr == "0" || !secureMetrics {
+			setupLog.Error(nil, "--enable-console requires the secure metrics service, set --metrics-bind-address")
+			os.Exit(1)
+		}
+
+		if metricsServerOptions.ExtraHandlers == nil {
+			metricsServerOptions.ExtraHandlers = map[string]http.Handler{}
+		}
+
+		console = &controller.Console{Ports: ports, Identity: holderIdentity}
+		metricsServerOptions.ExtraHandlers[controller.ConsolePath] = console
+	}
+
+	// The topologies are served by the metrics server too, access to the topology of a device is granted
+	// through the nonResourceURLs of its path. The RBAC is configured in 'config/rbac/topology_viewer_role.yaml'.
+	// The client reading the devices is set once the manager is created.
+	var topology *controller.Topology
+	if enableTopology {
+		if metricsAddr == "0" {
+			setupLog.Error(nil, "--enable-topology requires the metrics service, set --metrics-bind-address")
+			os.Exit(1)
+		}
+
+		if metricsServerOptions.ExtraHandlers == nil {
+			metricsServerOptions.ExtraHandlers = map[string]http.Handler{}
+		}
+
+		topology = &controller.Topology{}
+		metricsServerOptions.ExtraHandlers[controller.TopologyPath] = topology
+	}
+
 	mgr, err := ctrl.NewManager(ctrl.GetConfigOrDie(), ctrl.Options{
 		Scheme:                 scheme,
 		Metrics:                metricsServerOptions,
@@ -184,6 +334,9 @@ func main() {
 		HealthProbeBindAddress: probeAddr,
 		LeaderElection:         enableLeaderElection,
 		LeaderElectionID:       "73fafc64.detiber.us",
+		LeaseDuration:          &leaderElectionLeaseDuration,
+		RenewDeadline:          &leaderElectionRenewDeadline,
+		RetryPeriod:            &leaderElectionRetryPeriod,
 		// LeaderElectionReleaseOnCancel defines if the leader should step down voluntarily
 		// when the Manager ends. This requires the binary to immediately end when the
 		// Manager is stopped, otherwise, this setting is unsafe. Setting this significantly
@@ -201,13 +354,68 @@ func main() {
 		os.Exit(1)
 	}
 
+	// The agents driving the devices attached to other nodes are found by the endpoints they registered on
+	// their Nodes, which are read directly so the manager doesn't cache all Nodes
+	agentCreds, err := agentSecurity.Load()
+	if err != nil {
+		setupLog.Error(err, "unable to load the credentials of the connections to the agents")
+		os.Exit(1)
+	}
+	agents := agent.NewPool(mgr.GetAPIReader(), agentCreds.DialOptions()...)
+
+	if console != nil {
+		console.Client = mgr.GetClient()
+		console.Agents = agents
+	}
+	if topology != nil {
+		topology.Client = mgr.GetClient()
+	}
+
 	if err := (&controller.JumperlessReconciler{
-		Client: mgr.GetClient(),
-		Scheme: mgr.GetScheme(),
+		Client:                  mgr.GetClient(),
+		Scheme:                  mgr.GetScheme(),
+		Ports:                   ports,
+		Agents:                  agents,
+		Recorder:                mgr.GetEventRecorderFor("jumperless-controller"),
+		Identity:                holderIdentity,
+		LeaseDuration:           leaseDuration,
+		QuarantineThreshold:     quarantineThreshold,
+		QuarantineProbeInterval: quarantineProbeInterval,
+		CaptureDir:              captureDir,
+		Version:                 version.Get(),
+		Workqueue:               workqueue,
 	}).SetupWithManager(mgr); err != nil {
 		setupLog.Error(err, "unable to create controller", "controller", "Jumperless")
 		os.Exit(1)
 	}
+	if err := (&controller.JumperlessFleetReconciler{
+		Client:    mgr.GetClient(),
+		Scheme:    mgr.GetScheme(),
+		Discover:  ports.Discover,
+		Workqueue: workqueue,
+	}).SetupWithManager(mgr); err != nil {
+		setupLog.Error(err, "unable to create controller", "controller", "JumperlessFleet")
+		os.Exit(1)
+	}
+	if err := (&controller.JumperlessCommandReconciler{
+		Client:    mgr.GetClient(),
+		Scheme:    mgr.GetScheme(),
+		Ports:     ports,
+		Agents:    agents,
+		Identity:  holderIdentity,
+		Workqueue: workqueue,
+	}).SetupWithManager(mgr); err != nil {
+		setupLog.Error(err, "unable to create controller", "controller", "JumperlessCommand")
+		os.Exit(1)
+	}
+	// The conversion webhook is needed to serve v5alpha2, it can be disabled when running the manager outside
+	// of the cluster.
+	if os.Getenv("ENABLE_WEBHOOKS") != "false" {
+		if err := webhookv5alpha1.SetupJumperlessWebhookWithManager(mgr); err != nil {
+			setupLog.Error(err, "unable to create webhook", "webhook", "Jumperless")
+			os.Exit(1)
+		}
+	}
 	// +kubebuilder:scaffold:builder
 
 	if metricsCertWatcher != nil {
@@ -235,9 +443,13 @@ func main() {
 		os.Exit(1)
 	}
 
-	setupLog.Info("starting manager")
+	setupLog.Info("starting manager", "version", version.Get(), "protocolVersion", jumperless.ProtocolVersion)
 	if err := mgr.Start(ctrl.SetupSignalHandler()); err != nil {
 		setupLog.Error(err, "problem running manager")
 		os.Exit(1)
 	}
+
+	if err := agents.Close(); err != nil {
+		setupLog.Error(err, "unable to close the connections to the agents")
+	}
 }
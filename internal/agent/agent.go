@@ -0,0 +1,83 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package agent drives the Jumperless devices attached to a node on behalf of a manager running elsewhere. The
+// agent runs as a DaemonSet on the nodes with attached hardware: it discovers the local devices, registers them
+// on its Node, and serves their serial ports to the manager with the Remote service over gRPC. The manager opens the ports through a
+// Pool, so the devices are driven by the same code as the devices attached to the node of the manager.
+package agent
+
+import (
+	"github.com/detiber/k8s-jumperless/jumperless"
+)
+
+const (
+	// EndpointAnnotation is set on the Node of an agent to the address its gRPC server is reachable at
+	EndpointAnnotation = "jumperless.detiber.us/agent-endpoint"
+
+	// DevicesAnnotation is set on the Node of an agent to the JSON list of the devices it discovered
+	DevicesAnnotation = "jumperless.detiber.us/devices"
+
+	// AgentLabel is set to "true" on the Nodes running an agent
+	AgentLabel = "jumperless.detiber.us/agent"
+
+	// DeviceResource is the extended resource the Node of an agent advertises for each device it discovered,
+	// so workloads depending on a device can be scheduled to the nodes it is attached to
+	DeviceResource = "jumperless.detiber.us/device"
+
+	// DefaultPort is the port the gRPC server of the agent listens on, unless configured otherwise
+	DefaultPort = 9444
+)
+
+// Device is a Jumperless device attached to the node of an agent
+type Device struct {
+	// Port is the name of the serial port on the node
+	Port string `json:"port"`
+
+	// SerialNumber is the USB serial number, if known
+	SerialNumber string `json:"serialNumber,omitempty"`
+
+	// VID is the USB vendor ID, if known
+	VID string `json:"vid,omitempty"`
+
+	// PID is the USB product ID, if known
+	PID string `json:"pid,omitempty"`
+
+	// Version is the firmware version reported by the device
+	Version string `json:"version,omitempty"`
+}
+
+// newDevice returns the device discovered on a port
+func newDevice(info jumperless.DeviceInfo) Device {
+	return Device{
+		Port:         info.Port,
+		SerialNumber: info.SerialNumber,
+		VID:          info.VID,
+		PID:          info.PID,
+		Version:      info.Version,
+	}
+}
+
+// Info returns the device as discovered by the jumperless library
+func (d Device) Info() jumperless.DeviceInfo {
+	return jumperless.DeviceInfo{
+		Port:         d.Port,
+		SerialNumber: d.SerialNumber,
+		VID:          d.VID,
+		PID:          d.PID,
+		Version:      d.Version,
+	}
+}
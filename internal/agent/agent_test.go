@@ -0,0 +1,260 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package agent
+
+import (
+	"context"
+	"encoding/json"
+	"net"
+	"testing"
+	"time"
+
+	. "github.com/onsi/gomega"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	"github.com/detiber/k8s-jumperless/jumperless"
+	"github.com/detiber/k8s-jumperless/jumperless/serialmock"
+)
+
+// serve serves the Remote service of s in memory, returning a client connected to it
+func serve(t *testing.T, s *Server) *Client {
+	t.Helper()
+
+	return NewClient(dial(t, s), s.Node)
+}
+
+// dial serves the services of s in memory, returning a connection to them
+func dial(t *testing.T, s *Server) *grpc.ClientConn {
+	t.Helper()
+
+	listener := bufconn.Listen(1 << 20)
+	server := grpc.NewServer()
+	s.Register(server)
+
+	go func() { _ = server.Serve(listener) }()
+	t.Cleanup(server.Stop)
+
+	conn, err := grpc.NewClient("passthrough:///agent",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) {
+			return listener.DialContext(ctx)
+		}),
+		grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { _ = conn.Close() })
+
+	return conn
+}
+
+// discover returns a DiscoverFunc finding devices on the given ports
+func discover(ports ...string) DiscoverFunc {
+	return func(_ context.Context, _ int, inUse func(string) (string, bool)) ([]jumperless.DeviceInfo, error) {
+		devices := []jumperless.DeviceInfo{}
+		for _, port := range ports {
+			version, ok := inUse(port)
+			if !ok {
+				version = "5.3.1.0"
+			}
+
+			devices = append(devices, jumperless.DeviceInfo{Port: port, SerialNumber: "E6614103E7", Version: version})
+		}
+
+		return devices, nil
+	}
+}
+
+func TestRemotePort(t *testing.T) {
+	g := NewWithT(t)
+
+	port := serialmock.New()
+	port.Expect("?").Respond("Jumperless firmware version: 5.3.1.0\r\n")
+	port.Expect(">dac_get(0)").RespondAfter(20*time.Millisecond, "Python> >dac_get(0)\r\n3.3\r\n")
+
+	s := &Server{Node: "node-a", Discover: discover("/dev/ttyACM0"), Opener: port.Open}
+	c := serve(t, s)
+
+	devices, err := s.DiscoverDevices(t.Context())
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(devices).To(HaveLen(1))
+
+	listed, err := c.ListDevices(t.Context())
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(listed).To(Equal(devices))
+
+	// The device is driven through the agent like a local one
+	j, err := jumperless.NewJumperlessWithOpener("/dev/ttyACM0", 0, c.Opener())
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(j.GetVersion()).To(Equal("5.3.1.0"))
+	g.Expect(port.IsOpen()).To(BeFalse())
+
+	g.Expect(j.OpenPort()).To(Succeed())
+	g.Expect(port.Mode().BaudRate).To(Equal(115200))
+
+	// A port in use is reported by discovery without probing it
+	devices, err = s.DiscoverDevices(t.Context())
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(devices).To(HaveLen(1))
+
+	result, err := j.ExecPythonCommand("dac_get(0)", 0, jumperless.SingleLine())
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(result).To(Equal("3.3"))
+
+	g.Expect(j.ClosePort()).To(Succeed())
+	g.Expect(port.IsOpen()).To(BeFalse())
+	g.Expect(port.ExpectationsMet()).To(Succeed())
+}
+
+func TestRemotePortErrors(t *testing.T) {
+	g := NewWithT(t)
+
+	port := serialmock.New()
+	s := &Server{Node: "node-a", Discover: discover("/dev/ttyACM0"), Opener: port.Open}
+	c := serve(t, s)
+
+	_, err := s.DiscoverDevices(t.Context())
+	g.Expect(err).NotTo(HaveOccurred())
+
+	// Only the ports of discovered devices can be opened
+	_, err = c.Opener()("/dev/sda", nil)
+	g.Expect(status.Code(err)).To(Equal(codes.NotFound))
+
+	remote, err := c.Opener()("/dev/ttyACM0", nil)
+	g.Expect(err).NotTo(HaveOccurred())
+
+	// Opening the port again closes the session left behind
+	_, err = c.Opener()("/dev/ttyACM0", nil)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(port.IsOpen()).To(BeTrue())
+
+	_, err = remote.Read(make([]byte, 1))
+	g.Expect(status.Code(err)).To(Equal(codes.NotFound))
+	g.Expect(remote.Close()).To(Succeed())
+}
+
+func TestRemoteService(t *testing.T) {
+	g := NewWithT(t)
+
+	port := serialmock.New()
+
+	s := &Server{Node: "node-a", Discover: discover("/dev/ttyACM0"), Opener: port.Open}
+	c := jumperless.NewRemoteClient(dial(t, s))
+
+	_, err := s.DiscoverDevices(t.Context())
+	g.Expect(err).NotTo(HaveOccurred())
+
+	identity, err := c.Identity(t.Context())
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(identity.GetName()).To(Equal("node-a"))
+	g.Expect(identity.GetKind()).To(Equal(IdentityKind))
+	g.Expect(identity.GetDevices()).To(HaveLen(1))
+
+	// Without a port the port of the only device is opened
+	remote, err := c.Opener()("", nil)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(port.IsOpen()).To(BeTrue())
+
+	version, ok := s.inUse("/dev/ttyACM0")
+	g.Expect(ok).To(BeTrue())
+	g.Expect(version).To(Equal("5.3.1.0"))
+
+	// Opening the port again from another client takes it over
+	_, err = serve(t, s).Opener()("/dev/ttyACM0", nil)
+	g.Expect(err).NotTo(HaveOccurred())
+
+	_, err = remote.Read(make([]byte, 1))
+	g.Expect(status.Code(err)).To(Equal(codes.NotFound))
+	g.Expect(remote.Close()).To(Succeed())
+}
+
+func TestIdleSessionsAreClosed(t *testing.T) {
+	g := NewWithT(t)
+
+	port := serialmock.New()
+	s := &Server{
+		Node:               "node-a",
+		Discover:           discover("/dev/ttyACM0"),
+		Opener:             port.Open,
+		SessionIdleTimeout: time.Nanosecond,
+	}
+	c := serve(t, s)
+
+	_, err := s.DiscoverDevices(t.Context())
+	g.Expect(err).NotTo(HaveOccurred())
+
+	_, err = c.Opener()("/dev/ttyACM0", nil)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(port.IsOpen()).To(BeTrue())
+
+	ctx, cancel := context.WithCancel(t.Context())
+	registered := make(chan []Device, 1)
+	go s.Run(ctx, time.Hour, func(_ context.Context, devices []Device) error {
+		registered <- devices
+		return nil
+	})
+
+	g.Eventually(registered).Should(Receive(HaveLen(1)))
+	g.Eventually(port.IsOpen).Should(BeFalse())
+	cancel()
+}
+
+func TestNodeRegistrar(t *testing.T) {
+	g := NewWithT(t)
+
+	node := &corev1.Node{ObjectMeta: metav1.ObjectMeta{Name: "node-a"}}
+	c := fake.NewClientBuilder().WithObjects(node).WithStatusSubresource(node).Build()
+
+	r := &NodeRegistrar{Client: c, NodeName: "node-a", Endpoint: "10.0.0.5:9444"}
+	devices := []Device{{Port: "/dev/ttyACM0", SerialNumber: "E6614103E7", Version: "5.3.1.0"}}
+	g.Expect(r.Register(t.Context(), devices)).To(Succeed())
+
+	g.Expect(c.Get(t.Context(), client.ObjectKeyFromObject(node), node)).To(Succeed())
+	g.Expect(node.Labels).To(HaveKeyWithValue(AgentLabel, "true"))
+	g.Expect(node.Annotations).To(HaveKeyWithValue(EndpointAnnotation, "10.0.0.5:9444"))
+	g.Expect(node.Status.Capacity).To(HaveKeyWithValue(corev1.ResourceName(DeviceResource), resource.MustParse("1")))
+	g.Expect(node.Status.Allocatable).To(HaveKeyWithValue(corev1.ResourceName(DeviceResource), resource.MustParse("1")))
+
+	registered := []Device{}
+	g.Expect(json.Unmarshal([]byte(node.Annotations[DevicesAnnotation]), &registered)).To(Succeed())
+	g.Expect(registered).To(Equal(devices))
+
+	// The endpoint is found by the pool
+	pool := NewPool(c)
+	defer func() { _ = pool.Close() }()
+
+	agent, err := pool.Agent(t.Context(), "node-a")
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(agent.Node()).To(Equal("node-a"))
+
+	g.Expect(r.Unregister(t.Context())).To(Succeed())
+	g.Expect(c.Get(t.Context(), client.ObjectKeyFromObject(node), node)).To(Succeed())
+	g.Expect(node.Labels).NotTo(HaveKey(AgentLabel))
+	g.Expect(node.Annotations).NotTo(HaveKey(EndpointAnnotation))
+	g.Expect(node.Status.Capacity).NotTo(HaveKey(corev1.ResourceName(DeviceResource)))
+
+	_, err = pool.Agent(t.Context(), "node-a")
+	g.Expect(err).To(MatchError(ErrNoAgent))
+}
@@ -0,0 +1,193 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package agent
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"strconv"
+	"sync"
+
+	"go.bug.st/serial"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+	corev1 "k8s.io/api/core/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/detiber/k8s-jumperless/jumperless"
+)
+
+var ErrNoAgent = errors.New("no agent registered on node")
+
+// Pool connects to the agents of the nodes Jumperless devices are attached to, and to the servers of the Remote
+// service at the endpoints of other devices, keeping a connection to each of them. The agent of a node is found
+// by the endpoint it registered on its Node.
+type Pool struct {
+	reader      client.Reader
+	dialOptions []grpc.DialOption
+
+	mu      sync.Mutex
+	conns   map[string]*agentConn       // By node name
+	remotes map[string]*grpc.ClientConn // By endpoint
+}
+
+// agentConn is a connection to the agent of a node
+type agentConn struct {
+	endpoint string
+	conn     *grpc.ClientConn
+}
+
+// NewPool returns a Pool looking up the Nodes with reader. The connections are not encrypted unless dial
+// options with transport credentials are given.
+func NewPool(reader client.Reader, dialOptions ...grpc.DialOption) *Pool {
+	return &Pool{
+		reader:      reader,
+		dialOptions: append([]grpc.DialOption{grpc.WithTransportCredentials(insecure.NewCredentials())}, dialOptions...),
+		conns:       map[string]*agentConn{},
+		remotes:     map[string]*grpc.ClientConn{},
+	}
+}
+
+// Agent returns a client of the agent running on a node, reconnecting if the agent registered a new endpoint
+func (p *Pool) Agent(ctx context.Context, nodeName string) (*Client, error) {
+	node := &corev1.Node{}
+	if err := p.reader.Get(ctx, client.ObjectKey{Name: nodeName}, node); err != nil {
+		return nil, fmt.Errorf("unable to get node %s: %w", nodeName, err)
+	}
+
+	endpoint := node.Annotations[EndpointAnnotation]
+	if endpoint == "" {
+		return nil, fmt.Errorf("%w %s", ErrNoAgent, nodeName)
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if existing, ok := p.conns[nodeName]; ok {
+		if existing.endpoint == endpoint {
+			return NewClient(existing.conn, nodeName), nil
+		}
+
+		_ = existing.conn.Close()
+		delete(p.conns, nodeName)
+	}
+
+	conn, err := grpc.NewClient(endpoint, p.dialOptions...)
+	if err != nil {
+		return nil, fmt.Errorf("unable to connect to the agent of node %s at %s: %w", nodeName, endpoint, err)
+	}
+
+	p.conns[nodeName] = &agentConn{endpoint: endpoint, conn: conn}
+
+	return NewClient(conn, nodeName), nil
+}
+
+// Remote returns a client of the Remote service served at an endpoint, e.g. by the proxy of jumperless-utils
+func (p *Pool) Remote(endpoint string) (*jumperless.RemoteClient, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if conn, ok := p.remotes[endpoint]; ok {
+		return jumperless.NewRemoteClient(conn), nil
+	}
+
+	conn, err := grpc.NewClient(endpoint, p.dialOptions...)
+	if err != nil {
+		return nil, fmt.Errorf("unable to connect to %s: %w", endpoint, err)
+	}
+
+	p.remotes[endpoint] = conn
+
+	return jumperless.NewRemoteClient(conn), nil
+}
+
+// Close closes the connections to all agents and remote servers
+func (p *Pool) Close() error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	errs := []error{}
+	for nodeName, existing := range p.conns {
+		if err := existing.conn.Close(); err != nil {
+			errs = append(errs, fmt.Errorf("unable to close connection to the agent of node %s: %w", nodeName, err))
+		}
+	}
+
+	for endpoint, conn := range p.remotes {
+		if err := conn.Close(); err != nil {
+			errs = append(errs, fmt.Errorf("unable to close connection to %s: %w", endpoint, err))
+		}
+	}
+
+	p.conns = map[string]*agentConn{}
+	p.remotes = map[string]*grpc.ClientConn{}
+
+	return errors.Join(errs...)
+}
+
+// Client calls the agent of a node through the Remote service
+type Client struct {
+	remote *jumperless.RemoteClient
+	node   string
+}
+
+// NewClient returns a client of the agent of a node reachable through conn
+func NewClient(conn grpc.ClientConnInterface, nodeName string) *Client {
+	return &Client{remote: jumperless.NewRemoteClient(conn), node: nodeName}
+}
+
+// Node returns the name of the node of the agent
+func (c *Client) Node() string {
+	return c.node
+}
+
+// ListDevices returns the devices discovered by the agent
+func (c *Client) ListDevices(ctx context.Context) ([]Device, error) {
+	infos, err := c.remote.Devices(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("agent of node %s: %w", c.node, err)
+	}
+
+	devices := make([]Device, 0, len(infos))
+	for _, info := range infos {
+		devices = append(devices, newDevice(info))
+	}
+
+	return devices, nil
+}
+
+// Opener returns an Opener opening the serial ports of the node through the agent, to be passed to
+// jumperless.NewJumperlessWithOpener or PortManager.AcquireWithOpener
+func (c *Client) Opener() jumperless.Opener {
+	open := c.remote.Opener()
+
+	return func(portName string, mode *serial.Mode) (serial.Port, error) {
+		port, err := open(portName, mode)
+		if err != nil {
+			return nil, fmt.Errorf("agent of node %s: %w", c.node, err)
+		}
+
+		return port, nil
+	}
+}
+
+// Endpoint returns the endpoint of an agent listening on the given port of the host
+func Endpoint(host string, port int) string {
+	return net.JoinHostPort(host, strconv.Itoa(port))
+}
@@ -0,0 +1,110 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package agent
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	"k8s.io/apimachinery/pkg/api/resource"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// NodeRegistrar records the devices discovered by an agent on its Node: the endpoint of the agent and the
+// devices are annotated, and each device is advertised as a DeviceResource in the capacity of the node, like a
+// device plugin would.
+type NodeRegistrar struct {
+	Client client.Client
+
+	// NodeName is the name of the Node the agent runs on
+	NodeName string
+
+	// Endpoint is the address the gRPC server of the agent is reachable at from the manager
+	Endpoint string
+}
+
+// Register records the devices on the Node, it is only patched if they changed
+func (r *NodeRegistrar) Register(ctx context.Context, devices []Device) error {
+	encoded, err := json.Marshal(devices)
+	if err != nil {
+		return fmt.Errorf("unable to encode devices: %w", err)
+	}
+
+	return r.update(ctx, func(node *corev1.Node) {
+		if node.Labels == nil {
+			node.Labels = map[string]string{}
+		}
+		node.Labels[AgentLabel] = "true"
+
+		if node.Annotations == nil {
+			node.Annotations = map[string]string{}
+		}
+		node.Annotations[EndpointAnnotation] = r.Endpoint
+		node.Annotations[DevicesAnnotation] = string(encoded)
+	}, resource.NewQuantity(int64(len(devices)), resource.DecimalSI))
+}
+
+// Unregister removes the agent and its devices from the Node, e.g. when the agent stops
+func (r *NodeRegistrar) Unregister(ctx context.Context) error {
+	return r.update(ctx, func(node *corev1.Node) {
+		delete(node.Labels, AgentLabel)
+		delete(node.Annotations, EndpointAnnotation)
+		delete(node.Annotations, DevicesAnnotation)
+	}, nil)
+}
+
+// update applies mutate to the metadata of the Node and sets the capacity of DeviceResource, removing it if
+// devices is nil
+func (r *NodeRegistrar) update(ctx context.Context, mutate func(*corev1.Node), devices *resource.Quantity) error {
+	node := &corev1.Node{}
+	if err := r.Client.Get(ctx, client.ObjectKey{Name: r.NodeName}, node); err != nil {
+		return fmt.Errorf("unable to get node %s: %w", r.NodeName, err)
+	}
+
+	original := node.DeepCopy()
+	mutate(node)
+
+	if !equality.Semantic.DeepEqual(original.ObjectMeta, node.ObjectMeta) {
+		if err := r.Client.Patch(ctx, node, client.MergeFrom(original)); err != nil {
+			return fmt.Errorf("unable to patch node %s: %w", r.NodeName, err)
+		}
+	}
+
+	original = node.DeepCopy()
+	for _, list := range []*corev1.ResourceList{&node.Status.Capacity, &node.Status.Allocatable} {
+		if devices == nil {
+			delete(*list, DeviceResource)
+			continue
+		}
+
+		if *list == nil {
+			*list = corev1.ResourceList{}
+		}
+		(*list)[DeviceResource] = *devices
+	}
+
+	if !equality.Semantic.DeepEqual(original.Status, node.Status) {
+		if err := r.Client.Status().Patch(ctx, node, client.MergeFrom(original)); err != nil {
+			return fmt.Errorf("unable to patch status of node %s: %w", r.NodeName, err)
+		}
+	}
+
+	return nil
+}
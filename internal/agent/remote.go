@@ -0,0 +1,211 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package agent
+
+import (
+	"context"
+	"time"
+
+	"go.bug.st/serial"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/detiber/k8s-jumperless/internal/version"
+	"github.com/detiber/k8s-jumperless/jumperless"
+	"github.com/detiber/k8s-jumperless/jumperless/remotepb"
+)
+
+// IdentityKind is the kind of server the agent reports with the Remote service
+const IdentityKind = "agent"
+
+var _ jumperless.RemoteBackend = &Server{}
+
+// Identity implements jumperless.RemoteBackend, describing the node of the agent and the devices found by the
+// last discovery
+func (s *Server) Identity(_ context.Context) (*remotepb.Identity, error) {
+	identity := &remotepb.Identity{Name: s.Node, Kind: IdentityKind, Version: version.Get()}
+	for _, device := range s.Devices() {
+		identity.Devices = append(identity.Devices, &remotepb.Device{
+			Port:            device.Port,
+			SerialNumber:    device.SerialNumber,
+			Vid:             device.VID,
+			Pid:             device.PID,
+			FirmwareVersion: device.Version,
+		})
+	}
+
+	return identity, nil
+}
+
+// OpenPort implements jumperless.RemoteBackend, opening the port of a discovered device for a session that
+// lasts until the returned port is closed. The port of the only discovered device is opened if name is empty.
+func (s *Server) OpenPort(ctx context.Context, name string, mode *serial.Mode) (string, serial.Port, error) {
+	if name == "" {
+		devices := s.Devices()
+		if len(devices) != 1 {
+			return "", nil, status.Errorf(codes.InvalidArgument,
+				"%d devices discovered on node %s, the port has to be selected", len(devices), s.Node)
+		}
+
+		name = devices[0].Port
+	}
+
+	id, err := s.openSession(ctx, name, mode)
+	if err != nil {
+		return "", nil, statusError(err)
+	}
+
+	return name, &sessionPort{server: s, id: id}, nil
+}
+
+// sessionPort is the port of a session opened through the Remote service. Every call marks the session as
+// used, and fails once the session was closed, e.g. because another client opened the port.
+type sessionPort struct {
+	server *Server
+	id     string
+}
+
+var _ serial.Port = &sessionPort{}
+
+// port returns the port of the session
+func (p *sessionPort) port() (serial.Port, error) {
+	session, err := p.server.session(p.id)
+	if err != nil {
+		return nil, statusError(err)
+	}
+
+	return session.port, nil
+}
+
+// SetMode implements serial.Port
+func (p *sessionPort) SetMode(mode *serial.Mode) error {
+	port, err := p.port()
+	if err != nil {
+		return err
+	}
+
+	return port.SetMode(mode) //nolint:wrapcheck
+}
+
+// Read implements serial.Port
+func (p *sessionPort) Read(b []byte) (int, error) {
+	port, err := p.port()
+	if err != nil {
+		return 0, err
+	}
+
+	return port.Read(b) //nolint:wrapcheck
+}
+
+// Write implements serial.Port
+func (p *sessionPort) Write(b []byte) (int, error) {
+	port, err := p.port()
+	if err != nil {
+		return 0, err
+	}
+
+	return port.Write(b) //nolint:wrapcheck
+}
+
+// Drain implements serial.Port
+func (p *sessionPort) Drain() error {
+	port, err := p.port()
+	if err != nil {
+		return err
+	}
+
+	return port.Drain() //nolint:wrapcheck
+}
+
+// ResetInputBuffer implements serial.Port
+func (p *sessionPort) ResetInputBuffer() error {
+	port, err := p.port()
+	if err != nil {
+		return err
+	}
+
+	return port.ResetInputBuffer() //nolint:wrapcheck
+}
+
+// ResetOutputBuffer implements serial.Port
+func (p *sessionPort) ResetOutputBuffer() error {
+	port, err := p.port()
+	if err != nil {
+		return err
+	}
+
+	return port.ResetOutputBuffer() //nolint:wrapcheck
+}
+
+// SetDTR implements serial.Port
+func (p *sessionPort) SetDTR(dtr bool) error {
+	port, err := p.port()
+	if err != nil {
+		return err
+	}
+
+	return port.SetDTR(dtr) //nolint:wrapcheck
+}
+
+// SetRTS implements serial.Port
+func (p *sessionPort) SetRTS(rts bool) error {
+	port, err := p.port()
+	if err != nil {
+		return err
+	}
+
+	return port.SetRTS(rts) //nolint:wrapcheck
+}
+
+// GetModemStatusBits implements serial.Port
+func (p *sessionPort) GetModemStatusBits() (*serial.ModemStatusBits, error) {
+	port, err := p.port()
+	if err != nil {
+		return nil, err
+	}
+
+	return port.GetModemStatusBits() //nolint:wrapcheck
+}
+
+// SetReadTimeout implements serial.Port
+func (p *sessionPort) SetReadTimeout(t time.Duration) error {
+	port, err := p.port()
+	if err != nil {
+		return err
+	}
+
+	return port.SetReadTimeout(t) //nolint:wrapcheck
+}
+
+// Close implements serial.Port, closing the session
+func (p *sessionPort) Close() error {
+	if err := p.server.closeSession(context.Background(), p.id); err != nil {
+		return statusError(err)
+	}
+
+	return nil
+}
+
+// Break implements serial.Port
+func (p *sessionPort) Break(duration time.Duration) error {
+	port, err := p.port()
+	if err != nil {
+		return err
+	}
+
+	return port.Break(duration) //nolint:wrapcheck
+}
@@ -0,0 +1,336 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package agent
+
+import (
+	"context"
+	"crypto/rand"
+	"errors"
+	"fmt"
+	"path/filepath"
+	"slices"
+	"sync"
+	"time"
+
+	"go.bug.st/serial"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	ctrl "sigs.k8s.io/controller-runtime"
+
+	"github.com/detiber/k8s-jumperless/jumperless"
+)
+
+var ErrUnknownDevice = errors.New("no Jumperless device discovered on port")
+var ErrUnknownSession = errors.New("unknown port session")
+
+const (
+	// DefaultSessionIdleTimeout is the time after which the port of an unused session is closed, unless
+	// configured otherwise
+	DefaultSessionIdleTimeout = 2 * time.Minute
+)
+
+// DiscoverFunc discovers the Jumperless devices attached to the node, reporting the ports inUse returns the
+// firmware version of without probing them
+type DiscoverFunc func(ctx context.Context, baudRate int,
+	inUse func(portName string) (string, bool)) ([]jumperless.DeviceInfo, error)
+
+// Server serves the serial ports of the Jumperless devices attached to the node of the agent with the Remote
+// service. A client opens the port of a discovered device for a session, which lasts until it closes the port.
+type Server struct {
+	// Node is the name of the node the agent runs on
+	Node string
+
+	// BaudRate is the baud rate devices are probed with during discovery, 115200 if zero
+	BaudRate int
+
+	// SessionIdleTimeout is the time after which the port of an unused session is closed, e.g. a session left
+	// behind by a manager that was restarted. DefaultSessionIdleTimeout if zero.
+	SessionIdleTimeout time.Duration
+
+	// Discover discovers the devices, defaults to jumperless.DiscoverJumperlessDevicesExcept
+	Discover DiscoverFunc
+
+	// Opener opens the serial port of a device, defaults to locking the device node and opening it with
+	// jumperless.OpenSerialPort
+	Opener jumperless.Opener
+
+	// portsLock is held while discovering devices and opening ports, so a port is never probed while it is
+	// being opened
+	portsLock sync.Mutex
+
+	mu       sync.Mutex // Guards devices and sessions
+	devices  []Device
+	sessions map[string]*session
+}
+
+// session is a port opened by a client
+type session struct {
+	port     serial.Port
+	key      string       // The resolved name of the port
+	version  string       // The firmware version of the device, reported by discovery while the port is open
+	unlock   func() error // Unlocks the device node, if it was locked
+	lastUsed time.Time
+}
+
+// close closes the port of the session and unlocks its device node
+func (s *session) close() error {
+	err := s.port.Close()
+	if s.unlock != nil {
+		err = errors.Join(err, s.unlock())
+	}
+
+	return err //nolint:wrapcheck
+}
+
+// Register registers the Remote service on a gRPC server
+func (s *Server) Register(registrar grpc.ServiceRegistrar) {
+	jumperless.NewRemoteServer(s).Register(registrar)
+}
+
+// Devices returns the devices found by the last discovery
+func (s *Server) Devices() []Device {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return slices.Clone(s.devices)
+}
+
+// DiscoverDevices probes the serial ports of the node for Jumperless devices. The ports of open sessions are
+// not probed, they are reported with the firmware version found when they were opened.
+func (s *Server) DiscoverDevices(ctx context.Context) ([]Device, error) {
+	s.portsLock.Lock()
+	defer s.portsLock.Unlock()
+
+	discover := s.Discover
+	if discover == nil {
+		discover = jumperless.DiscoverJumperlessDevicesExcept
+	}
+
+	infos, err := discover(ctx, s.BaudRate, s.inUse)
+	if err != nil && !errors.Is(err, jumperless.ErrNoSerialPortFound) && len(infos) == 0 {
+		return nil, fmt.Errorf("unable to discover Jumperless devices: %w", err)
+	}
+
+	devices := make([]Device, 0, len(infos))
+	for _, info := range infos {
+		devices = append(devices, newDevice(info))
+	}
+
+	s.mu.Lock()
+	s.devices = devices
+	s.mu.Unlock()
+
+	// Errors probing individual ports are not fatal, the ports may not be Jumperless devices at all
+	if err != nil && !errors.Is(err, jumperless.ErrNoSerialPortFound) {
+		ctrl.LoggerFrom(ctx).Info("Errors encountered during discovery", "error", err.Error())
+	}
+
+	return slices.Clone(devices), nil
+}
+
+// inUse returns the firmware version of the device on a port that is open in a session
+func (s *Server) inUse(portName string) (string, bool) {
+	key := resolvePort(portName)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, session := range s.sessions {
+		if session.key == key {
+			return session.version, true
+		}
+	}
+
+	return "", false
+}
+
+// Run discovers the devices at the given interval until ctx is done, passing the devices found to register,
+// and closes the sessions that weren't used for too long. The open sessions are closed once ctx is done.
+func (s *Server) Run(ctx context.Context, interval time.Duration, register func(context.Context, []Device) error) {
+	log := ctrl.LoggerFrom(ctx)
+
+	defer s.closeSessions(ctx, func(*session) bool { return true })
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		devices, err := s.DiscoverDevices(ctx)
+		if err != nil {
+			log.Error(err, "Discovery failed")
+		} else {
+			log.V(1).Info("Discovered Jumperless devices", "devices", len(devices))
+			if err := register(ctx, devices); err != nil {
+				log.Error(err, "Unable to register the discovered devices")
+			}
+		}
+
+		idleTimeout := s.SessionIdleTimeout
+		if idleTimeout <= 0 {
+			idleTimeout = DefaultSessionIdleTimeout
+		}
+
+		s.closeSessions(ctx, func(session *session) bool { return time.Since(session.lastUsed) > idleTimeout })
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// closeSessions closes the sessions matching the filter
+func (s *Server) closeSessions(ctx context.Context, filter func(*session) bool) {
+	s.mu.Lock()
+	closing := map[string]*session{}
+	for id, session := range s.sessions {
+		if filter(session) {
+			closing[id] = session
+			delete(s.sessions, id)
+		}
+	}
+	s.mu.Unlock()
+
+	for id, session := range closing {
+		ctrl.LoggerFrom(ctx).Info("Closing port session", "session", id, "port", session.key)
+		if err := session.close(); err != nil {
+			ctrl.LoggerFrom(ctx).Error(err, "Unable to close port", "port", session.key)
+		}
+	}
+}
+
+// openSession opens the port of a discovered device for a new session, returning the ID of the session. A port
+// that is still open in another session, e.g. one left behind by a manager that was restarted, is closed first.
+func (s *Server) openSession(ctx context.Context, name string, mode *serial.Mode) (string, error) {
+	s.portsLock.Lock()
+	defer s.portsLock.Unlock()
+
+	key := resolvePort(name)
+
+	s.mu.Lock()
+	index := slices.IndexFunc(s.devices, func(device Device) bool { return resolvePort(device.Port) == key })
+	var version string
+	if index >= 0 {
+		version = s.devices[index].Version
+	}
+	s.mu.Unlock()
+
+	if index < 0 {
+		return "", fmt.Errorf("%w %s", ErrUnknownDevice, name)
+	}
+
+	s.closeSessions(ctx, func(session *session) bool { return session.key == key })
+
+	port, unlock, err := s.openPort(name, mode)
+	if err != nil {
+		return "", err
+	}
+
+	id := rand.Text()
+
+	s.mu.Lock()
+	if s.sessions == nil {
+		s.sessions = map[string]*session{}
+	}
+	s.sessions[id] = &session{port: port, key: key, version: version, unlock: unlock, lastUsed: time.Now()}
+	s.mu.Unlock()
+
+	ctrl.LoggerFrom(ctx).Info("Opened port session", "session", id, "port", key)
+
+	return id, nil
+}
+
+// openPort opens a serial port, returning a function to unlock its device node if it was locked
+func (s *Server) openPort(name string, mode *serial.Mode) (serial.Port, func() error, error) {
+	if s.Opener != nil {
+		port, err := s.Opener(name, mode)
+		if err != nil {
+			return nil, nil, fmt.Errorf("unable to open serial port %s: %w", name, err)
+		}
+
+		return port, nil, nil
+	}
+
+	lock, err := jumperless.LockDevice(name)
+	if err != nil {
+		return nil, nil, err //nolint:wrapcheck
+	}
+
+	port, err := jumperless.OpenSerialPort(name, mode)
+	if err != nil {
+		_ = lock.Unlock()
+		return nil, nil, fmt.Errorf("unable to open serial port %s: %w", name, err)
+	}
+
+	return port, lock.Unlock, nil
+}
+
+// session returns the session with the given ID, marking it as used
+func (s *Server) session(id string) (*session, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	session, ok := s.sessions[id]
+	if !ok {
+		return nil, fmt.Errorf("%w %q", ErrUnknownSession, id)
+	}
+
+	session.lastUsed = time.Now()
+
+	return session, nil
+}
+
+// closeSession closes the port of a session
+func (s *Server) closeSession(ctx context.Context, id string) error {
+	s.mu.Lock()
+	session, ok := s.sessions[id]
+	delete(s.sessions, id)
+	s.mu.Unlock()
+
+	if !ok {
+		return fmt.Errorf("%w %q", ErrUnknownSession, id)
+	}
+
+	ctrl.LoggerFrom(ctx).Info("Closed port session", "session", id, "port", session.key)
+
+	return session.close()
+}
+
+// resolvePort returns the name of the device node a port name links to, so symlinks such as /dev/serial/by-id
+// paths refer to the same port as the device node
+func resolvePort(portName string) string {
+	resolved, err := filepath.EvalSymlinks(portName)
+	if err != nil {
+		return filepath.Clean(portName)
+	}
+
+	return resolved
+}
+
+// statusError returns the gRPC status of an error returned by the server
+func statusError(err error) error {
+	switch {
+	case errors.Is(err, ErrUnknownDevice), errors.Is(err, ErrUnknownSession):
+		return status.Error(codes.NotFound, err.Error()) //nolint:wrapcheck
+	case errors.Is(err, jumperless.ErrDeviceLocked):
+		return status.Error(codes.FailedPrecondition, err.Error()) //nolint:wrapcheck
+	default:
+		return status.Error(codes.Unavailable, err.Error()) //nolint:wrapcheck
+	}
+}
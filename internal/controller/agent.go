@@ -0,0 +1,159 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+
+	"go.bug.st/serial/enumerator"
+	"k8s.io/utils/ptr"
+
+	jumperlessv5alpha1 "github.com/detiber/k8s-jumperless/api/v5alpha1"
+	"github.com/detiber/k8s-jumperless/internal/agent"
+	"github.com/detiber/k8s-jumperless/jumperless"
+)
+
+var ErrAgentsDisabled = errors.New("devices driven through agents require connections to the agents")
+
+// acquireAgentPort returns a handle to the device on the port or selector of a Jumperless attached to another
+// node, whose serial port is driven by the agent of that node. If ports is nil the port is opened just for the
+// caller.
+func acquireAgentPort(ctx context.Context, ports *jumperless.PortManager, agents *agent.Pool,
+	host *jumperlessv5alpha1.JumperlessHostLocal) (*jumperless.PortHandle, error) {
+	nodeName := ptr.Deref(host.NodeName, "")
+	if agents == nil {
+		return nil, fmt.Errorf("%w: node %s", ErrAgentsDisabled, nodeName)
+	}
+
+	if ports == nil {
+		ports = jumperless.NewPortManager()
+	}
+
+	client, err := agents.Agent(ctx, nodeName)
+	if err != nil {
+		return nil, err //nolint:wrapcheck
+	}
+
+	devices, err := client.ListDevices(ctx)
+	if err != nil {
+		return nil, err //nolint:wrapcheck
+	}
+
+	device, err := selectAgentDevice(devices, host)
+	if err != nil {
+		return nil, fmt.Errorf("node %s: %w", nodeName, err)
+	}
+
+	// Ports of other nodes may have the same names as the local ones, so they are shared by node and name
+	key := "node:" + nodeName + ":" + device.Port
+
+	return ports.AcquireWithOpener(key, device.Info(), int(ptr.Deref(host.BaudRate, 0)), //nolint:wrapcheck
+		client.Opener())
+}
+
+// acquireRemotePort returns a handle to the device on the port of a Jumperless driven through the Remote
+// service at an endpoint. If ports is nil the port is opened just for the caller.
+func acquireRemotePort(ctx context.Context, ports *jumperless.PortManager, agents *agent.Pool,
+	host *jumperlessv5alpha1.JumperlessHostAgent) (*jumperless.PortHandle, error) {
+	if agents == nil {
+		return nil, fmt.Errorf("%w: endpoint %s", ErrAgentsDisabled, host.Endpoint)
+	}
+
+	if ports == nil {
+		ports = jumperless.NewPortManager()
+	}
+
+	client, err := agents.Remote(host.Endpoint)
+	if err != nil {
+		return nil, err //nolint:wrapcheck
+	}
+
+	devices, err := client.Devices(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("endpoint %s: %w", host.Endpoint, err)
+	}
+
+	device, err := selectRemoteDevice(devices, ptr.Deref(host.Port, ""))
+	if err != nil {
+		return nil, fmt.Errorf("endpoint %s: %w", host.Endpoint, err)
+	}
+
+	// Like the ports of other nodes, ports of an endpoint are shared by endpoint and name
+	key := "endpoint:" + host.Endpoint + ":" + device.Port
+
+	return ports.AcquireWithOpener(key, device, int(ptr.Deref(host.BaudRate, 0)), //nolint:wrapcheck
+		client.Opener())
+}
+
+// selectRemoteDevice returns the device reported by a Remote service on the port, or its only device if port is
+// empty. A port the service reports no device for is returned as it is, the service decides whether it opens it.
+func selectRemoteDevice(devices []jumperless.DeviceInfo, port string) (jumperless.DeviceInfo, error) {
+	if port != "" {
+		if i := slices.IndexFunc(devices, func(device jumperless.DeviceInfo) bool {
+			return device.Port == port
+		}); i >= 0 {
+			return devices[i], nil
+		}
+
+		return jumperless.DeviceInfo{Port: port}, nil
+	}
+
+	if len(devices) != 1 {
+		return jumperless.DeviceInfo{}, fmt.Errorf("%d devices reported, a port must be specified: %w", len(devices),
+			jumperless.ErrNoJumperlessFound)
+	}
+
+	return devices[0], nil
+}
+
+// selectAgentDevice returns the device discovered by an agent on the port or matching the selector of a
+// Jumperless. A port the agent reports no device for is returned as it is, the agent refuses to open it
+// unless it resolves to the port of a discovered device, e.g. a /dev/serial/by-id path.
+func selectAgentDevice(devices []agent.Device,
+	host *jumperlessv5alpha1.JumperlessHostLocal) (agent.Device, error) {
+	if port := ptr.Deref(host.Port, ""); port != "" {
+		if i := slices.IndexFunc(devices, func(device agent.Device) bool { return device.Port == port }); i >= 0 {
+			return devices[i], nil
+		}
+
+		return agent.Device{Port: port}, nil
+	}
+
+	selector := jumperless.PortSelector{
+		SerialNumber: ptr.Deref(host.SerialNumber, ""),
+		VID:          ptr.Deref(host.VID, ""),
+		PID:          ptr.Deref(host.PID, ""),
+	}
+
+	for _, device := range devices {
+		details := &enumerator.PortDetails{
+			Name:         device.Port,
+			SerialNumber: device.SerialNumber,
+			VID:          device.VID,
+			PID:          device.PID,
+		}
+		if selector.Matches(details) {
+			return device, nil
+		}
+	}
+
+	return agent.Device{}, fmt.Errorf("no discovered device matches selector %+v: %w", selector,
+		jumperless.ErrNoJumperlessFound)
+}
@@ -0,0 +1,144 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"k8s.io/utils/ptr"
+
+	jumperlessv5alpha1 "github.com/detiber/k8s-jumperless/api/v5alpha1"
+	"github.com/detiber/k8s-jumperless/internal/agent"
+	"github.com/detiber/k8s-jumperless/jumperless"
+)
+
+var _ = Describe("Jumperless Agent", func() {
+	Context("When a device is attached to another node", func() {
+		devices := []agent.Device{
+			{Port: "/dev/ttyACM0", SerialNumber: "E6614103E7", VID: "1D50", PID: "ACAB", Version: "5.3.1.0"},
+			{Port: "/dev/ttyACM2", SerialNumber: "E6614103E8", VID: "1D50", PID: "ACAB", Version: "5.3.1.0"},
+		}
+
+		It("should select the device discovered by the agent", func() {
+			By("Selecting a device by its port")
+			device, err := selectAgentDevice(devices, &jumperlessv5alpha1.JumperlessHostLocal{
+				Port: ptr.To("/dev/ttyACM2"),
+			})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(device).To(Equal(devices[1]))
+
+			By("Leaving ports without a discovered device to the agent")
+			device, err = selectAgentDevice(devices, &jumperlessv5alpha1.JumperlessHostLocal{
+				Port: ptr.To("/dev/serial/by-id/usb-Jumperless"),
+			})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(device).To(Equal(agent.Device{Port: "/dev/serial/by-id/usb-Jumperless"}))
+
+			By("Selecting a device by its USB metadata")
+			device, err = selectAgentDevice(devices, &jumperlessv5alpha1.JumperlessHostLocal{
+				SerialNumber: ptr.To("E6614103E8"),
+				VID:          ptr.To("1d50"),
+			})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(device).To(Equal(devices[1]))
+
+			_, err = selectAgentDevice(devices, &jumperlessv5alpha1.JumperlessHostLocal{
+				SerialNumber: ptr.To("E6614103E9"),
+			})
+			Expect(err).To(MatchError(jumperless.ErrNoJumperlessFound))
+		})
+
+		It("should claim the port per node", func() {
+			instance := &jumperlessv5alpha1.Jumperless{
+				Spec: jumperlessv5alpha1.JumperlessSpec{
+					Host: jumperlessv5alpha1.JumperlessHost{
+						Local: &jumperlessv5alpha1.JumperlessHostLocal{
+							Port:     ptr.To("/dev/ttyACM0"),
+							NodeName: ptr.To("node-a"),
+						},
+					},
+				},
+			}
+			Expect(portClaims(instance)).To(ConsistOf("node:node-a/port:/dev/ttyACM0"))
+
+			instance.Spec.Host.Local.Port = nil
+			instance.Spec.Host.Local.SerialNumber = ptr.To("E6614103E7")
+			Expect(portClaims(instance)).To(ConsistOf("node:node-a/selector:E6614103E7//"))
+		})
+
+		It("should refuse devices on other nodes without agent connections", func() {
+			_, err := acquireLocalPort(context.Background(), nil, nil, &jumperlessv5alpha1.JumperlessHostLocal{
+				Port:     ptr.To("/dev/ttyACM0"),
+				NodeName: ptr.To("node-a"),
+			})
+			Expect(err).To(MatchError(ErrAgentsDisabled))
+		})
+	})
+
+	Context("When a device is driven through the Remote service of an agent", func() {
+		devices := []jumperless.DeviceInfo{
+			{Port: "/dev/ttyACM0", SerialNumber: "E6614103E7", VID: "1D50", PID: "ACAB", Version: "5.3.1.0"},
+			{Port: "/dev/ttyACM2", SerialNumber: "E6614103E8", VID: "1D50", PID: "ACAB", Version: "5.3.1.0"},
+		}
+
+		It("should select the device reported by the service", func() {
+			device, err := selectRemoteDevice(devices, "/dev/ttyACM2")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(device).To(Equal(devices[1]))
+
+			device, err = selectRemoteDevice(devices, "/dev/ttyUSB0")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(device).To(Equal(jumperless.DeviceInfo{Port: "/dev/ttyUSB0"}))
+
+			By("Selecting the only device if no port is specified")
+			device, err = selectRemoteDevice(devices[:1], "")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(device).To(Equal(devices[0]))
+
+			_, err = selectRemoteDevice(devices, "")
+			Expect(err).To(MatchError(jumperless.ErrNoJumperlessFound))
+		})
+
+		It("should claim the port per endpoint", func() {
+			instance := &jumperlessv5alpha1.Jumperless{
+				Spec: jumperlessv5alpha1.JumperlessSpec{
+					Host: jumperlessv5alpha1.JumperlessHost{
+						Agent: &jumperlessv5alpha1.JumperlessHostAgent{
+							Endpoint: "proxy.lab:9445",
+							Port:     ptr.To("/dev/ttyACM0"),
+						},
+					},
+				},
+			}
+			Expect(portClaims(instance)).To(ConsistOf("endpoint:proxy.lab:9445/port:/dev/ttyACM0"))
+
+			instance.Spec.Host.Agent.Port = nil
+			instance.Status.LocalPort = ptr.To("/dev/ttyACM0")
+			Expect(portClaims(instance)).To(ConsistOf("endpoint:proxy.lab:9445/port:*",
+				"endpoint:proxy.lab:9445/port:/dev/ttyACM0"))
+		})
+
+		It("should refuse devices without agent connections", func() {
+			_, err := acquirePort(context.Background(), nil, nil, &jumperlessv5alpha1.JumperlessHost{
+				Agent: &jumperlessv5alpha1.JumperlessHostAgent{Endpoint: "proxy.lab:9445"},
+			})
+			Expect(err).To(MatchError(ErrAgentsDisabled))
+		})
+	})
+})
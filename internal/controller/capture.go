@@ -0,0 +1,389 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/utils/ptr"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+
+	jumperlessv5alpha1 "github.com/detiber/k8s-jumperless/api/v5alpha1"
+	"github.com/detiber/k8s-jumperless/internal/controller/local"
+	"github.com/detiber/k8s-jumperless/jumperless"
+	"github.com/detiber/k8s-jumperless/jumperless/voltage"
+)
+
+var ErrCaptureDirNotConfigured = errors.New("no capture directory is configured, see --capture-dir")
+var ErrInvalidCapturePath = errors.New("invalid capture path")
+var ErrCaptureTooLarge = errors.New("capture doesn't fit into the ConfigMap")
+var ErrCaptureConfigMapNotOwned = errors.New("ConfigMap isn't owned by the Jumperless")
+
+// defaultCaptureInterval is the time between two samples of a capture that doesn't set it.
+const defaultCaptureInterval = 10 * time.Millisecond
+
+// captureChunkDuration is how long a capture samples the channel in a single reconcile, so the samples are
+// streamed to the target and the progress is reported while the capture is running.
+const captureChunkDuration = 10 * time.Second
+
+// captureRetryInterval is the interval between the reconciles sampling the channel of a running capture.
+const captureRetryInterval = time.Second
+
+// maxConfigMapCaptureSize is the size of the samples a ConfigMap target holds, leaving room for the other keys
+// and the metadata of the ConfigMap below its limit of 1MiB.
+const maxConfigMapCaptureSize = 900 * 1024
+
+// captureHeader is the first line of the samples written to a target.
+const captureHeader = "seconds,volts\n"
+
+// captureInterval returns the time between two samples of the capture.
+func captureInterval(capture *jumperlessv5alpha1.Capture) time.Duration {
+	if capture.Interval == nil {
+		return defaultCaptureInterval
+	}
+
+	return capture.Interval.Duration
+}
+
+// captureSlot returns the index of the first sample of a capture started at start that is due at or after now.
+func captureSlot(start time.Time, interval time.Duration, now time.Time) int64 {
+	elapsed := now.Sub(start)
+	if elapsed <= 0 {
+		return 0
+	}
+
+	return int64((elapsed + interval - 1) / interval)
+}
+
+// captureProgress returns the percentage of the duration of a capture started at start that passed by now.
+func captureProgress(start time.Time, duration time.Duration, now time.Time) int32 {
+	elapsed := now.Sub(start)
+	switch {
+	case elapsed <= 0:
+		return 0
+	case duration <= 0 || elapsed >= duration:
+		return 100
+	default:
+		return int32(elapsed * 100 / duration)
+	}
+}
+
+// formatCaptureSample formats a sample taken the offset after the start of a capture as a line of the target.
+func formatCaptureSample(offset time.Duration, v float64) string {
+	return fmt.Sprintf("%.3f,%s\n", offset.Seconds(), voltage.FormatValue(v))
+}
+
+// captureFilePath returns the file a capture with a file target is written to, below the directory of the
+// Jumperless in the capture directory.
+func captureFilePath(dir string, instance *jumperlessv5alpha1.Jumperless, path string) (string, error) {
+	if dir == "" {
+		return "", ErrCaptureDirNotConfigured
+	}
+
+	if !filepath.IsLocal(path) {
+		return "", fmt.Errorf("%w: %s must be relative and stay below the capture directory",
+			ErrInvalidCapturePath, path)
+	}
+
+	return filepath.Join(dir, instance.Namespace, instance.Name, path), nil
+}
+
+// captureTarget is where the samples of a capture are written.
+type captureTarget interface {
+	// location describes the target in status.capture.location
+	location() string
+
+	// write appends data to the target, replacing what was written before if reset is set
+	write(ctx context.Context, data string, reset bool) error
+}
+
+// configMapCaptureTarget writes the samples of a capture to a key of a ConfigMap, creating it owned by the
+// Jumperless if needed. Existing ConfigMaps are only written if they belong to the Jumperless, see
+// ownsCaptureConfigMap.
+type configMapCaptureTarget struct {
+	client client.Client
+	scheme *runtime.Scheme
+	owner  *jumperlessv5alpha1.Jumperless
+	key    client.ObjectKey
+	field  string
+}
+
+func (t *configMapCaptureTarget) location() string {
+	return fmt.Sprintf("configmap://%s/%s/%s", t.key.Namespace, t.key.Name, t.field)
+}
+
+func (t *configMapCaptureTarget) write(ctx context.Context, data string, reset bool) error {
+	configMap := &corev1.ConfigMap{}
+	err := t.client.Get(ctx, t.key, configMap)
+	if apierrors.IsNotFound(err) {
+		if len(data) > maxConfigMapCaptureSize {
+			return ErrCaptureTooLarge
+		}
+
+		configMap = &corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{Name: t.key.Name, Namespace: t.key.Namespace},
+			Data:       map[string]string{t.field: data},
+		}
+		if err := controllerutil.SetOwnerReference(t.owner, configMap, t.scheme); err != nil {
+			return fmt.Errorf("unable to set owner of ConfigMap %s: %w", t.key, err)
+		}
+		if err := t.client.Create(ctx, configMap); err != nil {
+			return fmt.Errorf("unable to create ConfigMap %s: %w", t.key, err)
+		}
+
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("unable to get ConfigMap %s: %w", t.key, err)
+	}
+
+	if !ownsCaptureConfigMap(t.owner, configMap) {
+		return fmt.Errorf("%w: %s has neither an owner reference to it nor the %s label set to its name",
+			ErrCaptureConfigMapNotOwned, t.key, jumperlessv5alpha1.CaptureLabel)
+	}
+
+	if configMap.Data == nil {
+		configMap.Data = map[string]string{}
+	}
+	if reset {
+		configMap.Data[t.field] = data
+	} else {
+		configMap.Data[t.field] += data
+	}
+
+	if len(configMap.Data[t.field]) > maxConfigMapCaptureSize {
+		return ErrCaptureTooLarge
+	}
+
+	if err := t.client.Update(ctx, configMap); err != nil {
+		return fmt.Errorf("unable to update ConfigMap %s: %w", t.key, err)
+	}
+
+	return nil
+}
+
+// ownsCaptureConfigMap returns whether the captures of a Jumperless may write to a ConfigMap, i.e. whether the
+// ConfigMap has an owner reference to the Jumperless or carries the capture label set to its name.
+func ownsCaptureConfigMap(owner *jumperlessv5alpha1.Jumperless, configMap *corev1.ConfigMap) bool {
+	for _, ref := range configMap.OwnerReferences {
+		if ref.UID == owner.UID {
+			return true
+		}
+	}
+
+	return configMap.Labels[jumperlessv5alpha1.CaptureLabel] == owner.Name
+}
+
+// fileCaptureTarget writes the samples of a capture to a file, creating its directory if needed.
+type fileCaptureTarget struct {
+	path string
+}
+
+func (t *fileCaptureTarget) location() string {
+	return "file://" + t.path
+}
+
+func (t *fileCaptureTarget) write(_ context.Context, data string, reset bool) error {
+	if err := os.MkdirAll(filepath.Dir(t.path), 0o755); err != nil {
+		return fmt.Errorf("unable to create capture directory: %w", err)
+	}
+
+	flags := os.O_WRONLY | os.O_CREATE | os.O_APPEND
+	if reset {
+		flags |= os.O_TRUNC
+	}
+
+	f, err := os.OpenFile(t.path, flags, 0o644)
+	if err != nil {
+		return fmt.Errorf("unable to open capture file: %w", err)
+	}
+
+	if _, err := f.WriteString(data); err != nil {
+		_ = f.Close()
+		return fmt.Errorf("unable to write capture file: %w", err)
+	}
+
+	if err := f.Close(); err != nil {
+		return fmt.Errorf("unable to write capture file: %w", err)
+	}
+
+	return nil
+}
+
+// captureTarget returns the target the samples of the capture are written to.
+func (r *JumperlessReconciler) captureTarget(instance *jumperlessv5alpha1.Jumperless,
+	capture *jumperlessv5alpha1.Capture) (captureTarget, error) {
+	switch {
+	case capture.Target.ConfigMap != nil:
+		field := capture.Target.ConfigMap.Key
+		if field == "" {
+			field = capture.Name + ".csv"
+		}
+
+		return &configMapCaptureTarget{
+			client: r.Client,
+			scheme: r.Scheme,
+			owner:  instance,
+			key:    client.ObjectKey{Namespace: instance.Namespace, Name: capture.Target.ConfigMap.Name},
+			field:  field,
+		}, nil
+	case capture.Target.File != nil:
+		path, err := captureFilePath(r.CaptureDir, instance, capture.Target.File.Path)
+		if err != nil {
+			return nil, err
+		}
+
+		return &fileCaptureTarget{path: path}, nil
+	default:
+		return nil, fmt.Errorf("%w: no target is set", ErrInvalidCapturePath)
+	}
+}
+
+// endCapture records the end of a capture, emitting an event about it.
+func (r *JumperlessReconciler) endCapture(instance *jumperlessv5alpha1.Jumperless,
+	capture *jumperlessv5alpha1.CaptureStatus, captureErr error, now time.Time) {
+	capture.CompletionTime = ptr.To(metav1.NewTime(now))
+
+	if captureErr != nil {
+		capture.Phase = jumperlessv5alpha1.CaptureFailed
+		capture.Message = captureErr.Error()
+		if r.Recorder != nil {
+			r.Recorder.Eventf(instance, corev1.EventTypeWarning, "CaptureFailed", "Capture %s failed: %s",
+				capture.Name, captureErr)
+		}
+
+		return
+	}
+
+	capture.Phase = jumperlessv5alpha1.CaptureCompleted
+	capture.Progress = 100
+	if r.Recorder != nil {
+		r.Recorder.Eventf(instance, corev1.EventTypeNormal, "CaptureCompleted",
+			"Capture %s completed with %d samples written to %s", capture.Name, capture.Samples, capture.Location)
+	}
+}
+
+// reconcileCapture starts the capture in the spec if it wasn't started yet, and samples the channel of a
+// running capture for up to captureChunkDuration, appending the samples to its target. Samples that were due
+// between two reconciles are skipped. A capture whose samples can't be written fails and is not retried,
+// failing to read the device fails the reconcile.
+func (r *JumperlessReconciler) reconcileCapture(ctx context.Context, j *jumperless.Jumperless,
+	instance *jumperlessv5alpha1.Jumperless, status *jumperlessv5alpha1.JumperlessStatus) error {
+	log := ctrl.LoggerFrom(ctx)
+
+	spec := instance.Spec.Capture
+	if spec == nil {
+		return nil
+	}
+
+	capture := status.Capture.DeepCopy()
+	defer func() { status.Capture = capture }()
+
+	if capture == nil || capture.Name != spec.Name {
+		now := time.Now()
+		log.Info("Starting capture", "capture", spec.Name, "channel", spec.Channel,
+			"interval", captureInterval(spec), "duration", spec.Duration.Duration)
+
+		capture = &jumperlessv5alpha1.CaptureStatus{
+			Name:      spec.Name,
+			Phase:     jumperlessv5alpha1.CaptureRunning,
+			StartTime: metav1.NewTime(now),
+		}
+
+		target, err := r.captureTarget(instance, spec)
+		if err == nil {
+			capture.Location = target.location()
+			err = target.write(ctx, captureHeader, true)
+		}
+		if err != nil {
+			log.Error(err, "unable to start capture", "capture", spec.Name)
+			r.endCapture(instance, capture, err, now)
+			return nil
+		}
+	}
+
+	if capture.Phase != jumperlessv5alpha1.CaptureRunning {
+		return nil
+	}
+
+	target, err := r.captureTarget(instance, spec)
+	if err != nil {
+		r.endCapture(instance, capture, err, time.Now())
+		return nil
+	}
+
+	interval := captureInterval(spec)
+	start := capture.StartTime.Time
+	end := start.Add(spec.Duration.Duration)
+	chunkEnd := time.Now().Add(captureChunkDuration)
+	if chunkEnd.After(end) {
+		chunkEnd = end
+	}
+
+	var samples strings.Builder
+	var count int64
+	for slot := captureSlot(start, interval, time.Now()); ; {
+		at := start.Add(time.Duration(slot) * interval)
+		if !at.Before(chunkEnd) {
+			break
+		}
+
+		select {
+		case <-ctx.Done():
+			return fmt.Errorf("unable to sample capture %s: %w", spec.Name, ctx.Err())
+		case <-time.After(time.Until(at)):
+		}
+
+		v, err := local.GetADC(j, spec.Channel)
+		if err != nil {
+			return fmt.Errorf("unable to sample capture %s: %w", spec.Name, err)
+		}
+
+		samples.WriteString(formatCaptureSample(time.Since(start), v))
+		count++
+
+		slot = max(slot+1, captureSlot(start, interval, time.Now()))
+	}
+
+	now := time.Now()
+	if err := target.write(ctx, samples.String(), false); err != nil {
+		log.Error(err, "unable to write capture", "capture", spec.Name)
+		r.endCapture(instance, capture, err, now)
+		return nil
+	}
+
+	capture.Samples += count
+	capture.Progress = captureProgress(start, spec.Duration.Duration, now)
+	if !now.Before(end) {
+		log.Info("Capture completed", "capture", spec.Name, "samples", capture.Samples)
+		r.endCapture(instance, capture, nil, now)
+	}
+
+	return nil
+}
@@ -0,0 +1,141 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"golang.org/x/net/websocket"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	jumperlessv5alpha1 "github.com/detiber/k8s-jumperless/api/v5alpha1"
+	"github.com/detiber/k8s-jumperless/internal/agent"
+	"github.com/detiber/k8s-jumperless/jumperless"
+)
+
+// ConsolePath is the path the console of a Jumperless is served below, followed by its namespace and name,
+// e.g. /console/default/jumperless-sample
+const ConsolePath = "/console/"
+
+// Console is an HTTP handler attaching a websocket to the raw serial console of a Jumperless, so users can
+// work interactively with a device that only the manager can reach. Input and output are sent as binary
+// messages. It is served by the metrics server, so access is authorized per device through the
+// nonResourceURLs of the path of its console.
+type Console struct {
+	// Client reads the Jumperless resources
+	Client client.Reader
+
+	// Ports shares the device ports with the controllers, commands sent by the controllers fail while a
+	// console is attached
+	Ports *jumperless.PortManager
+
+	// Agents connects to the agents driving the devices attached to other nodes, if nil their consoles fail
+	Agents *agent.Pool
+
+	// Identity identifies the manager in the leases of the devices it drives, consoles of devices whose lease
+	// the manager doesn't hold are refused
+	Identity string
+}
+
+// ServeHTTP implements http.Handler
+func (c *Console) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	log := ctrl.Log.WithName("console")
+
+	namespace, name, ok := strings.Cut(strings.TrimPrefix(req.URL.Path, ConsolePath), "/")
+	if !ok || namespace == "" || name == "" || strings.Contains(name, "/") {
+		http.Error(w, "expected "+ConsolePath+"<namespace>/<name>", http.StatusNotFound)
+		return
+	}
+
+	key := types.NamespacedName{Namespace: namespace, Name: name}
+	instance := &jumperlessv5alpha1.Jumperless{}
+	if err := c.Client.Get(req.Context(), key, instance); err != nil {
+		if apierrors.IsNotFound(err) {
+			http.Error(w, fmt.Sprintf("Jumperless %s not found", key), http.StatusNotFound)
+			return
+		}
+
+		log.Error(err, "unable to fetch Jumperless", "jumperless", key)
+		http.Error(w, "unable to fetch Jumperless", http.StatusInternalServerError)
+
+		return
+	}
+
+	if instance.Spec.Host.Local == nil && instance.Spec.Host.Agent == nil {
+		http.Error(w, "consoles of devices on remote hosts are not supported", http.StatusNotImplemented)
+		return
+	}
+
+	// Like commands, consoles are only served by the manager holding the lease of the device, so another
+	// replica of the manager never opens a session to the device concurrently
+	takeover := instance.GetAnnotations()[jumperlessv5alpha1.TakeoverAnnotation]
+	if c.Identity != "" && !holdsLease(instance.Status.HeldBy, c.Identity, takeover, time.Now()) {
+		holder := takeover
+		if holder == "" {
+			holder = previousHolder(instance.Status.HeldBy)
+		}
+
+		message := fmt.Sprintf("Jumperless %s is driven by manager %s", key, holder)
+		if holder == "" || holder == c.Identity {
+			message = fmt.Sprintf("Jumperless %s is not driven by this manager yet", key)
+		}
+
+		http.Error(w, message, http.StatusConflict)
+		return
+	}
+
+	handle, err := acquirePort(req.Context(), c.Ports, c.Agents, &instance.Spec.Host)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("unable to open Jumperless port: %v", err), http.StatusServiceUnavailable)
+		return
+	}
+	defer func() {
+		if err := handle.Release(); err != nil {
+			log.Error(err, "unable to release Jumperless port", "port", handle.Jumperless().GetPort())
+		}
+	}()
+
+	server := websocket.Server{
+		// Clients authenticate with a bearer token rather than cookies, so requests from any origin are accepted
+		Handshake: func(*websocket.Config, *http.Request) error { return nil },
+		Handler: func(ws *websocket.Conn) {
+			defer func() { _ = ws.Close() }()
+			ws.PayloadType = websocket.BinaryFrame
+
+			j := handle.Jumperless()
+			log.Info("Console attached", "jumperless", key, "port", j.GetPort())
+
+			err := j.Console(req.Context(), ws)
+			if errors.Is(err, jumperless.ErrConsoleAttached) {
+				_, _ = ws.Write([]byte("another console is attached to the device\r\n"))
+			} else if err != nil {
+				log.Error(err, "console failed", "jumperless", key)
+			}
+
+			log.Info("Console detached", "jumperless", key, "port", j.GetPort())
+		},
+	}
+
+	server.ServeHTTP(w, req)
+}
@@ -0,0 +1,85 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/ptr"
+
+	jumperlessv5alpha1 "github.com/detiber/k8s-jumperless/api/v5alpha1"
+)
+
+var _ = Describe("Jumperless Console", func() {
+	Context("When attaching to the console of a device", func() {
+		ctx := context.Background()
+
+		resource := &jumperlessv5alpha1.Jumperless{
+			ObjectMeta: metav1.ObjectMeta{Name: "console-held", Namespace: "default"},
+			Spec: jumperlessv5alpha1.JumperlessSpec{
+				Host: jumperlessv5alpha1.JumperlessHost{
+					Local: &jumperlessv5alpha1.JumperlessHostLocal{Port: ptr.To("/dev/ttyJumperlessConsole")},
+				},
+			},
+		}
+
+		BeforeEach(func() {
+			instance := resource.DeepCopy()
+			Expect(k8sClient.Create(ctx, instance)).To(Succeed())
+
+			instance.Status.HeldBy = &jumperlessv5alpha1.DeviceLease{
+				HolderIdentity:       "other",
+				AcquireTime:          metav1.Now(),
+				RenewTime:            metav1.Now(),
+				LeaseDurationSeconds: int32(time.Minute / time.Second),
+			}
+			Expect(k8sClient.Status().Update(ctx, instance)).To(Succeed())
+		})
+
+		AfterEach(func() {
+			Expect(k8sClient.Delete(ctx, resource.DeepCopy())).To(Succeed())
+		})
+
+		It("should refuse consoles it can't serve without opening the port", func() {
+			console := &Console{Client: k8sClient, Identity: "self"}
+
+			serve := func(path string) *httptest.ResponseRecorder {
+				recorder := httptest.NewRecorder()
+				console.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, path, nil))
+
+				return recorder
+			}
+
+			By("Refusing malformed paths")
+			Expect(serve(ConsolePath + "default").Code).To(Equal(http.StatusNotFound))
+
+			By("Refusing unknown devices")
+			Expect(serve(ConsolePath + "default/missing").Code).To(Equal(http.StatusNotFound))
+
+			By("Refusing devices driven by another manager")
+			response := serve(ConsolePath + "default/console-held")
+			Expect(response.Code).To(Equal(http.StatusConflict))
+			Expect(response.Body.String()).To(ContainSubstring("other"))
+		})
+	})
+})
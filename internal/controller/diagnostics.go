@@ -0,0 +1,59 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"encoding/json"
+	"net/http"
+	"runtime"
+
+	ctrl "sigs.k8s.io/controller-runtime"
+
+	"github.com/detiber/k8s-jumperless/jumperless"
+)
+
+// DiagnosticsReport is the runtime state returned by Diagnostics
+type DiagnosticsReport struct {
+	// Goroutines is the number of goroutines of the manager, see /debug/pprof/goroutine for their stacks
+	Goroutines int `json:"goroutines"`
+
+	// Ports is the state of the serial ports open for the controllers
+	Ports []jumperless.PortState `json:"ports"`
+}
+
+// Diagnostics is an HTTP handler dumping the state of the serial ports shared by the controllers as JSON,
+// e.g. the commands in progress and queued for each device, to debug wedged devices without restarting
+// the manager.
+type Diagnostics struct {
+	Ports *jumperless.PortManager
+}
+
+// ServeHTTP implements http.Handler
+func (d *Diagnostics) ServeHTTP(w http.ResponseWriter, _ *http.Request) {
+	report := DiagnosticsReport{
+		Goroutines: runtime.NumGoroutine(),
+		Ports:      d.Ports.State(),
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(report); err != nil {
+		ctrl.Log.WithName("diagnostics").Error(err, "Failed to write diagnostics")
+	}
+}
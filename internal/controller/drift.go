@@ -0,0 +1,186 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/ptr"
+	ctrl "sigs.k8s.io/controller-runtime"
+
+	jumperlessv5alpha1 "github.com/detiber/k8s-jumperless/api/v5alpha1"
+	"github.com/detiber/k8s-jumperless/internal/controller/local"
+	"github.com/detiber/k8s-jumperless/jumperless"
+	"github.com/detiber/k8s-jumperless/jumperless/voltage"
+)
+
+const (
+	// driftUnknown is reported as the observed value of settings the device state doesn't include
+	driftUnknown = "unknown"
+
+	driftConnected    = "connected"
+	driftDisconnected = "disconnected"
+
+	// maxDriftSummary is the number of drift entries included in the message of the Synced condition
+	maxDriftSummary = 3
+)
+
+// detectDrift compares the settings of the spec with the config, DACs and nets last read from the device, reading
+// the GPIO levels the spec drives, and returns the settings that differ sorted by field.
+func detectDrift(j *jumperless.Jumperless, spec jumperlessv5alpha1.JumperlessSpec, status *jumperlessv5alpha1.JumperlessStatus) ([]jumperlessv5alpha1.DriftEntry, error) {
+	drift := []jumperlessv5alpha1.DriftEntry{}
+	add := func(field, expected, observed string) {
+		drift = append(drift, jumperlessv5alpha1.DriftEntry{Field: field, Expected: expected, Observed: observed})
+	}
+
+	for _, section := range local.DesiredConfig(spec) {
+		for _, entry := range section.Entries {
+			current, ok := status.GetConfigEntry(section.Name, entry.Key)
+			if !ok {
+				current = driftUnknown
+			}
+			if current != entry.Value {
+				add("config."+section.Name+"."+entry.Key, entry.Value, current)
+			}
+		}
+	}
+
+	if display := spec.Display; display != nil && display.Text != nil && ptr.Deref(status.DisplayText, "") != *display.Text {
+		add("display.text", *display.Text, ptr.Deref(status.DisplayText, driftUnknown))
+	}
+
+	if bridge := spec.UARTBridge; bridge != nil {
+		routes := []struct {
+			field    string
+			uartNode string
+			node     *string
+		}{
+			{field: "uartBridge.txNode", uartNode: local.UARTTXNode, node: bridge.TXNode},
+			{field: "uartBridge.rxNode", uartNode: local.UARTRXNode, node: bridge.RXNode},
+		}
+		for _, route := range routes {
+			if route.node == nil || local.IsConnected(status.Nets, route.uartNode, *route.node) {
+				continue
+			}
+
+			observed := driftDisconnected
+			if nodes := local.ConnectedNodes(status.Nets, route.uartNode); len(nodes) > 0 {
+				observed = strings.Join(nodes, ",")
+			}
+			add(route.field, *route.node, observed)
+		}
+	}
+
+	for _, dac := range spec.DACS {
+		desired, err := voltage.ParseInRange(dac.Voltage)
+		if err != nil {
+			return nil, fmt.Errorf("unable to parse DAC voltage for channel %s: %w", dac.Channel, err)
+		}
+
+		observed := driftUnknown
+		if i := slices.IndexFunc(status.DACS, func(s jumperlessv5alpha1.DACStatus) bool {
+			return s.Channel == dac.Channel
+		}); i >= 0 {
+			if current, err := voltage.Parse(status.DACS[i].Voltage); err == nil && voltage.Equal(current, desired) {
+				continue
+			}
+			observed = status.DACS[i].Voltage
+		}
+		add("dacs["+dac.Channel+"]", voltage.Format(desired), observed)
+	}
+
+	for _, pin := range spec.GPIO {
+		high, err := local.GetGPIO(j, pin.Pin)
+		if err != nil {
+			return nil, fmt.Errorf("unable to read GPIO level: %w", err)
+		}
+
+		observed := jumperlessv5alpha1.GPIOLow
+		if high {
+			observed = jumperlessv5alpha1.GPIOHigh
+		}
+		if observed != pin.Level {
+			add("gpio["+strconv.Itoa(int(pin.Pin))+"]", string(pin.Level), string(observed))
+		}
+	}
+
+	for _, connection := range spec.Connections {
+		if !local.IsConnected(status.Nets, connection.From, connection.To) {
+			add("connections["+connection.From+","+connection.To+"]", driftConnected, driftDisconnected)
+		}
+	}
+
+	// Settings listed more than once, e.g. a connection made by both the profile and the spec, are reported once
+	slices.SortStableFunc(drift, func(a, b jumperlessv5alpha1.DriftEntry) int { return strings.Compare(a.Field, b.Field) })
+	drift = slices.CompactFunc(drift, func(a, b jumperlessv5alpha1.DriftEntry) bool { return a.Field == b.Field })
+
+	return drift, nil
+}
+
+// observeDrift sets the Synced condition from status.drift, emitting an event when drift is first detected.
+func (r *JumperlessReconciler) observeDrift(ctx context.Context, instance *jumperlessv5alpha1.Jumperless, status *jumperlessv5alpha1.JumperlessStatus) {
+	if len(status.Drift) == 0 {
+		meta.SetStatusCondition(&status.Conditions, metav1.Condition{
+			Type:               jumperlessv5alpha1.ConditionSynced,
+			Status:             metav1.ConditionTrue,
+			Reason:             "Synced",
+			Message:            "The device state matches the spec",
+			ObservedGeneration: instance.Generation,
+		})
+
+		return
+	}
+
+	message := "The device state differs from the spec: " + summarizeDrift(status.Drift)
+	previous := meta.FindStatusCondition(status.Conditions, jumperlessv5alpha1.ConditionSynced)
+	meta.SetStatusCondition(&status.Conditions, metav1.Condition{
+		Type:               jumperlessv5alpha1.ConditionSynced,
+		Status:             metav1.ConditionFalse,
+		Reason:             jumperlessv5alpha1.ReasonDriftDetected,
+		Message:            message,
+		ObservedGeneration: instance.Generation,
+	})
+
+	if previous == nil || previous.Reason != jumperlessv5alpha1.ReasonDriftDetected {
+		ctrl.LoggerFrom(ctx).Info("Drift detected between the spec and the device", "fields", len(status.Drift))
+		if r.Recorder != nil {
+			r.Recorder.Event(instance, corev1.EventTypeWarning, jumperlessv5alpha1.ReasonDriftDetected, message)
+		}
+	}
+}
+
+// summarizeDrift describes the first drift entries, e.g. "dacs[DAC0] expected 3.30V observed 2.50V".
+func summarizeDrift(drift []jumperlessv5alpha1.DriftEntry) string {
+	summary := make([]string, 0, maxDriftSummary+1)
+	for i, entry := range drift {
+		if i == maxDriftSummary {
+			summary = append(summary, fmt.Sprintf("and %d more", len(drift)-maxDriftSummary))
+			break
+		}
+
+		summary = append(summary, fmt.Sprintf("%s expected %s observed %s", entry.Field, entry.Expected, entry.Observed))
+	}
+
+	return strings.Join(summary, "; ")
+}
@@ -20,19 +20,30 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
 
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/runtime"
 	kerrors "k8s.io/apimachinery/pkg/util/errors"
+	"k8s.io/client-go/tools/record"
 	"k8s.io/utils/ptr"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
 
 	jumperlessv5alpha1 "github.com/detiber/k8s-jumperless/api/v5alpha1"
+	"github.com/detiber/k8s-jumperless/internal/agent"
 	"github.com/detiber/k8s-jumperless/internal/controller/local"
+	"github.com/detiber/k8s-jumperless/internal/version"
 	"github.com/detiber/k8s-jumperless/jumperless"
+	"github.com/detiber/k8s-jumperless/jumperless/voltage"
 )
 
 var ErrNotImplemented = errors.New("not yet implemented")
@@ -42,18 +53,70 @@ var ErrUnknownHostType = errors.New("unknown host type")
 type JumperlessReconciler struct {
 	client.Client
 	Scheme *runtime.Scheme
+
+	// Ports shares the device ports with the other controller components, if nil the port is only
+	// shared within a single reconcile
+	Ports *jumperless.PortManager
+
+	// Agents connects to the agents driving the devices attached to other nodes, if nil such devices fail
+	Agents *agent.Pool
+
+	// Recorder emits events about changes observed on the device, if nil no events are emitted
+	Recorder record.EventRecorder
+
+	// Identity identifies the manager in the leases of the devices it drives, see status.heldBy. If empty,
+	// devices are driven without holding a lease and the controller only runs on the leader of the managers.
+	Identity string
+
+	// LeaseDuration is the duration of the leases held by the manager, DefaultLeaseDuration if zero
+	LeaseDuration time.Duration
+
+	// PortVisible returns whether the port or selector of a device on a local host resolves on the node of the
+	// manager, so replicas only lease the devices attached to their node. jumperless.PortVisible if nil
+	PortVisible func(host *jumperlessv5alpha1.JumperlessHostLocal) bool
+
+	// QuarantineThreshold is the number of reconciles failing in a row after which a device is quarantined,
+	// DefaultQuarantineThreshold if zero
+	QuarantineThreshold int
+
+	// QuarantineProbeInterval is the interval between probes of a quarantined device,
+	// DefaultQuarantineProbeInterval if zero
+	QuarantineProbeInterval time.Duration
+
+	// CaptureDir is the directory captures with a file target are written to, if empty such captures fail
+	CaptureDir string
+
+	// Version is the build version of the manager reported in status.controllerVersion, the version of the
+	// running binary if empty
+	Version string
+
+	// Workqueue bounds the concurrency of the controller and the rate devices are requeued at
+	Workqueue WorkqueueOptions
+}
+
+// controllerVersion returns the build version of the manager reported in the status of the devices.
+func (r *JumperlessReconciler) controllerVersion() string {
+	if r.Version == "" {
+		return version.Get()
+	}
+
+	return r.Version
 }
 
 // +kubebuilder:rbac:groups=jumperless.detiber.us,resources=jumperlesses,verbs=get;list;watch;create;update;patch;delete
 // +kubebuilder:rbac:groups=jumperless.detiber.us,resources=jumperlesses/status,verbs=get;update;patch
 // +kubebuilder:rbac:groups=jumperless.detiber.us,resources=jumperlesses/finalizers,verbs=update
+// +kubebuilder:rbac:groups=jumperless.detiber.us,resources=jumperlessprofiles,verbs=get;list;watch
+// +kubebuilder:rbac:groups=core,resources=events,verbs=create;patch
+// +kubebuilder:rbac:groups=core,resources=configmaps,verbs=get;create;update
+// +kubebuilder:rbac:groups=core,resources=nodes,verbs=get
 
 // Reconcile is part of the main kubernetes reconciliation loop which aims to
 // move the current state of the cluster closer to the desired state.
 //
 // For more details, check Reconcile and its Result here:
 // - https://pkg.go.dev/sigs.k8s.io/controller-runtime@v0.21.0/pkg/reconcile
-func (r *JumperlessReconciler) Reconcile(ctx context.Context, req ctrl.Request) (_ ctrl.Result, retErr error) {
+func (r *JumperlessReconciler) Reconcile(ctx context.Context, req ctrl.Request) (retResult ctrl.Result, retErr error) {
 	log := ctrl.LoggerFrom(ctx)
 
 	log.Info("Reconciling Jumperless", "request", req.NamespacedName)
@@ -74,8 +137,17 @@ func (r *JumperlessReconciler) Reconcile(ctx context.Context, req ctrl.Request)
 	// differences in ordering is causing issues with comparison.
 	status := instance.Status.DeepCopy()
 
-	// Always update the status
+	// Record the versions driving the device, so fleets running mixed versions can be audited from the API
+	status.ControllerVersion = ptr.To(r.controllerVersion())
+	status.ProtocolVersion = ptr.To(jumperless.ProtocolVersion)
+
+	// Always update the status, unless the device is driven by another manager
+	skipStatusPatch := false
 	defer func() {
+		if skipStatusPatch {
+			return
+		}
+
 		if err := r.patchStatus(ctx, instance, status); err != nil {
 			log.Error(err, "unable to patch Jumperless status")
 			retErr = kerrors.NewAggregate([]error{retErr, err})
@@ -85,6 +157,24 @@ func (r *JumperlessReconciler) Reconcile(ctx context.Context, req ctrl.Request)
 		log.Info("Successfully patched Jumperless status", "name", instance.Name, "namespace", instance.Namespace)
 	}()
 
+	// Record failures before the status is patched, quarantining devices that fail persistently,
+	// unless the device wasn't probed since it is quarantined
+	skipFailures := false
+	defer func() {
+		if skipStatusPatch || skipFailures {
+			return
+		}
+
+		retResult, retErr = r.observeFailures(ctx, instance, status, retResult, retErr)
+	}()
+
+	// Record the outcome of the reconcile before failures are observed, since quarantined devices don't return
+	// their errors. Only reconciles that drove the device through to the end advance the observed generation.
+	synced := false
+	defer func() {
+		observeReconcile(instance, status, synced, retErr)
+	}()
+
 	// Initialize conditions if not already present
 	if len(instance.Status.Conditions) == 0 ||
 		meta.FindStatusCondition(instance.Status.Conditions, jumperlessv5alpha1.ConditionReady) == nil {
@@ -101,12 +191,96 @@ func (r *JumperlessReconciler) Reconcile(ctx context.Context, req ctrl.Request)
 		return ctrl.Result{}, nil
 	}
 
+	// Two resources driving the same device would interleave commands on its port, so only the
+	// oldest resource claiming a port or selector reconciles the device
+	if instance.Spec.Host.Local != nil || instance.Spec.Host.Agent != nil {
+		conflict, err := r.findPortConflict(ctx, instance)
+		if err != nil {
+			return ctrl.Result{}, fmt.Errorf("unable to check for port conflicts: %w", err)
+		}
+		if conflict != nil {
+			log.Info("Port is already claimed by another Jumperless, retrying later",
+				"claimedBy", client.ObjectKeyFromObject(conflict), "after", portConflictRetryInterval)
+			meta.SetStatusCondition(&status.Conditions, metav1.Condition{
+				Type:   jumperlessv5alpha1.ConditionReady,
+				Status: metav1.ConditionFalse,
+				Reason: "PortConflict",
+				Message: fmt.Sprintf("The port is already claimed by Jumperless %s",
+					client.ObjectKeyFromObject(conflict)),
+				ObservedGeneration: instance.Generation,
+			})
+
+			return ctrl.Result{RequeueAfter: portConflictRetryInterval}, nil
+		}
+	}
+
+	// A device on the local host of the manager is only leased by the replicas of the manager it is attached
+	// to, the others leave it alone and give up a lease they hold since the device was moved away from them
+	if r.Identity != "" && !r.reachesDevice(instance) {
+		if err := r.releaseLease(ctx, instance); err != nil {
+			return ctrl.Result{}, err
+		}
+
+		log.Info("Device isn't attached to the node of this manager, retrying later",
+			"port", hostPort(&instance.Spec.Host), "after", deviceVisibilityRetryInterval)
+		skipStatusPatch = true
+
+		return ctrl.Result{RequeueAfter: deviceVisibilityRetryInterval}, nil
+	}
+
+	// Only the manager holding the lease drives the device, over its serial port or its SSH route, so
+	// replicas of the manager never open a session to the same device. The status is left to that manager.
+	held, renewAfter, err := r.acquireLease(ctx, instance, status)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+	if !held {
+		log.Info("Device is driven by another manager, retrying later",
+			"heldBy", previousHolder(instance.Status.HeldBy), "after", renewAfter)
+		skipStatusPatch = true
+
+		return ctrl.Result{RequeueAfter: renewAfter}, nil
+	}
+
+	// A quarantined device is left alone until it is due for a probe, the lease is renewed meanwhile so
+	// the device isn't taken over by another manager
+	if wait := quarantineWait(instance, r.quarantineProbeInterval(), time.Now()); wait > 0 {
+		log.Info("Jumperless is quarantined, probing it later", "after", wait)
+		skipFailures = true
+
+		if renewAfter > 0 && renewAfter < wait {
+			wait = renewAfter
+		}
+
+		return ctrl.Result{RequeueAfter: wait}, nil
+	}
+
 	// Determine if we are running on localhost or a remote host
 	// and perform the appropriate reconciliation.
+	result := ctrl.Result{RequeueAfter: renewAfter}
+	applied := false
 	switch {
-	case instance.Spec.Host.Local != nil:
-		if err := r.reconcileLocal(ctx, instance, status); err != nil {
+	case instance.Spec.Host.Local != nil || instance.Spec.Host.Agent != nil:
+		// A console attached to the device owns it until it detaches, that is not a failure of the device
+		applied, err = r.reconcileLocal(ctx, instance, status)
+		if errors.Is(err, jumperless.ErrConsoleAttached) {
+			log.Info("Console is attached to the device, retrying later", "after", consoleRetryInterval)
+			skipFailures = true
+
+			return ctrl.Result{RequeueAfter: consoleRetryInterval}, nil
+		}
+		if err != nil {
 			log.Error(err, "unable to reconcile Jumperless locally")
+
+			// A manager that can't find or open the device gives up its lease, so another replica the
+			// device is attached to can drive it
+			if r.Identity != "" && deviceUnavailable(status) {
+				if err := r.releaseLease(ctx, instance); err != nil {
+					log.Error(err, "unable to release lease")
+				}
+				status.HeldBy = nil
+			}
+
 			return ctrl.Result{}, fmt.Errorf("unable to reconcile Jumperless locally: %w", err)
 		}
 	case instance.Spec.Host.SSH != nil:
@@ -118,11 +292,56 @@ func (r *JumperlessReconciler) Reconcile(ctx context.Context, req ctrl.Request)
 		return ctrl.Result{}, fmt.Errorf("unknown host type: %w", ErrUnknownHostType)
 	}
 
+	// Writes are retried periodically while degraded, since nothing else triggers a reconcile
+	// once the status is unchanged
+	if meta.IsStatusConditionTrue(status.Conditions, jumperlessv5alpha1.ConditionDegraded) {
+		log.Info("Jumperless is degraded, retrying writes later", "after", degradedRetryInterval)
+		if result.RequeueAfter == 0 || result.RequeueAfter > degradedRetryInterval {
+			result.RequeueAfter = degradedRetryInterval
+		}
+	}
+
+	// Running captures are sampled in chunks, the next chunk is sampled shortly after this one was written
+	if status.Capture != nil && status.Capture.Phase == jumperlessv5alpha1.CaptureRunning &&
+		(result.RequeueAfter == 0 || result.RequeueAfter > captureRetryInterval) {
+		result.RequeueAfter = captureRetryInterval
+	}
+
+	// Reconciles waiting for the device, its profile or writes that failed don't advance the observed generation
+	synced = applied
+
 	log.Info("Successfully reconciled Jumperless", "name", instance.Name, "namespace", instance.Namespace)
-	return ctrl.Result{}, nil
+	return result, nil
+}
+
+// observeReconcile records the outcome of a reconcile in the status. The error of a failed reconcile is kept
+// in status.lastError, a reconcile that applied the spec to the device clears it and advances
+// status.observedGeneration.
+func observeReconcile(instance *jumperlessv5alpha1.Jumperless, status *jumperlessv5alpha1.JumperlessStatus,
+	synced bool, err error) {
+	switch {
+	case err != nil:
+		status.LastError = ptr.To(err.Error())
+	case synced:
+		status.ObservedGeneration = instance.Generation
+		status.LastError = nil
+	}
 }
 
+// patchStatus applies the parts of the status that changed, each with the field manager of its subsystem so
+// the fields of the other parts are left alone, see statusApplies. An unchanged status isn't applied at all.
 func (r *JumperlessReconciler) patchStatus(ctx context.Context, instance *jumperlessv5alpha1.Jumperless, status *jumperlessv5alpha1.JumperlessStatus) error {
+	for _, apply := range statusApplies(instance, status) {
+		if err := r.applyStatus(ctx, instance, apply); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// applyStatus applies a part of the status with its field manager.
+func (r *JumperlessReconciler) applyStatus(ctx context.Context, instance *jumperlessv5alpha1.Jumperless, apply statusApply) error {
 	log := ctrl.LoggerFrom(ctx)
 
 	// Create a new instance to hold the status update to avoid issues with potential SSA diffs
@@ -133,7 +352,7 @@ func (r *JumperlessReconciler) patchStatus(ctx context.Context, instance *jumper
 
 	// Deep copy the existing status to the new instance to ensure similar ordering
 	// to appease SSA diffing
-	status.DeepCopyInto(&statusInstance.Status)
+	apply.status.DeepCopyInto(&statusInstance.Status)
 
 	// Convert to unstructured for SSA patch
 	uResource, err := runtime.DefaultUnstructuredConverter.ToUnstructured(statusInstance)
@@ -147,11 +366,11 @@ func (r *JumperlessReconciler) patchStatus(ctx context.Context, instance *jumper
 
 	// Patch the status using server-side apply
 	// Use ForceOwnership to ensure the controller can update the status
-	// Use FieldOwner to set the field owner to the controller
+	// Use FieldOwner to set the field owner to the subsystem of the part of the status
 	// This is using the deprecated client.Apply option, since controller-runtime v0.22
 	// does not yet support native SSA for subresources: https://github.com/kubernetes-sigs/controller-runtime/issues/3183
 	//nolint:staticcheck
-	if err := r.Status().Patch(ctx, u, client.Apply, client.ForceOwnership, client.FieldOwner("k8s-jumperless")); err != nil {
+	if err := r.Status().Patch(ctx, u, client.Apply, client.ForceOwnership, client.FieldOwner(apply.fieldOwner)); err != nil {
 		log.Error(err, "unable to patch Jumperless status")
 		return fmt.Errorf("unable to patch Jumperless status: %w", err)
 	}
@@ -175,7 +394,8 @@ func (r *JumperlessReconciler) reconcileRemote(_ context.Context, instance *jump
 	return fmt.Errorf("remote reconciliation not implemented: %w", ErrNotImplemented)
 }
 
-func (r *JumperlessReconciler) reconcileLocal(ctx context.Context, instance *jumperlessv5alpha1.Jumperless, status *jumperlessv5alpha1.JumperlessStatus) error {
+// reconcileLocal reconciles a device attached to a node, returning whether the spec was applied to it.
+func (r *JumperlessReconciler) reconcileLocal(ctx context.Context, instance *jumperlessv5alpha1.Jumperless, status *jumperlessv5alpha1.JumperlessStatus) (bool, error) {
 	log := ctrl.LoggerFrom(ctx)
 
 	// do local reconciliation
@@ -198,43 +418,45 @@ func (r *JumperlessReconciler) reconcileLocal(ctx context.Context, instance *jum
 		// status will be updated in the deferred patch
 		if changed {
 			log.Info("Jumperless is not ready, reconciling")
-			return nil
+			return false, nil
 		}
 	}
 
-	port := ptr.Deref(instance.Spec.Host.Local.Port, "")
-	var version string
-	baudRate := ptr.Deref(instance.Spec.Host.Local.BaudRate, 0)
-
-	j, err := jumperless.NewJumperless(ctx, port, int(baudRate))
-	if err != nil {
-		// set ready condition to false with no jumperless found reason
-		// status will be updated in the deferred patch in Reconcile
+	// A missing profile is not a failure of the device, the Jumperless is reconciled again once it is created
+	spec, profile, err := r.resolveProfile(ctx, instance)
+	if apierrors.IsNotFound(err) {
+		log.Info("JumperlessProfile not found, waiting for it to be created", "profile", instance.Spec.ProfileRef.Name)
 		meta.SetStatusCondition(&status.Conditions, metav1.Condition{
 			Type:               jumperlessv5alpha1.ConditionReady,
 			Status:             metav1.ConditionFalse,
-			Reason:             "NoJumperlessFound",
-			Message:            "No Jumperless device found on specified port: " + port,
+			Reason:             "ProfileNotFound",
+			Message:            "JumperlessProfile " + instance.Spec.ProfileRef.Name + " not found",
 			ObservedGeneration: instance.Generation,
 		})
 
-		return fmt.Errorf("unable to find Jumperless port: %w", err)
+		return false, nil
+	}
+	if err != nil {
+		return false, err
 	}
-	if j == nil {
-		// set ready condition to false with no jumperless port found reason
+
+	port := hostPort(&instance.Spec.Host)
+	var version string
+
+	handle, err := acquirePort(ctx, r.Ports, r.Agents, &instance.Spec.Host)
+	if errors.Is(err, jumperless.ErrDeviceLocked) {
+		// set ready condition to false with port locked reason
 		// status will be updated in the deferred patch in Reconcile
 		meta.SetStatusCondition(&status.Conditions, metav1.Condition{
 			Type:               jumperlessv5alpha1.ConditionReady,
 			Status:             metav1.ConditionFalse,
-			Reason:             "NoJumperlessFound",
-			Message:            "No Jumperless device found on specified port: " + port,
+			Reason:             "PortLocked",
+			Message:            "The Jumperless port is in use by another process: " + err.Error(),
 			ObservedGeneration: instance.Generation,
 		})
-
-		return fmt.Errorf("no Jumperless device found on specified port %s: %w", port, jumperless.ErrNoJumperlessFound)
+		return false, fmt.Errorf("unable to open Jumperless port: %w", err)
 	}
-
-	if err := j.OpenPort(); err != nil {
+	if errors.Is(err, jumperless.ErrSharedPortOpen) {
 		// set ready condition to false with port open error reason
 		// status will be updated in the deferred patch in Reconcile
 		meta.SetStatusCondition(&status.Conditions, metav1.Condition{
@@ -244,14 +466,29 @@ func (r *JumperlessReconciler) reconcileLocal(ctx context.Context, instance *jum
 			Message:            "Unable to open Jumperless port: " + err.Error(),
 			ObservedGeneration: instance.Generation,
 		})
-		return fmt.Errorf("unable to open Jumperless port: %w", err)
+		return false, fmt.Errorf("unable to open Jumperless port: %w", err)
+	}
+	if err != nil {
+		// set ready condition to false with no jumperless found reason
+		// status will be updated in the deferred patch in Reconcile
+		meta.SetStatusCondition(&status.Conditions, metav1.Condition{
+			Type:               jumperlessv5alpha1.ConditionReady,
+			Status:             metav1.ConditionFalse,
+			Reason:             "NoJumperlessFound",
+			Message:            "No Jumperless device found on specified port: " + port,
+			ObservedGeneration: instance.Generation,
+		})
+
+		return false, fmt.Errorf("unable to find Jumperless port: %w", err)
 	}
 	defer func() {
-		if err := j.ClosePort(); err != nil {
-			log.Error(err, "unable to close Jumperless port", "port", j.GetPort())
+		if err := handle.Release(); err != nil {
+			log.Error(err, "unable to release Jumperless port", "port", handle.Jumperless().GetPort())
 		}
 	}()
 
+	j := handle.Jumperless()
+
 	version = j.GetVersion()
 	port = j.GetPort()
 	log.Info("Verified Jumperless device on port", "port", port, "firmwareVersion", version)
@@ -267,41 +504,618 @@ func (r *JumperlessReconciler) reconcileLocal(ctx context.Context, instance *jum
 	status.LocalPort = ptr.To(port)
 	status.FirmwareVersion = ptr.To(version)
 
-	dacStatus := []jumperlessv5alpha1.DACStatus{}
-	for _, channel := range jumperlessv5alpha1.DACChannels {
-		dacVoltage, err := local.GetDAC(j, channel)
-		if err != nil {
-			log.Error(err, "unable to get DAC voltage", "channel", channel)
-			return fmt.Errorf("unable to get DAC voltage for channel %s: %w", channel, err)
+	state, err := readDeviceState(ctx, j)
+	if err != nil {
+		return false, err
+	}
+
+	status.DACS = state.DACS
+	status.Nets = state.Nets
+
+	// Nothing is written to a device that is only watched for drift, not even its bootstrap baseline
+	detectOnly := instance.Spec.ReconcilePolicy == jumperlessv5alpha1.ReconcilePolicyDetectOnly
+	if !detectOnly {
+		if err := r.bootstrap(ctx, j, instance, status); err != nil {
+			log.Error(err, "unable to bootstrap Jumperless")
+			return false, fmt.Errorf("unable to bootstrap Jumperless: %w", err)
 		}
+	}
+
+	drift, err := detectDrift(j, spec, status)
+	if err != nil {
+		log.Error(err, "unable to detect drift")
+		return false, fmt.Errorf("unable to detect drift: %w", err)
+	}
 
-		log.Info("Retrieved DAC voltage", "channel", channel, "voltage", dacVoltage)
+	status.Drift = drift
 
-		// Initialize DAC status for each channel
-		s := jumperlessv5alpha1.DACStatus{
-			Channel: channel.String(),
-			Voltage: dacVoltage,
+	// A device that can still be read is reported as degraded rather than failing the reconcile,
+	// so the status stays fresh while writes are failing. The spec only counts as applied once it was
+	// written, or compared to the device when it is only watched for drift.
+	applied := false
+	if detectOnly {
+		log.Info("Reconcile policy is DetectOnly, not applying Jumperless config", "drift", len(drift))
+		meta.SetStatusCondition(&status.Conditions, metav1.Condition{
+			Type:               jumperlessv5alpha1.ConditionDegraded,
+			Status:             metav1.ConditionFalse,
+			Reason:             "DetectOnly",
+			Message:            "Jumperless config is not applied since the reconcile policy is DetectOnly",
+			ObservedGeneration: instance.Generation,
+		})
+		applied = true
+	} else if err := r.applyConfig(ctx, j, spec, status); err != nil {
+		log.Error(err, "unable to apply Jumperless config, continuing read-only")
+		meta.SetStatusCondition(&status.Conditions, metav1.Condition{
+			Type:               jumperlessv5alpha1.ConditionDegraded,
+			Status:             metav1.ConditionTrue,
+			Reason:             "WriteFailed",
+			Message:            "Unable to apply Jumperless config, the device is read-only: " + err.Error(),
+			ObservedGeneration: instance.Generation,
+		})
+		status.LastError = ptr.To("unable to apply Jumperless config: " + err.Error())
+	} else {
+		meta.SetStatusCondition(&status.Conditions, metav1.Condition{
+			Type:               jumperlessv5alpha1.ConditionDegraded,
+			Status:             metav1.ConditionFalse,
+			Reason:             "Reconciled",
+			Message:            "Jumperless config successfully applied",
+			ObservedGeneration: instance.Generation,
+		})
+
+		if len(drift) > 0 || status.LastSyncTime == nil {
+			status.LastSyncTime = ptr.To(metav1.Now())
+		}
+
+		status.Drift = nil
+		status.Profile = nil
+		if profile != nil {
+			status.Profile = &jumperlessv5alpha1.ProfileStatus{Name: profile.Name, Generation: profile.Generation}
 		}
-		dacStatus = append(dacStatus, s)
+
+		applied = true
 	}
 
-	status.DACS = dacStatus
+	r.observeDrift(ctx, instance, status)
+
+	config, err := local.GetConfig(j)
+	if err != nil {
+		log.Error(err, "unable to get Jumperless config")
+		return false, fmt.Errorf("unable to get Jumperless config: %w", err)
+	}
+
+	status.UpsertConfig(config)
+	r.observeConfigSchema(ctx, instance, status, config)
+
+	identity := local.GetDeviceIdentity(j, status)
+	// Uptime is informational only, older firmware may not expose the ticks counter.
+	if state.Uptime == nil {
+		log.Info("Jumperless uptime is not available")
+	} else {
+		identity.BootTime = bootTime(status.Device, time.Now().Add(-*state.Uptime))
+	}
+
+	status.Device = identity
+
+	// Nets are read again since routing the UART bridge or connecting nodes may have changed them
+	if spec.UARTBridge != nil || len(spec.Connections) > 0 {
+		nets, err := local.GetNets(j)
+		if err != nil {
+			log.Error(err, "unable to get nets")
+			return false, fmt.Errorf("unable to get nets: %w", err)
+		}
+
+		status.Nets = nets
+	}
+
+	status.ConnectedNets = ptr.To(local.CountConnectedNets(status.Nets))
+	status.UARTBridge = local.GetUARTBridgeStatus(status)
+	if status.UARTBridge.Enabled {
+		status.UARTBridge.PassthroughPort = jumperless.LookupPassthroughPort(j.GetPort())
+	}
+
+	// Measurements only read the device, they are taken regardless of the reconcile policy
+	if err := takeMeasurements(ctx, j, instance, status); err != nil {
+		log.Error(err, "unable to take measurements")
+		return false, err
+	}
+
+	// Captures only read the device too, the capture in the spec is sampled in chunks of a reconcile each
+	if err := r.reconcileCapture(ctx, j, instance, status); err != nil {
+		log.Error(err, "unable to capture samples")
+		return false, err
+	}
+
+	if err := r.reconcileDeviceLabels(ctx, instance, status); err != nil {
+		log.Error(err, "unable to label Jumperless")
+		return false, fmt.Errorf("unable to label Jumperless: %w", err)
+	}
+
+	return applied, nil
+}
+
+// readDeviceState reads the DAC voltages, nets and uptime of the device in a single batch. Devices that don't
+// complete the batch, such as emulators answering recorded commands, are read one command at a time instead.
+func readDeviceState(ctx context.Context, j *jumperless.Jumperless) (*local.DeviceState, error) {
+	log := ctrl.LoggerFrom(ctx)
+
+	state, err := local.RefreshState(j)
+	if err == nil {
+		log.Info("Retrieved Jumperless state", "dacs", state.DACS, "nets", len(state.Nets))
+		return state, nil
+	}
+
+	log.Error(err, "unable to refresh Jumperless state in a batch, reading it one command at a time")
+
+	state = &local.DeviceState{}
+	for _, channel := range jumperlessv5alpha1.DACChannels {
+		dac, err := local.GetDAC(j, channel)
+		if err != nil {
+			log.Error(err, "unable to get DAC voltage", "channel", channel)
+			return nil, fmt.Errorf("unable to get DAC voltage for channel %s: %w", channel, err)
+		}
+
+		log.Info("Retrieved DAC voltage", "channel", channel, "voltage", dac.Voltage)
+		state.DACS = append(state.DACS, dac)
+	}
 
 	nets, err := local.GetNets(j)
 	if err != nil {
 		log.Error(err, "unable to get nets")
-		return fmt.Errorf("unable to get nets: %w", err)
+		return nil, fmt.Errorf("unable to get nets: %w", err)
 	}
+	state.Nets = nets
+
+	if uptime, err := local.GetUptime(j); err != nil {
+		log.Error(err, "unable to get Jumperless uptime")
+	} else {
+		state.Uptime = &uptime
+	}
+
+	return state, nil
+}
+
+// bootstrap applies the baseline in spec.bootstrap to a factory-fresh device once per profile and records the
+// result in status.bootstrap. The config is read from the device first, since status.config may predate the device
+// being reset or replaced.
+func (r *JumperlessReconciler) bootstrap(ctx context.Context, j *jumperless.Jumperless, instance *jumperlessv5alpha1.Jumperless, status *jumperlessv5alpha1.JumperlessStatus) error {
+	log := ctrl.LoggerFrom(ctx)
 
-	status.Nets = nets
+	bootstrap := instance.Spec.Bootstrap
+	if bootstrap == nil || status.Bootstrap != nil && status.Bootstrap.Profile == bootstrap.Profile {
+		return nil
+	}
 
 	config, err := local.GetConfig(j)
 	if err != nil {
-		log.Error(err, "unable to get Jumperless config")
 		return fmt.Errorf("unable to get Jumperless config: %w", err)
 	}
 
-	status.UpsertConfig(config)
+	factory := bootstrap.FactoryConfig
+	if len(factory) == 0 {
+		factory = local.FactoryConfig()
+	}
+
+	result := jumperlessv5alpha1.BootstrapSkipped
+	message := fmt.Sprintf("The device is not factory-fresh, skipped baseline %s", bootstrap.Profile)
+	if local.IsFactoryFresh(config, factory) {
+		log.Info("Bootstrapping factory-fresh Jumperless", "profile", bootstrap.Profile)
+
+		// The baseline is applied like a spec only holding its settings, compared against the config just read
+		baseline := jumperlessv5alpha1.JumperlessSpec{
+			Host:    instance.Spec.Host,
+			DACS:    bootstrap.DACS,
+			Display: bootstrap.Display,
+		}
+		status.UpsertConfig(config)
+		if err := r.applyConfig(ctx, j, baseline, status); err != nil {
+			return fmt.Errorf("unable to apply baseline %s: %w", bootstrap.Profile, err)
+		}
+
+		result = jumperlessv5alpha1.BootstrapApplied
+		message = fmt.Sprintf("Applied baseline %s to the factory-fresh device", bootstrap.Profile)
+	} else {
+		log.Info("Jumperless is not factory-fresh, skipping bootstrap", "profile", bootstrap.Profile)
+	}
+
+	status.Bootstrap = &jumperlessv5alpha1.BootstrapStatus{
+		Profile:        bootstrap.Profile,
+		Result:         result,
+		CompletionTime: metav1.Now(),
+	}
+	if r.Recorder != nil {
+		r.Recorder.Event(instance, corev1.EventTypeNormal, "Bootstrap"+string(result), message)
+	}
+
+	return nil
+}
+
+// observeConfigSchema records the schema hash of the config read from the device, reporting a change from the
+// previously observed hash with the ConfigSchemaChanged condition and an event. The condition remains true until
+// the change is acknowledged with the ConfigSchemaAcknowledgedAnnotation.
+func (r *JumperlessReconciler) observeConfigSchema(ctx context.Context, instance *jumperlessv5alpha1.Jumperless, status *jumperlessv5alpha1.JumperlessStatus, config []jumperlessv5alpha1.JumperLessConfigSection) {
+	log := ctrl.LoggerFrom(ctx)
+
+	hash := local.ConfigSchemaHash(config)
+	previous := ptr.Deref(status.ConfigSchemaHash, "")
+	status.ConfigSchemaHash = ptr.To(hash)
+
+	if previous != "" && previous != hash {
+		message := fmt.Sprintf("The config sections and keys reported by the device changed from %s to %s, "+
+			"the firmware may have been updated. Set the %s annotation to %s to acknowledge the change.",
+			previous, hash, jumperlessv5alpha1.ConfigSchemaAcknowledgedAnnotation, hash)
+
+		log.Info("Jumperless config schema changed", "previous", previous, "current", hash)
+		meta.SetStatusCondition(&status.Conditions, metav1.Condition{
+			Type:               jumperlessv5alpha1.ConditionConfigSchemaChanged,
+			Status:             metav1.ConditionTrue,
+			Reason:             "SchemaChanged",
+			Message:            message,
+			ObservedGeneration: instance.Generation,
+		})
+		if r.Recorder != nil {
+			r.Recorder.Event(instance, corev1.EventTypeWarning, "ConfigSchemaChanged", message)
+		}
+
+		return
+	}
+
+	condition := meta.FindStatusCondition(status.Conditions, jumperlessv5alpha1.ConditionConfigSchemaChanged)
+	switch {
+	case condition == nil:
+		meta.SetStatusCondition(&status.Conditions, metav1.Condition{
+			Type:               jumperlessv5alpha1.ConditionConfigSchemaChanged,
+			Status:             metav1.ConditionFalse,
+			Reason:             "Observed",
+			Message:            "The config sections and keys reported by the device are unchanged",
+			ObservedGeneration: instance.Generation,
+		})
+	case condition.Status == metav1.ConditionTrue &&
+		instance.GetAnnotations()[jumperlessv5alpha1.ConfigSchemaAcknowledgedAnnotation] == hash:
+		log.Info("Jumperless config schema change acknowledged", "current", hash)
+		meta.SetStatusCondition(&status.Conditions, metav1.Condition{
+			Type:               jumperlessv5alpha1.ConditionConfigSchemaChanged,
+			Status:             metav1.ConditionFalse,
+			Reason:             "Acknowledged",
+			Message:            "The change of the config sections and keys reported by the device was acknowledged",
+			ObservedGeneration: instance.Generation,
+		})
+	}
+}
+
+// reconcileDeviceLabels labels the resource with the serial number, firmware version and hardware generation
+// of the probed device, enabling label selectors across devices. Labels are only patched when they change.
+func (r *JumperlessReconciler) reconcileDeviceLabels(ctx context.Context, instance *jumperlessv5alpha1.Jumperless, status *jumperlessv5alpha1.JumperlessStatus) error {
+	desired := map[string]string{}
+	if status.FirmwareVersion != nil {
+		desired[jumperlessv5alpha1.FirmwareVersionLabel] = sanitizeLabelValue(*status.FirmwareVersion)
+	}
+	if status.Device != nil && status.Device.SerialNumber != nil {
+		desired[jumperlessv5alpha1.SerialNumberLabel] = sanitizeLabelValue(*status.Device.SerialNumber)
+	}
+	if status.Device != nil && status.Device.Generation != nil {
+		desired[jumperlessv5alpha1.GenerationLabel] = sanitizeLabelValue(*status.Device.Generation)
+	}
+
+	labels := instance.GetLabels()
+	changed := false
+	for key, value := range desired {
+		if value != "" && labels[key] != value {
+			changed = true
+		}
+	}
+	if !changed {
+		return nil
+	}
+
+	original := instance.DeepCopy()
+	if labels == nil {
+		labels = map[string]string{}
+	}
+	for key, value := range desired {
+		if value != "" {
+			labels[key] = value
+		}
+	}
+	instance.SetLabels(labels)
+
+	ctrl.LoggerFrom(ctx).Info("Updating Jumperless device labels", "labels", desired)
+	if err := r.Patch(ctx, instance, client.MergeFrom(original)); err != nil {
+		return fmt.Errorf("unable to patch Jumperless labels: %w", err)
+	}
+
+	return nil
+}
+
+// acquirePort returns a handle to the device of a Jumperless on a local host or driven through an agent. If
+// ports is nil the port is opened just for the caller.
+func acquirePort(ctx context.Context, ports *jumperless.PortManager, agents *agent.Pool,
+	host *jumperlessv5alpha1.JumperlessHost) (*jumperless.PortHandle, error) {
+	if host.Agent != nil {
+		return acquireRemotePort(ctx, ports, agents, host.Agent)
+	}
+
+	return acquireLocalPort(ctx, ports, agents, host.Local)
+}
+
+// hostPort returns the port configured for the device of a Jumperless, empty if it is selected otherwise
+func hostPort(host *jumperlessv5alpha1.JumperlessHost) string {
+	switch {
+	case host.Agent != nil:
+		return ptr.Deref(host.Agent.Port, "")
+	case host.Local != nil:
+		return ptr.Deref(host.Local.Port, "")
+	}
+
+	return ""
+}
+
+// acquireLocalPort returns a handle to the device on the local port or selector of a Jumperless, driven by
+// the agent of its node if it is attached to another node. If ports is nil the port is opened just for the caller.
+func acquireLocalPort(ctx context.Context, ports *jumperless.PortManager, agents *agent.Pool,
+	host *jumperlessv5alpha1.JumperlessHostLocal) (*jumperless.PortHandle, error) {
+	if ptr.Deref(host.NodeName, "") != "" {
+		return acquireAgentPort(ctx, ports, agents, host)
+	}
+
+	if ports == nil {
+		ports = jumperless.NewPortManager()
+	}
+
+	port := ptr.Deref(host.Port, "")
+	baudRate := int(ptr.Deref(host.BaudRate, 0))
+	selector := jumperless.PortSelector{
+		SerialNumber: ptr.Deref(host.SerialNumber, ""),
+		VID:          ptr.Deref(host.VID, ""),
+		PID:          ptr.Deref(host.PID, ""),
+	}
+
+	if port == "" && !selector.IsEmpty() {
+		return ports.AcquireFromSelector(ctx, selector, baudRate) //nolint:wrapcheck
+	}
+
+	return ports.Acquire(ctx, port, baudRate) //nolint:wrapcheck
+}
+
+// degradedRetryInterval is the interval between attempts to apply the desired settings to a read-only device.
+const degradedRetryInterval = time.Minute
+
+// consoleRetryInterval is the interval between attempts to reconcile a device while a console is attached to it.
+const consoleRetryInterval = 30 * time.Second
+
+// portConflictRetryInterval is the interval between checks whether a port claimed by another resource was released.
+const portConflictRetryInterval = time.Minute
+
+// findPortConflict returns the oldest other local Jumperless claiming a port or selector claimed by the instance,
+// if it was created before the instance. Ties in creation time are broken by namespace and name.
+func (r *JumperlessReconciler) findPortConflict(ctx context.Context, instance *jumperlessv5alpha1.Jumperless) (*jumperlessv5alpha1.Jumperless, error) {
+	claims := portClaims(instance)
+	if len(claims) == 0 {
+		return nil, nil //nolint:nilnil
+	}
+
+	list := &jumperlessv5alpha1.JumperlessList{}
+	if err := r.List(ctx, list); err != nil {
+		return nil, fmt.Errorf("unable to list Jumperless resources: %w", err)
+	}
+
+	var conflict *jumperlessv5alpha1.Jumperless
+	for i := range list.Items {
+		other := &list.Items[i]
+		if other.UID == instance.UID || !other.DeletionTimestamp.IsZero() || !claimedBefore(other, instance) {
+			continue
+		}
+
+		if !slices.ContainsFunc(portClaims(other), func(claim string) bool { return slices.Contains(claims, claim) }) {
+			continue
+		}
+
+		if conflict == nil || claimedBefore(other, conflict) {
+			conflict = other
+		}
+	}
+
+	return conflict, nil
+}
+
+// portClaims returns the ports and selectors a local Jumperless claims: the configured port or selector,
+// and the port the device was last found on. Ports are resolved so symlinks claim the port they link to.
+// Ports of a Jumperless driven through an agent are claimed per endpoint.
+func portClaims(j *jumperlessv5alpha1.Jumperless) []string {
+	if agentHost := j.Spec.Host.Agent; agentHost != nil {
+		return agentPortClaims(j, agentHost)
+	}
+
+	if j.Spec.Host.Local == nil {
+		return nil
+	}
+
+	local := j.Spec.Host.Local
+
+	// Ports of other nodes are claimed per node, they can't be resolved on the node of the manager
+	prefix := ""
+	if nodeName := ptr.Deref(local.NodeName, ""); nodeName != "" {
+		prefix = "node:" + nodeName + "/"
+	}
+
+	claims := []string{}
+	addPort := func(port string) {
+		if resolved, err := filepath.EvalSymlinks(port); err == nil && prefix == "" {
+			port = resolved
+		}
+
+		if claim := prefix + "port:" + filepath.Clean(port); !slices.Contains(claims, claim) {
+			claims = append(claims, claim)
+		}
+	}
+
+	selector := jumperless.PortSelector{
+		SerialNumber: ptr.Deref(local.SerialNumber, ""),
+		VID:          strings.ToUpper(ptr.Deref(local.VID, "")),
+		PID:          strings.ToUpper(ptr.Deref(local.PID, "")),
+	}
+
+	switch {
+	case ptr.Deref(local.Port, "") != "":
+		addPort(*local.Port)
+	case !selector.IsEmpty():
+		claims = append(claims, fmt.Sprintf("%sselector:%s/%s/%s", prefix, selector.SerialNumber, selector.VID,
+			selector.PID))
+	}
+
+	if ptr.Deref(j.Status.LocalPort, "") != "" {
+		addPort(*j.Status.LocalPort)
+	}
+
+	return claims
+}
+
+// agentPortClaims returns the ports a Jumperless driven through an agent claims: the configured port, or any
+// port of the endpoint if the agent selects the only device, and the port the device was last found on
+func agentPortClaims(j *jumperlessv5alpha1.Jumperless, host *jumperlessv5alpha1.JumperlessHostAgent) []string {
+	prefix := "endpoint:" + host.Endpoint + "/"
+
+	claims := []string{}
+	if port := ptr.Deref(host.Port, ""); port != "" {
+		claims = append(claims, prefix+"port:"+port)
+	} else {
+		claims = append(claims, prefix+"port:*")
+	}
+
+	if port := ptr.Deref(j.Status.LocalPort, ""); port != "" && !slices.Contains(claims, prefix+"port:"+port) {
+		claims = append(claims, prefix+"port:"+port)
+	}
+
+	return claims
+}
+
+// claimedBefore returns true if a was created before b, breaking ties by namespace and name
+func claimedBefore(a, b *jumperlessv5alpha1.Jumperless) bool {
+	if !a.CreationTimestamp.Equal(&b.CreationTimestamp) {
+		return a.CreationTimestamp.Before(&b.CreationTimestamp)
+	}
+
+	return client.ObjectKeyFromObject(a).String() < client.ObjectKeyFromObject(b).String()
+}
+
+// bootTimeTolerance is the maximum drift between the previously reported and newly computed boot time
+// before the reported boot time is updated, absorbing the latency of reading the uptime from the device.
+const bootTimeTolerance = 5 * time.Second
+
+// bootTime returns the boot time to report, keeping the previously reported boot time if it is within
+// bootTimeTolerance of the computed boot time to avoid status churn.
+func bootTime(previous *jumperlessv5alpha1.DeviceIdentity, computed time.Time) *metav1.Time {
+	if previous != nil && previous.BootTime != nil {
+		drift := computed.Sub(previous.BootTime.Time)
+		if drift.Abs() <= bootTimeTolerance {
+			return previous.BootTime
+		}
+	}
+
+	return &metav1.Time{Time: computed.Truncate(time.Second)}
+}
+
+// applyConfig writes the display, probe, UART bridge, DAC, GPIO and connection settings from the spec, expanded
+// with its profile, to the device when they differ from the config, DACs and nets last read from the device. The
+// config is read back afterwards to populate status.config.
+func (r *JumperlessReconciler) applyConfig(ctx context.Context, j *jumperless.Jumperless, spec jumperlessv5alpha1.JumperlessSpec, status *jumperlessv5alpha1.JumperlessStatus) error {
+	log := ctrl.LoggerFrom(ctx)
+
+	for _, section := range local.DesiredConfig(spec) {
+		for _, entry := range section.Entries {
+			if current, ok := status.GetConfigEntry(section.Name, entry.Key); ok && current == entry.Value {
+				continue
+			}
+
+			log.Info("Updating Jumperless config", "section", section.Name, "key", entry.Key, "value", entry.Value)
+			if err := local.SetConfigEntry(j, section.Name, entry.Key, entry.Value); err != nil {
+				return fmt.Errorf("unable to update config: %w", err)
+			}
+		}
+	}
+
+	display := spec.Display
+	if display != nil && display.Text != nil && ptr.Deref(status.DisplayText, "") != *display.Text {
+		log.Info("Updating Jumperless display text", "text", *display.Text)
+		if err := local.SetDisplayText(j, *display.Text); err != nil {
+			return fmt.Errorf("unable to update display text: %w", err)
+		}
+
+		status.DisplayText = ptr.To(*display.Text)
+	}
+
+	if bridge := spec.UARTBridge; bridge != nil {
+		routes := []struct {
+			uartNode string
+			node     *string
+		}{
+			{uartNode: local.UARTTXNode, node: bridge.TXNode},
+			{uartNode: local.UARTRXNode, node: bridge.RXNode},
+		}
+		for _, route := range routes {
+			uartNode, node := route.uartNode, route.node
+			if node == nil || local.IsConnected(status.Nets, uartNode, *node) {
+				continue
+			}
+
+			log.Info("Connecting UART node", "uartNode", uartNode, "node", *node)
+			if err := local.Connect(j, uartNode, *node); err != nil {
+				return fmt.Errorf("unable to route UART bridge: %w", err)
+			}
+		}
+	}
+
+	for _, dac := range spec.DACS {
+		channel, ok := jumperlessv5alpha1.ParseDACChannel(dac.Channel)
+		if !ok {
+			continue
+		}
+
+		desired, err := voltage.ParseInRange(dac.Voltage)
+		if err != nil {
+			return fmt.Errorf("unable to parse DAC voltage for channel %s: %w", dac.Channel, err)
+		}
+
+		i := slices.IndexFunc(status.DACS, func(s jumperlessv5alpha1.DACStatus) bool { return s.Channel == dac.Channel })
+		if i >= 0 {
+			if current, err := voltage.Parse(status.DACS[i].Voltage); err == nil && voltage.Equal(current, desired) {
+				continue
+			}
+		}
+
+		log.Info("Updating Jumperless DAC voltage", "channel", dac.Channel, "voltage", dac.Voltage)
+		if err := local.SetDAC(j, channel, desired, ptr.Deref(dac.Save, true)); err != nil {
+			return fmt.Errorf("unable to update DAC voltage: %w", err)
+		}
+
+		if i >= 0 {
+			status.DACS[i].Voltage = voltage.Format(desired)
+		}
+	}
+
+	for _, pin := range spec.GPIO {
+		high := pin.Level == jumperlessv5alpha1.GPIOHigh
+		if current, err := local.GetGPIO(j, pin.Pin); err != nil {
+			return fmt.Errorf("unable to read GPIO level: %w", err)
+		} else if current == high {
+			continue
+		}
+
+		log.Info("Updating Jumperless GPIO level", "pin", pin.Pin, "level", pin.Level)
+		if err := local.SetGPIO(j, pin.Pin, high); err != nil {
+			return fmt.Errorf("unable to update GPIO level: %w", err)
+		}
+	}
+
+	for _, connection := range spec.Connections {
+		if local.IsConnected(status.Nets, connection.From, connection.To) {
+			continue
+		}
+
+		log.Info("Connecting nodes", "from", connection.From, "to", connection.To)
+		if err := local.Connect(j, connection.From, connection.To); err != nil {
+			return fmt.Errorf("unable to connect nodes: %w", err)
+		}
+	}
 
 	return nil
 }
@@ -311,6 +1125,8 @@ func (r *JumperlessReconciler) SetupWithManager(mgr ctrl.Manager) error {
 	//nolint:wrapcheck
 	return ctrl.NewControllerManagedBy(mgr).
 		For(&jumperlessv5alpha1.Jumperless{}).
+		Watches(&jumperlessv5alpha1.JumperlessProfile{}, handler.EnqueueRequestsFromMapFunc(r.jumperlessesForProfile)).
+		WithOptions(deviceControllerOptions(r.Workqueue, r.Identity)).
 		Named("jumperless").
 		Complete(r)
 }
@@ -18,15 +18,23 @@ package controller
 
 import (
 	"context"
+	"os"
+	"path/filepath"
+	"time"
 
 	jumperlessv5alpha1 "github.com/detiber/k8s-jumperless/api/v5alpha1"
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	"k8s.io/utils/ptr"
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 
+	"github.com/detiber/k8s-jumperless/internal/controller/local"
 	"github.com/detiber/k8s-jumperless/jumperless"
 )
 
@@ -72,8 +80,9 @@ var _ = Describe("Jumperless Controller", func() {
 		It("should successfully reconcile the resource", func() {
 			By("Reconciling the created resource")
 			controllerReconciler := &JumperlessReconciler{
-				Client: k8sClient,
-				Scheme: k8sClient.Scheme(),
+				Client:  k8sClient,
+				Scheme:  k8sClient.Scheme(),
+				Version: "v0.0.0-test",
 			}
 
 			_, err := controllerReconciler.Reconcile(ctx, reconcile.Request{
@@ -84,8 +93,786 @@ var _ = Describe("Jumperless Controller", func() {
 				MatchError(ErrNotImplemented),
 				MatchError(jumperless.ErrNoSerialPortFound),
 			))
+
+			By("Reporting the versions driving the device")
+			resource := &jumperlessv5alpha1.Jumperless{}
+			Expect(k8sClient.Get(ctx, typeNamespacedName, resource)).To(Succeed())
+			Expect(resource.Status.ControllerVersion).To(HaveValue(Equal("v0.0.0-test")))
+			Expect(resource.Status.ProtocolVersion).To(HaveValue(Equal(jumperless.ProtocolVersion)))
 			// TODO(user): Add more specific assertions depending on your controller's reconciliation logic.
 			// Example: If you expect a certain status condition after reconciliation, verify it here.
 		})
 	})
+
+	Context("When another resource claims the same port", func() {
+		ctx := context.Background()
+
+		// Resources created within the same second are ordered by name, so the first resource is the oldest
+		first := types.NamespacedName{Name: "conflict-a", Namespace: "default"}
+		second := types.NamespacedName{Name: "conflict-b", Namespace: "default"}
+
+		BeforeEach(func() {
+			for _, name := range []types.NamespacedName{first, second} {
+				resource := &jumperlessv5alpha1.Jumperless{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      name.Name,
+						Namespace: name.Namespace,
+					},
+					Spec: jumperlessv5alpha1.JumperlessSpec{
+						Host: jumperlessv5alpha1.JumperlessHost{
+							Local: &jumperlessv5alpha1.JumperlessHostLocal{
+								Port: ptr.To("/dev/ttyJumperlessConflict"),
+							},
+						},
+					},
+				}
+				Expect(k8sClient.Create(ctx, resource)).To(Succeed())
+			}
+		})
+
+		AfterEach(func() {
+			for _, name := range []types.NamespacedName{first, second} {
+				resource := &jumperlessv5alpha1.Jumperless{}
+				Expect(k8sClient.Get(ctx, name, resource)).To(Succeed())
+				Expect(k8sClient.Delete(ctx, resource)).To(Succeed())
+			}
+		})
+
+		It("should report the newer resource as conflicting without probing the port", func() {
+			controllerReconciler := &JumperlessReconciler{
+				Client: k8sClient,
+				Scheme: k8sClient.Scheme(),
+			}
+
+			By("Initializing the conditions of the newer resource")
+			_, err := controllerReconciler.Reconcile(ctx, reconcile.Request{NamespacedName: second})
+			Expect(err).NotTo(HaveOccurred())
+
+			By("Reconciling the newer resource")
+			result, err := controllerReconciler.Reconcile(ctx, reconcile.Request{NamespacedName: second})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(result.RequeueAfter).To(Equal(portConflictRetryInterval))
+
+			resource := &jumperlessv5alpha1.Jumperless{}
+			Expect(k8sClient.Get(ctx, second, resource)).To(Succeed())
+
+			ready := meta.FindStatusCondition(resource.Status.Conditions, jumperlessv5alpha1.ConditionReady)
+			Expect(ready).NotTo(BeNil())
+			Expect(ready.Status).To(Equal(metav1.ConditionFalse))
+			Expect(ready.Reason).To(Equal("PortConflict"))
+			Expect(ready.Message).To(ContainSubstring(first.String()))
+		})
+	})
+
+	Context("When the config schema of the device changes", func() {
+		ctx := context.Background()
+
+		config := []jumperlessv5alpha1.JumperLessConfigSection{
+			{Name: "hardware", Entries: []jumperlessv5alpha1.JumperlessConfigEntry{{Key: "generation", Value: "5"}}},
+		}
+		updated := []jumperlessv5alpha1.JumperLessConfigSection{
+			{Name: "hardware", Entries: []jumperlessv5alpha1.JumperlessConfigEntry{
+				{Key: "generation", Value: "5"},
+				{Key: "probe_revision", Value: "4"},
+			}},
+		}
+
+		It("should report the change until it is acknowledged", func() {
+			recorder := record.NewFakeRecorder(10)
+			controllerReconciler := &JumperlessReconciler{Recorder: recorder}
+			instance := &jumperlessv5alpha1.Jumperless{}
+			status := &jumperlessv5alpha1.JumperlessStatus{}
+
+			By("Observing the initial schema")
+			controllerReconciler.observeConfigSchema(ctx, instance, status, config)
+			Expect(status.ConfigSchemaHash).To(Equal(ptr.To(local.ConfigSchemaHash(config))))
+			Expect(meta.IsStatusConditionFalse(status.Conditions,
+				jumperlessv5alpha1.ConditionConfigSchemaChanged)).To(BeTrue())
+			Expect(recorder.Events).To(BeEmpty())
+
+			By("Ignoring changed values")
+			changedValues := []jumperlessv5alpha1.JumperLessConfigSection{
+				{Name: "hardware", Entries: []jumperlessv5alpha1.JumperlessConfigEntry{{Key: "generation", Value: "6"}}},
+			}
+			controllerReconciler.observeConfigSchema(ctx, instance, status, changedValues)
+			Expect(meta.IsStatusConditionFalse(status.Conditions,
+				jumperlessv5alpha1.ConditionConfigSchemaChanged)).To(BeTrue())
+			Expect(recorder.Events).To(BeEmpty())
+
+			By("Observing an added key")
+			controllerReconciler.observeConfigSchema(ctx, instance, status, updated)
+			hash := local.ConfigSchemaHash(updated)
+			Expect(status.ConfigSchemaHash).To(Equal(ptr.To(hash)))
+			Expect(meta.IsStatusConditionTrue(status.Conditions,
+				jumperlessv5alpha1.ConditionConfigSchemaChanged)).To(BeTrue())
+			Expect(recorder.Events).To(Receive(ContainSubstring("ConfigSchemaChanged")))
+
+			By("Keeping the condition while unacknowledged")
+			controllerReconciler.observeConfigSchema(ctx, instance, status, updated)
+			Expect(meta.IsStatusConditionTrue(status.Conditions,
+				jumperlessv5alpha1.ConditionConfigSchemaChanged)).To(BeTrue())
+			Expect(recorder.Events).To(BeEmpty())
+
+			By("Acknowledging the change")
+			instance.SetAnnotations(map[string]string{jumperlessv5alpha1.ConfigSchemaAcknowledgedAnnotation: hash})
+			controllerReconciler.observeConfigSchema(ctx, instance, status, updated)
+			changed := meta.FindStatusCondition(status.Conditions, jumperlessv5alpha1.ConditionConfigSchemaChanged)
+			Expect(changed.Status).To(Equal(metav1.ConditionFalse))
+			Expect(changed.Reason).To(Equal("Acknowledged"))
+		})
+	})
+
+	Context("When another manager holds the lease of the device", func() {
+		now := time.Now()
+		lease := &jumperlessv5alpha1.DeviceLease{
+			HolderIdentity:       "other",
+			RenewTime:            metav1.NewTime(now.Add(-10 * time.Second)),
+			LeaseDurationSeconds: 60,
+		}
+
+		It("should wait for the lease to expire unless the device is handed over", func() {
+			By("Driving a device without a lease")
+			Expect(leaseWait(nil, "self", "", time.Minute, now)).To(BeZero())
+
+			By("Driving a device whose lease it holds")
+			Expect(leaseWait(lease, "other", "", time.Minute, now)).To(BeZero())
+
+			By("Waiting for the lease of another manager to expire")
+			Expect(leaseWait(lease, "self", "", time.Minute, now)).To(Equal(50 * time.Second))
+
+			By("Taking over an expired lease")
+			Expect(leaseWait(lease, "self", "", time.Minute, now.Add(time.Minute))).To(BeZero())
+
+			By("Taking over an unexpired lease handed over to it")
+			Expect(leaseWait(lease, "self", "self", time.Minute, now)).To(BeZero())
+
+			By("Leaving a device handed over to another manager")
+			Expect(leaseWait(lease, "other", "self", time.Minute, now)).To(Equal(time.Minute))
+			Expect(leaseWait(nil, "other", "self", time.Minute, now)).To(Equal(time.Minute))
+		})
+
+		It("should only run commands on devices whose unexpired lease it holds", func() {
+			By("Running commands as the holder of the lease")
+			Expect(holdsLease(lease, "other", "", now)).To(BeTrue())
+			Expect(holdsLease(lease, "other", "other", now)).To(BeTrue())
+
+			By("Leaving commands of devices without a lease or held by another manager")
+			Expect(holdsLease(nil, "self", "", now)).To(BeFalse())
+			Expect(holdsLease(lease, "self", "", now)).To(BeFalse())
+			Expect(holdsLease(lease, "self", "self", now)).To(BeFalse())
+
+			By("Leaving commands once the lease expired or the device is handed over")
+			Expect(holdsLease(lease, "other", "", now.Add(time.Minute))).To(BeFalse())
+			Expect(holdsLease(lease, "other", "self", now)).To(BeFalse())
+
+			By("Running the device controllers on every replica only if devices are leased")
+			Expect(*deviceControllerOptions(WorkqueueOptions{}, "self").NeedLeaderElection).To(BeFalse())
+			Expect(*deviceControllerOptions(WorkqueueOptions{}, "").NeedLeaderElection).To(BeTrue())
+		})
+	})
+
+	Context("When several replicas of the manager could drive the device", func() {
+		ctx := context.Background()
+
+		key := types.NamespacedName{Name: "replicated", Namespace: "default"}
+
+		replica := func(identity string, visible bool) *JumperlessReconciler {
+			return &JumperlessReconciler{
+				Client:      k8sClient,
+				Scheme:      k8sClient.Scheme(),
+				Identity:    identity,
+				PortVisible: func(*jumperlessv5alpha1.JumperlessHostLocal) bool { return visible },
+			}
+		}
+
+		BeforeEach(func() {
+			instance := &jumperlessv5alpha1.Jumperless{
+				ObjectMeta: metav1.ObjectMeta{Name: key.Name, Namespace: key.Namespace},
+				Spec: jumperlessv5alpha1.JumperlessSpec{
+					Host: jumperlessv5alpha1.JumperlessHost{
+						Local: &jumperlessv5alpha1.JumperlessHostLocal{Port: ptr.To("/dev/ttyReplicated")},
+					},
+				},
+			}
+			Expect(k8sClient.Create(ctx, instance)).To(Succeed())
+			DeferCleanup(k8sClient.Delete, ctx, instance)
+		})
+
+		It("should only lease the device on the replica its port resolves on", func() {
+			detached := replica("replica-a", false)
+			attached := replica("replica-b", true)
+
+			_, err := attached.Reconcile(ctx, reconcile.Request{NamespacedName: key})
+			Expect(err).NotTo(HaveOccurred())
+
+			By("Leaving the device to the replicas it is attached to")
+			result, err := detached.Reconcile(ctx, reconcile.Request{NamespacedName: key})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(result.RequeueAfter).To(Equal(deviceVisibilityRetryInterval))
+
+			instance := &jumperlessv5alpha1.Jumperless{}
+			Expect(k8sClient.Get(ctx, key, instance)).To(Succeed())
+			Expect(instance.Status.HeldBy).To(BeNil())
+			Expect(instance.Status.LastError).To(BeNil())
+
+			By("Releasing the lease once the device can't be found on the replica it is attached to")
+			_, err = attached.Reconcile(ctx, reconcile.Request{NamespacedName: key})
+			Expect(err).To(HaveOccurred())
+
+			Expect(k8sClient.Get(ctx, key, instance)).To(Succeed())
+			ready := meta.FindStatusCondition(instance.Status.Conditions, jumperlessv5alpha1.ConditionReady)
+			Expect(ready).NotTo(BeNil())
+			Expect(ready.Reason).To(Equal("NoJumperlessFound"))
+			Expect(instance.Status.HeldBy).To(BeNil())
+
+			By("Releasing a lease held by a replica the device isn't attached to")
+			instance.Status.HeldBy = &jumperlessv5alpha1.DeviceLease{
+				HolderIdentity:       "replica-a",
+				AcquireTime:          metav1.Now(),
+				RenewTime:            metav1.Now(),
+				LeaseDurationSeconds: 60,
+			}
+			Expect(k8sClient.Status().Update(ctx, instance)).To(Succeed())
+
+			_, err = detached.Reconcile(ctx, reconcile.Request{NamespacedName: key})
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(k8sClient.Get(ctx, key, instance)).To(Succeed())
+			Expect(instance.Status.HeldBy).To(BeNil())
+		})
+	})
+
+	Context("When tuning the workqueues of the controllers", func() {
+		It("should default unset options to the controller-runtime defaults", func() {
+			options := WorkqueueOptions{}.withDefaults()
+			Expect(options).To(Equal(WorkqueueOptions{
+				MaxConcurrentReconciles: DefaultMaxConcurrentReconciles,
+				RequeueBaseDelay:        DefaultRequeueBaseDelay,
+				RequeueMaxDelay:         DefaultRequeueMaxDelay,
+				RequeueQPS:              DefaultRequeueQPS,
+				RequeueBurst:            DefaultRequeueBurst,
+			}))
+
+			By("Never capping the backoff below its base delay")
+			options = WorkqueueOptions{RequeueBaseDelay: time.Minute, RequeueMaxDelay: time.Second}.withDefaults()
+			Expect(options.RequeueMaxDelay).To(Equal(time.Minute))
+		})
+
+		It("should back off failing resources up to the cap and limit the overall requeue rate", func() {
+			options := deviceControllerOptions(WorkqueueOptions{
+				MaxConcurrentReconciles: 4,
+				RequeueBaseDelay:        time.Second,
+				RequeueMaxDelay:         4 * time.Second,
+				RequeueQPS:              1,
+				RequeueBurst:            1,
+			}, "self")
+			Expect(options.MaxConcurrentReconciles).To(Equal(4))
+
+			failing := reconcile.Request{NamespacedName: types.NamespacedName{Namespace: "default", Name: "failing"}}
+			Expect(options.RateLimiter.When(failing)).To(Equal(time.Second))
+			Expect(options.RateLimiter.When(failing)).To(Equal(2 * time.Second))
+			Expect(options.RateLimiter.When(failing)).To(Equal(4 * time.Second))
+			Expect(options.RateLimiter.When(failing)).To(Equal(4 * time.Second))
+
+			By("Delaying other resources once the burst is used up")
+			other := reconcile.Request{NamespacedName: types.NamespacedName{Namespace: "default", Name: "other"}}
+			Expect(options.RateLimiter.When(other)).To(BeNumerically(">", time.Second))
+
+			By("Resetting the backoff once a resource reconciled successfully")
+			options.RateLimiter.Forget(failing)
+			Expect(options.RateLimiter.NumRequeues(failing)).To(BeZero())
+		})
+	})
+
+	Context("When recording the outcome of a reconcile", func() {
+		It("should only advance the observed generation once the device is synced", func() {
+			instance := &jumperlessv5alpha1.Jumperless{ObjectMeta: metav1.ObjectMeta{Generation: 2}}
+			status := &jumperlessv5alpha1.JumperlessStatus{ObservedGeneration: 1}
+
+			By("Keeping the error of a failed reconcile")
+			observeReconcile(instance, status, false, jumperless.ErrNoSerialPortFound)
+			Expect(status.ObservedGeneration).To(BeEquivalentTo(1))
+			Expect(status.LastError).To(Equal(ptr.To(jumperless.ErrNoSerialPortFound.Error())))
+
+			By("Leaving the status alone when the device wasn't driven")
+			observeReconcile(instance, status, false, nil)
+			Expect(status.ObservedGeneration).To(BeEquivalentTo(1))
+			Expect(status.LastError).NotTo(BeNil())
+
+			By("Advancing the observed generation and clearing the error once the device is synced")
+			observeReconcile(instance, status, true, nil)
+			Expect(status.ObservedGeneration).To(BeEquivalentTo(2))
+			Expect(status.LastError).To(BeNil())
+		})
+	})
+
+	Context("When patching the status", func() {
+		managedFields := func(fieldOwners ...string) []metav1.ManagedFieldsEntry {
+			entries := []metav1.ManagedFieldsEntry{}
+			for _, fieldOwner := range fieldOwners {
+				entries = append(entries, metav1.ManagedFieldsEntry{
+					Manager:     fieldOwner,
+					Operation:   metav1.ManagedFieldsOperationApply,
+					Subresource: "status",
+				})
+			}
+
+			return entries
+		}
+		fieldOwners := func(applies []statusApply) []string {
+			owners := []string{}
+			for _, apply := range applies {
+				owners = append(owners, apply.fieldOwner)
+			}
+
+			return owners
+		}
+
+		It("should only apply the parts of the status that changed", func() {
+			instance := &jumperlessv5alpha1.Jumperless{
+				ObjectMeta: metav1.ObjectMeta{ManagedFields: managedFields(statusFieldOwner,
+					"k8s-jumperless-dacs", "k8s-jumperless-nets", "k8s-jumperless-config", "k8s-jumperless-conditions")},
+				Status: jumperlessv5alpha1.JumperlessStatus{
+					FirmwareVersion: ptr.To("5.3.1.0"),
+					DACS:            []jumperlessv5alpha1.DACStatus{{Channel: "DAC0", Voltage: "3.3V"}},
+					Nets:            []jumperlessv5alpha1.Net{{Index: 1, Name: "GND", Nodes: []string{"GND"}}},
+					ConnectedNets:   ptr.To[int32](0),
+					Conditions: []metav1.Condition{{
+						Type:   jumperlessv5alpha1.ConditionReady,
+						Status: metav1.ConditionTrue,
+						Reason: "Reconciled",
+					}},
+				},
+			}
+
+			By("Applying nothing while the status is unchanged")
+			Expect(statusApplies(instance, instance.Status.DeepCopy())).To(BeEmpty())
+
+			By("Applying only the subsystem whose fields changed")
+			status := instance.Status.DeepCopy()
+			status.DACS[0].Voltage = "5V"
+			applies := statusApplies(instance, status)
+			Expect(fieldOwners(applies)).To(Equal([]string{"k8s-jumperless-dacs"}))
+			Expect(*applies[0].status).To(Equal(jumperlessv5alpha1.JumperlessStatus{DACS: status.DACS}))
+
+			By("Applying the remaining fields without the fields of the subsystems")
+			status = instance.Status.DeepCopy()
+			status.FirmwareVersion = ptr.To("5.4.0.0")
+			applies = statusApplies(instance, status)
+			Expect(fieldOwners(applies)).To(Equal([]string{statusFieldOwner}))
+			Expect(*applies[0].status).To(Equal(jumperlessv5alpha1.JumperlessStatus{FirmwareVersion: ptr.To("5.4.0.0")}))
+
+			By("Applying an emptied subsystem so its fields are removed")
+			status = instance.Status.DeepCopy()
+			status.Conditions = nil
+			applies = statusApplies(instance, status)
+			Expect(fieldOwners(applies)).To(Equal([]string{"k8s-jumperless-conditions"}))
+			Expect(*applies[0].status).To(Equal(jumperlessv5alpha1.JumperlessStatus{}))
+		})
+
+		It("should hand the fields of the subsystems over from the field manager of the whole status", func() {
+			instance := &jumperlessv5alpha1.Jumperless{
+				ObjectMeta: metav1.ObjectMeta{ManagedFields: managedFields(statusFieldOwner)},
+				Status: jumperlessv5alpha1.JumperlessStatus{
+					FirmwareVersion: ptr.To("5.3.1.0"),
+					Nets:            []jumperlessv5alpha1.Net{{Index: 1, Name: "GND", Nodes: []string{"GND"}}},
+				},
+			}
+
+			applies := statusApplies(instance, instance.Status.DeepCopy())
+			Expect(fieldOwners(applies)).To(Equal([]string{"k8s-jumperless-nets", statusFieldOwner}))
+			Expect(*applies[0].status).To(Equal(jumperlessv5alpha1.JumperlessStatus{Nets: instance.Status.Nets}))
+			Expect(*applies[1].status).To(Equal(jumperlessv5alpha1.JumperlessStatus{FirmwareVersion: ptr.To("5.3.1.0")}))
+		})
+	})
+
+	Context("When reconciling a device fails repeatedly", func() {
+		ctx := context.Background()
+		errProbe := jumperless.ErrNoSerialPortFound
+		errPort := ErrNotImplemented
+
+		It("should quarantine the device until a reconcile succeeds", func() {
+			recorder := record.NewFakeRecorder(10)
+			controllerReconciler := &JumperlessReconciler{
+				Recorder:                recorder,
+				QuarantineThreshold:     3,
+				QuarantineProbeInterval: time.Hour,
+			}
+			instance := &jumperlessv5alpha1.Jumperless{}
+			status := &jumperlessv5alpha1.JumperlessStatus{}
+
+			By("Returning failures below the threshold")
+			for _, err := range []error{errProbe, errPort} {
+				_, reconcileErr := controllerReconciler.observeFailures(ctx, instance, status, reconcile.Result{}, err)
+				Expect(reconcileErr).To(MatchError(err))
+			}
+			Expect(status.Failures.Consecutive).To(BeEquivalentTo(2))
+			Expect(meta.FindStatusCondition(status.Conditions, jumperlessv5alpha1.ConditionQuarantined)).To(BeNil())
+			Expect(recorder.Events).To(BeEmpty())
+
+			By("Quarantining the device at the threshold")
+			result, err := controllerReconciler.observeFailures(ctx, instance, status, reconcile.Result{}, errProbe)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(result.RequeueAfter).To(Equal(time.Hour))
+			Expect(status.Failures.Errors).To(HaveLen(2))
+			Expect(status.Failures.Errors[1].Message).To(Equal(errProbe.Error()))
+			Expect(status.Failures.Errors[1].Count).To(BeEquivalentTo(2))
+			Expect(meta.IsStatusConditionTrue(status.Conditions,
+				jumperlessv5alpha1.ConditionQuarantined)).To(BeTrue())
+			Expect(recorder.Events).To(Receive(And(
+				ContainSubstring("Quarantined"),
+				ContainSubstring("2x "+errProbe.Error()),
+				ContainSubstring("1x "+errPort.Error()),
+			)))
+
+			By("Waiting for the probe interval unless the spec changes")
+			instance.Status = *status.DeepCopy()
+			now := status.Failures.LastFailureTime.Time
+			Expect(quarantineWait(instance, time.Hour, now.Add(time.Minute))).To(Equal(59 * time.Minute))
+			Expect(quarantineWait(instance, time.Hour, now.Add(time.Hour))).To(BeZero())
+			instance.Generation++
+			Expect(quarantineWait(instance, time.Hour, now.Add(time.Minute))).To(BeZero())
+
+			By("Probing the quarantined device again later")
+			_, err = controllerReconciler.observeFailures(ctx, instance, status, reconcile.Result{}, errPort)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(recorder.Events).To(BeEmpty())
+
+			By("Releasing the device once a reconcile succeeds")
+			_, err = controllerReconciler.observeFailures(ctx, instance, status, reconcile.Result{}, nil)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(status.Failures).To(BeNil())
+			quarantined := meta.FindStatusCondition(status.Conditions, jumperlessv5alpha1.ConditionQuarantined)
+			Expect(quarantined.Status).To(Equal(metav1.ConditionFalse))
+			Expect(quarantined.Reason).To(Equal("Recovered"))
+			Expect(recorder.Events).To(Receive(ContainSubstring("Recovered")))
+		})
+	})
+
+	Context("When the spec references a profile", func() {
+		ctx := context.Background()
+
+		instanceName := types.NamespacedName{Name: "profile-user", Namespace: "default"}
+		profileName := types.NamespacedName{Name: "fixture", Namespace: "default"}
+
+		BeforeEach(func() {
+			resource := &jumperlessv5alpha1.Jumperless{
+				ObjectMeta: metav1.ObjectMeta{Name: instanceName.Name, Namespace: instanceName.Namespace},
+				Spec: jumperlessv5alpha1.JumperlessSpec{
+					Host: jumperlessv5alpha1.JumperlessHost{
+						Local: &jumperlessv5alpha1.JumperlessHostLocal{Port: ptr.To("/dev/ttyJumperlessProfile")},
+					},
+					ProfileRef: &jumperlessv5alpha1.ProfileReference{Name: profileName.Name},
+				},
+			}
+			Expect(k8sClient.Create(ctx, resource)).To(Succeed())
+		})
+
+		AfterEach(func() {
+			resource := &jumperlessv5alpha1.Jumperless{}
+			Expect(k8sClient.Get(ctx, instanceName, resource)).To(Succeed())
+			Expect(k8sClient.Delete(ctx, resource)).To(Succeed())
+
+			profile := &jumperlessv5alpha1.JumperlessProfile{}
+			if err := k8sClient.Get(ctx, profileName, profile); err == nil {
+				Expect(k8sClient.Delete(ctx, profile)).To(Succeed())
+			}
+		})
+
+		It("should wait for the profile without probing the port", func() {
+			controllerReconciler := &JumperlessReconciler{
+				Client: k8sClient,
+				Scheme: k8sClient.Scheme(),
+			}
+
+			By("Initializing the conditions")
+			_, err := controllerReconciler.Reconcile(ctx, reconcile.Request{NamespacedName: instanceName})
+			Expect(err).NotTo(HaveOccurred())
+
+			By("Reporting the missing profile")
+			_, err = controllerReconciler.Reconcile(ctx, reconcile.Request{NamespacedName: instanceName})
+			Expect(err).NotTo(HaveOccurred())
+
+			resource := &jumperlessv5alpha1.Jumperless{}
+			Expect(k8sClient.Get(ctx, instanceName, resource)).To(Succeed())
+			ready := meta.FindStatusCondition(resource.Status.Conditions, jumperlessv5alpha1.ConditionReady)
+			Expect(ready).NotTo(BeNil())
+			Expect(ready.Status).To(Equal(metav1.ConditionFalse))
+			Expect(ready.Reason).To(Equal("ProfileNotFound"))
+			Expect(resource.Status.Failures).To(BeNil())
+			Expect(resource.Status.ObservedGeneration).To(BeZero())
+
+			By("Not observing a new generation of the spec while the profile is missing")
+			resource.Spec.DACS = []jumperlessv5alpha1.DAC{{Channel: "DAC0", Voltage: "3.3V"}}
+			Expect(k8sClient.Update(ctx, resource)).To(Succeed())
+			_, err = controllerReconciler.Reconcile(ctx, reconcile.Request{NamespacedName: instanceName})
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(k8sClient.Get(ctx, instanceName, resource)).To(Succeed())
+			Expect(resource.Generation).To(BeNumerically(">", 1))
+			Expect(resource.Status.ObservedGeneration).To(BeZero())
+			Expect(resource.Status.LastError).To(BeNil())
+
+			By("Reconciling the resource once the profile is created")
+			profile := &jumperlessv5alpha1.JumperlessProfile{
+				ObjectMeta: metav1.ObjectMeta{Name: profileName.Name, Namespace: profileName.Namespace},
+			}
+			Expect(k8sClient.Create(ctx, profile)).To(Succeed())
+			Expect(controllerReconciler.jumperlessesForProfile(ctx, profile)).To(ConsistOf(
+				reconcile.Request{NamespacedName: instanceName},
+			))
+		})
+
+		It("should let the spec take precedence over the profile", func() {
+			spec := jumperlessv5alpha1.JumperlessSpec{
+				DACS: []jumperlessv5alpha1.DAC{{Channel: "DAC0", Voltage: "5V"}},
+				GPIO: []jumperlessv5alpha1.GPIO{{Pin: 1, Level: jumperlessv5alpha1.GPIOLow}},
+				Connections: []jumperlessv5alpha1.Connection{
+					{From: "D2", To: "15"},
+				},
+			}
+			profile := jumperlessv5alpha1.JumperlessProfileSpec{
+				DACS: []jumperlessv5alpha1.DAC{
+					{Channel: "DAC0", Voltage: "3.3V"},
+					{Channel: "TOP_RAIL", Voltage: "3.3V"},
+				},
+				GPIO: []jumperlessv5alpha1.GPIO{
+					{Pin: 1, Level: jumperlessv5alpha1.GPIOHigh},
+					{Pin: 2, Level: jumperlessv5alpha1.GPIOHigh},
+				},
+				Connections: []jumperlessv5alpha1.Connection{
+					{From: "GPIO_1", To: "D3"},
+				},
+			}
+
+			expanded := expandProfile(spec, profile)
+			Expect(expanded.DACS).To(Equal([]jumperlessv5alpha1.DAC{
+				{Channel: "TOP_RAIL", Voltage: "3.3V"},
+				{Channel: "DAC0", Voltage: "5V"},
+			}))
+			Expect(expanded.GPIO).To(Equal([]jumperlessv5alpha1.GPIO{
+				{Pin: 2, Level: jumperlessv5alpha1.GPIOHigh},
+				{Pin: 1, Level: jumperlessv5alpha1.GPIOLow},
+			}))
+			Expect(expanded.Connections).To(Equal([]jumperlessv5alpha1.Connection{
+				{From: "GPIO_1", To: "D3"},
+				{From: "D2", To: "15"},
+			}))
+			Expect(spec.DACS).To(HaveLen(1))
+		})
+	})
+
+	Context("When the device state differs from the spec", func() {
+		ctx := context.Background()
+
+		spec := jumperlessv5alpha1.JumperlessSpec{
+			DACS: []jumperlessv5alpha1.DAC{
+				{Channel: "DAC0", Voltage: "3.3V"},
+				{Channel: "DAC1", Voltage: "0V"},
+			},
+			Display:     &jumperlessv5alpha1.Display{Font: ptr.To("jokerman")},
+			Connections: []jumperlessv5alpha1.Connection{{From: "D2", To: "15"}},
+		}
+
+		It("should report the drift without writing to the device", func() {
+			recorder := record.NewFakeRecorder(10)
+			controllerReconciler := &JumperlessReconciler{Recorder: recorder}
+			instance := &jumperlessv5alpha1.Jumperless{Spec: spec}
+			status := &jumperlessv5alpha1.JumperlessStatus{
+				DACS: []jumperlessv5alpha1.DACStatus{
+					{Channel: "DAC0", Voltage: "2.50V"},
+					{Channel: "DAC1", Voltage: "0.00V"},
+				},
+			}
+			status.SetConfigEntry("top_oled", "font", "jokerman")
+
+			By("Detecting the settings that differ")
+			drift, err := detectDrift(nil, spec, status)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(drift).To(Equal([]jumperlessv5alpha1.DriftEntry{
+				{Field: "connections[D2,15]", Expected: "connected", Observed: "disconnected"},
+				{Field: "dacs[DAC0]", Expected: "3.30V", Observed: "2.50V"},
+			}))
+
+			By("Reporting the drift")
+			status.Drift = drift
+			controllerReconciler.observeDrift(ctx, instance, status)
+			synced := meta.FindStatusCondition(status.Conditions, jumperlessv5alpha1.ConditionSynced)
+			Expect(synced.Status).To(Equal(metav1.ConditionFalse))
+			Expect(synced.Reason).To(Equal(jumperlessv5alpha1.ReasonDriftDetected))
+			Expect(synced.Message).To(ContainSubstring("dacs[DAC0] expected 3.30V observed 2.50V"))
+			Expect(recorder.Events).To(Receive(ContainSubstring(jumperlessv5alpha1.ReasonDriftDetected)))
+
+			By("Emitting an event only when drift is first detected")
+			controllerReconciler.observeDrift(ctx, instance, status)
+			Expect(recorder.Events).To(BeEmpty())
+
+			By("Reporting the device as synced once the drift is gone")
+			status.Drift = nil
+			controllerReconciler.observeDrift(ctx, instance, status)
+			Expect(meta.IsStatusConditionTrue(status.Conditions, jumperlessv5alpha1.ConditionSynced)).To(BeTrue())
+		})
+	})
+
+	Context("When the spec requests measurements", func() {
+		ctx := context.Background()
+
+		measurement := jumperlessv5alpha1.Measurement{Name: "rail", Channel: 2, Samples: 4}
+
+		It("should record the samples with their statistics", func() {
+			now := time.Now()
+			result := newMeasurementResult(measurement, "", []float64{3.3, 3.28, 3.32, 3.3}, now)
+			Expect(result.Samples).To(Equal([]string{"3.30V", "3.28V", "3.32V", "3.30V"}))
+			Expect(result.Min).To(Equal("3.28V"))
+			Expect(result.Max).To(Equal("3.32V"))
+			Expect(result.Average).To(Equal("3.30V"))
+			Expect(result.Interval.Duration).To(Equal(defaultMeasurementInterval))
+
+			By("Keeping the result while the measurement and trigger are unchanged")
+			Expect(measurementCurrent(measurement, result, "")).To(BeTrue())
+
+			By("Taking the measurement again when it changes or is triggered")
+			changed := measurement
+			changed.Samples = 8
+			Expect(measurementCurrent(changed, result, "")).To(BeFalse())
+			changed = measurement
+			changed.Interval = &metav1.Duration{Duration: time.Second}
+			Expect(measurementCurrent(changed, result, "")).To(BeFalse())
+			Expect(measurementCurrent(measurement, result, "again")).To(BeFalse())
+		})
+
+		It("should drop the results of removed measurements without sampling", func() {
+			current := newMeasurementResult(measurement, "", []float64{3.3, 3.3, 3.3, 3.3}, time.Now())
+			removed := newMeasurementResult(jumperlessv5alpha1.Measurement{Name: "removed", Channel: 1, Samples: 1},
+				"", []float64{1}, time.Now())
+			instance := &jumperlessv5alpha1.Jumperless{
+				Spec: jumperlessv5alpha1.JumperlessSpec{Measurements: []jumperlessv5alpha1.Measurement{measurement}},
+			}
+			status := &jumperlessv5alpha1.JumperlessStatus{
+				Measurements: []jumperlessv5alpha1.MeasurementResult{removed, current},
+			}
+
+			Expect(takeMeasurements(ctx, nil, instance, status)).To(Succeed())
+			Expect(status.Measurements).To(Equal([]jumperlessv5alpha1.MeasurementResult{current}))
+		})
+	})
+
+	Context("When the spec requests a capture", func() {
+		ctx := context.Background()
+
+		start := time.Now()
+
+		It("should schedule the samples and report the progress", func() {
+			interval := 10 * time.Millisecond
+			Expect(captureSlot(start, interval, start.Add(-time.Second))).To(BeZero())
+			Expect(captureSlot(start, interval, start)).To(BeZero())
+			Expect(captureSlot(start, interval, start.Add(interval))).To(Equal(int64(1)))
+			Expect(captureSlot(start, interval, start.Add(interval+time.Millisecond))).To(Equal(int64(2)))
+
+			Expect(captureProgress(start, time.Minute, start)).To(BeZero())
+			Expect(captureProgress(start, time.Minute, start.Add(15*time.Second))).To(Equal(int32(25)))
+			Expect(captureProgress(start, time.Minute, start.Add(2*time.Minute))).To(Equal(int32(100)))
+
+			Expect(formatCaptureSample(1500*time.Millisecond, 3.3)).To(Equal("1.500,3.30\n"))
+		})
+
+		It("should keep file targets below the directory of the Jumperless", func() {
+			instance := &jumperlessv5alpha1.Jumperless{
+				ObjectMeta: metav1.ObjectMeta{Name: "bench-1", Namespace: "default"},
+			}
+
+			path, err := captureFilePath("/captures", instance, "runs/startup.csv")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(path).To(Equal("/captures/default/bench-1/runs/startup.csv"))
+
+			_, err = captureFilePath("/captures", instance, "../bench-2/startup.csv")
+			Expect(err).To(MatchError(ErrInvalidCapturePath))
+
+			_, err = captureFilePath("", instance, "startup.csv")
+			Expect(err).To(MatchError(ErrCaptureDirNotConfigured))
+		})
+
+		It("should append the samples to a file", func() {
+			target := &fileCaptureTarget{path: filepath.Join(GinkgoT().TempDir(), "default", "startup.csv")}
+			Expect(target.write(ctx, captureHeader, true)).To(Succeed())
+			Expect(target.write(ctx, "0.000,3.30\n", false)).To(Succeed())
+			Expect(os.ReadFile(target.path)).To(BeEquivalentTo("seconds,volts\n0.000,3.30\n"))
+
+			By("Replacing the samples when the capture is restarted")
+			Expect(target.write(ctx, captureHeader, true)).To(Succeed())
+			Expect(os.ReadFile(target.path)).To(BeEquivalentTo(captureHeader))
+		})
+
+		It("should only write ConfigMaps belonging to the Jumperless", func() {
+			instance := &jumperlessv5alpha1.Jumperless{
+				ObjectMeta: metav1.ObjectMeta{Name: "capture-owner", Namespace: "default"},
+				Spec: jumperlessv5alpha1.JumperlessSpec{
+					Host: jumperlessv5alpha1.JumperlessHost{Local: &jumperlessv5alpha1.JumperlessHostLocal{}},
+				},
+			}
+			Expect(k8sClient.Create(ctx, instance)).To(Succeed())
+			DeferCleanup(k8sClient.Delete, ctx, instance)
+
+			target := &configMapCaptureTarget{
+				client: k8sClient,
+				scheme: k8sClient.Scheme(),
+				owner:  instance,
+				key:    types.NamespacedName{Namespace: "default", Name: "capture-owned"},
+				field:  "startup.csv",
+			}
+			Expect(target.write(ctx, captureHeader, true)).To(Succeed())
+			Expect(target.write(ctx, "0.000,3.30\n", false)).To(Succeed())
+
+			owned := &corev1.ConfigMap{}
+			Expect(k8sClient.Get(ctx, target.key, owned)).To(Succeed())
+			DeferCleanup(k8sClient.Delete, ctx, owned)
+			Expect(owned.Data).To(HaveKeyWithValue("startup.csv", "seconds,volts\n0.000,3.30\n"))
+			Expect(owned.OwnerReferences).To(ContainElement(HaveField("UID", instance.UID)))
+
+			By("Refusing to write a ConfigMap of someone else")
+			other := &corev1.ConfigMap{
+				ObjectMeta: metav1.ObjectMeta{Name: "capture-other", Namespace: "default"},
+				Data:       map[string]string{"startup.csv": "keep"},
+			}
+			Expect(k8sClient.Create(ctx, other)).To(Succeed())
+			DeferCleanup(k8sClient.Delete, ctx, other)
+
+			target.key.Name = other.Name
+			Expect(target.write(ctx, captureHeader, true)).To(MatchError(ErrCaptureConfigMapNotOwned))
+			Expect(k8sClient.Get(ctx, target.key, other)).To(Succeed())
+			Expect(other.Data).To(HaveKeyWithValue("startup.csv", "keep"))
+
+			By("Writing the ConfigMap once it is labelled for the Jumperless")
+			other.Labels = map[string]string{jumperlessv5alpha1.CaptureLabel: instance.Name}
+			Expect(k8sClient.Update(ctx, other)).To(Succeed())
+			Expect(target.write(ctx, captureHeader, true)).To(Succeed())
+			Expect(k8sClient.Get(ctx, target.key, other)).To(Succeed())
+			Expect(other.Data).To(HaveKeyWithValue("startup.csv", captureHeader))
+		})
+
+		It("should fail captures to a file without a capture directory", func() {
+			instance := &jumperlessv5alpha1.Jumperless{
+				ObjectMeta: metav1.ObjectMeta{Name: "bench-1", Namespace: "default"},
+				Spec: jumperlessv5alpha1.JumperlessSpec{Capture: &jumperlessv5alpha1.Capture{
+					Name:     "startup",
+					Duration: metav1.Duration{Duration: time.Minute},
+					Target: jumperlessv5alpha1.CaptureTarget{
+						File: &jumperlessv5alpha1.FileCaptureTarget{Path: "startup.csv"},
+					},
+				}},
+			}
+			status := &jumperlessv5alpha1.JumperlessStatus{}
+
+			reconciler := &JumperlessReconciler{}
+			Expect(reconciler.reconcileCapture(ctx, nil, instance, status)).To(Succeed())
+			Expect(status.Capture.Phase).To(Equal(jumperlessv5alpha1.CaptureFailed))
+			Expect(status.Capture.Message).To(ContainSubstring(ErrCaptureDirNotConfigured.Error()))
+			Expect(status.Capture.CompletionTime).NotTo(BeNil())
+
+			By("Not retrying the failed capture")
+			failed := status.Capture.DeepCopy()
+			Expect(reconciler.reconcileCapture(ctx, nil, instance, status)).To(Succeed())
+			Expect(status.Capture).To(Equal(failed))
+		})
+	})
 })
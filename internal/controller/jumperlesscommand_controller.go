@@ -0,0 +1,370 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/x/ansi"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	kerrors "k8s.io/apimachinery/pkg/util/errors"
+	"k8s.io/utils/ptr"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	jumperlessv5alpha1 "github.com/detiber/k8s-jumperless/api/v5alpha1"
+	"github.com/detiber/k8s-jumperless/internal/agent"
+	"github.com/detiber/k8s-jumperless/jumperless"
+)
+
+// maxCommandOutput is the maximum number of bytes of a command response stored in the status,
+// keeping the resource well below the size limit of etcd.
+const maxCommandOutput = 32 * 1024
+
+// JumperlessCommandReconciler runs JumperlessCommand resources on their Jumperless devices
+type JumperlessCommandReconciler struct {
+	client.Client
+	Scheme *runtime.Scheme
+
+	// Ports shares the device ports with the other controller components, if nil the port is
+	// opened for each command
+	Ports *jumperless.PortManager
+
+	// Agents connects to the agents driving the devices attached to other nodes, if nil such devices fail
+	Agents *agent.Pool
+
+	// Identity identifies the manager in the leases of the devices, commands are only run by the manager
+	// holding the lease of their device. If empty, commands are run regardless of the leases and the controller
+	// only runs on the leader of the managers.
+	Identity string
+
+	// Workqueue bounds the concurrency of the controller and the rate commands are requeued at
+	Workqueue WorkqueueOptions
+}
+
+// +kubebuilder:rbac:groups=jumperless.detiber.us,resources=jumperlesscommands,verbs=get;list;watch;update;patch;delete
+// +kubebuilder:rbac:groups=jumperless.detiber.us,resources=jumperlesscommands/status,verbs=get;update;patch
+
+// Reconcile runs a JumperlessCommand once, storing the response in its status, and deletes it once
+// its TTL after completion has passed.
+func (r *JumperlessCommandReconciler) Reconcile(ctx context.Context, req ctrl.Request) (_ ctrl.Result, retErr error) {
+	log := ctrl.LoggerFrom(ctx)
+
+	command := &jumperlessv5alpha1.JumperlessCommand{}
+	if err := r.Get(ctx, req.NamespacedName, command); err != nil {
+		return ctrl.Result{}, client.IgnoreNotFound(err) //nolint:wrapcheck
+	}
+
+	if !command.DeletionTimestamp.IsZero() {
+		return ctrl.Result{}, nil
+	}
+
+	if command.Status.CompletionTime != nil {
+		return r.reconcileTTL(ctx, command)
+	}
+
+	status := command.Status.DeepCopy()
+
+	// Only the manager driving the device runs commands on it, so replicas of the manager never open a
+	// session to the same device. The command is left to that manager until this one holds the lease.
+	if command.Status.StartTime == nil {
+		holder, held, err := r.holdsDevice(ctx, command)
+		if err != nil {
+			return ctrl.Result{}, err
+		}
+		if !held {
+			log.Info("Jumperless is driven by another manager, retrying later",
+				"heldBy", holder, "after", leaseConflictRetryInterval)
+			return ctrl.Result{RequeueAfter: leaseConflictRetryInterval}, nil
+		}
+	}
+
+	// A command that was started but never completed was interrupted, e.g. by a controller restart.
+	// It is not run again since device commands are not necessarily idempotent. The status is only
+	// patched if the cached command is current, since it may not have observed the completion yet.
+	if status.StartTime != nil {
+		r.complete(command, status, "Interrupted", "The controller was interrupted while running the command", nil)
+		if err := r.patchStatus(ctx, command, status, command.ResourceVersion); err != nil {
+			return ctrl.Result{}, err
+		}
+
+		return ctrl.Result{}, nil
+	}
+
+	// The start is recorded before the command is sent, so it is never sent twice
+	status.StartTime = ptr.To(metav1.Now())
+	if err := r.patchStatus(ctx, command, status, command.ResourceVersion); err != nil {
+		return ctrl.Result{}, err
+	}
+
+	// Always update the status once the command was started
+	defer func() {
+		if err := r.patchStatus(ctx, command, status, ""); err != nil {
+			log.Error(err, "unable to patch JumperlessCommand status")
+			retErr = kerrors.NewAggregate([]error{retErr, err})
+		}
+	}()
+
+	output, err := r.run(ctx, command, status)
+	if err != nil {
+		log.Error(err, "unable to run JumperlessCommand")
+
+		reason := "CommandFailed"
+		var notRunnable commandNotRunnableError
+		if errors.As(err, &notRunnable) {
+			reason = notRunnable.reason
+		}
+
+		r.complete(command, status, reason, err.Error(), nil)
+		return ctrl.Result{}, nil
+	}
+
+	r.complete(command, status, "Succeeded", "Command completed", &output)
+
+	return ctrl.Result{}, nil
+}
+
+// holdsDevice returns whether the manager holds the lease of the device of the command, along with the current
+// holder of the lease. The lease is only held by a manager the device is reachable from, see reachesDevice.
+// Commands of missing devices are held, so they fail when they are run.
+func (r *JumperlessCommandReconciler) holdsDevice(ctx context.Context,
+	command *jumperlessv5alpha1.JumperlessCommand) (string, bool, error) {
+	if r.Identity == "" {
+		return "", true, nil
+	}
+
+	instance := &jumperlessv5alpha1.Jumperless{}
+	key := client.ObjectKey{Namespace: command.Namespace, Name: command.Spec.JumperlessName}
+	if err := r.Get(ctx, key, instance); err != nil {
+		if apierrors.IsNotFound(err) {
+			return "", true, nil
+		}
+
+		return "", false, fmt.Errorf("unable to fetch Jumperless %s: %w", key, err)
+	}
+
+	held := holdsLease(instance.Status.HeldBy, r.Identity,
+		instance.GetAnnotations()[jumperlessv5alpha1.TakeoverAnnotation], time.Now())
+
+	return previousHolder(instance.Status.HeldBy), held, nil
+}
+
+// commandNotRunnableError is returned when the command cannot be sent to a device at all
+type commandNotRunnableError struct {
+	reason string
+	err    error
+}
+
+func (e commandNotRunnableError) Error() string {
+	return e.err.Error()
+}
+
+func (e commandNotRunnableError) Unwrap() error {
+	return e.err
+}
+
+// run sends the command to the device of the referenced Jumperless and returns the response.
+func (r *JumperlessCommandReconciler) run(ctx context.Context, command *jumperlessv5alpha1.JumperlessCommand,
+	status *jumperlessv5alpha1.JumperlessCommandStatus) (string, error) {
+	log := ctrl.LoggerFrom(ctx)
+
+	instance := &jumperlessv5alpha1.Jumperless{}
+	key := client.ObjectKey{Namespace: command.Namespace, Name: command.Spec.JumperlessName}
+	if err := r.Get(ctx, key, instance); err != nil {
+		if apierrors.IsNotFound(err) {
+			return "", commandNotRunnableError{reason: "JumperlessNotFound", err: err}
+		}
+
+		return "", fmt.Errorf("unable to fetch Jumperless %s: %w", key, err)
+	}
+
+	if instance.Spec.Host.Local == nil && instance.Spec.Host.Agent == nil {
+		return "", commandNotRunnableError{
+			reason: "NotImplemented",
+			err:    fmt.Errorf("commands on remote hosts are not supported: %w", ErrNotImplemented),
+		}
+	}
+
+	handle, err := acquirePort(ctx, r.Ports, r.Agents, &instance.Spec.Host)
+	if err != nil {
+		return "", commandNotRunnableError{
+			reason: "PortUnavailable",
+			err:    fmt.Errorf("unable to open Jumperless port: %w", err),
+		}
+	}
+	defer func() {
+		if err := handle.Release(); err != nil {
+			log.Error(err, "unable to release Jumperless port", "port", handle.Jumperless().GetPort())
+		}
+	}()
+
+	j := handle.Jumperless()
+	status.Port = ptr.To(j.GetPort())
+
+	wait := time.Duration(0)
+	if command.Spec.Wait != nil {
+		wait = command.Spec.Wait.Duration
+	}
+
+	if scan := command.Spec.I2CScan; scan != nil {
+		log.Info("Scanning I2C bus on Jumperless", "jumperless", key, "port", j.GetPort(),
+			"sda", scan.SDA, "scl", scan.SCL)
+
+		return scanI2C(j, scan, status)
+	}
+
+	log.Info("Running command on Jumperless", "jumperless", key, "port", j.GetPort(),
+		"command", command.Spec.Command, "raw", command.Spec.Raw)
+
+	if command.Spec.Raw {
+		response, err := j.ExecRawCommand(command.Spec.Command, wait)
+		if errors.Is(err, jumperless.ErrConsoleAttached) {
+			return "", commandNotRunnableError{reason: "ConsoleAttached", err: err}
+		}
+		if err != nil {
+			return "", fmt.Errorf("unable to run raw command: %w", err)
+		}
+
+		// Raw responses are cleaned the same way as Python responses, without removing any lines
+		return strings.TrimSpace(strings.ReplaceAll(ansi.Strip(response), "\r\n", "\n")), nil
+	}
+
+	response, err := j.ExecPythonCommand(command.Spec.Command, wait)
+	if errors.Is(err, jumperless.ErrConsoleAttached) {
+		return "", commandNotRunnableError{reason: "ConsoleAttached", err: err}
+	}
+	if err != nil {
+		return "", fmt.Errorf("unable to run Python command: %w", err)
+	}
+
+	return response, nil
+}
+
+// scanI2C scans the I2C bus of the command, recording the addresses of the devices found in the status. The
+// output lists the addresses, or states that no device was found.
+func scanI2C(j *jumperless.Jumperless, scan *jumperlessv5alpha1.I2CScan,
+	status *jumperlessv5alpha1.JumperlessCommandStatus) (string, error) {
+	addresses, err := j.ScanI2C(scan.SDA, scan.SCL)
+	if errors.Is(err, jumperless.ErrConsoleAttached) {
+		return "", commandNotRunnableError{reason: "ConsoleAttached", err: err}
+	}
+	if err != nil {
+		return "", err //nolint:wrapcheck
+	}
+
+	status.I2CDevices = make([]string, 0, len(addresses))
+	for _, address := range addresses {
+		status.I2CDevices = append(status.I2CDevices, jumperless.FormatI2CAddress(address))
+	}
+
+	if len(addresses) == 0 {
+		return fmt.Sprintf("No I2C devices found on %s/%s", scan.SDA, scan.SCL), nil
+	}
+
+	return fmt.Sprintf("Found %d I2C devices on %s/%s: %s", len(addresses), scan.SDA, scan.SCL,
+		strings.Join(status.I2CDevices, ", ")), nil
+}
+
+// complete records the outcome of the command, a nil output marks the command as failed.
+func (r *JumperlessCommandReconciler) complete(command *jumperlessv5alpha1.JumperlessCommand,
+	status *jumperlessv5alpha1.JumperlessCommandStatus, reason, message string, output *string) {
+	conditionStatus := metav1.ConditionFalse
+	if output != nil {
+		conditionStatus = metav1.ConditionTrue
+
+		if len(*output) > maxCommandOutput {
+			output = ptr.To((*output)[:maxCommandOutput])
+			status.OutputTruncated = true
+		}
+		status.Output = output
+	}
+
+	status.CompletionTime = ptr.To(metav1.Now())
+	meta.SetStatusCondition(&status.Conditions, metav1.Condition{
+		Type:               jumperlessv5alpha1.ConditionComplete,
+		Status:             conditionStatus,
+		Reason:             reason,
+		Message:            message,
+		ObservedGeneration: command.Generation,
+	})
+}
+
+// reconcileTTL deletes a completed command once its TTL has passed, and requeues it until then.
+func (r *JumperlessCommandReconciler) reconcileTTL(ctx context.Context,
+	command *jumperlessv5alpha1.JumperlessCommand) (ctrl.Result, error) {
+	if command.Spec.TTLSecondsAfterFinished == nil {
+		return ctrl.Result{}, nil
+	}
+
+	ttl := time.Duration(*command.Spec.TTLSecondsAfterFinished) * time.Second
+	if remaining := time.Until(command.Status.CompletionTime.Add(ttl)); remaining > 0 {
+		return ctrl.Result{RequeueAfter: remaining}, nil
+	}
+
+	ctrl.LoggerFrom(ctx).Info("Deleting JumperlessCommand after its TTL", "ttl", ttl)
+	if err := r.Delete(ctx, command); client.IgnoreNotFound(err) != nil {
+		return ctrl.Result{}, fmt.Errorf("unable to delete JumperlessCommand: %w", err)
+	}
+
+	return ctrl.Result{}, nil
+}
+
+// patchStatus applies the status of the command. If resourceVersion is set, the patch fails with a conflict
+// unless the command is unchanged since that version.
+func (r *JumperlessCommandReconciler) patchStatus(ctx context.Context, command *jumperlessv5alpha1.JumperlessCommand, status *jumperlessv5alpha1.JumperlessCommandStatus, resourceVersion string) error {
+	// Create a new instance to hold the status update to avoid issues with potential SSA diffs
+	statusInstance := &jumperlessv5alpha1.JumperlessCommand{}
+	statusInstance.SetGroupVersionKind(jumperlessv5alpha1.GroupVersion.WithKind("JumperlessCommand"))
+	statusInstance.SetName(command.Name)
+	statusInstance.SetNamespace(command.Namespace)
+	statusInstance.SetResourceVersion(resourceVersion)
+	status.DeepCopyInto(&statusInstance.Status)
+
+	uResource, err := runtime.DefaultUnstructuredConverter.ToUnstructured(statusInstance)
+	if err != nil {
+		return fmt.Errorf("unable to convert JumperlessCommand status to unstructured: %w", err)
+	}
+
+	u := &unstructured.Unstructured{}
+	u.SetUnstructuredContent(uResource)
+
+	// See JumperlessReconciler.patchStatus for why the deprecated client.Apply is used here
+	//nolint:staticcheck
+	if err := r.Status().Patch(ctx, u, client.Apply, client.ForceOwnership, client.FieldOwner("k8s-jumperless")); err != nil {
+		return fmt.Errorf("unable to patch JumperlessCommand status: %w", err)
+	}
+
+	return nil
+}
+
+// SetupWithManager sets up the controller with the Manager.
+func (r *JumperlessCommandReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	//nolint:wrapcheck
+	return ctrl.NewControllerManagedBy(mgr).
+		For(&jumperlessv5alpha1.JumperlessCommand{}).
+		WithOptions(deviceControllerOptions(r.Workqueue, r.Identity)).
+		Named("jumperlesscommand").
+		Complete(r)
+}
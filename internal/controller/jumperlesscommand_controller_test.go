@@ -0,0 +1,153 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+
+	jumperlessv5alpha1 "github.com/detiber/k8s-jumperless/api/v5alpha1"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	"github.com/detiber/k8s-jumperless/jumperless"
+	"github.com/detiber/k8s-jumperless/jumperless/serialmock"
+)
+
+var _ = Describe("JumperlessCommand Controller", func() {
+	Context("When reconciling a resource", func() {
+		const resourceName = "test-command"
+
+		ctx := context.Background()
+
+		typeNamespacedName := types.NamespacedName{
+			Name:      resourceName,
+			Namespace: "default",
+		}
+
+		BeforeEach(func() {
+			By("creating the custom resource for the Kind JumperlessCommand")
+			command := &jumperlessv5alpha1.JumperlessCommand{}
+			err := k8sClient.Get(ctx, typeNamespacedName, command)
+			if err != nil && errors.IsNotFound(err) {
+				resource := &jumperlessv5alpha1.JumperlessCommand{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      resourceName,
+						Namespace: "default",
+					},
+					Spec: jumperlessv5alpha1.JumperlessCommandSpec{
+						JumperlessName:          "missing-jumperless",
+						Command:                 "dac_get(0)",
+						TTLSecondsAfterFinished: ptr.To[int32](0),
+					},
+				}
+				Expect(k8sClient.Create(ctx, resource)).To(Succeed())
+			}
+		})
+
+		AfterEach(func() {
+			resource := &jumperlessv5alpha1.JumperlessCommand{}
+			if err := k8sClient.Get(ctx, typeNamespacedName, resource); err == nil {
+				By("Cleanup the specific resource instance JumperlessCommand")
+				Expect(k8sClient.Delete(ctx, resource)).To(Succeed())
+			}
+		})
+
+		It("should fail without retrying and delete the command after its TTL", func() {
+			controllerReconciler := &JumperlessCommandReconciler{
+				Client: k8sClient,
+				Scheme: k8sClient.Scheme(),
+			}
+
+			By("Running the command on a Jumperless that does not exist")
+			_, err := controllerReconciler.Reconcile(ctx, reconcile.Request{NamespacedName: typeNamespacedName})
+			Expect(err).NotTo(HaveOccurred())
+
+			command := &jumperlessv5alpha1.JumperlessCommand{}
+			Expect(k8sClient.Get(ctx, typeNamespacedName, command)).To(Succeed())
+			Expect(command.Status.StartTime).NotTo(BeNil())
+			Expect(command.Status.CompletionTime).NotTo(BeNil())
+			Expect(command.Status.Output).To(BeNil())
+
+			complete := meta.FindStatusCondition(command.Status.Conditions, jumperlessv5alpha1.ConditionComplete)
+			Expect(complete).NotTo(BeNil())
+			Expect(complete.Status).To(Equal(metav1.ConditionFalse))
+			Expect(complete.Reason).To(Equal("JumperlessNotFound"))
+
+			By("Deleting the completed command once its TTL has passed")
+			_, err = controllerReconciler.Reconcile(ctx, reconcile.Request{NamespacedName: typeNamespacedName})
+			Expect(err).NotTo(HaveOccurred())
+
+			err = k8sClient.Get(ctx, typeNamespacedName, command)
+			Expect(errors.IsNotFound(err)).To(BeTrue())
+		})
+
+		It("should not run a command again once it was started", func() {
+			command := &jumperlessv5alpha1.JumperlessCommand{}
+			Expect(k8sClient.Get(ctx, typeNamespacedName, command)).To(Succeed())
+			command.Status.StartTime = ptr.To(metav1.Now())
+			Expect(k8sClient.Status().Update(ctx, command)).To(Succeed())
+
+			controllerReconciler := &JumperlessCommandReconciler{
+				Client: k8sClient,
+				Scheme: k8sClient.Scheme(),
+			}
+
+			_, err := controllerReconciler.Reconcile(ctx, reconcile.Request{NamespacedName: typeNamespacedName})
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(k8sClient.Get(ctx, typeNamespacedName, command)).To(Succeed())
+			complete := meta.FindStatusCondition(command.Status.Conditions, jumperlessv5alpha1.ConditionComplete)
+			Expect(complete).NotTo(BeNil())
+			Expect(complete.Reason).To(Equal("Interrupted"))
+		})
+	})
+
+	Context("When scanning an I2C bus", func() {
+		scan := &jumperlessv5alpha1.I2CScan{SDA: "20", SCL: "21"}
+
+		It("should record the addresses of the devices found", func() {
+			port := serialmock.New()
+			port.Expect("?").Respond("Jumperless firmware version: 5.3.1.0\r\n")
+
+			j, err := jumperless.NewJumperlessWithOpener("/dev/ttyMock", 0, port.Open)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(j.OpenPort()).To(Succeed())
+			DeferCleanup(j.ClosePort)
+
+			port.Expect(`>i2c_scan("20", "21")`).Respond("Python> >i2c_scan(\"20\", \"21\")\r\n[104, 60]\r\n")
+			status := &jumperlessv5alpha1.JumperlessCommandStatus{}
+			output, err := scanI2C(j, scan, status)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(status.I2CDevices).To(Equal([]string{"0x3C", "0x68"}))
+			Expect(output).To(Equal("Found 2 I2C devices on 20/21: 0x3C, 0x68"))
+
+			By("Reporting a bus without devices")
+			port.Expect(`>i2c_scan("20", "21")`).Respond("Python> >i2c_scan(\"20\", \"21\")\r\n[]\r\n")
+			output, err = scanI2C(j, scan, status)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(status.I2CDevices).To(BeEmpty())
+			Expect(output).To(Equal("No I2C devices found on 20/21"))
+			Expect(port.ExpectationsMet()).To(Succeed())
+		})
+	})
+})
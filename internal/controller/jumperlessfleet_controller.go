@@ -0,0 +1,284 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"maps"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"time"
+
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	kerrors "k8s.io/apimachinery/pkg/util/errors"
+	"k8s.io/utils/ptr"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+
+	jumperlessv5alpha1 "github.com/detiber/k8s-jumperless/api/v5alpha1"
+	"github.com/detiber/k8s-jumperless/jumperless"
+)
+
+const defaultDiscoveryInterval = time.Minute
+
+//nolint:gochecknoglobals
+var (
+	invalidNameChars       = regexp.MustCompile(`[^a-z0-9-]+`)
+	invalidLabelValueChars = regexp.MustCompile(`[^A-Za-z0-9._-]+`)
+)
+
+// DiscoverFunc discovers the Jumperless devices attached to the node
+type DiscoverFunc func(ctx context.Context, baudRate int) ([]jumperless.DeviceInfo, error)
+
+// JumperlessFleetReconciler reconciles a JumperlessFleet object
+type JumperlessFleetReconciler struct {
+	client.Client
+	Scheme *runtime.Scheme
+
+	// Discover is used to discover devices, defaults to jumperless.DiscoverJumperlessDevices.
+	// Use the Discover method of the PortManager shared with the JumperlessReconciler to avoid
+	// probing ports while they are in use.
+	Discover DiscoverFunc
+
+	// Workqueue bounds the concurrency of the controller and the rate fleets are requeued at
+	Workqueue WorkqueueOptions
+}
+
+// +kubebuilder:rbac:groups=jumperless.detiber.us,resources=jumperlessfleets,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups=jumperless.detiber.us,resources=jumperlessfleets/status,verbs=get;update;patch
+// +kubebuilder:rbac:groups=jumperless.detiber.us,resources=jumperlessfleets/finalizers,verbs=update
+
+// Reconcile enumerates the serial ports on the node, probes them for Jumperless devices and
+// creates or adopts a Jumperless resource for each device found.
+func (r *JumperlessFleetReconciler) Reconcile(ctx context.Context, req ctrl.Request) (_ ctrl.Result, retErr error) {
+	log := ctrl.LoggerFrom(ctx)
+
+	log.Info("Reconciling JumperlessFleet", "request", req.NamespacedName)
+
+	fleet := &jumperlessv5alpha1.JumperlessFleet{}
+	if err := r.Get(ctx, req.NamespacedName, fleet); err != nil {
+		log.Error(err, "unable to fetch JumperlessFleet")
+		return ctrl.Result{}, client.IgnoreNotFound(err) //nolint:wrapcheck
+	}
+
+	status := fleet.Status.DeepCopy()
+
+	// Always update the status
+	defer func() {
+		if err := r.patchStatus(ctx, fleet, status); err != nil {
+			log.Error(err, "unable to patch JumperlessFleet status")
+			retErr = kerrors.NewAggregate([]error{retErr, err})
+		}
+	}()
+
+	interval := defaultDiscoveryInterval
+	if fleet.Spec.DiscoveryInterval != nil {
+		interval = fleet.Spec.DiscoveryInterval.Duration
+	}
+
+	discover := r.Discover
+	if discover == nil {
+		discover = jumperless.DiscoverJumperlessDevices
+	}
+
+	// Errors probing individual ports are not fatal, devices may be busy or not Jumperless at all
+	devices, err := discover(ctx, int(ptr.Deref(fleet.Spec.BaudRate, 0)))
+	if err != nil && !errors.Is(err, jumperless.ErrNoSerialPortFound) {
+		log.Error(err, "errors encountered during discovery")
+	}
+
+	status.LastDiscoveryTime = ptr.To(metav1.Now())
+	status.Devices = []jumperlessv5alpha1.DiscoveredDevice{}
+
+	errs := []error{}
+	for _, device := range devices {
+		discovered, adopted, err := r.reconcileDevice(ctx, fleet, device)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+
+		// Devices whose Jumperless is owned by another controller are left to it
+		if !adopted {
+			continue
+		}
+
+		status.Devices = append(status.Devices, discovered)
+	}
+
+	if err := kerrors.NewAggregate(errs); err != nil {
+		meta.SetStatusCondition(&status.Conditions, metav1.Condition{
+			Type:               jumperlessv5alpha1.ConditionReady,
+			Status:             metav1.ConditionFalse,
+			Reason:             "DeviceReconcileError",
+			Message:            err.Error(),
+			ObservedGeneration: fleet.Generation,
+		})
+
+		return ctrl.Result{}, fmt.Errorf("unable to reconcile discovered devices: %w", err)
+	}
+
+	meta.SetStatusCondition(&status.Conditions, metav1.Condition{
+		Type:               jumperlessv5alpha1.ConditionReady,
+		Status:             metav1.ConditionTrue,
+		Reason:             "Discovered",
+		Message:            fmt.Sprintf("Discovered %d Jumperless devices", len(status.Devices)),
+		ObservedGeneration: fleet.Generation,
+	})
+
+	return ctrl.Result{RequeueAfter: interval}, nil
+}
+
+// reconcileDevice creates or adopts the Jumperless resource for a discovered device, returning whether the
+// resource belongs to the fleet. A Jumperless owned by another controller is left unchanged.
+func (r *JumperlessFleetReconciler) reconcileDevice(ctx context.Context, fleet *jumperlessv5alpha1.JumperlessFleet, device jumperless.DeviceInfo) (jumperlessv5alpha1.DiscoveredDevice, bool, error) {
+	log := ctrl.LoggerFrom(ctx)
+
+	instance := &jumperlessv5alpha1.Jumperless{}
+	instance.Name = deviceResourceName(device)
+	instance.Namespace = fleet.Spec.TargetNamespace
+
+	foreign := false
+	result, err := controllerutil.CreateOrPatch(ctx, r.Client, instance, func() error {
+		// Check the owner before changing anything, so nothing is patched onto a resource of another controller
+		if owner := metav1.GetControllerOf(instance); owner != nil && owner.UID != fleet.UID {
+			foreign = true
+			return nil
+		}
+
+		labels := instance.GetLabels()
+		if labels == nil {
+			labels = map[string]string{}
+		}
+		maps.Copy(labels, fleet.Spec.Labels)
+		labels[jumperlessv5alpha1.FleetLabel] = fleet.Name
+		if device.SerialNumber != "" {
+			labels[jumperlessv5alpha1.SerialNumberLabel] = sanitizeLabelValue(device.SerialNumber)
+		}
+		instance.SetLabels(labels)
+
+		// Only fill in the host for new resources, adopted resources keep their existing host.
+		// Select by serial number when available, since port numbering may change across reboots.
+		if instance.CreationTimestamp.IsZero() {
+			instance.Spec.Host.Local = &jumperlessv5alpha1.JumperlessHostLocal{
+				BaudRate: fleet.Spec.BaudRate,
+			}
+			if device.SerialNumber != "" {
+				instance.Spec.Host.Local.SerialNumber = ptr.To(device.SerialNumber)
+			} else {
+				instance.Spec.Host.Local.Port = ptr.To(device.Port)
+			}
+		}
+
+		if err := controllerutil.SetControllerReference(fleet, instance, r.Scheme); err != nil {
+			return fmt.Errorf("unable to set controller reference: %w", err)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return jumperlessv5alpha1.DiscoveredDevice{}, false, fmt.Errorf("unable to create or adopt Jumperless %s/%s: %w",
+			instance.Namespace, instance.Name, err)
+	}
+
+	if foreign {
+		log.Info("Jumperless is owned by another controller, not adopting", "name", instance.Name,
+			"owner", metav1.GetControllerOf(instance).Name)
+		return jumperlessv5alpha1.DiscoveredDevice{}, false, nil
+	}
+
+	log.Info("Reconciled discovered Jumperless", "name", instance.Name, "port", device.Port, "result", result)
+
+	discovered := jumperlessv5alpha1.DiscoveredDevice{
+		Name: instance.Name,
+		Port: device.Port,
+	}
+	if device.SerialNumber != "" {
+		discovered.SerialNumber = ptr.To(device.SerialNumber)
+	}
+	if device.Version != "" {
+		discovered.FirmwareVersion = ptr.To(device.Version)
+	}
+
+	return discovered, true, nil
+}
+
+func (r *JumperlessFleetReconciler) patchStatus(ctx context.Context, fleet *jumperlessv5alpha1.JumperlessFleet, status *jumperlessv5alpha1.JumperlessFleetStatus) error {
+	// Create a new instance to hold the status update to avoid issues with potential SSA diffs
+	statusInstance := &jumperlessv5alpha1.JumperlessFleet{}
+	statusInstance.SetGroupVersionKind(jumperlessv5alpha1.GroupVersion.WithKind("JumperlessFleet"))
+	statusInstance.SetName(fleet.Name)
+	status.DeepCopyInto(&statusInstance.Status)
+
+	uResource, err := runtime.DefaultUnstructuredConverter.ToUnstructured(statusInstance)
+	if err != nil {
+		return fmt.Errorf("unable to convert JumperlessFleet status to unstructured: %w", err)
+	}
+
+	u := &unstructured.Unstructured{}
+	u.SetUnstructuredContent(uResource)
+
+	// See JumperlessReconciler.patchStatus for why the deprecated client.Apply is used here
+	//nolint:staticcheck
+	if err := r.Status().Patch(ctx, u, client.Apply, client.ForceOwnership, client.FieldOwner("k8s-jumperless")); err != nil {
+		return fmt.Errorf("unable to patch JumperlessFleet status: %w", err)
+	}
+
+	return nil
+}
+
+// deviceResourceName returns the name of the Jumperless resource for a discovered device,
+// keyed by the USB serial number when available and the port name otherwise.
+func deviceResourceName(device jumperless.DeviceInfo) string {
+	key := device.SerialNumber
+	if key == "" {
+		key = filepath.Base(device.Port)
+	}
+
+	name := strings.Trim(invalidNameChars.ReplaceAllString(strings.ToLower(key), "-"), "-")
+
+	return "jumperless-" + name
+}
+
+// sanitizeLabelValue ensures a serial number can be used as a label value.
+func sanitizeLabelValue(value string) string {
+	value = invalidLabelValueChars.ReplaceAllString(value, "_")
+	if len(value) > 63 {
+		value = value[:63]
+	}
+
+	return strings.Trim(value, "._-")
+}
+
+// SetupWithManager sets up the controller with the Manager.
+// Owned Jumperless resources are intentionally not watched, since their status changes would
+// otherwise trigger a full re-probe of the serial ports; discovery is driven by the discovery interval.
+func (r *JumperlessFleetReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	//nolint:wrapcheck
+	return ctrl.NewControllerManagedBy(mgr).
+		For(&jumperlessv5alpha1.JumperlessFleet{}).
+		Named("jumperlessfleet").
+		WithOptions(r.Workqueue.controllerOptions()).
+		Complete(r)
+}
@@ -0,0 +1,161 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+
+	jumperlessv5alpha1 "github.com/detiber/k8s-jumperless/api/v5alpha1"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	"github.com/detiber/k8s-jumperless/jumperless"
+)
+
+var _ = Describe("JumperlessFleet Controller", func() {
+	Context("When reconciling a resource", func() {
+		const resourceName = "test-fleet"
+
+		ctx := context.Background()
+
+		typeNamespacedName := types.NamespacedName{
+			Name: resourceName,
+		}
+		deviceNamespacedName := types.NamespacedName{
+			Name:      "jumperless-abc123",
+			Namespace: "default",
+		}
+
+		BeforeEach(func() {
+			By("creating the custom resource for the Kind JumperlessFleet")
+			fleet := &jumperlessv5alpha1.JumperlessFleet{}
+			err := k8sClient.Get(ctx, typeNamespacedName, fleet)
+			if err != nil && errors.IsNotFound(err) {
+				resource := &jumperlessv5alpha1.JumperlessFleet{
+					ObjectMeta: metav1.ObjectMeta{
+						Name: resourceName,
+					},
+					Spec: jumperlessv5alpha1.JumperlessFleetSpec{
+						TargetNamespace: "default",
+					},
+				}
+				Expect(k8sClient.Create(ctx, resource)).To(Succeed())
+			}
+		})
+
+		AfterEach(func() {
+			device := &jumperlessv5alpha1.Jumperless{}
+			if err := k8sClient.Get(ctx, deviceNamespacedName, device); err == nil {
+				Expect(k8sClient.Delete(ctx, device)).To(Succeed())
+			}
+
+			resource := &jumperlessv5alpha1.JumperlessFleet{}
+			err := k8sClient.Get(ctx, typeNamespacedName, resource)
+			Expect(err).NotTo(HaveOccurred())
+
+			By("Cleanup the specific resource instance JumperlessFleet")
+			Expect(k8sClient.Delete(ctx, resource)).To(Succeed())
+		})
+
+		It("should create a Jumperless resource for each discovered device", func() {
+			By("Reconciling the created resource")
+			controllerReconciler := &JumperlessFleetReconciler{
+				Client: k8sClient,
+				Scheme: k8sClient.Scheme(),
+				Discover: func(_ context.Context, _ int) ([]jumperless.DeviceInfo, error) {
+					return []jumperless.DeviceInfo{
+						{Port: "/dev/ttyACM0", SerialNumber: "ABC123", Version: "5.3.1.0"},
+					}, nil
+				},
+			}
+
+			_, err := controllerReconciler.Reconcile(ctx, reconcile.Request{
+				NamespacedName: typeNamespacedName,
+			})
+			Expect(err).NotTo(HaveOccurred())
+
+			device := &jumperlessv5alpha1.Jumperless{}
+			Expect(k8sClient.Get(ctx, deviceNamespacedName, device)).To(Succeed())
+			Expect(device.Labels).To(HaveKeyWithValue(jumperlessv5alpha1.FleetLabel, resourceName))
+			Expect(device.Labels).To(HaveKeyWithValue(jumperlessv5alpha1.SerialNumberLabel, "ABC123"))
+			Expect(device.Spec.Host.Local).NotTo(BeNil())
+			Expect(device.Spec.Host.Local.SerialNumber).To(HaveValue(Equal("ABC123")))
+
+			fleet := &jumperlessv5alpha1.JumperlessFleet{}
+			Expect(k8sClient.Get(ctx, typeNamespacedName, fleet)).To(Succeed())
+			Expect(fleet.Status.Devices).To(HaveLen(1))
+		})
+
+		It("should leave a Jumperless owned by another controller untouched", func() {
+			By("Creating a Jumperless controlled by another owner")
+			owned := &jumperlessv5alpha1.Jumperless{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      deviceNamespacedName.Name,
+					Namespace: deviceNamespacedName.Namespace,
+					OwnerReferences: []metav1.OwnerReference{{
+						APIVersion: "v1",
+						Kind:       "ConfigMap",
+						Name:       "other-owner",
+						UID:        "other-owner-uid",
+						Controller: ptr.To(true),
+					}},
+				},
+				Spec: jumperlessv5alpha1.JumperlessSpec{
+					Host: jumperlessv5alpha1.JumperlessHost{
+						Local: &jumperlessv5alpha1.JumperlessHostLocal{
+							Port: ptr.To("/dev/ttyOther"),
+						},
+					},
+				},
+			}
+			Expect(k8sClient.Create(ctx, owned)).To(Succeed())
+
+			controllerReconciler := &JumperlessFleetReconciler{
+				Client: k8sClient,
+				Scheme: k8sClient.Scheme(),
+				Discover: func(_ context.Context, _ int) ([]jumperless.DeviceInfo, error) {
+					return []jumperless.DeviceInfo{
+						{Port: "/dev/ttyACM0", SerialNumber: "ABC123", Version: "5.3.1.0"},
+					}, nil
+				},
+			}
+
+			_, err := controllerReconciler.Reconcile(ctx, reconcile.Request{
+				NamespacedName: typeNamespacedName,
+			})
+			Expect(err).NotTo(HaveOccurred())
+
+			device := &jumperlessv5alpha1.Jumperless{}
+			Expect(k8sClient.Get(ctx, deviceNamespacedName, device)).To(Succeed())
+			Expect(device.Labels).NotTo(HaveKey(jumperlessv5alpha1.FleetLabel))
+			Expect(device.Labels).NotTo(HaveKey(jumperlessv5alpha1.SerialNumberLabel))
+			Expect(device.OwnerReferences).To(HaveLen(1))
+			Expect(device.OwnerReferences[0].UID).To(Equal(types.UID("other-owner-uid")))
+			Expect(device.Spec.Host.Local.Port).To(HaveValue(Equal("/dev/ttyOther")))
+			Expect(device.Spec.Host.Local.SerialNumber).To(BeNil())
+
+			fleet := &jumperlessv5alpha1.JumperlessFleet{}
+			Expect(k8sClient.Get(ctx, typeNamespacedName, fleet)).To(Succeed())
+			Expect(fleet.Status.Devices).To(BeEmpty())
+		})
+	})
+})
@@ -0,0 +1,216 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/ptr"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	jumperlessv5alpha1 "github.com/detiber/k8s-jumperless/api/v5alpha1"
+	"github.com/detiber/k8s-jumperless/jumperless"
+)
+
+// DefaultLeaseDuration is the duration of the device leases held by a manager, unless configured otherwise.
+const DefaultLeaseDuration = time.Minute
+
+// deviceVisibilityRetryInterval is the interval between checks whether a device on a local host was attached to
+// the node of the manager.
+const deviceVisibilityRetryInterval = 30 * time.Second
+
+// leaseConflictRetryInterval is the interval before retrying to acquire a lease another manager claimed first.
+const leaseConflictRetryInterval = 5 * time.Second
+
+// leaseWait returns how long the manager with the given identity has to wait before it may drive a device,
+// zero if it may drive it now. A device handed over with the takeover annotation may only be driven by the
+// manager it was handed to, otherwise it may be driven by the holder of the lease or by anyone once the lease
+// expired. The wait for a device handed to another manager is the given lease duration.
+func leaseWait(lease *jumperlessv5alpha1.DeviceLease, identity, takeover string, duration time.Duration,
+	now time.Time) time.Duration {
+	if takeover != "" {
+		if takeover == identity {
+			return 0
+		}
+
+		return duration
+	}
+
+	if lease == nil || lease.HolderIdentity == identity {
+		return 0
+	}
+
+	expiry := lease.RenewTime.Add(time.Duration(lease.LeaseDurationSeconds) * time.Second)
+	if now.Before(expiry) {
+		return expiry.Sub(now)
+	}
+
+	return 0
+}
+
+// holdsLease returns whether the manager with the given identity holds the unexpired lease of a device and the
+// device isn't handed over to another manager. Unlike leaseWait, a device without a lease isn't held by anyone.
+func holdsLease(lease *jumperlessv5alpha1.DeviceLease, identity, takeover string, now time.Time) bool {
+	if takeover != "" && takeover != identity {
+		return false
+	}
+
+	if lease == nil || lease.HolderIdentity != identity {
+		return false
+	}
+
+	return now.Before(lease.RenewTime.Add(time.Duration(lease.LeaseDurationSeconds) * time.Second))
+}
+
+// acquireLease acquires or renews the lease of the manager on the device of the instance, returning whether
+// it holds the lease and when the lease has to be renewed or acquiring it retried. Leases are disabled unless
+// the reconciler has an Identity.
+//
+// Acquiring the lease is patched with an optimistic lock right away, so when managers race for a device only
+// the first one drives it. Renewals are persisted by the status patch of the reconcile.
+func (r *JumperlessReconciler) acquireLease(ctx context.Context, instance *jumperlessv5alpha1.Jumperless,
+	status *jumperlessv5alpha1.JumperlessStatus) (bool, time.Duration, error) {
+	if r.Identity == "" {
+		return true, 0, nil
+	}
+
+	log := ctrl.LoggerFrom(ctx)
+
+	duration := r.LeaseDuration
+	if duration <= 0 {
+		duration = DefaultLeaseDuration
+	}
+	now := time.Now()
+
+	if wait := leaseWait(instance.Status.HeldBy, r.Identity,
+		instance.GetAnnotations()[jumperlessv5alpha1.TakeoverAnnotation], duration, now); wait > 0 {
+		return false, wait, nil
+	}
+
+	lease := &jumperlessv5alpha1.DeviceLease{
+		HolderIdentity:       r.Identity,
+		AcquireTime:          metav1.NewTime(now),
+		RenewTime:            metav1.NewTime(now),
+		LeaseDurationSeconds: int32(duration / time.Second),
+	}
+
+	if current := instance.Status.HeldBy; current != nil && current.HolderIdentity == r.Identity {
+		// Renew the lease once half of it has passed, so it doesn't change on every reconcile
+		renewAt := current.RenewTime.Add(duration / 2)
+		if now.Before(renewAt) && current.LeaseDurationSeconds == lease.LeaseDurationSeconds {
+			status.HeldBy = current.DeepCopy()
+			return true, renewAt.Sub(now), nil
+		}
+
+		lease.AcquireTime = current.AcquireTime
+		status.HeldBy = lease
+
+		return true, duration / 2, nil
+	}
+
+	patched := instance.DeepCopy()
+	patched.Status.HeldBy = lease
+	if err := r.Status().Patch(ctx, patched,
+		client.MergeFromWithOptions(instance, client.MergeFromWithOptimisticLock{})); err != nil {
+		if apierrors.IsConflict(err) {
+			log.Info("Lease was changed while acquiring it, retrying later", "after", leaseConflictRetryInterval)
+			return false, leaseConflictRetryInterval, nil
+		}
+
+		return false, 0, fmt.Errorf("unable to acquire lease: %w", err)
+	}
+
+	log.Info("Acquired lease", "previousHolder", previousHolder(instance.Status.HeldBy))
+	status.HeldBy = lease
+
+	return true, duration / 2, nil
+}
+
+// reachesDevice returns whether the manager can reach the device of the instance. A device on a local host is
+// only reached if its port or selector resolves on the node of the manager, devices attached to another node or
+// served at an endpoint are reached through the agents by every replica.
+func (r *JumperlessReconciler) reachesDevice(instance *jumperlessv5alpha1.Jumperless) bool {
+	host := instance.Spec.Host.Local
+	if host == nil || ptr.Deref(host.NodeName, "") != "" {
+		return true
+	}
+
+	if r.PortVisible != nil {
+		return r.PortVisible(host)
+	}
+
+	return jumperless.PortVisible(ptr.Deref(host.Port, ""), jumperless.PortSelector{
+		SerialNumber: ptr.Deref(host.SerialNumber, ""),
+		VID:          ptr.Deref(host.VID, ""),
+		PID:          ptr.Deref(host.PID, ""),
+	})
+}
+
+// deviceUnavailable returns whether the last reconcile couldn't find or open the device, see reconcileLocal.
+func deviceUnavailable(status *jumperlessv5alpha1.JumperlessStatus) bool {
+	ready := meta.FindStatusCondition(status.Conditions, jumperlessv5alpha1.ConditionReady)
+
+	return ready != nil && (ready.Reason == "NoJumperlessFound" || ready.Reason == "PortOpenError")
+}
+
+// releaseLease clears the lease of the device of the instance if the manager holds it, so another manager can
+// acquire it right away. Like acquiring it, releasing the lease is patched with an optimistic lock, a lease
+// changed meanwhile is left alone.
+func (r *JumperlessReconciler) releaseLease(ctx context.Context, instance *jumperlessv5alpha1.Jumperless) error {
+	current := &jumperlessv5alpha1.Jumperless{}
+	if err := r.Get(ctx, client.ObjectKeyFromObject(instance), current); err != nil {
+		if apierrors.IsNotFound(err) {
+			return nil
+		}
+
+		return fmt.Errorf("unable to get Jumperless to release its lease: %w", err)
+	}
+
+	if current.Status.HeldBy == nil || current.Status.HeldBy.HolderIdentity != r.Identity {
+		return nil
+	}
+
+	patched := current.DeepCopy()
+	patched.Status.HeldBy = nil
+	if err := r.Status().Patch(ctx, patched,
+		client.MergeFromWithOptions(current, client.MergeFromWithOptimisticLock{})); err != nil {
+		if apierrors.IsConflict(err) || apierrors.IsNotFound(err) {
+			return nil
+		}
+
+		return fmt.Errorf("unable to release lease: %w", err)
+	}
+
+	ctrl.LoggerFrom(ctx).Info("Released lease")
+
+	return nil
+}
+
+// previousHolder returns the identity of the holder of a lease, if any
+func previousHolder(lease *jumperlessv5alpha1.DeviceLease) string {
+	if lease == nil {
+		return ""
+	}
+
+	return lease.HolderIdentity
+}
@@ -0,0 +1,239 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package local
+
+import (
+	"bytes"
+	"cmp"
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	. "github.com/onsi/gomega"
+
+	jumperlessv5alpha1 "github.com/detiber/k8s-jumperless/api/v5alpha1"
+	"github.com/detiber/k8s-jumperless/jumperless"
+)
+
+// The golden tests run the parsers against the raw device outputs in testdata/fixtures/<kind>/<name>.txt and
+// compare the results with <name>.golden. New firmware output variants are added as new fixtures, either by
+// hand or recorded from a device with
+//
+//	go test ./internal/controller/local -run TestRecordFixtures -record-port /dev/ttyACM0 -record-name fw-5.3.1.0
+//
+// after which the golden files of the new fixtures are written with
+//
+//	go test ./internal/controller/local -run TestGolden -update
+var (
+	update     = flag.Bool("update", false, "write the golden files of the fixtures instead of comparing them")
+	recordPort = flag.String("record-port", "", "serial port of a Jumperless to record fixtures from")
+	recordName = flag.String("record-name", "", "name of the fixtures recorded from the device")
+	recordI2C  = flag.String("record-i2c", "", "SDA and SCL nodes of an I2C bus to scan while recording, e.g. 20,21")
+)
+
+// fixturesDir is the directory of the fixtures, relative to the package
+const fixturesDir = "testdata/fixtures"
+
+// recordADCChannels are the ADC channels read while recording fixtures
+var recordADCChannels = []int32{0, 1, 2, 3, 4} //nolint:gochecknoglobals
+
+// goldenResult is the content of a golden file, the parsed value or the error of the parser
+type goldenResult struct {
+	Result any    `json:"result,omitempty"`
+	Error  string `json:"error,omitempty"`
+}
+
+// goldenParsers parse the fixtures of a kind. The name of the fixture selects the DAC channel of dac fixtures,
+// and the firmware version of config and nets fixtures recorded from a device, e.g. fw-5.2.2.0.
+var goldenParsers = map[string]func(name, output string) (any, error){ //nolint:gochecknoglobals
+	"config": func(name, output string) (any, error) {
+		config, err := parsersFor(fixtureVersion(name)).config(output)
+
+		// The order of the sections and entries isn't meaningful
+		slices.SortFunc(config, func(a, b jumperlessv5alpha1.JumperLessConfigSection) int {
+			return cmp.Compare(a.Name, b.Name)
+		})
+		for _, section := range config {
+			slices.SortFunc(section.Entries, func(a, b jumperlessv5alpha1.JumperlessConfigEntry) int {
+				return cmp.Compare(a.Key, b.Key)
+			})
+		}
+
+		return config, err
+	},
+	"nets": func(name, output string) (any, error) {
+		return parsersFor(fixtureVersion(name)).nets(output)
+	},
+	"dac": func(name, output string) (any, error) {
+		channelName, _, _ := strings.Cut(name, "-")
+		channel, ok := jumperlessv5alpha1.ParseDACChannel(channelName)
+		if !ok {
+			return nil, fmt.Errorf("dac fixture %q must be named after its channel, e.g. TOP_RAIL-%s", name, name)
+		}
+
+		return parseDAC(channel, output)
+	},
+	"adc": func(_, output string) (any, error) {
+		return parseADC(output)
+	},
+	"uptime": func(_, output string) (any, error) {
+		uptime, err := parseUptime(output)
+		return uptime.String(), err
+	},
+	"i2cscan": func(_, output string) (any, error) {
+		addresses, err := jumperless.ParseI2CScan(output)
+
+		formatted := []string{}
+		for _, address := range addresses {
+			formatted = append(formatted, jumperless.FormatI2CAddress(address))
+		}
+
+		return formatted, err
+	},
+}
+
+// fixtureVersion returns the firmware version a fixture was recorded with, e.g. 5.2.2.0 for fw-5.2.2.0 or
+// fw-5.2.2.0-gpio, empty if the name doesn't start with one
+func fixtureVersion(name string) string {
+	version, ok := strings.CutPrefix(name, "fw-")
+	if !ok {
+		return ""
+	}
+
+	version, _, _ = strings.Cut(version, "-")
+
+	return version
+}
+
+func TestGolden(t *testing.T) {
+	kinds, err := os.ReadDir(fixturesDir)
+	if err != nil {
+		t.Fatalf("failed to read fixtures: %v", err)
+	}
+
+	for _, kind := range kinds {
+		parse, ok := goldenParsers[kind.Name()]
+		if !ok {
+			t.Errorf("no parser for the fixtures in %s", filepath.Join(fixturesDir, kind.Name()))
+			continue
+		}
+
+		fixtures, err := filepath.Glob(filepath.Join(fixturesDir, kind.Name(), "*.txt"))
+		if err != nil {
+			t.Fatalf("failed to list fixtures: %v", err)
+		}
+
+		for _, fixture := range fixtures {
+			name := strings.TrimSuffix(filepath.Base(fixture), ".txt")
+
+			t.Run(kind.Name()+"/"+name, func(t *testing.T) {
+				g := NewWithT(t)
+
+				output, err := os.ReadFile(fixture)
+				g.Expect(err).NotTo(HaveOccurred())
+
+				golden := goldenResult{}
+				result, err := parse(name, string(output))
+				if err != nil {
+					golden.Error = err.Error()
+				} else {
+					golden.Result = result
+				}
+
+				var b bytes.Buffer
+				encoder := json.NewEncoder(&b)
+				encoder.SetEscapeHTML(false)
+				encoder.SetIndent("", "  ")
+				g.Expect(encoder.Encode(golden)).To(Succeed())
+				actual := b.Bytes()
+
+				goldenFile := strings.TrimSuffix(fixture, ".txt") + ".golden"
+				if *update {
+					g.Expect(os.WriteFile(goldenFile, actual, 0o600)).To(Succeed())
+					return
+				}
+
+				expected, err := os.ReadFile(goldenFile)
+				g.Expect(err).NotTo(HaveOccurred(), "run the test with -update to write the golden file")
+				g.Expect(string(actual)).To(Equal(string(expected)))
+			})
+		}
+	}
+}
+
+// TestRecordFixtures records the outputs of a device as new fixtures, it only runs with -record-port
+func TestRecordFixtures(t *testing.T) {
+	if *recordPort == "" {
+		t.Skip("set -record-port to record fixtures from a device")
+	}
+
+	g := NewWithT(t)
+	g.Expect(*recordName).NotTo(BeEmpty(), "set -record-name to name the recorded fixtures")
+
+	j, err := jumperless.NewJumperless(context.Background(), *recordPort, 0)
+	g.Expect(err).NotTo(HaveOccurred())
+	defer func() { _ = j.ClosePort() }()
+
+	record := func(kind, name, output string) {
+		dir := filepath.Join(fixturesDir, kind)
+		g.Expect(os.MkdirAll(dir, 0o750)).To(Succeed())
+		g.Expect(os.WriteFile(filepath.Join(dir, name+".txt"), []byte(output), 0o600)).To(Succeed())
+		t.Logf("recorded %s/%s", kind, name)
+	}
+
+	output, err := j.ExecRawCommand("~", 500*time.Millisecond, jumperless.Idempotent())
+	g.Expect(err).NotTo(HaveOccurred())
+	record("config", *recordName, output)
+
+	output, err = j.ExecPythonCommand(printNetsCommand, 10*time.Millisecond, jumperless.Idempotent())
+	g.Expect(err).NotTo(HaveOccurred())
+	record("nets", *recordName, output)
+
+	for _, channel := range jumperlessv5alpha1.DACChannels {
+		output, err = j.ExecPythonCommand(dacGetCommand(channel), 10*time.Millisecond, jumperless.Idempotent(),
+			jumperless.SingleLine())
+		g.Expect(err).NotTo(HaveOccurred())
+		record("dac", channel.String()+"-"+*recordName, output)
+	}
+
+	for _, channel := range recordADCChannels {
+		output, err = j.ExecPythonCommand(fmt.Sprintf("adc_get(%d)", channel), 10*time.Millisecond,
+			jumperless.Idempotent(), jumperless.SingleLine())
+		g.Expect(err).NotTo(HaveOccurred())
+		record("adc", fmt.Sprintf("%s-%d", *recordName, channel), output)
+	}
+
+	output, err = j.ExecPythonCommand(uptimeCommand, 10*time.Millisecond, jumperless.Idempotent(),
+		jumperless.SingleLine())
+	g.Expect(err).NotTo(HaveOccurred())
+	record("uptime", *recordName, output)
+
+	if *recordI2C != "" {
+		sda, scl, _ := strings.Cut(*recordI2C, ",")
+		output, err = j.ExecPythonCommand(fmt.Sprintf("i2c_scan(%q, %q)", sda, scl), 200*time.Millisecond,
+			jumperless.Idempotent(), jumperless.SingleLine())
+		g.Expect(err).NotTo(HaveOccurred())
+		record("i2cscan", *recordName, output)
+	}
+}
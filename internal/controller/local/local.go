@@ -17,6 +17,9 @@ limitations under the License.
 package local
 
 import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
 	"errors"
 	"fmt"
 	"regexp"
@@ -30,11 +33,36 @@ import (
 
 	jumperlessv5alpha1 "github.com/detiber/k8s-jumperless/api/v5alpha1"
 	"github.com/detiber/k8s-jumperless/jumperless"
+	"github.com/detiber/k8s-jumperless/jumperless/voltage"
 )
 
 var ErrUnexpectedCommandOutput = errors.New("unexpected command output format")
 var ErrParseNetLine = errors.New("unable to parse net line")
 var ErrParseLineDuplicateIndex = errors.New("net index is not greater than previous index")
+var ErrInvalidConfigEntry = errors.New("invalid config entry")
+var ErrConfigEntryNotApplied = errors.New("config entry not applied")
+var ErrDACVoltageNotApplied = errors.New("DAC voltage not applied")
+var ErrConnectionFailed = errors.New("connection change failed")
+var ErrGPIONotApplied = errors.New("GPIO level not applied")
+
+const (
+	configSectionTopOLED     = "top_oled"
+	configSectionDisplay     = "display"
+	configSectionDACs        = "dacs"
+	configSectionCalibration = "calibration"
+	configSectionHardware    = "hardware"
+	configSectionSerial1     = "serial_1"
+)
+
+const (
+	// uartFunctionPassthrough and uartFunctionOff are the serial_1 functions used for the USB-UART passthrough
+	uartFunctionPassthrough = "passthrough"
+	uartFunctionOff         = "off"
+
+	// UARTTXNode and UARTRXNode are the names of the UART nodes as reported in the nets
+	UARTTXNode = "UART_Tx"
+	UARTRXNode = "UART_Rx"
+)
 
 var namedColors = []string{ //nolint:gochecknoglobals
 	"red",
@@ -155,7 +183,12 @@ func parseNetLine(netLine string, hasColor bool, currentIndex int32) (jumperless
 			return jumperlessv5alpha1.Net{}, fmt.Errorf("unable to find voltage in net line %s: %w", netLine, ErrParseNetLine)
 		}
 
-		net.Voltage = ptr.To(strings.TrimSpace(before) + "V") // ensure voltage is suffixed with "V"
+		netVoltage, err := voltage.Normalize(before)
+		if err != nil {
+			return jumperlessv5alpha1.Net{}, fmt.Errorf("unable to parse voltage in net line %s: %w: %w", netLine, ErrParseNetLine, err)
+		}
+
+		net.Voltage = ptr.To(netVoltage)
 
 		nodesPart = strings.TrimSpace(after)
 	} else {
@@ -219,7 +252,17 @@ func parseNetLine(netLine string, hasColor bool, currentIndex int32) (jumperless
 		nodesPart = strings.TrimSpace(before)
 
 		if found {
-			net.Data = ptr.To(strings.TrimSpace(after))
+			data := strings.TrimSpace(after)
+
+			// ADC nets report a voltage, which is normalized like the other reported voltages
+			for strings.HasPrefix(data, "\b") {
+				data = strings.TrimPrefix(data, "\b")
+			}
+			if adcVoltage, err := voltage.Normalize(data); err == nil {
+				net.Data = ptr.To(adcVoltage)
+			} else {
+				net.Data = ptr.To(strings.TrimSpace(after))
+			}
 		}
 	}
 
@@ -316,33 +359,586 @@ func parseConfig(configOutput string) ([]jumperlessv5alpha1.JumperLessConfigSect
 }
 
 func GetConfig(j *jumperless.Jumperless) ([]jumperlessv5alpha1.JumperLessConfigSection, error) {
-	configOutput, err := j.ExecRawCommand("~", 500*time.Millisecond)
+	configOutput, err := j.ExecRawCommand("~", 500*time.Millisecond, jumperless.Idempotent())
 	if err != nil {
 		return nil, fmt.Errorf("unable to get current config: %w", err)
 	}
 
-	return parseConfig(configOutput)
+	return parsersFor(j.GetVersion()).config(configOutput)
+}
+
+// ConfigSchemaHash returns a hash of the sections and keys of the config, ignoring their values and order.
+// It changes when a firmware update adds, removes or renames settings.
+func ConfigSchemaHash(config []jumperlessv5alpha1.JumperLessConfigSection) string {
+	keys := []string{}
+	for _, section := range config {
+		for _, entry := range section.Entries {
+			keys = append(keys, section.Name+"."+entry.Key)
+		}
+	}
+
+	slices.Sort(keys)
+	sum := sha256.Sum256([]byte(strings.Join(slices.Compact(keys), "\n")))
+
+	return hex.EncodeToString(sum[:8])
 }
 
+// printNetsCommand prints the nets of the breadboard
+const printNetsCommand = "print_nets()"
+
 func GetNets(j *jumperless.Jumperless) ([]jumperlessv5alpha1.Net, error) {
-	netsOutput, err := j.ExecPythonCommand("print_nets()", 10*time.Millisecond)
+	netsOutput, err := j.ExecPythonCommand(printNetsCommand, 10*time.Millisecond, jumperless.Idempotent())
 	if err != nil {
 		return nil, fmt.Errorf("unable to print nets: %w", err)
 	}
 
-	return parseNets(netsOutput)
+	return parsersFor(j.GetVersion()).nets(netsOutput)
+}
+
+// GetDAC returns the status of a DAC channel.
+func GetDAC(j *jumperless.Jumperless, channel jumperlessv5alpha1.DACChannel) (jumperlessv5alpha1.DACStatus, error) {
+	dacVoltage, err := j.ExecPythonCommand(dacGetCommand(channel), 10*time.Millisecond, jumperless.Idempotent(),
+		jumperless.SingleLine())
+	if err != nil {
+		return jumperlessv5alpha1.DACStatus{}, fmt.Errorf("unable to get DAC voltage for channel %s: %w", channel, err)
+	}
+
+	return parseDAC(channel, dacVoltage)
+}
+
+func dacGetCommand(channel jumperlessv5alpha1.DACChannel) string {
+	return fmt.Sprintf("dac_get(%d)", channel)
+}
+
+func parseDAC(channel jumperlessv5alpha1.DACChannel, dacVoltage string) (jumperlessv5alpha1.DACStatus, error) {
+	v, err := voltage.Parse(dacVoltage)
+	if err != nil {
+		return jumperlessv5alpha1.DACStatus{}, fmt.Errorf("unable to parse DAC voltage for channel %s: %w: %w", channel,
+			ErrUnexpectedCommandOutput, err)
+	}
+
+	return jumperlessv5alpha1.DACStatus{
+		Channel:    channel.String(),
+		Voltage:    voltage.Format(v),
+		Millivolts: ptr.To(voltage.Millivolts(v)),
+	}, nil
+}
+
+// uptimeCommand reads the MicroPython ticks counter
+const uptimeCommand = "__import__('time').ticks_ms()"
+
+// GetUptime returns the time since the device was last started, as reported by the MicroPython ticks counter.
+func GetUptime(j *jumperless.Jumperless) (time.Duration, error) {
+	ticksOutput, err := j.ExecPythonCommand(uptimeCommand, 10*time.Millisecond, jumperless.Idempotent(),
+		jumperless.SingleLine())
+	if err != nil {
+		return 0, fmt.Errorf("unable to get uptime: %w", err)
+	}
+
+	return parseUptime(ticksOutput)
+}
+
+func parseUptime(ticksOutput string) (time.Duration, error) {
+	ticks, err := strconv.ParseInt(strings.TrimSpace(ticksOutput), 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("%w: unable to parse uptime %q: %w", ErrUnexpectedCommandOutput, ticksOutput, err)
+	}
+
+	return time.Duration(ticks) * time.Millisecond, nil
+}
+
+// DeviceState is the state of a device read by RefreshState
+type DeviceState struct {
+	// DACS are the voltages of all DAC channels
+	DACS []jumperlessv5alpha1.DACStatus
+
+	// Nets are the nets of the breadboard
+	Nets []jumperlessv5alpha1.Net
+
+	// Uptime is the time since the device was last started, nil if the firmware doesn't expose the ticks counter
+	Uptime *time.Duration
+}
+
+// RefreshState reads the DAC voltages, nets and uptime of a device in a single batch of commands, rather than
+// one command each like GetDAC, GetNets and GetUptime.
+func RefreshState(j *jumperless.Jumperless) (*DeviceState, error) {
+	commands := []string{}
+	for _, channel := range jumperlessv5alpha1.DACChannels {
+		commands = append(commands, dacGetCommand(channel))
+	}
+	commands = append(commands, printNetsCommand, uptimeCommand)
+
+	// The uptime is read last, so older firmware failing to read it still reports the rest of the state
+	outputs, err := j.ExecPythonBatch(commands, jumperless.Idempotent())
+	if err != nil && (len(outputs) < len(commands)-1 || !errors.Is(err, jumperless.ErrPythonException)) {
+		return nil, fmt.Errorf("unable to refresh device state: %w", err)
+	}
+
+	state := &DeviceState{}
+	for i, channel := range jumperlessv5alpha1.DACChannels {
+		dac, err := parseDAC(channel, outputs[i])
+		if err != nil {
+			return nil, err
+		}
+
+		state.DACS = append(state.DACS, dac)
+	}
+
+	nets, err := parsersFor(j.GetVersion()).nets(outputs[len(jumperlessv5alpha1.DACChannels)])
+	if err != nil {
+		return nil, err
+	}
+	state.Nets = nets
+
+	if len(outputs) == len(commands) {
+		uptime, err := parseUptime(outputs[len(commands)-1])
+		if err != nil {
+			return nil, err
+		}
+		state.Uptime = &uptime
+	}
+
+	return state, nil
+}
+
+// GetDeviceIdentity returns the identity of the device, using the hardware section of the config
+// previously read into status.
+func GetDeviceIdentity(j *jumperless.Jumperless, status *jumperlessv5alpha1.JumperlessStatus) *jumperlessv5alpha1.DeviceIdentity {
+	identity := &jumperlessv5alpha1.DeviceIdentity{}
+
+	if serialNumber := j.GetSerialNumber(); serialNumber != "" {
+		identity.SerialNumber = ptr.To(serialNumber)
+	}
+	if generation, ok := status.GetConfigEntry(configSectionHardware, "generation"); ok {
+		identity.Generation = ptr.To(generation)
+	}
+	if revision, ok := status.GetConfigEntry(configSectionHardware, "revision"); ok {
+		identity.Revision = ptr.To(revision)
+	}
+	if probeRevision, ok := status.GetConfigEntry(configSectionHardware, "probe_revision"); ok {
+		identity.ProbeRevision = ptr.To(probeRevision)
+	}
+
+	return identity
+}
+
+// SetConfigEntry writes a single configuration entry to the device using the config line format
+// the device prints in its config dump, e.g. "`[top_oled] font = jokerman;".
+func SetConfigEntry(j *jumperless.Jumperless, section, key, value string) error {
+	if err := validateConfigEntry(section, key, value); err != nil {
+		return err
+	}
+
+	command := fmt.Sprintf("`[%s] %s = %s;", section, key, value)
+	output, err := j.ExecRawCommand(command, 100*time.Millisecond, jumperless.Idempotent())
+	if err != nil {
+		return fmt.Errorf("unable to set config %s.%s: %w", section, key, err)
+	}
+
+	return parseSetConfigEntryOutput(parsersFor(j.GetVersion()), output, section, key, value)
+}
+
+// validateConfigEntry rejects entries that can't be written as a single config line, since the device would
+// otherwise parse them as several lines or a different entry.
+func validateConfigEntry(section, key, value string) error {
+	if section == "" || strings.ContainsAny(section, "`[]=; \r\n") {
+		return fmt.Errorf("%w: section %q", ErrInvalidConfigEntry, section)
+	}
+	if key == "" || strings.ContainsAny(key, "`[]=; \r\n") {
+		return fmt.Errorf("%w: key %q in section %s", ErrInvalidConfigEntry, key, section)
+	}
+	if strings.ContainsAny(value, ";\r\n") {
+		return fmt.Errorf("%w: value %q for %s.%s", ErrInvalidConfigEntry, value, section, key)
+	}
+
+	return nil
+}
+
+// parseSetConfigEntryOutput checks the output of writing a config entry. The device may echo the updated
+// config lines, in which case the entry has to have the written value.
+func parseSetConfigEntryOutput(p parsers, output, section, key, value string) error {
+	config, err := p.config(output)
+	if err != nil {
+		return fmt.Errorf("unable to parse output of setting config %s.%s: %w", section, key, err)
+	}
+
+	for _, s := range config {
+		if s.Name != section {
+			continue
+		}
+		for _, entry := range s.Entries {
+			if entry.Key == key && entry.Value != value {
+				return fmt.Errorf("%w: %s.%s is %q instead of %q", ErrConfigEntryNotApplied, section, key, entry.Value, value)
+			}
+		}
+	}
+
+	return nil
+}
+
+// SetDAC sets the voltage of a DAC channel, optionally saving it to the device config so it persists
+// across power cycles.
+func SetDAC(j *jumperless.Jumperless, channel jumperlessv5alpha1.DACChannel, volts float64, save bool) error {
+	saveArg := "False"
+	if save {
+		saveArg = "True"
+	}
+
+	command := fmt.Sprintf("dac_set(%d, %s, %s)", channel, voltage.FormatValue(volts), saveArg)
+	output, err := j.ExecPythonCommand(command, 10*time.Millisecond, jumperless.Idempotent(),
+		jumperless.OptionalOutput())
+	if err != nil {
+		return fmt.Errorf("unable to set DAC voltage for channel %s: %w", channel, err)
+	}
+
+	if err := parseSetDACOutput(output, volts); err != nil {
+		return fmt.Errorf("unable to set DAC voltage for channel %s: %w", channel, err)
+	}
+
+	return nil
+}
+
+// parseSetDACOutput checks the output of dac_set, which is either empty or the voltage the DAC was set to.
+func parseSetDACOutput(output string, volts float64) error {
+	output = strings.TrimSpace(output)
+	if output == "" || output == "None" {
+		return nil
+	}
+
+	actual, err := voltage.Parse(output)
+	if err != nil {
+		return fmt.Errorf("%w: %q: %w", ErrUnexpectedCommandOutput, output, err)
+	}
+
+	if !voltage.Equal(actual, volts) {
+		return fmt.Errorf("%w: device reported %s instead of %s", ErrDACVoltageNotApplied,
+			voltage.Format(actual), voltage.Format(volts))
+	}
+
+	return nil
+}
+
+// GetGPIO returns true if the GPIO pin is high.
+func GetGPIO(j *jumperless.Jumperless, pin int32) (bool, error) {
+	output, err := j.ExecPythonCommand(fmt.Sprintf("gpio_get(%d)", pin), 10*time.Millisecond,
+		jumperless.Idempotent(), jumperless.SingleLine())
+	if err != nil {
+		return false, fmt.Errorf("unable to get GPIO %d: %w", pin, err)
+	}
+
+	high, err := parseGPIOLevel(output)
+	if err != nil {
+		return false, fmt.Errorf("unable to get GPIO %d: %w", pin, err)
+	}
+
+	return high, nil
 }
 
-func GetDAC(j *jumperless.Jumperless, channel jumperlessv5alpha1.DACChannel) (string, error) {
-	dacVoltage, err := j.ExecPythonCommand(fmt.Sprintf("dac_get(%d)", channel), 10*time.Millisecond)
+// SetGPIO drives the GPIO pin high or low.
+func SetGPIO(j *jumperless.Jumperless, pin int32, high bool) error {
+	level := "False"
+	if high {
+		level = "True"
+	}
+
+	command := fmt.Sprintf("gpio_set(%d, %s)", pin, level)
+	output, err := j.ExecPythonCommand(command, 10*time.Millisecond, jumperless.Idempotent(),
+		jumperless.OptionalOutput())
+	if err != nil {
+		return fmt.Errorf("unable to set GPIO %d: %w", pin, err)
+	}
+
+	if err := parseSetGPIOOutput(output, high); err != nil {
+		return fmt.Errorf("unable to set GPIO %d: %w", pin, err)
+	}
+
+	return nil
+}
+
+// parseGPIOLevel parses the level returned by gpio_get, true if the pin is high.
+func parseGPIOLevel(output string) (bool, error) {
+	switch output = strings.TrimSpace(output); output {
+	case "True", "1", "HIGH":
+		return true, nil
+	case "False", "0", "LOW":
+		return false, nil
+	default:
+		return false, fmt.Errorf("%w: %q", ErrUnexpectedCommandOutput, output)
+	}
+}
+
+// parseSetGPIOOutput checks the output of gpio_set, which is either empty or the level the pin was set to.
+func parseSetGPIOOutput(output string, high bool) error {
+	output = strings.TrimSpace(output)
+	if output == "" || output == "None" {
+		return nil
+	}
+
+	actual, err := parseGPIOLevel(output)
+	if err != nil {
+		return err
+	}
+
+	if actual != high {
+		return fmt.Errorf("%w: device reported %s", ErrGPIONotApplied, output)
+	}
+
+	return nil
+}
+
+// GetADC returns the voltage measured on an ADC channel.
+func GetADC(j *jumperless.Jumperless, channel int32) (float64, error) {
+	output, err := j.ExecPythonCommand(fmt.Sprintf("adc_get(%d)", channel), 10*time.Millisecond,
+		jumperless.Idempotent(), jumperless.SingleLine())
 	if err != nil {
-		return "", fmt.Errorf("unable to get DAC voltage for channel %s: %w", channel, err)
+		return 0, fmt.Errorf("unable to get ADC %d: %w", channel, err)
 	}
 
-	result := strings.TrimSpace(dacVoltage)
-	if !strings.HasSuffix(result, "V") {
-		result += "V" // Ensure result is suffixed with "V"
+	v, err := parseADC(output)
+	if err != nil {
+		return 0, fmt.Errorf("unable to get ADC %d: %w", channel, err)
+	}
+
+	return v, nil
+}
+
+// parseADC parses the voltage returned by adc_get.
+func parseADC(output string) (float64, error) {
+	v, err := voltage.Parse(output)
+	if err != nil {
+		return 0, fmt.Errorf("%w: %w", ErrUnexpectedCommandOutput, err)
+	}
+
+	return v, nil
+}
+
+// SampleADC reads an ADC channel the given number of times, waiting for the interval between two reads.
+func SampleADC(ctx context.Context, j *jumperless.Jumperless, channel, samples int32,
+	interval time.Duration) ([]float64, error) {
+	values := make([]float64, 0, samples)
+	for i := range samples {
+		if i > 0 {
+			select {
+			case <-ctx.Done():
+				return nil, fmt.Errorf("unable to sample ADC %d: %w", channel, ctx.Err())
+			case <-time.After(interval):
+			}
+		}
+
+		v, err := GetADC(j, channel)
+		if err != nil {
+			return nil, err
+		}
+
+		values = append(values, v)
+	}
+
+	return values, nil
+}
+
+// SummarizeSamples returns the lowest, highest and mean of the sampled voltages, zero if there are no samples.
+func SummarizeSamples(samples []float64) (lowest, highest, mean float64) {
+	if len(samples) == 0 {
+		return 0, 0, 0
+	}
+
+	lowest, highest = slices.Min(samples), slices.Max(samples)
+
+	var sum float64
+	for _, v := range samples {
+		sum += v
+	}
+
+	return lowest, highest, sum / float64(len(samples))
+}
+
+// SetDisplayText shows the given text on the top OLED display.
+func SetDisplayText(j *jumperless.Jumperless, text string) error {
+	command := fmt.Sprintf("oled_print(%s)", strconv.Quote(text))
+	if _, err := j.ExecPythonCommand(command, 10*time.Millisecond, jumperless.Idempotent()); err != nil {
+		return fmt.Errorf("unable to set display text: %w", err)
+	}
+
+	return nil
+}
+
+// DesiredConfig returns the configuration entries derived from the display and probe settings in the spec.
+func DesiredConfig(spec jumperlessv5alpha1.JumperlessSpec) []jumperlessv5alpha1.JumperLessConfigSection {
+	desired := jumperlessv5alpha1.JumperlessStatus{}
+
+	if display := spec.Display; display != nil {
+		if display.Enabled != nil {
+			desired.SetConfigEntry(configSectionTopOLED, "enabled", strconv.FormatBool(*display.Enabled))
+		}
+		if display.Font != nil {
+			desired.SetConfigEntry(configSectionTopOLED, "font", *display.Font)
+		}
+		if display.Brightness != nil {
+			desired.SetConfigEntry(configSectionDisplay, "led_brightness", strconv.FormatInt(int64(*display.Brightness), 10))
+		}
+	}
+
+	if probe := spec.Probe; probe != nil {
+		if probe.PowerDAC != nil {
+			powerDAC := "0"
+			if *probe.PowerDAC == jumperlessv5alpha1.DAC1.String() {
+				powerDAC = "1"
+			}
+			desired.SetConfigEntry(configSectionDACs, "probe_power_dac", powerDAC)
+		}
+		// The threshold is validated by the CRD, an unparsable value is left unset rather than written to the device
+		if probe.SwitchThreshold != nil {
+			if threshold, err := voltage.Parse(*probe.SwitchThreshold); err == nil {
+				desired.SetConfigEntry(configSectionCalibration, "probe_switch_threshold", voltage.FormatValue(threshold))
+			}
+		}
+	}
+
+	if bridge := spec.UARTBridge; bridge != nil {
+		if bridge.Enabled != nil {
+			function := uartFunctionOff
+			if *bridge.Enabled {
+				function = uartFunctionPassthrough
+			}
+			desired.SetConfigEntry(configSectionSerial1, "function", function)
+		}
+		if bridge.BaudRate != nil {
+			desired.SetConfigEntry(configSectionSerial1, "baud_rate", strconv.FormatInt(int64(*bridge.BaudRate), 10))
+		}
+	}
+
+	return desired.Config
+}
+
+// FactoryConfig returns the rail, DAC and display settings of the firmware default config, identifying a
+// factory-fresh device.
+func FactoryConfig() []jumperlessv5alpha1.JumperLessConfigSection {
+	factory := jumperlessv5alpha1.JumperlessStatus{}
+	factory.SetConfigEntry(configSectionDACs, "top_rail", "0.00")
+	factory.SetConfigEntry(configSectionDACs, "bottom_rail", "0.00")
+	factory.SetConfigEntry(configSectionDACs, "dac_0", "3.33")
+	factory.SetConfigEntry(configSectionDACs, "dac_1", "0.00")
+	factory.SetConfigEntry(configSectionDisplay, "led_brightness", "10")
+	factory.SetConfigEntry(configSectionDisplay, "rail_brightness", "55")
+	factory.SetConfigEntry(configSectionTopOLED, "font", "jokerman")
+
+	return factory.Config
+}
+
+// IsFactoryFresh returns true if the config read from a device matches every entry of the factory config.
+func IsFactoryFresh(config, factory []jumperlessv5alpha1.JumperLessConfigSection) bool {
+	current := jumperlessv5alpha1.JumperlessStatus{Config: config}
+
+	for _, section := range factory {
+		for _, entry := range section.Entries {
+			if value, ok := current.GetConfigEntry(section.Name, entry.Key); !ok || value != entry.Value {
+				return false
+			}
+		}
+	}
+
+	return true
+}
+
+// Connect connects two nodes on the breadboard, e.g. "UART_Tx" and "D1".
+func Connect(j *jumperless.Jumperless, a, b string) error {
+	command := fmt.Sprintf("connect(%s, %s)", strconv.Quote(a), strconv.Quote(b))
+	output, err := j.ExecPythonCommand(command, 10*time.Millisecond, jumperless.Idempotent(),
+		jumperless.OptionalOutput())
+	if err != nil {
+		return fmt.Errorf("unable to connect %s to %s: %w", a, b, err)
+	}
+
+	if err := parseConnectionOutput(output); err != nil {
+		return fmt.Errorf("unable to connect %s to %s: %w", a, b, err)
+	}
+
+	return nil
+}
+
+// Disconnect removes the connection between two nodes on the breadboard.
+func Disconnect(j *jumperless.Jumperless, a, b string) error {
+	command := fmt.Sprintf("disconnect(%s, %s)", strconv.Quote(a), strconv.Quote(b))
+	output, err := j.ExecPythonCommand(command, 10*time.Millisecond, jumperless.Idempotent(),
+		jumperless.OptionalOutput())
+	if err != nil {
+		return fmt.Errorf("unable to disconnect %s from %s: %w", a, b, err)
+	}
+
+	if err := parseConnectionOutput(output); err != nil {
+		return fmt.Errorf("unable to disconnect %s from %s: %w", a, b, err)
+	}
+
+	return nil
+}
+
+// parseConnectionOutput checks the output of connect and disconnect, which return False when the change
+// could not be made, e.g. because no path is left to route the connection.
+func parseConnectionOutput(output string) error {
+	switch output = strings.TrimSpace(output); output {
+	case "", "None", "True":
+		return nil
+	case "False":
+		return ErrConnectionFailed
+	default:
+		return fmt.Errorf("%w: %q", ErrUnexpectedCommandOutput, output)
+	}
+}
+
+// CountConnectedNets returns the number of nets connecting two or more nodes.
+func CountConnectedNets(nets []jumperlessv5alpha1.Net) int32 {
+	count := int32(0)
+	for _, net := range nets {
+		if len(net.Nodes) > 1 {
+			count++
+		}
+	}
+
+	return count
+}
+
+// ConnectedNodes returns the other nodes in the net containing the given node, node names are compared
+// case-insensitively since the firmware reports some nodes in mixed case.
+func ConnectedNodes(nets []jumperlessv5alpha1.Net, node string) []string {
+	for _, net := range nets {
+		if !slices.ContainsFunc(net.Nodes, func(n string) bool { return strings.EqualFold(n, node) }) {
+			continue
+		}
+
+		connected := []string{}
+		for _, n := range net.Nodes {
+			if !strings.EqualFold(n, node) {
+				connected = append(connected, n)
+			}
+		}
+
+		return connected
+	}
+
+	return nil
+}
+
+// IsConnected returns whether two nodes are in the same net.
+func IsConnected(nets []jumperlessv5alpha1.Net, a, b string) bool {
+	return slices.ContainsFunc(ConnectedNodes(nets, a), func(n string) bool { return strings.EqualFold(n, b) })
+}
+
+// GetUARTBridgeStatus returns the state of the USB-UART passthrough, using the serial_1 section of the config
+// and the nets previously read into status.
+func GetUARTBridgeStatus(status *jumperlessv5alpha1.JumperlessStatus) *jumperlessv5alpha1.UARTBridgeStatus {
+	bridge := &jumperlessv5alpha1.UARTBridgeStatus{
+		TXNodes: ConnectedNodes(status.Nets, UARTTXNode),
+		RXNodes: ConnectedNodes(status.Nets, UARTRXNode),
+	}
+
+	if function, ok := status.GetConfigEntry(configSectionSerial1, "function"); ok {
+		bridge.Enabled = function == uartFunctionPassthrough
+	}
+	if baudRate, ok := status.GetConfigEntry(configSectionSerial1, "baud_rate"); ok {
+		if parsed, err := strconv.ParseInt(baudRate, 10, 32); err == nil {
+			bridge.BaudRate = ptr.To(int32(parsed))
+		}
 	}
 
-	return result, nil
+	return bridge
 }
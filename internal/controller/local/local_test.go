@@ -0,0 +1,324 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package local
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	. "github.com/onsi/gomega"
+
+	jumperlessv5alpha1 "github.com/detiber/k8s-jumperless/api/v5alpha1"
+	"github.com/detiber/k8s-jumperless/jumperless"
+	"github.com/detiber/k8s-jumperless/jumperless/serialmock"
+)
+
+func TestValidateConfigEntry(t *testing.T) {
+	tests := []struct {
+		name    string
+		section string
+		key     string
+		value   string
+		err     error
+	}{
+		{name: "valid", section: "top_oled", key: "font", value: "jokerman"},
+		{name: "empty value", section: "display", key: "dump_leds", value: ""},
+		{name: "value with spaces", section: "top_oled", key: "text", value: "hello world"},
+		{name: "empty section", section: "", key: "font", value: "jokerman", err: ErrInvalidConfigEntry},
+		{name: "section with bracket", section: "top]oled", key: "font", value: "jokerman", err: ErrInvalidConfigEntry},
+		{name: "empty key", section: "top_oled", key: "", value: "jokerman", err: ErrInvalidConfigEntry},
+		{name: "key with equals", section: "top_oled", key: "font=", value: "jokerman", err: ErrInvalidConfigEntry},
+		{name: "value with semicolon", section: "top_oled", key: "font", value: "a;b", err: ErrInvalidConfigEntry},
+		{name: "value with newline", section: "top_oled", key: "font", value: "a\nb", err: ErrInvalidConfigEntry},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			err := validateConfigEntry(tt.section, tt.key, tt.value)
+			if tt.err != nil {
+				g.Expect(errors.Is(err, tt.err)).To(BeTrue())
+				return
+			}
+
+			g.Expect(err).NotTo(HaveOccurred())
+		})
+	}
+}
+
+func TestParseSetConfigEntryOutput(t *testing.T) {
+	tests := []struct {
+		name   string
+		output string
+		err    error
+	}{
+		{name: "no output", output: ""},
+		{name: "unrelated output", output: "\r\nmain menu\r\n"},
+		{name: "echoed entry", output: "`[top_oled] font = jokerman;\r\n"},
+		{name: "echoed other entries", output: "`[top_oled] enabled = true;\r\n`[display] font = other;\r\n"},
+		{name: "echoed different value", output: "`[top_oled] font = eurostile;\r\n", err: ErrConfigEntryNotApplied},
+		{name: "malformed config line", output: "`[top_oled font = jokerman;\r\n", err: ErrParseNetLine},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			err := parseSetConfigEntryOutput(defaultParsers, tt.output, "top_oled", "font", "jokerman")
+			if tt.err != nil {
+				g.Expect(errors.Is(err, tt.err)).To(BeTrue())
+				return
+			}
+
+			g.Expect(err).NotTo(HaveOccurred())
+		})
+	}
+}
+
+func TestParseSetDACOutput(t *testing.T) {
+	tests := []struct {
+		name   string
+		output string
+		volts  float64
+		err    error
+	}{
+		{name: "no output", output: "", volts: 3.3},
+		{name: "None", output: "None", volts: 3.3},
+		{name: "same voltage", output: "3.30", volts: 3.3},
+		{name: "same voltage with unit", output: "3.3 V\r\n", volts: 3.3},
+		{name: "rounded voltage", output: "1.01V", volts: 1.006},
+		{name: "negative voltage", output: "-2.50V", volts: -2.5},
+		{name: "clamped voltage", output: "8.00V", volts: 9, err: ErrDACVoltageNotApplied},
+		{name: "different voltage", output: "0.00V", volts: 3.3, err: ErrDACVoltageNotApplied},
+		{name: "unexpected output", output: "Invalid channel", volts: 3.3, err: ErrUnexpectedCommandOutput},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			err := parseSetDACOutput(tt.output, tt.volts)
+			if tt.err != nil {
+				g.Expect(errors.Is(err, tt.err)).To(BeTrue())
+				return
+			}
+
+			g.Expect(err).NotTo(HaveOccurred())
+		})
+	}
+}
+
+func TestParseSetGPIOOutput(t *testing.T) {
+	tests := []struct {
+		name   string
+		output string
+		high   bool
+		err    error
+	}{
+		{name: "no output", output: "", high: true},
+		{name: "None", output: "None", high: false},
+		{name: "high", output: "True\r\n", high: true},
+		{name: "low", output: "False", high: false},
+		{name: "numeric level", output: "1", high: true},
+		{name: "different level", output: "False", high: true, err: ErrGPIONotApplied},
+		{name: "unexpected output", output: "Invalid pin", high: true, err: ErrUnexpectedCommandOutput},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			err := parseSetGPIOOutput(tt.output, tt.high)
+			if tt.err != nil {
+				g.Expect(errors.Is(err, tt.err)).To(BeTrue())
+				return
+			}
+
+			g.Expect(err).NotTo(HaveOccurred())
+		})
+	}
+}
+
+func TestParseADC(t *testing.T) {
+	tests := []struct {
+		output string
+		want   float64
+		err    error
+	}{
+		{output: "3.30", want: 3.3},
+		{output: "-2.78 V\r\n", want: -2.78},
+		{output: "0.00", want: 0},
+		{output: "Invalid channel", err: ErrUnexpectedCommandOutput},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.output, func(t *testing.T) {
+			g := NewWithT(t)
+
+			got, err := parseADC(tt.output)
+			if tt.err != nil {
+				g.Expect(errors.Is(err, tt.err)).To(BeTrue())
+				return
+			}
+
+			g.Expect(err).NotTo(HaveOccurred())
+			g.Expect(got).To(BeNumerically("~", tt.want, 1e-9))
+		})
+	}
+}
+
+func TestSummarizeSamples(t *testing.T) {
+	g := NewWithT(t)
+
+	lowest, highest, mean := SummarizeSamples([]float64{3.3, 3.28, 3.32, 3.3})
+	g.Expect(lowest).To(BeNumerically("~", 3.28, 1e-9))
+	g.Expect(highest).To(BeNumerically("~", 3.32, 1e-9))
+	g.Expect(mean).To(BeNumerically("~", 3.3, 1e-9))
+
+	lowest, highest, mean = SummarizeSamples(nil)
+	g.Expect([]float64{lowest, highest, mean}).To(Equal([]float64{0, 0, 0}))
+}
+
+func TestParseConnectionOutput(t *testing.T) {
+	tests := []struct {
+		output string
+		err    error
+	}{
+		{output: ""},
+		{output: "None"},
+		{output: "True"},
+		{output: "True\r\n"},
+		{output: "False", err: ErrConnectionFailed},
+		{output: "0.00V", err: ErrUnexpectedCommandOutput},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.output, func(t *testing.T) {
+			g := NewWithT(t)
+
+			err := parseConnectionOutput(tt.output)
+			if tt.err != nil {
+				g.Expect(errors.Is(err, tt.err)).To(BeTrue())
+				return
+			}
+
+			g.Expect(err).NotTo(HaveOccurred())
+		})
+	}
+}
+
+func TestWritesWithoutOutput(t *testing.T) {
+	// Firmware whose writes return None prints nothing but the echoed command, the writes must not wait for a
+	// line of output that never comes
+	tests := []struct {
+		command string
+		write   func(j *jumperless.Jumperless) error
+	}{
+		{
+			command: ">dac_set(0, 3.30, False)",
+			write: func(j *jumperless.Jumperless) error {
+				return SetDAC(j, jumperlessv5alpha1.DAC0, 3.3, false)
+			},
+		},
+		{
+			command: ">gpio_set(1, True)",
+			write:   func(j *jumperless.Jumperless) error { return SetGPIO(j, 1, true) },
+		},
+		{
+			command: `>connect("TOP_RAIL", "5")`,
+			write:   func(j *jumperless.Jumperless) error { return Connect(j, "TOP_RAIL", "5") },
+		},
+		{
+			command: `>disconnect("TOP_RAIL", "5")`,
+			write:   func(j *jumperless.Jumperless) error { return Disconnect(j, "TOP_RAIL", "5") },
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.command, func(t *testing.T) {
+			g := NewWithT(t)
+
+			port := serialmock.New()
+			port.Expect("?").Respond("Jumperless firmware version: 5.3.1.0\r\n")
+
+			j, err := jumperless.NewJumperlessWithOpener("/dev/ttyMock", 0, port.Open)
+			g.Expect(err).NotTo(HaveOccurred())
+			g.Expect(j.OpenPort()).To(Succeed())
+			t.Cleanup(func() { _ = j.ClosePort() })
+
+			port.Expect(tt.command).Respond("Python> " + tt.command + "\r\n")
+
+			start := time.Now()
+			g.Expect(tt.write(j)).To(Succeed())
+			g.Expect(time.Since(start)).To(BeNumerically("<", time.Second))
+			g.Expect(port.ExpectationsMet()).To(Succeed())
+		})
+	}
+}
+
+func TestCountConnectedNets(t *testing.T) {
+	g := NewWithT(t)
+
+	nets := []jumperlessv5alpha1.Net{
+		{Index: 1, Name: "GND", Nodes: []string{"GND", "9"}},
+		{Index: 2, Name: "Top Rail", Nodes: []string{"TOP_R"}},
+		{Index: 6, Name: "Net 6", Nodes: []string{"UART_Rx", "D1"}},
+		{Index: 7, Name: "Net 7", Nodes: []string{"ADC_3", "20", "21"}},
+	}
+
+	g.Expect(CountConnectedNets(nets)).To(BeEquivalentTo(3))
+	g.Expect(CountConnectedNets(nil)).To(BeZero())
+}
+
+func TestIsFactoryFresh(t *testing.T) {
+	fresh := "`[dacs] top_rail = 0.00;\n`[dacs] bottom_rail = 0.00;\n`[dacs] dac_0 = 3.33;\n`[dacs] dac_1 = 0.00;\n" +
+		"`[display] led_brightness = 10;\n`[display] rail_brightness = 55;\n`[top_oled] font = jokerman;\n" +
+		"`[top_oled] enabled = true;\n"
+
+	tests := []struct {
+		name    string
+		config  string
+		factory string
+		want    bool
+	}{
+		{name: "factory config", config: fresh, want: true},
+		{name: "changed rail", config: strings.Replace(fresh, "top_rail = 0.00", "top_rail = 5.00", 1)},
+		{name: "missing entry", config: strings.Replace(fresh, "`[top_oled] font = jokerman;\n", "", 1)},
+		{name: "custom factory config", config: fresh, factory: "`[top_oled] enabled = true;\n", want: true},
+		{name: "custom factory config mismatch", config: fresh, factory: "`[top_oled] enabled = false;\n"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			config, err := parseConfig(tt.config)
+			g.Expect(err).NotTo(HaveOccurred())
+
+			factory := FactoryConfig()
+			if tt.factory != "" {
+				factory, err = parseConfig(tt.factory)
+				g.Expect(err).NotTo(HaveOccurred())
+			}
+
+			g.Expect(IsFactoryFresh(config, factory)).To(Equal(tt.want))
+		})
+	}
+}
@@ -0,0 +1,88 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package local
+
+import (
+	jumperlessv5alpha1 "github.com/detiber/k8s-jumperless/api/v5alpha1"
+	"github.com/detiber/k8s-jumperless/jumperless"
+)
+
+// parsers parse the outputs whose format depends on the firmware version
+type parsers struct {
+	// name identifies the parsers in tests
+	name string
+
+	// nets parses the output of print_nets()
+	nets func(output string) ([]jumperlessv5alpha1.Net, error)
+
+	// config parses the output of ~ and the config lines echoed when writing a config entry
+	config func(output string) ([]jumperlessv5alpha1.JumperLessConfigSection, error)
+}
+
+// parserStrategy selects parsers for the firmware versions from minVersion up to, but excluding, maxVersion.
+// An empty bound is open.
+type parserStrategy struct {
+	minVersion string
+	maxVersion string
+	parsers    parsers
+}
+
+// defaultParsers handle the outputs of all firmware versions known so far, and of devices whose version
+// couldn't be parsed
+var defaultParsers = parsers{name: "default", nets: parseNets, config: parseConfig} //nolint:gochecknoglobals
+
+// parserStrategies are the parsers of firmware versions formatting their output differently than the default
+// parsers expect. Support for a new output format is added as a strategy limited to the firmware versions
+// printing it, so the parsing of older versions is left as it is; the golden fixtures of every version make
+// sure of that. Strategies must not overlap.
+var parserStrategies = []parserStrategy{} //nolint:gochecknoglobals
+
+// parsersFor returns the parsers of the strategy matching the firmware version, or the default parsers if no
+// strategy matches or the version can't be parsed
+func parsersFor(version string) parsers {
+	v, err := jumperless.ParseFirmwareVersion(version)
+	if err != nil {
+		return defaultParsers
+	}
+
+	for _, strategy := range parserStrategies {
+		if strategy.matches(v) {
+			return strategy.parsers
+		}
+	}
+
+	return defaultParsers
+}
+
+// matches reports whether the firmware version is in the range of the strategy
+func (s parserStrategy) matches(version jumperless.FirmwareVersion) bool {
+	if s.minVersion != "" {
+		minVersion, err := jumperless.ParseFirmwareVersion(s.minVersion)
+		if err != nil || version.Compare(minVersion) < 0 {
+			return false
+		}
+	}
+
+	if s.maxVersion != "" {
+		maxVersion, err := jumperless.ParseFirmwareVersion(s.maxVersion)
+		if err != nil || version.Compare(maxVersion) >= 0 {
+			return false
+		}
+	}
+
+	return true
+}
@@ -0,0 +1,88 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package local
+
+import (
+	"testing"
+
+	. "github.com/onsi/gomega"
+
+	jumperlessv5alpha1 "github.com/detiber/k8s-jumperless/api/v5alpha1"
+	"github.com/detiber/k8s-jumperless/jumperless"
+)
+
+func TestParsersFor(t *testing.T) {
+	g := NewWithT(t)
+
+	noNets := func(string) ([]jumperlessv5alpha1.Net, error) { return nil, nil }
+	legacy := parsers{name: "legacy", nets: noNets, config: parseConfig}
+	next := parsers{name: "next", nets: noNets, config: parseConfig}
+
+	original := parserStrategies
+	t.Cleanup(func() { parserStrategies = original })
+	parserStrategies = []parserStrategy{
+		{maxVersion: "5.2", parsers: legacy},
+		{minVersion: "5.4.1", parsers: next},
+	}
+
+	tests := map[string]string{
+		"5.1.9.9":      "legacy",
+		"5.2.0.0":      "default",
+		"5.3.1.0":      "default",
+		"5.4.1.0":      "next",
+		"v6.0-beta":    "next",
+		"":             "default",
+		"experimental": "default",
+	}
+
+	for version, name := range tests {
+		g.Expect(parsersFor(version).name).To(Equal(name), version)
+	}
+}
+
+func TestParserStrategiesDontOverlap(t *testing.T) {
+	g := NewWithT(t)
+
+	bound := func(version string) jumperless.FirmwareVersion {
+		if version == "" {
+			return nil
+		}
+
+		v, err := jumperless.ParseFirmwareVersion(version)
+		g.Expect(err).NotTo(HaveOccurred())
+
+		return v
+	}
+
+	for i, strategy := range parserStrategies {
+		minVersion, maxVersion := bound(strategy.minVersion), bound(strategy.maxVersion)
+		if minVersion != nil && maxVersion != nil {
+			g.Expect(minVersion.Compare(maxVersion)).To(BeNumerically("<", 0), strategy.parsers.name)
+		}
+
+		for _, other := range parserStrategies[i+1:] {
+			// Two ranges overlap if each starts before the other ends
+			startsBefore := func(a, b parserStrategy) bool {
+				return a.minVersion == "" || b.maxVersion == "" ||
+					bound(a.minVersion).Compare(bound(b.maxVersion)) < 0
+			}
+
+			g.Expect(startsBefore(strategy, other) && startsBefore(other, strategy)).To(BeFalse(),
+				"%s overlaps %s", strategy.parsers.name, other.parsers.name)
+		}
+	}
+}
@@ -0,0 +1,124 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	ctrl "sigs.k8s.io/controller-runtime"
+
+	jumperlessv5alpha1 "github.com/detiber/k8s-jumperless/api/v5alpha1"
+	"github.com/detiber/k8s-jumperless/internal/controller/local"
+	"github.com/detiber/k8s-jumperless/jumperless"
+	"github.com/detiber/k8s-jumperless/jumperless/voltage"
+)
+
+// defaultMeasurementSamples is the number of samples of a measurement that doesn't set it.
+const defaultMeasurementSamples = 10
+
+// defaultMeasurementInterval is the time between two samples of a measurement that doesn't set it.
+const defaultMeasurementInterval = 100 * time.Millisecond
+
+// measurementSamples returns the number of samples to take for the measurement.
+func measurementSamples(measurement jumperlessv5alpha1.Measurement) int32 {
+	if measurement.Samples <= 0 {
+		return defaultMeasurementSamples
+	}
+
+	return measurement.Samples
+}
+
+// measurementInterval returns the time between two samples of the measurement.
+func measurementInterval(measurement jumperlessv5alpha1.Measurement) time.Duration {
+	if measurement.Interval == nil {
+		return defaultMeasurementInterval
+	}
+
+	return measurement.Interval.Duration
+}
+
+// measurementCurrent returns whether the result was taken for the measurement as it is in the spec, and for the
+// current value of the MeasureAnnotation.
+func measurementCurrent(measurement jumperlessv5alpha1.Measurement, result jumperlessv5alpha1.MeasurementResult,
+	trigger string) bool {
+	return result.Channel == measurement.Channel &&
+		len(result.Samples) == int(measurementSamples(measurement)) &&
+		result.Interval.Duration == measurementInterval(measurement) &&
+		result.Trigger == trigger
+}
+
+// newMeasurementResult records the samples taken for a measurement along with their statistics.
+func newMeasurementResult(measurement jumperlessv5alpha1.Measurement, trigger string, samples []float64,
+	now time.Time) jumperlessv5alpha1.MeasurementResult {
+	lowest, highest, mean := local.SummarizeSamples(samples)
+
+	result := jumperlessv5alpha1.MeasurementResult{
+		Name:           measurement.Name,
+		Channel:        measurement.Channel,
+		Interval:       metav1.Duration{Duration: measurementInterval(measurement)},
+		Samples:        make([]string, 0, len(samples)),
+		Min:            voltage.Format(lowest),
+		Max:            voltage.Format(highest),
+		Average:        voltage.Format(mean),
+		Trigger:        trigger,
+		CompletionTime: metav1.NewTime(now),
+	}
+
+	for _, sample := range samples {
+		result.Samples = append(result.Samples, voltage.Format(sample))
+	}
+
+	return result
+}
+
+// takeMeasurements samples the ADC channels of the measurements in the spec that have no current result, the
+// results of measurements that were removed from the spec are dropped.
+func takeMeasurements(ctx context.Context, j *jumperless.Jumperless, instance *jumperlessv5alpha1.Jumperless,
+	status *jumperlessv5alpha1.JumperlessStatus) error {
+	log := ctrl.LoggerFrom(ctx)
+
+	trigger := instance.GetAnnotations()[jumperlessv5alpha1.MeasureAnnotation]
+
+	var results []jumperlessv5alpha1.MeasurementResult
+	for _, measurement := range instance.Spec.Measurements {
+		if i := slices.IndexFunc(status.Measurements, func(result jumperlessv5alpha1.MeasurementResult) bool {
+			return result.Name == measurement.Name
+		}); i >= 0 && measurementCurrent(measurement, status.Measurements[i], trigger) {
+			results = append(results, status.Measurements[i])
+			continue
+		}
+
+		count, interval := measurementSamples(measurement), measurementInterval(measurement)
+		log.Info("Taking measurement", "measurement", measurement.Name, "channel", measurement.Channel,
+			"samples", count, "interval", interval)
+
+		samples, err := local.SampleADC(ctx, j, measurement.Channel, count, interval)
+		if err != nil {
+			return fmt.Errorf("unable to take measurement %s: %w", measurement.Name, err)
+		}
+
+		results = append(results, newMeasurementResult(measurement, trigger, samples, time.Now()))
+	}
+
+	status.Measurements = results
+
+	return nil
+}
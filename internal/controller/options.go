@@ -0,0 +1,103 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"time"
+
+	"golang.org/x/time/rate"
+	"k8s.io/client-go/util/workqueue"
+	"k8s.io/utils/ptr"
+	crcontroller "sigs.k8s.io/controller-runtime/pkg/controller"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+// Defaults of the WorkqueueOptions, matching the defaults of controller-runtime.
+const (
+	DefaultMaxConcurrentReconciles = 1
+	DefaultRequeueBaseDelay        = 5 * time.Millisecond
+	DefaultRequeueMaxDelay         = 1000 * time.Second
+	DefaultRequeueQPS              = 10
+	DefaultRequeueBurst            = 100
+)
+
+// WorkqueueOptions bound the concurrency of a controller and the rate resources are requeued at. Zero values
+// use the defaults.
+type WorkqueueOptions struct {
+	// MaxConcurrentReconciles is the number of resources reconciled at the same time. Reconciles of the same
+	// device are serialized by the PortManager regardless.
+	MaxConcurrentReconciles int
+
+	// RequeueBaseDelay is the delay before retrying a resource whose reconcile failed, doubled for every
+	// further failure in a row up to RequeueMaxDelay.
+	RequeueBaseDelay time.Duration
+	RequeueMaxDelay  time.Duration
+
+	// RequeueQPS and RequeueBurst limit the rate at which the resources of the controller are requeued
+	// altogether, so many failing resources can't cause a requeue storm.
+	RequeueQPS   float64
+	RequeueBurst int
+}
+
+// withDefaults returns the options with zero values replaced by the defaults.
+func (o WorkqueueOptions) withDefaults() WorkqueueOptions {
+	if o.MaxConcurrentReconciles <= 0 {
+		o.MaxConcurrentReconciles = DefaultMaxConcurrentReconciles
+	}
+	if o.RequeueBaseDelay <= 0 {
+		o.RequeueBaseDelay = DefaultRequeueBaseDelay
+	}
+	if o.RequeueMaxDelay <= 0 {
+		o.RequeueMaxDelay = DefaultRequeueMaxDelay
+	}
+	o.RequeueMaxDelay = max(o.RequeueMaxDelay, o.RequeueBaseDelay)
+	if o.RequeueQPS <= 0 {
+		o.RequeueQPS = DefaultRequeueQPS
+	}
+	if o.RequeueBurst <= 0 {
+		o.RequeueBurst = DefaultRequeueBurst
+	}
+
+	return o
+}
+
+// controllerOptions returns the controller-runtime options of a controller using the workqueue options. The
+// rate limiter of a resource is the slower of its exponential backoff and the overall rate limit.
+func (o WorkqueueOptions) controllerOptions() crcontroller.Options {
+	o = o.withDefaults()
+
+	return crcontroller.Options{
+		MaxConcurrentReconciles: o.MaxConcurrentReconciles,
+		RateLimiter: workqueue.NewTypedMaxOfRateLimiter(
+			workqueue.NewTypedItemExponentialFailureRateLimiter[reconcile.Request](o.RequeueBaseDelay,
+				o.RequeueMaxDelay),
+			&workqueue.TypedBucketRateLimiter[reconcile.Request]{
+				Limiter: rate.NewLimiter(rate.Limit(o.RequeueQPS), o.RequeueBurst),
+			},
+		),
+	}
+}
+
+// deviceControllerOptions returns the options of the controllers opening sessions to devices. With an identity
+// each device is driven by the manager holding its lease, so the controllers run on every replica of the manager
+// rather than only on the leader, otherwise only the leader drives devices.
+func deviceControllerOptions(queue WorkqueueOptions, identity string) crcontroller.Options {
+	options := queue.controllerOptions()
+	options.NeedLeaderElection = ptr.To(identity == "")
+
+	return options
+}
@@ -0,0 +1,92 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"fmt"
+	"slices"
+
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	jumperlessv5alpha1 "github.com/detiber/k8s-jumperless/api/v5alpha1"
+)
+
+// resolveProfile returns the spec of the instance expanded with the JumperlessProfile referenced by
+// spec.profileRef, along with the profile. The spec is returned as it is if it doesn't reference a profile.
+func (r *JumperlessReconciler) resolveProfile(ctx context.Context, instance *jumperlessv5alpha1.Jumperless) (jumperlessv5alpha1.JumperlessSpec, *jumperlessv5alpha1.JumperlessProfile, error) {
+	ref := instance.Spec.ProfileRef
+	if ref == nil {
+		return instance.Spec, nil, nil
+	}
+
+	profile := &jumperlessv5alpha1.JumperlessProfile{}
+	if err := r.Get(ctx, client.ObjectKey{Namespace: instance.Namespace, Name: ref.Name}, profile); err != nil {
+		return instance.Spec, nil, fmt.Errorf("unable to fetch JumperlessProfile %s: %w", ref.Name, err)
+	}
+
+	return expandProfile(instance.Spec, profile.Spec), profile, nil
+}
+
+// expandProfile merges the DACs, GPIO levels and connections of a profile into the spec. The DACs and GPIO
+// levels of the spec take precedence over the ones of the profile for the same channel or pin, the connections
+// of the profile are made before the ones of the spec.
+func expandProfile(spec jumperlessv5alpha1.JumperlessSpec, profile jumperlessv5alpha1.JumperlessProfileSpec) jumperlessv5alpha1.JumperlessSpec {
+	expanded := spec.DeepCopy()
+
+	dacs := []jumperlessv5alpha1.DAC{}
+	for _, dac := range profile.DACS {
+		if !slices.ContainsFunc(spec.DACS, func(d jumperlessv5alpha1.DAC) bool { return d.Channel == dac.Channel }) {
+			dacs = append(dacs, *dac.DeepCopy())
+		}
+	}
+	expanded.DACS = append(dacs, expanded.DACS...)
+
+	gpio := []jumperlessv5alpha1.GPIO{}
+	for _, pin := range profile.GPIO {
+		if !slices.ContainsFunc(spec.GPIO, func(g jumperlessv5alpha1.GPIO) bool { return g.Pin == pin.Pin }) {
+			gpio = append(gpio, pin)
+		}
+	}
+	expanded.GPIO = append(gpio, expanded.GPIO...)
+
+	expanded.Connections = append(slices.Clone(profile.Connections), expanded.Connections...)
+
+	return *expanded
+}
+
+// jumperlessesForProfile returns a request for every Jumperless referencing the profile, so changes to a profile
+// are applied to the devices using it.
+func (r *JumperlessReconciler) jumperlessesForProfile(ctx context.Context, profile client.Object) []reconcile.Request {
+	list := &jumperlessv5alpha1.JumperlessList{}
+	if err := r.List(ctx, list, client.InNamespace(profile.GetNamespace())); err != nil {
+		ctrl.LoggerFrom(ctx).Error(err, "unable to list Jumperless resources referencing JumperlessProfile",
+			"profile", client.ObjectKeyFromObject(profile))
+		return nil
+	}
+
+	requests := []reconcile.Request{}
+	for _, instance := range list.Items {
+		if ref := instance.Spec.ProfileRef; ref != nil && ref.Name == profile.GetName() {
+			requests = append(requests, reconcile.Request{NamespacedName: client.ObjectKeyFromObject(&instance)})
+		}
+	}
+
+	return requests
+}
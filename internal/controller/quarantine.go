@@ -0,0 +1,189 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	ctrl "sigs.k8s.io/controller-runtime"
+
+	jumperlessv5alpha1 "github.com/detiber/k8s-jumperless/api/v5alpha1"
+)
+
+// DefaultQuarantineThreshold is the number of reconciles of a device failing in a row after which it is
+// quarantined, unless configured otherwise.
+const DefaultQuarantineThreshold = 5
+
+// DefaultQuarantineProbeInterval is the interval between probes of a quarantined device, unless configured
+// otherwise.
+const DefaultQuarantineProbeInterval = 10 * time.Minute
+
+// maxFailureRecords is the number of distinct errors kept in status.failures.
+const maxFailureRecords = 10
+
+// quarantineThreshold returns the number of failures after which a device is quarantined.
+func (r *JumperlessReconciler) quarantineThreshold() int {
+	if r.QuarantineThreshold <= 0 {
+		return DefaultQuarantineThreshold
+	}
+
+	return r.QuarantineThreshold
+}
+
+// quarantineProbeInterval returns the interval between probes of a quarantined device.
+func (r *JumperlessReconciler) quarantineProbeInterval() time.Duration {
+	if r.QuarantineProbeInterval <= 0 {
+		return DefaultQuarantineProbeInterval
+	}
+
+	return r.QuarantineProbeInterval
+}
+
+// quarantineWait returns how long a quarantined device has to wait before it is probed again, zero if it may
+// be probed now. A device is probed once the probe interval passed since its last failure, or right away once
+// its spec changed after it was quarantined.
+func quarantineWait(instance *jumperlessv5alpha1.Jumperless, interval time.Duration, now time.Time) time.Duration {
+	condition := meta.FindStatusCondition(instance.Status.Conditions, jumperlessv5alpha1.ConditionQuarantined)
+	if condition == nil || condition.Status != metav1.ConditionTrue ||
+		condition.ObservedGeneration != instance.Generation || instance.Status.Failures == nil {
+		return 0
+	}
+
+	probeAt := instance.Status.Failures.LastFailureTime.Add(interval)
+	if now.Before(probeAt) {
+		return probeAt.Sub(now)
+	}
+
+	return 0
+}
+
+// recordFailure adds a failed reconcile to the failure history, merging it with previous failures with the
+// same error. Only the most recent distinct errors are kept.
+func recordFailure(history *jumperlessv5alpha1.FailureHistory, err error,
+	now time.Time) *jumperlessv5alpha1.FailureHistory {
+	record := jumperlessv5alpha1.FailureRecord{Message: err.Error(), Count: 1, LastTime: metav1.NewTime(now)}
+
+	if history == nil {
+		return &jumperlessv5alpha1.FailureHistory{
+			Consecutive:     1,
+			LastFailureTime: record.LastTime,
+			Errors:          []jumperlessv5alpha1.FailureRecord{record},
+		}
+	}
+
+	history = history.DeepCopy()
+	history.Consecutive++
+	history.LastFailureTime = record.LastTime
+
+	if i := slices.IndexFunc(history.Errors, func(r jumperlessv5alpha1.FailureRecord) bool {
+		return r.Message == record.Message
+	}); i >= 0 {
+		record.Count += history.Errors[i].Count
+		history.Errors = slices.Delete(history.Errors, i, i+1)
+	}
+
+	history.Errors = append(history.Errors, record)
+	if len(history.Errors) > maxFailureRecords {
+		history.Errors = history.Errors[len(history.Errors)-maxFailureRecords:]
+	}
+
+	return history
+}
+
+// summarizeFailures returns the distinct errors of the failure history along with their counts, the most
+// frequent first.
+func summarizeFailures(history *jumperlessv5alpha1.FailureHistory) string {
+	records := slices.Clone(history.Errors)
+	slices.SortStableFunc(records, func(a, b jumperlessv5alpha1.FailureRecord) int {
+		return int(b.Count - a.Count)
+	})
+
+	summary := make([]string, 0, len(records))
+	for _, record := range records {
+		summary = append(summary, fmt.Sprintf("%dx %s", record.Count, record.Message))
+	}
+
+	return strings.Join(summary, "; ")
+}
+
+// observeFailures records the outcome of a reconcile in the failure history of the device, quarantining it
+// once the reconciles failed too many times in a row and releasing it once a reconcile succeeds. The failures
+// of a quarantined device are not returned, so the device is only probed at the probe interval instead of
+// being retried with the backoff of the workqueue.
+func (r *JumperlessReconciler) observeFailures(ctx context.Context, instance *jumperlessv5alpha1.Jumperless,
+	status *jumperlessv5alpha1.JumperlessStatus, result ctrl.Result, reconcileErr error) (ctrl.Result, error) {
+	log := ctrl.LoggerFrom(ctx)
+
+	quarantined := meta.IsStatusConditionTrue(status.Conditions, jumperlessv5alpha1.ConditionQuarantined)
+
+	if reconcileErr == nil {
+		status.Failures = nil
+		if quarantined {
+			log.Info("Jumperless reconciled successfully, releasing it from quarantine")
+			meta.SetStatusCondition(&status.Conditions, metav1.Condition{
+				Type:               jumperlessv5alpha1.ConditionQuarantined,
+				Status:             metav1.ConditionFalse,
+				Reason:             "Recovered",
+				Message:            "Jumperless was reconciled successfully after being quarantined",
+				ObservedGeneration: instance.Generation,
+			})
+			if r.Recorder != nil {
+				r.Recorder.Event(instance, corev1.EventTypeNormal, "Recovered",
+					"Jumperless was reconciled successfully and released from quarantine")
+			}
+		}
+
+		return result, nil
+	}
+
+	status.Failures = recordFailure(status.Failures, reconcileErr, time.Now())
+	if int(status.Failures.Consecutive) < r.quarantineThreshold() {
+		return result, reconcileErr
+	}
+
+	interval := r.quarantineProbeInterval()
+	message := fmt.Sprintf("Reconciling failed %d times in a row, the device is only probed every %s until a "+
+		"reconcile succeeds or the spec changes", status.Failures.Consecutive, interval)
+
+	meta.SetStatusCondition(&status.Conditions, metav1.Condition{
+		Type:               jumperlessv5alpha1.ConditionQuarantined,
+		Status:             metav1.ConditionTrue,
+		Reason:             "RepeatedFailures",
+		Message:            message,
+		ObservedGeneration: instance.Generation,
+	})
+
+	if quarantined {
+		log.Error(reconcileErr, "Probing quarantined Jumperless failed, probing again later", "after", interval)
+	} else {
+		log.Error(reconcileErr, "Jumperless failed repeatedly, quarantining it",
+			"failures", status.Failures.Consecutive, "probeInterval", interval)
+		if r.Recorder != nil {
+			r.Recorder.Event(instance, corev1.EventTypeWarning, "Quarantined",
+				message+": "+summarizeFailures(status.Failures))
+		}
+	}
+
+	return ctrl.Result{RequeueAfter: interval}, nil
+}
@@ -0,0 +1,119 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"slices"
+
+	"k8s.io/apimachinery/pkg/api/equality"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	jumperlessv5alpha1 "github.com/detiber/k8s-jumperless/api/v5alpha1"
+)
+
+// statusFieldOwner is the field manager applying the fields of the Jumperless status that don't belong to one
+// of the statusSubsystems. It applied the whole status before the subsystems had field managers of their own.
+const statusFieldOwner = "k8s-jumperless"
+
+// statusSubsystem is a part of the Jumperless status applied by a field manager of its own, so a reconcile
+// only applies the parts of the status that changed, without removing the fields of the other parts.
+type statusSubsystem struct {
+	fieldOwner string
+
+	// take moves the fields of the subsystem from one status to the other
+	take func(from, to *jumperlessv5alpha1.JumperlessStatus)
+}
+
+// statusSubsystems are the parts of the Jumperless status applied separately, the remaining fields are applied
+// by the statusFieldOwner.
+var statusSubsystems = []statusSubsystem{ //nolint:gochecknoglobals
+	{
+		fieldOwner: "k8s-jumperless-dacs",
+		take: func(from, to *jumperlessv5alpha1.JumperlessStatus) {
+			to.DACS, from.DACS = from.DACS, nil
+		},
+	},
+	{
+		fieldOwner: "k8s-jumperless-nets",
+		take: func(from, to *jumperlessv5alpha1.JumperlessStatus) {
+			to.Nets, from.Nets = from.Nets, nil
+			to.ConnectedNets, from.ConnectedNets = from.ConnectedNets, nil
+		},
+	},
+	{
+		fieldOwner: "k8s-jumperless-config",
+		take: func(from, to *jumperlessv5alpha1.JumperlessStatus) {
+			to.Config, from.Config = from.Config, nil
+			to.ConfigSchemaHash, from.ConfigSchemaHash = from.ConfigSchemaHash, nil
+			to.UARTBridge, from.UARTBridge = from.UARTBridge, nil
+		},
+	},
+	{
+		fieldOwner: "k8s-jumperless-conditions",
+		take: func(from, to *jumperlessv5alpha1.JumperlessStatus) {
+			to.Conditions, from.Conditions = from.Conditions, nil
+		},
+	},
+}
+
+// statusApply is a part of the Jumperless status and the field manager applying it.
+type statusApply struct {
+	fieldOwner string
+	status     *jumperlessv5alpha1.JumperlessStatus
+}
+
+// statusApplies splits the status into the parts of the statusSubsystems and the remaining fields, returning
+// the parts that differ from the current status of the instance in the order they have to be applied.
+//
+// A subsystem is also applied while its field manager doesn't own its fields yet, followed by the remaining
+// fields, so the fields are handed over from the statusFieldOwner rather than removed once it applies the
+// status without them.
+func statusApplies(instance *jumperlessv5alpha1.Jumperless,
+	status *jumperlessv5alpha1.JumperlessStatus) []statusApply {
+	current := instance.Status.DeepCopy()
+	rest := status.DeepCopy()
+
+	applies := []statusApply{}
+	handover := false
+	for _, subsystem := range statusSubsystems {
+		currentPart, part := &jumperlessv5alpha1.JumperlessStatus{}, &jumperlessv5alpha1.JumperlessStatus{}
+		subsystem.take(current, currentPart)
+		subsystem.take(rest, part)
+
+		unowned := !ownsStatusFields(instance, subsystem.fieldOwner) &&
+			!equality.Semantic.DeepEqual(part, &jumperlessv5alpha1.JumperlessStatus{})
+		if unowned || !equality.Semantic.DeepEqual(currentPart, part) {
+			applies = append(applies, statusApply{fieldOwner: subsystem.fieldOwner, status: part})
+		}
+
+		handover = handover || unowned
+	}
+
+	if handover || !equality.Semantic.DeepEqual(current, rest) {
+		applies = append(applies, statusApply{fieldOwner: statusFieldOwner, status: rest})
+	}
+
+	return applies
+}
+
+// ownsStatusFields returns whether the field manager applied fields of the status of the instance.
+func ownsStatusFields(instance *jumperlessv5alpha1.Jumperless, fieldOwner string) bool {
+	return slices.ContainsFunc(instance.ManagedFields, func(entry metav1.ManagedFieldsEntry) bool {
+		return entry.Manager == fieldOwner && entry.Operation == metav1.ManagedFieldsOperationApply &&
+			entry.Subresource == "status"
+	})
+}
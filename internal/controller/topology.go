@@ -0,0 +1,72 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	jumperlessv5alpha1 "github.com/detiber/k8s-jumperless/api/v5alpha1"
+	"github.com/detiber/k8s-jumperless/internal/topology"
+)
+
+// TopologyPath is the path the net topology of a Jumperless is served below, followed by its namespace and
+// name, e.g. /topology/default/jumperless-sample
+const TopologyPath = "/topology/"
+
+// Topology is an HTTP handler rendering the nets in the status of a Jumperless as a Graphviz DOT graph. The
+// graph is rendered from the status on every request, so it follows the nets as the controller observes them.
+// It is served by the metrics server, so access is authorized per device through the nonResourceURLs of the
+// path of its topology.
+type Topology struct {
+	// Client reads the Jumperless resources
+	Client client.Reader
+}
+
+// ServeHTTP implements http.Handler
+func (t *Topology) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	namespace, name, ok := strings.Cut(strings.TrimPrefix(req.URL.Path, TopologyPath), "/")
+	if !ok || namespace == "" || name == "" || strings.Contains(name, "/") {
+		http.Error(w, "expected "+TopologyPath+"<namespace>/<name>", http.StatusNotFound)
+		return
+	}
+
+	key := types.NamespacedName{Namespace: namespace, Name: name}
+	instance := &jumperlessv5alpha1.Jumperless{}
+	if err := t.Client.Get(req.Context(), key, instance); err != nil {
+		if apierrors.IsNotFound(err) {
+			http.Error(w, fmt.Sprintf("Jumperless %s not found", key), http.StatusNotFound)
+			return
+		}
+
+		ctrl.Log.WithName("topology").Error(err, "unable to fetch Jumperless", "jumperless", key)
+		http.Error(w, "unable to fetch Jumperless", http.StatusInternalServerError)
+
+		return
+	}
+
+	w.Header().Set("Content-Type", topology.ContentType)
+	if _, err := w.Write(topology.DOT(instance)); err != nil {
+		ctrl.Log.WithName("topology").Error(err, "Failed to write topology", "jumperless", key)
+	}
+}
@@ -0,0 +1,83 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/ptr"
+
+	jumperlessv5alpha1 "github.com/detiber/k8s-jumperless/api/v5alpha1"
+	"github.com/detiber/k8s-jumperless/internal/topology"
+)
+
+var _ = Describe("Jumperless Topology", func() {
+	Context("When rendering the topology of a device", func() {
+		ctx := context.Background()
+
+		resource := &jumperlessv5alpha1.Jumperless{
+			ObjectMeta: metav1.ObjectMeta{Name: "topology", Namespace: "default"},
+			Spec: jumperlessv5alpha1.JumperlessSpec{
+				Host: jumperlessv5alpha1.JumperlessHost{
+					Local: &jumperlessv5alpha1.JumperlessHostLocal{Port: ptr.To("/dev/ttyJumperlessTopology")},
+				},
+			},
+		}
+
+		BeforeEach(func() {
+			instance := resource.DeepCopy()
+			Expect(k8sClient.Create(ctx, instance)).To(Succeed())
+
+			instance.Status.Nets = []jumperlessv5alpha1.Net{
+				{Index: 8, Name: "Net 8", Color: ptr.To("red"), Nodes: []string{"D1", "12"}},
+			}
+			Expect(k8sClient.Status().Update(ctx, instance)).To(Succeed())
+		})
+
+		AfterEach(func() {
+			Expect(k8sClient.Delete(ctx, resource.DeepCopy())).To(Succeed())
+		})
+
+		It("should render the nets in the status as a DOT graph", func() {
+			handler := &Topology{Client: k8sClient}
+
+			serve := func(path string) *httptest.ResponseRecorder {
+				recorder := httptest.NewRecorder()
+				handler.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, path, nil))
+
+				return recorder
+			}
+
+			By("Refusing malformed paths")
+			Expect(serve(TopologyPath + "default").Code).To(Equal(http.StatusNotFound))
+
+			By("Refusing unknown devices")
+			Expect(serve(TopologyPath + "default/missing").Code).To(Equal(http.StatusNotFound))
+
+			By("Rendering the nets of the device")
+			response := serve(TopologyPath + "default/topology")
+			Expect(response.Code).To(Equal(http.StatusOK))
+			Expect(response.Header().Get("Content-Type")).To(Equal(topology.ContentType))
+			Expect(response.Body.String()).To(ContainSubstring(`"net:8" -- "node:D1" [color="red"];`))
+		})
+	})
+})
@@ -0,0 +1,238 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package ksm generates kube-state-metrics CustomResourceStateMetrics configuration
+// for the Jumperless APIs.
+//
+// The metrics are built from a stable set of status fields, changes to these fields
+// must be made in a backwards compatible way:
+//   - Jumperless: status.firmwareVersion, status.localPort, status.dacs[].channel,
+//     status.dacs[].millivolts, status.nets[].index, status.nets[].name, status.conditions
+//   - JumperlessFleet: status.devices[].name, status.devices[].port,
+//     status.devices[].serialNumber, status.devices[].firmwareVersion, status.conditions
+package ksm
+
+import (
+	"fmt"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/yaml"
+
+	jumperlessv5alpha1 "github.com/detiber/k8s-jumperless/api/v5alpha1"
+)
+
+const (
+	kindCustomResourceStateMetrics = "CustomResourceStateMetrics"
+
+	metricTypeGauge    = "Gauge"
+	metricTypeInfo     = "Info"
+	metricTypeStateSet = "StateSet"
+)
+
+// conditionStatuses are the values of the status of a condition, each gets a series of the condition metric.
+var conditionStatuses = []string{ //nolint:gochecknoglobals
+	string(metav1.ConditionTrue),
+	string(metav1.ConditionFalse),
+	string(metav1.ConditionUnknown),
+}
+
+// Config is a kube-state-metrics custom resource state configuration.
+type Config struct {
+	Kind string `json:"kind"`
+	Spec Spec   `json:"spec"`
+}
+
+// Spec is the spec of a kube-state-metrics custom resource state configuration.
+type Spec struct {
+	Resources []Resource `json:"resources"`
+}
+
+// GroupVersionKind identifies the resource metrics are generated for.
+type GroupVersionKind struct {
+	Group   string `json:"group"`
+	Version string `json:"version"`
+	Kind    string `json:"kind"`
+}
+
+// Resource configures the metrics generated for a single resource kind.
+type Resource struct {
+	GroupVersionKind GroupVersionKind    `json:"groupVersionKind"`
+	MetricNamePrefix string              `json:"metricNamePrefix"`
+	LabelsFromPath   map[string][]string `json:"labelsFromPath,omitempty"`
+	Metrics          []Metric            `json:"metrics"`
+}
+
+// Metric configures a single metric.
+type Metric struct {
+	Name string `json:"name"`
+	Help string `json:"help"`
+	Each Each   `json:"each"`
+}
+
+// Each configures how the metric value is generated.
+type Each struct {
+	Type     string      `json:"type"`
+	Gauge    *MetricSpec `json:"gauge,omitempty"`
+	Info     *MetricSpec `json:"info,omitempty"`
+	StateSet *MetricSpec `json:"stateSet,omitempty"`
+}
+
+// MetricSpec configures the path, labels and value of a metric. A StateSet emits a series for each value of
+// List, labelled with the value in LabelName and set to 1 for the value found at ValueFrom.
+type MetricSpec struct {
+	Path           []string            `json:"path,omitempty"`
+	LabelsFromPath map[string][]string `json:"labelsFromPath,omitempty"`
+	ValueFrom      []string            `json:"valueFrom,omitempty"`
+	LabelName      string              `json:"labelName,omitempty"`
+	List           []string            `json:"list,omitempty"`
+}
+
+// NewConfig returns the kube-state-metrics configuration for the Jumperless APIs.
+func NewConfig() *Config {
+	return &Config{
+		Kind: kindCustomResourceStateMetrics,
+		Spec: Spec{
+			Resources: []Resource{
+				jumperlessResource(),
+				jumperlessFleetResource(),
+			},
+		},
+	}
+}
+
+// Marshal returns the kube-state-metrics configuration as YAML.
+func (c *Config) Marshal() ([]byte, error) {
+	out, err := yaml.Marshal(c)
+	if err != nil {
+		return nil, fmt.Errorf("unable to marshal kube-state-metrics config: %w", err)
+	}
+
+	return out, nil
+}
+
+// conditionMetric returns the metric of the conditions of a kind, with a series for each status of a condition
+// like the condition metrics of kube-state-metrics, so conditions with the Unknown status are reported as well.
+func conditionMetric(kind string) Metric {
+	return Metric{
+		Name: "status_condition",
+		Help: "The current status conditions of the " + kind + " resource.",
+		Each: Each{
+			Type: metricTypeStateSet,
+			StateSet: &MetricSpec{
+				Path: []string{"status", "conditions"},
+				LabelsFromPath: map[string][]string{
+					"type":   {"type"},
+					"reason": {"reason"},
+				},
+				ValueFrom: []string{"status"},
+				LabelName: "status",
+				List:      conditionStatuses,
+			},
+		},
+	}
+}
+
+func jumperlessResource() Resource {
+	return Resource{
+		GroupVersionKind: GroupVersionKind{
+			Group:   jumperlessv5alpha1.GroupVersion.Group,
+			Version: jumperlessv5alpha1.GroupVersion.Version,
+			Kind:    "Jumperless",
+		},
+		MetricNamePrefix: "jumperless",
+		LabelsFromPath: map[string][]string{
+			"name":      {"metadata", "name"},
+			"namespace": {"metadata", "namespace"},
+		},
+		Metrics: []Metric{
+			{
+				Name: "info",
+				Help: "Information about the connected Jumperless device.",
+				Each: Each{
+					Type: metricTypeInfo,
+					Info: &MetricSpec{
+						LabelsFromPath: map[string][]string{
+							"firmware_version": {"status", "firmwareVersion"},
+							"local_port":       {"status", "localPort"},
+						},
+					},
+				},
+			},
+			{
+				Name: "dac_millivolts",
+				Help: "The current voltage of each DAC channel in millivolts.",
+				Each: Each{
+					Type: metricTypeGauge,
+					Gauge: &MetricSpec{
+						Path: []string{"status", "dacs"},
+						LabelsFromPath: map[string][]string{
+							"channel": {"channel"},
+						},
+						ValueFrom: []string{"millivolts"},
+					},
+				},
+			},
+			{
+				Name: "net_info",
+				Help: "The nets currently configured on the Jumperless device.",
+				Each: Each{
+					Type: metricTypeInfo,
+					Info: &MetricSpec{
+						Path: []string{"status", "nets"},
+						LabelsFromPath: map[string][]string{
+							"index": {"index"},
+							"net":   {"name"},
+						},
+					},
+				},
+			},
+			conditionMetric("Jumperless"),
+		},
+	}
+}
+
+func jumperlessFleetResource() Resource {
+	return Resource{
+		GroupVersionKind: GroupVersionKind{
+			Group:   jumperlessv5alpha1.GroupVersion.Group,
+			Version: jumperlessv5alpha1.GroupVersion.Version,
+			Kind:    "JumperlessFleet",
+		},
+		MetricNamePrefix: "jumperlessfleet",
+		LabelsFromPath: map[string][]string{
+			"name": {"metadata", "name"},
+		},
+		Metrics: []Metric{
+			{
+				Name: "device_info",
+				Help: "The devices discovered by the JumperlessFleet.",
+				Each: Each{
+					Type: metricTypeInfo,
+					Info: &MetricSpec{
+						Path: []string{"status", "devices"},
+						LabelsFromPath: map[string][]string{
+							"device":           {"name"},
+							"port":             {"port"},
+							"serial_number":    {"serialNumber"},
+							"firmware_version": {"firmwareVersion"},
+						},
+					},
+				},
+			},
+			conditionMetric("JumperlessFleet"),
+		},
+	}
+}
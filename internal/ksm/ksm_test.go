@@ -0,0 +1,50 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ksm
+
+import (
+	"flag"
+	"os"
+	"testing"
+
+	. "github.com/onsi/gomega"
+)
+
+// The generated configuration is compared with testdata/config.golden.yaml, which is written with
+//
+//	go test ./internal/ksm -run TestGolden -update
+var update = flag.Bool("update", false, "write the golden file instead of comparing it")
+
+// goldenFile is the expected configuration, relative to the package
+const goldenFile = "testdata/config.golden.yaml"
+
+func TestGolden(t *testing.T) {
+	g := NewWithT(t)
+
+	actual, err := NewConfig().Marshal()
+	g.Expect(err).NotTo(HaveOccurred())
+
+	if *update {
+		g.Expect(os.MkdirAll("testdata", 0o750)).To(Succeed())
+		g.Expect(os.WriteFile(goldenFile, actual, 0o600)).To(Succeed())
+		return
+	}
+
+	expected, err := os.ReadFile(goldenFile)
+	g.Expect(err).NotTo(HaveOccurred(), "run the test with -update to write the golden file")
+	g.Expect(string(actual)).To(Equal(string(expected)))
+}
@@ -0,0 +1,97 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package topology renders the nets of a Jumperless as a Graphviz graph, so complex breadboard routing can be
+// inspected visually instead of reading the nets in the status.
+package topology
+
+import (
+	"bytes"
+	"fmt"
+	"slices"
+	"strings"
+
+	jumperlessv5alpha1 "github.com/detiber/k8s-jumperless/api/v5alpha1"
+)
+
+// ContentType is the media type of the DOT graphs rendered by DOT
+const ContentType = "text/vnd.graphviz; charset=utf-8"
+
+// DOT renders the nets in the status of the Jumperless as an undirected Graphviz graph, with a node per net
+// connected to the breadboard nodes that are part of it. Nets are drawn in their color and labeled with their
+// voltage. The graph can be rendered with e.g. "dot -Tsvg".
+func DOT(instance *jumperlessv5alpha1.Jumperless) []byte {
+	title := instance.Namespace + "/" + instance.Name
+	if instance.Namespace == "" {
+		title = instance.Name
+	}
+
+	var b bytes.Buffer
+	fmt.Fprintf(&b, "graph %s {\n", quote(title))
+	fmt.Fprintf(&b, "\tlabel=%s;\n\tlabelloc=t;\n\trankdir=LR;\n", quote(title))
+	b.WriteString("\tnode [fontname=\"Helvetica\"];\n\tedge [penwidth=2];\n")
+
+	nets := slices.Clone(instance.Status.Nets)
+	slices.SortStableFunc(nets, func(a, b jumperlessv5alpha1.Net) int { return int(a.Index - b.Index) })
+
+	for _, net := range nets {
+		id := netID(net)
+		netAttrs, edgeAttrs := "", ""
+		if net.Color != nil && *net.Color != "" {
+			netAttrs = ", color=" + quote(*net.Color)
+			edgeAttrs = " [color=" + quote(*net.Color) + "]"
+		}
+
+		fmt.Fprintf(&b, "\n\t%s [label=%s, shape=ellipse, penwidth=2%s];\n", quote(id), quote(netLabel(net)),
+			netAttrs)
+		for _, node := range net.Nodes {
+			fmt.Fprintf(&b, "\t%s [label=%s, shape=box];\n", quote(nodeID(node)), quote(node))
+			fmt.Fprintf(&b, "\t%s -- %s%s;\n", quote(id), quote(nodeID(node)), edgeAttrs)
+		}
+	}
+
+	b.WriteString("}\n")
+
+	return b.Bytes()
+}
+
+// netLabel returns the label of a net, its name followed by its voltage if it has one
+func netLabel(net jumperlessv5alpha1.Net) string {
+	if net.Voltage == nil {
+		return net.Name
+	}
+
+	return net.Name + "\n" + *net.Voltage
+}
+
+// netID returns the graph node ID of a net, distinct from the IDs of breadboard nodes
+func netID(net jumperlessv5alpha1.Net) string {
+	return fmt.Sprintf("net:%d", net.Index)
+}
+
+// nodeID returns the graph node ID of a breadboard node
+func nodeID(node string) string {
+	return "node:" + node
+}
+
+// quote returns s as a quoted DOT string, where newlines become centered line breaks
+func quote(s string) string {
+	s = strings.ReplaceAll(s, `\`, `\\`)
+	s = strings.ReplaceAll(s, `"`, `\"`)
+	s = strings.ReplaceAll(s, "\n", `\n`)
+
+	return `"` + s + `"`
+}
@@ -0,0 +1,67 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package topology
+
+import (
+	"testing"
+
+	. "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/ptr"
+
+	jumperlessv5alpha1 "github.com/detiber/k8s-jumperless/api/v5alpha1"
+)
+
+func TestDOT(t *testing.T) {
+	g := NewWithT(t)
+
+	instance := &jumperlessv5alpha1.Jumperless{
+		ObjectMeta: metav1.ObjectMeta{Name: "bench-1", Namespace: "default"},
+		Status: jumperlessv5alpha1.JumperlessStatus{
+			Nets: []jumperlessv5alpha1.Net{
+				{Index: 8, Name: "Net 8", Voltage: ptr.To("3.30V"), Color: ptr.To("red"), Nodes: []string{"D1", "12"}},
+				{Index: 1, Name: "GND", Nodes: []string{"GND"}},
+			},
+		},
+	}
+
+	g.Expect(string(DOT(instance))).To(Equal(`graph "default/bench-1" {
+	label="default/bench-1";
+	labelloc=t;
+	rankdir=LR;
+	node [fontname="Helvetica"];
+	edge [penwidth=2];
+
+	"net:1" [label="GND", shape=ellipse, penwidth=2];
+	"node:GND" [label="GND", shape=box];
+	"net:1" -- "node:GND";
+
+	"net:8" [label="Net 8\n3.30V", shape=ellipse, penwidth=2, color="red"];
+	"node:D1" [label="D1", shape=box];
+	"net:8" -- "node:D1" [color="red"];
+	"node:12" [label="12", shape=box];
+	"net:8" -- "node:12" [color="red"];
+}
+`))
+}
+
+func TestQuote(t *testing.T) {
+	g := NewWithT(t)
+
+	g.Expect(quote(`say "hi"\now`)).To(Equal(`"say \"hi\"\\now"`))
+	g.Expect(quote("a\nb")).To(Equal(`"a\nb"`))
+}
@@ -0,0 +1,163 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package transport
+
+import (
+	"context"
+	"crypto/tls"
+	"errors"
+	"net"
+	"strings"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/credentials/insecure"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+var ErrClientCredentials = errors.New("client credentials can't serve connections")
+
+// authorizationKey is the metadata key of the bearer token of gRPC calls
+const authorizationKey = "authorization"
+
+// ServerOptions returns the options of a gRPC server serving TLS if a certificate is configured, and rejecting
+// calls without the bearer token if a token file is configured. The health service is served to everyone, since
+// kubelet probes can't authenticate.
+func (c *Credentials) ServerOptions() []grpc.ServerOption {
+	var opts []grpc.ServerOption
+	if config := c.ServerTLS(); config != nil {
+		opts = append(opts, grpc.Creds(credentials.NewTLS(config)))
+	}
+
+	if c.token != nil {
+		opts = append(opts,
+			grpc.ChainUnaryInterceptor(func(ctx context.Context, req any, info *grpc.UnaryServerInfo,
+				handler grpc.UnaryHandler) (any, error) {
+				if err := c.authorize(ctx, info.FullMethod); err != nil {
+					return nil, err
+				}
+
+				return handler(ctx, req)
+			}),
+			grpc.ChainStreamInterceptor(func(srv any, stream grpc.ServerStream, info *grpc.StreamServerInfo,
+				handler grpc.StreamHandler) error {
+				if err := c.authorize(stream.Context(), info.FullMethod); err != nil {
+					return err
+				}
+
+				return handler(srv, stream)
+			}),
+		)
+	}
+
+	return opts
+}
+
+// authorize returns an Unauthenticated error unless a call carries the bearer token
+func (c *Credentials) authorize(ctx context.Context, method string) error {
+	if strings.HasPrefix(method, "/"+healthpb.Health_ServiceDesc.ServiceName+"/") {
+		return nil
+	}
+
+	var authorization string
+	if values := metadata.ValueFromIncomingContext(ctx, authorizationKey); len(values) > 0 {
+		authorization = values[0]
+	}
+
+	if err := c.CheckToken(authorization); err != nil {
+		return status.Error(codes.Unauthenticated, err.Error())
+	}
+
+	return nil
+}
+
+// DialOptions returns the options of a gRPC client connecting with TLS if a certificate or CA bundle is
+// configured, and sending the bearer token if a token file is configured
+func (c *Credentials) DialOptions() []grpc.DialOption {
+	var creds credentials.TransportCredentials = insecure.NewCredentials()
+	if c.certificate != nil || c.ca != nil {
+		creds = clientCredentials{creds: c}
+	}
+
+	opts := []grpc.DialOption{grpc.WithTransportCredentials(creds)}
+	if c.token != nil {
+		opts = append(opts, grpc.WithPerRPCCredentials(tokenCredentials{creds: c}))
+	}
+
+	return opts
+}
+
+// clientCredentials are TLS transport credentials using the current client certificate and CA bundle for every
+// connection
+type clientCredentials struct {
+	creds *Credentials
+}
+
+// ClientHandshake implements credentials.TransportCredentials
+func (c clientCredentials) ClientHandshake(ctx context.Context, authority string,
+	conn net.Conn) (net.Conn, credentials.AuthInfo, error) {
+	config, err := c.creds.ClientTLS()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return credentials.NewTLS(config).ClientHandshake(ctx, authority, conn) //nolint:wrapcheck
+}
+
+// ServerHandshake implements credentials.TransportCredentials
+func (c clientCredentials) ServerHandshake(net.Conn) (net.Conn, credentials.AuthInfo, error) {
+	return nil, nil, ErrClientCredentials
+}
+
+// Info implements credentials.TransportCredentials
+func (c clientCredentials) Info() credentials.ProtocolInfo {
+	return credentials.NewTLS(&tls.Config{ServerName: c.creds.config.ServerName}).Info() //nolint:gosec
+}
+
+// Clone implements credentials.TransportCredentials
+func (c clientCredentials) Clone() credentials.TransportCredentials {
+	return c
+}
+
+// OverrideServerName implements credentials.TransportCredentials, the server name is configured instead
+func (c clientCredentials) OverrideServerName(string) error {
+	return nil
+}
+
+// tokenCredentials send the current bearer token with every call
+type tokenCredentials struct {
+	creds *Credentials
+}
+
+// GetRequestMetadata implements credentials.PerRPCCredentials
+func (t tokenCredentials) GetRequestMetadata(context.Context, ...string) (map[string]string, error) {
+	token, err := t.creds.Token()
+	if err != nil {
+		return nil, err
+	}
+
+	return map[string]string{authorizationKey: bearerPrefix + token}, nil
+}
+
+// RequireTransportSecurity implements credentials.PerRPCCredentials, tokens may be sent without TLS on networks
+// that are trusted otherwise
+func (t tokenCredentials) RequireTransportSecurity() bool {
+	return false
+}
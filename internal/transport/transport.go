@@ -0,0 +1,368 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package transport secures the network endpoints serving the serial ports of Jumperless devices with TLS,
+// client certificates and bearer tokens. Certificates, CA bundles and tokens are read from files that are
+// reloaded whenever they change, so they can be rotated without a restart, e.g. when mounted from a Secret.
+package transport
+
+import (
+	"context"
+	"crypto/subtle"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"os"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+)
+
+var ErrUnauthenticated = errors.New("refusing to serve serial ports without authentication on a non-loopback address")
+var ErrIncompleteKeyPair = errors.New("a TLS certificate requires a key and vice versa")
+var ErrNoCACertificates = errors.New("no certificates found in CA file")
+var ErrEmptyToken = errors.New("token file is empty")
+var ErrInvalidToken = errors.New("missing or invalid bearer token")
+
+// bearerPrefix prefixes the token in the authorization header of HTTP requests and gRPC calls
+const bearerPrefix = "Bearer "
+
+// Config configures the authentication of an endpoint, or of the client connecting to it. The same files serve
+// both sides: a server presents its certificate and requires client certificates signed by the CA, a client
+// presents its certificate and verifies the server certificate with the CA.
+type Config struct {
+	// CertFile and KeyFile are the PEM encoded certificate and key presented to the peer, TLS is disabled on
+	// servers without them
+	CertFile string `json:"tlsCertFile,omitempty" mapstructure:"tlsCertFile" yaml:"tlsCertFile,omitempty"`
+	KeyFile  string `json:"tlsKeyFile,omitempty"  mapstructure:"tlsKeyFile"  yaml:"tlsKeyFile,omitempty"`
+
+	// CAFile is the PEM encoded CA bundle the certificates of the peers are verified with. Servers require
+	// client certificates signed by it, clients verify the server certificate with it instead of the system roots.
+	CAFile string `json:"tlsCAFile,omitempty" mapstructure:"tlsCAFile" yaml:"tlsCAFile,omitempty"`
+
+	// ServerName is the name clients verify the server certificate for, instead of the host they connect to
+	ServerName string `json:"tlsServerName,omitempty" mapstructure:"tlsServerName" yaml:"tlsServerName,omitempty"`
+
+	// TokenFile contains the bearer token clients send with every request, only supported by endpoints serving
+	// requests such as gRPC and HTTP. Servers reject requests without it.
+	TokenFile string `json:"tokenFile,omitempty" mapstructure:"tokenFile" yaml:"tokenFile,omitempty"`
+
+	// AllowUnauthenticated serves endpoints on non-loopback addresses without authentication
+	AllowUnauthenticated bool `json:"allowUnauthenticated,omitempty" mapstructure:"allowUnauthenticated" yaml:"allowUnauthenticated,omitempty"`
+}
+
+// Credentials are the loaded files of a Config, reloaded whenever they change
+type Credentials struct {
+	config      Config
+	certificate *reloader[*tls.Certificate]
+	ca          *reloader[*x509.CertPool]
+	token       *reloader[string]
+}
+
+// Load loads the configured files, returning an error if they can't be loaded
+func (c Config) Load() (*Credentials, error) {
+	if (c.CertFile == "") != (c.KeyFile == "") {
+		return nil, ErrIncompleteKeyPair
+	}
+
+	creds := &Credentials{config: c}
+
+	var errs []error
+	if c.CertFile != "" {
+		creds.certificate = newReloader(func() (*tls.Certificate, error) {
+			certificate, err := tls.LoadX509KeyPair(c.CertFile, c.KeyFile)
+			if err != nil {
+				return nil, fmt.Errorf("unable to load TLS certificate %s: %w", c.CertFile, err)
+			}
+
+			return &certificate, nil
+		}, c.CertFile, c.KeyFile)
+
+		_, err := creds.certificate.get()
+		errs = append(errs, err)
+	}
+
+	if c.CAFile != "" {
+		creds.ca = newReloader(func() (*x509.CertPool, error) {
+			data, err := os.ReadFile(c.CAFile)
+			if err != nil {
+				return nil, fmt.Errorf("unable to read CA file: %w", err)
+			}
+
+			pool := x509.NewCertPool()
+			if !pool.AppendCertsFromPEM(data) {
+				return nil, fmt.Errorf("%w: %s", ErrNoCACertificates, c.CAFile)
+			}
+
+			return pool, nil
+		}, c.CAFile)
+
+		_, err := creds.ca.get()
+		errs = append(errs, err)
+	}
+
+	if c.TokenFile != "" {
+		creds.token = newReloader(func() (string, error) {
+			data, err := os.ReadFile(c.TokenFile)
+			if err != nil {
+				return "", fmt.Errorf("unable to read token file: %w", err)
+			}
+
+			token := strings.TrimSpace(string(data))
+			if token == "" {
+				return "", fmt.Errorf("%w: %s", ErrEmptyToken, c.TokenFile)
+			}
+
+			return token, nil
+		}, c.TokenFile)
+
+		_, err := creds.token.get()
+		errs = append(errs, err)
+	}
+
+	if err := errors.Join(errs...); err != nil {
+		return nil, err
+	}
+
+	return creds, nil
+}
+
+// Authenticated returns true if clients have to authenticate, with a client certificate or, if the endpoint
+// supports them, a bearer token
+func (c Config) Authenticated(tokens bool) bool {
+	return (c.CertFile != "" && c.CAFile != "") || (tokens && c.TokenFile != "")
+}
+
+// CheckExposure returns an error if an endpoint would be served on a non-loopback address without requiring
+// clients to authenticate, unless that is explicitly allowed. tokens reports whether the endpoint supports
+// bearer tokens.
+func (c Config) CheckExposure(address string, tokens bool) error {
+	if c.AllowUnauthenticated || c.Authenticated(tokens) {
+		return nil
+	}
+
+	host, _, err := net.SplitHostPort(address)
+	if err != nil {
+		host = address
+	}
+
+	if host == "localhost" {
+		return nil
+	}
+	if ip := net.ParseIP(host); ip != nil && ip.IsLoopback() {
+		return nil
+	}
+
+	return fmt.Errorf("%w: %s", ErrUnauthenticated, address)
+}
+
+// CheckExposure returns an error if an endpoint would be served on a non-loopback address without requiring
+// clients to authenticate, see Config.CheckExposure
+func (c *Credentials) CheckExposure(address string, tokens bool) error {
+	return c.config.CheckExposure(address, tokens)
+}
+
+// ServerTLS returns the TLS config of a server, or nil if TLS is disabled. The certificate and CA bundle are
+// reloaded for every handshake if their files changed.
+func (c *Credentials) ServerTLS() *tls.Config {
+	if c.certificate == nil {
+		return nil
+	}
+
+	return &tls.Config{
+		MinVersion: tls.VersionTLS12,
+		GetConfigForClient: func(*tls.ClientHelloInfo) (*tls.Config, error) {
+			certificate, err := c.certificate.get()
+			if err != nil {
+				return nil, err
+			}
+
+			config := &tls.Config{
+				MinVersion:   tls.VersionTLS12,
+				Certificates: []tls.Certificate{*certificate},
+			}
+
+			if c.ca != nil {
+				pool, err := c.ca.get()
+				if err != nil {
+					return nil, err
+				}
+
+				config.ClientAuth = tls.RequireAndVerifyClientCert
+				config.ClientCAs = pool
+			}
+
+			return config, nil
+		},
+	}
+}
+
+// ClientTLS returns the TLS config of a client with the current client certificate and CA bundle, or nil if
+// neither is configured. The files are reloaded if they changed, so a config is created for every connection.
+func (c *Credentials) ClientTLS() (*tls.Config, error) {
+	if c.certificate == nil && c.ca == nil {
+		return nil, nil //nolint:nilnil
+	}
+
+	config := &tls.Config{
+		MinVersion: tls.VersionTLS12,
+		ServerName: c.config.ServerName,
+	}
+
+	if c.certificate != nil {
+		certificate, err := c.certificate.get()
+		if err != nil {
+			return nil, err
+		}
+
+		config.Certificates = []tls.Certificate{*certificate}
+	}
+
+	if c.ca != nil {
+		pool, err := c.ca.get()
+		if err != nil {
+			return nil, err
+		}
+
+		config.RootCAs = pool
+	}
+
+	return config, nil
+}
+
+// Listen listens on a TCP address, serving TLS if a certificate is configured
+func (c *Credentials) Listen(ctx context.Context, address string) (net.Listener, error) {
+	var lc net.ListenConfig
+	listener, err := lc.Listen(ctx, "tcp", address)
+	if err != nil {
+		return nil, fmt.Errorf("failed to listen on %s: %w", address, err)
+	}
+
+	if config := c.ServerTLS(); config != nil {
+		listener = tls.NewListener(listener, config)
+	}
+
+	return listener, nil
+}
+
+// Token returns the bearer token, empty if no token file is configured
+func (c *Credentials) Token() (string, error) {
+	if c.token == nil {
+		return "", nil
+	}
+
+	return c.token.get()
+}
+
+// CheckToken returns an error unless the authorization header value carries the configured bearer token,
+// any value is accepted if no token file is configured
+func (c *Credentials) CheckToken(authorization string) error {
+	expected, err := c.Token()
+	if err != nil {
+		return err
+	}
+	if expected == "" {
+		return nil
+	}
+
+	token, ok := strings.CutPrefix(authorization, bearerPrefix)
+	if !ok || subtle.ConstantTimeCompare([]byte(token), []byte(expected)) != 1 {
+		return ErrInvalidToken
+	}
+
+	return nil
+}
+
+// Handler wraps an HTTP handler, rejecting requests without the configured bearer token
+func (c *Credentials) Handler(handler http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		if err := c.CheckToken(req.Header.Get("Authorization")); err != nil {
+			w.Header().Set("WWW-Authenticate", "Bearer")
+			http.Error(w, err.Error(), http.StatusUnauthorized)
+
+			return
+		}
+
+		handler.ServeHTTP(w, req)
+	})
+}
+
+// reloader caches a value loaded from files, loading it again once one of the files changed. If loading the
+// changed files fails, e.g. because only the certificate of a key pair was replaced so far, the previous value
+// is returned until the files can be loaded.
+type reloader[T any] struct {
+	load  func() (T, error)
+	paths []string
+
+	mu     sync.Mutex
+	loaded bool
+	value  T
+	stamps []fileStamp
+}
+
+// fileStamp identifies a version of a file
+type fileStamp struct {
+	modTime time.Time
+	size    int64
+}
+
+func newReloader[T any](load func() (T, error), paths ...string) *reloader[T] {
+	return &reloader[T]{load: load, paths: paths}
+}
+
+// get returns the value, loading it again if the files changed
+func (r *reloader[T]) get() (T, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	stamps := make([]fileStamp, 0, len(r.paths))
+	for _, path := range r.paths {
+		info, err := os.Stat(path)
+		if err != nil {
+			if r.loaded {
+				return r.value, nil
+			}
+
+			var zero T
+			return zero, fmt.Errorf("unable to read %s: %w", path, err)
+		}
+
+		stamps = append(stamps, fileStamp{modTime: info.ModTime(), size: info.Size()})
+	}
+
+	if r.loaded && slices.Equal(stamps, r.stamps) {
+		return r.value, nil
+	}
+
+	value, err := r.load()
+	if err != nil {
+		if r.loaded {
+			return r.value, nil
+		}
+
+		return value, err
+	}
+
+	r.loaded = true
+	r.value = value
+	r.stamps = stamps
+
+	return value, nil
+}
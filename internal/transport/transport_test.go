@@ -0,0 +1,392 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package transport
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"io"
+	"math/big"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	. "github.com/onsi/gomega"
+	"go.bug.st/serial"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/status"
+
+	"github.com/detiber/k8s-jumperless/jumperless"
+	"github.com/detiber/k8s-jumperless/jumperless/remotepb"
+)
+
+// testCA signs the certificates of a test
+type testCA struct {
+	cert *x509.Certificate
+	key  *ecdsa.PrivateKey
+	pem  []byte
+}
+
+func newTestCA(g *WithT) *testCA {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	g.Expect(err).NotTo(HaveOccurred())
+
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "test-ca"},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		IsCA:                  true,
+		KeyUsage:              x509.KeyUsageCertSign,
+		BasicConstraintsValid: true,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	g.Expect(err).NotTo(HaveOccurred())
+
+	cert, err := x509.ParseCertificate(der)
+	g.Expect(err).NotTo(HaveOccurred())
+
+	return &testCA{cert: cert, key: key, pem: pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})}
+}
+
+// issue writes a certificate and key signed by the CA to dir, returning their paths
+func (ca *testCA) issue(g *WithT, dir, name string, usage x509.ExtKeyUsage) (string, string) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	g.Expect(err).NotTo(HaveOccurred())
+
+	serial, err := rand.Int(rand.Reader, big.NewInt(1<<62))
+	g.Expect(err).NotTo(HaveOccurred())
+
+	template := &x509.Certificate{
+		SerialNumber: serial,
+		Subject:      pkix.Name{CommonName: name},
+		DNSNames:     []string{name},
+		IPAddresses:  []net.IP{net.IPv4(127, 0, 0, 1)},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:  []x509.ExtKeyUsage{usage},
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, ca.cert, &key.PublicKey, ca.key)
+	g.Expect(err).NotTo(HaveOccurred())
+
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	g.Expect(err).NotTo(HaveOccurred())
+
+	certFile := filepath.Join(dir, name+".crt")
+	keyFile := filepath.Join(dir, name+".key")
+	writeFile(g, certFile, string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})))
+	writeFile(g, keyFile, string(pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER})))
+
+	return certFile, keyFile
+}
+
+// writeFile writes a file with a modification time after the previous one, so it is reloaded even on
+// filesystems with coarse timestamps
+func writeFile(g *WithT, path, content string) {
+	modTime := time.Now()
+	if info, err := os.Stat(path); err == nil && !modTime.After(info.ModTime()) {
+		modTime = info.ModTime().Add(time.Second)
+	}
+
+	g.Expect(os.WriteFile(path, []byte(content), 0o600)).To(Succeed())
+	g.Expect(os.Chtimes(path, modTime, modTime)).To(Succeed())
+}
+
+// testSetup are the files of a server and a client authenticating each other with certificates of the same CA
+type testSetup struct {
+	server Config
+	client Config
+}
+
+func newTestSetup(g *WithT, dir string) testSetup {
+	ca := newTestCA(g)
+	// The CA bundles are separate files, so the bundles of each side can be rotated on their own
+	caFile := filepath.Join(dir, "ca.crt")
+	writeFile(g, caFile, string(ca.pem))
+	clientCAFile := filepath.Join(dir, "client-ca.crt")
+	writeFile(g, clientCAFile, string(ca.pem))
+
+	serverCert, serverKey := ca.issue(g, dir, "server", x509.ExtKeyUsageServerAuth)
+	clientCert, clientKey := ca.issue(g, dir, "client", x509.ExtKeyUsageClientAuth)
+
+	return testSetup{
+		server: Config{CertFile: serverCert, KeyFile: serverKey, CAFile: clientCAFile},
+		client: Config{CertFile: clientCert, KeyFile: clientKey, CAFile: caFile},
+	}
+}
+
+func TestLoad(t *testing.T) {
+	g := NewWithT(t)
+	dir := t.TempDir()
+	setup := newTestSetup(g, dir)
+
+	_, err := Config{CertFile: setup.server.CertFile}.Load()
+	g.Expect(err).To(MatchError(ErrIncompleteKeyPair))
+
+	_, err = Config{CAFile: setup.server.CertFile + ".missing"}.Load()
+	g.Expect(err).To(HaveOccurred())
+
+	empty := filepath.Join(dir, "empty")
+	writeFile(g, empty, "\n")
+	_, err = Config{TokenFile: empty}.Load()
+	g.Expect(err).To(MatchError(ErrEmptyToken))
+
+	_, err = Config{CAFile: empty}.Load()
+	g.Expect(err).To(MatchError(ErrNoCACertificates))
+
+	creds, err := Config{}.Load()
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(creds.ServerTLS()).To(BeNil())
+	g.Expect(creds.ClientTLS()).To(BeNil())
+	g.Expect(creds.CheckToken("")).To(Succeed())
+}
+
+func TestCheckExposure(t *testing.T) {
+	tests := []struct {
+		name    string
+		config  Config
+		address string
+		tokens  bool
+		err     error
+	}{
+		{name: "loopback", address: "127.0.0.1:7332"},
+		{name: "localhost", address: "localhost:7332"},
+		{name: "IPv6 loopback", address: "[::1]:7332"},
+		{name: "all interfaces", address: ":7332", err: ErrUnauthenticated},
+		{name: "network address", address: "10.0.0.1:7332", err: ErrUnauthenticated},
+		{name: "allowed", config: Config{AllowUnauthenticated: true}, address: "0.0.0.0:7332"},
+		{name: "client certificates", config: Config{CertFile: "tls.crt", KeyFile: "tls.key", CAFile: "ca.crt"},
+			address: "0.0.0.0:7332"},
+		{name: "TLS without client certificates", config: Config{CertFile: "tls.crt", KeyFile: "tls.key"},
+			address: "0.0.0.0:7332", err: ErrUnauthenticated},
+		{name: "token", config: Config{TokenFile: "token"}, address: "0.0.0.0:7332", tokens: true},
+		{name: "token not supported", config: Config{TokenFile: "token"}, address: "0.0.0.0:7332",
+			err: ErrUnauthenticated},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			err := tt.config.CheckExposure(tt.address, tt.tokens)
+			if tt.err == nil {
+				g.Expect(err).NotTo(HaveOccurred())
+			} else {
+				g.Expect(err).To(MatchError(tt.err))
+			}
+		})
+	}
+}
+
+func TestMutualTLS(t *testing.T) {
+	g := NewWithT(t)
+	dir := t.TempDir()
+	setup := newTestSetup(g, dir)
+
+	serverCreds, err := setup.server.Load()
+	g.Expect(err).NotTo(HaveOccurred())
+
+	listener, err := serverCreds.Listen(t.Context(), "127.0.0.1:0")
+	g.Expect(err).NotTo(HaveOccurred())
+	defer func() { _ = listener.Close() }()
+
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+
+			go func() {
+				defer func() { _ = conn.Close() }()
+				_, _ = io.Copy(conn, conn)
+			}()
+		}
+	}()
+
+	dial := func(config Config) error {
+		creds, err := config.Load()
+		if err != nil {
+			return err
+		}
+
+		clientTLS, err := creds.ClientTLS()
+		if err != nil {
+			return err
+		}
+
+		conn, err := tls.Dial("tcp", listener.Addr().String(), clientTLS)
+		if err != nil {
+			return err
+		}
+		defer func() { _ = conn.Close() }()
+
+		if _, err := conn.Write([]byte("?")); err != nil {
+			return err
+		}
+
+		_, err = conn.Read(make([]byte, 1))
+
+		return err
+	}
+
+	// The server certificate is verified for the IP address dialed
+	g.Expect(dial(setup.client)).To(Succeed())
+
+	// Clients without a certificate are rejected
+	g.Expect(dial(Config{CAFile: setup.client.CAFile})).NotTo(Succeed())
+
+	// Clients verify the server certificate for the configured name
+	mismatch := setup.client
+	mismatch.ServerName = "agent"
+	g.Expect(dial(mismatch)).NotTo(Succeed())
+
+	// Certificates of another CA are rejected on both sides, until the CA bundle of the server is rotated
+	other := newTestSetup(g, t.TempDir())
+	g.Expect(dial(other.client)).NotTo(Succeed())
+
+	data, err := os.ReadFile(other.client.CAFile)
+	g.Expect(err).NotTo(HaveOccurred())
+	writeFile(g, setup.server.CAFile, string(data))
+	g.Expect(dial(Config{CertFile: other.client.CertFile, KeyFile: other.client.KeyFile,
+		CAFile: setup.client.CAFile})).To(Succeed())
+	g.Expect(dial(setup.client)).NotTo(Succeed())
+}
+
+func TestToken(t *testing.T) {
+	g := NewWithT(t)
+	tokenFile := filepath.Join(t.TempDir(), "token")
+	writeFile(g, tokenFile, "first\n")
+
+	creds, err := Config{TokenFile: tokenFile}.Load()
+	g.Expect(err).NotTo(HaveOccurred())
+
+	g.Expect(creds.CheckToken("Bearer first")).To(Succeed())
+	g.Expect(creds.CheckToken("first")).To(MatchError(ErrInvalidToken))
+	g.Expect(creds.CheckToken("")).To(MatchError(ErrInvalidToken))
+
+	// The token is rotated by replacing the file, an invalid file keeps the previous token
+	writeFile(g, tokenFile, "second")
+	g.Expect(creds.CheckToken("Bearer first")).To(MatchError(ErrInvalidToken))
+	g.Expect(creds.CheckToken("Bearer second")).To(Succeed())
+
+	writeFile(g, tokenFile, "")
+	g.Expect(creds.CheckToken("Bearer second")).To(Succeed())
+
+	server := httptest.NewServer(creds.Handler(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusNoContent)
+	})))
+	defer server.Close()
+
+	request := func(authorization string) int {
+		req, err := http.NewRequestWithContext(t.Context(), http.MethodGet, server.URL, nil)
+		g.Expect(err).NotTo(HaveOccurred())
+		if authorization != "" {
+			req.Header.Set("Authorization", authorization)
+		}
+
+		resp, err := server.Client().Do(req)
+		g.Expect(err).NotTo(HaveOccurred())
+		_ = resp.Body.Close()
+
+		return resp.StatusCode
+	}
+
+	g.Expect(request("")).To(Equal(http.StatusUnauthorized))
+	g.Expect(request("Bearer first")).To(Equal(http.StatusUnauthorized))
+	g.Expect(request("Bearer second")).To(Equal(http.StatusNoContent))
+}
+
+// testBackend serves an identity without devices
+type testBackend struct{}
+
+func (testBackend) Identity(context.Context) (*remotepb.Identity, error) {
+	return &remotepb.Identity{Name: "test"}, nil
+}
+
+func (testBackend) OpenPort(context.Context, string, *serial.Mode) (string, serial.Port, error) {
+	return "", nil, status.Error(codes.NotFound, "no devices")
+}
+
+func TestGRPC(t *testing.T) {
+	g := NewWithT(t)
+	dir := t.TempDir()
+	setup := newTestSetup(g, dir)
+
+	tokenFile := filepath.Join(dir, "token")
+	writeFile(g, tokenFile, "secret")
+
+	serverConfig := setup.server
+	serverConfig.TokenFile = tokenFile
+	serverCreds, err := serverConfig.Load()
+	g.Expect(err).NotTo(HaveOccurred())
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	g.Expect(err).NotTo(HaveOccurred())
+
+	server := grpc.NewServer(serverCreds.ServerOptions()...)
+	jumperless.NewRemoteServer(testBackend{}).Register(server)
+	healthpb.RegisterHealthServer(server, health.NewServer())
+	go func() { _ = server.Serve(listener) }()
+	defer server.Stop()
+
+	call := func(config Config) (error, error) {
+		creds, err := config.Load()
+		g.Expect(err).NotTo(HaveOccurred())
+
+		conn, err := grpc.NewClient(listener.Addr().String(), creds.DialOptions()...)
+		g.Expect(err).NotTo(HaveOccurred())
+		defer func() { _ = conn.Close() }()
+
+		ctx, cancel := context.WithTimeout(t.Context(), 5*time.Second)
+		defer cancel()
+
+		_, healthErr := healthpb.NewHealthClient(conn).Check(ctx, &healthpb.HealthCheckRequest{})
+		_, remoteErr := jumperless.NewRemoteClient(conn).Devices(ctx)
+
+		return healthErr, remoteErr
+	}
+
+	clientConfig := setup.client
+	clientConfig.TokenFile = tokenFile
+	healthErr, remoteErr := call(clientConfig)
+	g.Expect(healthErr).NotTo(HaveOccurred())
+	g.Expect(remoteErr).NotTo(HaveOccurred())
+
+	// The health service is served without a token, the devices aren't
+	healthErr, remoteErr = call(setup.client)
+	g.Expect(healthErr).NotTo(HaveOccurred())
+	g.Expect(status.Code(remoteErr)).To(Equal(codes.Unauthenticated))
+
+	// Without a client certificate the connection is rejected
+	healthErr, _ = call(Config{CAFile: setup.client.CAFile, TokenFile: tokenFile})
+	g.Expect(status.Code(healthErr)).To(Equal(codes.Unavailable))
+}
@@ -0,0 +1,57 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package version reports the build version of the manager.
+package version
+
+import (
+	"runtime/debug"
+)
+
+// Version is the version of the manager, set when building release images with
+// -ldflags "-X github.com/detiber/k8s-jumperless/internal/version.Version=<version>"
+var Version = "" //nolint:gochecknoglobals
+
+// Get returns the version the manager was built with. Builds without a version set through ldflags report the
+// module version, or the VCS revision when built from a checkout.
+func Get() string {
+	if Version != "" {
+		return Version
+	}
+
+	info, ok := debug.ReadBuildInfo()
+	if !ok {
+		return "unknown"
+	}
+
+	if info.Main.Version != "" && info.Main.Version != "(devel)" {
+		return info.Main.Version
+	}
+
+	version := "(devel)"
+	for _, setting := range info.Settings {
+		switch setting.Key {
+		case "vcs.revision":
+			version += " " + setting.Value
+		case "vcs.modified":
+			if setting.Value == "true" {
+				version += "+dirty"
+			}
+		}
+	}
+
+	return version
+}
@@ -0,0 +1,29 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v5alpha1
+
+import (
+	ctrl "sigs.k8s.io/controller-runtime"
+
+	jumperlessv5alpha1 "github.com/detiber/k8s-jumperless/api/v5alpha1"
+)
+
+// SetupJumperlessWebhookWithManager registers the conversion webhook for Jumperless in the manager. The
+// conversions are implemented by the spoke versions, v5alpha1 is the hub.
+func SetupJumperlessWebhookWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewWebhookManagedBy(mgr).For(&jumperlessv5alpha1.Jumperless{}).Complete() //nolint:wrapcheck
+}
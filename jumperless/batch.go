@@ -0,0 +1,134 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package jumperless
+
+import (
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/charmbracelet/x/ansi"
+)
+
+// batchMarker prefixes the lines printed before each command of a batch and after the last one, splitting the
+// output of the batch into the output of its commands
+const batchMarker = "--batch-"
+
+// batchEnd is printed after the last command of a batch
+const batchEnd = batchMarker + "end"
+
+// ExecPythonBatch executes MicroPython commands back-to-back as a single line and returns the output of each
+// command, parsed like the output of ExecPythonCommand. This saves a round trip and the wait for the end of the
+// output per command, e.g. when refreshing the state of a device.
+//
+// Commands must be simple statements, e.g. function calls, since they are joined with semicolons. If a command
+// raises an exception the following commands are not executed, the outputs of the commands executed before it
+// are returned along with an error wrapping ErrPythonException.
+// The batch is retried as a whole if it is marked Idempotent.
+func (j *Jumperless) ExecPythonBatch(commands []string, opts ...CommandOption) ([]string, error) {
+	if j == nil {
+		return nil, ErrNilJumperlessPort
+	}
+
+	var line strings.Builder
+	for i, command := range commands {
+		fmt.Fprintf(&line, "print(%s); %s; ", strconv.Quote(batchMarker+strconv.Itoa(i)), command)
+	}
+	fmt.Fprintf(&line, "print(%s)", strconv.Quote(batchEnd))
+
+	idempotent := newCommandOptions("", opts).idempotent
+
+	var outputs []string
+	_, err := j.retry(opts, func() (string, error) {
+		result, err := j.execRawCommand(">"+line.String(), 0, batchComplete, idempotent)
+		if err != nil {
+			return "", fmt.Errorf("failed to execute batch: %w", err)
+		}
+
+		outputs, err = splitBatchOutput(result, commands)
+
+		return "", err
+	})
+
+	return outputs, err
+}
+
+// batchComplete reports whether the output of a batch has the line printed after the last command, or the
+// exception ending a traceback
+func batchComplete(output []byte) bool {
+	lines := strings.Split(ansi.Strip(string(output)), "\r\n")
+
+	// The last element is an incomplete line, or empty if the output ends with a line break
+	complete := lines[:len(lines)-1]
+	for i, line := range complete {
+		switch strings.TrimSpace(line) {
+		case batchEnd:
+			return true
+		case pythonTraceback:
+			// The traceback lines are indented, the exception is not
+			for _, line := range complete[i+1:] {
+				if line != "" && !strings.HasPrefix(line, " ") {
+					return true
+				}
+			}
+		}
+	}
+
+	return false
+}
+
+// splitBatchOutput splits the output of a batch at the lines printed before each command. The echoed batch
+// precedes the first of them.
+func splitBatchOutput(result string, commands []string) ([]string, error) {
+	segments := [][]string{}
+	ended := false
+
+	for line := range strings.SplitSeq(ansi.Strip(result), "\r\n") {
+		trimmed := strings.TrimSpace(line)
+
+		switch {
+		case trimmed == batchEnd:
+			ended = true
+		case trimmed == batchMarker+strconv.Itoa(len(segments)):
+			segments = append(segments, []string{})
+		case len(segments) > 0 && trimmed != "" && !strings.HasPrefix(trimmed, pythonPrompt):
+			segments[len(segments)-1] = append(segments[len(segments)-1], trimmed)
+		}
+
+		if ended {
+			break
+		}
+	}
+
+	outputs := make([]string, 0, len(segments))
+	for i, lines := range segments {
+		// The last line of a traceback is the exception, e.g. "OSError: [Errno 5] EIO"
+		if slices.Contains(lines, pythonTraceback) {
+			return outputs, fmt.Errorf("%w: %s: %s", ErrPythonException, commands[i], lines[len(lines)-1])
+		}
+
+		outputs = append(outputs, strings.Join(lines, "\n"))
+	}
+
+	if !ended || len(outputs) != len(commands) {
+		return outputs, fmt.Errorf("%w: expected the output of %d commands, got %d",
+			ErrUnexpectedCommandOutput, len(commands), len(outputs))
+	}
+
+	return outputs, nil
+}
@@ -0,0 +1,81 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package jumperless
+
+import (
+	"errors"
+	"testing"
+
+	. "github.com/onsi/gomega"
+)
+
+func TestSplitBatchOutput(t *testing.T) {
+	commands := []string{"dac_get(0)", "print_nets()", "ticks_ms()"}
+	echo := "Python> \rPython> \x1b[38;5;207mprint\x1b[38;5;255m(\"--batch-0\"); dac_get(0); ...\x1b[0m\r\n"
+
+	tests := []struct {
+		name     string
+		output   string
+		complete bool
+		want     []string
+		err      error
+	}{
+		{
+			name:     "complete",
+			output:   echo + "--batch-0\r\n3.3\r\n--batch-1\r\nIndex\tName\r\n1\tGND\r\n--batch-2\r\n1234\r\n--batch-end\r\n",
+			complete: true,
+			want:     []string{"3.3", "Index\tName\n1\tGND", "1234"},
+		},
+		{
+			name:   "incomplete",
+			output: echo + "--batch-0\r\n3.3\r\n--batch-1\r\n",
+			want:   []string{"3.3", ""},
+			err:    ErrUnexpectedCommandOutput,
+		},
+		{
+			name: "exception",
+			output: echo + "--batch-0\r\n3.3\r\n--batch-1\r\n--batch-2\r\nTraceback (most recent call last):\r\n" +
+				"  File \"<stdin>\", line 1, in <module>\r\nAttributeError: 'module' object has no attribute 'ticks_ms'\r\n",
+			complete: true,
+			want:     []string{"3.3", ""},
+			err:      ErrPythonException,
+		},
+		{
+			name:   "traceback without exception",
+			output: echo + "--batch-0\r\nTraceback (most recent call last):\r\n  File \"<stdin>\", line 1\r\n",
+			want:   []string{},
+			err:    ErrPythonException,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			g.Expect(batchComplete([]byte(tt.output))).To(Equal(tt.complete))
+
+			outputs, err := splitBatchOutput(tt.output, commands)
+			g.Expect(outputs).To(Equal(tt.want))
+			if tt.err != nil {
+				g.Expect(errors.Is(err, tt.err)).To(BeTrue())
+				return
+			}
+
+			g.Expect(err).NotTo(HaveOccurred())
+		})
+	}
+}
@@ -0,0 +1,131 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package jumperless
+
+import (
+	"bytes"
+	"strings"
+
+	"github.com/charmbracelet/x/ansi"
+)
+
+// configTerminator is the last line of the config printed by the ~ command
+const configTerminator = "\nEND"
+
+// Terminators ends the output of a command once any of the terminators has been read, instead of once the
+// device stops sending output. This avoids waiting for the read timeout after every command, and slow devices
+// can't cut the output short. Passing no terminators disables the default terminators of the command.
+func Terminators(terminators ...string) CommandOption {
+	return func(o *commandOptions) {
+		o.terminators = terminators
+		o.terminatorsSet = true
+	}
+}
+
+// SingleLine ends the output of a MicroPython command once a line of output follows the echoed command, for
+// commands printing a single value such as dac_get. The REPL doesn't print a prompt after the output of a
+// command, the prompt is only printed before the next command is echoed. A traceback ends with the exception.
+func SingleLine() CommandOption {
+	return func(o *commandOptions) {
+		o.singleLine = true
+	}
+}
+
+// OptionalOutput accepts MicroPython commands that print nothing but the echoed command, such as functions
+// returning None, returning empty output instead of failing with ErrUnexpectedCommandOutput. The output is read
+// until the device stops sending output, it can't be combined with SingleLine.
+func OptionalOutput() CommandOption {
+	return func(o *commandOptions) {
+		o.optionalOutput = true
+	}
+}
+
+// defaultTerminators returns the terminators of commands whose output has a known end
+func defaultTerminators(command string) []string {
+	switch command {
+	case "~":
+		return []string{configTerminator}
+	default:
+		return nil
+	}
+}
+
+// newCommandOptions applies the options for a command, starting from the defaults of the command
+func newCommandOptions(command string, opts []CommandOption) *commandOptions {
+	options := &commandOptions{}
+	for _, opt := range opts {
+		opt(options)
+	}
+
+	if !options.terminatorsSet {
+		options.terminators = defaultTerminators(command)
+	}
+
+	return options
+}
+
+// completion returns a function reporting whether the output read so far is complete, or nil if the
+// output is read until the device stops sending output
+func (o *commandOptions) completion() func(output []byte) bool {
+	switch {
+	case o.singleLine:
+		return singleLineComplete
+	case len(o.terminators) > 0:
+		terminators := o.terminators
+		return func(output []byte) bool {
+			for _, terminator := range terminators {
+				if bytes.Contains(output, []byte(terminator)) {
+					return true
+				}
+			}
+			return false
+		}
+	default:
+		return nil
+	}
+}
+
+// singleLineComplete reports whether the output of a MicroPython command has a complete line following the
+// echoed command, or the exception ending a traceback
+func singleLineComplete(output []byte) bool {
+	lines := strings.Split(ansi.Strip(string(output)), "\r\n")
+
+	// The last element is an incomplete line, or empty if the output ends with a line break.
+	// The first line is the echoed command.
+	complete := lines[:len(lines)-1]
+	for i := 1; i < len(complete); i++ {
+		line := strings.TrimSpace(complete[i])
+		if line == "" {
+			continue
+		}
+
+		if line != pythonTraceback {
+			return true
+		}
+
+		// The traceback lines are indented, the exception is not
+		for _, line := range complete[i+1:] {
+			if line != "" && !strings.HasPrefix(line, " ") {
+				return true
+			}
+		}
+
+		return false
+	}
+
+	return false
+}
@@ -0,0 +1,114 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package jumperless
+
+import (
+	"testing"
+
+	. "github.com/onsi/gomega"
+)
+
+func TestCompletion(t *testing.T) {
+	tests := []struct {
+		name     string
+		command  string
+		opts     []CommandOption
+		output   string
+		noEnd    bool
+		complete bool
+	}{
+		{
+			name:    "no end by default",
+			command: "?",
+			output:  "Jumperless firmware version: 5.3.1.0\r\n",
+			noEnd:   true,
+		},
+		{
+			name:     "config with END",
+			command:  "~",
+			output:   "\r\nJumperless Config:\r\n`[top_oled] font = jokerman;\r\n\x1b[38;5;46m\nEND\n\r",
+			complete: true,
+		},
+		{
+			name:    "config without END",
+			command: "~",
+			output:  "\r\nJumperless Config:\r\n`[top_oled] font = jokerman;\r\n",
+		},
+		{
+			name:    "config without terminators",
+			command: "~",
+			opts:    []CommandOption{Terminators()},
+			output:  "\r\nJumperless Config:\r\n`[top_oled] font = jokerman;\r\n\nEND\n",
+			noEnd:   true,
+		},
+		{
+			name:     "custom terminator",
+			command:  "f",
+			opts:     []CommandOption{Terminators("Done", "Failed")},
+			output:   "Flashing...\r\nFailed\r\n",
+			complete: true,
+		},
+		{
+			name:    "single line echo only",
+			command: ">dac_get(0)",
+			opts:    []CommandOption{SingleLine()},
+			output:  "Python> \rPython> \x1b[38;5;207mdac_get\x1b[38;5;255m(\x1b[38;5;199m0\x1b[38;5;255m)\x1b[0m\r\n",
+		},
+		{
+			name:    "single line partial value",
+			command: ">dac_get(0)",
+			opts:    []CommandOption{SingleLine()},
+			output:  "Python> >dac_get(0)\r\n3.3",
+		},
+		{
+			name:     "single line value",
+			command:  ">dac_get(0)",
+			opts:     []CommandOption{SingleLine()},
+			output:   "Python> >dac_get(0)\r\n3.33\r\n",
+			complete: true,
+		},
+		{
+			name:    "single line traceback",
+			command: ">dac_get(7)",
+			opts:    []CommandOption{SingleLine()},
+			output:  "Python> >dac_get(7)\r\nTraceback (most recent call last):\r\n  File \"<stdin>\", line 1, in <module>\r\n",
+		},
+		{
+			name:    "single line exception",
+			command: ">dac_get(7)",
+			opts:    []CommandOption{SingleLine()},
+			output: "Python> >dac_get(7)\r\nTraceback (most recent call last):\r\n" +
+				"  File \"<stdin>\", line 1, in <module>\r\nValueError: invalid channel\r\n",
+			complete: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			complete := newCommandOptions(tt.command, tt.opts).completion()
+			if tt.noEnd {
+				g.Expect(complete).To(BeNil())
+				return
+			}
+
+			g.Expect(complete).NotTo(BeNil())
+			g.Expect(complete([]byte(tt.output))).To(Equal(tt.complete))
+		})
+	}
+}
@@ -0,0 +1,131 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package jumperless
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"time"
+)
+
+var ErrConsoleAttached = errors.New("console attached to serial port")
+
+// consoleCommand is the command reported by the port diagnostics while a console is attached
+const consoleCommand = "<console>"
+
+// consoleReadTimeout bounds each read of the port while a console is attached, so input is forwarded to the
+// device and cancellation is noticed between reads
+const consoleReadTimeout = 20 * time.Millisecond
+
+// Console attaches rw to the raw console of the device until ctx is done or reading rw fails, e.g. because the
+// other end closed it. Everything read from rw is written to the port and everything the device outputs is
+// written to rw. Only one console can be attached at a time, while it is attached other commands fail with
+// ErrConsoleAttached rather than waiting for the console to detach. A read of rw that is still blocked once
+// the console detached returns its data to nobody, so callers close rw once Console returns.
+func (p *JumperlessPort) Console(ctx context.Context, rw io.ReadWriter) (err error) {
+	if p == nil {
+		return ErrNilJumperlessPort
+	}
+
+	if p.port == nil {
+		return ErrUninitializedSerialPort
+	}
+
+	if !p.console.CompareAndSwap(false, true) {
+		return fmt.Errorf("%w: %s", ErrConsoleAttached, p.portName)
+	}
+	defer p.console.Store(false)
+
+	p.diagnostics.queue()
+	p.portLock.Lock()
+	defer p.portLock.Unlock()
+
+	p.diagnostics.start(consoleCommand)
+	defer func() { p.diagnostics.finish(err) }()
+
+	if err := p.port.SetReadTimeout(consoleReadTimeout); err != nil {
+		return fmt.Errorf("unable to set read timeout on serial port %s: %w", p.portName, err)
+	}
+
+	done := make(chan struct{})
+	defer close(done)
+
+	input := make(chan []byte)
+	inputErr := make(chan error, 1)
+	go func() {
+		buff := make([]byte, 1024)
+		for {
+			n, err := rw.Read(buff)
+			if n > 0 {
+				select {
+				case input <- bytes.Clone(buff[:n]):
+				case <-done:
+					return
+				}
+			}
+
+			if err != nil {
+				inputErr <- err
+				return
+			}
+		}
+	}()
+
+	buff := make([]byte, 1024)
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case err := <-inputErr:
+			if errors.Is(err, io.EOF) {
+				return nil
+			}
+
+			return fmt.Errorf("unable to read console input: %w", err)
+		case data := <-input:
+			if _, err := p.port.Write(data); err != nil {
+				return fmt.Errorf("unable to write to serial port %s: %w", p.portName, err)
+			}
+		default:
+		}
+
+		n, err := p.port.Read(buff)
+		if err != nil {
+			return fmt.Errorf("%w %s: %w", ErrSerialRead, p.portName, err)
+		}
+
+		if n == 0 {
+			continue
+		}
+
+		if _, err := rw.Write(buff[:n]); err != nil {
+			return fmt.Errorf("unable to write console output: %w", err)
+		}
+	}
+}
+
+// Console attaches rw to the raw console of the device, see JumperlessPort.Console
+func (j *Jumperless) Console(ctx context.Context, rw io.ReadWriter) error {
+	if j == nil || j.port == nil {
+		return ErrNilJumperlessPort
+	}
+
+	return j.port.Console(ctx, rw)
+}
@@ -0,0 +1,106 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package jumperless
+
+import (
+	"context"
+	"io"
+	"net"
+	"testing"
+	"time"
+
+	. "github.com/onsi/gomega"
+
+	"github.com/detiber/k8s-jumperless/jumperless/serialmock"
+)
+
+func TestConsole(t *testing.T) {
+	g := NewWithT(t)
+
+	port := serialmock.New()
+	port.Expect("?").Respond("Jumperless firmware version: 5.3.1.0\r\n")
+
+	j, err := NewJumperlessWithOpener("/dev/ttyMock", 0, port.Open)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(j.OpenPort()).To(Succeed())
+
+	client, server := net.Pipe()
+	defer func() { _ = client.Close() }()
+
+	consoleErr := make(chan error, 1)
+	go func() {
+		consoleErr <- j.Console(context.Background(), server)
+		_ = server.Close()
+	}()
+
+	g.Eventually(func() string { return j.port.state().Command }).Should(Equal(consoleCommand))
+
+	// Input is forwarded to the device and its output to the console
+	port.Expect("help\r\n").Respond("usage: help\r\n")
+	_, err = client.Write([]byte("help\r\n"))
+	g.Expect(err).NotTo(HaveOccurred())
+
+	output := make([]byte, len("usage: help\r\n"))
+	g.Expect(client.SetReadDeadline(time.Now().Add(time.Second))).To(Succeed())
+	_, err = io.ReadFull(client, output)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(string(output)).To(Equal("usage: help\r\n"))
+
+	// Commands fail while the console is attached
+	_, err = j.ExecRawCommand("?", 0)
+	g.Expect(err).To(MatchError(ErrConsoleAttached))
+	g.Expect(j.SetDTR(false)).To(MatchError(ErrConsoleAttached))
+	g.Expect(j.Console(context.Background(), client)).To(MatchError(ErrConsoleAttached))
+
+	// The console detaches once its input is closed
+	g.Expect(client.Close()).To(Succeed())
+	g.Eventually(consoleErr).Should(Receive(BeNil()))
+
+	port.Expect("?").Respond("Jumperless firmware version: 5.3.1.0\r\n")
+	_, err = j.ExecRawCommand("?", 0)
+	g.Expect(err).NotTo(HaveOccurred())
+
+	g.Expect(port.ExpectationsMet()).To(Succeed())
+	g.Expect(j.ClosePort()).To(Succeed())
+}
+
+func TestConsoleCanceled(t *testing.T) {
+	g := NewWithT(t)
+
+	port := serialmock.New()
+	port.Expect("?").Respond("Jumperless firmware version: 5.3.1.0\r\n")
+
+	j, err := NewJumperlessWithOpener("/dev/ttyMock", 0, port.Open)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(j.OpenPort()).To(Succeed())
+
+	client, server := net.Pipe()
+	defer func() { _ = client.Close() }()
+	defer func() { _ = server.Close() }()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	consoleErr := make(chan error, 1)
+	go func() { consoleErr <- j.Console(ctx, server) }()
+
+	g.Eventually(func() string { return j.port.state().Command }).Should(Equal(consoleCommand))
+
+	cancel()
+	g.Eventually(consoleErr).Should(Receive(BeNil()))
+	g.Expect(j.port.state().Command).To(BeEmpty())
+
+	g.Expect(j.ClosePort()).To(Succeed())
+}
@@ -0,0 +1,132 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package jumperless
+
+import (
+	"fmt"
+	"time"
+
+	"go.bug.st/serial"
+)
+
+// PulseDTR drops the DTR line of an open serial port for the duration and raises it again, then waits for
+// the duration so the device can wake up or finish resetting before it is sent commands.
+func PulseDTR(port serial.Port, duration time.Duration) error {
+	if err := port.SetDTR(false); err != nil {
+		return fmt.Errorf("unable to drop DTR: %w", err)
+	}
+
+	time.Sleep(duration)
+
+	if err := port.SetDTR(true); err != nil {
+		return fmt.Errorf("unable to raise DTR: %w", err)
+	}
+
+	time.Sleep(duration)
+
+	return nil
+}
+
+// SetDTR sets the DTR line of the open port
+func (p *JumperlessPort) SetDTR(dtr bool) error {
+	return p.withPort(func(port serial.Port) error {
+		return port.SetDTR(dtr) //nolint:wrapcheck
+	})
+}
+
+// SetRTS sets the RTS line of the open port
+func (p *JumperlessPort) SetRTS(rts bool) error {
+	return p.withPort(func(port serial.Port) error {
+		return port.SetRTS(rts) //nolint:wrapcheck
+	})
+}
+
+// SendBreak sends a break of the given duration on the open port
+func (p *JumperlessPort) SendBreak(duration time.Duration) error {
+	return p.withPort(func(port serial.Port) error {
+		return port.Break(duration) //nolint:wrapcheck
+	})
+}
+
+// PulseDTR pulses the DTR line of the open port, see PulseDTR
+func (p *JumperlessPort) PulseDTR(duration time.Duration) error {
+	return p.withPort(func(port serial.Port) error {
+		return PulseDTR(port, duration)
+	})
+}
+
+// withPort calls fn with the open port while holding the port lock, so control lines don't change while
+// a command is executed
+func (p *JumperlessPort) withPort(fn func(port serial.Port) error) error {
+	if p == nil {
+		return ErrNilJumperlessPort
+	}
+
+	if p.console.Load() {
+		return fmt.Errorf("%w: %s", ErrConsoleAttached, p.portName)
+	}
+
+	p.portLock.Lock()
+	defer p.portLock.Unlock()
+
+	if p.port == nil {
+		return ErrPortNotOpen
+	}
+
+	if err := fn(p.port); err != nil {
+		return fmt.Errorf("unable to control serial port %s: %w", p.portName, err)
+	}
+
+	return nil
+}
+
+// SetDTR sets the DTR line of the device port
+func (j *Jumperless) SetDTR(dtr bool) error {
+	if j == nil || j.port == nil {
+		return ErrNilJumperlessPort
+	}
+
+	return j.port.SetDTR(dtr)
+}
+
+// SetRTS sets the RTS line of the device port
+func (j *Jumperless) SetRTS(rts bool) error {
+	if j == nil || j.port == nil {
+		return ErrNilJumperlessPort
+	}
+
+	return j.port.SetRTS(rts)
+}
+
+// SendBreak sends a break of the given duration on the device port
+func (j *Jumperless) SendBreak(duration time.Duration) error {
+	if j == nil || j.port == nil {
+		return ErrNilJumperlessPort
+	}
+
+	return j.port.SendBreak(duration)
+}
+
+// SetConnectDTRPulse makes OpenPort pulse the DTR line for the duration after opening the port, for host
+// setups that require toggling DTR to wake or reset the device. Zero disables the pulse.
+func (j *Jumperless) SetConnectDTRPulse(duration time.Duration) {
+	if j == nil {
+		return
+	}
+
+	j.connectDTRPulse = duration
+}
@@ -0,0 +1,40 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package jumperless
+
+import (
+	"testing"
+	"time"
+
+	. "github.com/onsi/gomega"
+)
+
+func TestControlLinesRequireOpenPort(t *testing.T) {
+	g := NewWithT(t)
+
+	port := &JumperlessPort{portName: "/dev/ttyACM0"}
+	g.Expect(port.SetDTR(true)).To(MatchError(ErrPortNotOpen))
+	g.Expect(port.SetRTS(false)).To(MatchError(ErrPortNotOpen))
+	g.Expect(port.SendBreak(time.Millisecond)).To(MatchError(ErrPortNotOpen))
+	g.Expect(port.PulseDTR(time.Millisecond)).To(MatchError(ErrPortNotOpen))
+
+	var j *Jumperless
+	g.Expect(j.SetDTR(true)).To(MatchError(ErrNilJumperlessPort))
+	g.Expect(j.SetRTS(true)).To(MatchError(ErrNilJumperlessPort))
+	g.Expect(j.SendBreak(time.Millisecond)).To(MatchError(ErrNilJumperlessPort))
+	j.SetConnectDTRPulse(time.Millisecond)
+}
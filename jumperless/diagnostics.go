@@ -0,0 +1,160 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package jumperless
+
+import (
+	"maps"
+	"slices"
+	"sync"
+	"time"
+)
+
+// PortState is a snapshot of a shared serial port, used to debug commands that do not complete
+type PortState struct {
+	// Port is the name of the serial port
+	Port         string `json:"port"`
+	Version      string `json:"version,omitempty"`
+	SerialNumber string `json:"serialNumber,omitempty"`
+
+	// Open is whether the serial port is open
+	Open bool `json:"open"`
+
+	// Handles is the number of PortHandles referencing the port
+	Handles int `json:"handles"`
+
+	// Waiting is the number of commands waiting for the command in progress to complete
+	Waiting int `json:"waiting"`
+
+	// Command is the command in progress, if any, along with when it was written
+	Command        string     `json:"command,omitempty"`
+	CommandStarted *time.Time `json:"commandStarted,omitempty"`
+
+	// Commands is the number of commands executed on the port
+	Commands int64 `json:"commands"`
+
+	// LastError is the last error returned by a command, along with when it happened
+	LastError     string     `json:"lastError,omitempty"`
+	LastErrorTime *time.Time `json:"lastErrorTime,omitempty"`
+}
+
+// portDiagnostics tracks the commands executed on a port. It has its own lock since the port lock is held
+// for the whole duration of a command.
+type portDiagnostics struct {
+	lock           sync.Mutex
+	open           bool
+	waiting        int
+	command        string
+	commandStarted time.Time
+	commands       int64
+	lastError      error
+	lastErrorTime  time.Time
+}
+
+// setOpen records whether the serial port is open
+func (d *portDiagnostics) setOpen(open bool) {
+	d.lock.Lock()
+	defer d.lock.Unlock()
+
+	d.open = open
+}
+
+// queue records a command waiting for the port lock
+func (d *portDiagnostics) queue() {
+	d.lock.Lock()
+	defer d.lock.Unlock()
+
+	d.waiting++
+}
+
+// start records a queued command that acquired the port lock
+func (d *portDiagnostics) start(command string) {
+	d.lock.Lock()
+	defer d.lock.Unlock()
+
+	d.waiting--
+	d.command = command
+	d.commandStarted = time.Now()
+	d.commands++
+}
+
+// finish records the completion of the command in progress
+func (d *portDiagnostics) finish(err error) {
+	d.lock.Lock()
+	defer d.lock.Unlock()
+
+	d.command = ""
+	d.commandStarted = time.Time{}
+	if err != nil {
+		d.lastError = err
+		d.lastErrorTime = time.Now()
+	}
+}
+
+// fail records an error of a command that already finished, e.g. once retries are exhausted
+func (d *portDiagnostics) fail(err error) {
+	d.lock.Lock()
+	defer d.lock.Unlock()
+
+	d.lastError = err
+	d.lastErrorTime = time.Now()
+}
+
+// state returns the state of the port
+func (p *JumperlessPort) state() PortState {
+	p.diagnostics.lock.Lock()
+	defer p.diagnostics.lock.Unlock()
+
+	d := &p.diagnostics
+	state := PortState{
+		Port:         p.portName,
+		Version:      p.version,
+		SerialNumber: p.serialNumber,
+		Open:         d.open,
+		Waiting:      d.waiting,
+		Command:      d.command,
+		Commands:     d.commands,
+	}
+
+	if d.command != "" {
+		state.CommandStarted = &d.commandStarted
+	}
+	if d.lastError != nil {
+		state.LastError = d.lastError.Error()
+		state.LastErrorTime = &d.lastErrorTime
+	}
+
+	return state
+}
+
+// State returns the state of the ports referenced by handles, sorted by port name. Unlike the other
+// methods of PortManager it does not wait for ports being probed or opened, so it can be used to debug
+// a PortManager that is stuck.
+func (m *PortManager) State() []PortState {
+	m.stateLock.Lock()
+	defer m.stateLock.Unlock()
+
+	states := []PortState{}
+	for _, key := range slices.Sorted(maps.Keys(m.ports)) {
+		shared := m.ports[key]
+
+		state := shared.jumperless.port.state()
+		state.Handles = shared.refs
+		states = append(states, state)
+	}
+
+	return states
+}
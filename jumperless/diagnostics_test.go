@@ -0,0 +1,71 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package jumperless
+
+import (
+	"errors"
+	"testing"
+
+	. "github.com/onsi/gomega"
+)
+
+var errTest = errors.New("test error")
+
+func TestPortManagerState(t *testing.T) {
+	g := NewWithT(t)
+
+	port := &JumperlessPort{portName: "/dev/ttyACM1", version: "5.3.1.0"}
+	port.diagnostics.setOpen(true)
+
+	m := NewPortManager()
+	m.ports["/dev/ttyACM1"] = &sharedPort{jumperless: &Jumperless{port: port}, refs: 2}
+	m.ports["/dev/ttyACM0"] = &sharedPort{jumperless: &Jumperless{port: &JumperlessPort{portName: "/dev/ttyACM0"}}, refs: 1}
+
+	// A command in progress with another one waiting for it
+	port.diagnostics.queue()
+	port.diagnostics.start("?")
+	port.diagnostics.queue()
+
+	states := m.State()
+	g.Expect(states).To(HaveLen(2))
+	g.Expect(states[0].Port).To(Equal("/dev/ttyACM0"))
+	g.Expect(states[0].Open).To(BeFalse())
+
+	state := states[1]
+	g.Expect(state.Port).To(Equal("/dev/ttyACM1"))
+	g.Expect(state.Version).To(Equal("5.3.1.0"))
+	g.Expect(state.Open).To(BeTrue())
+	g.Expect(state.Handles).To(Equal(2))
+	g.Expect(state.Waiting).To(Equal(1))
+	g.Expect(state.Command).To(Equal("?"))
+	g.Expect(state.CommandStarted).NotTo(BeNil())
+	g.Expect(state.Commands).To(Equal(int64(1)))
+	g.Expect(state.LastError).To(BeEmpty())
+
+	// The waiting command fails
+	port.diagnostics.finish(nil)
+	port.diagnostics.start("~")
+	port.diagnostics.finish(errTest)
+
+	state = m.State()[1]
+	g.Expect(state.Waiting).To(Equal(0))
+	g.Expect(state.Command).To(BeEmpty())
+	g.Expect(state.CommandStarted).To(BeNil())
+	g.Expect(state.Commands).To(Equal(int64(2)))
+	g.Expect(state.LastError).To(Equal(errTest.Error()))
+	g.Expect(state.LastErrorTime).NotTo(BeNil())
+}
@@ -17,29 +17,69 @@ limitations under the License.
 package jumperless
 
 import (
+	"bytes"
+	"context"
 	"errors"
 	"fmt"
 	"strings"
 	"sync"
+	"sync/atomic"
 	"time"
 
+	"github.com/charmbracelet/x/ansi"
 	"go.bug.st/serial"
+	"k8s.io/apimachinery/pkg/util/wait"
 )
 
 var ErrNilJumperlessPort = errors.New("called method on nil JumperlessPort")
 var ErrUninitializedSerialPort = errors.New("serial port uninitialized")
 var ErrPortAlreadyOpen = errors.New("serial port already open")
 var ErrPortNotOpen = errors.New("serial port not open")
+var ErrDeviceBusy = errors.New("device busy")
+var ErrCommandIncomplete = errors.New("command did not complete")
+var ErrSerialRead = errors.New("unable to read from serial port")
+
+// completionTimeout bounds waiting for the output of a command with a known end to complete
+const completionTimeout = 2 * time.Second
+
+// longCommandReadTimeout bounds each read of a long-running command so that cancellation is noticed between reads
+const longCommandReadTimeout = 100 * time.Millisecond
+
+// busyMarker is the line the firmware sends instead of executing a command while it is busy or its prompt
+// is not ready to accept a new command.
+const busyMarker = "busy"
+
+// defaultBusyBackoff returns the backoff used when retrying commands while the device is busy.
+func defaultBusyBackoff() wait.Backoff {
+	return wait.Backoff{
+		Duration: 50 * time.Millisecond,
+		Factor:   2.0,
+		Jitter:   0.1,
+		Steps:    5,
+	}
+}
+
+// Opener opens a serial port, e.g. OpenSerialPort or the Open method of a serialmock.Port
+type Opener func(portName string, mode *serial.Mode) (serial.Port, error)
 
 type JumperlessPort struct {
-	portName string
-	portLock sync.Mutex
-	port     serial.Port
-	mode     *serial.Mode
-	version  string
+	portName     string
+	portLock     sync.Mutex
+	port         serial.Port
+	opener       Opener      // Opens the port instead of OpenSerialPort if set, without locking the device node
+	lock         *DeviceLock // Held while the port is open
+	mode         *serial.Mode
+	version      string
+	serialNumber string
+	diagnostics  portDiagnostics
+	console      atomic.Bool // Set while a console is attached, see Console
 }
 
 func NewJumperlessPort(portName string, baudRate int) (*JumperlessPort, error) {
+	return newJumperlessPort(portName, baudRate, nil)
+}
+
+func newJumperlessPort(portName string, baudRate int, opener Opener) (*JumperlessPort, error) {
 	if baudRate == 0 {
 		baudRate = 115200
 	}
@@ -50,6 +90,7 @@ func NewJumperlessPort(portName string, baudRate int) (*JumperlessPort, error) {
 
 	j := &JumperlessPort{
 		portName: portName,
+		opener:   opener,
 		mode:     mode,
 	}
 
@@ -77,19 +118,40 @@ func (p *JumperlessPort) Open() error {
 		return ErrNilJumperlessPort
 	}
 
+	p.portLock.Lock()
+	defer p.portLock.Unlock()
+
 	if p.port != nil {
 		return ErrPortAlreadyOpen
 	}
 
-	p.portLock.Lock()
-	defer p.portLock.Unlock()
+	if p.opener != nil {
+		port, err := p.opener(p.portName, p.mode)
+		if err != nil {
+			return fmt.Errorf("unable to open serial port %s: %w", p.portName, err)
+		}
+
+		p.port = port
+		p.diagnostics.setOpen(true)
+
+		return nil
+	}
+
+	lock, err := LockDevice(p.portName)
+	if err != nil {
+		return err
+	}
 
-	port, err := serial.Open(p.portName, p.mode)
+	port, err := OpenSerialPort(p.portName, p.mode)
 	if err != nil {
+		_ = lock.Unlock()
 		return fmt.Errorf("unable to open serial port %s: %w", p.portName, err)
 	}
 
 	p.port = port
+	p.lock = lock
+	p.diagnostics.setOpen(true)
+
 	return nil
 }
 
@@ -98,13 +160,13 @@ func (p *JumperlessPort) Close() error {
 		return ErrNilJumperlessPort
 	}
 
+	p.portLock.Lock()
+	defer p.portLock.Unlock()
+
 	if p.port == nil {
 		return ErrPortNotOpen
 	}
 
-	p.portLock.Lock()
-	defer p.portLock.Unlock()
-
 	// If there's no closePort but the port is open, attempt to close it directly
 	if err := p.port.Close(); err != nil {
 		return fmt.Errorf("unable to close serial port %s: %w", p.portName, err)
@@ -112,12 +174,19 @@ func (p *JumperlessPort) Close() error {
 
 	// Clear the port reference
 	p.port = nil
+	p.diagnostics.setOpen(false)
+
+	lock := p.lock
+	p.lock = nil
+	if err := lock.Unlock(); err != nil {
+		return err
+	}
 
 	return nil
 }
 
 func (p *JumperlessPort) isJumperlessPort() (bool, string, error) {
-	result, err := p.execRawCommand("?", 10*time.Millisecond)
+	result, err := p.execRawCommand("?", 10*time.Millisecond, nil)
 	if err != nil {
 		return false, "", fmt.Errorf("failed to execute command: %w", err)
 	}
@@ -132,7 +201,57 @@ func (p *JumperlessPort) isJumperlessPort() (bool, string, error) {
 	return false, "", nil
 }
 
-func (p *JumperlessPort) execRawCommand(command string, waitForRead time.Duration) (string, error) {
+// isBusyResponse reports whether the device rejected the command as busy. Only the first line following the
+// prompt and the echoed command is compared to the busy marker, so output of the command that merely starts
+// with or contains it isn't mistaken for it.
+func isBusyResponse(command, response string) bool {
+	command = strings.TrimSpace(command)
+	for line := range strings.SplitSeq(ansi.Strip(response), "\n") {
+		trimmed := strings.TrimSpace(line)
+		if trimmed == "" || trimmed == command || strings.HasPrefix(trimmed, pythonPrompt) {
+			continue
+		}
+
+		return strings.EqualFold(trimmed, busyMarker)
+	}
+
+	return false
+}
+
+// execRawCommandWithRetry executes a command, retrying idempotent commands with backoff while the device
+// reports it is busy. Other commands are not sent again, since it can't be told whether the device executed
+// them. If the device is busy and the command isn't retried or the backoff is exhausted, ErrDeviceBusy is
+// returned rather than the partial output so that it does not reach parsers downstream.
+func (p *JumperlessPort) execRawCommandWithRetry(command string, waitForRead time.Duration,
+	complete func([]byte) bool, idempotent bool) (string, error) {
+	backoff := defaultBusyBackoff()
+
+	for {
+		result, err := p.execRawCommand(command, waitForRead, complete)
+		if err != nil {
+			return "", err
+		}
+
+		if !isBusyResponse(command, result) {
+			return result, nil
+		}
+
+		if !idempotent || backoff.Steps <= 1 {
+			err := fmt.Errorf("command %q not accepted by device on port %s: %w", command, p.portName, ErrDeviceBusy)
+			p.diagnostics.fail(err)
+
+			return "", err
+		}
+
+		time.Sleep(backoff.Step())
+	}
+}
+
+// execRawCommand writes a command and returns its output. Without complete the output is read after waiting
+// for waitForRead, until a read returns no data. Otherwise reads start right away and the output is returned
+// as soon as complete reports it complete, or ErrCommandIncomplete after completionTimeout.
+func (p *JumperlessPort) execRawCommand(command string, waitForRead time.Duration,
+	complete func([]byte) bool) (_ string, err error) {
 	if p == nil {
 		return "", ErrNilJumperlessPort
 	}
@@ -141,9 +260,17 @@ func (p *JumperlessPort) execRawCommand(command string, waitForRead time.Duratio
 		return "", ErrUninitializedSerialPort
 	}
 
+	if p.console.Load() {
+		return "", fmt.Errorf("%w: %s", ErrConsoleAttached, p.portName)
+	}
+
+	p.diagnostics.queue()
 	p.portLock.Lock()
 	defer p.portLock.Unlock()
 
+	p.diagnostics.start(command)
+	defer func() { p.diagnostics.finish(err) }()
+
 	// Reset input and output buffers to ensure clean state
 	if err := p.port.ResetInputBuffer(); err != nil {
 		return "", fmt.Errorf("unable to reset input buffer: %w", err)
@@ -161,6 +288,10 @@ func (p *JumperlessPort) execRawCommand(command string, waitForRead time.Duratio
 		return "", fmt.Errorf("failed to drain serial port: %s: %w", p.portName, err)
 	}
 
+	if complete != nil {
+		return p.readUntilComplete(command, complete)
+	}
+
 	if err := p.port.SetReadTimeout(time.Second); err != nil {
 		return "", fmt.Errorf("unable to set read timeout on serial port %s: %w", p.portName, err)
 	}
@@ -173,7 +304,7 @@ func (p *JumperlessPort) execRawCommand(command string, waitForRead time.Duratio
 	for {
 		n, err := p.port.Read(buff)
 		if err != nil {
-			return "", fmt.Errorf("unable to read from serial port %s: %w", p.portName, err)
+			return "", fmt.Errorf("%w %s: %w", ErrSerialRead, p.portName, err)
 		}
 
 		if n == 0 {
@@ -185,3 +316,113 @@ func (p *JumperlessPort) execRawCommand(command string, waitForRead time.Duratio
 
 	return result, nil
 }
+
+// readUntilComplete reads the output of a command until complete reports it complete. Must be called with
+// portLock held.
+func (p *JumperlessPort) readUntilComplete(command string, complete func([]byte) bool) (string, error) {
+	if err := p.port.SetReadTimeout(longCommandReadTimeout); err != nil {
+		return "", fmt.Errorf("unable to set read timeout on serial port %s: %w", p.portName, err)
+	}
+
+	deadline := time.Now().Add(completionTimeout)
+	result := []byte{}
+
+	buff := make([]byte, 128)
+	for !complete(result) {
+		if time.Now().After(deadline) {
+			return "", fmt.Errorf("%w: %q on port %s: no end of output after %s",
+				ErrCommandIncomplete, command, p.portName, completionTimeout)
+		}
+
+		n, err := p.port.Read(buff)
+		if err != nil {
+			return "", fmt.Errorf("%w %s: %w", ErrSerialRead, p.portName, err)
+		}
+
+		result = append(result, buff[:n]...)
+	}
+
+	return string(result), nil
+}
+
+// execLongCommand writes a command and passes the output to onChunk as it is read, until any of the
+// terminators is read or ctx is done. Without terminators the output is read until ctx is done.
+func (p *JumperlessPort) execLongCommand(ctx context.Context, command string,
+	onChunk func([]byte), terminators []string) (err error) {
+	if p == nil {
+		return ErrNilJumperlessPort
+	}
+
+	if p.port == nil {
+		return ErrUninitializedSerialPort
+	}
+
+	if p.console.Load() {
+		return fmt.Errorf("%w: %s", ErrConsoleAttached, p.portName)
+	}
+
+	p.diagnostics.queue()
+	p.portLock.Lock()
+	defer p.portLock.Unlock()
+
+	p.diagnostics.start(command)
+	defer func() { p.diagnostics.finish(err) }()
+
+	// Reset input and output buffers to ensure clean state
+	if err := p.port.ResetInputBuffer(); err != nil {
+		return fmt.Errorf("unable to reset input buffer: %w", err)
+	}
+
+	if err := p.port.ResetOutputBuffer(); err != nil {
+		return fmt.Errorf("unable to reset output buffer: %w", err)
+	}
+
+	if _, err := p.port.Write([]byte(command)); err != nil {
+		return fmt.Errorf("unable to write to serial port %s: %w", p.portName, err)
+	}
+
+	if err := p.port.Drain(); err != nil {
+		return fmt.Errorf("failed to drain serial port: %s: %w", p.portName, err)
+	}
+
+	if err := p.port.SetReadTimeout(longCommandReadTimeout); err != nil {
+		return fmt.Errorf("unable to set read timeout on serial port %s: %w", p.portName, err)
+	}
+
+	// Keep enough of the previous output to find a terminator split across reads
+	keep := 0
+	for _, terminator := range terminators {
+		keep = max(keep, len(terminator)-1)
+	}
+	tail := []byte{}
+
+	buff := make([]byte, 1024)
+	for {
+		if err := ctx.Err(); err != nil {
+			if len(terminators) == 0 {
+				return nil
+			}
+			return fmt.Errorf("%w: %q on port %s: %w", ErrCommandIncomplete, command, p.portName, err)
+		}
+
+		n, err := p.port.Read(buff)
+		if err != nil {
+			return fmt.Errorf("%w %s: %w", ErrSerialRead, p.portName, err)
+		}
+
+		if n == 0 {
+			continue // No data within the read timeout, keep waiting
+		}
+
+		onChunk(bytes.Clone(buff[:n]))
+
+		tail = append(tail, buff[:n]...)
+		for _, terminator := range terminators {
+			if bytes.Contains(tail, []byte(terminator)) {
+				return nil
+			}
+		}
+
+		tail = tail[max(len(tail)-keep, 0):]
+	}
+}
@@ -0,0 +1,137 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package jumperless
+
+import (
+	"testing"
+	"time"
+
+	. "github.com/onsi/gomega"
+
+	"github.com/detiber/k8s-jumperless/jumperless/serialmock"
+)
+
+func TestJumperlessPortWithMock(t *testing.T) {
+	g := NewWithT(t)
+
+	port := serialmock.New()
+	port.Expect("?").Respond("Jumperless firmware version: 5.3.1.0\r\n")
+
+	j, err := NewJumperlessWithOpener("/dev/ttyMock", 0, port.Open)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(j.GetVersion()).To(Equal("5.3.1.0"))
+	g.Expect(port.Mode().BaudRate).To(Equal(115200))
+	g.Expect(port.IsOpen()).To(BeFalse())
+
+	policy := DefaultRetryPolicy()
+	policy.Backoff.Duration = 0
+	j.SetRetryPolicy(policy)
+
+	// Reconnecting after the device was unplugged
+	port.FailOpen(errTest)
+	g.Expect(j.OpenPort()).To(MatchError(errTest))
+	g.Expect(j.OpenPort()).To(Succeed())
+	g.Expect(port.Opens()).To(Equal(3))
+
+	// Retrying an idempotent command after a failed read
+	port.Expect(">dac_get(0)").FailRead(errTest)
+	port.Expect(">dac_get(0)").Respond("Python> >dac_get(0)\r\n3.3\r\n")
+	result, err := j.ExecPythonCommand("dac_get(0)", 0, Idempotent(), SingleLine())
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(result).To(Equal("3.3"))
+
+	// Reading the output until its terminator while it is still being sent
+	port.Expect("~").
+		Respond("\r\nJumperless Config:\r\n").
+		RespondAfter(20*time.Millisecond, "`[top_oled] font = jokerman;\r\nEND\n")
+	result, err = j.ExecRawCommand("~", 0)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(result).To(HaveSuffix("END\n"))
+
+	// Failing once the end of the output doesn't arrive in time
+	port.Expect("~").Respond("\r\nJumperless Config:\r\n")
+	_, err = j.ExecRawCommand("~", 0)
+	g.Expect(err).To(MatchError(ErrCommandIncomplete))
+
+	g.Expect(j.SetDTR(false)).To(Succeed())
+	g.Expect(port.DTR()).To(BeFalse())
+
+	g.Expect(j.ClosePort()).To(Succeed())
+	g.Expect(port.ExpectationsMet()).To(Succeed())
+	g.Expect(port.Writes()).To(Equal([]string{"?", ">dac_get(0)", ">dac_get(0)", "~", "~"}))
+}
+
+func TestIsBusyResponse(t *testing.T) {
+	tests := []struct {
+		name     string
+		command  string
+		response string
+		want     bool
+	}{
+		{name: "busy marker", command: "~", response: "\r\nbusy\r\n", want: true},
+		{name: "busy marker after prompt and echo", command: ">dac_get(0)",
+			response: "Python> >dac_get(0)\r\nBusy\r\n", want: true},
+		{name: "busy marker with ANSI codes", command: ">nodes_clear()",
+			response: "\x1b[32mPython>\x1b[0m >nodes_clear()\r\n\x1b[31mbusy\x1b[0m\r\n", want: true},
+		{name: "output", command: ">dac_get(0)", response: "Python> >dac_get(0)\r\n3.3\r\n"},
+		{name: "output starting with the marker", command: ">print(\"Busy rail\")",
+			response: "Python> >print(\"Busy rail\")\r\nBusy rail\r\n"},
+		{name: "marker after the first line of output", command: "~",
+			response: "\r\nJumperless Config:\r\nbusy\r\nEND\n"},
+		{name: "menu line", command: "m", response: "\r\nnot ready yet? please wait\r\n"},
+		{name: "empty", command: "?", response: ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+			g.Expect(isBusyResponse(tt.command, tt.response)).To(Equal(tt.want))
+		})
+	}
+}
+
+func TestBusyRetry(t *testing.T) {
+	g := NewWithT(t)
+
+	port := serialmock.New()
+	port.Expect("?").Respond("Jumperless firmware version: 5.3.1.0\r\n")
+
+	j, err := NewJumperlessWithOpener("/dev/ttyMock", 0, port.Open)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(j.OpenPort()).To(Succeed())
+	defer func() { _ = j.ClosePort() }()
+
+	// Retrying an idempotent command until the device accepts it
+	port.Expect(">dac_get(0)").Respond("Python> >dac_get(0)\r\nbusy\r\n")
+	port.Expect(">dac_get(0)").Respond("Python> >dac_get(0)\r\n3.3\r\n")
+	result, err := j.ExecPythonCommand("dac_get(0)", 0, Idempotent(), SingleLine())
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(result).To(Equal("3.3"))
+
+	// Sending other commands only once
+	port.Expect(">connect(1, 2)").Respond("Python> >connect(1, 2)\r\nbusy\r\n")
+	_, err = j.ExecPythonCommand("connect(1, 2)", 0, SingleLine())
+	g.Expect(err).To(MatchError(ErrDeviceBusy))
+
+	// Failing once the backoff is exhausted
+	port.Expect(">dac_get(1)").Respond("Python> >dac_get(1)\r\nbusy\r\n").Times(defaultBusyBackoff().Steps)
+	_, err = j.ExecPythonCommand("dac_get(1)", 0, Idempotent(), SingleLine())
+	g.Expect(err).To(MatchError(ErrDeviceBusy))
+
+	g.Expect(port.ExpectationsMet()).To(Succeed())
+	g.Expect(port.Writes()).To(HaveLen(1 + 2 + 1 + defaultBusyBackoff().Steps))
+}
@@ -20,6 +20,8 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"os"
+	"path/filepath"
 	"slices"
 	"strings"
 	"time"
@@ -32,9 +34,117 @@ import (
 var ErrNoSerialPortFound = errors.New("no serial port found")
 var ErrNoJumperlessFound = errors.New("no Jumperless device found")
 var ErrUnexpectedCommandOutput = errors.New("unexpected command output format")
+var ErrPythonException = errors.New("python command raised an exception")
+
+// ProtocolVersion is the version of the dialect the library speaks to the device: the firmware query, the
+// config dump and the MicroPython commands it sends, and the output it parses. It changes whenever the library
+// relies on commands or output of the firmware it didn't use before.
+const ProtocolVersion = "2"
+
+// pythonTraceback is the first line of the output when a MicroPython command raises an exception
+const pythonTraceback = "Traceback (most recent call last):"
+
+// pythonPrompt is the prompt printed by the MicroPython REPL of the device
+const pythonPrompt = "Python>"
 
 type Jumperless struct {
-	port *JumperlessPort
+	port        *JumperlessPort
+	retryPolicy *RetryPolicy
+
+	// connectDTRPulse is the duration DTR is dropped for after opening the port, zero disables the pulse
+	connectDTRPulse time.Duration
+}
+
+// PortSelector selects serial ports using the USB metadata reported for them.
+// Empty fields match any value.
+type PortSelector struct {
+	// SerialNumber is the USB serial number of the device
+	SerialNumber string
+	// VID is the USB vendor ID of the device, e.g. "2E8A"
+	VID string
+	// PID is the USB product ID of the device
+	PID string
+}
+
+// IsEmpty returns true if the selector matches every port.
+func (s PortSelector) IsEmpty() bool {
+	return s.SerialNumber == "" && s.VID == "" && s.PID == ""
+}
+
+// Matches returns true if the port details match the selector.
+func (s PortSelector) Matches(details *enumerator.PortDetails) bool {
+	if details == nil {
+		return false
+	}
+
+	if s.SerialNumber != "" && details.SerialNumber != s.SerialNumber {
+		return false
+	}
+
+	if s.VID != "" && !strings.EqualFold(details.VID, s.VID) {
+		return false
+	}
+
+	if s.PID != "" && !strings.EqualFold(details.PID, s.PID) {
+		return false
+	}
+
+	return true
+}
+
+// PortVisible returns whether the named port exists on this host, or without a name whether a serial port
+// matches the selector, without probing or opening any port. An empty selector matches any serial port.
+func PortVisible(portName string, selector PortSelector) bool {
+	if portName != "" {
+		_, err := os.Stat(portName)
+		return err == nil
+	}
+
+	ports, err := enumerateSerialPorts()
+	if err != nil {
+		return false
+	}
+
+	return slices.ContainsFunc(ports, selector.Matches)
+}
+
+// NewJumperlessFromSelector finds a Jumperless device on a serial port matching the selector.
+// Only ports matching the selector are probed.
+func NewJumperlessFromSelector(_ context.Context, selector PortSelector, baudRate int) (*Jumperless, error) {
+	ports, err := enumerateSerialPorts()
+	if err != nil {
+		return nil, fmt.Errorf("unable to enumerate serial ports: %w", err)
+	}
+
+	matching := slices.DeleteFunc(ports, func(details *enumerator.PortDetails) bool {
+		return !selector.Matches(details)
+	})
+
+	if len(matching) == 0 {
+		return nil, fmt.Errorf("no serial port matches selector %+v: %w", selector, ErrNoSerialPortFound)
+	}
+
+	detectedPort, err := findJumperlessPort(matching, baudRate)
+	if err != nil {
+		return nil, fmt.Errorf("unable to find Jumperless port: %w", err)
+	}
+
+	if detectedPort == nil {
+		return nil, ErrNoJumperlessFound
+	}
+
+	return &Jumperless{port: detectedPort}, nil
+}
+
+// NewJumperlessWithOpener verifies that the port opened with opener is a Jumperless device, e.g. a
+// serialmock.Port in unit tests. The device node of the port is not locked.
+func NewJumperlessWithOpener(portName string, baudRate int, opener Opener) (*Jumperless, error) {
+	port, err := newJumperlessPort(portName, baudRate, opener)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Jumperless{port: port}, nil
 }
 
 func NewJumperless(ctx context.Context, portName string, baudRate int) (*Jumperless, error) {
@@ -49,6 +159,8 @@ func NewJumperless(ctx context.Context, portName string, baudRate int) (*Jumperl
 			return nil, ErrNoJumperlessFound
 		}
 
+		port.serialNumber = lookupSerialNumber(portName)
+
 		return &Jumperless{port: port}, nil
 	}
 
@@ -78,6 +190,15 @@ func (j *Jumperless) GetVersion() string {
 	return j.port.version
 }
 
+// GetSerialNumber returns the USB serial number of the device, if known.
+func (j *Jumperless) GetSerialNumber() string {
+	if j == nil || j.port == nil {
+		return ""
+	}
+
+	return j.port.serialNumber
+}
+
 func (j *Jumperless) GetPort() string {
 	if j == nil || j.port == nil {
 		return ""
@@ -91,7 +212,18 @@ func (j *Jumperless) OpenPort() error {
 		return ErrNilJumperlessPort
 	}
 
-	return j.port.Open()
+	if err := j.port.Open(); err != nil {
+		return err
+	}
+
+	if j.connectDTRPulse > 0 {
+		if err := j.port.PulseDTR(j.connectDTRPulse); err != nil {
+			_ = j.port.Close()
+			return err
+		}
+	}
+
+	return nil
 }
 
 func (j *Jumperless) ClosePort() error {
@@ -102,14 +234,36 @@ func (j *Jumperless) ClosePort() error {
 	return j.port.Close()
 }
 
-func (j *Jumperless) ExecPythonCommand(command string, waitForRead time.Duration) (string, error) {
-	result, err := j.ExecRawCommand(">"+command, waitForRead)
+// ExecPythonCommand executes a MicroPython command and returns its output without the prompt and echoed command.
+// Commands marked Idempotent are retried when the output is truncated or can't be read.
+func (j *Jumperless) ExecPythonCommand(command string, waitForRead time.Duration,
+	opts ...CommandOption) (string, error) {
+	if j == nil {
+		return "", ErrNilJumperlessPort
+	}
+
+	options := newCommandOptions(">"+command, opts)
+	complete := options.completion()
+
+	return j.retry(opts, func() (string, error) {
+		return j.execPythonCommand(command, waitForRead, complete, options)
+	})
+}
+
+func (j *Jumperless) execPythonCommand(command string, waitForRead time.Duration,
+	complete func([]byte) bool, options *commandOptions) (string, error) {
+	result, err := j.execRawCommand(">"+command, waitForRead, complete, options.idempotent)
 	if err != nil {
 		return "", fmt.Errorf("failed to execute command: %w", err)
 	}
 
 	result = ansi.Strip(result) // Remove ANSI escape codes
 
+	// Commands printing nothing only echo the command, e.g. Python> >gpio_set(1, True)\r\n
+	if options.optionalOutput && isEchoOnly(result) {
+		return "", nil
+	}
+
 	// Split the output and strip the first and last lines
 	// Example output:
 	// Python> >dac_get(0)\r\n3.3V\r\n
@@ -128,7 +282,7 @@ func (j *Jumperless) ExecPythonCommand(command string, waitForRead time.Duration
 	filtered := slices.Collect(func(yield func(string) bool) {
 		for _, line := range resultLines {
 			trimmed := strings.TrimSpace(line)
-			if trimmed != "" && !strings.HasPrefix(trimmed, "Python>") {
+			if trimmed != "" && !strings.HasPrefix(trimmed, pythonPrompt) {
 				if !yield(trimmed) {
 					return
 				}
@@ -136,6 +290,11 @@ func (j *Jumperless) ExecPythonCommand(command string, waitForRead time.Duration
 		}
 	})
 
+	// The last line of a traceback is the exception, e.g. "OSError: [Errno 5] EIO"
+	if slices.Contains(filtered, pythonTraceback) {
+		return "", fmt.Errorf("%w: %s: %s", ErrPythonException, command, filtered[len(filtered)-1])
+	}
+
 	switch len(filtered) {
 	case 0:
 		return "", fmt.Errorf(
@@ -149,15 +308,128 @@ func (j *Jumperless) ExecPythonCommand(command string, waitForRead time.Duration
 	}
 }
 
-func (j *Jumperless) ExecRawCommand(command string, waitForRead time.Duration) (string, error) {
+// isEchoOnly reports whether the output of a MicroPython command is a single complete line holding the prompt
+// and the echoed command
+func isEchoOnly(result string) bool {
+	line, rest, ok := strings.Cut(result, "\r\n")
+
+	return ok && strings.TrimSpace(rest) == "" && strings.HasPrefix(strings.TrimSpace(line), pythonPrompt)
+}
+
+// ExecLongCommand executes a long-running command, e.g. a firmware update or app, passing its output to
+// onChunk as it is read instead of waiting for all of it. It returns once any of the terminators has been
+// read, or ErrCommandIncomplete if ctx is done first. Without terminators the output is read until ctx is done.
+func (j *Jumperless) ExecLongCommand(ctx context.Context, command string,
+	onChunk func([]byte), terminators ...string) error {
+	if j == nil {
+		return ErrNilJumperlessPort
+	}
+	if j.port == nil {
+		return ErrUninitializedSerialPort
+	}
+
+	return j.port.execLongCommand(ctx, command, onChunk, terminators)
+}
+
+// ExecRawCommand writes a command as is and returns all output read afterwards, or the output up to the
+// terminators of the command, e.g. the END line of the config printed by "~".
+// Commands marked Idempotent are retried when the output can't be read or isn't complete.
+func (j *Jumperless) ExecRawCommand(command string, waitForRead time.Duration,
+	opts ...CommandOption) (string, error) {
 	if j == nil {
 		return "", ErrNilJumperlessPort
 	}
+
+	options := newCommandOptions(command, opts)
+	complete := options.completion()
+
+	return j.retry(opts, func() (string, error) {
+		return j.execRawCommand(command, waitForRead, complete, options.idempotent)
+	})
+}
+
+func (j *Jumperless) execRawCommand(command string, waitForRead time.Duration,
+	complete func([]byte) bool, idempotent bool) (string, error) {
 	if j.port == nil {
 		return "", ErrUninitializedSerialPort
 	}
 
-	return j.port.execRawCommand(command, waitForRead)
+	return j.port.execRawCommandWithRetry(command, waitForRead, complete, idempotent)
+}
+
+// DeviceInfo describes a Jumperless device found on a serial port.
+type DeviceInfo struct {
+	// Port is the name of the serial port
+	Port string
+	// SerialNumber is the USB serial number, if known
+	SerialNumber string
+	// VID is the USB vendor ID, if known
+	VID string
+	// PID is the USB product ID, if known
+	PID string
+	// Version is the firmware version reported by the device
+	Version string
+}
+
+// DiscoverJumperlessDevices enumerates all serial ports and probes each of them,
+// returning the Jumperless devices found. Errors probing individual ports are aggregated
+// and returned alongside any devices that were found.
+func DiscoverJumperlessDevices(ctx context.Context, baudRate int) ([]DeviceInfo, error) {
+	return discoverJumperlessDevices(ctx, baudRate, func(string) *JumperlessPort { return nil })
+}
+
+// DiscoverJumperlessDevicesExcept probes the serial ports for Jumperless devices like DiscoverJumperlessDevices,
+// except for the ports inUse returns the firmware version of, which are reported without probing them, e.g.
+// ports opened by another process that must not be disturbed.
+func DiscoverJumperlessDevicesExcept(ctx context.Context, baudRate int,
+	inUse func(portName string) (string, bool)) ([]DeviceInfo, error) {
+	return discoverJumperlessDevices(ctx, baudRate, func(portName string) *JumperlessPort {
+		if version, ok := inUse(portName); ok {
+			return &JumperlessPort{portName: portName, version: version}
+		}
+
+		return nil
+	})
+}
+
+// discoverJumperlessDevices probes the serial ports for Jumperless devices, except for the ports
+// inUse returns an already open device for.
+func discoverJumperlessDevices(ctx context.Context, baudRate int,
+	inUse func(portName string) *JumperlessPort) ([]DeviceInfo, error) {
+	ports, err := enumerateSerialPorts()
+	if err != nil {
+		return nil, fmt.Errorf("unable to enumerate serial ports: %w", err)
+	}
+
+	devices := []DeviceInfo{}
+	errs := []error{}
+
+	for _, details := range ports {
+		if err := ctx.Err(); err != nil {
+			return devices, fmt.Errorf("discovery cancelled: %w", err)
+		}
+
+		port := inUse(details.Name)
+		if port == nil {
+			port, err = NewJumperlessPort(details.Name, baudRate)
+			if err != nil {
+				if !errors.Is(err, ErrNoJumperlessFound) {
+					errs = append(errs, fmt.Errorf("unable to determine if port %s is Jumperless: %w", details.Name, err))
+				}
+				continue
+			}
+		}
+
+		devices = append(devices, DeviceInfo{
+			Port:         details.Name,
+			SerialNumber: details.SerialNumber,
+			VID:          details.VID,
+			PID:          details.PID,
+			Version:      port.version,
+		})
+	}
+
+	return devices, kerrors.NewAggregate(errs)
 }
 
 func enumerateSerialPorts() ([]*enumerator.PortDetails, error) {
@@ -176,8 +448,8 @@ func enumerateSerialPorts() ([]*enumerator.PortDetails, error) {
 func findJumperlessPort(ports []*enumerator.PortDetails, baudRate int) (*JumperlessPort, error) {
 	errs := []error{}
 
-	for _, port := range ports {
-		port, err := NewJumperlessPort(port.Name, baudRate)
+	for _, details := range ports {
+		port, err := NewJumperlessPort(details.Name, baudRate)
 		if err != nil {
 			errs = append(errs, fmt.Errorf("unable to determine if port is Jumperless %w", err))
 			continue
@@ -187,6 +459,8 @@ func findJumperlessPort(ports []*enumerator.PortDetails, baudRate int) (*Jumperl
 			continue
 		}
 
+		port.serialNumber = details.SerialNumber
+
 		return port, nil
 	}
 
@@ -196,3 +470,75 @@ func findJumperlessPort(ports []*enumerator.PortDetails, baudRate int) (*Jumperl
 
 	return nil, ErrNoJumperlessFound
 }
+
+// lookupSerialNumber returns the USB serial number of the named port, resolving symlinks
+// such as /dev/serial/by-id paths. An empty string is returned if it cannot be determined.
+func lookupSerialNumber(portName string) string {
+	resolved, err := filepath.EvalSymlinks(portName)
+	if err != nil {
+		resolved = portName
+	}
+
+	ports, err := enumerator.GetDetailedPortsList()
+	if err != nil {
+		return ""
+	}
+
+	for _, details := range ports {
+		if details.Name == portName || details.Name == resolved {
+			return details.SerialNumber
+		}
+	}
+
+	return ""
+}
+
+// LookupPassthroughPort returns the serial port of the USB-UART passthrough of the device on the named port,
+// resolving symlinks such as /dev/serial/by-id paths. An empty string is returned if it cannot be determined.
+func LookupPassthroughPort(portName string) string {
+	ports, err := enumerator.GetDetailedPortsList()
+	if err != nil {
+		return ""
+	}
+
+	resolved, err := filepath.EvalSymlinks(portName)
+	if err != nil {
+		resolved = portName
+	}
+
+	return passthroughPort(ports, resolved)
+}
+
+// passthroughPort returns the serial port of the USB-UART passthrough of the device on the named port, which
+// the device enumerates as its next serial port, e.g. /dev/ttyACM1 for /dev/ttyACM0. Ports of other USB devices
+// are ignored, an empty string is returned if the device has no further port or its serial number is unknown.
+func passthroughPort(ports []*enumerator.PortDetails, portName string) string {
+	i := slices.IndexFunc(ports, func(details *enumerator.PortDetails) bool { return details.Name == portName })
+	if i < 0 || !ports[i].IsUSB || ports[i].SerialNumber == "" {
+		return ""
+	}
+
+	device := ports[i]
+	names := []string{}
+	for _, details := range ports {
+		if details.IsUSB && details.SerialNumber == device.SerialNumber &&
+			details.VID == device.VID && details.PID == device.PID {
+			names = append(names, details.Name)
+		}
+	}
+
+	// Sort numerically, so /dev/ttyACM10 follows /dev/ttyACM9
+	slices.SortFunc(names, func(a, b string) int {
+		if len(a) != len(b) {
+			return len(a) - len(b)
+		}
+		return strings.Compare(a, b)
+	})
+
+	next := slices.Index(names, portName) + 1
+	if next >= len(names) {
+		return ""
+	}
+
+	return names[next]
+}
@@ -0,0 +1,72 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package jumperless
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	. "github.com/onsi/gomega"
+	"go.bug.st/serial/enumerator"
+)
+
+func TestPassthroughPort(t *testing.T) {
+	device := func(name, serialNumber string) *enumerator.PortDetails {
+		return &enumerator.PortDetails{Name: name, IsUSB: true, VID: "1D50", PID: "ACAB", SerialNumber: serialNumber}
+	}
+
+	ports := []*enumerator.PortDetails{
+		device("/dev/ttyACM10", "B"),
+		device("/dev/ttyACM9", "B"),
+		device("/dev/ttyACM1", "A"),
+		device("/dev/ttyACM0", "A"),
+		device("/dev/ttyACM2", ""),
+		device("/dev/ttyACM3", ""),
+		{Name: "/dev/ttyS0"},
+	}
+
+	tests := []struct {
+		name     string
+		portName string
+		want     string
+	}{
+		{name: "next port of the device", portName: "/dev/ttyACM0", want: "/dev/ttyACM1"},
+		{name: "sorted numerically", portName: "/dev/ttyACM9", want: "/dev/ttyACM10"},
+		{name: "last port of the device", portName: "/dev/ttyACM1", want: ""},
+		{name: "unknown serial number", portName: "/dev/ttyACM2", want: ""},
+		{name: "not a USB port", portName: "/dev/ttyS0", want: ""},
+		{name: "unknown port", portName: "/dev/ttyACM5", want: ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+			g.Expect(passthroughPort(ports, tt.portName)).To(Equal(tt.want))
+		})
+	}
+}
+
+func TestPortVisible(t *testing.T) {
+	g := NewWithT(t)
+
+	port := filepath.Join(t.TempDir(), "ttyACM0")
+	g.Expect(PortVisible(port, PortSelector{})).To(BeFalse())
+
+	g.Expect(os.WriteFile(port, nil, 0o600)).To(Succeed())
+	g.Expect(PortVisible(port, PortSelector{})).To(BeTrue())
+}
@@ -0,0 +1,184 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package jumperless
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"go.bug.st/serial"
+)
+
+var ErrInvalidLineSettings = errors.New("invalid serial line settings")
+
+// DefaultDataBits is the number of data bits of the Jumperless, which uses 8N1 framing
+const DefaultDataBits = 8
+
+// parityNames are the names of the parity settings, as accepted by ParseLineSettings
+var parityNames = map[string]serial.Parity{ //nolint:gochecknoglobals
+	"none":  serial.NoParity,
+	"odd":   serial.OddParity,
+	"even":  serial.EvenParity,
+	"mark":  serial.MarkParity,
+	"space": serial.SpaceParity,
+}
+
+// stopBitsNames are the names of the stop bits settings, as accepted by ParseLineSettings
+var stopBitsNames = map[string]serial.StopBits{ //nolint:gochecknoglobals
+	"1":   serial.OneStopBit,
+	"1.5": serial.OnePointFiveStopBits,
+	"2":   serial.TwoStopBits,
+}
+
+// LineSettings are the data bits, parity and stop bits of a serial port. The zero value is 8N1, the framing
+// of the Jumperless, other settings are only needed for devices attached to a USB-UART adapter.
+type LineSettings struct {
+	// DataBits is the size of a character, 5 to 8, or zero for 8
+	DataBits int
+
+	// Parity is the parity check of a character
+	Parity serial.Parity
+
+	// StopBits is the number of stop bits after a character
+	StopBits serial.StopBits
+}
+
+// ParseLineSettings returns the line settings of the number of data bits, the name of the parity (none, odd,
+// even, mark or space) and the number of stop bits (1, 1.5 or 2). Empty names and zero data bits select the
+// defaults of 8N1.
+func ParseLineSettings(dataBits int, parity, stopBits string) (LineSettings, error) {
+	l := LineSettings{DataBits: dataBits}
+
+	if parity != "" {
+		p, ok := parityNames[strings.ToLower(parity)]
+		if !ok {
+			return LineSettings{}, fmt.Errorf("%w: parity %q (use none, odd, even, mark or space)",
+				ErrInvalidLineSettings, parity)
+		}
+
+		l.Parity = p
+	}
+
+	if stopBits != "" {
+		s, ok := stopBitsNames[stopBits]
+		if !ok {
+			return LineSettings{}, fmt.Errorf("%w: stop bits %q (use 1, 1.5 or 2)", ErrInvalidLineSettings, stopBits)
+		}
+
+		l.StopBits = s
+	}
+
+	if err := l.Validate(); err != nil {
+		return LineSettings{}, err
+	}
+
+	return l, nil
+}
+
+// Validate returns an error if the line settings can't be set on a serial port
+func (l LineSettings) Validate() error {
+	if l.DataBits != 0 && (l.DataBits < 5 || l.DataBits > 8) {
+		return fmt.Errorf("%w: %d data bits (use 5 to 8)", ErrInvalidLineSettings, l.DataBits)
+	}
+
+	if l.Parity < serial.NoParity || l.Parity > serial.SpaceParity {
+		return fmt.Errorf("%w: parity %d", ErrInvalidLineSettings, l.Parity)
+	}
+
+	if l.StopBits < serial.OneStopBit || l.StopBits > serial.TwoStopBits {
+		return fmt.Errorf("%w: stop bits %d", ErrInvalidLineSettings, l.StopBits)
+	}
+
+	// UARTs only support 1.5 stop bits for 5 bit characters, and 2 stop bits for longer ones
+	if l.StopBits == serial.OnePointFiveStopBits && l.dataBits() != 5 {
+		return fmt.Errorf("%w: 1.5 stop bits require 5 data bits", ErrInvalidLineSettings)
+	}
+
+	return nil
+}
+
+// Mode returns the serial port mode of the line settings at the baud rate
+func (l LineSettings) Mode(baudRate int) *serial.Mode {
+	return &serial.Mode{
+		BaudRate: baudRate,
+		DataBits: l.dataBits(),
+		Parity:   l.Parity,
+		StopBits: l.StopBits,
+	}
+}
+
+// String returns the line settings in the usual notation, e.g. 8N1 or 7E2
+func (l LineSettings) String() string {
+	parity := "?"
+	for name, p := range parityNames {
+		if p == l.Parity {
+			parity = strings.ToUpper(name[:1])
+		}
+	}
+
+	stopBits := "?"
+	for name, s := range stopBitsNames {
+		if s == l.StopBits {
+			stopBits = name
+		}
+	}
+
+	return fmt.Sprintf("%d%s%s", l.dataBits(), parity, stopBits)
+}
+
+func (l LineSettings) dataBits() int {
+	if l.DataBits == 0 {
+		return DefaultDataBits
+	}
+
+	return l.DataBits
+}
+
+// SetLineSettings sets the line settings the port is opened with, applying them right away if it is open
+func (p *JumperlessPort) SetLineSettings(l LineSettings) error {
+	if p == nil {
+		return ErrNilJumperlessPort
+	}
+
+	if err := l.Validate(); err != nil {
+		return err
+	}
+
+	p.portLock.Lock()
+	defer p.portLock.Unlock()
+
+	mode := l.Mode(p.mode.BaudRate)
+	if p.port != nil {
+		if err := p.port.SetMode(mode); err != nil {
+			return fmt.Errorf("unable to set line settings of serial port %s: %w", p.portName, err)
+		}
+	}
+
+	p.mode = mode
+
+	return nil
+}
+
+// SetLineSettings sets the line settings of the serial port of the device
+func (j *Jumperless) SetLineSettings(l LineSettings) error {
+	if j == nil || j.port == nil {
+		return ErrNilJumperlessPort
+	}
+
+	return j.port.SetLineSettings(l)
+}
@@ -0,0 +1,79 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package jumperless
+
+import (
+	"testing"
+
+	. "github.com/onsi/gomega"
+	"go.bug.st/serial"
+
+	"github.com/detiber/k8s-jumperless/jumperless/serialmock"
+)
+
+func TestParseLineSettings(t *testing.T) {
+	g := NewWithT(t)
+
+	l, err := ParseLineSettings(0, "", "")
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(l.String()).To(Equal("8N1"))
+	g.Expect(l.Mode(9600)).To(Equal(&serial.Mode{BaudRate: 9600, DataBits: 8}))
+
+	l, err = ParseLineSettings(7, "Even", "2")
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(l).To(Equal(LineSettings{DataBits: 7, Parity: serial.EvenParity, StopBits: serial.TwoStopBits}))
+	g.Expect(l.String()).To(Equal("7E2"))
+
+	l, err = ParseLineSettings(5, "none", "1.5")
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(l.String()).To(Equal("5N1.5"))
+
+	_, err = ParseLineSettings(9, "", "")
+	g.Expect(err).To(MatchError(ErrInvalidLineSettings))
+
+	_, err = ParseLineSettings(8, "parity", "")
+	g.Expect(err).To(MatchError(ErrInvalidLineSettings))
+
+	_, err = ParseLineSettings(8, "", "3")
+	g.Expect(err).To(MatchError(ErrInvalidLineSettings))
+
+	_, err = ParseLineSettings(8, "", "1.5")
+	g.Expect(err).To(MatchError(ErrInvalidLineSettings))
+}
+
+func TestSetLineSettings(t *testing.T) {
+	g := NewWithT(t)
+
+	port := serialmock.New()
+	port.Expect("?").Respond("Jumperless firmware version: 5.3.1.0\r\n")
+
+	j, err := NewJumperlessWithOpener("/dev/ttyMock", 9600, port.Open)
+	g.Expect(err).NotTo(HaveOccurred())
+
+	// The settings are used once the port is opened
+	g.Expect(j.SetLineSettings(LineSettings{DataBits: 7, Parity: serial.OddParity})).To(Succeed())
+	g.Expect(j.OpenPort()).To(Succeed())
+	g.Expect(port.Mode()).To(Equal(&serial.Mode{BaudRate: 9600, DataBits: 7, Parity: serial.OddParity}))
+
+	// and applied right away to an open port
+	g.Expect(j.SetLineSettings(LineSettings{StopBits: serial.TwoStopBits})).To(Succeed())
+	g.Expect(port.Mode()).To(Equal(&serial.Mode{BaudRate: 9600, DataBits: 8, StopBits: serial.TwoStopBits}))
+
+	g.Expect(j.SetLineSettings(LineSettings{DataBits: 4})).To(MatchError(ErrInvalidLineSettings))
+
+	g.Expect(j.ClosePort()).To(Succeed())
+}
@@ -0,0 +1,50 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package jumperless
+
+import (
+	"errors"
+)
+
+var ErrDeviceLocked = errors.New("serial port is in use by another process")
+
+// DeviceLock is an exclusive advisory lock on the device node of a serial port. Processes driving a device,
+// such as the controller or the proxy of jumperless-utils, hold it while the port is open so they don't
+// interleave their commands with those of another process.
+type DeviceLock struct {
+	lock deviceLock
+}
+
+// LockDevice locks the device node of a serial port, returning ErrDeviceLocked if another process holds
+// the lock. The lock is held until Unlock is called or the process exits.
+func LockDevice(portName string) (*DeviceLock, error) {
+	lock, err := lockDevice(portName)
+	if err != nil {
+		return nil, err
+	}
+
+	return &DeviceLock{lock: lock}, nil
+}
+
+// Unlock releases the lock
+func (l *DeviceLock) Unlock() error {
+	if l == nil {
+		return nil
+	}
+
+	return l.lock.unlock()
+}
@@ -0,0 +1,50 @@
+//go:build unix
+
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package jumperless
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	. "github.com/onsi/gomega"
+)
+
+func TestLockDevice(t *testing.T) {
+	g := NewWithT(t)
+
+	// flock works on any file, so a regular file stands in for the device node
+	portName := filepath.Join(t.TempDir(), "ttyACM0")
+	g.Expect(os.WriteFile(portName, nil, 0o600)).To(Succeed())
+
+	lock, err := LockDevice(portName)
+	g.Expect(err).NotTo(HaveOccurred())
+
+	_, err = LockDevice(portName)
+	g.Expect(err).To(MatchError(ErrDeviceLocked))
+
+	g.Expect(lock.Unlock()).To(Succeed())
+
+	relocked, err := LockDevice(portName)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(relocked.Unlock()).To(Succeed())
+
+	var unlocked *DeviceLock
+	g.Expect(unlocked.Unlock()).To(Succeed())
+}
@@ -0,0 +1,70 @@
+//go:build unix
+
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package jumperless
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"syscall"
+
+	"golang.org/x/sys/unix"
+)
+
+// deviceLock is an open file descriptor of the device node holding a flock
+type deviceLock struct {
+	file *os.File
+}
+
+func lockDevice(portName string) (deviceLock, error) {
+	// Opening the device node must neither block on the carrier nor make it the controlling terminal
+	file, err := os.OpenFile(portName, os.O_RDONLY|syscall.O_NOCTTY|syscall.O_NONBLOCK, 0)
+	if errors.Is(err, syscall.EBUSY) {
+		// The port was opened exclusively by another process
+		return deviceLock{}, fmt.Errorf("%w: %s: %w", ErrDeviceLocked, portName, err)
+	}
+	if err != nil {
+		return deviceLock{}, fmt.Errorf("unable to open %s for locking: %w", portName, err)
+	}
+
+	if err := unix.Flock(int(file.Fd()), unix.LOCK_EX|unix.LOCK_NB); err != nil { //nolint:gosec
+		_ = file.Close()
+
+		if errors.Is(err, unix.EWOULDBLOCK) {
+			return deviceLock{}, fmt.Errorf("%w: %s", ErrDeviceLocked, portName)
+		}
+
+		return deviceLock{}, fmt.Errorf("unable to lock %s: %w", portName, err)
+	}
+
+	return deviceLock{file: file}, nil
+}
+
+func (l deviceLock) unlock() error {
+	if l.file == nil {
+		return nil
+	}
+
+	// Closing the last descriptor of the open file releases the flock
+	if err := l.file.Close(); err != nil {
+		return fmt.Errorf("unable to unlock %s: %w", l.file.Name(), err)
+	}
+
+	return nil
+}
@@ -0,0 +1,178 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package jumperless
+
+import (
+	"errors"
+	"os"
+	"sync"
+	"time"
+
+	"go.bug.st/serial"
+)
+
+var ErrNamedPipeUnsupported = errors.New("named pipes are only supported on Windows")
+
+// pipeDrainTimeout is how long ResetInputBuffer waits for buffered data of a pipe
+const pipeDrainTimeout = time.Millisecond
+
+// OpenSerialPort opens a serial port, or the named pipe an emulator serves its virtual serial port on under Windows.
+func OpenSerialPort(portName string, mode *serial.Mode) (serial.Port, error) {
+	if isNamedPipe(portName) {
+		return openNamedPipe(portName, mode)
+	}
+
+	return serial.Open(portName, mode) //nolint:wrapcheck
+}
+
+// pipePort is a serial.Port over a pipe, such as the named pipe of an emulator on Windows. The line settings
+// and modem lines don't apply to a pipe, they are kept but have no effect.
+type pipePort struct {
+	file *os.File
+
+	mu          sync.Mutex
+	mode        serial.Mode
+	readTimeout time.Duration
+	dtr         bool
+	rts         bool
+}
+
+var _ serial.Port = &pipePort{}
+
+// newPipePort returns a serial.Port over file, which has to support deadlines
+func newPipePort(file *os.File, mode *serial.Mode) *pipePort {
+	p := &pipePort{file: file, readTimeout: serial.NoTimeout, dtr: true, rts: true}
+	if mode != nil {
+		p.mode = *mode
+	}
+
+	return p
+}
+
+// SetMode implements serial.Port
+func (p *pipePort) SetMode(mode *serial.Mode) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if mode != nil {
+		p.mode = *mode
+	}
+
+	return nil
+}
+
+// Read implements serial.Port, returning no data once the read timeout passes like a serial port
+func (p *pipePort) Read(b []byte) (int, error) {
+	p.mu.Lock()
+	timeout := p.readTimeout
+	p.mu.Unlock()
+
+	deadline := time.Time{}
+	if timeout != serial.NoTimeout {
+		deadline = time.Now().Add(timeout)
+	}
+
+	if err := p.file.SetReadDeadline(deadline); err != nil {
+		return 0, err //nolint:wrapcheck
+	}
+
+	n, err := p.file.Read(b)
+	if errors.Is(err, os.ErrDeadlineExceeded) {
+		return n, nil
+	}
+
+	return n, err //nolint:wrapcheck
+}
+
+// Write implements serial.Port
+func (p *pipePort) Write(b []byte) (int, error) {
+	return p.file.Write(b) //nolint:wrapcheck
+}
+
+// Drain implements serial.Port, writes to a pipe don't need to be drained
+func (p *pipePort) Drain() error {
+	return nil
+}
+
+// ResetInputBuffer implements serial.Port, discarding the data already written to the pipe
+func (p *pipePort) ResetInputBuffer() error {
+	buffer := make([]byte, 1024)
+	for {
+		if err := p.file.SetReadDeadline(time.Now().Add(pipeDrainTimeout)); err != nil {
+			return err //nolint:wrapcheck
+		}
+
+		n, err := p.file.Read(buffer)
+		if errors.Is(err, os.ErrDeadlineExceeded) || (err == nil && n == 0) {
+			return nil
+		}
+
+		if err != nil {
+			return err //nolint:wrapcheck
+		}
+	}
+}
+
+// ResetOutputBuffer implements serial.Port
+func (p *pipePort) ResetOutputBuffer() error {
+	return nil
+}
+
+// SetDTR implements serial.Port
+func (p *pipePort) SetDTR(dtr bool) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.dtr = dtr
+
+	return nil
+}
+
+// SetRTS implements serial.Port
+func (p *pipePort) SetRTS(rts bool) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.rts = rts
+
+	return nil
+}
+
+// GetModemStatusBits implements serial.Port, the other side of a pipe is always ready
+func (p *pipePort) GetModemStatusBits() (*serial.ModemStatusBits, error) {
+	return &serial.ModemStatusBits{CTS: true, DSR: true, DCD: true}, nil
+}
+
+// SetReadTimeout implements serial.Port
+func (p *pipePort) SetReadTimeout(t time.Duration) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.readTimeout = t
+
+	return nil
+}
+
+// Close implements serial.Port
+func (p *pipePort) Close() error {
+	return p.file.Close() //nolint:wrapcheck
+}
+
+// Break implements serial.Port, a pipe has no line to break
+func (p *pipePort) Break(time.Duration) error {
+	return nil
+}
@@ -0,0 +1,92 @@
+//go:build unix
+
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package jumperless
+
+import (
+	"os"
+	"syscall"
+	"testing"
+	"time"
+
+	. "github.com/onsi/gomega"
+	"go.bug.st/serial"
+)
+
+// socketPair returns both ends of a connected socket pair supporting deadlines, standing in for a named pipe
+func socketPair(g Gomega) (*os.File, *os.File) {
+	fds, err := syscall.Socketpair(syscall.AF_UNIX, syscall.SOCK_STREAM, 0)
+	g.Expect(err).NotTo(HaveOccurred())
+
+	for _, fd := range fds {
+		g.Expect(syscall.SetNonblock(fd, true)).To(Succeed())
+	}
+
+	return os.NewFile(uintptr(fds[0]), "server"), os.NewFile(uintptr(fds[1]), "client")
+}
+
+func TestPipePort(t *testing.T) {
+	g := NewWithT(t)
+
+	server, client := socketPair(g)
+	defer func() { _ = server.Close() }()
+
+	port := newPipePort(client, &serial.Mode{BaudRate: 115200})
+	g.Expect(port.SetReadTimeout(20 * time.Millisecond)).To(Succeed())
+
+	buffer := make([]byte, 64)
+
+	// A read without data times out like a serial port
+	n, err := port.Read(buffer)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(n).To(BeZero())
+
+	_, err = port.Write([]byte("?"))
+	g.Expect(err).NotTo(HaveOccurred())
+
+	n, err = server.Read(buffer)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(string(buffer[:n])).To(Equal("?"))
+
+	// Stale data is discarded by resetting the input buffer
+	_, err = server.Write([]byte("stale"))
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(port.ResetInputBuffer()).To(Succeed())
+
+	_, err = server.Write([]byte("fresh"))
+	g.Expect(err).NotTo(HaveOccurred())
+
+	n, err = port.Read(buffer)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(string(buffer[:n])).To(Equal("fresh"))
+
+	bits, err := port.GetModemStatusBits()
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(bits.CTS).To(BeTrue())
+
+	g.Expect(port.Close()).To(Succeed())
+}
+
+func TestOpenSerialPortWithoutNamedPipes(t *testing.T) {
+	g := NewWithT(t)
+
+	g.Expect(isNamedPipe(`\\.\pipe\jumperless`)).To(BeFalse())
+
+	_, err := openNamedPipe(`\\.\pipe\jumperless`, nil)
+	g.Expect(err).To(MatchError(ErrNamedPipeUnsupported))
+}
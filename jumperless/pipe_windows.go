@@ -0,0 +1,52 @@
+//go:build windows
+
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package jumperless
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"go.bug.st/serial"
+	"golang.org/x/sys/windows"
+)
+
+// namedPipePrefix is the prefix of the names of local named pipes
+const namedPipePrefix = `\\.\pipe\`
+
+// isNamedPipe returns true if the port is a named pipe, e.g. \\.\pipe\jumperless served by the emulator
+func isNamedPipe(portName string) bool {
+	return strings.HasPrefix(strings.ToLower(portName), namedPipePrefix)
+}
+
+// openNamedPipe opens a named pipe for overlapped I/O, so reads can time out like those of a serial port
+func openNamedPipe(portName string, mode *serial.Mode) (serial.Port, error) {
+	name, err := windows.UTF16PtrFromString(portName)
+	if err != nil {
+		return nil, fmt.Errorf("invalid named pipe %s: %w", portName, err)
+	}
+
+	handle, err := windows.CreateFile(name, windows.GENERIC_READ|windows.GENERIC_WRITE, 0, nil,
+		windows.OPEN_EXISTING, windows.FILE_FLAG_OVERLAPPED, 0)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open named pipe %s: %w", portName, err)
+	}
+
+	return newPipePort(os.NewFile(uintptr(handle), portName), mode), nil
+}
@@ -0,0 +1,583 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package jumperless
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"sync"
+	"time"
+
+	"go.bug.st/serial"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+
+	"github.com/detiber/k8s-jumperless/jumperless/remotepb"
+)
+
+var ErrRemotePortClosed = errors.New("remote serial port closed")
+var ErrRemoteOperation = errors.New("remote serial port operation failed")
+var ErrRemoteTimeout = errors.New("remote serial port did not respond")
+var ErrUnexpectedRemoteResponse = errors.New("unexpected response of remote serial port")
+
+const (
+	// remoteOpenTimeout bounds opening a port through the Remote service, including probing the device
+	remoteOpenTimeout = 30 * time.Second
+
+	// remoteControlTimeout bounds waiting for the result of an operation other than reading and writing
+	remoteControlTimeout = 10 * time.Second
+)
+
+// RemoteClient drives the Jumperless devices served by the Remote gRPC service, e.g. by the node agent or the
+// proxy of jumperless-utils. Ports opened with its Opener behave like local serial ports, so the devices are
+// driven by the same code as devices attached to the local host.
+type RemoteClient struct {
+	client remotepb.RemoteClient
+	conn   *grpc.ClientConn // Closed by Close if the client created it
+}
+
+// NewRemoteClient returns a client using an existing connection to a server of the Remote service
+func NewRemoteClient(conn grpc.ClientConnInterface) *RemoteClient {
+	return &RemoteClient{client: remotepb.NewRemoteClient(conn)}
+}
+
+// DialRemote returns a client connecting to the server of the Remote service at endpoint, a host:port address.
+// The connection is not encrypted unless dial options with other transport credentials are passed.
+func DialRemote(endpoint string, opts ...grpc.DialOption) (*RemoteClient, error) {
+	opts = append([]grpc.DialOption{grpc.WithTransportCredentials(insecure.NewCredentials())}, opts...)
+
+	conn, err := grpc.NewClient(endpoint, opts...)
+	if err != nil {
+		return nil, fmt.Errorf("unable to connect to %s: %w", endpoint, err)
+	}
+
+	return &RemoteClient{client: remotepb.NewRemoteClient(conn), conn: conn}, nil
+}
+
+// Close closes the connection of a client created by DialRemote
+func (c *RemoteClient) Close() error {
+	if c.conn == nil {
+		return nil
+	}
+
+	return c.conn.Close() //nolint:wrapcheck
+}
+
+// Identity describes the server and the devices it serves
+func (c *RemoteClient) Identity(ctx context.Context) (*remotepb.Identity, error) {
+	return c.client.GetIdentity(ctx, &remotepb.GetIdentityRequest{}) //nolint:wrapcheck
+}
+
+// Devices returns the devices served by the server
+func (c *RemoteClient) Devices(ctx context.Context) ([]DeviceInfo, error) {
+	identity, err := c.Identity(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	devices := make([]DeviceInfo, 0, len(identity.GetDevices()))
+	for _, device := range identity.GetDevices() {
+		devices = append(devices, DeviceInfo{
+			Port:         device.GetPort(),
+			SerialNumber: device.GetSerialNumber(),
+			VID:          device.GetVid(),
+			PID:          device.GetPid(),
+			Version:      device.GetFirmwareVersion(),
+		})
+	}
+
+	return devices, nil
+}
+
+// Exec executes a command on the device on a port of the server, which is opened just for the command. The
+// server waits up to wait for the output after writing the command. If python is set, the command is executed
+// with the MicroPython REPL of the device.
+func (c *RemoteClient) Exec(ctx context.Context, portName string, baudRate int, command string, python bool,
+	wait time.Duration) (string, error) {
+	resp, err := c.client.Exec(ctx, &remotepb.ExecRequest{
+		Open:    &remotepb.OpenRequest{Port: portName, Mode: modeToProto(&serial.Mode{BaudRate: baudRate})},
+		Command: command,
+		Python:  python,
+		WaitMs:  wait.Milliseconds(),
+	})
+	if err != nil {
+		return "", err //nolint:wrapcheck
+	}
+
+	return resp.GetOutput(), nil
+}
+
+// Console attaches rw to the raw console of the device on a port of the server until ctx is done or reading
+// rw fails, see Jumperless.Console
+func (c *RemoteClient) Console(ctx context.Context, portName string, baudRate int, rw io.ReadWriter) error {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	stream, err := c.client.StreamConsole(ctx)
+	if err != nil {
+		return err //nolint:wrapcheck
+	}
+
+	if err := stream.Send(&remotepb.ConsoleInput{Input: &remotepb.ConsoleInput_Open{Open: &remotepb.OpenRequest{
+		Port: portName,
+		Mode: modeToProto(&serial.Mode{BaudRate: baudRate}),
+	}}}); err != nil {
+		return err //nolint:wrapcheck
+	}
+
+	inputErr := make(chan error, 1)
+	go func() {
+		buff := make([]byte, 1024)
+		for {
+			n, err := rw.Read(buff)
+			if n > 0 {
+				input := &remotepb.ConsoleInput{Input: &remotepb.ConsoleInput_Data{Data: bytes.Clone(buff[:n])}}
+				if err := stream.Send(input); err != nil {
+					return
+				}
+			}
+
+			if err != nil {
+				inputErr <- err
+				return
+			}
+		}
+	}()
+
+	outputErr := make(chan error, 1)
+	go func() {
+		for {
+			output, err := stream.Recv()
+			if err != nil {
+				outputErr <- err
+				return
+			}
+
+			if _, err := rw.Write(output.GetData()); err != nil {
+				outputErr <- fmt.Errorf("unable to write console output: %w", err)
+				return
+			}
+		}
+	}()
+
+	select {
+	case <-ctx.Done():
+		return nil
+	case err := <-inputErr:
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+
+		return fmt.Errorf("unable to read console input: %w", err)
+	case err := <-outputErr:
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+
+		return err
+	}
+}
+
+// Opener returns an Opener opening ports of the server, the port stays open on the server until it is closed
+func (c *RemoteClient) Opener() Opener {
+	return func(portName string, mode *serial.Mode) (serial.Port, error) {
+		return c.openPort(portName, mode)
+	}
+}
+
+// openPort opens a port of the server for the lifetime of an OpenPort stream
+func (c *RemoteClient) openPort(portName string, mode *serial.Mode) (serial.Port, error) {
+	ctx, cancel := context.WithCancel(context.Background())
+	timeout := time.AfterFunc(remoteOpenTimeout, cancel)
+	defer timeout.Stop()
+
+	stream, err := c.client.OpenPort(ctx)
+	if err != nil {
+		cancel()
+		return nil, err //nolint:wrapcheck
+	}
+
+	if err := stream.Send(&remotepb.PortRequest{Request: &remotepb.PortRequest_Open{Open: &remotepb.OpenRequest{
+		Port: portName,
+		Mode: modeToProto(mode),
+	}}}); err != nil && !errors.Is(err, io.EOF) {
+		cancel()
+		return nil, err //nolint:wrapcheck
+	}
+
+	// The error of a failed open is returned by Recv, sending only reports that the stream ended
+	resp, err := stream.Recv()
+	if err != nil {
+		cancel()
+		return nil, err //nolint:wrapcheck
+	}
+
+	if resp.GetOpened() == nil {
+		cancel()
+		return nil, fmt.Errorf("%w: expected the port to be opened", ErrUnexpectedRemoteResponse)
+	}
+
+	port := &remotePort{
+		stream:      stream,
+		cancel:      cancel,
+		readTimeout: serial.NoTimeout,
+		pending:     map[uint64]chan *remotepb.ControlResponse{},
+		notify:      make(chan struct{}, 1),
+		done:        make(chan struct{}),
+	}
+	go port.receive()
+
+	return port, nil
+}
+
+// remotePort is a serial port opened through the Remote service. The output of the port is streamed by the
+// server as it is read, and buffered until it is read from the remotePort.
+type remotePort struct {
+	stream remotepb.Remote_OpenPortClient
+	cancel context.CancelFunc
+
+	sendLock sync.Mutex
+
+	mu          sync.Mutex // Guards the fields below
+	buffer      []byte
+	err         error // Set once the stream ended
+	readTimeout time.Duration
+	nextID      uint64
+	pending     map[uint64]chan *remotepb.ControlResponse
+
+	notify chan struct{} // Signaled when data was received or the stream ended
+	done   chan struct{} // Closed once the stream ended
+}
+
+var _ serial.Port = &remotePort{}
+
+// receive buffers the data and delivers the control responses received on the stream until it ends
+func (p *remotePort) receive() {
+	for {
+		resp, err := p.stream.Recv()
+		if err != nil {
+			if errors.Is(err, io.EOF) {
+				err = ErrRemotePortClosed
+			}
+
+			p.fail(err)
+
+			return
+		}
+
+		switch response := resp.GetResponse().(type) {
+		case *remotepb.PortResponse_Data:
+			p.mu.Lock()
+			p.buffer = append(p.buffer, response.Data...)
+			p.mu.Unlock()
+
+			p.signal()
+		case *remotepb.PortResponse_Control:
+			p.mu.Lock()
+			result, ok := p.pending[response.Control.GetId()]
+			delete(p.pending, response.Control.GetId())
+			p.mu.Unlock()
+
+			if ok {
+				result <- response.Control
+			}
+		}
+	}
+}
+
+// fail records the error the stream ended with, unless it already ended
+func (p *remotePort) fail(err error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.err != nil {
+		return
+	}
+
+	p.err = err
+	close(p.done)
+	p.signal()
+}
+
+// signal wakes up a pending read
+func (p *remotePort) signal() {
+	select {
+	case p.notify <- struct{}{}:
+	default:
+	}
+}
+
+// streamErr returns the error the stream ended with
+func (p *remotePort) streamErr() error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	return p.err
+}
+
+// send sends a request on the stream, returning the error the stream ended with if it ended
+func (p *remotePort) send(req *remotepb.PortRequest) error {
+	if err := p.streamErr(); err != nil {
+		return err
+	}
+
+	p.sendLock.Lock()
+	err := p.stream.Send(req)
+	p.sendLock.Unlock()
+
+	// Send only reports that the stream ended, the error it ended with is returned by Recv
+	if errors.Is(err, io.EOF) {
+		<-p.done
+		return p.streamErr()
+	}
+
+	return err //nolint:wrapcheck
+}
+
+// control applies an operation to the port, waiting for the result
+func (p *remotePort) control(req *remotepb.ControlRequest, timeout time.Duration) (*remotepb.ControlResponse,
+	error) {
+	result := make(chan *remotepb.ControlResponse, 1)
+
+	p.mu.Lock()
+	p.nextID++
+	req.Id = p.nextID
+	p.pending[req.Id] = result
+	p.mu.Unlock()
+
+	defer func() {
+		p.mu.Lock()
+		delete(p.pending, req.Id)
+		p.mu.Unlock()
+	}()
+
+	if err := p.send(&remotepb.PortRequest{Request: &remotepb.PortRequest_Control{Control: req}}); err != nil {
+		return nil, err
+	}
+
+	timer := time.NewTimer(timeout)
+	defer timer.Stop()
+
+	select {
+	case resp := <-result:
+		if resp.GetError() != "" {
+			return nil, fmt.Errorf("%w: %s: %s", ErrRemoteOperation, req.GetOperation(), resp.GetError())
+		}
+
+		return resp, nil
+	case <-p.done:
+		return nil, p.streamErr()
+	case <-timer.C:
+		return nil, fmt.Errorf("%w: %s", ErrRemoteTimeout, req.GetOperation())
+	}
+}
+
+// SetMode implements serial.Port
+func (p *remotePort) SetMode(mode *serial.Mode) error {
+	_, err := p.control(&remotepb.ControlRequest{Operation: remotepb.Operation_OPERATION_SET_MODE,
+		Mode: modeToProto(mode)}, remoteControlTimeout)
+
+	return err
+}
+
+// Read implements serial.Port, returning the buffered output of the port or waiting up to the read timeout for
+// output to arrive
+func (p *remotePort) Read(b []byte) (int, error) {
+	p.mu.Lock()
+	timeout := p.readTimeout
+	p.mu.Unlock()
+
+	var deadline <-chan time.Time
+	if timeout != serial.NoTimeout {
+		timer := time.NewTimer(timeout)
+		defer timer.Stop()
+
+		deadline = timer.C
+	}
+
+	for {
+		p.mu.Lock()
+		if len(p.buffer) > 0 {
+			n := copy(b, p.buffer)
+			p.buffer = p.buffer[n:]
+			p.mu.Unlock()
+
+			return n, nil
+		}
+		err := p.err
+		p.mu.Unlock()
+
+		if err != nil {
+			return 0, err
+		}
+
+		select {
+		case <-p.notify:
+		case <-deadline:
+			return 0, nil
+		}
+	}
+}
+
+// Write implements serial.Port, the data is written by the server without waiting for the result. A write
+// failing on the server closes the port, so the error is returned by the next call.
+func (p *remotePort) Write(b []byte) (int, error) {
+	if err := p.send(&remotepb.PortRequest{Request: &remotepb.PortRequest_Data{Data: bytes.Clone(b)}}); err != nil {
+		return 0, err
+	}
+
+	return len(b), nil
+}
+
+// Drain implements serial.Port
+func (p *remotePort) Drain() error {
+	_, err := p.control(&remotepb.ControlRequest{Operation: remotepb.Operation_OPERATION_DRAIN},
+		remoteControlTimeout)
+
+	return err
+}
+
+// ResetInputBuffer implements serial.Port, discarding the output that was received before the input buffer
+// of the port was reset
+func (p *remotePort) ResetInputBuffer() error {
+	if _, err := p.control(&remotepb.ControlRequest{Operation: remotepb.Operation_OPERATION_RESET_INPUT_BUFFER},
+		remoteControlTimeout); err != nil {
+		return err
+	}
+
+	p.mu.Lock()
+	p.buffer = nil
+	p.mu.Unlock()
+
+	return nil
+}
+
+// ResetOutputBuffer implements serial.Port
+func (p *remotePort) ResetOutputBuffer() error {
+	_, err := p.control(&remotepb.ControlRequest{Operation: remotepb.Operation_OPERATION_RESET_OUTPUT_BUFFER},
+		remoteControlTimeout)
+
+	return err
+}
+
+// SetDTR implements serial.Port
+func (p *remotePort) SetDTR(dtr bool) error {
+	_, err := p.control(&remotepb.ControlRequest{Operation: remotepb.Operation_OPERATION_SET_DTR, Value: dtr},
+		remoteControlTimeout)
+
+	return err
+}
+
+// SetRTS implements serial.Port
+func (p *remotePort) SetRTS(rts bool) error {
+	_, err := p.control(&remotepb.ControlRequest{Operation: remotepb.Operation_OPERATION_SET_RTS, Value: rts},
+		remoteControlTimeout)
+
+	return err
+}
+
+// GetModemStatusBits implements serial.Port
+func (p *remotePort) GetModemStatusBits() (*serial.ModemStatusBits, error) {
+	resp, err := p.control(&remotepb.ControlRequest{
+		Operation: remotepb.Operation_OPERATION_GET_MODEM_STATUS_BITS,
+	}, remoteControlTimeout)
+	if err != nil {
+		return nil, err
+	}
+
+	status := resp.GetModemStatus()
+
+	return &serial.ModemStatusBits{
+		CTS: status.GetCts(),
+		DSR: status.GetDsr(),
+		RI:  status.GetRi(),
+		DCD: status.GetDcd(),
+	}, nil
+}
+
+// SetReadTimeout implements serial.Port, the timeout only applies to waiting for output from the server
+func (p *remotePort) SetReadTimeout(t time.Duration) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.readTimeout = t
+
+	return nil
+}
+
+// Close implements serial.Port, ending the stream so the server closes the port
+func (p *remotePort) Close() error {
+	p.sendLock.Lock()
+	err := p.stream.CloseSend()
+	p.sendLock.Unlock()
+
+	// The server closes the port once the stream was closed, the stream is canceled if it doesn't end in time
+	select {
+	case <-p.done:
+	case <-time.After(remoteControlTimeout):
+	}
+
+	p.fail(ErrRemotePortClosed)
+	p.cancel()
+
+	return err //nolint:wrapcheck
+}
+
+// Break implements serial.Port
+func (p *remotePort) Break(duration time.Duration) error {
+	_, err := p.control(&remotepb.ControlRequest{Operation: remotepb.Operation_OPERATION_BREAK,
+		DurationMs: duration.Milliseconds()}, remoteControlTimeout+duration)
+
+	return err
+}
+
+// modeToProto returns the line settings of a serial.Mode
+func modeToProto(mode *serial.Mode) *remotepb.Mode {
+	if mode == nil {
+		return nil
+	}
+
+	return &remotepb.Mode{
+		BaudRate: int32(mode.BaudRate),
+		DataBits: int32(mode.DataBits),
+		Parity:   int32(mode.Parity),
+		StopBits: int32(mode.StopBits),
+	}
+}
+
+// modeFromProto returns the serial.Mode of line settings, defaulting to 115200 baud and 8 data bits
+func modeFromProto(mode *remotepb.Mode) *serial.Mode {
+	result := &serial.Mode{
+		BaudRate: int(mode.GetBaudRate()),
+		DataBits: int(mode.GetDataBits()),
+		Parity:   serial.Parity(mode.GetParity()),
+		StopBits: serial.StopBits(mode.GetStopBits()),
+	}
+
+	if result.BaudRate == 0 {
+		result.BaudRate = 115200
+	}
+
+	if result.DataBits == 0 {
+		result.DataBits = 8
+	}
+
+	return result
+}
@@ -0,0 +1,383 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package jumperless
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"sync"
+	"time"
+
+	"go.bug.st/serial"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/detiber/k8s-jumperless/jumperless/remotepb"
+)
+
+// remoteReadInterval bounds each read of a port opened through the Remote service, so the end of the stream is
+// noticed between reads
+const remoteReadInterval = 20 * time.Millisecond
+
+// remoteReadSize is the most output of a port sent in a single response
+const remoteReadSize = 4096
+
+// RemoteBackend provides the devices served by a RemoteServer
+type RemoteBackend interface {
+	// Identity describes the server and the devices it serves
+	Identity(ctx context.Context) (*remotepb.Identity, error)
+
+	// OpenPort opens the serial port of a device, or the port of the only device if name is empty, returning
+	// the name of the port that was opened. Errors may be gRPC status errors.
+	OpenPort(ctx context.Context, name string, mode *serial.Mode) (string, serial.Port, error)
+}
+
+// RemoteServer serves the devices of a RemoteBackend with the Remote gRPC service
+type RemoteServer struct {
+	remotepb.UnimplementedRemoteServer
+
+	backend RemoteBackend
+}
+
+// NewRemoteServer returns a server of the Remote service for the devices of backend
+func NewRemoteServer(backend RemoteBackend) *RemoteServer {
+	return &RemoteServer{backend: backend}
+}
+
+// Register registers the Remote service on a gRPC server
+func (s *RemoteServer) Register(registrar grpc.ServiceRegistrar) {
+	remotepb.RegisterRemoteServer(registrar, s)
+}
+
+// GetIdentity implements remotepb.RemoteServer
+func (s *RemoteServer) GetIdentity(ctx context.Context, _ *remotepb.GetIdentityRequest) (*remotepb.Identity,
+	error) {
+	identity, err := s.backend.Identity(ctx)
+	if err != nil {
+		return nil, remoteStatus(err)
+	}
+
+	return identity, nil
+}
+
+// OpenPort implements remotepb.RemoteServer, streaming the output of the port while applying the requests of
+// the client until either side ends the stream
+func (s *RemoteServer) OpenPort(stream remotepb.Remote_OpenPortServer) error {
+	req, err := stream.Recv()
+	if err != nil {
+		return err //nolint:wrapcheck
+	}
+
+	open := req.GetOpen()
+	if open == nil {
+		return status.Error(codes.InvalidArgument, "the first request must open the port")
+	}
+
+	name, port, err := s.backend.OpenPort(stream.Context(), open.GetPort(), modeFromProto(open.GetMode()))
+	if err != nil {
+		return remoteStatus(err)
+	}
+	defer func() { _ = port.Close() }()
+
+	if err := port.SetReadTimeout(remoteReadInterval); err != nil {
+		return remoteStatus(err)
+	}
+
+	var sendLock sync.Mutex
+	send := func(resp *remotepb.PortResponse) error {
+		sendLock.Lock()
+		defer sendLock.Unlock()
+
+		return stream.Send(resp)
+	}
+
+	opened := &remotepb.PortResponse{Response: &remotepb.PortResponse_Opened{
+		Opened: &remotepb.OpenResponse{Port: name},
+	}}
+	if err := send(opened); err != nil {
+		return err //nolint:wrapcheck
+	}
+
+	ctx, cancel := context.WithCancel(stream.Context())
+	var wg sync.WaitGroup
+	defer func() {
+		cancel()
+		wg.Wait()
+	}()
+
+	readErr := make(chan error, 1)
+	wg.Go(func() { readErr <- readRemotePort(ctx, port, send) })
+
+	requests, recvErr := receive(ctx, stream.Recv)
+
+	for {
+		select {
+		case err := <-readErr:
+			return remoteStatus(err)
+		case err := <-recvErr:
+			if errors.Is(err, io.EOF) {
+				return nil
+			}
+
+			return err
+		case req := <-requests:
+			if err := applyPortRequest(port, req, send); err != nil {
+				return remoteStatus(err)
+			}
+		}
+	}
+}
+
+// receive receives messages on a stream until ctx is done, returning the channels the messages and the error
+// the stream ended with are sent to
+func receive[T any](ctx context.Context, recv func() (*T, error)) (<-chan *T, <-chan error) {
+	messages := make(chan *T)
+	errs := make(chan error, 1)
+
+	go func() {
+		for {
+			message, err := recv()
+			if err != nil {
+				errs <- err
+				return
+			}
+
+			select {
+			case messages <- message:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	return messages, errs
+}
+
+// readRemotePort sends the output of a port until ctx is done or reading the port fails
+func readRemotePort(ctx context.Context, port serial.Port, send func(*remotepb.PortResponse) error) error {
+	buff := make([]byte, remoteReadSize)
+	for ctx.Err() == nil {
+		n, err := port.Read(buff)
+		if err != nil {
+			return err //nolint:wrapcheck
+		}
+
+		if n == 0 {
+			continue
+		}
+
+		data := &remotepb.PortResponse{Response: &remotepb.PortResponse_Data{Data: bytes.Clone(buff[:n])}}
+		if err := send(data); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// applyPortRequest writes to or controls a port. Failing writes end the stream, failing operations are
+// reported in their response.
+func applyPortRequest(port serial.Port, req *remotepb.PortRequest,
+	send func(*remotepb.PortResponse) error) error {
+	switch request := req.GetRequest().(type) {
+	case *remotepb.PortRequest_Data:
+		for data := request.Data; len(data) > 0; {
+			n, err := port.Write(data)
+			if err != nil {
+				return err //nolint:wrapcheck
+			}
+
+			data = data[n:]
+		}
+
+		return nil
+	case *remotepb.PortRequest_Control:
+		resp := controlPort(port, request.Control)
+		return send(&remotepb.PortResponse{Response: &remotepb.PortResponse_Control{Control: resp}})
+	default:
+		return status.Error(codes.InvalidArgument, "the port is already open")
+	}
+}
+
+// controlPort applies an operation to a port
+func controlPort(port serial.Port, req *remotepb.ControlRequest) *remotepb.ControlResponse {
+	resp := &remotepb.ControlResponse{Id: req.GetId()}
+
+	var err error
+	switch req.GetOperation() {
+	case remotepb.Operation_OPERATION_SET_MODE:
+		err = port.SetMode(modeFromProto(req.GetMode()))
+	case remotepb.Operation_OPERATION_DRAIN:
+		err = port.Drain()
+	case remotepb.Operation_OPERATION_RESET_INPUT_BUFFER:
+		err = port.ResetInputBuffer()
+	case remotepb.Operation_OPERATION_RESET_OUTPUT_BUFFER:
+		err = port.ResetOutputBuffer()
+	case remotepb.Operation_OPERATION_SET_DTR:
+		err = port.SetDTR(req.GetValue())
+	case remotepb.Operation_OPERATION_SET_RTS:
+		err = port.SetRTS(req.GetValue())
+	case remotepb.Operation_OPERATION_BREAK:
+		err = port.Break(time.Duration(req.GetDurationMs()) * time.Millisecond)
+	case remotepb.Operation_OPERATION_GET_MODEM_STATUS_BITS:
+		var bits *serial.ModemStatusBits
+		bits, err = port.GetModemStatusBits()
+		if err == nil {
+			resp.ModemStatus = &remotepb.ModemStatus{Cts: bits.CTS, Dsr: bits.DSR, Ri: bits.RI, Dcd: bits.DCD}
+		}
+	default:
+		resp.Error = "unknown operation " + req.GetOperation().String()
+	}
+
+	if err != nil {
+		resp.Error = err.Error()
+	}
+
+	return resp
+}
+
+// openRemoteDevice verifies that the port of the backend selected by open is a Jumperless device and opens it
+func (s *RemoteServer) openRemoteDevice(ctx context.Context, open *remotepb.OpenRequest) (*Jumperless, error) {
+	mode := modeFromProto(open.GetMode())
+	opener := func(portName string, mode *serial.Mode) (serial.Port, error) {
+		_, port, err := s.backend.OpenPort(ctx, portName, mode)
+		return port, err
+	}
+
+	j, err := NewJumperlessWithOpener(open.GetPort(), mode.BaudRate, opener)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := j.OpenPort(); err != nil {
+		return nil, err
+	}
+
+	return j, nil
+}
+
+// Exec implements remotepb.RemoteServer
+func (s *RemoteServer) Exec(ctx context.Context, req *remotepb.ExecRequest) (*remotepb.ExecResponse, error) {
+	j, err := s.openRemoteDevice(ctx, req.GetOpen())
+	if err != nil {
+		return nil, remoteStatus(err)
+	}
+	defer func() { _ = j.ClosePort() }()
+
+	wait := time.Duration(req.GetWaitMs()) * time.Millisecond
+
+	var output string
+	if req.GetPython() {
+		output, err = j.ExecPythonCommand(req.GetCommand(), wait)
+	} else {
+		output, err = j.ExecRawCommand(req.GetCommand(), wait)
+	}
+	if err != nil {
+		return nil, remoteStatus(err)
+	}
+
+	return &remotepb.ExecResponse{Output: output, FirmwareVersion: j.GetVersion()}, nil
+}
+
+// StreamConsole implements remotepb.RemoteServer
+func (s *RemoteServer) StreamConsole(stream remotepb.Remote_StreamConsoleServer) error {
+	input, err := stream.Recv()
+	if err != nil {
+		return err //nolint:wrapcheck
+	}
+
+	open := input.GetOpen()
+	if open == nil {
+		return status.Error(codes.InvalidArgument, "the first input must open the port")
+	}
+
+	j, err := s.openRemoteDevice(stream.Context(), open)
+	if err != nil {
+		return remoteStatus(err)
+	}
+	defer func() { _ = j.ClosePort() }()
+
+	if err := j.Console(stream.Context(), &consoleStream{stream: stream}); err != nil {
+		return remoteStatus(err)
+	}
+
+	return nil
+}
+
+// consoleStream reads the data of the inputs of a StreamConsole stream and writes outputs to it
+type consoleStream struct {
+	stream  remotepb.Remote_StreamConsoleServer
+	pending []byte
+}
+
+// Read implements io.Reader, returning io.EOF once the client closed the stream
+func (c *consoleStream) Read(b []byte) (int, error) {
+	for len(c.pending) == 0 {
+		input, err := c.stream.Recv()
+		if err != nil {
+			return 0, err //nolint:wrapcheck
+		}
+
+		if input.GetOpen() != nil {
+			return 0, status.Error(codes.InvalidArgument, "the port is already open")
+		}
+
+		c.pending = input.GetData()
+	}
+
+	n := copy(b, c.pending)
+	c.pending = c.pending[n:]
+
+	return n, nil
+}
+
+// Write implements io.Writer
+func (c *consoleStream) Write(b []byte) (int, error) {
+	if err := c.stream.Send(&remotepb.ConsoleOutput{Data: bytes.Clone(b)}); err != nil {
+		return 0, err //nolint:wrapcheck
+	}
+
+	return len(b), nil
+}
+
+// remoteStatus returns the gRPC status of an error, errors that are status errors already are returned as is
+func remoteStatus(err error) error {
+	if err == nil {
+		return nil
+	}
+
+	if _, ok := status.FromError(err); ok {
+		return err
+	}
+
+	code := codes.Unknown
+	switch {
+	case errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded):
+		return status.FromContextError(err).Err()
+	case errors.Is(err, ErrNoJumperlessFound) || errors.Is(err, ErrNoSerialPortFound):
+		code = codes.NotFound
+	case errors.Is(err, ErrConsoleAttached) || errors.Is(err, ErrDeviceLocked):
+		code = codes.FailedPrecondition
+	case errors.Is(err, ErrSerialRead) || errors.Is(err, ErrDeviceBusy):
+		code = codes.Unavailable
+	}
+
+	return status.Error(code, err.Error())
+}
@@ -0,0 +1,227 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package jumperless
+
+import (
+	"context"
+	"io"
+	"net"
+	"testing"
+	"time"
+
+	. "github.com/onsi/gomega"
+	"go.bug.st/serial"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+
+	"github.com/detiber/k8s-jumperless/jumperless/remotepb"
+	"github.com/detiber/k8s-jumperless/jumperless/serialmock"
+)
+
+// mockBackend serves a single mock port with the Remote service
+type mockBackend struct {
+	port *serialmock.Port
+}
+
+func (b *mockBackend) Identity(context.Context) (*remotepb.Identity, error) {
+	return &remotepb.Identity{Name: "host-a", Kind: "mock", Devices: []*remotepb.Device{
+		{Port: "/dev/ttyMock", SerialNumber: "E6614103E7", FirmwareVersion: "5.3.1.0"},
+	}}, nil
+}
+
+func (b *mockBackend) OpenPort(_ context.Context, name string, mode *serial.Mode) (string, serial.Port, error) {
+	if name != "" && name != "/dev/ttyMock" {
+		return "", nil, status.Errorf(codes.NotFound, "unknown port %s", name)
+	}
+
+	port, err := b.port.Open(name, mode)
+	if err != nil {
+		return "", nil, err
+	}
+
+	return "/dev/ttyMock", &pacedPort{Port: port}, nil
+}
+
+// pacedPort waits briefly on reads without output like a serial port waiting for its read timeout, so the
+// server doesn't spin reading the mock port
+type pacedPort struct {
+	serial.Port
+}
+
+func (p *pacedPort) Read(b []byte) (int, error) {
+	n, err := p.Port.Read(b)
+	if n == 0 && err == nil {
+		time.Sleep(time.Millisecond)
+	}
+
+	return n, err
+}
+
+// serveRemote serves the Remote service for a mock port in memory, returning a client connected to it
+func serveRemote(t *testing.T, port *serialmock.Port) *RemoteClient {
+	t.Helper()
+
+	listener := bufconn.Listen(1 << 20)
+	server := grpc.NewServer()
+	NewRemoteServer(&mockBackend{port: port}).Register(server)
+
+	go func() { _ = server.Serve(listener) }()
+	t.Cleanup(server.Stop)
+
+	conn, err := grpc.NewClient("passthrough:///remote",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) {
+			return listener.DialContext(ctx)
+		}),
+		grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { _ = conn.Close() })
+
+	return NewRemoteClient(conn)
+}
+
+func TestRemoteClientOpener(t *testing.T) {
+	g := NewWithT(t)
+
+	port := serialmock.New()
+	port.Expect("?").Respond("Jumperless firmware version: 5.3.1.0\r\n")
+	port.Expect(">dac_get(0)").RespondAfter(20*time.Millisecond, "Python> >dac_get(0)\r\n3.3\r\n")
+	port.SetModemStatusBits(serial.ModemStatusBits{CTS: true})
+
+	c := serveRemote(t, port)
+
+	devices, err := c.Devices(t.Context())
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(devices).To(Equal([]DeviceInfo{{Port: "/dev/ttyMock", SerialNumber: "E6614103E7", Version: "5.3.1.0"}}))
+
+	// The device is driven through the stream like a local one
+	j, err := NewJumperlessWithOpener("/dev/ttyMock", 0, c.Opener())
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(j.GetVersion()).To(Equal("5.3.1.0"))
+	g.Eventually(port.IsOpen).Should(BeFalse())
+
+	g.Expect(j.OpenPort()).To(Succeed())
+	g.Expect(port.Mode().BaudRate).To(Equal(115200))
+
+	result, err := j.ExecPythonCommand("dac_get(0)", 0, SingleLine())
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(result).To(Equal("3.3"))
+
+	// Operations other than reading and writing are applied by the server
+	g.Expect(j.port.port.SetDTR(false)).To(Succeed())
+	g.Expect(port.DTR()).To(BeFalse())
+	g.Expect(j.port.port.Break(10 * time.Millisecond)).To(Succeed())
+	g.Expect(port.Breaks()).To(Equal([]time.Duration{10 * time.Millisecond}))
+
+	bits, err := j.port.port.GetModemStatusBits()
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(bits.CTS).To(BeTrue())
+
+	g.Expect(j.ClosePort()).To(Succeed())
+	g.Eventually(port.IsOpen).Should(BeFalse())
+	g.Expect(port.ExpectationsMet()).To(Succeed())
+}
+
+func TestRemoteClientExec(t *testing.T) {
+	g := NewWithT(t)
+
+	port := serialmock.New()
+	port.Expect("?").Respond("Jumperless firmware version: 5.3.1.0\r\n")
+	port.Expect("~").Respond("\r\nJumperless Config:\r\n").RespondAfter(20*time.Millisecond, "END\n")
+
+	c := serveRemote(t, port)
+
+	output, err := c.Exec(t.Context(), "", 0, "~", false, 100*time.Millisecond)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(output).To(ContainSubstring("Jumperless Config:"))
+	g.Expect(port.IsOpen()).To(BeFalse())
+	g.Expect(port.ExpectationsMet()).To(Succeed())
+
+	// Errors of the backend are returned with their status
+	_, err = c.Exec(t.Context(), "/dev/ttyACM9", 0, "~", false, 0)
+	g.Expect(status.Code(err)).To(Equal(codes.NotFound))
+}
+
+func TestRemoteClientConsole(t *testing.T) {
+	g := NewWithT(t)
+
+	port := serialmock.New()
+	port.Expect("?").Respond("Jumperless firmware version: 5.3.1.0\r\n")
+
+	c := serveRemote(t, port)
+
+	client, server := net.Pipe()
+	defer func() { _ = client.Close() }()
+
+	consoleErr := make(chan error, 1)
+	go func() {
+		consoleErr <- c.Console(t.Context(), "/dev/ttyMock", 0, server)
+		_ = server.Close()
+	}()
+
+	// Input is forwarded to the device and its output to the console once the port is open
+	g.Eventually(port.Writes).Should(HaveLen(1))
+	port.Expect("help\r\n").Respond("usage: help\r\n")
+	_, err := client.Write([]byte("help\r\n"))
+	g.Expect(err).NotTo(HaveOccurred())
+
+	output := make([]byte, len("usage: help\r\n"))
+	g.Expect(client.SetReadDeadline(time.Now().Add(time.Second))).To(Succeed())
+	_, err = io.ReadFull(client, output)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(string(output)).To(Equal("usage: help\r\n"))
+
+	// Closing the console input detaches the console and closes the port
+	g.Expect(client.Close()).To(Succeed())
+	g.Eventually(consoleErr).Should(Receive(BeNil()))
+	g.Eventually(port.IsOpen).Should(BeFalse())
+	g.Expect(port.ExpectationsMet()).To(Succeed())
+}
+
+func TestRemoteServerErrors(t *testing.T) {
+	g := NewWithT(t)
+
+	port := serialmock.New()
+	c := serveRemote(t, port)
+
+	// The first request of a stream has to open the port
+	stream, err := c.client.OpenPort(t.Context())
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(stream.Send(&remotepb.PortRequest{Request: &remotepb.PortRequest_Data{Data: []byte("?")}})).To(Succeed())
+	_, err = stream.Recv()
+	g.Expect(status.Code(err)).To(Equal(codes.InvalidArgument))
+
+	_, err = c.Opener()("/dev/ttyACM9", nil)
+	g.Expect(status.Code(err)).To(Equal(codes.NotFound))
+
+	// Failing operations are returned without closing the port
+	remote, err := c.Opener()("", nil)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(port.IsOpen()).To(BeTrue())
+
+	g.Expect(port.Close()).To(Succeed())
+	g.Expect(remote.SetDTR(false)).To(MatchError(ErrRemoteOperation))
+
+	// Reads failing on the server end the stream
+	_, err = remote.Read(make([]byte, 1))
+	g.Expect(status.Code(err)).To(Equal(codes.Unknown))
+	g.Expect(remote.Close()).To(Succeed())
+}
@@ -0,0 +1,110 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package jumperless
+
+import (
+	"errors"
+	"time"
+
+	"k8s.io/apimachinery/pkg/util/wait"
+)
+
+// RetryPolicy decides how often idempotent commands are retried when they fail with a transient error,
+// e.g. truncated output or a failed read, instead of failing the caller.
+type RetryPolicy struct {
+	// Attempts is the maximum number of times a command is executed, 1 disables retries
+	Attempts int
+
+	// Backoff is the delay between attempts, its Steps are ignored in favor of Attempts
+	Backoff wait.Backoff
+}
+
+// DefaultRetryPolicy returns the RetryPolicy used unless another one is set.
+func DefaultRetryPolicy() RetryPolicy {
+	return RetryPolicy{
+		Attempts: 3,
+		Backoff: wait.Backoff{
+			Duration: 50 * time.Millisecond,
+			Factor:   2.0,
+			Jitter:   0.1,
+		},
+	}
+}
+
+// CommandOption configures the execution of a single command
+type CommandOption func(*commandOptions)
+
+type commandOptions struct {
+	idempotent     bool
+	terminators    []string
+	terminatorsSet bool
+	singleLine     bool
+	optionalOutput bool
+}
+
+// Idempotent marks a command as safe to execute more than once, e.g. a read or setting an absolute value,
+// so it is retried according to the RetryPolicy. Other commands are executed once, since a command may have
+// been executed by the device even though its output was lost.
+func Idempotent() CommandOption {
+	return func(o *commandOptions) {
+		o.idempotent = true
+	}
+}
+
+// isTransient reports whether err may not occur when the command is executed again
+func isTransient(err error) bool {
+	return errors.Is(err, ErrUnexpectedCommandOutput) || errors.Is(err, ErrSerialRead) ||
+		errors.Is(err, ErrCommandIncomplete)
+}
+
+// SetRetryPolicy sets the policy used to retry idempotent commands, it must not be called while commands
+// are executed.
+func (j *Jumperless) SetRetryPolicy(policy RetryPolicy) {
+	if j == nil {
+		return
+	}
+
+	j.retryPolicy = &policy
+}
+
+// retry executes a command until it succeeds, fails with an error that is not transient, or the attempts
+// of the retry policy are exhausted. Commands not marked Idempotent are executed once.
+func (j *Jumperless) retry(opts []CommandOption, exec func() (string, error)) (string, error) {
+	options := newCommandOptions("", opts)
+
+	policy := DefaultRetryPolicy()
+	if j.retryPolicy != nil {
+		policy = *j.retryPolicy
+	}
+
+	attempts := 1
+	if options.idempotent {
+		attempts = max(policy.Attempts, 1)
+	}
+
+	backoff := policy.Backoff
+	backoff.Steps = attempts
+
+	for attempt := 1; ; attempt++ {
+		result, err := exec()
+		if err == nil || attempt >= attempts || !isTransient(err) {
+			return result, err
+		}
+
+		time.Sleep(backoff.Step())
+	}
+}
@@ -0,0 +1,88 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package jumperless
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	. "github.com/onsi/gomega"
+)
+
+func TestRetry(t *testing.T) {
+	truncated := fmt.Errorf("expected 3 lines, got 2 %w", ErrUnexpectedCommandOutput)
+	readFailed := fmt.Errorf("%w /dev/ttyACM0: %w", ErrSerialRead, errTest)
+	exception := fmt.Errorf("%w: dac_get(7): ValueError", ErrPythonException)
+
+	tests := []struct {
+		name         string
+		idempotent   bool
+		errs         []error
+		wantErr      error
+		wantAttempts int
+	}{
+		{name: "success", idempotent: true, errs: []error{nil}, wantAttempts: 1},
+		{name: "truncated output", idempotent: true, errs: []error{truncated, nil}, wantAttempts: 2},
+		{name: "read failure", idempotent: true, errs: []error{readFailed, truncated, nil}, wantAttempts: 3},
+		{
+			name:         "attempts exhausted",
+			idempotent:   true,
+			errs:         []error{truncated, truncated, readFailed, nil},
+			wantErr:      ErrSerialRead,
+			wantAttempts: 3,
+		},
+		{name: "not transient", idempotent: true, errs: []error{exception, nil}, wantErr: exception, wantAttempts: 1},
+		{name: "not idempotent", errs: []error{truncated, nil}, wantErr: truncated, wantAttempts: 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			j := &Jumperless{}
+			policy := DefaultRetryPolicy()
+			policy.Backoff.Duration = 0
+			j.SetRetryPolicy(policy)
+
+			opts := []CommandOption{}
+			if tt.idempotent {
+				opts = append(opts, Idempotent())
+			}
+
+			attempts := 0
+			result, err := j.retry(opts, func() (string, error) {
+				err := tt.errs[attempts]
+				attempts++
+				if err != nil {
+					return "", err
+				}
+
+				return "3.30V", nil
+			})
+
+			g.Expect(attempts).To(Equal(tt.wantAttempts))
+			if tt.wantErr != nil {
+				g.Expect(errors.Is(err, tt.wantErr)).To(BeTrue())
+				return
+			}
+
+			g.Expect(err).NotTo(HaveOccurred())
+			g.Expect(result).To(Equal("3.30V"))
+		})
+	}
+}
@@ -0,0 +1,82 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package jumperless
+
+import (
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// maxI2CAddress is the highest 7-bit I2C address
+const maxI2CAddress = 0x7F
+
+// i2cScanWait is how long to wait for the output of an I2C scan, which probes every address of the bus
+const i2cScanWait = 200 * time.Millisecond
+
+// ScanI2C scans the I2C bus with SDA and SCL on the given nodes, e.g. "20" and "21", and returns the sorted
+// 7-bit addresses of the devices that acknowledged their address.
+func (j *Jumperless) ScanI2C(sda, scl string) ([]uint8, error) {
+	command := fmt.Sprintf("i2c_scan(%s, %s)", strconv.Quote(sda), strconv.Quote(scl))
+
+	output, err := j.ExecPythonCommand(command, i2cScanWait, Idempotent(), SingleLine())
+	if err != nil {
+		return nil, fmt.Errorf("unable to scan I2C bus on %s/%s: %w", sda, scl, err)
+	}
+
+	addresses, err := ParseI2CScan(output)
+	if err != nil {
+		return nil, fmt.Errorf("unable to scan I2C bus on %s/%s: %w", sda, scl, err)
+	}
+
+	return addresses, nil
+}
+
+// ParseI2CScan parses the list of addresses returned by i2c_scan, e.g. "[60, 104]", in decimal or hexadecimal.
+// The addresses are returned sorted and without duplicates.
+func ParseI2CScan(output string) ([]uint8, error) {
+	output = strings.TrimSpace(output)
+	if !strings.HasPrefix(output, "[") || !strings.HasSuffix(output, "]") {
+		return nil, fmt.Errorf("%w: expected a list of addresses, got %q", ErrUnexpectedCommandOutput, output)
+	}
+
+	addresses := []uint8{}
+	for field := range strings.SplitSeq(output[1:len(output)-1], ",") {
+		field = strings.TrimSpace(field)
+		if field == "" {
+			continue
+		}
+
+		address, err := strconv.ParseUint(field, 0, 8)
+		if err != nil || address > maxI2CAddress {
+			return nil, fmt.Errorf("%w: invalid I2C address %q", ErrUnexpectedCommandOutput, field)
+		}
+
+		addresses = append(addresses, uint8(address))
+	}
+
+	slices.Sort(addresses)
+
+	return slices.Compact(addresses), nil
+}
+
+// FormatI2CAddress formats a 7-bit I2C address the way datasheets do, e.g. "0x3C".
+func FormatI2CAddress(address uint8) string {
+	return fmt.Sprintf("0x%02X", address)
+}
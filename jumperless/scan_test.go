@@ -0,0 +1,76 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package jumperless
+
+import (
+	"testing"
+
+	. "github.com/onsi/gomega"
+
+	"github.com/detiber/k8s-jumperless/jumperless/serialmock"
+)
+
+func TestParseI2CScan(t *testing.T) {
+	tests := []struct {
+		name    string
+		output  string
+		want    []uint8
+		wantErr bool
+	}{
+		{name: "decimal", output: "[60, 104]", want: []uint8{0x3C, 0x68}},
+		{name: "hexadecimal", output: "[0x68, 0x3c]", want: []uint8{0x3C, 0x68}},
+		{name: "duplicates", output: "[60, 60]", want: []uint8{0x3C}},
+		{name: "no devices", output: "[]", want: []uint8{}},
+		{name: "not a list", output: "60", wantErr: true},
+		{name: "out of range", output: "[128]", wantErr: true},
+		{name: "not an address", output: "[None]", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			addresses, err := ParseI2CScan(tt.output)
+			if tt.wantErr {
+				g.Expect(err).To(MatchError(ErrUnexpectedCommandOutput))
+				return
+			}
+
+			g.Expect(err).NotTo(HaveOccurred())
+			g.Expect(addresses).To(Equal(tt.want))
+		})
+	}
+}
+
+func TestScanI2C(t *testing.T) {
+	g := NewWithT(t)
+
+	port := serialmock.New()
+	port.Expect("?").Respond("Jumperless firmware version: 5.3.1.0\r\n")
+
+	j, err := NewJumperlessWithOpener("/dev/ttyMock", 0, port.Open)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(j.OpenPort()).To(Succeed())
+
+	port.Expect(`>i2c_scan("20", "21")`).Respond("Python> >i2c_scan(\"20\", \"21\")\r\n[60, 104]\r\n")
+	addresses, err := j.ScanI2C("20", "21")
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(addresses).To(Equal([]uint8{0x3C, 0x68}))
+	g.Expect(FormatI2CAddress(addresses[0])).To(Equal("0x3C"))
+	g.Expect(j.ClosePort()).To(Succeed())
+	g.Expect(port.ExpectationsMet()).To(Succeed())
+}
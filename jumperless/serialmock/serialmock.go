@@ -0,0 +1,484 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package serialmock provides a scriptable serial.Port for unit tests of code driving a Jumperless, so the
+// framing, retries and reconnects of a port can be tested without ptys or the emulator.
+//
+// Commands written to the port are matched against the expectations in the order they were added, each
+// expectation schedules its response chunks relative to the time the command was written:
+//
+//	port := serialmock.New()
+//	port.Expect("?").Respond("Jumperless firmware version: 5.3.1.0\r\n")
+//	port.Expect("~").Respond("\r\nJumperless Config:\r\n").RespondAfter(20*time.Millisecond, "END\n")
+//
+//	j, err := jumperless.NewJumperlessWithOpener("/dev/ttyMock", 0, port.Open)
+//	...
+//	err = port.ExpectationsMet()
+package serialmock
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"sync"
+	"time"
+
+	"go.bug.st/serial"
+	kerrors "k8s.io/apimachinery/pkg/util/errors"
+)
+
+var ErrPortClosed = errors.New("mock serial port closed")
+var ErrUnexpectedWrite = errors.New("unexpected write to mock serial port")
+var ErrUnmetExpectation = errors.New("unmet expectation of mock serial port")
+
+// chunk is output scheduled on the port, or a read error if err is set
+type chunk struct {
+	data []byte
+	err  error
+	at   time.Time
+}
+
+// step is a part of the response to an expected command
+type step struct {
+	data  string
+	err   error
+	delay time.Duration
+}
+
+// Expectation is a command expected to be written to the port and the response the port sends for it
+type Expectation struct {
+	request  string
+	steps    []step
+	writeErr error
+	times    int
+	matched  int
+}
+
+// Respond appends data to the response, sent right after the previous part of the response
+func (e *Expectation) Respond(data string) *Expectation {
+	return e.RespondAfter(0, data)
+}
+
+// RespondAfter appends data to the response, sent the delay after the previous part of the response or the
+// command was written
+func (e *Expectation) RespondAfter(delay time.Duration, data string) *Expectation {
+	e.steps = append(e.steps, step{data: data, delay: delay})
+	return e
+}
+
+// FailRead fails the read of the port once the previous part of the response was read
+func (e *Expectation) FailRead(err error) *Expectation {
+	e.steps = append(e.steps, step{err: err})
+	return e
+}
+
+// FailWrite fails writing the command, the port doesn't respond to it
+func (e *Expectation) FailWrite(err error) *Expectation {
+	e.writeErr = err
+	return e
+}
+
+// Times expects the command to be written n times in a row, responding the same way every time
+func (e *Expectation) Times(n int) *Expectation {
+	e.times = n
+	return e
+}
+
+// Port is a serial.Port sending scripted responses to the commands written to it. Reads block until the
+// next part of the response is due or the read timeout passes, a read without any pending output returns
+// right away so tests don't wait for the read timeout at the end of every command.
+type Port struct {
+	mu sync.Mutex
+
+	expectations []*Expectation
+	pending      []chunk
+	writes       []string
+	unexpected   []string
+
+	open        bool
+	opens       int
+	openErrs    []error
+	mode        *serial.Mode
+	readTimeout time.Duration
+	dtr         bool
+	rts         bool
+	breaks      []time.Duration
+	modemStatus serial.ModemStatusBits
+}
+
+var _ serial.Port = &Port{}
+
+// New returns a closed mock port without expectations
+func New() *Port {
+	return &Port{
+		readTimeout: serial.NoTimeout,
+		dtr:         true,
+		rts:         true,
+	}
+}
+
+// Expect adds a command expected to be written to the port after the previously expected commands,
+// the returned expectation scripts the response
+func (p *Port) Expect(request string) *Expectation {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	e := &Expectation{request: request, times: 1}
+	p.expectations = append(p.expectations, e)
+
+	return e
+}
+
+// Open opens the port, matching the signature of serial.Open so it can be passed to
+// jumperless.NewJumperlessWithOpener. Errors passed to FailOpen are returned first.
+func (p *Port) Open(_ string, mode *serial.Mode) (serial.Port, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.opens++
+	if len(p.openErrs) > 0 {
+		err := p.openErrs[0]
+		p.openErrs = p.openErrs[1:]
+
+		return nil, err
+	}
+
+	p.open = true
+	p.mode = mode
+	p.pending = nil
+
+	return p, nil
+}
+
+// FailOpen fails the next call to Open with err, e.g. to test reconnecting to an unplugged device
+func (p *Port) FailOpen(err error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.openErrs = append(p.openErrs, err)
+}
+
+// Send schedules unsolicited output on the port, e.g. a message printed by the firmware, the delay after now
+func (p *Port) Send(delay time.Duration, data string) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.pending = append(p.pending, chunk{data: []byte(data), at: time.Now().Add(delay)})
+	slices.SortStableFunc(p.pending, func(a, b chunk) int { return a.at.Compare(b.at) })
+}
+
+// SetModemStatusBits sets the modem status bits reported by the port
+func (p *Port) SetModemStatusBits(bits serial.ModemStatusBits) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.modemStatus = bits
+}
+
+// SetMode implements serial.Port
+func (p *Port) SetMode(mode *serial.Mode) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if !p.open {
+		return ErrPortClosed
+	}
+
+	p.mode = mode
+
+	return nil
+}
+
+// Read implements serial.Port, returning the output that is due
+func (p *Port) Read(b []byte) (int, error) {
+	for {
+		p.mu.Lock()
+		if !p.open {
+			p.mu.Unlock()
+			return 0, ErrPortClosed
+		}
+
+		if len(p.pending) == 0 {
+			p.mu.Unlock()
+			return 0, nil
+		}
+
+		next := p.pending[0]
+		wait := time.Until(next.at)
+		if wait <= 0 {
+			if next.err != nil {
+				p.pending = p.pending[1:]
+				p.mu.Unlock()
+
+				return 0, next.err
+			}
+
+			n := copy(b, next.data)
+			if n < len(next.data) {
+				p.pending[0].data = next.data[n:]
+			} else {
+				p.pending = p.pending[1:]
+			}
+			p.mu.Unlock()
+
+			return n, nil
+		}
+
+		timeout := p.readTimeout
+		p.mu.Unlock()
+
+		if timeout != serial.NoTimeout && wait > timeout {
+			time.Sleep(timeout)
+			return 0, nil
+		}
+
+		time.Sleep(wait)
+	}
+}
+
+// Write implements serial.Port, scheduling the response of the expectation matching the command
+func (p *Port) Write(b []byte) (int, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if !p.open {
+		return 0, ErrPortClosed
+	}
+
+	request := string(b)
+	p.writes = append(p.writes, request)
+
+	if len(p.expectations) == 0 || p.expectations[0].request != request {
+		p.unexpected = append(p.unexpected, request)
+		return len(b), nil
+	}
+
+	e := p.expectations[0]
+	e.matched++
+	if e.matched >= e.times {
+		p.expectations = p.expectations[1:]
+	}
+
+	if e.writeErr != nil {
+		return 0, e.writeErr
+	}
+
+	at := time.Now()
+	for _, s := range e.steps {
+		at = at.Add(s.delay)
+		p.pending = append(p.pending, chunk{data: []byte(s.data), err: s.err, at: at})
+	}
+	slices.SortStableFunc(p.pending, func(a, b chunk) int { return a.at.Compare(b.at) })
+
+	return len(b), nil
+}
+
+// Drain implements serial.Port
+func (p *Port) Drain() error {
+	return p.checkOpen()
+}
+
+// ResetInputBuffer implements serial.Port, discarding the output that is already due. Output scheduled
+// later is kept, so late output of a previous command can be tested.
+func (p *Port) ResetInputBuffer() error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if !p.open {
+		return ErrPortClosed
+	}
+
+	now := time.Now()
+	p.pending = slices.DeleteFunc(p.pending, func(c chunk) bool { return !c.at.After(now) })
+
+	return nil
+}
+
+// ResetOutputBuffer implements serial.Port
+func (p *Port) ResetOutputBuffer() error {
+	return p.checkOpen()
+}
+
+// SetDTR implements serial.Port
+func (p *Port) SetDTR(dtr bool) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if !p.open {
+		return ErrPortClosed
+	}
+
+	p.dtr = dtr
+
+	return nil
+}
+
+// SetRTS implements serial.Port
+func (p *Port) SetRTS(rts bool) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if !p.open {
+		return ErrPortClosed
+	}
+
+	p.rts = rts
+
+	return nil
+}
+
+// GetModemStatusBits implements serial.Port
+func (p *Port) GetModemStatusBits() (*serial.ModemStatusBits, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if !p.open {
+		return nil, ErrPortClosed
+	}
+
+	bits := p.modemStatus
+
+	return &bits, nil
+}
+
+// SetReadTimeout implements serial.Port
+func (p *Port) SetReadTimeout(t time.Duration) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if !p.open {
+		return ErrPortClosed
+	}
+
+	p.readTimeout = t
+
+	return nil
+}
+
+// Close implements serial.Port, output that wasn't read yet is discarded
+func (p *Port) Close() error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if !p.open {
+		return ErrPortClosed
+	}
+
+	p.open = false
+	p.pending = nil
+
+	return nil
+}
+
+// Break implements serial.Port, recording the break without waiting for it
+func (p *Port) Break(duration time.Duration) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if !p.open {
+		return ErrPortClosed
+	}
+
+	p.breaks = append(p.breaks, duration)
+
+	return nil
+}
+
+func (p *Port) checkOpen() error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if !p.open {
+		return ErrPortClosed
+	}
+
+	return nil
+}
+
+// IsOpen reports whether the port is open
+func (p *Port) IsOpen() bool {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	return p.open
+}
+
+// Opens returns the number of calls to Open, including failed ones
+func (p *Port) Opens() int {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	return p.opens
+}
+
+// Mode returns the mode the port was last opened or set with
+func (p *Port) Mode() *serial.Mode {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	return p.mode
+}
+
+// Writes returns everything written to the port, one entry per write
+func (p *Port) Writes() []string {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	return slices.Clone(p.writes)
+}
+
+// DTR returns the state of the DTR line, raised unless dropped with SetDTR
+func (p *Port) DTR() bool {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	return p.dtr
+}
+
+// RTS returns the state of the RTS line, raised unless dropped with SetRTS
+func (p *Port) RTS() bool {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	return p.rts
+}
+
+// Breaks returns the durations of the breaks sent on the port
+func (p *Port) Breaks() []time.Duration {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	return slices.Clone(p.breaks)
+}
+
+// ExpectationsMet returns an error if commands were written that weren't expected, or if expected commands
+// weren't written
+func (p *Port) ExpectationsMet() error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	errs := []error{}
+	for _, request := range p.unexpected {
+		errs = append(errs, fmt.Errorf("%w: %q", ErrUnexpectedWrite, request))
+	}
+
+	for _, e := range p.expectations {
+		errs = append(errs, fmt.Errorf("%w: %q written %d of %d times", ErrUnmetExpectation,
+			e.request, e.matched, e.times))
+	}
+
+	return kerrors.NewAggregate(errs)
+}
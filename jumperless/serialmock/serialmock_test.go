@@ -0,0 +1,81 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package serialmock
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	. "github.com/onsi/gomega"
+	"go.bug.st/serial"
+)
+
+var errTest = errors.New("test error")
+
+func TestPort(t *testing.T) {
+	g := NewWithT(t)
+
+	p := New()
+	_, err := p.Write([]byte("?"))
+	g.Expect(err).To(MatchError(ErrPortClosed))
+
+	_, err = p.Open("/dev/ttyMock", &serial.Mode{BaudRate: 115200})
+	g.Expect(err).NotTo(HaveOccurred())
+
+	p.Expect("?").Respond("5.3").RespondAfter(50*time.Millisecond, ".1.0").Times(2)
+	p.Expect("x").FailWrite(errTest)
+
+	buff := make([]byte, 2)
+	_, err = p.Write([]byte("?"))
+	g.Expect(err).NotTo(HaveOccurred())
+
+	read := func(n int, err error) string {
+		g.Expect(err).NotTo(HaveOccurred())
+		return string(buff[:n])
+	}
+
+	// Chunks larger than the buffer are read in parts
+	g.Expect(read(p.Read(buff))).To(Equal("5."))
+	g.Expect(read(p.Read(buff))).To(Equal("3"))
+
+	// Reads time out before the next chunk is due
+	g.Expect(p.SetReadTimeout(10 * time.Millisecond)).To(Succeed())
+	g.Expect(read(p.Read(buff))).To(BeEmpty())
+
+	// Resetting the input buffer keeps output that isn't due yet
+	g.Expect(p.ResetInputBuffer()).To(Succeed())
+	g.Expect(p.SetReadTimeout(serial.NoTimeout)).To(Succeed())
+	g.Expect(read(p.Read(buff))).To(Equal(".1"))
+	g.Expect(read(p.Read(buff))).To(Equal(".0"))
+	g.Expect(read(p.Read(buff))).To(BeEmpty())
+
+	g.Expect(p.ExpectationsMet()).To(MatchError(ErrUnmetExpectation))
+
+	_, err = p.Write([]byte("?"))
+	g.Expect(err).NotTo(HaveOccurred())
+	_, err = p.Write([]byte("x"))
+	g.Expect(err).To(MatchError(errTest))
+	_, err = p.Write([]byte("y"))
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(p.ExpectationsMet()).To(MatchError(ErrUnexpectedWrite))
+
+	g.Expect(p.Break(time.Millisecond)).To(Succeed())
+	g.Expect(p.Breaks()).To(Equal([]time.Duration{time.Millisecond}))
+	g.Expect(p.Close()).To(Succeed())
+	g.Expect(p.Close()).To(MatchError(ErrPortClosed))
+}
@@ -0,0 +1,288 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package jumperless
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"path/filepath"
+	"slices"
+	"sync"
+	"time"
+
+	"go.bug.st/serial/enumerator"
+)
+
+var ErrSharedPortOpen = errors.New("unable to open shared serial port")
+var ErrHandleReleased = errors.New("shared port handle already released")
+
+// PortManager is the single owner of the serial ports shared between controller components, such as the
+// reconcile loop and device discovery. Each port is opened by the first handle acquired for it and closed
+// once the last handle is released, so components never open, probe or close a port another one is using.
+type PortManager struct {
+	lock  sync.Mutex
+	ports map[string]*sharedPort
+
+	// stateLock additionally guards changes to ports, so State does not wait for lock while ports are probed
+	stateLock sync.Mutex
+
+	// retryPolicy is set on the devices opened by the manager, unless nil
+	retryPolicy *RetryPolicy
+
+	// connectDTRPulse is set on the devices opened by the manager
+	connectDTRPulse time.Duration
+}
+
+// sharedPort is an open Jumperless device along with the number of handles referencing it
+type sharedPort struct {
+	jumperless *Jumperless
+	refs       int
+}
+
+// PortHandle is a reference to a Jumperless device shared through a PortManager.
+// It must be released once the caller is done with the device.
+type PortHandle struct {
+	manager *PortManager
+	key     string
+	device  *Jumperless
+	once    sync.Once
+}
+
+// NewPortManager returns a PortManager without any open ports.
+func NewPortManager() *PortManager {
+	return &PortManager{
+		ports: map[string]*sharedPort{},
+	}
+}
+
+// SetRetryPolicy sets the RetryPolicy of the devices opened afterwards.
+func (m *PortManager) SetRetryPolicy(policy RetryPolicy) {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	m.retryPolicy = &policy
+}
+
+// SetConnectDTRPulse sets the DTR pulse of the devices opened afterwards, see Jumperless.SetConnectDTRPulse.
+func (m *PortManager) SetConnectDTRPulse(duration time.Duration) {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	m.connectDTRPulse = duration
+}
+
+// Acquire returns a handle to the Jumperless device on the named port, probing and opening the port unless
+// another handle already references it. The baud rate of a port is the one it was first opened with.
+// Like NewJumperless, devices are detected on all ports if no port name is given.
+func (m *PortManager) Acquire(ctx context.Context, portName string, baudRate int) (*PortHandle, error) {
+	if portName == "" {
+		return m.AcquireFromSelector(ctx, PortSelector{}, baudRate)
+	}
+
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	if handle := m.reference(portName); handle != nil {
+		return handle, nil
+	}
+
+	j, err := NewJumperless(ctx, portName, baudRate)
+	if err != nil {
+		return nil, err
+	}
+
+	return m.open(j)
+}
+
+// AcquireFromSelector returns a handle to a Jumperless device on a serial port matching the selector.
+// Ports already referenced by other handles are reused without probing them again.
+func (m *PortManager) AcquireFromSelector(ctx context.Context, selector PortSelector,
+	baudRate int) (*PortHandle, error) {
+	ports, err := enumerateSerialPorts()
+	if err != nil {
+		return nil, fmt.Errorf("unable to enumerate serial ports: %w", err)
+	}
+
+	matching := slices.DeleteFunc(ports, func(details *enumerator.PortDetails) bool {
+		return !selector.Matches(details)
+	})
+
+	if len(matching) == 0 {
+		return nil, fmt.Errorf("no serial port matches selector %+v: %w", selector, ErrNoSerialPortFound)
+	}
+
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	for _, details := range matching {
+		if handle := m.reference(details.Name); handle != nil {
+			return handle, nil
+		}
+	}
+
+	detectedPort, err := findJumperlessPort(matching, baudRate)
+	if err != nil {
+		return nil, fmt.Errorf("unable to find Jumperless port: %w", err)
+	}
+
+	return m.open(&Jumperless{port: detectedPort})
+}
+
+// AcquireWithOpener returns a handle to the Jumperless device on a port opened with opener, such as a port
+// attached to another node, probing and opening the port unless another handle already references it. The
+// port is identified by key instead of its resolved name, which is only meaningful on the local node.
+func (m *PortManager) AcquireWithOpener(key string, device DeviceInfo, baudRate int,
+	opener Opener) (*PortHandle, error) {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	if handle := m.referenceKey(key); handle != nil {
+		return handle, nil
+	}
+
+	j, err := NewJumperlessWithOpener(device.Port, baudRate, opener)
+	if err != nil {
+		return nil, err
+	}
+
+	j.port.serialNumber = device.SerialNumber
+
+	return m.openKey(key, j)
+}
+
+// Discover probes the serial ports for Jumperless devices like DiscoverJumperlessDevices, except that
+// ports referenced by handles are reported using the details found when they were opened instead of
+// being probed while in use.
+func (m *PortManager) Discover(ctx context.Context, baudRate int) ([]DeviceInfo, error) {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	return discoverJumperlessDevices(ctx, baudRate, func(portName string) *JumperlessPort {
+		if shared, ok := m.ports[portKey(portName)]; ok {
+			return shared.jumperless.port
+		}
+
+		return nil
+	})
+}
+
+// reference returns a new handle to the named port if it is already open, m.lock must be held.
+func (m *PortManager) reference(portName string) *PortHandle {
+	return m.referenceKey(portKey(portName))
+}
+
+// referenceKey returns a new handle to the port identified by key if it is already open, m.lock must be held.
+func (m *PortManager) referenceKey(key string) *PortHandle {
+	shared, ok := m.ports[key]
+	if !ok {
+		return nil
+	}
+
+	m.stateLock.Lock()
+	shared.refs++
+	m.stateLock.Unlock()
+
+	return &PortHandle{manager: m, key: key, device: shared.jumperless}
+}
+
+// open opens the port of a probed device and returns the first handle to it, m.lock must be held.
+func (m *PortManager) open(j *Jumperless) (*PortHandle, error) {
+	return m.openKey("", j)
+}
+
+// openKey opens the port of a probed device and returns the first handle to it, identifying the port by key
+// or by its resolved name if key is empty. m.lock must be held.
+func (m *PortManager) openKey(key string, j *Jumperless) (*PortHandle, error) {
+	if m.retryPolicy != nil {
+		j.SetRetryPolicy(*m.retryPolicy)
+	}
+	j.SetConnectDTRPulse(m.connectDTRPulse)
+
+	if err := j.OpenPort(); err != nil {
+		return nil, fmt.Errorf("%w %s: %w", ErrSharedPortOpen, j.GetPort(), err)
+	}
+
+	if key == "" {
+		key = portKey(j.GetPort())
+	}
+
+	m.stateLock.Lock()
+	m.ports[key] = &sharedPort{jumperless: j, refs: 1}
+	m.stateLock.Unlock()
+
+	return &PortHandle{manager: m, key: key, device: j}, nil
+}
+
+// Jumperless returns the shared device, it must not be opened or closed by the caller.
+func (h *PortHandle) Jumperless() *Jumperless {
+	if h == nil {
+		return nil
+	}
+
+	return h.device
+}
+
+// Release drops the reference to the shared device, closing its port if this was the last handle
+// referencing it. Releasing a handle more than once returns ErrHandleReleased.
+func (h *PortHandle) Release() error {
+	if h == nil {
+		return ErrNilJumperlessPort
+	}
+
+	err := ErrHandleReleased
+	h.once.Do(func() {
+		err = h.manager.release(h.key)
+	})
+
+	return err
+}
+
+func (m *PortManager) release(key string) error {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	shared, ok := m.ports[key]
+	if !ok {
+		return ErrHandleReleased
+	}
+
+	m.stateLock.Lock()
+	shared.refs--
+	closing := shared.refs == 0
+	if closing {
+		delete(m.ports, key)
+	}
+	m.stateLock.Unlock()
+
+	if !closing {
+		return nil
+	}
+
+	return shared.jumperless.ClosePort()
+}
+
+// portKey identifies a port by its resolved name, so that symlinks such as /dev/serial/by-id paths
+// share the handles of the device they point to.
+func portKey(portName string) string {
+	resolved, err := filepath.EvalSymlinks(portName)
+	if err != nil {
+		return portName
+	}
+
+	return resolved
+}
@@ -0,0 +1,54 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package jumperless
+
+import (
+	"testing"
+
+	. "github.com/onsi/gomega"
+
+	"github.com/detiber/k8s-jumperless/jumperless/serialmock"
+)
+
+func TestPortManagerAcquireWithOpener(t *testing.T) {
+	g := NewWithT(t)
+
+	port := serialmock.New()
+	port.Expect("?").Respond("Jumperless firmware version: 5.3.1.0\r\n")
+
+	m := NewPortManager()
+	device := DeviceInfo{Port: "/dev/ttyACM0", SerialNumber: "E6614103E7"}
+
+	handle, err := m.AcquireWithOpener("node-a/dev/ttyACM0", device, 0, port.Open)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(handle.Jumperless().GetPort()).To(Equal("/dev/ttyACM0"))
+	g.Expect(handle.Jumperless().GetSerialNumber()).To(Equal("E6614103E7"))
+	g.Expect(handle.Jumperless().GetVersion()).To(Equal("5.3.1.0"))
+	g.Expect(port.IsOpen()).To(BeTrue())
+
+	// The open port is shared by its key without probing it again
+	other, err := m.AcquireWithOpener("node-a/dev/ttyACM0", device, 0, port.Open)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(other.Jumperless()).To(BeIdenticalTo(handle.Jumperless()))
+	g.Expect(port.Opens()).To(Equal(2))
+
+	g.Expect(handle.Release()).To(Succeed())
+	g.Expect(port.IsOpen()).To(BeTrue())
+	g.Expect(other.Release()).To(Succeed())
+	g.Expect(port.IsOpen()).To(BeFalse())
+	g.Expect(port.ExpectationsMet()).To(Succeed())
+}
@@ -0,0 +1,198 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package jumperless
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/charmbracelet/x/ansi"
+)
+
+var ErrEmptySnippet = errors.New("python snippet is empty")
+
+const (
+	// MicroPython paste mode control characters, paste mode compiles the pasted lines as a single block
+	// instead of auto-indenting each line like the interactive REPL
+	pasteModeEnter  = "\x05" // Ctrl-E
+	pasteModeFinish = "\x04" // Ctrl-D
+
+	// pasteModeMarker prefixes the lines echoed by the REPL in paste mode
+	pasteModeMarker = "==="
+)
+
+// NormalizePythonSnippet prepares a multi-line MicroPython snippet for sending to the device: line endings are
+// converted to newlines, the indentation common to all lines is removed and leading and trailing blank lines
+// are dropped, so snippets can be indented to match the surrounding code of the caller.
+func NormalizePythonSnippet(snippet string) (string, error) {
+	snippet = strings.ReplaceAll(snippet, "\r\n", "\n")
+	snippet = strings.ReplaceAll(snippet, "\r", "\n")
+
+	lines := strings.Split(snippet, "\n")
+	for i, line := range lines {
+		lines[i] = strings.TrimRightFunc(line, unicode.IsSpace)
+	}
+
+	// Drop leading and trailing blank lines
+	for len(lines) > 0 && lines[0] == "" {
+		lines = lines[1:]
+	}
+	for len(lines) > 0 && lines[len(lines)-1] == "" {
+		lines = lines[:len(lines)-1]
+	}
+
+	if len(lines) == 0 {
+		return "", ErrEmptySnippet
+	}
+
+	// Remove the indentation common to all non-blank lines, blank lines don't affect the indentation
+	indent := ""
+	for i, line := range slices.DeleteFunc(slices.Clone(lines), func(l string) bool { return l == "" }) {
+		current := line[:len(line)-len(strings.TrimLeft(line, " \t"))]
+		if i == 0 {
+			indent = current
+			continue
+		}
+
+		for !strings.HasPrefix(current, indent) {
+			indent = indent[:len(indent)-1]
+		}
+	}
+
+	for i, line := range lines {
+		lines[i] = strings.TrimPrefix(line, indent)
+	}
+
+	return strings.Join(lines, "\n"), nil
+}
+
+// FormatPythonSnippet returns a single line Python command running the multi-line snippet using exec, the
+// snippet is normalized and escaped so it can be sent like any other single line command.
+func FormatPythonSnippet(snippet string) (string, error) {
+	normalized, err := NormalizePythonSnippet(snippet)
+	if err != nil {
+		return "", err
+	}
+
+	return "exec(" + pythonStringLiteral(normalized) + ")", nil
+}
+
+// pythonStringLiteral quotes s as a single quoted Python string literal, escaping line breaks and other
+// control characters so the literal fits on a single line
+func pythonStringLiteral(s string) string {
+	var b strings.Builder
+
+	b.WriteByte('\'')
+	for _, r := range s {
+		switch {
+		case r == '\\' || r == '\'':
+			b.WriteByte('\\')
+			b.WriteRune(r)
+		case r == '\n':
+			b.WriteString(`\n`)
+		case r == '\t':
+			b.WriteString(`\t`)
+		case r < ' ' || r == unicode.MaxASCII:
+			fmt.Fprintf(&b, `\x%02x`, r)
+		default:
+			b.WriteRune(r)
+		}
+	}
+	b.WriteByte('\'')
+
+	return b.String()
+}
+
+// ExecPythonSnippet executes a multi-line MicroPython snippet as a single command using exec, returning its
+// complete output with the indentation of each line preserved. Unlike ExecPythonCommand, a snippet that
+// prints nothing returns an empty output.
+func (j *Jumperless) ExecPythonSnippet(snippet string, waitForRead time.Duration) (string, error) {
+	command, err := FormatPythonSnippet(snippet)
+	if err != nil {
+		return "", err
+	}
+
+	result, err := j.ExecRawCommand(">"+command, waitForRead)
+	if err != nil {
+		return "", fmt.Errorf("failed to execute snippet: %w", err)
+	}
+
+	return snippetOutput(snippet, result, nil)
+}
+
+// ExecPythonPaste executes a multi-line MicroPython snippet using the paste mode of the REPL, for firmware
+// whose REPL supports it. The device must already be running its REPL. Output is read until the REPL prints
+// its prompt again, or ErrCommandIncomplete is returned once ctx is done.
+func (j *Jumperless) ExecPythonPaste(ctx context.Context, snippet string) (string, error) {
+	normalized, err := NormalizePythonSnippet(snippet)
+	if err != nil {
+		return "", err
+	}
+
+	// The REPL reads lines ending with a carriage return, like typed ones
+	command := pasteModeEnter + strings.ReplaceAll(normalized, "\n", "\r") + "\r" + pasteModeFinish
+
+	// The prompt is only expected once the pasted block was compiled and run, the paste mode banner and
+	// echoed lines come first
+	var output bytes.Buffer
+	if err := j.ExecLongCommand(ctx, command, func(chunk []byte) { output.Write(chunk) },
+		"\r\n"+pythonPrompt); err != nil {
+		return "", fmt.Errorf("failed to execute snippet: %w", err)
+	}
+
+	return snippetOutput(snippet, output.String(), func(line string) bool {
+		return strings.HasPrefix(line, pasteModeMarker) || strings.HasPrefix(line, "paste mode;")
+	})
+}
+
+// snippetOutput returns the output of a snippet without prompts and the lines matched by skip, returning
+// ErrPythonException if the snippet raised an exception
+func snippetOutput(snippet, result string, skip func(string) bool) (string, error) {
+	lines := []string{}
+	for line := range strings.SplitSeq(ansi.Strip(result), "\n") {
+		line = strings.TrimRightFunc(line, unicode.IsSpace)
+
+		trimmed := strings.TrimSpace(line)
+		if strings.HasPrefix(trimmed, pythonPrompt) || skip != nil && skip(trimmed) {
+			continue
+		}
+
+		lines = append(lines, line)
+	}
+
+	// Drop leading and trailing blank lines
+	for len(lines) > 0 && strings.TrimSpace(lines[0]) == "" {
+		lines = lines[1:]
+	}
+	for len(lines) > 0 && strings.TrimSpace(lines[len(lines)-1]) == "" {
+		lines = lines[:len(lines)-1]
+	}
+
+	// The last line of a traceback is the exception, e.g. "NameError: name 'x' isn't defined"
+	if slices.ContainsFunc(lines, func(l string) bool { return strings.TrimSpace(l) == pythonTraceback }) {
+		firstLine, _, _ := strings.Cut(strings.TrimSpace(snippet), "\n")
+		return "", fmt.Errorf("%w: %s: %s", ErrPythonException, firstLine, strings.TrimSpace(lines[len(lines)-1]))
+	}
+
+	return strings.Join(lines, "\n"), nil
+}
@@ -0,0 +1,102 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package jumperless_test
+
+import (
+	"errors"
+	"testing"
+
+	. "github.com/onsi/gomega"
+
+	"github.com/detiber/k8s-jumperless/jumperless"
+)
+
+func TestNormalizePythonSnippet(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		want  string
+		err   error
+	}{
+		{name: "single line", input: "print(1)", want: "print(1)"},
+		{
+			name:  "common indentation",
+			input: "\n\t\tfor i in range(4):\n\t\t    print(dac_get(i))\n\n",
+			want:  "for i in range(4):\n    print(dac_get(i))",
+		},
+		{
+			name:  "carriage returns",
+			input: "if True:\r\n  x = 1\r  print(x)\r\n",
+			want:  "if True:\n  x = 1\n  print(x)",
+		},
+		{
+			name:  "blank lines inside",
+			input: "  def f():\n      return 1\n\n  print(f())  ",
+			want:  "def f():\n    return 1\n\nprint(f())",
+		},
+		{name: "empty", input: " \n\t\n", err: jumperless.ErrEmptySnippet},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			got, err := jumperless.NormalizePythonSnippet(tt.input)
+			if tt.err != nil {
+				g.Expect(errors.Is(err, tt.err)).To(BeTrue())
+				return
+			}
+
+			g.Expect(err).NotTo(HaveOccurred())
+			g.Expect(got).To(Equal(tt.want))
+		})
+	}
+}
+
+func TestFormatPythonSnippet(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		want  string
+	}{
+		{
+			name:  "multi-line",
+			input: "for i in range(4):\n    print(dac_get(i))",
+			want:  `exec('for i in range(4):\n    print(dac_get(i))')`,
+		},
+		{
+			name:  "quotes and backslashes",
+			input: `print('a\b', "c")`,
+			want:  `exec('print(\'a\\b\', "c")')`,
+		},
+		{
+			name:  "control characters",
+			input: "print('\x1b[0m\tx')",
+			want:  `exec('print(\'\x1b[0m\tx\')')`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			got, err := jumperless.FormatPythonSnippet(tt.input)
+			g.Expect(err).NotTo(HaveOccurred())
+			g.Expect(got).To(Equal(tt.want))
+		})
+	}
+}
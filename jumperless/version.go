@@ -0,0 +1,81 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package jumperless
+
+import (
+	"cmp"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+var ErrInvalidFirmwareVersion = errors.New("invalid firmware version")
+
+// FirmwareVersion is the version of the firmware of a Jumperless, e.g. 5.2.2.0 for "5.2.2.0"
+type FirmwareVersion []int
+
+// ParseFirmwareVersion parses a dotted firmware version as reported by the device, e.g. "5.2.2.0". A leading "v"
+// and a suffix after a "-" or "+", e.g. "5.3.0.0-beta", are ignored.
+func ParseFirmwareVersion(s string) (FirmwareVersion, error) {
+	trimmed := strings.TrimPrefix(strings.TrimSpace(s), "v")
+	if i := strings.IndexAny(trimmed, "-+ "); i >= 0 {
+		trimmed = trimmed[:i]
+	}
+
+	version := FirmwareVersion{}
+	for part := range strings.SplitSeq(trimmed, ".") {
+		n, err := strconv.Atoi(part)
+		if err != nil || n < 0 {
+			return nil, fmt.Errorf("%w: %q", ErrInvalidFirmwareVersion, s)
+		}
+
+		version = append(version, n)
+	}
+
+	return version, nil
+}
+
+// Compare returns -1, 0 or +1 depending on whether v is older, the same as or newer than other. Missing
+// components count as zero, so 5.2 is the same as 5.2.0.0.
+func (v FirmwareVersion) Compare(other FirmwareVersion) int {
+	for i := range max(len(v), len(other)) {
+		if c := cmp.Compare(v.component(i), other.component(i)); c != 0 {
+			return c
+		}
+	}
+
+	return 0
+}
+
+func (v FirmwareVersion) component(i int) int {
+	if i < len(v) {
+		return v[i]
+	}
+
+	return 0
+}
+
+// String returns the dotted version, e.g. "5.2.2.0"
+func (v FirmwareVersion) String() string {
+	parts := make([]string, 0, len(v))
+	for _, n := range v {
+		parts = append(parts, strconv.Itoa(n))
+	}
+
+	return strings.Join(parts, ".")
+}
@@ -0,0 +1,65 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package jumperless
+
+import (
+	"errors"
+	"testing"
+
+	. "github.com/onsi/gomega"
+)
+
+func TestParseFirmwareVersion(t *testing.T) {
+	tests := []struct {
+		name    string
+		version string
+		want    FirmwareVersion
+		err     error
+	}{
+		{name: "four components", version: "5.2.2.0", want: FirmwareVersion{5, 2, 2, 0}},
+		{name: "two components", version: "5.3", want: FirmwareVersion{5, 3}},
+		{name: "leading v and whitespace", version: " v5.3.1.0\r\n", want: FirmwareVersion{5, 3, 1, 0}},
+		{name: "pre-release suffix", version: "5.3.0.0-beta", want: FirmwareVersion{5, 3, 0, 0}},
+		{name: "empty", version: "", err: ErrInvalidFirmwareVersion},
+		{name: "not a number", version: "5.x.1", err: ErrInvalidFirmwareVersion},
+		{name: "negative component", version: "5.-1", err: ErrInvalidFirmwareVersion},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			version, err := ParseFirmwareVersion(tt.version)
+			if tt.err != nil {
+				g.Expect(errors.Is(err, tt.err)).To(BeTrue())
+				return
+			}
+
+			g.Expect(err).NotTo(HaveOccurred())
+			g.Expect(version).To(Equal(tt.want))
+		})
+	}
+}
+
+func TestFirmwareVersionCompare(t *testing.T) {
+	g := NewWithT(t)
+
+	g.Expect(FirmwareVersion{5, 2, 2, 0}.Compare(FirmwareVersion{5, 3})).To(Equal(-1))
+	g.Expect(FirmwareVersion{5, 10}.Compare(FirmwareVersion{5, 9, 9, 9})).To(Equal(1))
+	g.Expect(FirmwareVersion{5, 2}.Compare(FirmwareVersion{5, 2, 0, 0})).To(Equal(0))
+	g.Expect(FirmwareVersion{5, 2, 2, 0}.String()).To(Equal("5.2.2.0"))
+}
@@ -0,0 +1,129 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package voltage parses and formats the voltage strings used by Jumperless devices and the Jumperless API.
+package voltage
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+)
+
+var ErrInvalidVoltage = errors.New("invalid voltage")
+var ErrOutOfRange = errors.New("voltage out of range")
+
+const (
+	// Unit is the unit suffix used when formatting voltages.
+	Unit = "V"
+
+	// Min is the lowest voltage a Jumperless DAC channel can output.
+	Min = -8.0
+	// Max is the highest voltage a Jumperless DAC channel can output.
+	Max = 8.0
+
+	// Decimals is the number of decimal places voltages are formatted with, matching the device output.
+	Decimals = 2
+
+	// Resolution is the precision voltages are rounded to.
+	Resolution = 1.0 / steps
+
+	// steps is the number of Resolution steps per volt.
+	steps = 100
+)
+
+// Parse parses a voltage string such as "3.3V", "-0.50 V", "330mV" or "3.3" and returns the value in volts.
+// Whitespace around the value and between the value and the unit is ignored, a missing unit is treated as volts.
+func Parse(s string) (float64, error) {
+	value := strings.TrimSpace(s)
+	scale := 1.0
+
+	switch {
+	case strings.HasSuffix(value, "mV"):
+		value = strings.TrimSuffix(value, "mV")
+		scale = 0.001
+	case strings.HasSuffix(value, Unit):
+		value = strings.TrimSuffix(value, Unit)
+	}
+
+	v, err := strconv.ParseFloat(strings.TrimSpace(value), 64)
+	if err != nil || math.IsNaN(v) || math.IsInf(v, 0) {
+		return 0, fmt.Errorf("%w: %q", ErrInvalidVoltage, s)
+	}
+
+	return v * scale, nil
+}
+
+// ParseInRange parses a voltage string like Parse and verifies it is within the range of a DAC channel.
+func ParseInRange(s string) (float64, error) {
+	v, err := Parse(s)
+	if err != nil {
+		return 0, err
+	}
+
+	if Round(v) < Min || Round(v) > Max {
+		return 0, fmt.Errorf("%w: %q is not between %s and %s", ErrOutOfRange, s, Format(Min), Format(Max))
+	}
+
+	return v, nil
+}
+
+// Round rounds a voltage to the nearest Resolution, rounding half away from zero.
+func Round(v float64) float64 {
+	// Dividing by the number of steps yields the closest float to the decimal value, unlike multiplying by Resolution
+	rounded := math.Round(v*steps) / steps
+
+	// Avoid reporting "-0V"
+	if rounded == 0 {
+		return 0
+	}
+
+	return rounded
+}
+
+// FormatValue formats a voltage rounded to Resolution without a unit, always using Decimals decimal places
+// so the same voltage is formatted identically regardless of how it was parsed, e.g. 3.30 for "3.3V" and
+// 0.00 for "0 V".
+func FormatValue(v float64) string {
+	return strconv.FormatFloat(Round(v), 'f', Decimals, 64)
+}
+
+// Format formats a voltage like FormatValue with the unit suffix, e.g. "3.30V", "-0.50V" or "0.00V".
+func Format(v float64) string {
+	return FormatValue(v) + Unit
+}
+
+// Normalize parses a voltage string and formats it consistently.
+func Normalize(s string) (string, error) {
+	v, err := Parse(s)
+	if err != nil {
+		return "", err
+	}
+
+	return Format(v), nil
+}
+
+// Millivolts returns a voltage rounded to Resolution in millivolts, e.g. 3300 for 3.3V.
+func Millivolts(v float64) int32 {
+	return int32(math.Round(Round(v) * 1000))
+}
+
+// Equal reports whether two voltages are equal once rounded to Resolution.
+func Equal(a, b float64) bool {
+	return Round(a) == Round(b)
+}
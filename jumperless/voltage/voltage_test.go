@@ -0,0 +1,95 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package voltage_test
+
+import (
+	"errors"
+	"testing"
+
+	. "github.com/onsi/gomega"
+
+	"github.com/detiber/k8s-jumperless/jumperless/voltage"
+)
+
+func TestNormalize(t *testing.T) {
+	tests := []struct {
+		input string
+		want  string
+		err   error
+	}{
+		{input: "3.3V", want: "3.30V"},
+		{input: "3.30V", want: "3.30V"},
+		{input: "-0.50 V", want: "-0.50V"},
+		{input: "330mV", want: "0.33V"},
+		{input: " 3.33 ", want: "3.33V"},
+		{input: "0 V", want: "0.00V"},
+		{input: "0.00 V", want: "0.00V"},
+		{input: "-0.001V", want: "0.00V"},
+		{input: "1.006V", want: "1.01V"},
+		{input: "8V", want: "8.00V"},
+		{input: "abc", err: voltage.ErrInvalidVoltage},
+		{input: "V", err: voltage.ErrInvalidVoltage},
+		{input: "NaNV", err: voltage.ErrInvalidVoltage},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			g := NewWithT(t)
+
+			got, err := voltage.Normalize(tt.input)
+			if tt.err != nil {
+				g.Expect(errors.Is(err, tt.err)).To(BeTrue())
+				return
+			}
+
+			g.Expect(err).NotTo(HaveOccurred())
+			g.Expect(got).To(Equal(tt.want))
+		})
+	}
+}
+
+func TestMillivolts(t *testing.T) {
+	g := NewWithT(t)
+
+	tests := []struct {
+		input float64
+		want  int32
+	}{
+		{input: 3.3, want: 3300},
+		{input: -1.25, want: -1250},
+		{input: 1.006, want: 1010},
+		{input: -0.001, want: 0},
+		{input: 8, want: 8000},
+	}
+
+	for _, tt := range tests {
+		g.Expect(voltage.Millivolts(tt.input)).To(Equal(tt.want), "input %v", tt.input)
+	}
+}
+
+func TestParseInRange(t *testing.T) {
+	g := NewWithT(t)
+
+	_, err := voltage.ParseInRange("8.001V")
+	g.Expect(err).NotTo(HaveOccurred())
+
+	_, err = voltage.ParseInRange("-8.01V")
+	g.Expect(errors.Is(err, voltage.ErrOutOfRange)).To(BeTrue())
+
+	_, err = voltage.ParseInRange("9000mV")
+	g.Expect(errors.Is(err, voltage.ErrOutOfRange)).To(BeTrue())
+}
@@ -0,0 +1,159 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package capture
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+
+	"github.com/detiber/k8s-jumperless/utils/internal/capture"
+	emulatorConfig "github.com/detiber/k8s-jumperless/utils/internal/emulator/config"
+	"github.com/detiber/k8s-jumperless/utils/internal/logging"
+)
+
+var ErrUnsupportedConversion = errors.New("unsupported conversion (convert between .pcap and .yaml, .yml or .json)")
+
+func NewCaptureCommand(parentLogger *slog.Logger) *cobra.Command {
+	logger := logging.Subsystem(parentLogger, "capture")
+
+	cmd := &cobra.Command{
+		Use:   "capture",
+		Short: "Work with raw captures",
+		Long:  `Raw captures store serial traffic losslessly as pcap files, as written by proxy --capture`,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			return cmd.Help()
+		},
+	}
+
+	cmd.AddCommand(&cobra.Command{
+		Use:   "convert <input> <output>",
+		Short: "Convert between raw captures and emulator configs",
+		Long: `Converts a raw capture (.pcap) to emulator mappings (.yaml, .yml or .json), or emulator mappings
+to a raw capture, based on the file extensions`,
+		Args: cobra.ExactArgs(2), //nolint:mnd
+		RunE: func(_ *cobra.Command, args []string) error {
+			return convert(logger, args[0], args[1])
+		},
+	})
+
+	return cmd
+}
+
+func isCapture(path string) bool {
+	return strings.EqualFold(filepath.Ext(path), ".pcap")
+}
+
+func isConfig(path string) bool {
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".yaml", ".yml", ".json":
+		return true
+	default:
+		return false
+	}
+}
+
+func convert(logger *slog.Logger, input, output string) error {
+	switch {
+	case isCapture(input) && isConfig(output):
+		return captureToConfig(logger, input, output)
+	case isConfig(input) && isCapture(output):
+		return configToCapture(logger, input, output)
+	default:
+		return fmt.Errorf("%w: %s to %s", ErrUnsupportedConversion, input, output)
+	}
+}
+
+func captureToConfig(logger *slog.Logger, input, output string) error {
+	file, err := os.Open(input)
+	if err != nil {
+		return fmt.Errorf("failed to open capture: %w", err)
+	}
+	defer file.Close() //nolint:errcheck
+
+	reader, err := capture.NewReader(file)
+	if err != nil {
+		return fmt.Errorf("failed to read capture %s: %w", input, err)
+	}
+
+	frames, err := reader.ReadAll()
+	if err != nil {
+		return fmt.Errorf("failed to read capture %s: %w", input, err)
+	}
+
+	mappings := capture.ToMappings(frames)
+
+	v := viper.New()
+	v.Set("emulator.mappings", mappings)
+	if err := v.WriteConfigAs(output); err != nil {
+		return fmt.Errorf("failed to write config: %w", err)
+	}
+
+	logger.Info("Converted frames to request/response mappings",
+		"frames", len(frames), "mappings", len(mappings), "output", output)
+
+	return nil
+}
+
+func configToCapture(logger *slog.Logger, input, output string) error {
+	v := viper.New()
+	v.SetConfigFile(input)
+	if err := v.ReadInConfig(); err != nil {
+		return fmt.Errorf("failed to read config %s: %w", input, err)
+	}
+
+	mappings := emulatorConfig.NewFromViper(v).Mappings
+
+	frames, err := capture.FromMappings(mappings, time.Now())
+	if err != nil {
+		return fmt.Errorf("failed to convert config %s: %w", input, err)
+	}
+
+	file, err := os.Create(output)
+	if err != nil {
+		return fmt.Errorf("failed to create capture: %w", err)
+	}
+
+	writer, err := capture.NewWriter(file)
+	if err != nil {
+		_ = file.Close()
+		return fmt.Errorf("failed to write capture %s: %w", output, err)
+	}
+
+	for _, frame := range frames {
+		if err := writer.WriteFrame(frame); err != nil {
+			_ = file.Close()
+			return fmt.Errorf("failed to write capture %s: %w", output, err)
+		}
+	}
+
+	if err := file.Close(); err != nil {
+		return fmt.Errorf("failed to close capture: %w", err)
+	}
+
+	logger.Info("Converted request/response mappings to frames",
+		"mappings", len(mappings), "frames", len(frames), "output", output)
+
+	return nil
+}
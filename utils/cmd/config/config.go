@@ -0,0 +1,163 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package config
+
+import (
+	"fmt"
+	"log/slog"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+
+	"github.com/detiber/k8s-jumperless/utils/internal/logging"
+	"github.com/detiber/k8s-jumperless/utils/internal/migrate"
+	"github.com/detiber/k8s-jumperless/utils/internal/optimize"
+	"github.com/detiber/k8s-jumperless/utils/internal/proxy"
+)
+
+const (
+	flagIn  = "in"
+	flagOut = "out"
+)
+
+func NewConfigCommand(parentLogger *slog.Logger) *cobra.Command {
+	logger := logging.Subsystem(parentLogger, "config")
+
+	cmd := &cobra.Command{
+		Use:   "config",
+		Short: "Work with config files",
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			return cmd.Help()
+		},
+	}
+
+	migrateCmd := &cobra.Command{
+		Use:   "migrate",
+		Short: "Migrate a config file to the current schema",
+		Long: `Migrates a config file written for an older schema version to the current one, e.g. moving the
+legacy nested proxy.serial settings to the flat proxy settings. Renamed, dropped and unknown keys are reported`,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			in, err := cmd.Flags().GetString(flagIn)
+			if err != nil {
+				return fmt.Errorf("failed to get in flag: %w", err)
+			}
+
+			out, err := cmd.Flags().GetString(flagOut)
+			if err != nil {
+				return fmt.Errorf("failed to get out flag: %w", err)
+			}
+
+			return migrateConfig(logger, in, out)
+		},
+	}
+
+	migrateCmd.Flags().String(flagIn, "", "config file to migrate")
+	migrateCmd.Flags().String(flagOut, "", "file to write the migrated config to, may be the same as --in")
+	_ = migrateCmd.MarkFlagRequired(flagIn)
+	_ = migrateCmd.MarkFlagRequired(flagOut)
+
+	optimizeCmd := &cobra.Command{
+		Use:   "optimize",
+		Short: "Merge the mappings of a recording into parameterized regex mappings",
+		Long: `Optimizes the mappings recorded by the proxy. Mappings of the same request are merged and their
+duplicate responses dropped, and requests that only differ in their numeric arguments, e.g. ">dac_get(0)" and
+">dac_get(1)", are replaced by a regex mapping with a response template. The recorded requests are answered
+like before, review the result before extending the patterns to other arguments`,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			in, err := cmd.Flags().GetString(flagIn)
+			if err != nil {
+				return fmt.Errorf("failed to get in flag: %w", err)
+			}
+
+			out, err := cmd.Flags().GetString(flagOut)
+			if err != nil {
+				return fmt.Errorf("failed to get out flag: %w", err)
+			}
+
+			return optimizeConfig(logger, in, out)
+		},
+	}
+
+	optimizeCmd.Flags().String(flagIn, "", "recording or config file to optimize")
+	optimizeCmd.Flags().String(flagOut, "", "file to write the optimized config to, may be the same as --in")
+	_ = optimizeCmd.MarkFlagRequired(flagIn)
+	_ = optimizeCmd.MarkFlagRequired(flagOut)
+
+	cmd.AddCommand(migrateCmd, optimizeCmd)
+
+	return cmd
+}
+
+func migrateConfig(logger *slog.Logger, in, out string) error {
+	v := viper.New()
+	v.SetConfigFile(in)
+	if err := v.ReadInConfig(); err != nil {
+		return fmt.Errorf("failed to read config %s: %w", in, err)
+	}
+
+	migrated, report, err := migrate.Migrate(v)
+	if err != nil {
+		return fmt.Errorf("failed to migrate config %s: %w", in, err)
+	}
+
+	if err := migrated.WriteConfigAs(out); err != nil {
+		return fmt.Errorf("failed to write config: %w", err)
+	}
+
+	for _, rename := range report.Renamed {
+		logger.Info("Renamed config key", "from", rename.From, "to", rename.To)
+	}
+	for _, key := range report.Dropped {
+		logger.Warn("Dropped config key", "key", key)
+	}
+	for _, key := range report.Unknown {
+		logger.Warn("Kept unknown config key", "key", key)
+	}
+
+	logger.Info("Migrated config", "fromVersion", report.FromVersion, "toVersion", report.ToVersion,
+		"renamed", len(report.Renamed), "dropped", len(report.Dropped), "unknown", len(report.Unknown), "output", out)
+
+	return nil
+}
+
+func optimizeConfig(logger *slog.Logger, in, out string) error {
+	v := viper.New()
+	if err := proxy.ReadRecording(v, in); err != nil {
+		return fmt.Errorf("failed to read config %s: %w", in, err)
+	}
+
+	rec, err := proxy.RecordingFromViper(v)
+	if err != nil {
+		return fmt.Errorf("failed to read config %s: %w", in, err)
+	}
+
+	mappings, report := optimize.Mappings(rec.Emulator.Mappings)
+	v.Set(proxy.RecordingKeyMappings, mappings)
+
+	if err := v.WriteConfigAs(out); err != nil {
+		return fmt.Errorf("failed to write config: %w", err)
+	}
+
+	for _, parameterized := range report.Parameterized {
+		logger.Info("Parameterized mappings", "request", parameterized.Request, "replaces", parameterized.Replaces)
+	}
+
+	logger.Info("Optimized config", "mappings", report.Mappings, "optimized", report.Optimized,
+		"duplicateResponses", report.DuplicateResponses, "parameterized", len(report.Parameterized), "output", out)
+
+	return nil
+}
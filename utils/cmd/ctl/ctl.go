@@ -0,0 +1,152 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ctl
+
+import (
+	"fmt"
+	"log/slog"
+	"os"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+
+	"github.com/detiber/k8s-jumperless/utils/internal/ctl"
+	"github.com/detiber/k8s-jumperless/utils/internal/ctl/config"
+	"github.com/detiber/k8s-jumperless/utils/internal/logging"
+	logconfig "github.com/detiber/k8s-jumperless/utils/internal/logging/config"
+)
+
+func NewCtlCommand(v *viper.Viper, verboseKey string) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "ctl",
+		Short: "Manage Jumperless resources through the Kubernetes API",
+		Long: `Changes Jumperless resources through the Kubernetes API like kubectl, and streams their status conditions
+until the controller has applied the change to the device`,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			return cmd.Help()
+		},
+	}
+
+	// newCtl creates the client for a subcommand, logs are only written to stderr when verbose so the output
+	// can be used in scripts
+	newCtl := func(cmd *cobra.Command) (*ctl.Ctl, error) {
+		// The arguments are valid, failures from here on are not usage errors
+		cmd.SilenceUsage = true
+
+		logger := slog.New(slog.DiscardHandler)
+		if v.GetBool(verboseKey) {
+			var err error
+			if logger, err = logging.New(os.Stderr, logconfig.NewFromViper(v)); err != nil {
+				return nil, fmt.Errorf("failed to configure logging: %w", err)
+			}
+		}
+
+		return ctl.New(config.NewFromViper(v), cmd.OutOrStdout(), logging.Subsystem(logger, "ctl")) //nolint:wrapcheck
+	}
+
+	// Command-line flags
+	cmd.PersistentFlags().String(config.FlagKubeconfig, "",
+		"kubeconfig file to use (if not specified, the KUBECONFIG environment variable or ~/.kube/config is used)")
+	_ = v.BindPFlag(config.ViperKubeconfig, cmd.PersistentFlags().Lookup(config.FlagKubeconfig))
+
+	cmd.PersistentFlags().String(config.FlagContext, "", "kubeconfig context to use")
+	_ = v.BindPFlag(config.ViperContext, cmd.PersistentFlags().Lookup(config.FlagContext))
+
+	cmd.PersistentFlags().StringP(config.FlagNamespace, "n", "",
+		"namespace of the Jumperless resource (if not specified, the namespace of the context is used)")
+	_ = v.BindPFlag(config.ViperNamespace, cmd.PersistentFlags().Lookup(config.FlagNamespace))
+
+	cmd.PersistentFlags().Bool(config.FlagWait, config.DefaultWait,
+		"stream the status conditions until the controller has applied the change")
+	_ = v.BindPFlag(config.ViperWait, cmd.PersistentFlags().Lookup(config.FlagWait))
+
+	cmd.PersistentFlags().Duration(config.FlagTimeout, config.DefaultTimeout,
+		"time to wait for the controller to apply the change")
+	_ = v.BindPFlag(config.ViperTimeout, cmd.PersistentFlags().Lookup(config.FlagTimeout))
+
+	cmd.AddCommand(newGetCommand(newCtl))
+	cmd.AddCommand(newSetDACCommand(newCtl))
+	cmd.AddCommand(newConnectCommand(newCtl))
+
+	return cmd
+}
+
+func newGetCommand(newCtl func(cmd *cobra.Command) (*ctl.Ctl, error)) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "get",
+		Short: "Display the state of a Jumperless resource",
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			return cmd.Help()
+		},
+	}
+
+	cmd.AddCommand(&cobra.Command{
+		Use:   "nets <name>",
+		Short: "Display the nets last read from the device",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			c, err := newCtl(cmd)
+			if err != nil {
+				return err
+			}
+
+			return c.GetNets(cmd.Context(), args[0]) //nolint:wrapcheck
+		},
+	})
+
+	return cmd
+}
+
+func newSetDACCommand(newCtl func(cmd *cobra.Command) (*ctl.Ctl, error)) *cobra.Command {
+	var save bool
+
+	cmd := &cobra.Command{
+		Use:     "set-dac <name> <channel> <voltage>",
+		Short:   "Set the voltage of a DAC channel",
+		Example: "  jumperless-utils ctl set-dac my-jumperless DAC0 3.3V",
+		Args:    cobra.ExactArgs(3), //nolint:mnd
+		RunE: func(cmd *cobra.Command, args []string) error {
+			c, err := newCtl(cmd)
+			if err != nil {
+				return err
+			}
+
+			return c.SetDAC(cmd.Context(), args[0], args[1], args[2], save) //nolint:wrapcheck
+		},
+	}
+
+	cmd.Flags().BoolVar(&save, "save", true, "save the voltage to the device config so it persists across power cycles")
+
+	return cmd
+}
+
+func newConnectCommand(newCtl func(cmd *cobra.Command) (*ctl.Ctl, error)) *cobra.Command {
+	return &cobra.Command{
+		Use:     "connect <name> <node> <node>",
+		Short:   "Connect two nodes",
+		Example: "  jumperless-utils ctl connect my-jumperless D2 GPIO_1",
+		Args:    cobra.ExactArgs(3), //nolint:mnd
+		RunE: func(cmd *cobra.Command, args []string) error {
+			c, err := newCtl(cmd)
+			if err != nil {
+				return err
+			}
+
+			return c.Connect(cmd.Context(), args[0], args[1], args[2]) //nolint:wrapcheck
+		},
+	}
+}
@@ -19,17 +19,22 @@ package emulator
 import (
 	"context"
 	"fmt"
-	"log"
+	"log/slog"
 
 	"github.com/spf13/cobra"
 	"github.com/spf13/viper"
+	"sigs.k8s.io/yaml"
 
+	"github.com/detiber/k8s-jumperless/utils/internal/client"
 	"github.com/detiber/k8s-jumperless/utils/internal/emulator"
 	"github.com/detiber/k8s-jumperless/utils/internal/emulator/config"
+	"github.com/detiber/k8s-jumperless/utils/internal/health"
+	"github.com/detiber/k8s-jumperless/utils/internal/logging"
+	"github.com/detiber/k8s-jumperless/utils/internal/server"
 )
 
-func NewEmulatorCommand(v *viper.Viper, parentLogger *log.Logger) *cobra.Command {
-	logger := log.New(parentLogger.Writer(), parentLogger.Prefix()+" [emulator]", parentLogger.Flags())
+func NewEmulatorCommand(v *viper.Viper, parentLogger *slog.Logger) *cobra.Command {
+	logger := logging.Subsystem(parentLogger, "emulator")
 	cmd := &cobra.Command{
 		Use:   "emulator",
 		Short: "Jumperless emulator",
@@ -48,13 +53,196 @@ func NewEmulatorCommand(v *viper.Viper, parentLogger *log.Logger) *cobra.Command
 		"symlink for virtual serial port(if not specified, it will use the autogenerated virtual port)")
 	_ = v.BindPFlag(config.ViperVirtualPort, cmd.Flags().Lookup(config.FlagVirtualPort))
 
+	cmd.Flags().String(config.FlagListen, "",
+		"TCP address to serve the emulated device on as a raw byte stream instead of a virtual serial port (e.g. :7331)")
+	_ = v.BindPFlag(config.ViperListen, cmd.Flags().Lookup(config.FlagListen))
+
+	cmd.Flags().String(config.FlagProtocol, config.ProtocolRaw,
+		"protocol served on the listen address: raw, or rfc2217 for clients changing the DTR and RTS lines or sending breaks")
+	_ = v.BindPFlag(config.ViperProtocol, cmd.Flags().Lookup(config.FlagProtocol))
+
+	cmd.Flags().StringSlice(config.FlagExtraPorts, nil,
+		"symlinks for additional virtual serial ports sharing the same emulated device, for attaching several clients")
+	_ = v.BindPFlag(config.ViperExtraPorts, cmd.Flags().Lookup(config.FlagExtraPorts))
+
+	cmd.Flags().String(config.FlagPassthrough, "",
+		"symlink for a second virtual serial port emulating the UART passthrough interface (disabled if not specified)")
+	_ = v.BindPFlag(config.ViperPassthroughPort, cmd.Flags().Lookup(config.FlagPassthrough))
+
+	cmd.Flags().String(config.FlagEngine, "",
+		"state engine answering requests without a mapping and providing state to response templates ("+
+			emulator.EngineJumperless+" or "+emulator.EnginePython+")")
+	_ = v.BindPFlag(config.ViperEngine, cmd.Flags().Lookup(config.FlagEngine))
+
+	cmd.Flags().String(config.FlagPersonality, "",
+		"built-in device config loaded into the engine (e.g. "+config.PersonalityFactory+
+			" for a factory-fresh device), config writes persist across resets")
+	_ = v.BindPFlag(config.ViperPersonality, cmd.Flags().Lookup(config.FlagPersonality))
+
+	cmd.Flags().String(config.FlagExec, "",
+		"client command to run once the virtual port is ready, "+client.PortPlaceholder+
+			" is replaced with the virtual port or listen address (the emulator stops when the client exits)")
+	_ = v.BindPFlag(config.ViperExec, cmd.Flags().Lookup(config.FlagExec))
+
+	cmd.Flags().Int64(config.FlagSeed, 0,
+		"seed of the random source for response jitter, random responses and injected faults (random if 0)")
+	_ = v.BindPFlag(config.ViperSeed, cmd.Flags().Lookup(config.FlagSeed))
+
+	cmd.Flags().String(config.FlagHealthAddr, "",
+		"TCP address to serve the /healthz and /readyz probes on (e.g. :8081, disabled if not specified)")
+	_ = v.BindPFlag(config.ViperHealthAddr, cmd.Flags().Lookup(config.FlagHealthAddr))
+
+	cmd.Flags().String(config.FlagStateFile, "",
+		"file the DAC, GPIO, connection and config state is saved to and restored from on start, so it survives "+
+			"restarts (disabled if not specified)")
+	_ = v.BindPFlag(config.ViperStateFile, cmd.Flags().Lookup(config.FlagStateFile))
+
+	cmd.Flags().String(config.FlagControlAddr, "",
+		"TCP address to serve the control API on, changing the emulated hardware at runtime (e.g. :8082, "+
+			"disabled if not specified)")
+	_ = v.BindPFlag(config.ViperControlAddr, cmd.Flags().Lookup(config.FlagControlAddr))
+
+	cmd.Flags().String(config.FlagBind, server.DefaultBind,
+		"host the listen address and endpoints are bound to when they don't specify one, listening on other "+
+			"interfaces than the loopback interface requires changing it (e.g. 0.0.0.0)")
+	_ = v.BindPFlag(config.ViperBind, cmd.Flags().Lookup(config.FlagBind))
+
+	cmd.Flags().String(config.FlagTLSCertFile, "",
+		"certificate the listen address and control API are served with over TLS, reloaded when it changes")
+	_ = v.BindPFlag(config.ViperTLSCertFile, cmd.Flags().Lookup(config.FlagTLSCertFile))
+
+	cmd.Flags().String(config.FlagTLSKeyFile, "", "key of --tls-cert-file")
+	_ = v.BindPFlag(config.ViperTLSKeyFile, cmd.Flags().Lookup(config.FlagTLSKeyFile))
+
+	cmd.Flags().String(config.FlagTLSCAFile, "",
+		"CA bundle client certificates have to be signed by, reloaded when it changes")
+	_ = v.BindPFlag(config.ViperTLSCAFile, cmd.Flags().Lookup(config.FlagTLSCAFile))
+
+	cmd.Flags().String(config.FlagTokenFile, "",
+		"file containing the bearer token clients of the control API have to send, reloaded when it changes")
+	_ = v.BindPFlag(config.ViperTokenFile, cmd.Flags().Lookup(config.FlagTokenFile))
+
+	cmd.Flags().Bool(config.FlagAllowUnauthenticated, false,
+		"serve the emulated device on non-loopback addresses without client certificates or a bearer token")
+	_ = v.BindPFlag(config.ViperAllowUnauthenticated, cmd.Flags().Lookup(config.FlagAllowUnauthenticated))
+
+	cmd.AddCommand(newSelfTestCommand(v, logger))
+	cmd.AddCommand(newValidateCommand(v))
+	cmd.AddCommand(newManifestCommand(v))
+	cmd.AddCommand(newDumpCommand(v))
+
+	return cmd
+}
+
+func newSelfTestCommand(v *viper.Viper, logger *slog.Logger) *cobra.Command {
+	return &cobra.Command{
+		Use:   "selftest",
+		Short: "Verify the emulator config",
+		Long: `Starts the emulator on a private virtual port, exercises every configured mapping once and verifies
+the responses render, failing on broken fixtures before they are used`,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			if err := emulator.SelfTest(cmd.Context(), config.NewFromViper(v), logger); err != nil {
+				return fmt.Errorf("emulator: %w", err)
+			}
+
+			return nil
+		},
+	}
+}
+
+func newValidateCommand(v *viper.Viper) *cobra.Command {
+	var strict bool
+
+	cmd := &cobra.Command{
+		Use:   "validate",
+		Short: "Lint the emulator config",
+		Long: `Checks the emulator config without starting the emulator: regular expressions compile, no mapping is
+shadowed by an earlier one, chunk delays are sane, and response templates only reference state the engine
+provides and groups the request pattern captures. Fails if any errors are found, or warnings with --strict`,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			findings := emulator.Validate(config.NewFromViper(v))
+
+			for _, finding := range findings {
+				if _, err := fmt.Fprintln(cmd.OutOrStdout(), finding); err != nil {
+					return fmt.Errorf("failed to write findings: %w", err)
+				}
+			}
+
+			if emulator.HasErrors(findings) || strict && len(findings) > 0 {
+				return fmt.Errorf("emulator: %w: %d problems found", emulator.ErrInvalidConfig, len(findings))
+			}
+
+			return nil
+		},
+	}
+
+	cmd.Flags().BoolVar(&strict, "strict", false, "fail on warnings too")
+
 	return cmd
 }
 
-func runEmulator(ctx context.Context, v *viper.Viper, logger *log.Logger) error {
-	emulatorConfig := config.NewFromViper(v)
+func newManifestCommand(v *viper.Viper) *cobra.Command {
+	var name, namespace string
 
-	logger.Printf("Starting Jumperless emulator with config: %+v", emulatorConfig)
+	cmd := &cobra.Command{
+		Use:   "manifest",
+		Short: "Generate a Jumperless manifest from the emulator config",
+		Long: `Writes a Jumperless resource whose DACs and connections match the hardware section of the emulator
+config, so emulator fixtures and cluster manifests stay in sync`,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			j, err := emulator.Manifest(config.NewFromViper(v), name, namespace)
+			if err != nil {
+				return fmt.Errorf("emulator: %w", err)
+			}
+
+			data, err := yaml.Marshal(j)
+			if err != nil {
+				return fmt.Errorf("failed to marshal manifest: %w", err)
+			}
+
+			if _, err := cmd.OutOrStdout().Write(data); err != nil {
+				return fmt.Errorf("failed to write manifest: %w", err)
+			}
+
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVar(&name, "name", "jumperless-emulator", "name of the Jumperless resource")
+	cmd.Flags().StringVar(&namespace, "namespace", "", "namespace of the Jumperless resource")
+
+	return cmd
+}
+
+func newDumpCommand(v *viper.Viper) *cobra.Command {
+	return &cobra.Command{
+		Use:   "dump",
+		Short: "Print the emulated device state as a config dump",
+		Long: `Writes the hardware state the emulator starts in, from the personality and hardware section of the
+emulator config, in the exact format of the config dump printed by the device for "~". The dacs section holds
+the current DAC voltages. Useful for diffing against real devices and as a fixture for config parsers`,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			dump, err := emulator.Dump(config.NewFromViper(v))
+			if err != nil {
+				return fmt.Errorf("emulator: %w", err)
+			}
+
+			if _, err := fmt.Fprint(cmd.OutOrStdout(), dump); err != nil {
+				return fmt.Errorf("failed to write config dump: %w", err)
+			}
+
+			return nil
+		},
+	}
+}
+
+func runEmulator(ctx context.Context, v *viper.Viper, logger *slog.Logger) error {
+	return Run(ctx, config.NewFromViper(v), logger)
+}
+
+// Run runs the emulator until ctx is cancelled, or until the configured client command exits
+func Run(ctx context.Context, emulatorConfig *config.EmulatorConfig, logger *slog.Logger) error {
+	logger.Info("Starting Jumperless emulator", "config", emulatorConfig)
 
 	// Create emulator
 	e, err := emulator.New(emulatorConfig, logger)
@@ -62,6 +250,28 @@ func runEmulator(ctx context.Context, v *viper.Viper, logger *log.Logger) error
 		return fmt.Errorf("failed to create emulator: %w", err)
 	}
 
+	if emulatorConfig.HealthAddr != "" {
+		addr, err := server.ResolveAddr(emulatorConfig.HealthAddr, emulatorConfig.Bind)
+		if err != nil {
+			return fmt.Errorf("invalid health address: %w", err)
+		}
+
+		if err := health.Serve(ctx, addr, e.Status, logger); err != nil {
+			return fmt.Errorf("failed to serve health probes: %w", err)
+		}
+	}
+
+	if emulatorConfig.ControlAddr != "" {
+		addr, err := server.ResolveAddr(emulatorConfig.ControlAddr, emulatorConfig.Bind)
+		if err != nil {
+			return fmt.Errorf("invalid control address: %w", err)
+		}
+
+		if err := e.ServeControl(ctx, addr); err != nil {
+			return fmt.Errorf("failed to serve control API: %w", err)
+		}
+	}
+
 	emuCtx, cancel := context.WithCancel(ctx)
 
 	// Start emulator
@@ -70,17 +280,41 @@ func runEmulator(ctx context.Context, v *viper.Viper, logger *log.Logger) error
 		return fmt.Errorf("failed to start emulator: %w", err)
 	}
 
-	logger.Printf("Emulator started. Virtual serial port: %s", e.GetPortName())
-	logger.Printf("Press Ctrl+C to stop")
+	if emulatorConfig.Listen != "" {
+		logger.Info("Emulator started", "listen", e.GetPortName())
+	} else {
+		logger.Info("Emulator started", "port", e.GetPortName())
+	}
+
+	if extraPorts := e.GetPortNames(); len(extraPorts) > 1 {
+		logger.Info("Extra virtual serial ports", "ports", extraPorts[1:])
+	}
+
+	if passthroughPort := e.GetPassthroughPortName(); passthroughPort != "" {
+		logger.Info("Passthrough port", "port", passthroughPort)
+	}
+
+	var clientErr error
+	if emulatorConfig.Exec != "" {
+		// Run the client to completion, its exit status becomes ours
+		clientErr = client.Run(ctx, logger, emulatorConfig.Exec, e.GetPortName())
+	} else {
+		logger.Info("Press Ctrl+C to stop")
+		<-ctx.Done()
+	}
 
-	<-ctx.Done()
 	cancel()
 
-	logger.Printf("Stopping emulator...")
+	logger.Info("Stopping emulator")
 	if err := e.Stop(); err != nil {
-		logger.Printf("Error stopping emulator: %v", err)
+		logger.Error("Error stopping emulator", logging.Err(err))
+	}
+
+	logger.Info("Emulator stopped")
+
+	if clientErr != nil {
+		return fmt.Errorf("emulator client error: %w", clientErr)
 	}
 
-	logger.Printf("emulator stopped")
 	return nil
 }
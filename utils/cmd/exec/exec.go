@@ -0,0 +1,110 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package exec
+
+import (
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"os"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+
+	"github.com/detiber/k8s-jumperless/utils/internal/exec"
+	"github.com/detiber/k8s-jumperless/utils/internal/exec/config"
+	"github.com/detiber/k8s-jumperless/utils/internal/logging"
+	logconfig "github.com/detiber/k8s-jumperless/utils/internal/logging/config"
+)
+
+func NewExecCommand(v *viper.Viper, verboseKey string) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "exec <command>",
+		Short: "Execute a single command on a Jumperless device",
+		Long: `Executes a single Python command (or a raw command with --raw) on a Jumperless device using the same
+client as the controller, and prints the cleaned response`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			// The arguments are valid, failures from here on are not usage errors
+			cmd.SilenceUsage = true
+
+			// Only the response is written to stdout so it can be used in scripts, logs are only written
+			// to stderr when verbose
+			logger := slog.New(slog.DiscardHandler)
+			if v.GetBool(verboseKey) {
+				var err error
+				if logger, err = logging.New(os.Stderr, logconfig.NewFromViper(v)); err != nil {
+					return fmt.Errorf("failed to configure logging: %w", err)
+				}
+			}
+
+			return runExec(cmd, v, args[0], logging.Subsystem(logger, "exec"))
+		},
+	}
+
+	// Command-line flags
+	cmd.Flags().Int(config.FlagBaudRate, config.DefaultBaudRate, "baud rate for the serial port")
+	_ = v.BindPFlag(config.ViperBaudRate, cmd.Flags().Lookup(config.FlagBaudRate))
+
+	cmd.Flags().String(config.FlagPort, "",
+		"serial port of the device (if not specified, will attempt to auto-detect)")
+	_ = v.BindPFlag(config.ViperPort, cmd.Flags().Lookup(config.FlagPort))
+
+	cmd.Flags().Bool(config.FlagRaw, false, "send the command as it is instead of as a Python command")
+	_ = v.BindPFlag(config.ViperRaw, cmd.Flags().Lookup(config.FlagRaw))
+
+	cmd.Flags().Duration(config.FlagWait, config.DefaultWait, "time to wait for the device to respond")
+	_ = v.BindPFlag(config.ViperWait, cmd.Flags().Lookup(config.FlagWait))
+
+	cmd.Flags().Bool(config.FlagJSON, false, "print the result as JSON, including the device and any error")
+	_ = v.BindPFlag(config.ViperJSON, cmd.Flags().Lookup(config.FlagJSON))
+
+	cmd.Flags().Duration(config.FlagDTRPulse, 0,
+		"drop DTR for this duration after opening the port, to wake or reset the device (disabled if zero)")
+	_ = v.BindPFlag(config.ViperDTRPulse, cmd.Flags().Lookup(config.FlagDTRPulse))
+
+	return cmd
+}
+
+func runExec(cmd *cobra.Command, v *viper.Viper, command string, logger *slog.Logger) error {
+	execConfig := config.NewFromViper(v)
+
+	result, err := exec.Run(cmd.Context(), execConfig, command, logger)
+
+	if !execConfig.JSON {
+		if err != nil {
+			return err
+		}
+
+		_, _ = fmt.Fprintln(cmd.OutOrStdout(), result.Response)
+
+		return nil
+	}
+
+	if err != nil {
+		result.Error = err.Error()
+	}
+
+	encoder := json.NewEncoder(cmd.OutOrStdout())
+	encoder.SetIndent("", "  ")
+
+	if encodeErr := encoder.Encode(result); encodeErr != nil {
+		return fmt.Errorf("failed to encode result: %w", encodeErr)
+	}
+
+	return err
+}
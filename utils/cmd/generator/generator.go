@@ -19,17 +19,20 @@ package generator
 import (
 	"context"
 	"fmt"
-	"log"
+	"log/slog"
 
 	"github.com/spf13/cobra"
 	"github.com/spf13/viper"
 
+	emulatorConfig "github.com/detiber/k8s-jumperless/utils/internal/emulator/config"
 	"github.com/detiber/k8s-jumperless/utils/internal/generator"
 	"github.com/detiber/k8s-jumperless/utils/internal/generator/config"
+	"github.com/detiber/k8s-jumperless/utils/internal/logging"
+	"github.com/detiber/k8s-jumperless/utils/internal/proxy"
 )
 
-func NewGeneratorCommand(v *viper.Viper, parentLogger *log.Logger) *cobra.Command {
-	logger := log.New(parentLogger.Writer(), parentLogger.Prefix()+" [generator]", parentLogger.Flags())
+func NewGeneratorCommand(v *viper.Viper, parentLogger *slog.Logger) *cobra.Command {
+	logger := logging.Subsystem(parentLogger, "generator")
 	cmd := &cobra.Command{
 		Use:   "generator",
 		Short: "Jumperless generator",
@@ -44,6 +47,16 @@ func NewGeneratorCommand(v *viper.Viper, parentLogger *log.Logger) *cobra.Comman
 	cmd.Flags().Int(config.FlagBaudRate, config.DefaultBaudRate, "baud rate for the real serial port")
 	_ = v.BindPFlag(config.ViperBaudRate, cmd.Flags().Lookup(config.FlagBaudRate))
 
+	cmd.Flags().Int(config.FlagDataBits, 0, "data bits for the real serial port, 5 to 8 (default 8)")
+	_ = v.BindPFlag(config.ViperDataBits, cmd.Flags().Lookup(config.FlagDataBits))
+
+	cmd.Flags().String(config.FlagParity, "",
+		"parity for the real serial port, one of none, odd, even, mark or space (default none)")
+	_ = v.BindPFlag(config.ViperParity, cmd.Flags().Lookup(config.FlagParity))
+
+	cmd.Flags().String(config.FlagStopBits, "", "stop bits for the real serial port, one of 1, 1.5 or 2 (default 1)")
+	_ = v.BindPFlag(config.ViperStopBits, cmd.Flags().Lookup(config.FlagStopBits))
+
 	cmd.Flags().Int(config.FlagBufferSize, config.DefaultBufferSize, "buffer size for reading from the real serial port")
 	_ = v.BindPFlag(config.ViperBufferSize, cmd.Flags().Lookup(config.FlagBufferSize))
 
@@ -51,13 +64,26 @@ func NewGeneratorCommand(v *viper.Viper, parentLogger *log.Logger) *cobra.Comman
 		"real serial port to use (if not specified, will attempt to auto-detect)")
 	_ = v.BindPFlag(config.ViperPort, cmd.Flags().Lookup(config.FlagPort))
 
+	cmd.Flags().String(config.FlagOutput, "",
+		"emulator config file to write the request/response pairs to, replacing it (disabled if not specified)")
+	_ = v.BindPFlag(config.ViperOutput, cmd.Flags().Lookup(config.FlagOutput))
+
+	cmd.Flags().String(config.FlagSuite, "",
+		"standard request suite to send before the configured requests: quick (firmware, config and nets), "+
+			"analog (all DAC and ADC reads) or full (both along with all GPIO reads)")
+	_ = v.BindPFlag(config.ViperSuite, cmd.Flags().Lookup(config.FlagSuite))
+
+	cmd.Flags().Duration(config.FlagIdle, config.DefaultResponseIdle,
+		"time without data from the device ending a response")
+	_ = v.BindPFlag(config.ViperIdle, cmd.Flags().Lookup(config.FlagIdle))
+
 	return cmd
 }
 
-func runGenerator(ctx context.Context, v *viper.Viper, logger *log.Logger) error {
+func runGenerator(ctx context.Context, v *viper.Viper, logger *slog.Logger) error {
 	generatorConfig := config.NewFromViper(v)
 
-	logger.Printf("Starting Jumperless generator with config: %+v", generatorConfig)
+	logger.Info("Starting Jumperless generator", "config", generatorConfig)
 
 	// Create generator
 	g, err := generator.New(generatorConfig, logger)
@@ -70,6 +96,30 @@ func runGenerator(ctx context.Context, v *viper.Viper, logger *log.Logger) error
 		return fmt.Errorf("failed to run generator: %w", err)
 	}
 
-	logger.Printf("generator stopped")
+	if generatorConfig.Output != "" {
+		if err := writeEmulatorConfig(generatorConfig, g.Recording()); err != nil {
+			return err
+		}
+
+		logger.Info("Saved emulator config", "file", generatorConfig.Output, "pairs", len(g.Recording()))
+	}
+
+	logger.Info("Generator stopped")
+	return nil
+}
+
+// writeEmulatorConfig writes the recorded request/response pairs as the mappings of an emulator config,
+// along with the environment they were recorded in
+func writeEmulatorConfig(generatorConfig *config.GeneratorConfig, recording emulatorConfig.Mappings) error {
+	v := viper.New()
+	v.Set("emulator.mappings", recording)
+	v.Set("emulator.recordings", []emulatorConfig.RecordingMetadata{
+		proxy.CaptureEnvironment(generatorConfig.Port, ""),
+	})
+
+	if err := v.WriteConfigAs(generatorConfig.Output); err != nil {
+		return fmt.Errorf("failed to write emulator config: %w", err)
+	}
+
 	return nil
 }
@@ -19,17 +19,21 @@ package main
 import (
 	"context"
 	"errors"
-	"log"
+	"log/slog"
 	"os"
+	"os/exec"
 	"os/signal"
 	"syscall"
+
+	"github.com/detiber/k8s-jumperless/utils/internal/logging"
 )
 
 func main() {
-	// Setup logger
-	logger := log.New(os.Stdout, "", log.LstdFlags)
+	// Setup logger, the format and level are configured once the flags and config file are read
+	handler := logging.NewHandler(os.Stdout)
+	logger := slog.New(handler)
 
-	c := newRootCommand(logger)
+	c := newRootCommand(logger, handler)
 
 	// Setup signal handling
 	ctx, cancel := context.WithCancelCause(context.Background())
@@ -38,7 +42,7 @@ func main() {
 
 	go func() {
 		sig := <-sigChan
-		logger.Printf("Received signal %s, shutting down...", sig)
+		logger.Info("Received signal, shutting down", "signal", sig)
 
 		cancel(nil)
 	}()
@@ -48,9 +52,20 @@ func main() {
 			os.Exit(0)
 		}
 
-		logger.Printf("Error: %v", err)
-		os.Exit(1)
+		logger.Error("Command failed", logging.Err(err))
+
+		os.Exit(exitCode(err))
 	}
 
 	cancel(nil)
 }
+
+// exitCode returns the exit code for an error, propagating the exit code of a failed client command
+func exitCode(err error) int {
+	var exitErr *exec.ExitError
+	if errors.As(err, &exitErr) && exitErr.ExitCode() > 0 {
+		return exitErr.ExitCode()
+	}
+
+	return 1
+}
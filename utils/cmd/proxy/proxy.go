@@ -20,20 +20,34 @@ import (
 	"context"
 	"errors"
 	"fmt"
-	"log"
+	"log/slog"
+	"net/http"
 	"os"
+	"slices"
 
 	"github.com/spf13/cobra"
 	"github.com/spf13/viper"
 
+	emulatorCmd "github.com/detiber/k8s-jumperless/utils/cmd/emulator"
+	"github.com/detiber/k8s-jumperless/utils/internal/client"
 	emulatorConfig "github.com/detiber/k8s-jumperless/utils/internal/emulator/config"
+	"github.com/detiber/k8s-jumperless/utils/internal/health"
+	"github.com/detiber/k8s-jumperless/utils/internal/logging"
+	logconfig "github.com/detiber/k8s-jumperless/utils/internal/logging/config"
 	"github.com/detiber/k8s-jumperless/utils/internal/proxy"
 	"github.com/detiber/k8s-jumperless/utils/internal/proxy/config"
+	"github.com/detiber/k8s-jumperless/utils/internal/server"
 )
 
-func NewProxyCommand(v *viper.Viper, parentLogger *log.Logger,
+var ErrReplayListen = errors.New("replaying a recording over RFC2217 is not supported, use emulator --listen instead")
+var ErrReplayListenGRPC = errors.New("replaying a recording over the Remote gRPC service is not supported")
+var ErrEmptyRecording = errors.New("recording contains no request/response pairs")
+var ErrRecordStdoutLogs = errors.New("logs are written to stdout, use --log-output stderr with --record-stdout " +
+	"or a stdout sink")
+
+func NewProxyCommand(v *viper.Viper, parentLogger *slog.Logger,
 	defaultConfigFile, configFileFlagName string) *cobra.Command {
-	logger := log.New(parentLogger.Writer(), parentLogger.Prefix()+" [proxy]", parentLogger.Flags())
+	logger := logging.Subsystem(parentLogger, "proxy")
 
 	cmd := &cobra.Command{
 		Use:   "proxy",
@@ -48,14 +62,30 @@ func NewProxyCommand(v *viper.Viper, parentLogger *log.Logger,
 			}
 
 			proxyConfig := config.NewFromViper(v)
+			if proxyConfig.Replay != "" {
+				return runReplay(ctx, logger, proxyConfig)
+			}
+
+			// The streamed pairs would be interleaved with the logs
+			streamsStdout := proxyConfig.RecordStdout != "" || slices.ContainsFunc(proxyConfig.Sinks,
+				func(sink config.SinkConfig) bool { return sink.Type == config.SinkStdout })
+			if streamsStdout && logconfig.NewFromViper(v).Output == logconfig.OutputStdout {
+				return ErrRecordStdoutLogs
+			}
+
 			emuConfig := emulatorConfig.NewFromViper(v)
 
-			recording, err := runProxy(ctx, logger, proxyConfig)
-			if err != nil {
+			// A failing client command still produces a recording worth saving
+			recording, events, runErr := runProxy(ctx, logger, proxyConfig, configFile)
+			if runErr != nil && !errors.Is(runErr, client.ErrClientFailed) {
+				return runErr
+			}
+
+			if err := saveRecording(logger, proxyConfig, emuConfig, configFile, recording, events); err != nil {
 				return err
 			}
 
-			return saveRecording(logger, proxyConfig, emuConfig, configFile, recording)
+			return runErr
 		},
 	}
 
@@ -71,33 +101,244 @@ func NewProxyCommand(v *viper.Viper, parentLogger *log.Logger,
 		"real serial port to use (if not specified, will attempt to auto-detect)")
 	_ = v.BindPFlag(config.ViperRealPort, cmd.Flags().Lookup(config.FlagRealPort))
 
+	cmd.Flags().String(config.FlagListen, "",
+		"TCP address to serve the virtual side on using RFC2217 instead of a virtual serial port (e.g. :7332)")
+	_ = v.BindPFlag(config.ViperListen, cmd.Flags().Lookup(config.FlagListen))
+
+	cmd.Flags().String(config.FlagListenGRPC, "",
+		"TCP address to serve the virtual side on using the Remote gRPC service instead of a virtual serial port, "+
+			"so the operator can drive the device through the proxy (e.g. :9445)")
+	_ = v.BindPFlag(config.ViperListenGRPC, cmd.Flags().Lookup(config.FlagListenGRPC))
+
 	cmd.Flags().Int(config.FlagBaudRate, config.DefaultBaudRate, "baud rate for the real serial port")
 	_ = v.BindPFlag(config.ViperBaudRate, cmd.Flags().Lookup(config.FlagBaudRate))
 
+	cmd.Flags().Int(config.FlagDataBits, 0, "data bits for the real serial port, 5 to 8 (default 8)")
+	_ = v.BindPFlag(config.ViperDataBits, cmd.Flags().Lookup(config.FlagDataBits))
+
+	cmd.Flags().String(config.FlagParity, "",
+		"parity for the real serial port, one of none, odd, even, mark or space (default none)")
+	_ = v.BindPFlag(config.ViperParity, cmd.Flags().Lookup(config.FlagParity))
+
+	cmd.Flags().String(config.FlagStopBits, "", "stop bits for the real serial port, one of 1, 1.5 or 2 (default 1)")
+	_ = v.BindPFlag(config.ViperStopBits, cmd.Flags().Lookup(config.FlagStopBits))
+
 	cmd.Flags().Bool(config.FlagOverwrite, false, "overwrite existing emulator mappings instead of appending")
 	_ = v.BindPFlag(config.ViperOverwrite, cmd.Flags().Lookup(config.FlagOverwrite))
 
+	cmd.Flags().String(config.FlagExec, "",
+		"client command to run once the virtual port is ready, "+client.PortPlaceholder+
+			" is replaced with the virtual port or listen address (the proxy stops when the client exits)")
+	_ = v.BindPFlag(config.ViperExec, cmd.Flags().Lookup(config.FlagExec))
+
+	cmd.Flags().String(config.FlagFraming, config.DefaultFraming,
+		"how requests are split for recording: read (every read), newline, prompt (until the device prompt) or idle")
+	_ = v.BindPFlag(config.ViperFramingMode, cmd.Flags().Lookup(config.FlagFraming))
+
+	cmd.Flags().String(config.FlagFramingPrompt, config.DefaultFramingPrompt,
+		"device prompt ending a request in prompt framing")
+	_ = v.BindPFlag(config.ViperFramingPrompt, cmd.Flags().Lookup(config.FlagFramingPrompt))
+
+	cmd.Flags().Duration(config.FlagFramingIdle, config.DefaultFramingIdle,
+		"time without client data ending a request in idle framing")
+	_ = v.BindPFlag(config.ViperFramingIdle, cmd.Flags().Lookup(config.FlagFramingIdle))
+
+	cmd.Flags().StringSlice(config.FlagInclude, []string{},
+		"regular expressions, when set only requests matching any of them are recorded")
+	_ = v.BindPFlag(config.ViperInclude, cmd.Flags().Lookup(config.FlagInclude))
+
+	cmd.Flags().StringSlice(config.FlagExclude, []string{},
+		"regular expressions, requests matching any of them are not recorded, e.g. keep-alive traffic")
+	_ = v.BindPFlag(config.ViperExclude, cmd.Flags().Lookup(config.FlagExclude))
+
+	cmd.Flags().Int(config.FlagShapeRate, 0,
+		"bandwidth of the virtual side link in bytes per second, to test clients against slow links (unlimited if 0)")
+	_ = v.BindPFlag(config.ViperShapeRate, cmd.Flags().Lookup(config.FlagShapeRate))
+
+	cmd.Flags().Duration(config.FlagShapeRTT, 0, "round trip time added to the virtual side link")
+	_ = v.BindPFlag(config.ViperShapeRTT, cmd.Flags().Lookup(config.FlagShapeRTT))
+
+	cmd.Flags().Duration(config.FlagShapeJitter, 0,
+		"maximum random delay added in each direction of the virtual side link, data is still delivered in order")
+	_ = v.BindPFlag(config.ViperShapeJitter, cmd.Flags().Lookup(config.FlagShapeJitter))
+
+	cmd.Flags().Int(config.FlagShapeRequestRate, 0,
+		"bandwidth of requests on the virtual side link in bytes per second, overriding --shape-rate")
+	_ = v.BindPFlag(config.ViperShapeRequestRate, cmd.Flags().Lookup(config.FlagShapeRequestRate))
+
+	cmd.Flags().Duration(config.FlagShapeRequestLatency, 0,
+		"latency added to requests on the virtual side link, overriding half of --shape-rtt")
+	_ = v.BindPFlag(config.ViperShapeRequestLatency, cmd.Flags().Lookup(config.FlagShapeRequestLatency))
+
+	cmd.Flags().Int(config.FlagShapeResponseRate, 0,
+		"bandwidth of responses on the virtual side link in bytes per second, overriding --shape-rate")
+	_ = v.BindPFlag(config.ViperShapeResponseRate, cmd.Flags().Lookup(config.FlagShapeResponseRate))
+
+	cmd.Flags().Duration(config.FlagShapeResponseLatency, 0,
+		"latency added to responses on the virtual side link, overriding half of --shape-rtt")
+	_ = v.BindPFlag(config.ViperShapeResponseLatency, cmd.Flags().Lookup(config.FlagShapeResponseLatency))
+
+	cmd.Flags().String(config.FlagHealthAddr, "",
+		"TCP address to serve the /healthz and /readyz probes on (e.g. :8081, disabled if not specified)")
+	_ = v.BindPFlag(config.ViperHealthAddr, cmd.Flags().Lookup(config.FlagHealthAddr))
+
+	cmd.Flags().String(config.FlagMetricsAddr, "",
+		"TCP address to serve the Prometheus /metrics endpoint on while recording (e.g. :9090, disabled if not specified)")
+	_ = v.BindPFlag(config.ViperMetricsAddr, cmd.Flags().Lookup(config.FlagMetricsAddr))
+
+	cmd.Flags().String(config.FlagBind, server.DefaultBind,
+		"host the listen address and endpoints are bound to when they don't specify one, listening on other "+
+			"interfaces than the loopback interface requires changing it (e.g. 0.0.0.0)")
+	_ = v.BindPFlag(config.ViperBind, cmd.Flags().Lookup(config.FlagBind))
+
+	cmd.Flags().String(config.FlagTLSCertFile, "",
+		"certificate the listen address, gRPC service and TCP observers are served with over TLS, reloaded "+
+			"when it changes")
+	_ = v.BindPFlag(config.ViperTLSCertFile, cmd.Flags().Lookup(config.FlagTLSCertFile))
+
+	cmd.Flags().String(config.FlagTLSKeyFile, "", "key of --tls-cert-file")
+	_ = v.BindPFlag(config.ViperTLSKeyFile, cmd.Flags().Lookup(config.FlagTLSKeyFile))
+
+	cmd.Flags().String(config.FlagTLSCAFile, "",
+		"CA bundle client certificates have to be signed by, reloaded when it changes")
+	_ = v.BindPFlag(config.ViperTLSCAFile, cmd.Flags().Lookup(config.FlagTLSCAFile))
+
+	cmd.Flags().String(config.FlagTokenFile, "",
+		"file containing the bearer token clients of the gRPC service have to send, reloaded when it changes")
+	_ = v.BindPFlag(config.ViperTokenFile, cmd.Flags().Lookup(config.FlagTokenFile))
+
+	cmd.Flags().Bool(config.FlagAllowUnauthenticated, false,
+		"serve the serial port on non-loopback addresses without client certificates or a bearer token")
+	_ = v.BindPFlag(config.ViperAllowUnauthenticated, cmd.Flags().Lookup(config.FlagAllowUnauthenticated))
+
+	cmd.Flags().String(config.FlagRecordStdout, "",
+		"stream each recorded request/response pair to stdout as it happens, in the given format (ndjson, "+
+			"requires --log-output stderr)")
+	_ = v.BindPFlag(config.ViperRecordStdout, cmd.Flags().Lookup(config.FlagRecordStdout))
+
+	cmd.Flags().StringSlice(config.FlagRecordSink, []string{},
+		"additional sinks streaming each recorded request/response pair as ndjson as it happens: stdout, "+
+			"file:<path> or tcp:<address> (may be repeated, stdout requires --log-output stderr)")
+	_ = v.BindPFlag(config.ViperRecordSink, cmd.Flags().Lookup(config.FlagRecordSink))
+
+	cmd.Flags().StringSlice(config.FlagObserver, []string{},
+		"mirror the traffic in both directions to a read-only observer as it passes the proxy: pty:<path> for a "+
+			"virtual serial port or tcp:<address> to listen for TCP clients (may be repeated)")
+	_ = v.BindPFlag(config.ViperObserver, cmd.Flags().Lookup(config.FlagObserver))
+
+	cmd.Flags().Int64(config.FlagRecordingMaxSize, 0,
+		"rotate the recording into a separate file once it exceeds this many bytes (disabled if 0)")
+	_ = v.BindPFlag(config.ViperRecordingMaxSize, cmd.Flags().Lookup(config.FlagRecordingMaxSize))
+
+	cmd.Flags().Duration(config.FlagRecordingMaxAge, 0,
+		"rotate the recording into a separate file once it is this old (disabled if 0)")
+	_ = v.BindPFlag(config.ViperRecordingMaxAge, cmd.Flags().Lookup(config.FlagRecordingMaxAge))
+
+	cmd.Flags().Int(config.FlagRecordingMaxFiles, 0,
+		"number of rotated recording files to keep, removing the oldest first (all are kept if 0)")
+	_ = v.BindPFlag(config.ViperRecordingMaxFiles, cmd.Flags().Lookup(config.FlagRecordingMaxFiles))
+
+	cmd.Flags().String(config.FlagRecordingCompression, config.CompressionNone,
+		"compression of rotated recording files: none, gzip or zstd")
+	_ = v.BindPFlag(config.ViperRecordingCompression, cmd.Flags().Lookup(config.FlagRecordingCompression))
+
+	cmd.Flags().String(config.FlagCapture, "",
+		"pcap file to write the raw traffic in both directions to, alongside the recording")
+	_ = v.BindPFlag(config.ViperCapture, cmd.Flags().Lookup(config.FlagCapture))
+
+	cmd.Flags().String(config.FlagReplay, "",
+		"recording, emulator config or ndjson sink file to serve on the virtual port instead of forwarding to a "+
+			"real serial port, rotated recordings may be compressed (no recording is saved)")
+	_ = v.BindPFlag(config.ViperReplay, cmd.Flags().Lookup(config.FlagReplay))
+
 	return cmd
 }
 
-func runProxy(ctx context.Context, logger *log.Logger,
-	proxyConfig *config.ProxyConfig) (emulatorConfig.Mappings, error) {
-	logger.Printf("Starting Jumperless proxy with config: %+v", proxyConfig)
+// runReplay serves the mappings of a previous recording on the virtual port using the emulator
+func runReplay(ctx context.Context, logger *slog.Logger, proxyConfig *config.ProxyConfig) error {
+	if proxyConfig.Listen != "" {
+		return ErrReplayListen
+	}
+	if proxyConfig.ListenGRPC != "" {
+		return ErrReplayListenGRPC
+	}
+
+	recording, err := proxy.LoadRecording(proxyConfig.Replay)
+	if err != nil {
+		return fmt.Errorf("failed to read recording %s: %w", proxyConfig.Replay, err)
+	}
+
+	replayConfig := recording.Emulator
+	if len(replayConfig.Mappings) == 0 {
+		return fmt.Errorf("%w: %s", ErrEmptyRecording, proxyConfig.Replay)
+	}
+
+	// Serve the recording where the proxy would have served the real device
+	replayConfig.BufferSize = proxyConfig.BufferSize
+	replayConfig.VirtualPort = proxyConfig.VirtualPort
+	replayConfig.ExtraPorts = nil
+	replayConfig.Listen = ""
+	replayConfig.Exec = proxyConfig.Exec
+	replayConfig.HealthAddr = proxyConfig.HealthAddr
+	replayConfig.Bind = proxyConfig.Bind
+
+	logger.Info("Replaying recorded request/response pairs",
+		"pairs", len(replayConfig.Mappings), "recording", proxyConfig.Replay)
+
+	if err := emulatorCmd.Run(ctx, replayConfig, logger); err != nil {
+		return fmt.Errorf("failed to replay recording: %w", err)
+	}
+
+	return nil
+}
+
+func runProxy(ctx context.Context, logger *slog.Logger, proxyConfig *config.ProxyConfig,
+	configFile string) (emulatorConfig.Mappings, []emulatorConfig.PortEvent, error) {
+	logger.Info("Starting Jumperless proxy", "config", proxyConfig)
 
 	// Create proxy
 	p, err := proxy.New(proxyConfig, logger)
 	if err != nil {
-		return nil, fmt.Errorf("failed to create proxy: %w", err)
+		return nil, nil, fmt.Errorf("failed to create proxy: %w", err)
+	}
+
+	if err := p.RotateRecording(configFile); err != nil {
+		return nil, nil, fmt.Errorf("failed to configure recording rotation: %w", err)
+	}
+
+	if proxyConfig.HealthAddr != "" {
+		addr, err := server.ResolveAddr(proxyConfig.HealthAddr, proxyConfig.Bind)
+		if err != nil {
+			return nil, nil, fmt.Errorf("invalid health address: %w", err)
+		}
+
+		if err := health.Serve(ctx, addr, p.Status, logger); err != nil {
+			return nil, nil, fmt.Errorf("failed to serve health probes: %w", err)
+		}
+	}
+
+	if proxyConfig.MetricsAddr != "" {
+		addr, err := server.ResolveAddr(proxyConfig.MetricsAddr, proxyConfig.Bind)
+		if err != nil {
+			return nil, nil, fmt.Errorf("invalid metrics address: %w", err)
+		}
+
+		mux := http.NewServeMux()
+		mux.Handle("GET /metrics", p.MetricsHandler())
+
+		if err := server.Serve(ctx, addr, "metrics", mux, logger); err != nil {
+			return nil, nil, fmt.Errorf("failed to serve metrics: %w", err)
+		}
 	}
 
 	recording, err := p.Run(ctx)
 	if err != nil {
-		return nil, fmt.Errorf("failed to run proxy: %w", err)
+		return recording, p.Events(), fmt.Errorf("failed to run proxy: %w", err)
 	}
 
-	logger.Printf("proxy stopped")
+	logger.Info("Proxy stopped")
 
-	return recording, nil
+	return recording, p.Events(), nil
 }
 
 func findConfigFile(cmd *cobra.Command, v *viper.Viper, configFileFlagName, defaultConfigFile string) (string, error) {
@@ -120,35 +361,39 @@ func findConfigFile(cmd *cobra.Command, v *viper.Viper, configFileFlagName, defa
 	return defaultConfigFile, nil
 }
 
-func saveRecording(logger *log.Logger, proxyConfig *config.ProxyConfig,
+func saveRecording(logger *slog.Logger, proxyConfig *config.ProxyConfig,
 	emuConfig *emulatorConfig.EmulatorConfig, configFile string,
-	recording emulatorConfig.Mappings) error {
-	if len(recording) == 0 {
-		logger.Printf("No requests/responses recorded")
+	recording emulatorConfig.Mappings, events []emulatorConfig.PortEvent) error {
+	if len(recording) == 0 && len(events) == 0 {
+		logger.Info("No requests/responses recorded")
 		return nil
 	}
 
+	// Capture the environment before the config file is updated, so the hash matches the config used
+	environment := proxy.CaptureEnvironment(proxyConfig.RealPort, configFile)
+	environment.Events = events
+	if len(events) > 0 {
+		logger.Info("Recorded port events", "events", len(events))
+	}
+
 	// Save recording
 	switch {
+	case len(recording) == 0:
+		logger.Info("No requests/responses recorded, keeping the existing emulator mappings")
 	case proxyConfig.Overwrite:
-		logger.Printf(
-			"Overwriting existing emulator mappings and saving %d recorded request/response pairs to emulator config",
-			len(recording),
-		)
+		logger.Info("Overwriting existing emulator mappings with the recorded request/response pairs",
+			"pairs", len(recording))
 
 		emuConfig.Mappings = recording
+		emuConfig.Recordings = nil
 	case len(emuConfig.Mappings) == 0:
-		logger.Printf(
-			"No existing emulator mappings, saving %d recorded request/response pairs to emulator config",
-			len(recording),
-		)
+		logger.Info("No existing emulator mappings, saving the recorded request/response pairs",
+			"pairs", len(recording))
 
 		emuConfig.Mappings = recording
 	default:
-		logger.Printf(
-			"Existing emulator mappings, appending %d recorded request/response pairs to emulator config",
-			len(recording),
-		)
+		logger.Info("Existing emulator mappings, appending the recorded request/response pairs",
+			"pairs", len(recording))
 
 		for _, r := range recording {
 			emuConfig.Mappings.AddResponse(r.Request, r.Responses...)
@@ -166,12 +411,15 @@ func saveRecording(logger *log.Logger, proxyConfig *config.ProxyConfig,
 		return fmt.Errorf("error reading config file: %w", err)
 	}
 
+	emuConfig.Recordings = append(emuConfig.Recordings, environment)
+
+	v.Set("emulator.recordings", emuConfig.Recordings)
 	v.Set("emulator.mappings", emuConfig.Mappings)
 	if err := v.WriteConfigAs(configFile); err != nil {
 		return fmt.Errorf("failed to write updated config file: %w", err)
 	}
 
-	logger.Printf("Saved updated emulator mappings to config file: %s", configFile)
+	logger.Info("Saved updated emulator mappings to config file", "file", configFile)
 
 	return nil
 }
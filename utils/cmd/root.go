@@ -20,7 +20,7 @@ import (
 	"context"
 	"errors"
 	"fmt"
-	"log"
+	"log/slog"
 	"os"
 	"path/filepath"
 	"strings"
@@ -28,16 +28,23 @@ import (
 	"github.com/spf13/cobra"
 	"github.com/spf13/viper"
 
+	"github.com/detiber/k8s-jumperless/utils/cmd/capture"
+	"github.com/detiber/k8s-jumperless/utils/cmd/config"
+	"github.com/detiber/k8s-jumperless/utils/cmd/ctl"
 	"github.com/detiber/k8s-jumperless/utils/cmd/emulator"
+	"github.com/detiber/k8s-jumperless/utils/cmd/exec"
 	"github.com/detiber/k8s-jumperless/utils/cmd/generator"
 	"github.com/detiber/k8s-jumperless/utils/cmd/proxy"
+	"github.com/detiber/k8s-jumperless/utils/cmd/terminal"
+	"github.com/detiber/k8s-jumperless/utils/internal/logging"
+	logconfig "github.com/detiber/k8s-jumperless/utils/internal/logging/config"
 )
 
 const (
 	defaultConfigFile = "jumperless-utils.yml"
 	cfgConfig         = "config"
 	cfgGenerateConfig = "generate-config"
-	cfgVerbose        = "verbose"
+	cfgVerbose        = logconfig.FlagVerbose
 	cfgShowConfig     = "show-config"
 )
 
@@ -47,10 +54,13 @@ var ErrGenerateConfig = errors.New("generate config requested")
 type rootCommand struct {
 	cmd    *cobra.Command
 	v      *viper.Viper
-	logger *log.Logger
+	logger *slog.Logger
+
+	// lifecycle logs the end of the subcommand once it started, nil until then
+	lifecycle *logging.Lifecycle
 }
 
-func newRootCommand(logger *log.Logger) *rootCommand {
+func newRootCommand(logger *slog.Logger, handler *logging.Handler) *rootCommand {
 	v := viper.New()
 
 	// Environment variable support
@@ -58,9 +68,12 @@ func newRootCommand(logger *log.Logger) *rootCommand {
 	v.SetEnvKeyReplacer(strings.NewReplacer(".", "_", "-", "_"))
 	v.AutomaticEnv()
 
-	rootLogger := log.New(logger.Writer(), "[jumperless-utils] ", logger.Flags())
+	// The subcommands add their own subsystem to the logger
+	rootLogger := logging.Subsystem(logger, "jumperless-utils")
 
-	c := &rootCommand{
+	// The persistent pre-run of the subcommands starts their lifecycle on c
+	var c *rootCommand
+	c = &rootCommand{
 		v:      v,
 		logger: rootLogger,
 		cmd: &cobra.Command{
@@ -73,10 +86,23 @@ func newRootCommand(logger *log.Logger) *rootCommand {
 					return fmt.Errorf("failed to get config flag: %w", err)
 				}
 
-				if err := loadConfig(v, configFile, rootLogger); err != nil {
+				if err := loadConfig(v, configFile); err != nil {
 					return fmt.Errorf("failed to load config: %w", err)
 				}
 
+				logConfig := logconfig.NewFromViper(v)
+
+				output, err := logging.Output(logConfig)
+				if err != nil {
+					return fmt.Errorf("failed to configure logging: %w", err)
+				}
+
+				if err := handler.Configure(output, logConfig); err != nil {
+					return fmt.Errorf("failed to configure logging: %w", err)
+				}
+
+				rootLogger.Debug("Loaded config", "file", v.ConfigFileUsed(), "values", v.AllSettings())
+
 				// Handle utility flags
 				shouldShowConfig, err := cmd.Flags().GetBool(cfgShowConfig)
 				if err != nil {
@@ -93,9 +119,12 @@ func newRootCommand(logger *log.Logger) *rootCommand {
 					return showConfig(cmd, v)
 				case shouldGenerateConfig:
 					return generateConfig(cmd, v, configFile, rootLogger)
-				default:
-					return nil
+				case cmd != cmd.Root():
+					command := strings.TrimPrefix(cmd.CommandPath(), cmd.Root().Name()+" ")
+					c.lifecycle = logging.StartLifecycle(rootLogger, command, os.Getpid())
 				}
+
+				return nil
 			},
 			RunE: func(cmd *cobra.Command, _ []string) error {
 				return cmd.Help()
@@ -109,30 +138,58 @@ func newRootCommand(logger *log.Logger) *rootCommand {
 	c.cmd.PersistentFlags().String(cfgConfig, "", "config file (default is "+defaultConfigFile+")")
 
 	// General flags mapped to config
-	c.cmd.PersistentFlags().Bool(cfgVerbose, false, "enable verbose logging")
-	_ = v.BindPFlag(cfgVerbose, c.cmd.PersistentFlags().Lookup(cfgVerbose))
+	c.cmd.PersistentFlags().Bool(cfgVerbose, false, "enable verbose logging, same as --log-level debug")
+	_ = v.BindPFlag(logconfig.ViperVerbose, c.cmd.PersistentFlags().Lookup(cfgVerbose))
+
+	c.cmd.PersistentFlags().String(logconfig.FlagFormat, logconfig.DefaultFormat, "log format (text or json)")
+	_ = v.BindPFlag(logconfig.ViperFormat, c.cmd.PersistentFlags().Lookup(logconfig.FlagFormat))
+
+	c.cmd.PersistentFlags().String(logconfig.FlagLevel, logconfig.DefaultLevel,
+		"minimum log level (debug, info, warn or error)")
+	_ = v.BindPFlag(logconfig.ViperLevel, c.cmd.PersistentFlags().Lookup(logconfig.FlagLevel))
+
+	c.cmd.PersistentFlags().String(logconfig.FlagOutput, logconfig.DefaultOutput, "log output (stdout or stderr)")
+	_ = v.BindPFlag(logconfig.ViperOutput, c.cmd.PersistentFlags().Lookup(logconfig.FlagOutput))
 
 	// Utility flags not mapped to config
 	c.cmd.PersistentFlags().Bool(cfgGenerateConfig, false, "generate default config file and exit")
 	c.cmd.PersistentFlags().Bool(cfgShowConfig, false, "show current configuration and exit")
 
 	// Add subcommands
-	c.cmd.AddCommand(generator.NewGeneratorCommand(v, rootLogger))
-	c.cmd.AddCommand(emulator.NewEmulatorCommand(v, rootLogger))
-	c.cmd.AddCommand(proxy.NewProxyCommand(v, rootLogger, defaultConfigFile, cfgConfig))
+	c.cmd.AddCommand(generator.NewGeneratorCommand(v, logger))
+	c.cmd.AddCommand(emulator.NewEmulatorCommand(v, logger))
+	c.cmd.AddCommand(proxy.NewProxyCommand(v, logger, defaultConfigFile, cfgConfig))
+	c.cmd.AddCommand(capture.NewCaptureCommand(logger))
+	c.cmd.AddCommand(config.NewConfigCommand(logger))
+	c.cmd.AddCommand(terminal.NewTerminalCommand(v, logger))
+	c.cmd.AddCommand(exec.NewExecCommand(v, cfgVerbose))
+	c.cmd.AddCommand(ctl.NewCtlCommand(v, cfgVerbose))
 
 	return c
 }
 
 func (c *rootCommand) execute(ctx context.Context) error {
-	if err := c.cmd.ExecuteContext(ctx); err != nil {
+	err := c.cmd.ExecuteContext(ctx)
+
+	if c.lifecycle != nil {
+		switch {
+		case err != nil:
+			c.lifecycle.Abort(err, exitCode(err))
+		case ctx.Err() != nil:
+			c.lifecycle.Stop(logging.ExitInterrupted)
+		default:
+			c.lifecycle.Stop(logging.ExitCompleted)
+		}
+	}
+
+	if err != nil {
 		return fmt.Errorf("command execution failed: %w", err)
 	}
 
 	return nil
 }
 
-func loadConfig(v *viper.Viper, configFile string, logger *log.Logger) error {
+func loadConfig(v *viper.Viper, configFile string) error {
 	if configFile != "" {
 		v.SetConfigFile(configFile)
 	} else {
@@ -150,17 +207,6 @@ func loadConfig(v *viper.Viper, configFile string, logger *log.Logger) error {
 		return fmt.Errorf("error reading config file: %w", err)
 	}
 
-	if v.GetBool(cfgVerbose) {
-		cfgFile := v.ConfigFileUsed()
-		if cfgFile == "" {
-			defaultConfigValues := v.AllSettings()
-			logger.Printf("No config file specified, using the default config values: %+v\n", defaultConfigValues)
-		} else {
-			logger.Printf("Using config file: %s\n", v.ConfigFileUsed())
-			logger.Printf("Config values: %+v\n", v.AllSettings())
-		}
-	}
-
 	return nil
 }
 
@@ -176,7 +222,7 @@ func showConfig(cmd *cobra.Command, v *viper.Viper) error {
 	return ErrShowConfig
 }
 
-func generateConfig(cmd *cobra.Command, v *viper.Viper, configFile string, logger *log.Logger) error {
+func generateConfig(cmd *cobra.Command, v *viper.Viper, configFile string, logger *slog.Logger) error {
 	// Generate default config file
 	if configFile == "" {
 		configFile = defaultConfigFile
@@ -189,7 +235,7 @@ func generateConfig(cmd *cobra.Command, v *viper.Viper, configFile string, logge
 		}
 	}
 
-	logger.Printf("Generated default config file: %s", configFile)
+	logger.Info("Generated default config file", "file", configFile)
 
 	cmd.SilenceErrors = true
 	cmd.SilenceUsage = true
@@ -0,0 +1,89 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package terminal
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+
+	"github.com/detiber/k8s-jumperless/utils/internal/logging"
+	"github.com/detiber/k8s-jumperless/utils/internal/terminal"
+	"github.com/detiber/k8s-jumperless/utils/internal/terminal/config"
+)
+
+func NewTerminalCommand(v *viper.Viper, parentLogger *slog.Logger) *cobra.Command {
+	logger := logging.Subsystem(parentLogger, "terminal")
+	cmd := &cobra.Command{
+		Use:   "terminal",
+		Short: "Interactive Jumperless terminal",
+		Long: `An interactive terminal for a Jumperless device or emulator port, with line editing, command history
+and an optional session log`,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			ctx := cmd.Context()
+			return runTerminal(ctx, v, logger)
+		},
+	}
+
+	// Command-line flags
+	cmd.Flags().Int(config.FlagBaudRate, config.DefaultBaudRate, "baud rate for the serial port")
+	_ = v.BindPFlag(config.ViperBaudRate, cmd.Flags().Lookup(config.FlagBaudRate))
+
+	cmd.Flags().Int(config.FlagBufferSize, config.DefaultBufferSize, "buffer size for reading from the serial port")
+	_ = v.BindPFlag(config.ViperBufferSize, cmd.Flags().Lookup(config.FlagBufferSize))
+
+	cmd.Flags().String(config.FlagPort, "",
+		"serial port to use, e.g. an emulator virtual port (if not specified, will attempt to auto-detect)")
+	_ = v.BindPFlag(config.ViperPort, cmd.Flags().Lookup(config.FlagPort))
+
+	cmd.Flags().String(config.FlagLineEnding, config.DefaultLineEnding,
+		"line ending sent after each line: cr, lf, crlf or none")
+	_ = v.BindPFlag(config.ViperLineEnding, cmd.Flags().Lookup(config.FlagLineEnding))
+
+	cmd.Flags().Bool(config.FlagStripANSI, false, "remove ANSI escape sequences from the device output")
+	_ = v.BindPFlag(config.ViperStripANSI, cmd.Flags().Lookup(config.FlagStripANSI))
+
+	cmd.Flags().String(config.FlagHistory, "", "file to load and save the command history (not saved if not specified)")
+	_ = v.BindPFlag(config.ViperHistory, cmd.Flags().Lookup(config.FlagHistory))
+
+	cmd.Flags().String(config.FlagLog, "", "file to append the session output to, without ANSI escape sequences")
+	_ = v.BindPFlag(config.ViperLog, cmd.Flags().Lookup(config.FlagLog))
+
+	cmd.Flags().Duration(config.FlagDTRPulse, 0,
+		"drop DTR for this duration after opening the port, to wake or reset the device (disabled if zero)")
+	_ = v.BindPFlag(config.ViperDTRPulse, cmd.Flags().Lookup(config.FlagDTRPulse))
+
+	return cmd
+}
+
+func runTerminal(ctx context.Context, v *viper.Viper, logger *slog.Logger) error {
+	terminalConfig := config.NewFromViper(v)
+
+	t, err := terminal.New(terminalConfig, logger)
+	if err != nil {
+		return fmt.Errorf("failed to create terminal: %w", err)
+	}
+
+	if err := t.Run(ctx); err != nil {
+		return fmt.Errorf("failed to run terminal: %w", err)
+	}
+
+	return nil
+}
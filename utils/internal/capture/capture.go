@@ -0,0 +1,217 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package capture reads and writes raw captures of serial traffic as pcap files. Each packet holds the bytes
+// of a single read from one side of the connection, prefixed with a byte giving the direction.
+package capture
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"sync"
+	"time"
+)
+
+var ErrInvalidCapture = errors.New("invalid capture")
+
+// Direction is the direction of the data in a frame
+type Direction byte
+
+const (
+	// DirectionRequest is data sent by the client to the device
+	DirectionRequest Direction = 0
+
+	// DirectionResponse is data sent by the device to the client
+	DirectionResponse Direction = 1
+)
+
+func (d Direction) String() string {
+	switch d {
+	case DirectionRequest:
+		return "request"
+	case DirectionResponse:
+		return "response"
+	default:
+		return fmt.Sprintf("Direction(%d)", byte(d))
+	}
+}
+
+const (
+	// LinkType is LINKTYPE_USER0, reserved for private use, since no link type describes raw serial traffic
+	LinkType = 147
+
+	// magicNanoseconds identifies a pcap file with nanosecond timestamps
+	magicNanoseconds = 0xa1b23c4d
+
+	// magicMicroseconds identifies a pcap file with microsecond timestamps
+	magicMicroseconds = 0xa1b2c3d4
+
+	versionMajor = 2
+	versionMinor = 4
+
+	// snapLength is the maximum length of a packet, larger than any single read from a serial port
+	snapLength = 262144
+
+	fileHeaderLength   = 24
+	packetHeaderLength = 16
+)
+
+// Frame is the data of a single read from one side of the connection
+type Frame struct {
+	Time      time.Time
+	Direction Direction
+	Data      []byte
+}
+
+// Writer writes frames to a pcap file
+type Writer struct {
+	w    io.Writer
+	lock sync.Mutex
+}
+
+// NewWriter writes the pcap file header and returns a Writer for the frames
+func NewWriter(w io.Writer) (*Writer, error) {
+	header := make([]byte, fileHeaderLength)
+	binary.LittleEndian.PutUint32(header[0:], magicNanoseconds)
+	binary.LittleEndian.PutUint16(header[4:], versionMajor)
+	binary.LittleEndian.PutUint16(header[6:], versionMinor)
+	// The time zone offset and timestamp accuracy are always zero
+	binary.LittleEndian.PutUint32(header[16:], snapLength)
+	binary.LittleEndian.PutUint32(header[20:], LinkType)
+
+	if _, err := w.Write(header); err != nil {
+		return nil, fmt.Errorf("failed to write capture header: %w", err)
+	}
+
+	return &Writer{w: w}, nil
+}
+
+// WriteFrame writes a single frame, it is safe to call from multiple goroutines
+func (w *Writer) WriteFrame(frame Frame) error {
+	length := len(frame.Data) + 1
+
+	packet := make([]byte, packetHeaderLength, packetHeaderLength+length)
+	binary.LittleEndian.PutUint32(packet[0:], uint32(frame.Time.Unix()))       //nolint:gosec
+	binary.LittleEndian.PutUint32(packet[4:], uint32(frame.Time.Nanosecond())) //nolint:gosec
+	binary.LittleEndian.PutUint32(packet[8:], uint32(length))                  //nolint:gosec
+	binary.LittleEndian.PutUint32(packet[12:], uint32(length))                 //nolint:gosec
+	packet = append(packet, byte(frame.Direction))
+	packet = append(packet, frame.Data...)
+
+	w.lock.Lock()
+	defer w.lock.Unlock()
+
+	if _, err := w.w.Write(packet); err != nil {
+		return fmt.Errorf("failed to write capture frame: %w", err)
+	}
+
+	return nil
+}
+
+// Reader reads frames from a pcap file written by Writer
+type Reader struct {
+	r           io.Reader
+	order       binary.ByteOrder
+	nanoseconds bool
+}
+
+// NewReader reads the pcap file header and returns a Reader for the frames
+func NewReader(r io.Reader) (*Reader, error) {
+	header := make([]byte, fileHeaderLength)
+	if _, err := io.ReadFull(r, header); err != nil {
+		return nil, fmt.Errorf("%w: failed to read header: %w", ErrInvalidCapture, err)
+	}
+
+	reader := &Reader{r: r}
+
+	// The magic number identifies both the byte order and the timestamp resolution
+	for _, order := range []binary.ByteOrder{binary.LittleEndian, binary.BigEndian} {
+		switch order.Uint32(header[0:]) {
+		case magicNanoseconds:
+			reader.order = order
+			reader.nanoseconds = true
+		case magicMicroseconds:
+			reader.order = order
+		}
+	}
+
+	if reader.order == nil {
+		return nil, fmt.Errorf("%w: not a pcap file", ErrInvalidCapture)
+	}
+
+	if linkType := reader.order.Uint32(header[20:]); linkType != LinkType {
+		return nil, fmt.Errorf("%w: unsupported link type %d", ErrInvalidCapture, linkType)
+	}
+
+	return reader, nil
+}
+
+// ReadFrame returns the next frame, or io.EOF once all frames have been read
+func (r *Reader) ReadFrame() (Frame, error) {
+	header := make([]byte, packetHeaderLength)
+	if _, err := io.ReadFull(r.r, header); err != nil {
+		if errors.Is(err, io.EOF) {
+			return Frame{}, io.EOF
+		}
+		return Frame{}, fmt.Errorf("%w: failed to read frame header: %w", ErrInvalidCapture, err)
+	}
+
+	seconds := int64(r.order.Uint32(header[0:]))
+	fraction := int64(r.order.Uint32(header[4:]))
+	if !r.nanoseconds {
+		fraction *= int64(time.Microsecond)
+	}
+
+	length := r.order.Uint32(header[8:])
+	if length == 0 || length > snapLength {
+		return Frame{}, fmt.Errorf("%w: invalid frame length %d", ErrInvalidCapture, length)
+	}
+
+	packet := make([]byte, length)
+	if _, err := io.ReadFull(r.r, packet); err != nil {
+		return Frame{}, fmt.Errorf("%w: failed to read frame: %w", ErrInvalidCapture, err)
+	}
+
+	direction := Direction(packet[0])
+	if direction != DirectionRequest && direction != DirectionResponse {
+		return Frame{}, fmt.Errorf("%w: unknown direction %d", ErrInvalidCapture, packet[0])
+	}
+
+	return Frame{
+		Time:      time.Unix(seconds, fraction),
+		Direction: direction,
+		Data:      packet[1:],
+	}, nil
+}
+
+// ReadAll returns all remaining frames
+func (r *Reader) ReadAll() ([]Frame, error) {
+	frames := []Frame{}
+
+	for {
+		frame, err := r.ReadFrame()
+		if errors.Is(err, io.EOF) {
+			return frames, nil
+		}
+		if err != nil {
+			return frames, err
+		}
+
+		frames = append(frames, frame)
+	}
+}
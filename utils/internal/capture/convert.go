@@ -0,0 +1,94 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package capture
+
+import (
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/detiber/k8s-jumperless/utils/internal/emulator"
+	emulatorConfig "github.com/detiber/k8s-jumperless/utils/internal/emulator/config"
+)
+
+// ToMappings converts frames to emulator mappings the same way the proxy recorder does: every request
+// frame starts a new request, and the response frames that follow become its response chunks, delayed
+// by the time since the previous frame.
+func ToMappings(frames []Frame) emulatorConfig.Mappings {
+	mappings := emulatorConfig.Mappings{}
+
+	var currentRequest string
+	var currentResponse *emulatorConfig.ResponseOption
+	var previous time.Time
+
+	for _, frame := range frames {
+		switch frame.Direction {
+		case DirectionRequest:
+			if currentResponse != nil {
+				mappings.AddResponse(currentRequest, *currentResponse)
+			}
+
+			currentRequest = string(frame.Data)
+			currentResponse = new(emulatorConfig.ResponseOption)
+		case DirectionResponse:
+			// Responses without a preceding request can't be replayed
+			if currentResponse == nil {
+				continue
+			}
+
+			delay := frame.Time.Sub(previous)
+			currentResponse.Chunks = append(currentResponse.Chunks, emulatorConfig.ResponseChunk{
+				Data:      strconv.Quote(string(frame.Data)),
+				Delay:     delay,
+				JitterMax: delay / 10, //nolint:mnd // 10% of the delay
+			})
+		}
+
+		previous = frame.Time
+	}
+
+	if currentResponse != nil {
+		mappings.AddResponse(currentRequest, *currentResponse)
+	}
+
+	return mappings
+}
+
+// FromMappings converts emulator mappings to frames starting at the given time. Every response of every
+// mapping becomes a request frame followed by its response chunks, spaced by the chunk delays.
+func FromMappings(mappings emulatorConfig.Mappings, start time.Time) ([]Frame, error) {
+	frames := []Frame{}
+	now := start
+
+	for _, mapping := range mappings {
+		for _, response := range mapping.Responses {
+			frames = append(frames, Frame{Time: now, Direction: DirectionRequest, Data: []byte(mapping.Request)})
+
+			for _, chunk := range response.Chunks {
+				data, err := emulator.RenderChunk(chunk)
+				if err != nil {
+					return nil, fmt.Errorf("failed to convert response to %q: %w", mapping.Request, err)
+				}
+
+				now = now.Add(chunk.Delay)
+				frames = append(frames, Frame{Time: now, Direction: DirectionResponse, Data: []byte(data)})
+			}
+		}
+	}
+
+	return frames, nil
+}
@@ -0,0 +1,98 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package client
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"os/exec"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/detiber/k8s-jumperless/utils/internal/logging"
+)
+
+const (
+	// PortPlaceholder is replaced with the virtual serial port name in client commands
+	PortPlaceholder = "{{port}}"
+
+	// EnvPort is the environment variable containing the virtual serial port name
+	EnvPort = "JUMPERLESS_PORT"
+
+	// shutdownGracePeriod is how long the client is given to exit after being signalled
+	shutdownGracePeriod = 5 * time.Second
+)
+
+var ErrEmptyCommand = errors.New("empty client command")
+var ErrClientFailed = errors.New("client command failed")
+
+// Expand substitutes the virtual port name into the client command
+func Expand(command, portName string) string {
+	return strings.ReplaceAll(command, PortPlaceholder, portName)
+}
+
+// Run runs the client command against the given virtual serial port.
+// The command is run through /bin/sh so that quoting and pipelines work as expected.
+// Run blocks until the command exits, if the context is cancelled first the client
+// is sent SIGTERM and given a grace period to exit before being killed, in which case
+// no error is returned.
+func Run(ctx context.Context, logger *slog.Logger, command, portName string) error {
+	if strings.TrimSpace(command) == "" {
+		return ErrEmptyCommand
+	}
+
+	expanded := Expand(command, portName)
+
+	cmd := exec.CommandContext(ctx, "/bin/sh", "-c", expanded)
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+	cmd.Env = append(os.Environ(), EnvPort+"="+portName)
+	cmd.Cancel = func() error {
+		return cmd.Process.Signal(syscall.SIGTERM)
+	}
+	cmd.WaitDelay = shutdownGracePeriod
+
+	logger.Info("Starting client command", "command", expanded)
+
+	if err := cmd.Start(); err != nil {
+		return fmt.Errorf("%w: %q: %w", ErrClientFailed, expanded, err)
+	}
+
+	lifecycle := logging.StartLifecycle(logger, expanded, cmd.Process.Pid)
+
+	if err := cmd.Wait(); err != nil {
+		if ctx.Err() != nil {
+			// The client was stopped because we are shutting down, this is not a client failure
+			logger.Info("Client command stopped", logging.Err(err))
+			lifecycle.Stop(logging.ExitInterrupted)
+			return nil
+		}
+
+		lifecycle.Abort(err, cmd.ProcessState.ExitCode())
+
+		return fmt.Errorf("%w: %q: %w", ErrClientFailed, expanded, err)
+	}
+
+	logger.Info("Client command exited successfully")
+	lifecycle.Stop(logging.ExitCompleted)
+
+	return nil
+}
@@ -0,0 +1,96 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package config
+
+import (
+	"time"
+
+	"github.com/spf13/viper"
+)
+
+const (
+	// Default values for the ctl configuration
+	DefaultWait    = true
+	DefaultTimeout = 2 * time.Minute
+
+	// Flag names for command-line arguments
+	FlagKubeconfig = "kubeconfig"
+	FlagContext    = "context"
+	FlagNamespace  = "namespace"
+	FlagWait       = "wait"
+	FlagTimeout    = "timeout"
+
+	// Viper prefix and keys for configuration
+	ViperPrefix     = "ctl"
+	ViperKubeconfig = ViperPrefix + "." + FlagKubeconfig
+	ViperContext    = ViperPrefix + "." + FlagContext
+	ViperNamespace  = ViperPrefix + "." + FlagNamespace
+	ViperWait       = ViperPrefix + "." + FlagWait
+	ViperTimeout    = ViperPrefix + "." + FlagTimeout
+)
+
+// NewDefaultConfig returns a CtlConfig with default values
+func NewDefaultConfig() *CtlConfig {
+	return &CtlConfig{
+		Kubeconfig: "",
+		Context:    "",
+		Namespace:  "",
+		Wait:       DefaultWait,
+		Timeout:    DefaultTimeout,
+	}
+}
+
+// NewFromViper creates a CtlConfig from a viper instance
+func NewFromViper(v *viper.Viper) *CtlConfig {
+	cfg := NewDefaultConfig()
+
+	if v.IsSet(ViperKubeconfig) {
+		cfg.Kubeconfig = v.GetString(ViperKubeconfig)
+	}
+	if v.IsSet(ViperContext) {
+		cfg.Context = v.GetString(ViperContext)
+	}
+	if v.IsSet(ViperNamespace) {
+		cfg.Namespace = v.GetString(ViperNamespace)
+	}
+	if v.IsSet(ViperWait) {
+		cfg.Wait = v.GetBool(ViperWait)
+	}
+	if v.IsSet(ViperTimeout) {
+		cfg.Timeout = v.GetDuration(ViperTimeout)
+	}
+
+	return cfg
+}
+
+// CtlConfig represents the ctl configuration
+type CtlConfig struct {
+	// Kubeconfig is the kubeconfig file to use, the default loading rules apply if not set
+	Kubeconfig string `json:"kubeconfig" mapstructure:"kubeconfig" yaml:"kubeconfig"`
+
+	// Context is the kubeconfig context to use, the current context is used if not set
+	Context string `json:"context" mapstructure:"context" yaml:"context"`
+
+	// Namespace of the Jumperless resources, the namespace of the context is used if not set
+	Namespace string `json:"namespace" mapstructure:"namespace" yaml:"namespace"`
+
+	// Wait streams the status conditions after a change until the controller has applied it
+	Wait bool `json:"wait" mapstructure:"wait" yaml:"wait"`
+
+	// Timeout is how long to wait for the controller to apply a change
+	Timeout time.Duration `json:"timeout" mapstructure:"timeout" yaml:"timeout"`
+}
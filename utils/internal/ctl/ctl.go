@@ -0,0 +1,343 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ctl
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"slices"
+	"strings"
+	"text/tabwriter"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/fields"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/watch"
+	"k8s.io/client-go/tools/clientcmd"
+	"k8s.io/client-go/util/retry"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	jumperlessv5alpha1 "github.com/detiber/k8s-jumperless/api/v5alpha1"
+	"github.com/detiber/k8s-jumperless/internal/controller/local"
+	"github.com/detiber/k8s-jumperless/jumperless/voltage"
+	"github.com/detiber/k8s-jumperless/utils/internal/ctl/config"
+)
+
+var (
+	ErrInvalidDACChannel = errors.New("invalid DAC channel")
+	ErrDegraded          = errors.New("jumperless is degraded")
+	ErrNotConverged      = errors.New("timed out waiting for the controller to apply the change")
+)
+
+// Ctl changes Jumperless resources through the Kubernetes API and waits for the controller to apply the changes
+type Ctl struct {
+	client    client.WithWatch
+	namespace string
+	config    *config.CtlConfig
+	out       io.Writer
+	logger    *slog.Logger
+}
+
+// New creates a Ctl using the kubeconfig, context and namespace from the config
+func New(c *config.CtlConfig, out io.Writer, logger *slog.Logger) (*Ctl, error) {
+	loadingRules := clientcmd.NewDefaultClientConfigLoadingRules()
+	loadingRules.ExplicitPath = c.Kubeconfig
+
+	overrides := &clientcmd.ConfigOverrides{CurrentContext: c.Context}
+	if c.Namespace != "" {
+		overrides.Context.Namespace = c.Namespace
+	}
+
+	clientConfig := clientcmd.NewNonInteractiveDeferredLoadingClientConfig(loadingRules, overrides)
+
+	restConfig, err := clientConfig.ClientConfig()
+	if err != nil {
+		return nil, fmt.Errorf("failed to load kubeconfig: %w", err)
+	}
+
+	namespace, _, err := clientConfig.Namespace()
+	if err != nil {
+		return nil, fmt.Errorf("failed to determine namespace: %w", err)
+	}
+
+	scheme := runtime.NewScheme()
+	if err := jumperlessv5alpha1.AddToScheme(scheme); err != nil {
+		return nil, fmt.Errorf("failed to register Jumperless types: %w", err)
+	}
+
+	k8sClient, err := client.NewWithWatch(restConfig, client.Options{Scheme: scheme})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create Kubernetes client: %w", err)
+	}
+
+	logger.Debug("Using namespace", "namespace", namespace)
+
+	return &Ctl{
+		client:    k8sClient,
+		namespace: namespace,
+		config:    c,
+		out:       out,
+		logger:    logger,
+	}, nil
+}
+
+// GetNets prints the nets last read from the device of a Jumperless resource
+func (c *Ctl) GetNets(ctx context.Context, name string) error {
+	j := &jumperlessv5alpha1.Jumperless{}
+	if err := c.client.Get(ctx, types.NamespacedName{Namespace: c.namespace, Name: name}, j); err != nil {
+		return fmt.Errorf("failed to get jumperless %s: %w", name, err)
+	}
+
+	if len(j.Status.Nets) == 0 {
+		_, _ = fmt.Fprintf(c.out, "No nets found for jumperless %s\n", name)
+
+		return nil
+	}
+
+	w := tabwriter.NewWriter(c.out, 0, 0, 3, ' ', 0) //nolint:mnd
+	_, _ = fmt.Fprintln(w, "INDEX\tNAME\tVOLTAGE\tNODES")
+
+	for _, net := range j.Status.Nets {
+		_, _ = fmt.Fprintf(w, "%d\t%s\t%s\t%s\n",
+			net.Index, net.Name, ptr.Deref(net.Voltage, "-"), strings.Join(net.Nodes, ","))
+	}
+
+	if err := w.Flush(); err != nil {
+		return fmt.Errorf("failed to write nets: %w", err)
+	}
+
+	return nil
+}
+
+// SetDAC sets the desired voltage of a DAC channel and waits until the device reports it
+func (c *Ctl) SetDAC(ctx context.Context, name, channel, volts string, save bool) error {
+	if _, ok := jumperlessv5alpha1.ParseDACChannel(channel); !ok {
+		return fmt.Errorf("%w: %s", ErrInvalidDACChannel, channel)
+	}
+
+	desired, err := voltage.ParseInRange(volts)
+	if err != nil {
+		return fmt.Errorf("invalid voltage %s: %w", volts, err)
+	}
+
+	dac := jumperlessv5alpha1.DAC{
+		Channel: channel,
+		Voltage: voltage.Format(desired),
+		Save:    ptr.To(save),
+	}
+
+	j, err := c.patch(ctx, name, func(j *jumperlessv5alpha1.Jumperless) {
+		i := slices.IndexFunc(j.Spec.DACS, func(d jumperlessv5alpha1.DAC) bool { return d.Channel == channel })
+		if i < 0 {
+			j.Spec.DACS = append(j.Spec.DACS, dac)
+
+			return
+		}
+
+		j.Spec.DACS[i] = dac
+	})
+	if err != nil {
+		return err
+	}
+
+	return c.wait(ctx, j, func(j *jumperlessv5alpha1.Jumperless) bool {
+		i := slices.IndexFunc(j.Status.DACS, func(s jumperlessv5alpha1.DACStatus) bool { return s.Channel == channel })
+		if i < 0 {
+			return false
+		}
+
+		current, err := voltage.Parse(j.Status.DACS[i].Voltage)
+
+		return err == nil && voltage.Equal(current, desired)
+	})
+}
+
+// Connect adds a connection between two nodes and waits until the device reports them in the same net
+func (c *Ctl) Connect(ctx context.Context, name, from, to string) error {
+	j, err := c.patch(ctx, name, func(j *jumperlessv5alpha1.Jumperless) {
+		if slices.ContainsFunc(j.Spec.Connections, func(conn jumperlessv5alpha1.Connection) bool {
+			return (strings.EqualFold(conn.From, from) && strings.EqualFold(conn.To, to)) ||
+				(strings.EqualFold(conn.From, to) && strings.EqualFold(conn.To, from))
+		}) {
+			return
+		}
+
+		j.Spec.Connections = append(j.Spec.Connections, jumperlessv5alpha1.Connection{From: from, To: to})
+	})
+	if err != nil {
+		return err
+	}
+
+	return c.wait(ctx, j, func(j *jumperlessv5alpha1.Jumperless) bool {
+		return local.IsConnected(j.Status.Nets, from, to)
+	})
+}
+
+// patch applies mutate to the spec of a Jumperless resource, retrying when it was changed concurrently
+func (c *Ctl) patch(
+	ctx context.Context,
+	name string,
+	mutate func(j *jumperlessv5alpha1.Jumperless),
+) (*jumperlessv5alpha1.Jumperless, error) {
+	j := &jumperlessv5alpha1.Jumperless{}
+
+	err := retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		if err := c.client.Get(ctx, types.NamespacedName{Namespace: c.namespace, Name: name}, j); err != nil {
+			return err //nolint:wrapcheck
+		}
+
+		original := j.DeepCopy()
+		mutate(j)
+
+		return c.client.Patch(ctx, j, client.MergeFromWithOptions(original, client.MergeFromWithOptimisticLock{}))
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to patch jumperless %s: %w", name, err)
+	}
+
+	_, _ = fmt.Fprintf(c.out, "jumperless/%s patched (generation %d)\n", name, j.Generation)
+
+	return j, nil
+}
+
+// wait streams the status conditions of a Jumperless resource until the controller reports it ready for the
+// patched generation and converged reports the desired state, or the controller reports it degraded
+func (c *Ctl) wait(
+	ctx context.Context,
+	j *jumperlessv5alpha1.Jumperless,
+	converged func(j *jumperlessv5alpha1.Jumperless) bool,
+) error {
+	if !c.config.Wait {
+		return nil
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, c.config.Timeout)
+	defer cancel()
+
+	generation := j.Generation
+	printed := map[string]metav1.Condition{}
+
+	for {
+		done, err := c.check(j, generation, converged, printed)
+		if done || err != nil {
+			return err
+		}
+
+		// Watch from the last seen version so no update is missed, the watch is restarted when the server
+		// closes it
+		watcher, err := c.client.Watch(ctx, &jumperlessv5alpha1.JumperlessList{}, &client.ListOptions{
+			Namespace:     c.namespace,
+			FieldSelector: fields.OneTermEqualSelector("metadata.name", j.Name),
+			Raw:           &metav1.ListOptions{ResourceVersion: j.ResourceVersion},
+		})
+		if err != nil {
+			return fmt.Errorf("failed to watch jumperless %s: %w", j.Name, err)
+		}
+
+		j, err = c.next(ctx, watcher, j)
+		watcher.Stop()
+
+		if err != nil {
+			return err
+		}
+	}
+}
+
+// next returns the next version of a Jumperless resource from a watch, or the last seen version when the
+// watch is closed
+func (c *Ctl) next(
+	ctx context.Context,
+	watcher watch.Interface,
+	last *jumperlessv5alpha1.Jumperless,
+) (*jumperlessv5alpha1.Jumperless, error) {
+	select {
+	case <-ctx.Done():
+		if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+			return nil, fmt.Errorf("%w after %s", ErrNotConverged, c.config.Timeout)
+		}
+
+		return nil, ctx.Err() //nolint:wrapcheck
+	case event, ok := <-watcher.ResultChan():
+		if !ok {
+			c.logger.Debug("Watch closed, restarting", "resourceVersion", last.ResourceVersion)
+
+			return last, nil
+		}
+
+		switch event.Type {
+		case watch.Error:
+			return nil, fmt.Errorf("failed to watch jumperless %s: %w", last.Name, apierrors.FromObject(event.Object))
+		case watch.Deleted:
+			return nil, fmt.Errorf("jumperless %s was deleted: %w", last.Name,
+				apierrors.NewNotFound(jumperlessv5alpha1.GroupVersion.WithResource("jumperlesses").GroupResource(),
+					last.Name))
+		default:
+			j, ok := event.Object.(*jumperlessv5alpha1.Jumperless)
+			if !ok {
+				return last, nil
+			}
+
+			return j, nil
+		}
+	}
+}
+
+// check prints the conditions of a Jumperless resource that changed since they were last printed and reports
+// whether the controller applied the patched generation
+func (c *Ctl) check(
+	j *jumperlessv5alpha1.Jumperless,
+	generation int64,
+	converged func(j *jumperlessv5alpha1.Jumperless) bool,
+	printed map[string]metav1.Condition,
+) (bool, error) {
+	for _, condition := range j.Status.Conditions {
+		last, ok := printed[condition.Type]
+		if ok && last.Status == condition.Status && last.Reason == condition.Reason &&
+			last.Message == condition.Message && last.ObservedGeneration == condition.ObservedGeneration {
+			continue
+		}
+
+		printed[condition.Type] = condition
+		_, _ = fmt.Fprintf(c.out, "%s=%s %s: %s (generation %d)\n",
+			condition.Type, condition.Status, condition.Reason, condition.Message, condition.ObservedGeneration)
+	}
+
+	degraded := meta.FindStatusCondition(j.Status.Conditions, jumperlessv5alpha1.ConditionDegraded)
+	if degraded != nil && degraded.Status == metav1.ConditionTrue && degraded.ObservedGeneration >= generation {
+		return true, fmt.Errorf("%w: %s", ErrDegraded, degraded.Message)
+	}
+
+	ready := meta.FindStatusCondition(j.Status.Conditions, jumperlessv5alpha1.ConditionReady)
+	if ready == nil || ready.Status != metav1.ConditionTrue || ready.ObservedGeneration < generation {
+		return false, nil
+	}
+
+	if !converged(j) {
+		return false, nil
+	}
+
+	_, _ = fmt.Fprintf(c.out, "jumperless/%s converged\n", j.Name)
+
+	return true, nil
+}
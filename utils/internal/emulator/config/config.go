@@ -22,20 +22,105 @@ import (
 	"time"
 
 	"github.com/spf13/viper"
+
+	"github.com/detiber/k8s-jumperless/internal/transport"
+	"github.com/detiber/k8s-jumperless/utils/internal/server"
 )
 
 const (
 	// Default values for the emulator configuration
 	DefaultBufferSize = 1024
 
+	// DefaultBaudRate is the baud rate reported to RFC2217 clients
+	DefaultBaudRate = 115200
+
+	// Protocols served on the listen address
+	ProtocolRaw     = "raw"
+	ProtocolRFC2217 = "rfc2217"
+
+	// Request matching modes
+	MatchExact = "exact"
+	MatchRegex = "regex"
+
+	// Response selection modes of mappings with several responses
+	SelectSequential = "sequential"
+	SelectRandom     = "random"
+
+	// Scenario event actions
+	ActionSet    = "set"
+	ActionRamp   = "ramp"
+	ActionToggle = "toggle"
+	ActionWave   = "wave"
+
+	// Waveforms generated by wave scenario events
+	WaveformSine     = "sine"
+	WaveformSquare   = "square"
+	WaveformTriangle = "triangle"
+	WaveformSawtooth = "sawtooth"
+
+	// Port events recorded by the proxy and handled by control rules
+	EventBaudRate = "baud-rate"
+	EventDTR      = "dtr"
+	EventRTS      = "rts"
+	EventBreak    = "break"
+
+	// Values of DTR, RTS and break events
+	EventOn  = "on"
+	EventOff = "off"
+
+	// Control rule actions
+	ActionReset = "reset"
+	ActionDump  = "dump"
+
+	// PersonalityFactory is the personality of a factory-fresh device, answering the firmware default config
+	PersonalityFactory = "factory"
+
 	// Flag names for command-line arguments
 	FlagBufferSize  = "buffer-size"
 	FlagVirtualPort = "virtual-port"
+	FlagListen      = "listen"
+	FlagProtocol    = "listen-protocol"
+	FlagExtraPorts  = "extra-ports"
+	FlagEngine      = "engine"
+	FlagPersonality = "personality"
+	FlagPassthrough = "passthrough-port"
+	FlagExec        = "exec"
+	FlagSeed        = "seed"
+	FlagHealthAddr  = "health-addr"
+	FlagBind        = "bind"
+	FlagStateFile   = "state-file"
+	FlagControlAddr = "control-addr"
+
+	FlagTLSCertFile          = server.FlagTLSCertFile
+	FlagTLSKeyFile           = server.FlagTLSKeyFile
+	FlagTLSCAFile            = server.FlagTLSCAFile
+	FlagTokenFile            = server.FlagTokenFile
+	FlagAllowUnauthenticated = server.FlagAllowUnauthenticated
 
 	// Viper prefix and keys for configuration
-	ViperPrefix      = "emulator"
-	ViperBufferSize  = ViperPrefix + "." + FlagBufferSize
-	ViperVirtualPort = ViperPrefix + "." + FlagVirtualPort
+	ViperPrefix          = "emulator"
+	ViperBufferSize      = ViperPrefix + "." + FlagBufferSize
+	ViperVirtualPort     = ViperPrefix + "." + FlagVirtualPort
+	ViperListen          = ViperPrefix + "." + FlagListen
+	ViperProtocol        = ViperPrefix + "." + FlagProtocol
+	ViperExtraPorts      = ViperPrefix + "." + FlagExtraPorts
+	ViperEngine          = ViperPrefix + "." + FlagEngine
+	ViperPersonality     = ViperPrefix + "." + FlagPersonality
+	ViperPassthrough     = ViperPrefix + ".passthrough"
+	ViperPassthroughPort = ViperPassthrough + ".virtual-port"
+	ViperExec            = ViperPrefix + "." + FlagExec
+	ViperSeed            = ViperPrefix + "." + FlagSeed
+	ViperHealthAddr      = ViperPrefix + "." + FlagHealthAddr
+	ViperBind            = ViperPrefix + "." + FlagBind
+	ViperStateFile       = ViperPrefix + "." + FlagStateFile
+	ViperControlAddr     = ViperPrefix + "." + FlagControlAddr
+
+	ViperSecurity             = ViperPrefix + "." + server.KeySecurity
+	ViperTLSCertFile          = ViperSecurity + ".tlsCertFile"
+	ViperTLSKeyFile           = ViperSecurity + ".tlsKeyFile"
+	ViperTLSCAFile            = ViperSecurity + ".tlsCAFile"
+	ViperTokenFile            = ViperSecurity + ".tokenFile"
+	ViperAllowUnauthenticated = ViperSecurity + ".allowUnauthenticated"
 )
 
 // NewFromViper creates an EmulatorConfig from a viper instance
@@ -48,6 +133,106 @@ func NewFromViper(v *viper.Viper) *EmulatorConfig {
 	if v.IsSet(ViperVirtualPort) {
 		cfg.VirtualPort = v.GetString(ViperVirtualPort)
 	}
+	if v.IsSet(ViperListen) {
+		cfg.Listen = v.GetString(ViperListen)
+	}
+	if v.IsSet(ViperProtocol) {
+		cfg.Protocol = v.GetString(ViperProtocol)
+	}
+	if v.IsSet(ViperExtraPorts) {
+		cfg.ExtraPorts = v.GetStringSlice(ViperExtraPorts)
+	}
+	if v.IsSet(ViperEngine) {
+		cfg.Engine = v.GetString(ViperEngine)
+	}
+	if v.IsSet(ViperPersonality) {
+		cfg.Personality = v.GetString(ViperPersonality)
+	}
+	if v.IsSet(ViperExec) {
+		cfg.Exec = v.GetString(ViperExec)
+	}
+	if v.IsSet(ViperSeed) {
+		cfg.Seed = v.GetInt64(ViperSeed)
+	}
+	if v.IsSet(ViperHealthAddr) {
+		cfg.HealthAddr = v.GetString(ViperHealthAddr)
+	}
+	if v.IsSet(ViperStateFile) {
+		cfg.StateFile = v.GetString(ViperStateFile)
+	}
+	if v.IsSet(ViperControlAddr) {
+		cfg.ControlAddr = v.GetString(ViperControlAddr)
+	}
+
+	if v.IsSet(ViperBind) {
+		cfg.Bind = v.GetString(ViperBind)
+	}
+	if v.IsSet(ViperTLSCertFile) {
+		cfg.Security.CertFile = v.GetString(ViperTLSCertFile)
+	}
+	if v.IsSet(ViperTLSKeyFile) {
+		cfg.Security.KeyFile = v.GetString(ViperTLSKeyFile)
+	}
+	if v.IsSet(ViperTLSCAFile) {
+		cfg.Security.CAFile = v.GetString(ViperTLSCAFile)
+	}
+	if v.IsSet(ViperTokenFile) {
+		cfg.Security.TokenFile = v.GetString(ViperTokenFile)
+	}
+	if v.IsSet(ViperAllowUnauthenticated) {
+		cfg.Security.AllowUnauthenticated = v.GetBool(ViperAllowUnauthenticated)
+	}
+	if v.IsSet(ViperPrefix + ".scenario") {
+		if err := v.UnmarshalKey(ViperPrefix+".scenario", &cfg.Scenario); err != nil {
+			// If unmarshaling fails, return an empty scenario
+			cfg.Scenario = []ScenarioEvent{}
+		}
+	}
+	if v.IsSet(ViperPassthrough) {
+		cfg.Passthrough = &PassthroughConfig{}
+		if err := v.UnmarshalKey(ViperPassthrough, cfg.Passthrough); err != nil {
+			// If unmarshaling fails, disable the passthrough port
+			cfg.Passthrough = nil
+		}
+	}
+	if v.IsSet(ViperPassthroughPort) && v.GetString(ViperPassthroughPort) != "" {
+		if cfg.Passthrough == nil {
+			cfg.Passthrough = &PassthroughConfig{}
+		}
+		cfg.Passthrough.VirtualPort = v.GetString(ViperPassthroughPort)
+	}
+	if v.IsSet(ViperPrefix + ".faults") {
+		cfg.Faults = &FaultConfig{}
+		if err := v.UnmarshalKey(ViperPrefix+".faults", cfg.Faults); err != nil {
+			// If unmarshaling fails, disable fault injection
+			cfg.Faults = nil
+		}
+	}
+	if v.IsSet(ViperPrefix + ".failures") {
+		if err := v.UnmarshalKey(ViperPrefix+".failures", &cfg.Failures); err != nil {
+			// If unmarshaling fails, disable failure injection
+			cfg.Failures = []FailureRule{}
+		}
+	}
+	if v.IsSet(ViperPrefix + ".control") {
+		if err := v.UnmarshalKey(ViperPrefix+".control", &cfg.Control); err != nil {
+			// If unmarshaling fails, ignore port events
+			cfg.Control = []ControlRule{}
+		}
+	}
+	if v.IsSet(ViperPrefix + ".hardware") {
+		cfg.Hardware = &HardwareConfig{}
+		if err := v.UnmarshalKey(ViperPrefix+".hardware", cfg.Hardware); err != nil {
+			// If unmarshaling fails, start without initial hardware state
+			cfg.Hardware = nil
+		}
+	}
+	if v.IsSet(ViperPrefix + ".recordings") {
+		if err := v.UnmarshalKey(ViperPrefix+".recordings", &cfg.Recordings); err != nil {
+			// If unmarshaling fails, return an empty list of recordings
+			cfg.Recordings = []RecordingMetadata{}
+		}
+	}
 	if v.IsSet(ViperPrefix + ".mappings") {
 		if err := v.UnmarshalKey(ViperPrefix+".mappings", &cfg.Mappings); err != nil {
 			// If unmarshaling fails, return an empty list of mappings
@@ -63,6 +248,16 @@ func NewDefaultConfig() *EmulatorConfig {
 	return &EmulatorConfig{
 		BufferSize:  DefaultBufferSize,
 		VirtualPort: "",
+		Listen:      "",
+		Protocol:    ProtocolRaw,
+		Bind:        server.DefaultBind,
+		ExtraPorts:  []string{},
+		Engine:      "",
+		Exec:        "",
+		Scenario:    []ScenarioEvent{},
+		Failures:    []FailureRule{},
+		Control:     []ControlRule{},
+		Recordings:  []RecordingMetadata{},
 		Mappings:    []RequestResponse{},
 	}
 }
@@ -72,10 +267,265 @@ type EmulatorConfig struct {
 	BufferSize  int    `json:"bufferSize"  mapstructure:"buffer-size"  yaml:"bufferSize"`
 	VirtualPort string `json:"virtualPort" mapstructure:"virtual-port" yaml:"virtualPort"`
 
+	// Listen is a TCP address to serve the emulated device on instead of a virtual serial port
+	Listen string `json:"listen" mapstructure:"listen" yaml:"listen"`
+
+	// Protocol is the protocol served on the listen address, ProtocolRaw for a raw byte stream or ProtocolRFC2217
+	// for RFC2217 clients, which can change the DTR and RTS lines and send breaks
+	Protocol string `json:"protocol,omitempty" mapstructure:"listen-protocol" yaml:"protocol,omitempty"`
+
+	// ExtraPorts are additional virtual ports backed by the same emulated device, so several clients can be attached
+	ExtraPorts []string `json:"extraPorts,omitempty" mapstructure:"extra-ports" yaml:"extraPorts,omitempty"`
+
+	// Engine is the name of the state engine that tracks device state and answers requests without a mapping
+	Engine string `json:"engine" mapstructure:"engine" yaml:"engine"`
+
+	// Personality loads a built-in device config into the engine when the emulator starts, e.g.
+	// PersonalityFactory. Config writes change the loaded config and persist across resets.
+	Personality string `json:"personality,omitempty" mapstructure:"personality" yaml:"personality,omitempty"`
+
+	// Exec is a client command to run against the virtual port, the emulator stops when it exits
+	Exec string `json:"exec" mapstructure:"exec" yaml:"exec"`
+
+	// Seed seeds the random source of response jitter, random response selection and injected faults
+	// so runs are reproducible, zero picks a random seed
+	Seed int64 `json:"seed,omitempty" mapstructure:"seed" yaml:"seed,omitempty"`
+
+	// HealthAddr is a TCP address to serve the /healthz and /readyz probes on, disabled if empty
+	HealthAddr string `json:"healthAddr,omitempty" mapstructure:"health-addr" yaml:"healthAddr,omitempty"`
+
+	// Bind is the host network listeners without a host are bound to, listening on other interfaces than
+	// the loopback interface requires changing it
+	Bind string `json:"bind,omitempty" mapstructure:"bind" yaml:"bind,omitempty"`
+
+	// Security authenticates the clients of the listen address and the control API, which are only served on
+	// non-loopback addresses if clients have to authenticate. Bearer tokens are only supported by the control API.
+	Security transport.Config `json:"security,omitzero" mapstructure:"security" yaml:"security,omitempty"`
+
+	// StateFile is a file the DAC voltages, GPIO levels, connections and config of the engine are saved to while
+	// the emulator runs and restored from when it starts, so the state survives restarts. Disabled if empty.
+	StateFile string `json:"stateFile,omitempty" mapstructure:"state-file" yaml:"stateFile,omitempty"`
+
+	// ControlAddr is a TCP address to serve the control API on, which changes the emulated hardware while the
+	// emulator runs, e.g. to set ADC voltages from a test. Disabled if empty.
+	ControlAddr string `json:"controlAddr,omitempty" mapstructure:"control-addr" yaml:"controlAddr,omitempty"`
+
+	// Scenario is a list of timed events changing the engine state while the emulator runs
+	Scenario []ScenarioEvent `json:"scenario,omitempty" mapstructure:"scenario" yaml:"scenario,omitempty"`
+
+	// Passthrough configures a second virtual port emulating the UART passthrough interface of the device
+	Passthrough *PassthroughConfig `json:"passthrough,omitempty" mapstructure:"passthrough" yaml:"passthrough,omitempty"`
+
+	// Faults configures faults injected into the responses of the control port
+	Faults *FaultConfig `json:"faults,omitempty" mapstructure:"faults" yaml:"faults,omitempty"`
+
+	// Failures makes matching requests fail after a number of successes, e.g. to emulate failing writes
+	Failures []FailureRule `json:"failures,omitempty" mapstructure:"failures" yaml:"failures,omitempty"`
+
+	// Control handles port events such as DTR toggles and breaks sent by clients of an RFC2217 listener,
+	// or baud rate changes of a virtual serial port
+	Control []ControlRule `json:"control,omitempty" mapstructure:"control" yaml:"control,omitempty"`
+
+	// Hardware is the initial DAC and connection state of the emulated device, applied to the engine when the
+	// emulator starts and used to generate a matching Jumperless manifest
+	Hardware *HardwareConfig `json:"hardware,omitempty" mapstructure:"hardware" yaml:"hardware,omitempty"`
+
+	// Recordings describes the environment of each proxy session that recorded the mappings
+	Recordings []RecordingMetadata `json:"recordings,omitempty" mapstructure:"recordings" yaml:"recordings,omitempty"`
+
 	// Request/response mappings
 	Mappings Mappings `json:"mappings" mapstructure:"mappings" yaml:"mappings"`
 }
 
+// RecordingMetadata describes the environment a recording was made in, so fixtures can be traced back to it.
+// Recordings are written with the yaml keys, so the mapstructure keys match them to read them back unchanged.
+type RecordingMetadata struct {
+	// RecordedAt is the time the recording was saved, in RFC3339 format
+	RecordedAt string `json:"recordedAt" mapstructure:"recordedAt" yaml:"recordedAt"`
+
+	// OS is the operating system and architecture of the host, e.g. "linux/amd64 (Ubuntu 24.04.1 LTS)"
+	OS string `json:"os" mapstructure:"os" yaml:"os"`
+
+	// Kernel is the kernel release of the host
+	Kernel string `json:"kernel,omitempty" mapstructure:"kernel" yaml:"kernel,omitempty"`
+
+	// SerialDriver is the kernel driver bound to the real serial port, e.g. "cdc_acm"
+	SerialDriver string `json:"serialDriver,omitempty" mapstructure:"serialDriver" yaml:"serialDriver,omitempty"`
+
+	// Port is the real serial port the recording was made on
+	Port string `json:"port" mapstructure:"port" yaml:"port"`
+
+	// Device contains the USB descriptors of the device, if it is a USB device
+	Device *USBDescriptors `json:"device,omitempty" mapstructure:"device" yaml:"device,omitempty"`
+
+	// UtilsVersion is the version of jumperless-utils that made the recording
+	UtilsVersion string `json:"utilsVersion" mapstructure:"utilsVersion" yaml:"utilsVersion"`
+
+	// ConfigHash is the SHA-256 hash of the config file the recording was made with, before it was updated
+	ConfigHash string `json:"configHash,omitempty" mapstructure:"configHash" yaml:"configHash,omitempty"`
+
+	// Events are the port state changes requested by the client during the recording
+	Events []PortEvent `json:"events,omitempty" mapstructure:"events" yaml:"events,omitempty"`
+}
+
+// PortEvent is a change of the port state, such as the baud rate or the DTR line, requested by a client
+type PortEvent struct {
+	// Offset is the time since the recording started
+	Offset time.Duration `json:"offset" mapstructure:"offset" yaml:"offset"`
+
+	// Type is one of EventBaudRate, EventDTR, EventRTS or EventBreak
+	Type string `json:"type" mapstructure:"type" yaml:"type"`
+
+	// Value is the baud rate, EventOn or EventOff for the DTR and RTS lines and the break state,
+	// or empty for a momentary break
+	Value string `json:"value,omitempty" mapstructure:"value" yaml:"value,omitempty"`
+}
+
+// USBDescriptors are the USB descriptors of a serial device
+type USBDescriptors struct {
+	VID          string `json:"vid"                    mapstructure:"vid"          yaml:"vid"`
+	PID          string `json:"pid"                    mapstructure:"pid"          yaml:"pid"`
+	SerialNumber string `json:"serialNumber,omitempty" mapstructure:"serialNumber" yaml:"serialNumber,omitempty"`
+	Product      string `json:"product,omitempty"      mapstructure:"product"      yaml:"product,omitempty"`
+}
+
+// HardwareConfig describes the DACs and connections of the emulated device
+type HardwareConfig struct {
+	// DACs are the initial DAC voltages
+	DACs []HardwareDAC `json:"dacs,omitempty" mapstructure:"dacs" yaml:"dacs,omitempty"`
+
+	// Connections are the node pairs connected initially
+	Connections []HardwareConnection `json:"connections,omitempty" mapstructure:"connections" yaml:"connections,omitempty"`
+
+	// I2CDevices are the 7-bit addresses of the devices found by I2C scans, e.g. "0x3C"
+	I2CDevices []string `json:"i2cDevices,omitempty" mapstructure:"i2cDevices" yaml:"i2cDevices,omitempty"`
+}
+
+// HardwareDAC is the voltage of a DAC channel
+type HardwareDAC struct {
+	// Channel is one of DAC0, DAC1, TOP_RAIL or BOTTOM_RAIL
+	Channel string `json:"channel" mapstructure:"channel" yaml:"channel"`
+
+	// Voltage is the voltage of the channel, e.g. "3.3V"
+	Voltage string `json:"voltage" mapstructure:"voltage" yaml:"voltage"`
+}
+
+// HardwareConnection is a pair of connected nodes, e.g. D2 and GPIO_1
+type HardwareConnection struct {
+	From string `json:"from" mapstructure:"from" yaml:"from"`
+	To   string `json:"to"   mapstructure:"to"   yaml:"to"`
+}
+
+// FaultConfig configures fault injection, rates are probabilities between 0 and 1
+type FaultConfig struct {
+	// Seed seeds the random source of injected faults separately, zero uses the emulator seed
+	Seed int64 `json:"seed" mapstructure:"seed" yaml:"seed"`
+
+	// DropRate is the probability each response byte is dropped
+	DropRate float64 `json:"dropRate" mapstructure:"drop-rate" yaml:"dropRate"`
+
+	// CorruptRate is the probability each response byte is replaced with a random byte
+	CorruptRate float64 `json:"corruptRate" mapstructure:"corrupt-rate" yaml:"corruptRate"`
+
+	// NoiseRate is the probability a random ANSI escape sequence is inserted before a response chunk
+	NoiseRate float64 `json:"noiseRate" mapstructure:"noise-rate" yaml:"noiseRate"`
+
+	// StallRate is the probability a response chunk is delayed by StallDuration
+	StallRate float64 `json:"stallRate" mapstructure:"stall-rate" yaml:"stallRate"`
+
+	// StallDuration is the delay of a stalled response chunk
+	StallDuration time.Duration `json:"stallDuration" mapstructure:"stall-duration" yaml:"stallDuration"`
+
+	// DisconnectRate is the probability the client is disconnected after a response chunk,
+	// a virtual serial port is closed and recreated as if the device was unplugged and reattached
+	DisconnectRate float64 `json:"disconnectRate" mapstructure:"disconnect-rate" yaml:"disconnectRate"`
+}
+
+// PassthroughConfig configures the UART passthrough port of the emulator
+type PassthroughConfig struct {
+	VirtualPort string `json:"virtualPort" mapstructure:"virtual-port" yaml:"virtualPort"`
+
+	// Listen is a TCP address to serve the passthrough port on instead of a virtual serial port
+	Listen string `json:"listen" mapstructure:"listen" yaml:"listen"`
+
+	// Loopback echoes data written to the passthrough port back to it, as if UART TX were wired to RX
+	Loopback bool `json:"loopback" mapstructure:"loopback" yaml:"loopback"`
+
+	// PrintToControl copies data written to the passthrough port to the control port, like the
+	// print_passthrough setting of the device
+	PrintToControl bool `json:"printToControl" mapstructure:"print-to-control" yaml:"printToControl"`
+
+	// Mappings answer requests written to the passthrough port, emulating the attached UART device
+	Mappings Mappings `json:"mappings" mapstructure:"mappings" yaml:"mappings"`
+}
+
+// FailureRule makes the requests matching a pattern fail with an error response after a number of successes
+type FailureRule struct {
+	// Request is a regular expression matched anywhere in the request, e.g. "dac_set" for all DAC writes
+	Request string `json:"request" mapstructure:"request" yaml:"request"`
+
+	// After is the number of matching requests that succeed before requests start failing
+	After int `json:"after,omitempty" mapstructure:"after" yaml:"after,omitempty"`
+
+	// Count is the number of matching requests that fail before requests succeed again, zero keeps failing
+	Count int `json:"count,omitempty" mapstructure:"count" yaml:"count,omitempty"`
+
+	// Response is the error response, rendered as a template with the request available as .Request.
+	// Defaults to the MicroPython traceback of an I/O error.
+	Response string `json:"response,omitempty" mapstructure:"response" yaml:"response,omitempty"`
+}
+
+// ControlRule acts on the port events matching its event type and value
+type ControlRule struct {
+	// Event is the event type, one of EventBaudRate, EventDTR, EventRTS or EventBreak
+	Event string `json:"event" mapstructure:"event" yaml:"event"`
+
+	// Value is the event value to match, e.g. "off" for DTR or "1200" for the baud rate, empty matches any value
+	Value string `json:"value,omitempty" mapstructure:"value" yaml:"value,omitempty"`
+
+	// Action is ActionReset to reset the engine state, response sequences and failure counters as if the
+	// device rebooted, ActionDump to send the hardware state in the config dump format of the device, or empty
+	// to only send the response
+	Action string `json:"action,omitempty" mapstructure:"action" yaml:"action,omitempty"`
+
+	// Response is sent to the client after the action, quoted like recorded response chunks
+	Response string `json:"response,omitempty" mapstructure:"response" yaml:"response,omitempty"`
+}
+
+// ScenarioEvent changes a value of the engine state at a time relative to the emulator start
+type ScenarioEvent struct {
+	// At is the time after the emulator starts that the event begins
+	At time.Duration `json:"at" mapstructure:"at" yaml:"at"`
+
+	// Action is ActionSet to set Value, ActionRamp to change a voltage linearly from Value to To over
+	// Duration, ActionToggle to alternate between Value and To every Interval, or ActionWave to generate a
+	// Waveform between the voltages Value and To with a period of Interval
+	Action string `json:"action" mapstructure:"action" yaml:"action"`
+
+	// Key is the engine state key to change, e.g. adc2 or gpio3
+	Key string `json:"key" mapstructure:"key" yaml:"key"`
+
+	// Value is the value to set, the starting value of a ramp or toggle, or the lowest voltage of a wave
+	Value string `json:"value" mapstructure:"value" yaml:"value"`
+
+	// To is the final value of a ramp, the alternate value of a toggle, or the highest voltage of a wave
+	To string `json:"to,omitempty" mapstructure:"to" yaml:"to,omitempty"`
+
+	// Duration is the time a ramp takes to reach To, or the time a wave is generated for, zero generates a
+	// wave until the emulator stops
+	Duration time.Duration `json:"duration,omitempty" mapstructure:"duration" yaml:"duration,omitempty"`
+
+	// Interval is the time between changes of a toggle, e.g. 500ms toggles at 1Hz, or the period of a wave
+	Interval time.Duration `json:"interval,omitempty" mapstructure:"interval" yaml:"interval,omitempty"`
+
+	// Waveform is the shape of a wave, WaveformSine, WaveformSquare, WaveformTriangle or WaveformSawtooth.
+	// Defaults to WaveformSine.
+	Waveform string `json:"waveform,omitempty" mapstructure:"waveform" yaml:"waveform,omitempty"`
+
+	// Count limits the number of changes of a toggle, zero toggles until the emulator stops
+	Count int `json:"count,omitempty" mapstructure:"count" yaml:"count,omitempty"`
+}
+
 type Mappings []RequestResponse
 
 func (m *Mappings) Get(request string) (*RequestResponse, bool) {
@@ -119,6 +569,16 @@ type RequestResponse struct {
 	// Request
 	Request string `json:"request" mapstructure:"request" yaml:"request"`
 
+	// Match selects how Request is matched, MatchExact (the default) or MatchRegex
+	Match string `json:"match,omitempty" mapstructure:"match" yaml:"match,omitempty"`
+
+	// Template renders response chunks as Go templates with the request, regex groups and engine state
+	Template bool `json:"template,omitempty" mapstructure:"template" yaml:"template,omitempty"`
+
+	// Select chooses between several responses, SelectSequential (the default) cycles through them in
+	// order and SelectRandom picks one at random for every request
+	Select string `json:"select,omitempty" mapstructure:"select" yaml:"select,omitempty"`
+
 	// Multiple responses with ordering
 	Responses []ResponseOption `json:"responses" mapstructure:"responses" yaml:"responses"`
 }
@@ -0,0 +1,156 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package emulator
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/detiber/k8s-jumperless/utils/internal/emulator/config"
+	"github.com/detiber/k8s-jumperless/utils/internal/logging"
+)
+
+var ErrInvalidControlRule = errors.New("invalid control rule")
+
+// termiosPollInterval is the interval the line settings of virtual serial ports are polled at
+const termiosPollInterval = 100 * time.Millisecond
+
+// validateControlRules checks the event types and actions of the control rules
+func validateControlRules(rules []config.ControlRule) error {
+	for _, rule := range rules {
+		switch rule.Event {
+		case config.EventBaudRate, config.EventDTR, config.EventRTS, config.EventBreak:
+		default:
+			return fmt.Errorf("%w: unknown event %q", ErrInvalidControlRule, rule.Event)
+		}
+
+		switch rule.Action {
+		case "", config.ActionReset, config.ActionDump:
+		default:
+			return fmt.Errorf("%w: %s: unknown action %q", ErrInvalidControlRule, rule.Event, rule.Action)
+		}
+	}
+
+	return nil
+}
+
+// handleEvent applies the control rules matching a port event requested by the client, writing their
+// responses to w. Events are handled between requests, like requests from other clients.
+func (e *Emulator) handleEvent(w io.Writer, eventType, value string) {
+	e.logger.Info("Port event", "event", eventType, "value", value)
+
+	e.requestLock.Lock()
+	defer e.requestLock.Unlock()
+
+	for _, rule := range e.config.Control {
+		if rule.Event != eventType || (rule.Value != "" && rule.Value != value) {
+			continue
+		}
+
+		switch rule.Action {
+		case config.ActionReset:
+			if err := e.reset(); err != nil {
+				e.logger.Error("Error resetting emulated device", logging.Err(err))
+			}
+		case config.ActionDump:
+			e.engineLock.Lock()
+			dump, err := dumpState(e.engine)
+			e.engineLock.Unlock()
+			if err != nil {
+				e.logger.Error("Error dumping emulated device state", logging.Err(err))
+				break
+			}
+
+			if err := e.writeChunk(w, dump); err != nil && !errors.Is(err, ErrInjectedDisconnect) {
+				e.logger.Error("Error sending emulated device state", logging.Err(err))
+			}
+		}
+
+		if rule.Response == "" {
+			continue
+		}
+
+		response := rule.Response
+		if unquoted, err := strconv.Unquote(response); err == nil {
+			// Like response chunks, responses may be quoted to preserve control characters
+			response = unquoted
+		}
+
+		if err := e.writeChunk(w, response); err != nil && !errors.Is(err, ErrInjectedDisconnect) {
+			e.logger.Error("Error sending port event response", logging.Err(err))
+		}
+	}
+}
+
+// reset restores the engine state, response sequences and failure counters the emulator started with, as if
+// the device rebooted. Like on the device, the config and the DAC voltages saved to it persist.
+// Must be called with requestLock held.
+func (e *Emulator) reset() error {
+	engine, err := NewEngine(e.config.Engine)
+	if err != nil {
+		return err
+	}
+
+	if err := applyPersonality(e.config.Personality, engine); err != nil {
+		return err
+	}
+
+	e.engineLock.Lock()
+	previous := e.engine
+	e.engineLock.Unlock()
+
+	if err := persistConfig(previous, engine); err != nil {
+		return err
+	}
+
+	if err := applyHardware(e.config.Hardware, engine); err != nil {
+		return err
+	}
+
+	failures, err := newFailureRules(e.config.Failures)
+	if err != nil {
+		return err
+	}
+
+	e.engineLock.Lock()
+	e.engine = engine
+	e.engineLock.Unlock()
+
+	clear(e.requestCounters)
+	e.failures = failures
+
+	e.logger.Info("Reset emulated device")
+
+	return nil
+}
+
+// masterTTY returns the pseudo TTY side of the port, which changes when the port is recreated, or nil if the
+// port isn't a pty
+func (p *ptyPort) masterTTY() *os.File {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+
+	if p.virtual == nil {
+		return nil
+	}
+
+	return p.virtual.TTY()
+}
@@ -0,0 +1,125 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package emulator
+
+import (
+	"log/slog"
+	"path/filepath"
+	"strconv"
+	"testing"
+	"time"
+
+	. "github.com/onsi/gomega"
+	"go.bug.st/serial"
+
+	"github.com/detiber/k8s-jumperless/utils/internal/emulator/config"
+)
+
+func TestControlResetOnBaudRate(t *testing.T) {
+	g := NewWithT(t)
+
+	mappings := config.Mappings{}
+	mappings.AddResponse("count", config.ResponseOption{Chunks: []config.ResponseChunk{{Data: strconv.Quote("1\r\n")}}})
+	mappings.AddResponse("count", config.ResponseOption{Chunks: []config.ResponseChunk{{Data: strconv.Quote("2\r\n")}}})
+
+	c := config.NewDefaultConfig()
+	c.VirtualPort = filepath.Join(t.TempDir(), "jumperless")
+	c.Mappings = mappings
+	c.Control = []config.ControlRule{
+		{Event: config.EventBaudRate, Value: "1200", Action: config.ActionReset, Response: strconv.Quote("reset\r\n")},
+	}
+
+	e, err := New(c, slog.New(slog.NewTextHandler(t.Output(), nil)))
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(e.Start(t.Context())).To(Succeed())
+	t.Cleanup(func() { g.Expect(e.Stop()).To(Succeed()) })
+
+	port, err := serial.Open(e.GetPortName(), &serial.Mode{BaudRate: 115200})
+	g.Expect(err).NotTo(HaveOccurred())
+	t.Cleanup(func() { _ = port.Close() })
+	g.Expect(port.SetReadTimeout(100 * time.Millisecond)).To(Succeed())
+
+	received := ""
+	read := func() string {
+		buffer := make([]byte, 64)
+		n, err := port.Read(buffer)
+		g.Expect(err).NotTo(HaveOccurred())
+		received += string(buffer[:n])
+		return received
+	}
+	exchange := func(request, response string) {
+		received = ""
+		_, err := port.Write([]byte(request + "\r\n"))
+		g.Expect(err).NotTo(HaveOccurred())
+		g.Eventually(read).Should(Equal(response))
+	}
+
+	exchange("count", "1\r\n")
+
+	// Switching to 1200 baud resets the sequence of responses, like an Arduino style bootloader touch
+	received = ""
+	g.Expect(port.SetMode(&serial.Mode{BaudRate: 1200})).To(Succeed())
+	g.Eventually(read).Should(Equal("reset\r\n"))
+
+	exchange("count", "1\r\n")
+	exchange("count", "2\r\n")
+}
+
+func TestValidateControlRules(t *testing.T) {
+	tests := []struct {
+		name  string
+		rules []config.ControlRule
+		valid bool
+	}{
+		{
+			name:  "reset on DTR off",
+			rules: []config.ControlRule{{Event: config.EventDTR, Value: config.EventOff, Action: config.ActionReset}},
+			valid: true,
+		},
+		{
+			name:  "response on any break",
+			rules: []config.ControlRule{{Event: config.EventBreak, Response: "\"\\r\\n>>> \""}},
+			valid: true,
+		},
+		{
+			name:  "dump on break",
+			rules: []config.ControlRule{{Event: config.EventBreak, Action: config.ActionDump}},
+			valid: true,
+		},
+		{
+			name:  "unknown event",
+			rules: []config.ControlRule{{Event: "cts"}},
+		},
+		{
+			name:  "unknown action",
+			rules: []config.ControlRule{{Event: config.EventRTS, Action: "reboot"}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			err := validateControlRules(tt.rules)
+			if tt.valid {
+				g.Expect(err).NotTo(HaveOccurred())
+			} else {
+				g.Expect(err).To(MatchError(ErrInvalidControlRule))
+			}
+		})
+	}
+}
@@ -0,0 +1,243 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package emulator
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"slices"
+
+	"github.com/detiber/k8s-jumperless/utils/internal/emulator/config"
+	"github.com/detiber/k8s-jumperless/utils/internal/server"
+)
+
+var ErrStateNotSettable = errors.New("the engine doesn't support setting state")
+
+// maxControlBody limits the size of control API requests, mapping sets are the largest
+const maxControlBody = 4 << 20
+
+// controlError is the body of failed control API requests
+type controlError struct {
+	Error string `json:"error"`
+}
+
+// stateValue is the body of control API requests setting a state value
+type stateValue struct {
+	Value string `json:"value"`
+}
+
+// adcValue is the body of control API requests setting an ADC voltage, e.g. "1.65V"
+type adcValue struct {
+	Voltage string `json:"voltage"`
+}
+
+// gpioValue is the body of control API requests setting a GPIO level
+type gpioValue struct {
+	High bool `json:"high"`
+}
+
+// SetState changes a value of the engine state, using the keys and formats of the state, e.g. "adc0" and
+// "1.65V". It fails if the engine doesn't support setting state.
+func (e *Emulator) SetState(key, value string) error {
+	e.engineLock.Lock()
+	defer e.engineLock.Unlock()
+
+	setter, ok := e.engine.(StateSetter)
+	if !ok {
+		return ErrStateNotSettable
+	}
+
+	if err := setter.SetState(key, value); err != nil {
+		return err //nolint:wrapcheck
+	}
+
+	e.logger.Info("State changed", "key", key, "value", value)
+
+	return nil
+}
+
+// InjectDisconnect disconnects the clients after the next response chunk sent to them, like the disconnect
+// fault. Virtual serial ports are recreated and TCP connections closed.
+func (e *Emulator) InjectDisconnect() {
+	e.disconnect.Store(true)
+	e.logger.Info("Disconnect injected, disconnecting after the next response")
+}
+
+// Reset restores the state the emulator started with, as if the device rebooted
+func (e *Emulator) Reset() error {
+	e.requestLock.Lock()
+	defer e.requestLock.Unlock()
+
+	return e.reset()
+}
+
+// Mappings returns the mappings the emulator currently answers requests with
+func (e *Emulator) Mappings() config.Mappings {
+	e.requestLock.Lock()
+	defer e.requestLock.Unlock()
+
+	return slices.Clone(e.config.Mappings)
+}
+
+// SetMappings replaces the mappings the emulator answers requests with. The sequences of the responses start
+// over, as they would for a new emulator.
+func (e *Emulator) SetMappings(mappings config.Mappings) error {
+	patterns, err := compilePatterns(mappings)
+	if err != nil {
+		return err
+	}
+
+	e.requestLock.Lock()
+	defer e.requestLock.Unlock()
+
+	e.config.Mappings = mappings
+	e.patterns = patterns
+	clear(e.requestCounters)
+
+	e.logger.Info("Mappings replaced", "mappings", len(mappings))
+
+	return nil
+}
+
+// ServeControl serves the control API on addr until ctx is done. It is served over HTTPS if a certificate is
+// configured, and only to requests with the bearer token if a token file is configured.
+func (e *Emulator) ServeControl(ctx context.Context, addr string) error {
+	return server.ServeSecured(ctx, addr, "control API", e.ControlHandler(), e.creds, e.logger) //nolint:wrapcheck
+}
+
+// ControlHandler returns the HTTP control API, which changes the emulated hardware while clients use it:
+//
+//	GET  /state          the engine state
+//	PUT  /state/{key}    sets a state value, {"value": "1.65V"}
+//	PUT  /adc/{channel}  sets the voltage of an ADC channel, {"voltage": "1.65V"}
+//	PUT  /gpio/{pin}     sets the level of a GPIO pin, {"high": true}
+//	POST /disconnect     disconnects the clients after the next response
+//	POST /reset          resets the emulated device
+//	GET  /mappings       the mappings requests are answered with
+//	PUT  /mappings       replaces the mappings, with a list of mappings like in the config
+func (e *Emulator) ControlHandler() http.Handler {
+	mux := http.NewServeMux()
+
+	mux.HandleFunc("GET /state", func(w http.ResponseWriter, _ *http.Request) {
+		state := e.GetState()
+		if state == nil {
+			state = map[string]string{}
+		}
+
+		writeControlResponse(w, http.StatusOK, state)
+	})
+
+	mux.HandleFunc("PUT /state/{key}", func(w http.ResponseWriter, r *http.Request) {
+		body := stateValue{}
+		if !readControlRequest(w, r, &body) {
+			return
+		}
+
+		e.setControlState(w, r.PathValue("key"), body.Value)
+	})
+
+	mux.HandleFunc("PUT /adc/{channel}", func(w http.ResponseWriter, r *http.Request) {
+		body := adcValue{}
+		if !readControlRequest(w, r, &body) {
+			return
+		}
+
+		e.setControlState(w, "adc"+r.PathValue("channel"), body.Voltage)
+	})
+
+	mux.HandleFunc("PUT /gpio/{pin}", func(w http.ResponseWriter, r *http.Request) {
+		body := gpioValue{}
+		if !readControlRequest(w, r, &body) {
+			return
+		}
+
+		e.setControlState(w, "gpio"+r.PathValue("pin"), pythonBool(body.High))
+	})
+
+	mux.HandleFunc("POST /disconnect", func(w http.ResponseWriter, _ *http.Request) {
+		e.InjectDisconnect()
+		w.WriteHeader(http.StatusNoContent)
+	})
+
+	mux.HandleFunc("POST /reset", func(w http.ResponseWriter, _ *http.Request) {
+		if err := e.Reset(); err != nil {
+			writeControlResponse(w, http.StatusInternalServerError, controlError{Error: err.Error()})
+			return
+		}
+
+		w.WriteHeader(http.StatusNoContent)
+	})
+
+	mux.HandleFunc("GET /mappings", func(w http.ResponseWriter, _ *http.Request) {
+		writeControlResponse(w, http.StatusOK, e.Mappings())
+	})
+
+	mux.HandleFunc("PUT /mappings", func(w http.ResponseWriter, r *http.Request) {
+		mappings := config.Mappings{}
+		if !readControlRequest(w, r, &mappings) {
+			return
+		}
+
+		if err := e.SetMappings(mappings); err != nil {
+			writeControlResponse(w, http.StatusBadRequest, controlError{Error: err.Error()})
+			return
+		}
+
+		w.WriteHeader(http.StatusNoContent)
+	})
+
+	return mux
+}
+
+// setControlState sets a state value for the control API, answering with the new state
+func (e *Emulator) setControlState(w http.ResponseWriter, key, value string) {
+	err := e.SetState(key, value)
+
+	switch {
+	case err == nil:
+		writeControlResponse(w, http.StatusOK, e.GetState())
+	case errors.Is(err, ErrStateNotSettable):
+		writeControlResponse(w, http.StatusNotImplemented, controlError{Error: err.Error()})
+	case errors.Is(err, ErrUnknownStateKey):
+		writeControlResponse(w, http.StatusNotFound, controlError{Error: err.Error()})
+	default:
+		writeControlResponse(w, http.StatusBadRequest, controlError{Error: err.Error()})
+	}
+}
+
+// readControlRequest decodes the JSON body of a control API request, answering with an error if it is invalid
+func readControlRequest(w http.ResponseWriter, r *http.Request, body any) bool {
+	decoder := json.NewDecoder(http.MaxBytesReader(w, r.Body, maxControlBody))
+	decoder.DisallowUnknownFields()
+
+	if err := decoder.Decode(body); err != nil {
+		writeControlResponse(w, http.StatusBadRequest, controlError{Error: "invalid request body: " + err.Error()})
+
+		return false
+	}
+
+	return true
+}
+
+func writeControlResponse(w http.ResponseWriter, code int, body any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(code)
+
+	_ = json.NewEncoder(w).Encode(body)
+}
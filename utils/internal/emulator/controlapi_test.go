@@ -0,0 +1,137 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package emulator
+
+import (
+	"encoding/json"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	. "github.com/onsi/gomega"
+
+	"github.com/detiber/k8s-jumperless/internal/controller/local"
+	"github.com/detiber/k8s-jumperless/jumperless"
+	"github.com/detiber/k8s-jumperless/utils/internal/emulator/config"
+)
+
+func TestControlAPI(t *testing.T) {
+	g := NewWithT(t)
+
+	c := config.NewDefaultConfig()
+	c.VirtualPort = filepath.Join(t.TempDir(), "jumperless")
+	c.Engine = EngineJumperless
+	c.Personality = config.PersonalityFactory
+	c.Seed = 1
+
+	e, err := New(c, slog.New(slog.NewTextHandler(t.Output(), nil)))
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(e.Start(t.Context())).To(Succeed())
+	t.Cleanup(func() { _ = e.Stop() })
+
+	control := httptest.NewServer(e.ControlHandler())
+	t.Cleanup(control.Close)
+
+	call := func(method, path, body string) (int, string) {
+		req, err := http.NewRequestWithContext(t.Context(), method, control.URL+path, strings.NewReader(body))
+		g.Expect(err).NotTo(HaveOccurred())
+
+		resp, err := control.Client().Do(req)
+		g.Expect(err).NotTo(HaveOccurred())
+		defer func() { _ = resp.Body.Close() }()
+
+		response, err := io.ReadAll(resp.Body)
+		g.Expect(err).NotTo(HaveOccurred())
+
+		return resp.StatusCode, string(response)
+	}
+
+	j, err := jumperless.NewJumperless(t.Context(), e.GetPortName(), 0)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(j.OpenPort()).To(Succeed())
+	t.Cleanup(func() { _ = j.ClosePort() })
+
+	// The hardware changes while the client is connected
+	code, body := call(http.MethodPut, "/adc/2", `{"voltage": "1.65V"}`)
+	g.Expect(code).To(Equal(http.StatusOK), body)
+	g.Expect(local.GetADC(j, 2)).To(BeNumerically("~", 1.65, 0.001))
+
+	code, body = call(http.MethodPut, "/gpio/3", `{"high": true}`)
+	g.Expect(code).To(Equal(http.StatusOK), body)
+	g.Expect(local.GetGPIO(j, 3)).To(BeTrue())
+
+	code, body = call(http.MethodPut, "/state/dac1", `{"value": "2.5"}`)
+	g.Expect(code).To(Equal(http.StatusOK), body)
+
+	code, body = call(http.MethodGet, "/state", "")
+	g.Expect(code).To(Equal(http.StatusOK))
+	state := map[string]string{}
+	g.Expect(json.Unmarshal([]byte(body), &state)).To(Succeed())
+	g.Expect(state).To(And(
+		HaveKeyWithValue("adc2", "1.65"),
+		HaveKeyWithValue("gpio3", "True"),
+		HaveKeyWithValue("dac1", "2.50"),
+	))
+
+	code, _ = call(http.MethodPut, "/state/unknown", `{"value": "1"}`)
+	g.Expect(code).To(Equal(http.StatusNotFound))
+	code, _ = call(http.MethodPut, "/adc/0", `{"voltage": "20V"}`)
+	g.Expect(code).To(Equal(http.StatusBadRequest))
+	code, _ = call(http.MethodPut, "/adc/0", `{"volts": 1}`)
+	g.Expect(code).To(Equal(http.StatusBadRequest))
+
+	// Mappings take precedence over the engine, replacing them changes the answers right away
+	code, body = call(http.MethodPut, "/mappings",
+		`[{"request": ">adc_get(2)", "responses": [{"chunks": [{"data": "\"Python> adc_get(2)\\r\\n3.14\\r\\n\""}]}]}]`)
+	g.Expect(code).To(Equal(http.StatusNoContent), body)
+	g.Expect(local.GetADC(j, 2)).To(BeNumerically("~", 3.14, 0.001))
+	g.Expect(e.Mappings()).To(HaveLen(1))
+
+	code, _ = call(http.MethodPut, "/mappings", `[{"request": "(", "match": "regex", "responses": []}]`)
+	g.Expect(code).To(Equal(http.StatusBadRequest))
+	g.Expect(e.Mappings()).To(HaveLen(1))
+
+	code, _ = call(http.MethodPut, "/mappings", `[]`)
+	g.Expect(code).To(Equal(http.StatusNoContent))
+
+	// A reset restores the state the emulator started with
+	code, _ = call(http.MethodPost, "/reset", "")
+	g.Expect(code).To(Equal(http.StatusNoContent))
+	g.Expect(e.GetState()).To(HaveKeyWithValue("adc2", "0.00"))
+
+	code, _ = call(http.MethodPost, "/disconnect", "")
+	g.Expect(code).To(Equal(http.StatusNoContent))
+	g.Expect(e.disconnect.Load()).To(BeTrue())
+}
+
+func TestControlAPIWithoutEngine(t *testing.T) {
+	g := NewWithT(t)
+
+	e, err := New(config.NewDefaultConfig(), slog.New(slog.NewTextHandler(t.Output(), nil)))
+	g.Expect(err).NotTo(HaveOccurred())
+
+	req := httptest.NewRequestWithContext(t.Context(), http.MethodPut, "/adc/0", strings.NewReader(`{"voltage": "1V"}`))
+	rec := httptest.NewRecorder()
+	e.ControlHandler().ServeHTTP(rec, req)
+
+	g.Expect(rec.Code).To(Equal(http.StatusNotImplemented))
+	g.Expect(e.SetState("adc0", "1V")).To(MatchError(ErrStateNotSettable))
+}
@@ -0,0 +1,81 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package emulator
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/detiber/k8s-jumperless/utils/internal/emulator/config"
+)
+
+var ErrDumpUnsupported = errors.New("config dumps require the jumperless engine")
+
+// Dump renders the hardware state the emulator starts in with the config in the config dump format of the
+// device, see dumpState. The jumperless engine is used unless the config names another engine.
+func Dump(c *config.EmulatorConfig) (string, error) {
+	name := c.Engine
+	if name == "" {
+		name = EngineJumperless
+	}
+
+	engine, err := NewEngine(name)
+	if err != nil {
+		return "", err
+	}
+
+	if err := applyPersonality(c.Personality, engine); err != nil {
+		return "", err
+	}
+
+	if err := applyHardware(c.Hardware, engine); err != nil {
+		return "", err
+	}
+
+	return dumpState(engine)
+}
+
+// dumpState renders the hardware state of the engine in the config dump format of the device
+func dumpState(engine Engine) (string, error) {
+	jumperlessEngine, ok := engine.(*JumperlessEngine)
+	if !ok {
+		return "", ErrDumpUnsupported
+	}
+
+	return jumperlessEngine.dumpState()
+}
+
+// dumpState renders the config in the format of the config dump printed by the device, with the DAC entries
+// set to the current voltages rather than the saved ones. Without a loaded config the factory config is
+// rendered, so any synthetic state can be dumped.
+func (j *JumperlessEngine) dumpState() (string, error) {
+	base := factoryConfig
+	if j.config != nil {
+		base = j.renderConfig()
+	}
+
+	dump := NewJumperlessEngine()
+	if err := dump.loadConfig(base); err != nil {
+		return "", err
+	}
+
+	for channel, key := range dacConfigKeys {
+		dump.setConfig("dacs", key, fmt.Sprintf("%.2f", j.dacs[channel]))
+	}
+
+	return dump.renderConfig(), nil
+}
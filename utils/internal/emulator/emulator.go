@@ -21,152 +21,511 @@ import (
 	"errors"
 	"fmt"
 	"io"
-	"log"
+	"log/slog"
 	"math/rand"
+	"net"
 	"os"
+	"regexp"
 	"strconv"
 	"strings"
 	"sync"
-	"syscall"
+	"sync/atomic"
 	"time"
 
-	"github.com/creack/pty"
+	"github.com/detiber/k8s-jumperless/internal/transport"
 	"github.com/detiber/k8s-jumperless/utils/internal/emulator/config"
+	"github.com/detiber/k8s-jumperless/utils/internal/health"
+	"github.com/detiber/k8s-jumperless/utils/internal/logging"
+	"github.com/detiber/k8s-jumperless/utils/internal/termios"
+	"github.com/detiber/k8s-jumperless/utils/internal/vport"
 )
 
 var ErrNoResponsesConfigured = errors.New("no responses configured")
 var ErrPartialWrite = errors.New("partial write")
+var ErrInvalidMapping = errors.New("invalid mapping")
+
+// TemplateData is available to the response chunks of mappings with templates enabled
+type TemplateData struct {
+	// Request is the request being answered
+	Request string
+
+	// Groups contains the numbered and named capture groups of a regex mapping, e.g. {{index .Groups "1"}}
+	Groups map[string]string
+
+	// State is the state of the engine, if one is configured, e.g. {{.State.dac0}}
+	State map[string]string
+}
 
 // Emulator represents a Jumperless device emulator
 type Emulator struct {
 	config          *config.EmulatorConfig
-	logger          *log.Logger
-	pseudoTTY       *os.File // This is what we listen on for user input
-	virtualTTY      *os.File // This is what we return to the user as the virtual port
+	logger          *slog.Logger
+	ports           []*ptyPort             // Virtual serial ports, the configured virtual port followed by any extra ports
+	listener        net.Listener           // Used instead of the virtual serial ports when listening on TCP
+	creds           *transport.Credentials // Authentication of the clients of the listener and control API
 	cancel          context.CancelCauseFunc
 	wg              sync.WaitGroup
-	requestCounters map[string]int // Track request counts for sequential responses
+	requestCounters map[string]int   // Track request counts for sequential responses
+	patterns        []*regexp.Regexp // Compiled patterns for regex mappings, indexed like the mappings
+	engine          Engine           // Optional engine tracking device state, replaced when the device is reset
+	engineLock      sync.Mutex       // Serializes access to the engine from requests and scenario events
+	requestLock     sync.Mutex       // Serializes requests from all clients, the device handles one at a time
+
+	passthrough *Emulator                      // Optional UART passthrough endpoint
+	onData      func(data []byte, w io.Writer) // Called with all data read, used by the passthrough endpoint
+	controlLock sync.Mutex                     // Guards controls
+	controls    map[io.Writer]struct{}         // The connected control port clients
+
+	faults   *faultInjector // Optional fault injection for responses
+	failures []*failureRule // Requests failing with an error response, guarded by requestLock
+
+	seed int64      // The effective seed of the random source
+	rand *rand.Rand // Random source for jitter and random response selection, guarded by requestLock
+
+	stateLock  sync.Mutex // Serializes writes of the state file
+	savedState []byte     // The content last written to the state file, guarded by stateLock
+
+	ready      atomic.Bool  // Set while the emulator serves clients
+	requests   atomic.Int64 // The number of requests received from all clients
+	disconnect atomic.Bool  // Set to disconnect the client after the next response chunk, see InjectDisconnect
+}
+
+// Status is the readiness reported by the health probes
+type Status struct {
+	Ports           []string `json:"ports,omitempty"`
+	PassthroughPort string   `json:"passthroughPort,omitempty"`
+	Requests        int64    `json:"requests"`
+}
+
+// ptyPort is a virtual serial port, optionally linked to a configured name
+type ptyPort struct {
+	link    string      // The configured name of the virtual port, if any
+	lock    sync.Mutex  // Guards replacing the pty after an injected disconnect
+	virtual *vport.Port // A pty on Unix or a named pipe on Windows
 }
 
 // New creates a new emulator instance
-func New(c *config.EmulatorConfig, logger *log.Logger) (*Emulator, error) {
+func New(c *config.EmulatorConfig, logger *slog.Logger) (*Emulator, error) {
 	if logger == nil {
-		logger = log.New(os.Stdout, "[emulator] ", log.LstdFlags)
+		logger = logging.Subsystem(slog.Default(), "emulator")
+	}
+
+	patterns, err := compilePatterns(c.Mappings)
+	if err != nil {
+		return nil, err
+	}
+
+	engine, err := NewEngine(c.Engine)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := validateScenario(c.Scenario, engine); err != nil {
+		return nil, err
+	}
+
+	if err := applyPersonality(c.Personality, engine); err != nil {
+		return nil, err
+	}
+
+	if err := applyHardware(c.Hardware, engine); err != nil {
+		return nil, err
+	}
+
+	// The state saved by a previous run takes precedence over the hardware config
+	if c.StateFile != "" {
+		if err := loadStateFile(c.StateFile, engine); err != nil {
+			return nil, err
+		}
+		logger.Info("Persisting emulated device state", "file", c.StateFile)
 	}
 
-	return &Emulator{
+	if err := validateControlRules(c.Control); err != nil {
+		return nil, err
+	}
+
+	failures, err := newFailureRules(c.Failures)
+	if err != nil {
+		return nil, err
+	}
+
+	creds, err := c.Security.Load()
+	if err != nil {
+		return nil, fmt.Errorf("failed to load credentials: %w", err)
+	}
+
+	seed := c.Seed
+	if seed == 0 {
+		seed = time.Now().UnixNano()
+	}
+
+	// Log the seed so a run can be reproduced
+	logger.Info("Using random seed", "seed", seed)
+
+	e := &Emulator{
 		config:          c,
 		logger:          logger,
 		requestCounters: make(map[string]int, len(c.Mappings)),
-	}, nil
+		patterns:        patterns,
+		engine:          engine,
+		controls:        map[io.Writer]struct{}{},
+		failures:        failures,
+		creds:           creds,
+		seed:            seed,
+		rand:            rand.New(rand.NewSource(seed)), //nolint:gosec
+	}
+
+	if c.Passthrough != nil {
+		if err := e.newPassthrough(c.Passthrough); err != nil {
+			return nil, err
+		}
+	}
+
+	if c.Faults != nil {
+		if e.faults, err = newFaultInjector(c.Faults, seed, logger); err != nil {
+			return nil, err
+		}
+	}
+
+	return e, nil
+}
+
+// compilePatterns compiles the requests of regex mappings, which must match the whole request
+func compilePatterns(mappings config.Mappings) ([]*regexp.Regexp, error) {
+	patterns := make([]*regexp.Regexp, len(mappings))
+
+	for i, mapping := range mappings {
+		switch mapping.Match {
+		case "", config.MatchExact:
+		case config.MatchRegex:
+			pattern, err := regexp.Compile(`^(?:` + strings.TrimSpace(mapping.Request) + `)$`)
+			if err != nil {
+				return nil, fmt.Errorf("%w: %q: %w", ErrInvalidMapping, mapping.Request, err)
+			}
+			patterns[i] = pattern
+		default:
+			return nil, fmt.Errorf("%w: %q: unknown match %q", ErrInvalidMapping, mapping.Request, mapping.Match)
+		}
+
+		switch mapping.Select {
+		case "", config.SelectSequential, config.SelectRandom:
+		default:
+			return nil, fmt.Errorf("%w: %q: unknown select %q", ErrInvalidMapping, mapping.Request, mapping.Select)
+		}
+	}
+
+	return patterns, nil
 }
 
 // Start starts the emulator
 func (e *Emulator) Start(ctx context.Context) error {
-	// Create virtual serial port (pty)
-	pseudoTTY, virtualTTY, err := pty.Open()
-	if err != nil {
-		return fmt.Errorf("failed to create pty: %w", err)
+	if e.passthrough != nil {
+		if err := e.passthrough.Start(ctx); err != nil {
+			return fmt.Errorf("failed to start passthrough port: %w", err)
+		}
 	}
 
-	// Ensure non-blocking reads on pseudo TTY, this allows us to implement read timeouts
-	fd := pseudoTTY.Fd()
-	if err := syscall.SetNonblock(int(fd), true); err != nil {
-		e.tryCleanup()
-		return fmt.Errorf("failed to set pseudo TTY to non-blocking: %w", err)
+	var err error
+	if e.config.Listen != "" {
+		err = e.startListener(ctx)
+	} else {
+		err = e.startPTY(ctx)
 	}
 
-	e.pseudoTTY = pseudoTTY
-	e.virtualTTY = virtualTTY
-
-	// Create symlink to the configured virtual port name if specified
-	if e.config.VirtualPort != "" && e.config.VirtualPort != virtualTTY.Name() {
-		// Remove existing symlink if it exists
-		if err := os.Remove(e.config.VirtualPort); err != nil && !os.IsNotExist(err) {
-			e.tryCleanup() // Clean up if symlink creation fails
-			return fmt.Errorf("failed to remove existing virtual port %s: %w", e.config.VirtualPort, err)
+	if err != nil && e.passthrough != nil {
+		if stopErr := e.passthrough.Stop(); stopErr != nil {
+			e.logger.Warn("Failed to stop passthrough port", logging.Err(stopErr))
 		}
+	}
+
+	e.ready.Store(err == nil)
+
+	return err
+}
 
-		// Create symlink
-		if err := os.Symlink(virtualTTY.Name(), e.config.VirtualPort); err != nil {
-			e.tryCleanup() // Clean up if symlink creation fails
-			return fmt.Errorf("failed to create symlink %s -> %s: %w", e.config.VirtualPort, virtualTTY.Name(), err)
+// startPTY starts serving the emulated device on the configured virtual serial port and any extra ports,
+// all backed by the same device state
+func (e *Emulator) startPTY(ctx context.Context) error {
+	for _, link := range append([]string{e.config.VirtualPort}, e.config.ExtraPorts...) {
+		port := &ptyPort{link: link}
+		e.ports = append(e.ports, port)
+
+		if err := e.openPTY(port); err != nil {
+			e.tryCleanup()
+			return err
 		}
-		e.logger.Printf("Created virtual serial port: %s -> %s", e.config.VirtualPort, virtualTTY.Name())
-	} else {
-		e.logger.Printf("Created virtual serial port: %s", virtualTTY.Name())
 	}
 
 	// Start recorder
 	handlerctx, cancel := context.WithCancelCause(ctx)
 	e.cancel = cancel
-	e.wg.Go(func() { e.handleRequests(handlerctx) })
+	for _, port := range e.ports {
+		e.wg.Go(func() { e.handleRequests(handlerctx, port) })
+
+		// Clients change the line settings of a virtual serial port with termios, which has to be polled
+		if len(e.config.Control) > 0 {
+			e.wg.Go(func() {
+				termios.WatchBaudRate(handlerctx, port.masterTTY, termiosPollInterval, func(baudRate int) {
+					e.handleEvent(port.masterTTY(), config.EventBaudRate, strconv.Itoa(baudRate))
+				})
+			})
+		}
+	}
+	e.startScenario(handlerctx)
+	e.startStatePersistence(handlerctx)
+
+	return nil
+}
+
+// openPTY creates a virtual serial port under its configured name
+func (e *Emulator) openPTY(port *ptyPort) error {
+	virtual, err := vport.Open(port.link)
+	if err != nil {
+		return fmt.Errorf("failed to create virtual serial port: %w", err)
+	}
+
+	port.virtual = virtual
+
+	if virtual.Name() != virtual.Device() {
+		e.logger.Info("Created virtual serial port", "link", virtual.Name(), "port", virtual.Device())
+	} else {
+		e.logger.Info("Created virtual serial port", "port", virtual.Name())
+	}
 
 	return nil
 }
 
-// handleRequests handles incoming requests from the serial port
-func (e *Emulator) handleRequests(ctx context.Context) {
+// reopenPTY replaces a virtual serial port, as if the device was unplugged and reattached
+func (e *Emulator) reopenPTY(ctx context.Context, port *ptyPort) {
+	port.lock.Lock()
+	defer port.lock.Unlock()
+
+	if ctx.Err() != nil {
+		return
+	}
+
+	e.closePTY(port)
+
+	if err := e.openPTY(port); err != nil {
+		e.logger.Error("Error recreating virtual serial port", logging.Err(err))
+	}
+}
+
+// handleRequests handles incoming requests from a virtual serial port
+func (e *Emulator) handleRequests(ctx context.Context, port *ptyPort) {
+	// The pty remains usable after the client disconnects, keep serving until cancelled
+	for ctx.Err() == nil {
+		if err := e.serve(ctx, port.virtual); errors.Is(err, ErrInjectedDisconnect) {
+			e.logger.Info("Injecting disconnect, recreating virtual serial port")
+			e.reopenPTY(ctx, port)
+		}
+	}
+}
+
+// serve handles incoming requests from rw until the client disconnects or ctx is cancelled.
+// ErrInjectedDisconnect is returned when the client should be disconnected by an injected fault.
+func (e *Emulator) serve(ctx context.Context, rw io.ReadWriter) error {
 	buffer := make([]byte, e.config.BufferSize)
 	requestBuffer := strings.Builder{}
+	repl := replInput{}
+
+	e.addControl(rw)
+	defer e.removeControl(rw)
 
 	for {
 		select {
 		case <-ctx.Done():
-			return
+			return nil
 		default:
-			n, err := e.pseudoTTY.Read(buffer)
+			n, err := rw.Read(buffer)
 			if err != nil {
 				if os.IsTimeout(err) {
 					continue // Timeout is expected
 				}
 				if errors.Is(err, io.EOF) {
-					e.logger.Printf("Client disconnected")
+					e.logger.Info("Client disconnected")
+					return nil
+				}
+				if errors.Is(err, net.ErrClosed) || errors.Is(err, os.ErrClosed) {
+					return nil
+				}
+				e.logger.Error("Error reading request", logging.Err(err))
+				continue
+			}
+
+			if n > 0 && e.onData != nil {
+				e.onData(buffer[:n], rw)
+
+				// Passthrough ports without mappings only route data
+				if len(e.config.Mappings) == 0 {
 					continue
 				}
-				e.logger.Printf("Error reading from pty: %v", err)
+			}
+
+			if n == 0 {
 				continue
 			}
 
-			if n > 0 {
-				data := string(buffer[:n])
+			// Multi-line statements are answered once they are complete, with the REPL prompts in between
+			echo, requests := repl.feed(string(buffer[:n]))
+			if echo != "" {
+				err := e.writeChunk(rw, echo)
+
+				if errors.Is(err, ErrInjectedDisconnect) {
+					return err
+				}
+				if err != nil {
+					e.logger.Error("Error sending prompt", logging.Err(err))
+				}
+			}
+
+			for _, data := range requests {
 				requestBuffer.WriteString(data)
 
 				// Process complete requests (assuming they end with newline or are single commands)
 				request := strings.TrimSpace(requestBuffer.String())
-				if request != "" {
-					e.logger.Printf("Received request: %q", request)
-
-					// Find matching response
-					response := e.findResponse(request)
-					if response != nil {
-						if err := e.sendResponse(response); err != nil {
-							e.logger.Printf("Error sending response: %v", err)
-						}
-					} else {
-						e.logger.Printf("No response configured for request: %q", request)
-					}
-
-					requestBuffer.Reset()
+				if request == "" {
+					continue
+				}
+
+				e.logger.Debug("Received request", "request", request)
+				e.requests.Add(1)
+
+				err := e.handleRequest(rw, request)
+
+				if errors.Is(err, ErrInjectedDisconnect) {
+					return err
+				}
+				if err != nil {
+					e.logger.Error("Error sending response", logging.Err(err))
 				}
+
+				requestBuffer.Reset()
 			}
 		}
 	}
 }
 
-// findResponse finds the appropriate response for a request
-func (e *Emulator) findResponse(request string) *config.RequestResponse {
-	for _, mapping := range e.config.Mappings {
-		if strings.TrimSpace(request) == strings.TrimSpace(mapping.Request) {
-			return &mapping
+// handleRequest answers a single request. Requests from all clients are handled one at a time,
+// so clients attached to different ports observe the same device state and response sequences.
+func (e *Emulator) handleRequest(w io.Writer, request string) error {
+	e.requestLock.Lock()
+	defer e.requestLock.Unlock()
+
+	// Statements joined on a single line are answered one after the other, like the REPL executes them
+	if statements, ok := splitStatements(request); ok {
+		for _, statement := range statements {
+			var err error
+			if text, ok := printedLiteral(statement); ok {
+				err = e.writeChunk(w, text+"\r\n")
+			} else {
+				err = e.answer(w, statement)
+			}
+			if err != nil {
+				return err
+			}
 		}
+
+		return nil
 	}
 
-	return nil
+	return e.answer(w, request)
+}
+
+// answer answers a single request, e.requestLock must be held
+func (e *Emulator) answer(w io.Writer, request string) error {
+	// Failing requests are answered with an error and don't change the engine state
+	if response, failed := e.failureResponse(request); failed {
+		e.logger.Info("Injecting failure for request", "request", request)
+		return e.writeChunk(w, response)
+	}
+
+	// The engine sees every request to keep its state current
+	var engineResponse string
+	var handled bool
+	if e.engine != nil {
+		e.engineLock.Lock()
+		engineResponse, handled = e.engine.Handle(request)
+		e.engineLock.Unlock()
+	}
+
+	// Find matching response, falling back to the engine
+	response, groups := e.findResponse(request)
+	switch {
+	case response != nil:
+		return e.sendResponse(w, response, e.templateData(request, groups))
+	case handled:
+		return e.writeChunk(w, engineResponse)
+	default:
+		e.logger.Warn("No response configured for request", "request", request)
+		return nil
+	}
+}
+
+// findResponse finds the appropriate response for a request, along with the capture groups of a regex mapping
+func (e *Emulator) findResponse(request string) (*config.RequestResponse, map[string]string) {
+	index, groups := e.findMapping(request)
+	if index < 0 {
+		return nil, nil
+	}
+
+	mapping := e.config.Mappings[index]
+
+	return &mapping, groups
+}
+
+// findMapping returns the index of the first mapping matching a request, or -1 if none does, along with the
+// capture groups of a regex mapping
+func (e *Emulator) findMapping(request string) (int, map[string]string) {
+	request = strings.TrimSpace(request)
+
+	for i, mapping := range e.config.Mappings {
+		pattern := e.patterns[i]
+		if pattern == nil {
+			if request == strings.TrimSpace(mapping.Request) {
+				return i, nil
+			}
+			continue
+		}
+
+		match := pattern.FindStringSubmatch(request)
+		if match == nil {
+			continue
+		}
+
+		groups := make(map[string]string, len(match))
+		for j, name := range pattern.SubexpNames() {
+			groups[strconv.Itoa(j)] = match[j]
+			if name != "" {
+				groups[name] = match[j]
+			}
+		}
+
+		return i, groups
+	}
+
+	return -1, nil
+}
+
+// templateData returns the data available to response templates for a request
+func (e *Emulator) templateData(request string, groups map[string]string) TemplateData {
+	data := TemplateData{
+		Request: request,
+		Groups:  groups,
+		State:   map[string]string{},
+	}
+
+	if e.engine != nil {
+		e.engineLock.Lock()
+		data.State = e.engine.State()
+		e.engineLock.Unlock()
+	}
+
+	return data
 }
 
 // sendResponse sends a response with configured delays and chunking
-func (e *Emulator) sendResponse(mapping *config.RequestResponse) error {
+func (e *Emulator) sendResponse(w io.Writer, mapping *config.RequestResponse, data TemplateData) error {
 	requestKey := mapping.Request
 	requestIndex := e.requestCounters[requestKey]
 
@@ -176,7 +535,11 @@ func (e *Emulator) sendResponse(mapping *config.RequestResponse) error {
 	case 1:
 		requestIndex = 0
 	default:
-		requestIndex %= len(mapping.Responses)
+		if mapping.Select == config.SelectRandom {
+			requestIndex = e.rand.Intn(len(mapping.Responses))
+		} else {
+			requestIndex %= len(mapping.Responses)
+		}
 	}
 
 	// Update request counter for this mapping
@@ -188,7 +551,7 @@ func (e *Emulator) sendResponse(mapping *config.RequestResponse) error {
 		delay := chunk.Delay
 
 		if chunk.JitterMax > 0 {
-			jitter := time.Duration(rand.Int63n(int64(chunk.JitterMax))) //nolint:gosec
+			jitter := time.Duration(e.rand.Int63n(int64(chunk.JitterMax)))
 			delay += jitter
 		}
 
@@ -196,62 +559,124 @@ func (e *Emulator) sendResponse(mapping *config.RequestResponse) error {
 			time.Sleep(delay)
 		}
 
-		responseText := chunk.Data
-
-		// try to unquote the response chunk
-		unquoted, err := strconv.Unquote(responseText)
+		responseText, err := RenderChunk(chunk)
 		if err != nil {
-			// if unquoting fails, just use the original string
-			e.logger.Printf("Warning: failed to unquote response chunk %q: %v", responseText, err)
-		} else {
-			responseText = unquoted
+			// if rendering fails, just use the original string
+			e.logger.Warn("Failed to render response chunk", logging.Err(err))
+			responseText = chunk.Data
 		}
 
-		n, err := e.pseudoTTY.Write([]byte(responseText))
-		if err != nil {
-			return fmt.Errorf("failed to write response to pty: %w", err)
+		if mapping.Template {
+			rendered, err := RenderTemplate(responseText, data)
+			if err != nil {
+				// if the template fails, just use the unrendered string
+				e.logger.Warn("Failed to render response template", logging.Err(err))
+			} else {
+				responseText = rendered
+			}
 		}
-		if n != len(responseText) {
-			return fmt.Errorf("%w: wrote %d of %d bytes", ErrPartialWrite, n, len(responseText))
+
+		if err := e.writeChunk(w, responseText); err != nil {
+			return err
 		}
+	}
+
+	return nil
+}
 
-		e.logger.Printf("Sent response chunk: %q", responseText)
+// writeChunk writes a single response chunk, applying any injected faults
+func (e *Emulator) writeChunk(w io.Writer, responseText string) error {
+	var disconnect bool
+	if e.faults != nil {
+		faults := e.faults.inject(responseText)
+		if faults.stall > 0 {
+			e.logger.Info("Injecting stall", "duration", faults.stall)
+			time.Sleep(faults.stall)
+		}
+		if faults.data != responseText {
+			e.logger.Info("Injecting faults into response chunk", "response", responseText)
+		}
+		responseText = faults.data
+		disconnect = faults.disconnect
+	}
+	if e.disconnect.CompareAndSwap(true, false) {
+		disconnect = true
+	}
+
+	n, err := w.Write([]byte(responseText))
+	if err != nil {
+		return fmt.Errorf("failed to write response: %w", err)
+	}
+	if n != len(responseText) {
+		return fmt.Errorf("%w: wrote %d of %d bytes", ErrPartialWrite, n, len(responseText))
+	}
+
+	e.logger.Debug("Sent response chunk", "response", responseText)
+
+	if disconnect {
+		return ErrInjectedDisconnect
 	}
 
 	return nil
 }
 
+// RenderChunk returns the data to send for a response chunk, chunks are stored quoted to preserve control characters
+func RenderChunk(chunk config.ResponseChunk) (string, error) {
+	unquoted, err := strconv.Unquote(chunk.Data)
+	if err != nil {
+		return "", fmt.Errorf("failed to unquote response chunk %q: %w", chunk.Data, err)
+	}
+
+	return unquoted, nil
+}
+
+// RenderTemplate renders a response chunk of a mapping with templates enabled, missing state renders empty
+func RenderTemplate(text string, data TemplateData) (string, error) {
+	tmpl, err := parseTemplate(text, groupNames(data.Groups))
+	if err != nil {
+		return "", err
+	}
+
+	rendered := strings.Builder{}
+	if err := tmpl.Execute(&rendered, data); err != nil {
+		return "", fmt.Errorf("failed to render response template %q: %w", text, err)
+	}
+
+	return rendered.String(), nil
+}
+
 func (e *Emulator) tryCleanup() {
-	// Close pseudo TTY
-	if e.pseudoTTY != nil {
-		if err := e.pseudoTTY.Close(); err != nil {
-			e.logger.Printf("Warning: failed to close pseudo TTY: %v", err)
-		} else {
-			e.logger.Printf("Closed pseudo TTY: %s", e.pseudoTTY.Name())
-		}
+	for _, port := range e.ports {
+		e.closePTY(port)
 	}
 
-	// Close virtual TTY
-	if e.virtualTTY != nil {
-		if err := e.virtualTTY.Close(); err != nil {
-			e.logger.Printf("Warning: failed to close virtual TTY: %v", err)
+	// Close TCP listener
+	if e.listener != nil {
+		if err := e.listener.Close(); err != nil && !errors.Is(err, net.ErrClosed) {
+			e.logger.Warn("Failed to close listener", logging.Err(err))
 		} else {
-			e.logger.Printf("Closed virtual TTY: %s", e.virtualTTY.Name())
+			e.logger.Debug("Closed listener", "listen", e.listener.Addr())
 		}
 	}
+}
 
-	// Remove symlink if it was created
-	if e.config.VirtualPort != "" {
-		if err := os.Remove(e.config.VirtualPort); err != nil && !os.IsNotExist(err) {
-			e.logger.Printf("Warning: failed to remove virtual port %s: %v", e.config.VirtualPort, err)
-		} else {
-			e.logger.Printf("Removed virtual port symlink: %s", e.config.VirtualPort)
-		}
+// closePTY closes a virtual serial port and removes its link
+func (e *Emulator) closePTY(port *ptyPort) {
+	if port.virtual == nil {
+		return
+	}
+
+	if err := port.virtual.Close(); err != nil && !errors.Is(err, os.ErrClosed) {
+		e.logger.Warn("Failed to close virtual serial port", "port", port.virtual.Name(), logging.Err(err))
+	} else {
+		e.logger.Debug("Closed virtual serial port", "port", port.virtual.Name())
 	}
 }
 
 // Stop stops the emulator
 func (e *Emulator) Stop() error {
+	e.ready.Store(false)
+
 	// Cancel emulator goroutines
 	if e.cancel != nil {
 		// attempt to cancel between reads/writes
@@ -260,29 +685,98 @@ func (e *Emulator) Stop() error {
 		// Give some time for an active read/write to finish
 		time.Sleep(100 * time.Millisecond)
 
-		// Force close the pseudo TTY to unblock any active reads
-		if err := e.pseudoTTY.Close(); err != nil {
-			e.logger.Printf("Warning: failed to close pseudo TTY: %v", err)
-		} else {
-			e.logger.Printf("Closed pseudo TTY: %s", e.pseudoTTY.Name())
+		// Force close the served side of the virtual ports to unblock any active reads
+		for _, port := range e.ports {
+			port.lock.Lock()
+			if port.virtual != nil {
+				if err := port.virtual.CloseServed(); err != nil {
+					e.logger.Warn("Failed to close virtual serial port", logging.Err(err))
+				}
+			}
+			port.lock.Unlock()
 		}
 
+		// Close the listener to unblock any pending accepts
+		if e.listener != nil {
+			if err := e.listener.Close(); err != nil {
+				e.logger.Warn("Failed to close listener", logging.Err(err))
+			}
+		}
 	}
 
 	e.wg.Wait()
 
+	if e.config.StateFile != "" {
+		e.saveStateFile()
+	}
+
 	e.tryCleanup()
 
+	if e.passthrough != nil {
+		if err := e.passthrough.Stop(); err != nil {
+			e.logger.Warn("Failed to stop passthrough port", logging.Err(err))
+		}
+	}
+
 	return nil
 }
 
-// GetPortName returns the actual port name
-func (e *Emulator) GetPortName() string {
-	if e.config.VirtualPort != "" {
-		return e.config.VirtualPort
+// Status reports the emulator ready once its ports are created, along with the number of requests received
+func (e *Emulator) Status() health.Status {
+	status := Status{Requests: e.requests.Load()}
+
+	if !e.ready.Load() {
+		return health.Status{Ready: false, Details: status}
 	}
-	if e.virtualTTY != nil {
-		return e.virtualTTY.Name()
+
+	status.Ports = e.GetPortNames()
+	status.PassthroughPort = e.GetPassthroughPortName()
+
+	return health.Status{Ready: true, Details: status}
+}
+
+// GetSeed returns the effective seed of the random source, which reproduces the run when configured as the seed
+func (e *Emulator) GetSeed() int64 {
+	return e.seed
+}
+
+// GetState returns the state of the engine, e.g. {"dac0": "3.30"}, or nil if no engine is configured
+func (e *Emulator) GetState() map[string]string {
+	e.engineLock.Lock()
+	defer e.engineLock.Unlock()
+
+	if e.engine == nil {
+		return nil
+	}
+
+	return e.engine.State()
+}
+
+// GetPortName returns the actual port name, or the listen address when serving over TCP
+func (e *Emulator) GetPortName() string {
+	if names := e.GetPortNames(); len(names) > 0 {
+		return names[0]
 	}
 	return ""
 }
+
+// GetPortNames returns the names of all virtual serial ports, or the listen address when serving over TCP
+func (e *Emulator) GetPortNames() []string {
+	if e.listener != nil {
+		return []string{e.listener.Addr().String()}
+	}
+
+	names := make([]string, 0, len(e.ports))
+	for _, port := range e.ports {
+		// The virtual port is replaced after an injected disconnect
+		port.lock.Lock()
+		switch {
+		case port.virtual != nil:
+			names = append(names, port.virtual.Name())
+		case port.link != "":
+			names = append(names, port.link)
+		}
+		port.lock.Unlock()
+	}
+	return names
+}
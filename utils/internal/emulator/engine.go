@@ -0,0 +1,515 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package emulator
+
+import (
+	"errors"
+	"fmt"
+	"maps"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/detiber/k8s-jumperless/jumperless/voltage"
+)
+
+var ErrUnknownEngine = errors.New("unknown emulator engine")
+var ErrUnknownStateKey = errors.New("unknown state key")
+var ErrInvalidStateValue = errors.New("invalid state value")
+var ErrInvalidI2CAddress = errors.New("invalid I2C address")
+
+// EngineJumperless is the name of the built-in engine modelling Jumperless DAC, GPIO and connection state
+const EngineJumperless = "jumperless"
+
+// EnginePython is the name of the built-in engine emulating the MicroPython REPL of a Jumperless, interpreting
+// statements against the state modelled by the jumperless engine
+const EnginePython = "python"
+
+// Engine models the state of the emulated device. Every request is passed to the engine so the state
+// tracks the requests sent to the emulator, including requests answered by the configured mappings.
+// The emulator serializes all calls to an engine.
+type Engine interface {
+	// Handle updates the state for a request. It returns a response and true when the engine is able to
+	// answer the request itself, the response is only used when no mapping matches the request.
+	Handle(request string) (string, bool)
+
+	// State returns a snapshot of the state, made available to response templates as .State
+	State() map[string]string
+}
+
+// StateSetter is implemented by engines whose state can be changed by scenario events
+type StateSetter interface {
+	// SetState sets a single value of the state, using the keys and formats returned by State
+	SetState(key, value string) error
+}
+
+// EngineFactory creates a new engine
+type EngineFactory func() Engine
+
+var engines = map[string]EngineFactory{ //nolint:gochecknoglobals
+	EngineJumperless: func() Engine { return NewJumperlessEngine() },
+	EnginePython:     func() Engine { return NewPythonEngine() },
+}
+var enginesLock sync.RWMutex //nolint:gochecknoglobals
+
+// RegisterEngine makes an engine available by name for use in the emulator config
+func RegisterEngine(name string, factory EngineFactory) {
+	enginesLock.Lock()
+	defer enginesLock.Unlock()
+
+	engines[name] = factory
+}
+
+// NewEngine creates the named engine, an empty name returns no engine
+func NewEngine(name string) (Engine, error) {
+	if name == "" {
+		return nil, nil //nolint:nilnil
+	}
+
+	enginesLock.RLock()
+	defer enginesLock.RUnlock()
+
+	factory, ok := engines[name]
+	if !ok {
+		return nil, fmt.Errorf("%w: %q", ErrUnknownEngine, name)
+	}
+
+	return factory(), nil
+}
+
+// pythonCallPattern matches the MicroPython function calls sent by the jumperless library, e.g. ">dac_set(0, 3.3)"
+var pythonCallPattern = regexp.MustCompile(`^>\s*([a-z_][a-z0-9_]*)\((.*)\)$`) //nolint:gochecknoglobals
+
+const (
+	// dacCount is the number of DAC channels, DAC0, DAC1, TOP_RAIL and BOTTOM_RAIL
+	dacCount = 4
+
+	// adcCount is the number of ADC channels
+	adcCount = 8
+)
+
+// dacConfigKeys are the keys of the dacs config section holding the saved voltage of each DAC channel
+var dacConfigKeys = [dacCount]string{"dac_0", "dac_1", "top_rail", "bottom_rail"} //nolint:gochecknoglobals
+
+// configLinePattern matches a config line as printed by the config dump and written to change a setting,
+// e.g. "`[top_oled] font = jokerman;"
+var configLinePattern = regexp.MustCompile("^`?\\[([^\\]]+)\\]\\s*([^=\\s]+)\\s*=\\s*([^;]*);?$") //nolint:gochecknoglobals
+
+// i2cStateKey is the state key of the addresses of the devices found by I2C scans
+const i2cStateKey = "i2c"
+
+// JumperlessEngine models the DAC and ADC voltages, GPIO levels and node connections of a Jumperless,
+// answering the MicroPython calls that read and change them the way the device REPL does. Once a config is
+// loaded it also models the device config, answering the config dump and config writes. I2C scans find the
+// same devices on any nodes. In REPL emulation mode it interprets the statements instead of answering a fixed
+// set of calls.
+type JumperlessEngine struct {
+	dacs        [dacCount]float64
+	adcs        [adcCount]float64
+	gpios       map[string]bool
+	connections map[string]bool
+	i2cDevices  []uint64
+	config      []configSection
+	python      *pythonInterpreter // Set in REPL emulation mode
+}
+
+// configSection is a section of the device config, kept in the order the device prints it
+type configSection struct {
+	name    string
+	entries []configEntry
+}
+
+type configEntry struct {
+	key   string
+	value string
+}
+
+// NewJumperlessEngine returns a JumperlessEngine with all DACs and ADCs at 0V, no GPIOs set and no connections
+func NewJumperlessEngine() *JumperlessEngine {
+	return &JumperlessEngine{
+		gpios:       map[string]bool{},
+		connections: map[string]bool{},
+	}
+}
+
+// NewPythonEngine returns a JumperlessEngine in REPL emulation mode. It interprets the MicroPython statements
+// it is sent, e.g. "x = dac_get(TOP_RAIL)" or "print(x * 2)", with the common functions of the Jumperless
+// API, so well-formed statements are answered without recording each one. Raised exceptions are printed as
+// tracebacks, statements opening a block are left to the mappings.
+func NewPythonEngine() *JumperlessEngine {
+	j := NewJumperlessEngine()
+	j.python = newPythonInterpreter(j)
+
+	return j
+}
+
+// Handle implements Engine
+func (j *JumperlessEngine) Handle(request string) (string, bool) {
+	if response, ok := j.handleConfig(strings.TrimSpace(request)); ok {
+		return response, true
+	}
+
+	if code, ok := strings.CutPrefix(strings.TrimSpace(request), ">"); ok && j.python != nil {
+		return j.python.run(code)
+	}
+
+	match := pythonCallPattern.FindStringSubmatch(strings.TrimSpace(request))
+	if match == nil {
+		return "", false
+	}
+
+	function := match[1]
+	args := []string{}
+	for arg := range strings.SplitSeq(match[2], ",") {
+		if trimmed := strings.Trim(strings.TrimSpace(arg), `"'`); trimmed != "" {
+			args = append(args, trimmed)
+		}
+	}
+
+	result, ok := j.call(function, args)
+	if !ok {
+		return "", false
+	}
+
+	// The REPL echoes the command after the prompt, followed by the result
+	return fmt.Sprintf("Python> %s(%s)\r\n%s\r\n", function, match[2], result), true
+}
+
+func (j *JumperlessEngine) call(function string, args []string) (string, bool) {
+	switch function {
+	case "dac_get":
+		if len(args) != 1 {
+			break
+		}
+		if channel, ok := dacChannel(args[0]); ok {
+			return voltage.FormatValue(j.dacs[channel]), true
+		}
+	case "dac_set":
+		// An optional third argument controls whether the voltage is saved to the config
+		if len(args) != 2 && len(args) != 3 {
+			break
+		}
+		if channel, ok := dacChannel(args[0]); ok {
+			v, err := voltage.ParseInRange(args[1])
+			if err != nil {
+				return "", false
+			}
+			j.dacs[channel] = voltage.Round(v)
+			if len(args) == 3 && parsePythonBool(args[2]) && j.config != nil {
+				j.setConfig("dacs", dacConfigKeys[channel], fmt.Sprintf("%.2f", j.dacs[channel]))
+			}
+			return voltage.FormatValue(j.dacs[channel]), true
+		}
+	case "adc_get":
+		if len(args) != 1 {
+			break
+		}
+		if channel, err := strconv.Atoi(args[0]); err == nil && channel >= 0 && channel < adcCount {
+			return voltage.FormatValue(j.adcs[channel]), true
+		}
+	case "gpio_get":
+		if len(args) == 1 {
+			return pythonBool(j.gpios[args[0]]), true
+		}
+	case "gpio_set":
+		if len(args) == 2 {
+			j.gpios[args[0]] = parsePythonBool(args[1])
+			return pythonBool(j.gpios[args[0]]), true
+		}
+	case "connect":
+		if len(args) == 2 {
+			j.connections[connectionKey(args[0], args[1])] = true
+			return pythonBool(true), true
+		}
+	case "disconnect":
+		if len(args) == 2 {
+			delete(j.connections, connectionKey(args[0], args[1]))
+			return pythonBool(true), true
+		}
+	case "is_connected":
+		if len(args) == 2 {
+			return pythonBool(j.connections[connectionKey(args[0], args[1])]), true
+		}
+	case "nodes_clear":
+		if len(args) == 0 {
+			clear(j.connections)
+			return pythonBool(true), true
+		}
+	case "i2c_scan":
+		if len(args) == 2 {
+			addresses := make([]string, 0, len(j.i2cDevices))
+			for _, address := range j.i2cDevices {
+				addresses = append(addresses, strconv.FormatUint(address, 10))
+			}
+			return "[" + strings.Join(addresses, ", ") + "]", true
+		}
+	}
+
+	return "", false
+}
+
+// handleConfig answers the firmware query, the config dump and config writes when a config is loaded
+func (j *JumperlessEngine) handleConfig(request string) (string, bool) {
+	if j.config == nil {
+		return "", false
+	}
+
+	switch request {
+	case "?":
+		if version, ok := j.getConfig("config", "firmware_version"); ok {
+			return "Jumperless firmware version: " + version + "\r\n", true
+		}
+	case "~":
+		return j.renderConfig(), true
+	}
+
+	match := configLinePattern.FindStringSubmatch(request)
+	if match == nil || !strings.HasPrefix(request, "`") {
+		return "", false
+	}
+
+	value := strings.TrimSpace(match[3])
+	j.setConfig(match[1], match[2], value)
+
+	// The device echoes the updated entry
+	return fmt.Sprintf("`[%s] %s = %s;\r\n", match[1], match[2], value), true
+}
+
+// renderConfig returns the config dump in the format printed by the device
+func (j *JumperlessEngine) renderConfig() string {
+	var b strings.Builder
+
+	b.WriteString("\r\ncopy / edit / paste any of these lines \r\ninto the main menu to change a setting\r\n")
+	b.WriteString("\r\nJumperless Config:\r\n")
+
+	for _, section := range j.config {
+		b.WriteString("\r\n")
+		for _, entry := range section.entries {
+			fmt.Fprintf(&b, "`[%s] %s = %s;\r\n", section.name, entry.key, entry.value)
+		}
+	}
+
+	b.WriteString("\r\nEND\r\n")
+
+	return b.String()
+}
+
+// loadConfig replaces the config with the config lines in text, in the format of the config dump with the
+// leading backtick and trailing semicolon of each line optional, and sets the DACs to the voltages saved in the config
+// the way the device does on boot
+func (j *JumperlessEngine) loadConfig(text string) error {
+	j.config = []configSection{}
+
+	for line := range strings.SplitSeq(text, "\n") {
+		line = strings.TrimSpace(line)
+		if !strings.HasPrefix(strings.TrimPrefix(line, "`"), "[") {
+			continue // skip empty lines and the text around the config lines
+		}
+
+		match := configLinePattern.FindStringSubmatch(line)
+		if match == nil {
+			return fmt.Errorf("%w: config line %q", ErrInvalidStateValue, line)
+		}
+
+		j.setConfig(match[1], match[2], strings.TrimSpace(match[3]))
+	}
+
+	for channel, key := range dacConfigKeys {
+		value, ok := j.getConfig("dacs", key)
+		if !ok {
+			continue
+		}
+
+		v, err := voltage.ParseInRange(value)
+		if err != nil {
+			return fmt.Errorf("%w: dacs.%s: %w", ErrInvalidStateValue, key, err)
+		}
+		j.dacs[channel] = voltage.Round(v)
+	}
+
+	return nil
+}
+
+// getConfig returns the value of a config entry
+func (j *JumperlessEngine) getConfig(section, key string) (string, bool) {
+	for _, s := range j.config {
+		if s.name != section {
+			continue
+		}
+		for _, entry := range s.entries {
+			if entry.key == key {
+				return entry.value, true
+			}
+		}
+	}
+
+	return "", false
+}
+
+// setConfig sets the value of a config entry, appending the entry and its section when they don't exist
+func (j *JumperlessEngine) setConfig(section, key, value string) {
+	i := slices.IndexFunc(j.config, func(s configSection) bool { return s.name == section })
+	if i < 0 {
+		j.config = append(j.config, configSection{name: section})
+		i = len(j.config) - 1
+	}
+
+	entries := j.config[i].entries
+	if k := slices.IndexFunc(entries, func(e configEntry) bool { return e.key == key }); k >= 0 {
+		entries[k].value = value
+		return
+	}
+
+	j.config[i].entries = append(entries, configEntry{key: key, value: value})
+}
+
+// State implements Engine. DAC voltages are available as dac0 to dac3, ADC voltages as adc0 to adc7,
+// GPIO levels as gpio<pin>, the sorted connections as a comma separated list of node pairs in connections and
+// the addresses of the I2C devices as a comma separated list of hexadecimal addresses in i2c.
+// Config entries are available as config.<section>.<key> once a config is loaded.
+func (j *JumperlessEngine) State() map[string]string {
+	state := map[string]string{}
+
+	for channel, v := range j.dacs {
+		state["dac"+strconv.Itoa(channel)] = voltage.FormatValue(v)
+	}
+
+	for channel, v := range j.adcs {
+		state["adc"+strconv.Itoa(channel)] = voltage.FormatValue(v)
+	}
+
+	for pin, level := range j.gpios {
+		state["gpio"+pin] = pythonBool(level)
+	}
+
+	state["connections"] = strings.Join(slices.Sorted(maps.Keys(j.connections)), ",")
+
+	addresses := make([]string, 0, len(j.i2cDevices))
+	for _, address := range j.i2cDevices {
+		addresses = append(addresses, fmt.Sprintf("0x%02X", address))
+	}
+	state[i2cStateKey] = strings.Join(addresses, ",")
+
+	for _, section := range j.config {
+		for _, entry := range section.entries {
+			state["config."+section.name+"."+entry.key] = entry.value
+		}
+	}
+
+	return state
+}
+
+// SetState implements StateSetter for the dac, adc, gpio, i2c and config keys returned by State
+func (j *JumperlessEngine) SetState(key, value string) error {
+	switch {
+	case strings.HasPrefix(key, "config.") && j.config != nil:
+		section, entry, ok := strings.Cut(strings.TrimPrefix(key, "config."), ".")
+		if !ok || section == "" || entry == "" {
+			return fmt.Errorf("%w: %q", ErrUnknownStateKey, key)
+		}
+		j.setConfig(section, entry, value)
+	case strings.HasPrefix(key, "dac"), strings.HasPrefix(key, "adc"):
+		channels := j.dacs[:]
+		if strings.HasPrefix(key, "adc") {
+			channels = j.adcs[:]
+		}
+
+		channel, err := strconv.Atoi(key[len("dac"):])
+		if err != nil || channel < 0 || channel >= len(channels) {
+			return fmt.Errorf("%w: %q", ErrUnknownStateKey, key)
+		}
+
+		v, err := voltage.ParseInRange(value)
+		if err != nil {
+			return fmt.Errorf("%w: %q: %w", ErrInvalidStateValue, key, err)
+		}
+
+		channels[channel] = voltage.Round(v)
+	case strings.HasPrefix(key, "gpio") && len(key) > len("gpio"):
+		j.gpios[strings.TrimPrefix(key, "gpio")] = parsePythonBool(value)
+	case key == i2cStateKey:
+		addresses, err := parseI2CAddresses(value)
+		if err != nil {
+			return fmt.Errorf("%w: %q: %w", ErrInvalidStateValue, key, err)
+		}
+		j.i2cDevices = addresses
+	default:
+		return fmt.Errorf("%w: %q", ErrUnknownStateKey, key)
+	}
+
+	return nil
+}
+
+// parseI2CAddresses parses a comma separated list of 7-bit I2C addresses, e.g. "0x3C,0x68", returning them
+// sorted and without duplicates
+func parseI2CAddresses(value string) ([]uint64, error) {
+	addresses := []uint64{}
+	for field := range strings.SplitSeq(value, ",") {
+		field = strings.TrimSpace(field)
+		if field == "" {
+			continue
+		}
+
+		address, err := strconv.ParseUint(field, 0, 8)
+		if err != nil || address > 0x7F {
+			return nil, fmt.Errorf("%w: %q", ErrInvalidI2CAddress, field)
+		}
+		addresses = append(addresses, address)
+	}
+
+	slices.Sort(addresses)
+
+	return slices.Compact(addresses), nil
+}
+
+// dacChannel parses a DAC channel number
+func dacChannel(arg string) (int, bool) {
+	channel, err := strconv.Atoi(arg)
+	if err != nil || channel < 0 || channel >= dacCount {
+		return 0, false
+	}
+
+	return channel, true
+}
+
+// connectionKey returns the same key for a connection regardless of the order of the nodes
+func connectionKey(a, b string) string {
+	if b < a {
+		a, b = b, a
+	}
+
+	return a + "-" + b
+}
+
+func pythonBool(b bool) string {
+	if b {
+		return "True"
+	}
+
+	return "False"
+}
+
+func parsePythonBool(s string) bool {
+	switch strings.ToLower(s) {
+	case "true", "1", "high":
+		return true
+	default:
+		return false
+	}
+}
@@ -0,0 +1,45 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package emulator
+
+import (
+	"testing"
+
+	. "github.com/onsi/gomega"
+
+	"github.com/detiber/k8s-jumperless/utils/internal/emulator/config"
+)
+
+func TestJumperlessEngineI2CScan(t *testing.T) {
+	g := NewWithT(t)
+
+	engine := NewJumperlessEngine()
+	response, ok := engine.Handle(`>i2c_scan("20", "21")`)
+	g.Expect(ok).To(BeTrue())
+	g.Expect(response).To(Equal("Python> i2c_scan(\"20\", \"21\")\r\n[]\r\n"))
+
+	g.Expect(applyHardware(&config.HardwareConfig{I2CDevices: []string{"0x68", "60"}}, engine)).To(Succeed())
+	response, ok = engine.Handle(`>i2c_scan("20", "21")`)
+	g.Expect(ok).To(BeTrue())
+	g.Expect(response).To(Equal("Python> i2c_scan(\"20\", \"21\")\r\n[60, 104]\r\n"))
+	g.Expect(engine.State()).To(HaveKeyWithValue("i2c", "0x3C,0x68"))
+
+	g.Expect(engine.SetState("i2c", "")).To(Succeed())
+	g.Expect(engine.State()).To(HaveKeyWithValue("i2c", ""))
+
+	g.Expect(engine.SetState("i2c", "0x80")).To(MatchError(ErrInvalidI2CAddress))
+}
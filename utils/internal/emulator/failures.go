@@ -0,0 +1,103 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package emulator
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/detiber/k8s-jumperless/utils/internal/emulator/config"
+	"github.com/detiber/k8s-jumperless/utils/internal/logging"
+)
+
+var ErrInvalidFailure = errors.New("invalid failure rule")
+
+// defaultFailureResponse is the response of the REPL when a command raises an I/O error
+const defaultFailureResponse = "Python> {{.Request}}\r\n" +
+	"Traceback (most recent call last):\r\n" +
+	"  File \"<stdin>\", line 1, in <module>\r\n" +
+	"OSError: [Errno 5] EIO\r\n"
+
+// failureRule tracks how many requests matched a configured failure rule
+type failureRule struct {
+	config  config.FailureRule
+	pattern *regexp.Regexp
+	matched int
+}
+
+func newFailureRules(rules []config.FailureRule) ([]*failureRule, error) {
+	failures := make([]*failureRule, 0, len(rules))
+
+	for _, rule := range rules {
+		pattern, err := regexp.Compile(rule.Request)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %q: %w", ErrInvalidFailure, rule.Request, err)
+		}
+
+		if rule.After < 0 || rule.Count < 0 {
+			return nil, fmt.Errorf("%w: %q: after and count must not be negative", ErrInvalidFailure, rule.Request)
+		}
+
+		failures = append(failures, &failureRule{config: rule, pattern: pattern})
+	}
+
+	return failures, nil
+}
+
+// fails records a matching request and reports whether it should fail
+func (f *failureRule) fails(request string) bool {
+	if !f.pattern.MatchString(request) {
+		return false
+	}
+
+	f.matched++
+	failure := f.matched - f.config.After
+
+	return failure > 0 && (f.config.Count == 0 || failure <= f.config.Count)
+}
+
+// failureResponse returns the error response for a request that should fail, and false if it should succeed.
+// Must be called with requestLock held.
+func (e *Emulator) failureResponse(request string) (string, bool) {
+	for _, rule := range e.failures {
+		if !rule.fails(request) {
+			continue
+		}
+
+		response := rule.config.Response
+		if response == "" {
+			response = defaultFailureResponse
+		} else if unquoted, err := strconv.Unquote(response); err == nil {
+			// Like response chunks, responses may be quoted to preserve control characters
+			response = unquoted
+		}
+
+		// The REPL echoes Python commands without the leading '>'
+		rendered, err := RenderTemplate(response, TemplateData{Request: strings.TrimPrefix(request, ">")})
+		if err != nil {
+			e.logger.Warn("Failed to render failure response template", logging.Err(err))
+			rendered = response
+		}
+
+		return rendered, true
+	}
+
+	return "", false
+}
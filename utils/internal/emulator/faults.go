@@ -0,0 +1,125 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package emulator
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"math/rand"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/detiber/k8s-jumperless/utils/internal/emulator/config"
+)
+
+var ErrInvalidFaults = errors.New("invalid fault injection config")
+var ErrInjectedDisconnect = errors.New("injected disconnect")
+
+// noiseSequences are the ANSI escape sequences inserted as noise, similar to the output of the device
+var noiseSequences = []string{ //nolint:gochecknoglobals
+	"\x1b[0m",
+	"\x1b[2K",
+	"\x1b[38;5;%dm",
+	"\x1b[48;5;%dm",
+	"\r",
+}
+
+// faultInjector applies the configured faults to response chunks using a seeded random source
+type faultInjector struct {
+	config config.FaultConfig
+	lock   sync.Mutex
+	rand   *rand.Rand
+}
+
+// injectedFaults are the faults to apply to a single response chunk
+type injectedFaults struct {
+	data       string
+	stall      time.Duration
+	disconnect bool
+}
+
+// newFaultInjector creates a fault injector, seeded with the seed of the fault config or the emulator seed
+func newFaultInjector(c *config.FaultConfig, seed int64, logger *slog.Logger) (*faultInjector, error) {
+	rates := map[string]float64{
+		"drop rate":       c.DropRate,
+		"corrupt rate":    c.CorruptRate,
+		"noise rate":      c.NoiseRate,
+		"stall rate":      c.StallRate,
+		"disconnect rate": c.DisconnectRate,
+	}
+	for name, rate := range rates {
+		if rate < 0 || rate > 1 {
+			return nil, fmt.Errorf("%w: %s %v is not between 0 and 1", ErrInvalidFaults, name, rate)
+		}
+	}
+
+	if c.StallRate > 0 && c.StallDuration <= 0 {
+		return nil, fmt.Errorf("%w: stall rate requires a stall duration", ErrInvalidFaults)
+	}
+
+	if c.Seed != 0 {
+		seed = c.Seed
+	}
+
+	// Log the seed so a failing run can be reproduced
+	logger.Info("Injecting faults", "seed", seed)
+
+	return &faultInjector{
+		config: *c,
+		rand:   rand.New(rand.NewSource(seed)), //nolint:gosec
+	}, nil
+}
+
+// inject returns the faults to apply to a response chunk
+func (f *faultInjector) inject(data string) injectedFaults {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+
+	faults := injectedFaults{}
+
+	if f.config.StallRate > 0 && f.rand.Float64() < f.config.StallRate {
+		faults.stall = f.config.StallDuration
+	}
+
+	out := make([]byte, 0, len(data))
+
+	if f.config.NoiseRate > 0 && f.rand.Float64() < f.config.NoiseRate {
+		noise := noiseSequences[f.rand.Intn(len(noiseSequences))]
+		if strings.Contains(noise, "%d") {
+			noise = fmt.Sprintf(noise, f.rand.Intn(256)) //nolint:mnd
+		}
+		out = append(out, noise...)
+	}
+
+	for i := range len(data) {
+		switch {
+		case f.config.DropRate > 0 && f.rand.Float64() < f.config.DropRate:
+			continue
+		case f.config.CorruptRate > 0 && f.rand.Float64() < f.config.CorruptRate:
+			out = append(out, byte(f.rand.Intn(256))) //nolint:mnd,gosec
+		default:
+			out = append(out, data[i])
+		}
+	}
+
+	faults.data = string(out)
+	faults.disconnect = f.config.DisconnectRate > 0 && f.rand.Float64() < f.config.DisconnectRate
+
+	return faults
+}
@@ -0,0 +1,150 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package emulator
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"k8s.io/utils/ptr"
+
+	jumperlessv5alpha1 "github.com/detiber/k8s-jumperless/api/v5alpha1"
+	"github.com/detiber/k8s-jumperless/jumperless/voltage"
+	"github.com/detiber/k8s-jumperless/utils/internal/emulator/config"
+)
+
+var ErrInvalidHardware = errors.New("invalid hardware config")
+var ErrUnknownDACChannel = errors.New("unknown DAC channel")
+
+// applyHardware sets the initial DAC and connection state by passing the requests setting it to the engine,
+// so the state is the same as if a client had set it. The I2C devices, which clients can't change, are set
+// as engine state.
+func applyHardware(c *config.HardwareConfig, engine Engine) error {
+	if c == nil {
+		return nil
+	}
+
+	if engine == nil {
+		return fmt.Errorf("%w: the hardware state requires an engine", ErrInvalidHardware)
+	}
+
+	requests, err := hardwareRequests(c)
+	if err != nil {
+		return err
+	}
+
+	for _, request := range requests {
+		if _, ok := engine.Handle(request); !ok {
+			return fmt.Errorf("%w: the engine does not support %q", ErrInvalidHardware, request)
+		}
+	}
+
+	if len(c.I2CDevices) > 0 {
+		setter, ok := engine.(StateSetter)
+		if !ok {
+			return fmt.Errorf("%w: I2C devices require an engine that supports setting state", ErrInvalidHardware)
+		}
+
+		if err := setter.SetState(i2cStateKey, strings.Join(c.I2CDevices, ",")); err != nil {
+			return fmt.Errorf("%w: %w", ErrInvalidHardware, err)
+		}
+	}
+
+	return nil
+}
+
+// hardwareRequests returns the MicroPython requests setting the DACs and connections of the hardware config
+func hardwareRequests(c *config.HardwareConfig) ([]string, error) {
+	requests := make([]string, 0, len(c.DACs)+len(c.Connections))
+
+	for i, dac := range c.DACs {
+		channel, volts, err := parseHardwareDAC(dac)
+		if err != nil {
+			return nil, fmt.Errorf("%w: dac %d: %w", ErrInvalidHardware, i, err)
+		}
+
+		requests = append(requests, fmt.Sprintf(">dac_set(%d, %s)", channel, voltage.FormatValue(volts)))
+	}
+
+	for i, connection := range c.Connections {
+		if connection.From == "" || connection.To == "" {
+			return nil, fmt.Errorf("%w: connection %d requires two nodes", ErrInvalidHardware, i)
+		}
+
+		requests = append(requests, fmt.Sprintf(">connect(%s, %s)", connection.From, connection.To))
+	}
+
+	return requests, nil
+}
+
+func parseHardwareDAC(dac config.HardwareDAC) (jumperlessv5alpha1.DACChannel, float64, error) {
+	channel, ok := jumperlessv5alpha1.ParseDACChannel(dac.Channel)
+	if !ok {
+		return 0, 0, fmt.Errorf("%w %q", ErrUnknownDACChannel, dac.Channel)
+	}
+
+	volts, err := voltage.ParseInRange(dac.Voltage)
+	if err != nil {
+		return 0, 0, fmt.Errorf("channel %s: %w", dac.Channel, err)
+	}
+
+	return channel, volts, nil
+}
+
+// Manifest returns a Jumperless resource whose spec matches the hardware state of the emulator config, so the
+// controller converges a device emulated with the config to the state the emulator starts in. The resource uses
+// the virtual port of the emulator when it is configured.
+func Manifest(c *config.EmulatorConfig, name, namespace string) (*jumperlessv5alpha1.Jumperless, error) {
+	j := &jumperlessv5alpha1.Jumperless{}
+	j.APIVersion = jumperlessv5alpha1.GroupVersion.String()
+	j.Kind = "Jumperless"
+	j.Name = name
+	j.Namespace = namespace
+	j.Spec.Host.Local = &jumperlessv5alpha1.JumperlessHostLocal{}
+
+	if c.VirtualPort != "" {
+		j.Spec.Host.Local.Port = ptr.To(c.VirtualPort)
+	}
+
+	if c.Hardware == nil {
+		return j, nil
+	}
+
+	// Validate the whole hardware config the same way the emulator does
+	if _, err := hardwareRequests(c.Hardware); err != nil {
+		return nil, err
+	}
+
+	for _, dac := range c.Hardware.DACs {
+		_, volts, _ := parseHardwareDAC(dac)
+
+		j.Spec.DACS = append(j.Spec.DACS, jumperlessv5alpha1.DAC{
+			Channel: dac.Channel,
+			Voltage: voltage.Format(volts),
+		})
+	}
+
+	for _, connection := range c.Hardware.Connections {
+		j.Spec.Connections = append(j.Spec.Connections, jumperlessv5alpha1.Connection{
+			From: connection.From,
+			To:   connection.To,
+		})
+	}
+
+	return j, nil
+}
@@ -0,0 +1,96 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package emulator
+
+import (
+	"io"
+
+	"github.com/detiber/k8s-jumperless/utils/internal/emulator/config"
+	"github.com/detiber/k8s-jumperless/utils/internal/logging"
+)
+
+// newPassthrough creates the UART passthrough endpoint. It is served like the control port, but only
+// answers its own mappings and routes the data written to it according to the passthrough config.
+func (e *Emulator) newPassthrough(c *config.PassthroughConfig) error {
+	logger := e.logger.With("component", "passthrough")
+
+	passthrough, err := New(&config.EmulatorConfig{
+		BufferSize:  e.config.BufferSize,
+		VirtualPort: c.VirtualPort,
+		Listen:      c.Listen,
+		Bind:        e.config.Bind,
+		Security:    e.config.Security,
+		Mappings:    c.Mappings,
+		Seed:        e.seed,
+	}, logger)
+	if err != nil {
+		return err
+	}
+
+	passthrough.onData = func(data []byte, w io.Writer) {
+		if c.Loopback {
+			if err := passthrough.writeChunk(w, string(data)); err != nil {
+				logger.Error("Error looping back data", logging.Err(err))
+			}
+		}
+
+		if c.PrintToControl {
+			e.writeControl(data)
+		}
+	}
+
+	e.passthrough = passthrough
+
+	return nil
+}
+
+// GetPassthroughPortName returns the passthrough port name, or an empty string if it is not configured
+func (e *Emulator) GetPassthroughPortName() string {
+	if e.passthrough == nil {
+		return ""
+	}
+
+	return e.passthrough.GetPortName()
+}
+
+// addControl records a connected control port client that passthrough data is copied to
+func (e *Emulator) addControl(w io.Writer) {
+	e.controlLock.Lock()
+	defer e.controlLock.Unlock()
+
+	e.controls[w] = struct{}{}
+}
+
+// removeControl forgets a control port client once it is disconnected
+func (e *Emulator) removeControl(w io.Writer) {
+	e.controlLock.Lock()
+	defer e.controlLock.Unlock()
+
+	delete(e.controls, w)
+}
+
+// writeControl copies data to all connected control port clients, data is dropped if no client is connected
+func (e *Emulator) writeControl(data []byte) {
+	e.controlLock.Lock()
+	defer e.controlLock.Unlock()
+
+	for control := range e.controls {
+		if err := e.writeChunk(control, string(data)); err != nil {
+			e.logger.Error("Error copying passthrough data to control port", logging.Err(err))
+		}
+	}
+}
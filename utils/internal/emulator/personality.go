@@ -0,0 +1,108 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package emulator
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/detiber/k8s-jumperless/utils/internal/emulator/config"
+)
+
+var ErrUnknownPersonality = errors.New("unknown emulator personality")
+var ErrInvalidPersonality = errors.New("invalid emulator personality")
+
+// factoryConfig is the firmware default config of a Jumperless V5, as printed by a factory-fresh device
+const factoryConfig = `
+[config] firmware_version = 5.3.1.0
+[hardware] generation = 5
+[hardware] revision = 5
+[hardware] probe_revision = 5
+[dacs] top_rail = 0.00
+[dacs] bottom_rail = 0.00
+[dacs] dac_0 = 3.33
+[dacs] dac_1 = 0.00
+[dacs] set_dacs_on_boot = false
+[dacs] set_rails_on_boot = true
+[dacs] probe_power_dac = 0
+[dacs] limit_max = 8.00
+[dacs] limit_min = -8.00
+[routing] stack_paths = 2
+[routing] stack_rails = 3
+[routing] stack_dacs = 0
+[routing] rail_priority = 1
+[calibration] probe_switch_threshold = 0.50
+[calibration] measure_mode_output_voltage = 3.30
+[logo_pads] top_guy = uart_tx
+[logo_pads] bottom_guy = uart_rx
+[display] lines_wires = wires
+[display] menu_brightness = -10
+[display] led_brightness = 10
+[display] rail_brightness = 55
+[display] special_net_brightness = 20
+[display] net_color_mode = rainbow
+[serial_1] function = passthrough
+[serial_1] baud_rate = 115200
+[serial_2] function = off
+[serial_2] baud_rate = 115200
+[top_oled] enabled = true
+[top_oled] i2c_address = 0x3C
+[top_oled] width = 128
+[top_oled] height = 32
+[top_oled] font = jokerman
+`
+
+// personalities are the built-in device configs by name
+var personalities = map[string]string{ //nolint:gochecknoglobals
+	config.PersonalityFactory: factoryConfig,
+}
+
+// applyPersonality loads the config of the named personality into the engine, an empty name loads nothing
+func applyPersonality(name string, engine Engine) error {
+	if name == "" {
+		return nil
+	}
+
+	text, ok := personalities[name]
+	if !ok {
+		return fmt.Errorf("%w: %q", ErrUnknownPersonality, name)
+	}
+
+	jumperlessEngine, ok := engine.(*JumperlessEngine)
+	if !ok {
+		return fmt.Errorf("%w: %q requires the %s or %s engine", ErrInvalidPersonality, name, EngineJumperless,
+			EnginePython)
+	}
+
+	return jumperlessEngine.loadConfig(text)
+}
+
+// persistConfig carries the config of the engine being replaced over to the new engine, setting the DACs to
+// the voltages saved in it the way the device does on boot
+func persistConfig(from, to Engine) error {
+	previous, ok := from.(*JumperlessEngine)
+	if !ok || previous.config == nil {
+		return nil
+	}
+
+	next, ok := to.(*JumperlessEngine)
+	if !ok {
+		return nil
+	}
+
+	return next.loadConfig(previous.renderConfig())
+}
@@ -0,0 +1,128 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package emulator
+
+import (
+	"log/slog"
+	"path/filepath"
+	"testing"
+
+	. "github.com/onsi/gomega"
+
+	jumperlessv5alpha1 "github.com/detiber/k8s-jumperless/api/v5alpha1"
+	"github.com/detiber/k8s-jumperless/internal/controller/local"
+	"github.com/detiber/k8s-jumperless/jumperless"
+	"github.com/detiber/k8s-jumperless/utils/internal/emulator/config"
+)
+
+func TestFactoryPersonality(t *testing.T) {
+	g := NewWithT(t)
+
+	c := config.NewDefaultConfig()
+	c.VirtualPort = filepath.Join(t.TempDir(), "jumperless")
+	c.Engine = EngineJumperless
+	c.Personality = config.PersonalityFactory
+	c.Seed = 1
+
+	e, err := New(c, slog.New(slog.NewTextHandler(t.Output(), nil)))
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(e.Start(t.Context())).To(Succeed())
+	t.Cleanup(func() { g.Expect(e.Stop()).To(Succeed()) })
+
+	j, err := jumperless.NewJumperless(t.Context(), e.GetPortName(), 0)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(j.OpenPort()).To(Succeed())
+	t.Cleanup(func() { g.Expect(j.ClosePort()).To(Succeed()) })
+
+	// The personality answers the config the controller recognizes as factory-fresh
+	factory, err := local.GetConfig(j)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(local.IsFactoryFresh(factory, local.FactoryConfig())).To(BeTrue())
+	g.Expect(e.GetState()).To(HaveKeyWithValue("dac0", "3.33"))
+
+	// Config writes and saved DAC voltages change the config
+	g.Expect(local.SetConfigEntry(j, "display", "led_brightness", "25")).To(Succeed())
+	g.Expect(local.SetDAC(j, jumperlessv5alpha1.TOP_RAIL, 5, true)).To(Succeed())
+	g.Expect(local.SetDAC(j, jumperlessv5alpha1.DAC1, 1, false)).To(Succeed())
+
+	g.Expect(e.GetState()).To(And(
+		HaveKeyWithValue("config.display.led_brightness", "25"),
+		HaveKeyWithValue("config.dacs.top_rail", "5.00"),
+		HaveKeyWithValue("config.dacs.dac_1", "0.00"),
+	))
+
+	provisioned, err := local.GetConfig(j)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(local.IsFactoryFresh(provisioned, local.FactoryConfig())).To(BeFalse())
+
+	// The config persists across a reset, which only restores the voltages saved to it
+	e.requestLock.Lock()
+	g.Expect(e.reset()).To(Succeed())
+	e.requestLock.Unlock()
+
+	g.Expect(e.GetState()).To(And(
+		HaveKeyWithValue("config.display.led_brightness", "25"),
+		HaveKeyWithValue("dac1", "0.00"),
+		HaveKeyWithValue("dac2", "5.00"),
+	))
+}
+
+func TestApplyPersonality(t *testing.T) {
+	g := NewWithT(t)
+
+	g.Expect(applyPersonality("", nil)).To(Succeed())
+	g.Expect(applyPersonality("unknown", NewJumperlessEngine())).To(MatchError(ErrUnknownPersonality))
+	g.Expect(applyPersonality(config.PersonalityFactory, nil)).To(MatchError(ErrInvalidPersonality))
+
+	engine := NewJumperlessEngine()
+	g.Expect(engine.State()).NotTo(HaveKey("config.config.firmware_version"))
+	g.Expect(applyPersonality(config.PersonalityFactory, engine)).To(Succeed())
+	g.Expect(engine.State()).To(HaveKeyWithValue("config.config.firmware_version", "5.3.1.0"))
+
+	g.Expect(engine.SetState("config.top_oled.font", "gamer")).To(Succeed())
+	response, ok := engine.Handle("~")
+	g.Expect(ok).To(BeTrue())
+	g.Expect(response).To(ContainSubstring("`[top_oled] font = gamer;\r\n"))
+	g.Expect(response).To(HaveSuffix("\r\nEND\r\n"))
+}
+
+func TestDump(t *testing.T) {
+	g := NewWithT(t)
+
+	c := config.NewDefaultConfig()
+	c.Hardware = &config.HardwareConfig{DACs: []config.HardwareDAC{{Channel: "DAC0", Voltage: "1.5V"}}}
+
+	// Without a personality the factory config is dumped, with the DACs at their current voltages
+	dump, err := Dump(c)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(dump).To(HavePrefix("\r\ncopy / edit / paste any of these lines \r\n"))
+	g.Expect(dump).To(ContainSubstring("`[config] firmware_version = 5.3.1.0;\r\n"))
+	g.Expect(dump).To(ContainSubstring("`[dacs] dac_0 = 1.50;\r\n"))
+	g.Expect(dump).To(HaveSuffix("\r\nEND\r\n"))
+
+	// The dump doesn't change the config of the engine
+	engine := NewJumperlessEngine()
+	g.Expect(applyPersonality(config.PersonalityFactory, engine)).To(Succeed())
+	g.Expect(engine.SetState("dac0", "2.5")).To(Succeed())
+	dump, err = dumpState(engine)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(dump).To(ContainSubstring("`[dacs] dac_0 = 2.50;\r\n"))
+	g.Expect(engine.State()).To(HaveKeyWithValue("config.dacs.dac_0", "3.33"))
+
+	_, err = dumpState(nil)
+	g.Expect(err).To(MatchError(ErrDumpUnsupported))
+}
@@ -0,0 +1,730 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package emulator
+
+import (
+	"fmt"
+	"maps"
+	"math"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/detiber/k8s-jumperless/jumperless/voltage"
+)
+
+// pythonTraceback is the first line of the error printed by the REPL when a statement raises an exception
+const pythonTraceback = "Traceback (most recent call last):\r\n"
+
+// pythonError is an exception raised by a statement, e.g. a NameError for an unknown function
+type pythonError struct {
+	kind    string
+	message string
+}
+
+func (e *pythonError) Error() string {
+	return e.kind + ": " + e.message
+}
+
+func raise(kind, format string, args ...any) error {
+	return &pythonError{kind: kind, message: fmt.Sprintf(format, args...)}
+}
+
+// pythonNode is a node constant, e.g. TOP_RAIL, printed with the name the device uses in the nets
+type pythonNode string
+
+// pythonFunction is a built-in function of the REPL
+type pythonFunction struct {
+	name string
+	call func(args []any, kwargs map[string]any) (any, error)
+}
+
+// pythonModule is a module that can be imported, e.g. time
+type pythonModule struct {
+	name  string
+	attrs map[string]any
+}
+
+// pythonInterpreter interprets the simple MicroPython statements sent to the REPL of a Jumperless against the
+// state of the engine: assignments and expressions with literals, variables, arithmetic, comparisons and calls
+// of the Jumperless API and common built-in functions. Compound statements aren't supported.
+type pythonInterpreter struct {
+	engine  *JumperlessEngine
+	globals map[string]any
+	start   time.Time
+	output  strings.Builder // Text printed by the statement being run
+}
+
+// nodeAliases maps the names of node constants and the names printed by the device to the printed names
+var nodeAliases = map[string]string{} //nolint:gochecknoglobals
+
+// pythonConstants are the constants of the Jumperless API
+var pythonConstants = map[string]any{ //nolint:gochecknoglobals
+	"HIGH": true,
+	"LOW":  false,
+}
+
+func init() { //nolint:gochecknoinits
+	nodes := map[string]string{
+		"GND":         "GND",
+		"TOP_RAIL":    "TOP_R",
+		"BOTTOM_RAIL": "BOT_R",
+		"DAC0":        "DAC_0",
+		"DAC1":        "DAC_1",
+		"UART_TX":     "UART_Tx",
+		"UART_RX":     "UART_Rx",
+	}
+	for i := 1; i <= 8; i++ {
+		nodes[fmt.Sprintf("GPIO_%d", i)] = fmt.Sprintf("GP_%d", i)
+	}
+	for i := range 5 {
+		nodes[fmt.Sprintf("ADC%d", i)] = fmt.Sprintf("ADC_%d", i)
+	}
+	for i := range 14 {
+		nodes[fmt.Sprintf("D%d", i)] = fmt.Sprintf("D%d", i)
+	}
+	for i := range 8 {
+		nodes[fmt.Sprintf("A%d", i)] = fmt.Sprintf("A%d", i)
+	}
+
+	for name, node := range nodes {
+		pythonConstants[name] = pythonNode(node)
+		nodeAliases[name] = node
+		nodeAliases[node] = node
+	}
+}
+
+// specialNets are the nets the device always prints first, along with the DAC channel setting their voltage
+var specialNets = []struct { //nolint:gochecknoglobals
+	name string
+	node string
+	dac  int // -1 for GND
+}{
+	{name: "GND", node: "GND", dac: -1},
+	{name: "Top Rail", node: "TOP_R", dac: 2},
+	{name: "Bottom Rail", node: "BOT_R", dac: 3},
+	{name: "DAC 0", node: "DAC_0", dac: 0},
+	{name: "DAC 1", node: "DAC_1", dac: 1},
+}
+
+// netColors are the colors of the nets printed after the special nets, in the order they are assigned
+var netColors = []string{ //nolint:gochecknoglobals
+	"red", "orange", "yellow", "green", "cyan", "blue", "indigo", "violet", "pink", "magenta",
+}
+
+func newPythonInterpreter(engine *JumperlessEngine) *pythonInterpreter {
+	return &pythonInterpreter{engine: engine, globals: map[string]any{}, start: time.Now()}
+}
+
+// run runs the lines of code, returning the REPL output echoing the code followed by what it printed. It
+// returns false for code with compound statements, which are left to the mappings.
+func (p *pythonInterpreter) run(code string) (string, bool) {
+	lines := strings.Split(strings.ReplaceAll(strings.TrimSpace(code), "\r", ""), "\n")
+	if slices.ContainsFunc(lines, opensBlock) {
+		return "", false
+	}
+
+	p.output.Reset()
+	p.output.WriteString("Python> " + strings.Join(lines, "\r\n") + "\r\n")
+
+	for n, line := range lines {
+		if err := p.exec(line); err != nil {
+			p.output.WriteString(pythonTraceback)
+			if err.(*pythonError).kind == "SyntaxError" { //nolint:errorlint,forcetypeassert
+				fmt.Fprintf(&p.output, "  File \"<stdin>\", line %d\r\n", n+1)
+			} else {
+				fmt.Fprintf(&p.output, "  File \"<stdin>\", line %d, in <module>\r\n", n+1)
+			}
+			p.output.WriteString(err.Error() + "\r\n")
+
+			break
+		}
+	}
+
+	return p.output.String(), true
+}
+
+// exec runs a single statement, printing the value of an expression statement unless it is None
+func (p *pythonInterpreter) exec(line string) error {
+	tokens, err := tokenizePython(line)
+	if err != nil {
+		return err
+	}
+
+	s := &pythonParser{tokens: tokens, interpreter: p}
+
+	switch {
+	case s.peek().is(tokenName, "import"):
+		s.next()
+		name := s.next()
+		if name.kind != tokenName || !s.atEnd() {
+			return raise("SyntaxError", "invalid syntax")
+		}
+
+		module, err := p.importModule(name.text)
+		if err != nil {
+			return err
+		}
+		p.globals[name.text] = module
+
+		return nil
+	case s.peek().kind == tokenName && s.peekAt(1).is(tokenOperator, "="):
+		name := s.next().text
+		s.next()
+
+		value, err := s.expression()
+		if err != nil {
+			return err
+		}
+		if !s.atEnd() {
+			return raise("SyntaxError", "invalid syntax")
+		}
+		p.globals[name] = value
+
+		return nil
+	case s.atEnd():
+		return nil
+	}
+
+	value, err := s.expression()
+	if err != nil {
+		return err
+	}
+	if !s.atEnd() {
+		return raise("SyntaxError", "invalid syntax")
+	}
+
+	if value != nil {
+		p.output.WriteString(pythonRepr(value) + "\r\n")
+	}
+
+	return nil
+}
+
+// lookup returns the value of a name, looking at the variables, the constants of the Jumperless API and the
+// built-in functions in that order
+func (p *pythonInterpreter) lookup(name string) (any, error) {
+	switch name {
+	case "True":
+		return true, nil
+	case "False":
+		return false, nil
+	case "None":
+		return nil, nil
+	}
+
+	if value, ok := p.globals[name]; ok {
+		return value, nil
+	}
+
+	if value, ok := pythonConstants[name]; ok {
+		return value, nil
+	}
+
+	if function, ok := p.builtins()[name]; ok {
+		return function, nil
+	}
+
+	return nil, raise("NameError", "name '%s' isn't defined", name)
+}
+
+// importModule returns one of the modules that can be imported
+func (p *pythonInterpreter) importModule(name string) (*pythonModule, error) {
+	if name != "time" {
+		return nil, raise("ImportError", "no module named '%s'", name)
+	}
+
+	return &pythonModule{name: name, attrs: map[string]any{
+		"ticks_ms": p.function("ticks_ms", 0, 0, func(_ []any) (any, error) {
+			return time.Since(p.start).Milliseconds(), nil
+		}),
+	}}, nil
+}
+
+// function returns a built-in function taking from min to max positional arguments and no keyword arguments
+func (p *pythonInterpreter) function(name string, minArgs, maxArgs int, call func(args []any) (any, error)) any {
+	return &pythonFunction{name: name, call: func(args []any, kwargs map[string]any) (any, error) {
+		if len(kwargs) > 0 {
+			return nil, raise("TypeError", "function doesn't take keyword arguments")
+		}
+
+		if len(args) < minArgs || len(args) > maxArgs {
+			return nil, raise("TypeError", "function takes %d positional arguments but %d were given",
+				maxArgs, len(args))
+		}
+
+		return call(args)
+	}}
+}
+
+// builtins returns the built-in functions and the functions of the Jumperless API
+func (p *pythonInterpreter) builtins() map[string]any {
+	j := p.engine
+
+	return map[string]any{
+		"print": &pythonFunction{name: "print", call: p.print},
+		"str": p.function("str", 1, 1, func(args []any) (any, error) {
+			return pythonStr(args[0]), nil
+		}),
+		"int":   p.function("int", 1, 1, func(args []any) (any, error) { return pythonInt(args[0]) }),
+		"float": p.function("float", 1, 1, func(args []any) (any, error) { return pythonFloat(args[0]) }),
+		"len": p.function("len", 1, 1, func(args []any) (any, error) {
+			switch v := args[0].(type) {
+			case string:
+				return int64(len(v)), nil
+			case []any:
+				return int64(len(v)), nil
+			}
+			return nil, raise("TypeError", "object of type '%s' has no len()", pythonType(args[0]))
+		}),
+		"abs": p.function("abs", 1, 1, func(args []any) (any, error) {
+			if v, ok := args[0].(int64); ok {
+				return max(v, -v), nil
+			}
+			v, err := pythonFloat(args[0])
+			if err != nil {
+				return nil, err
+			}
+			return math.Abs(v), nil
+		}),
+		"round": p.function("round", 1, 2, func(args []any) (any, error) {
+			v, err := pythonFloat(args[0])
+			if err != nil {
+				return nil, err
+			}
+			if len(args) == 1 {
+				return int64(math.RoundToEven(v)), nil
+			}
+			digits, err := pythonInt(args[1])
+			if err != nil {
+				return nil, err
+			}
+			scale := math.Pow(10, float64(digits))
+			return math.RoundToEven(v*scale) / scale, nil
+		}),
+		"__import__": p.function("__import__", 1, 1, func(args []any) (any, error) {
+			name, ok := args[0].(string)
+			if !ok {
+				return nil, raise("TypeError", "can't convert '%s' object to str implicitly", pythonType(args[0]))
+			}
+			return p.importModule(name)
+		}),
+
+		"dac_set": &pythonFunction{name: "dac_set", call: p.dacSet},
+		"dac_get": p.function("dac_get", 1, 1, func(args []any) (any, error) {
+			channel, err := pythonDACChannel(args[0])
+			if err != nil {
+				return nil, err
+			}
+			return j.dacs[channel], nil
+		}),
+		"adc_get": p.function("adc_get", 1, 1, func(args []any) (any, error) {
+			channel, err := pythonInt(args[0])
+			if err != nil {
+				return nil, err
+			}
+			if channel < 0 || channel >= adcCount {
+				return nil, raise("ValueError", "invalid ADC channel %d", channel)
+			}
+			return j.adcs[channel], nil
+		}),
+		"gpio_get": p.function("gpio_get", 1, 1, func(args []any) (any, error) {
+			pin, err := pythonGPIOPin(args[0])
+			if err != nil {
+				return nil, err
+			}
+			return j.gpios[pin], nil
+		}),
+		"gpio_set": p.function("gpio_set", 2, 2, func(args []any) (any, error) {
+			pin, err := pythonGPIOPin(args[0])
+			if err != nil {
+				return nil, err
+			}
+			j.gpios[pin] = pythonTruth(args[1])
+			return j.gpios[pin], nil
+		}),
+		"connect": p.function("connect", 2, 2, func(args []any) (any, error) {
+			a, b, err := pythonNodePair(args)
+			if err != nil {
+				return nil, err
+			}
+			j.connections[connectionKey(a, b)] = true
+			return true, nil
+		}),
+		"disconnect": p.function("disconnect", 2, 2, func(args []any) (any, error) {
+			a, b, err := pythonNodePair(args)
+			if err != nil {
+				return nil, err
+			}
+			delete(j.connections, connectionKey(a, b))
+			return true, nil
+		}),
+		"is_connected": p.function("is_connected", 2, 2, func(args []any) (any, error) {
+			a, b, err := pythonNodePair(args)
+			if err != nil {
+				return nil, err
+			}
+			return j.connections[connectionKey(a, b)], nil
+		}),
+		"nodes_clear": p.function("nodes_clear", 0, 0, func(_ []any) (any, error) {
+			clear(j.connections)
+			return true, nil
+		}),
+		"print_nets": p.function("print_nets", 0, 0, func(_ []any) (any, error) {
+			p.output.WriteString(j.renderNets())
+			return nil, nil
+		}),
+		"i2c_scan": p.function("i2c_scan", 2, 2, func(_ []any) (any, error) {
+			addresses := make([]any, 0, len(j.i2cDevices))
+			for _, address := range j.i2cDevices {
+				addresses = append(addresses, int64(address)) //nolint:gosec
+			}
+			return addresses, nil
+		}),
+		"oled_print": p.function("oled_print", 1, 1, func(_ []any) (any, error) {
+			return true, nil
+		}),
+	}
+}
+
+// print implements print(*args, sep=" ", end="\n")
+func (p *pythonInterpreter) print(args []any, kwargs map[string]any) (any, error) {
+	sep, end := " ", "\n"
+	for key, value := range kwargs {
+		text, ok := value.(string)
+		if !ok || (key != "sep" && key != "end") {
+			return nil, raise("TypeError", "unexpected keyword argument '%s'", key)
+		}
+		if key == "sep" {
+			sep = text
+		} else {
+			end = text
+		}
+	}
+
+	texts := make([]string, 0, len(args))
+	for _, arg := range args {
+		texts = append(texts, pythonStr(arg))
+	}
+
+	p.output.WriteString(strings.ReplaceAll(strings.Join(texts, sep)+end, "\n", "\r\n"))
+
+	return nil, nil
+}
+
+// dacSet implements dac_set(channel, voltage, save=False), saving the voltage to the config if a config is
+// loaded and save is set
+func (p *pythonInterpreter) dacSet(args []any, kwargs map[string]any) (any, error) {
+	if save, ok := kwargs["save"]; ok && len(args) == 2 {
+		args = append(args, save)
+		delete(kwargs, "save")
+	}
+	if len(kwargs) > 0 {
+		return nil, raise("TypeError", "unexpected keyword argument '%s'", slices.Sorted(maps.Keys(kwargs))[0])
+	}
+	if len(args) != 2 && len(args) != 3 {
+		return nil, raise("TypeError", "function takes 3 positional arguments but %d were given", len(args))
+	}
+
+	channel, err := pythonDACChannel(args[0])
+	if err != nil {
+		return nil, err
+	}
+
+	v, err := pythonFloat(args[1])
+	if err != nil {
+		return nil, err
+	}
+	if voltage.Round(v) < voltage.Min || voltage.Round(v) > voltage.Max {
+		return nil, raise("ValueError", "voltage must be between %s and %s", voltage.Format(voltage.Min),
+			voltage.Format(voltage.Max))
+	}
+
+	j := p.engine
+	j.dacs[channel] = voltage.Round(v)
+	if len(args) == 3 && pythonTruth(args[2]) && j.config != nil {
+		j.setConfig("dacs", dacConfigKeys[channel], fmt.Sprintf("%.2f", j.dacs[channel]))
+	}
+
+	return j.dacs[channel], nil
+}
+
+// renderNets returns the nets in the format printed by print_nets: the special nets with their voltages,
+// followed by the nets formed by the connections with their colors
+func (j *JumperlessEngine) renderNets() string {
+	// Group the connected nodes into nets
+	netOf := map[string]int{}
+	var nets [][]string
+	for _, special := range specialNets {
+		netOf[special.node] = len(nets)
+		nets = append(nets, []string{special.node})
+	}
+
+	for _, key := range slices.Sorted(maps.Keys(j.connections)) {
+		a, b, _ := strings.Cut(key, "-")
+		netA, okA := netOf[a]
+		netB, okB := netOf[b]
+
+		switch {
+		case okA && okB:
+			if netA == netB {
+				continue
+			}
+			// Merge the later net into the earlier one
+			if netB < netA {
+				netA, netB = netB, netA
+			}
+			for _, node := range nets[netB] {
+				netOf[node] = netA
+			}
+			nets[netA] = append(nets[netA], nets[netB]...)
+			nets[netB] = nil
+		case okA:
+			netOf[b] = netA
+			nets[netA] = append(nets[netA], b)
+		case okB:
+			netOf[a] = netB
+			nets[netB] = append(nets[netB], a)
+		default:
+			netOf[a], netOf[b] = len(nets), len(nets)
+			nets = append(nets, []string{a, b})
+		}
+	}
+
+	var b strings.Builder
+	b.WriteString("\r\nIndex\tName\t\tVoltage\t    Nodes\t\r\n")
+	for i, special := range specialNets {
+		v := "0"
+		if special.dac >= 0 {
+			v = voltage.FormatValue(j.dacs[special.dac])
+		}
+		fmt.Fprintf(&b, "%d\t %s%s %-11s %s\r\n", i+1, special.name, netNameSeparator(special.name), v+" V",
+			strings.Join(nets[i], ","))
+	}
+
+	index := len(specialNets)
+	for _, nodes := range nets[len(specialNets):] {
+		if len(nodes) == 0 {
+			continue
+		}
+
+		if index == len(specialNets) {
+			b.WriteString("\r\nIndex\tName\t\tColor\t    Nodes\r\n")
+		}
+
+		index++
+		name := fmt.Sprintf("Net %d", index)
+		color := netColors[(index-len(specialNets)-1)%len(netColors)]
+		fmt.Fprintf(&b, "%d\t %s%s %-11s %s\r\n", index, name, netNameSeparator(name), color,
+			strings.Join(nodes, ","))
+	}
+
+	return b.String()
+}
+
+// netNameSeparator returns the tabs printed after a net name to align the columns
+func netNameSeparator(name string) string {
+	if len(name) < 7 {
+		return "\t\t"
+	}
+
+	return "\t"
+}
+
+// pythonDACChannel returns the DAC channel of a channel number or node, e.g. 2 or TOP_RAIL
+func pythonDACChannel(v any) (int, error) {
+	switch v := v.(type) {
+	case int64:
+		if v >= 0 && v < dacCount {
+			return int(v), nil
+		}
+	case pythonNode, string:
+		node := nodeAliases[strings.ToUpper(fmt.Sprint(v))]
+		for _, special := range specialNets {
+			if special.node == node && special.dac >= 0 {
+				return special.dac, nil
+			}
+		}
+	}
+
+	return 0, raise("ValueError", "invalid DAC channel %s", pythonRepr(v))
+}
+
+// pythonGPIOPin returns the pin of a GPIO number or node, e.g. 1 or GPIO_1
+func pythonGPIOPin(v any) (string, error) {
+	switch v := v.(type) {
+	case int64:
+		return strconv.FormatInt(v, 10), nil
+	case pythonNode:
+		if pin, ok := strings.CutPrefix(string(v), "GP_"); ok {
+			return pin, nil
+		}
+	}
+
+	return "", raise("ValueError", "invalid GPIO pin %s", pythonRepr(v))
+}
+
+// pythonNodePair returns the names of the two nodes of a connection, breadboard rows are numbers and the
+// names of other nodes are printed the way the device prints them in the nets
+func pythonNodePair(args []any) (string, string, error) {
+	names := make([]string, 0, len(args))
+	for _, arg := range args {
+		switch v := arg.(type) {
+		case int64:
+			names = append(names, strconv.FormatInt(v, 10))
+		case pythonNode:
+			names = append(names, string(v))
+		case string:
+			if node, ok := nodeAliases[strings.ToUpper(v)]; ok {
+				v = node
+			}
+			names = append(names, v)
+		default:
+			return "", "", raise("ValueError", "invalid node %s", pythonRepr(arg))
+		}
+	}
+
+	return names[0], names[1], nil
+}
+
+func pythonType(v any) string {
+	switch v.(type) {
+	case nil:
+		return "NoneType"
+	case bool:
+		return "bool"
+	case int64:
+		return "int"
+	case float64:
+		return "float"
+	case string:
+		return "str"
+	case []any:
+		return "list"
+	case pythonNode:
+		return "node"
+	case *pythonFunction:
+		return "function"
+	case *pythonModule:
+		return "module"
+	default:
+		return "object"
+	}
+}
+
+// pythonRepr returns the text printed by the REPL for the value of an expression
+func pythonRepr(v any) string {
+	switch v := v.(type) {
+	case nil:
+		return "None"
+	case bool:
+		return pythonBool(v)
+	case int64:
+		return strconv.FormatInt(v, 10)
+	case float64:
+		text := strconv.FormatFloat(v, 'g', 7, 64)
+		if !strings.ContainsAny(text, ".eIN") {
+			text += ".0"
+		}
+		return text
+	case string:
+		return "'" + strings.NewReplacer(`\`, `\\`, "'", `\'`, "\n", `\n`, "\r", `\r`, "\t", `\t`).Replace(v) + "'"
+	case []any:
+		items := make([]string, 0, len(v))
+		for _, item := range v {
+			items = append(items, pythonRepr(item))
+		}
+		return "[" + strings.Join(items, ", ") + "]"
+	case pythonNode:
+		return string(v)
+	case *pythonFunction:
+		return "<function " + v.name + ">"
+	case *pythonModule:
+		return "<module '" + v.name + "'>"
+	default:
+		return fmt.Sprint(v)
+	}
+}
+
+// pythonStr returns the text printed by print for a value
+func pythonStr(v any) string {
+	if text, ok := v.(string); ok {
+		return text
+	}
+
+	return pythonRepr(v)
+}
+
+func pythonTruth(v any) bool {
+	switch v := v.(type) {
+	case nil:
+		return false
+	case bool:
+		return v
+	case int64:
+		return v != 0
+	case float64:
+		return v != 0
+	case string:
+		return v != ""
+	case []any:
+		return len(v) > 0
+	default:
+		return true
+	}
+}
+
+func pythonInt(v any) (int64, error) {
+	switch v := v.(type) {
+	case bool:
+		if v {
+			return 1, nil
+		}
+		return 0, nil
+	case int64:
+		return v, nil
+	case float64:
+		return int64(v), nil
+	case string:
+		i, err := strconv.ParseInt(strings.TrimSpace(v), 10, 64)
+		if err != nil {
+			return 0, raise("ValueError", "invalid syntax for integer with base 10: %s", pythonRepr(v))
+		}
+		return i, nil
+	}
+
+	return 0, raise("TypeError", "can't convert %s to int", pythonType(v))
+}
+
+func pythonFloat(v any) (float64, error) {
+	switch v := v.(type) {
+	case float64:
+		return v, nil
+	case string:
+		f, err := strconv.ParseFloat(strings.TrimSpace(v), 64)
+		if err != nil {
+			return 0, raise("ValueError", "could not convert string to float: %s", pythonRepr(v))
+		}
+		return f, nil
+	case bool, int64:
+		i, _ := pythonInt(v)
+		return float64(i), nil
+	}
+
+	return 0, raise("TypeError", "can't convert %s to float", pythonType(v))
+}
@@ -0,0 +1,96 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package emulator
+
+import (
+	"testing"
+
+	. "github.com/onsi/gomega"
+)
+
+func TestPythonEngine(t *testing.T) {
+	traceback := func(exception string) string {
+		return pythonTraceback + "  File \"<stdin>\", line 1, in <module>\r\n" + exception + "\r\n"
+	}
+
+	// The statements run in order against the same engine
+	tests := []struct {
+		request  string
+		response string
+		ignored  bool
+	}{
+		{request: ">dac_set(TOP_RAIL, 3.3)", response: "3.3\r\n"},
+		{request: ">x = dac_get(2) * 2"},
+		{request: `>print("x is", x, sep=": ")`, response: "x is: 6.6\r\n"},
+		{request: ">gpio_set(GPIO_1, HIGH)", response: "True\r\n"},
+		{request: ">gpio_get(1)", response: "True\r\n"},
+		{request: ">connect(TOP_RAIL, 5)", response: "True\r\n"},
+		{request: `>connect("D1", 5)`, response: "True\r\n"},
+		{request: ">connect(10, 11)", response: "True\r\n"},
+		{request: `>is_connected(5, "TOP_RAIL")`, response: "True\r\n"},
+		{
+			request: ">print_nets()",
+			response: "\r\nIndex\tName\t\tVoltage\t    Nodes\t\r\n" +
+				"1\t GND\t\t 0 V         GND\r\n" +
+				"2\t Top Rail\t 3.30 V      TOP_R,5,D1\r\n" +
+				"3\t Bottom Rail\t 0.00 V      BOT_R\r\n" +
+				"4\t DAC 0\t\t 0.00 V      DAC_0\r\n" +
+				"5\t DAC 1\t\t 0.00 V      DAC_1\r\n" +
+				"\r\nIndex\tName\t\tColor\t    Nodes\r\n" +
+				"6\t Net 6\t\t red         10,11\r\n",
+		},
+		{request: ">__import__('time').ticks_ms() >= 0", response: "True\r\n"},
+		{request: ">import time"},
+		{
+			request:  ">[1, 2.5, 'a', None, 7 // 2, -7 % 3, 2 ** 3, len('abc')]",
+			response: "[1, 2.5, 'a', None, 3, 2, 8, 3]\r\n",
+		},
+		{request: ">[(-3) ** 3, 2 ** 62, 1 ** 10000000000]", response: "[-27, 4611686018427387904, 1]\r\n"},
+		{request: ">2 ** 10000000000", response: traceback("OverflowError: result too large")},
+		{request: ">[len('ab' * 3), 'a' * -1]", response: "[6, '']\r\n"},
+		{request: ">'a' * 10 ** 10", response: traceback("MemoryError: memory allocation failed")},
+		{request: ">foo(1)", response: traceback("NameError: name 'foo' isn't defined")},
+		{request: ">dac_set(0, 9)", response: traceback("ValueError: voltage must be between -8.00V and 8.00V")},
+		{request: ">i2c_scan(20, 21)[0]", response: traceback("IndexError: index out of range")},
+		{
+			request:  ">dac_get(",
+			response: pythonTraceback + "  File \"<stdin>\", line 1\r\nSyntaxError: invalid syntax\r\n",
+		},
+		{request: ">for i in range(4):\n    print(i)", ignored: true},
+	}
+
+	engine := NewPythonEngine()
+	for _, tt := range tests {
+		t.Run(tt.request, func(t *testing.T) {
+			g := NewWithT(t)
+
+			response, ok := engine.Handle(tt.request)
+			if tt.ignored {
+				g.Expect(ok).To(BeFalse())
+				return
+			}
+
+			g.Expect(ok).To(BeTrue())
+			g.Expect(response).To(Equal("Python> " + tt.request[1:] + "\r\n" + tt.response))
+		})
+	}
+
+	g := NewWithT(t)
+	g.Expect(engine.State()).To(HaveKeyWithValue("dac2", "3.30"))
+	g.Expect(engine.State()).To(HaveKeyWithValue("gpio1", "True"))
+	g.Expect(engine.State()).To(HaveKeyWithValue("connections", "10-11,5-D1,5-TOP_R"))
+}
@@ -0,0 +1,657 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package emulator
+
+import (
+	"cmp"
+	"math"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// maxStringLength is the longest string a statement may build, longer strings raise a MemoryError like they
+// would on the device instead of exhausting the memory of the emulator
+const maxStringLength = 64 * 1024
+
+type tokenKind int
+
+const (
+	tokenEnd tokenKind = iota
+	tokenName
+	tokenNumber
+	tokenString
+	tokenOperator
+)
+
+// pythonToken is a token of a MicroPython statement, value holds the value of number and string literals
+type pythonToken struct {
+	kind  tokenKind
+	text  string
+	value any
+}
+
+func (t pythonToken) is(kind tokenKind, text string) bool {
+	return t.kind == kind && t.text == text
+}
+
+// pythonOperators are the operators and delimiters of the statements, longest first
+var pythonOperators = []string{ //nolint:gochecknoglobals
+	"==", "!=", "<=", ">=", "//", "**",
+	"(", ")", "[", "]", ",", ".", "=", "+", "-", "*", "/", "%", "<", ">",
+}
+
+// operatorMethods are the names of the methods implementing the binary operators, used in error messages
+var operatorMethods = map[string]string{ //nolint:gochecknoglobals
+	"+": "__add__", "-": "__sub__", "*": "__mul__", "/": "__truediv__", "//": "__floordiv__", "%": "__mod__",
+	"**": "__pow__", "<": "__lt__", ">": "__gt__", "<=": "__le__", ">=": "__ge__",
+}
+
+// tokenizePython splits a line of MicroPython into tokens, ending with a tokenEnd token
+func tokenizePython(line string) ([]pythonToken, error) {
+	tokens := []pythonToken{}
+
+	for i := 0; i < len(line); {
+		c := line[i]
+		switch {
+		case c == ' ' || c == '\t':
+			i++
+		case c == '#':
+			i = len(line)
+		case isDigit(c) || (c == '.' && i+1 < len(line) && isDigit(line[i+1])):
+			start := i
+			for i < len(line) && (isNameChar(line[i]) || line[i] == '.' ||
+				((line[i] == '+' || line[i] == '-') && (line[i-1] == 'e' || line[i-1] == 'E') &&
+					!strings.HasPrefix(strings.ToLower(line[start:]), "0x"))) {
+				i++
+			}
+
+			value, err := parsePythonNumber(line[start:i])
+			if err != nil {
+				return nil, err
+			}
+			tokens = append(tokens, pythonToken{kind: tokenNumber, text: line[start:i], value: value})
+		case isNameChar(c):
+			start := i
+			for i < len(line) && isNameChar(line[i]) {
+				i++
+			}
+			tokens = append(tokens, pythonToken{kind: tokenName, text: line[start:i]})
+		case c == '\'' || c == '"':
+			text, n, err := parsePythonString(line[i:])
+			if err != nil {
+				return nil, err
+			}
+			tokens = append(tokens, pythonToken{kind: tokenString, text: line[i : i+n], value: text})
+			i += n
+		default:
+			operator := ""
+			for _, op := range pythonOperators {
+				if strings.HasPrefix(line[i:], op) {
+					operator = op
+					break
+				}
+			}
+			if operator == "" {
+				return nil, raise("SyntaxError", "invalid syntax")
+			}
+			tokens = append(tokens, pythonToken{kind: tokenOperator, text: operator})
+			i += len(operator)
+		}
+	}
+
+	return append(tokens, pythonToken{kind: tokenEnd}), nil
+}
+
+func isDigit(c byte) bool {
+	return c >= '0' && c <= '9'
+}
+
+func isNameChar(c byte) bool {
+	return c == '_' || isDigit(c) || (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z')
+}
+
+// parsePythonNumber parses an integer or float literal, e.g. 42, 0x3C or 3.3
+func parsePythonNumber(text string) (any, error) {
+	lower := strings.ToLower(text)
+	if !strings.HasPrefix(lower, "0x") && strings.ContainsAny(lower, ".e") {
+		if f, err := strconv.ParseFloat(text, 64); err == nil {
+			return f, nil
+		}
+	} else if i, err := strconv.ParseInt(strings.ReplaceAll(text, "_", ""), 0, 64); err == nil {
+		return i, nil
+	}
+
+	return nil, raise("SyntaxError", "invalid syntax for number")
+}
+
+// parsePythonString parses the string literal at the start of text, returning its value and length
+func parsePythonString(text string) (string, int, error) {
+	quote := text[0]
+	var value strings.Builder
+
+	for i := 1; i < len(text); i++ {
+		c := text[i]
+		switch {
+		case c == quote:
+			return value.String(), i + 1, nil
+		case c == '\\' && i+1 < len(text):
+			i++
+			switch text[i] {
+			case 'n':
+				value.WriteByte('\n')
+			case 'r':
+				value.WriteByte('\r')
+			case 't':
+				value.WriteByte('\t')
+			case '0':
+				value.WriteByte(0)
+			case '\\', '\'', '"':
+				value.WriteByte(text[i])
+			default:
+				value.WriteByte('\\')
+				value.WriteByte(text[i])
+			}
+		default:
+			value.WriteByte(c)
+		}
+	}
+
+	return "", 0, raise("SyntaxError", "unterminated string")
+}
+
+// pythonParser evaluates the tokens of a statement as it parses them, calling the functions of the
+// interpreter in the order they appear
+type pythonParser struct {
+	tokens      []pythonToken
+	pos         int
+	interpreter *pythonInterpreter
+}
+
+func (s *pythonParser) peek() pythonToken {
+	return s.peekAt(0)
+}
+
+func (s *pythonParser) peekAt(n int) pythonToken {
+	if s.pos+n >= len(s.tokens) {
+		return s.tokens[len(s.tokens)-1]
+	}
+
+	return s.tokens[s.pos+n]
+}
+
+func (s *pythonParser) next() pythonToken {
+	t := s.tokens[s.pos]
+	if t.kind != tokenEnd {
+		s.pos++
+	}
+
+	return t
+}
+
+func (s *pythonParser) atEnd() bool {
+	return s.peek().kind == tokenEnd
+}
+
+// accept consumes the operator if it is next
+func (s *pythonParser) accept(operator string) bool {
+	if s.peek().is(tokenOperator, operator) {
+		s.next()
+		return true
+	}
+
+	return false
+}
+
+func (s *pythonParser) expect(operator string) error {
+	if !s.accept(operator) {
+		return raise("SyntaxError", "invalid syntax")
+	}
+
+	return nil
+}
+
+// expression parses an arithmetic expression optionally compared to another one
+func (s *pythonParser) expression() (any, error) {
+	left, err := s.arithmetic()
+	if err != nil {
+		return nil, err
+	}
+
+	op := s.peek()
+	if op.kind != tokenOperator || !slices.Contains([]string{"==", "!=", "<", ">", "<=", ">="}, op.text) {
+		return left, nil
+	}
+	s.next()
+
+	right, err := s.arithmetic()
+	if err != nil {
+		return nil, err
+	}
+
+	return pythonCompare(op.text, left, right)
+}
+
+func (s *pythonParser) arithmetic() (any, error) {
+	return s.binary(s.term, "+", "-")
+}
+
+func (s *pythonParser) term() (any, error) {
+	return s.binary(s.unary, "*", "/", "//", "%")
+}
+
+// binary parses operands joined by the left-associative operators
+func (s *pythonParser) binary(operand func() (any, error), operators ...string) (any, error) {
+	left, err := operand()
+	if err != nil {
+		return nil, err
+	}
+
+	for op := s.peek(); op.kind == tokenOperator && slices.Contains(operators, op.text); op = s.peek() {
+		s.next()
+
+		right, err := operand()
+		if err != nil {
+			return nil, err
+		}
+
+		if left, err = pythonBinary(op.text, left, right); err != nil {
+			return nil, err
+		}
+	}
+
+	return left, nil
+}
+
+func (s *pythonParser) unary() (any, error) {
+	switch {
+	case s.accept("-"):
+		v, err := s.unary()
+		if err != nil {
+			return nil, err
+		}
+		return pythonBinary("-", int64(0), v)
+	case s.accept("+"):
+		return s.unary()
+	}
+
+	base, err := s.primary()
+	if err != nil {
+		return nil, err
+	}
+
+	if !s.accept("**") {
+		return base, nil
+	}
+
+	exponent, err := s.unary()
+	if err != nil {
+		return nil, err
+	}
+
+	return pythonBinary("**", base, exponent)
+}
+
+// primary parses an atom followed by any number of calls, attribute references and subscriptions
+func (s *pythonParser) primary() (any, error) {
+	value, err := s.atom()
+	if err != nil {
+		return nil, err
+	}
+
+	for {
+		switch {
+		case s.accept("("):
+			args, kwargs, err := s.arguments()
+			if err != nil {
+				return nil, err
+			}
+
+			function, ok := value.(*pythonFunction)
+			if !ok {
+				return nil, raise("TypeError", "'%s' object isn't callable", pythonType(value))
+			}
+
+			if value, err = function.call(args, kwargs); err != nil {
+				return nil, err
+			}
+		case s.accept("."):
+			name := s.next()
+			if name.kind != tokenName {
+				return nil, raise("SyntaxError", "invalid syntax")
+			}
+
+			module, ok := value.(*pythonModule)
+			if !ok || module.attrs[name.text] == nil {
+				return nil, raise("AttributeError", "'%s' object has no attribute '%s'", pythonType(value), name.text)
+			}
+			value = module.attrs[name.text]
+		case s.accept("["):
+			index, err := s.expression()
+			if err != nil {
+				return nil, err
+			}
+			if err := s.expect("]"); err != nil {
+				return nil, err
+			}
+
+			if value, err = pythonIndex(value, index); err != nil {
+				return nil, err
+			}
+		default:
+			return value, nil
+		}
+	}
+}
+
+// arguments parses the positional and keyword arguments of a call up to the closing parenthesis
+func (s *pythonParser) arguments() ([]any, map[string]any, error) {
+	args := []any{}
+	kwargs := map[string]any{}
+
+	for !s.peek().is(tokenOperator, ")") {
+		if s.peek().kind == tokenName && s.peekAt(1).is(tokenOperator, "=") {
+			name := s.next().text
+			s.next()
+
+			value, err := s.expression()
+			if err != nil {
+				return nil, nil, err
+			}
+			kwargs[name] = value
+		} else {
+			if len(kwargs) > 0 {
+				return nil, nil, raise("SyntaxError", "positional argument follows keyword argument")
+			}
+
+			value, err := s.expression()
+			if err != nil {
+				return nil, nil, err
+			}
+			args = append(args, value)
+		}
+
+		if !s.accept(",") {
+			break
+		}
+	}
+
+	if err := s.expect(")"); err != nil {
+		return nil, nil, err
+	}
+
+	return args, kwargs, nil
+}
+
+// atom parses a literal, a name, a parenthesized expression or a list
+func (s *pythonParser) atom() (any, error) {
+	t := s.next()
+
+	switch t.kind {
+	case tokenNumber, tokenString:
+		return t.value, nil
+	case tokenName:
+		return s.interpreter.lookup(t.text)
+	case tokenOperator:
+		switch t.text {
+		case "(":
+			value, err := s.expression()
+			if err != nil {
+				return nil, err
+			}
+			if err := s.expect(")"); err != nil {
+				return nil, err
+			}
+			return value, nil
+		case "[":
+			items := []any{}
+			for !s.peek().is(tokenOperator, "]") {
+				item, err := s.expression()
+				if err != nil {
+					return nil, err
+				}
+				items = append(items, item)
+
+				if !s.accept(",") {
+					break
+				}
+			}
+			if err := s.expect("]"); err != nil {
+				return nil, err
+			}
+			return items, nil
+		}
+	}
+
+	return nil, raise("SyntaxError", "invalid syntax")
+}
+
+// pythonNumber returns a numeric value as an int64 or float64, booleans are integers like in Python
+func pythonNumber(v any) (any, bool) {
+	switch v := v.(type) {
+	case bool:
+		if v {
+			return int64(1), true
+		}
+		return int64(0), true
+	case int64, float64:
+		return v, true
+	}
+
+	return nil, false
+}
+
+// pythonBinary applies an arithmetic operator to two values
+func pythonBinary(op string, a, b any) (any, error) {
+	x, okA := pythonNumber(a)
+	y, okB := pythonNumber(b)
+	if okA && okB {
+		i, intA := x.(int64)
+		k, intB := y.(int64)
+		if intA && intB && op != "/" && (op != "**" || k >= 0) {
+			return integerBinary(op, i, k)
+		}
+
+		f, _ := pythonFloat(x)
+		g, _ := pythonFloat(y)
+		return floatBinary(op, f, g)
+	}
+
+	switch a := a.(type) {
+	case string:
+		if text, ok := b.(string); ok && op == "+" {
+			return a + text, nil
+		}
+		if n, ok := b.(int64); ok && op == "*" {
+			if len(a) > 0 && n > int64(maxStringLength/len(a)) {
+				return nil, raise("MemoryError", "memory allocation failed")
+			}
+			return strings.Repeat(a, int(max(n, 0))), nil
+		}
+	case []any:
+		if items, ok := b.([]any); ok && op == "+" {
+			return append(slices.Clone(a), items...), nil
+		}
+	}
+
+	return nil, raise("TypeError", "unsupported types for %s: '%s', '%s'", operatorMethods[op], pythonType(a),
+		pythonType(b))
+}
+
+func integerBinary(op string, a, b int64) (any, error) {
+	switch op {
+	case "+":
+		return a + b, nil
+	case "-":
+		return a - b, nil
+	case "*":
+		return a * b, nil
+	case "**":
+		return integerPower(a, b)
+	}
+
+	if b == 0 {
+		return nil, raise("ZeroDivisionError", "divide by zero")
+	}
+
+	// Python rounds integer division towards negative infinity, the remainder has the sign of the divisor
+	quotient, remainder := a/b, a%b
+	if remainder != 0 && (remainder < 0) != (b < 0) {
+		quotient--
+		remainder += b
+	}
+
+	if op == "//" {
+		return quotient, nil
+	}
+
+	return remainder, nil
+}
+
+// integerPower raises a to the non-negative power b by squaring, results that don't fit 64 bits raise an
+// OverflowError rather than wrapping around
+func integerPower(a, b int64) (any, error) {
+	result := int64(1)
+	for b > 0 {
+		var ok bool
+		if b&1 == 1 {
+			if result, ok = multiplyInt64(result, a); !ok {
+				return nil, raise("OverflowError", "result too large")
+			}
+		}
+
+		b >>= 1
+		if b > 0 {
+			if a, ok = multiplyInt64(a, a); !ok {
+				return nil, raise("OverflowError", "result too large")
+			}
+		}
+	}
+
+	return result, nil
+}
+
+// multiplyInt64 multiplies two integers, reporting whether the product fits 64 bits
+func multiplyInt64(a, b int64) (int64, bool) {
+	if a == 0 || b == 0 {
+		return 0, true
+	}
+
+	product := a * b
+	if product/b != a || (a == -1 && b == math.MinInt64) || (b == -1 && a == math.MinInt64) {
+		return 0, false
+	}
+
+	return product, true
+}
+
+func floatBinary(op string, a, b float64) (any, error) {
+	switch op {
+	case "+":
+		return a + b, nil
+	case "-":
+		return a - b, nil
+	case "*":
+		return a * b, nil
+	case "**":
+		return math.Pow(a, b), nil
+	}
+
+	if b == 0 {
+		return nil, raise("ZeroDivisionError", "divide by zero")
+	}
+
+	switch op {
+	case "/":
+		return a / b, nil
+	case "//":
+		return math.Floor(a / b), nil
+	default:
+		return a - b*math.Floor(a/b), nil
+	}
+}
+
+// pythonCompare compares two values, numbers are compared by value and strings lexically
+func pythonCompare(op string, a, b any) (any, error) {
+	var order int
+	x, okA := pythonNumber(a)
+	y, okB := pythonNumber(b)
+	textA, strA := a.(string)
+	textB, strB := b.(string)
+
+	switch {
+	case okA && okB:
+		f, _ := pythonFloat(x)
+		g, _ := pythonFloat(y)
+		order = cmp.Compare(f, g)
+	case strA && strB:
+		order = strings.Compare(textA, textB)
+	case op == "==" || op == "!=":
+		equal := pythonRepr(a) == pythonRepr(b) && pythonType(a) == pythonType(b)
+		return equal == (op == "=="), nil
+	default:
+		return nil, raise("TypeError", "unsupported types for %s: '%s', '%s'", operatorMethods[op],
+			pythonType(a), pythonType(b))
+	}
+
+	switch op {
+	case "==":
+		return order == 0, nil
+	case "!=":
+		return order != 0, nil
+	case "<":
+		return order < 0, nil
+	case ">":
+		return order > 0, nil
+	case "<=":
+		return order <= 0, nil
+	default:
+		return order >= 0, nil
+	}
+}
+
+// pythonIndex returns the item of a list or the character of a string at the index, negative indexes count
+// from the end
+func pythonIndex(v, index any) (any, error) {
+	i, ok := index.(int64)
+	if !ok {
+		return nil, raise("TypeError", "indices must be integers, not %s", pythonType(index))
+	}
+
+	var length int64
+	switch v := v.(type) {
+	case string:
+		length = int64(len(v))
+	case []any:
+		length = int64(len(v))
+	default:
+		return nil, raise("TypeError", "'%s' object isn't subscriptable", pythonType(v))
+	}
+
+	if i < 0 {
+		i += length
+	}
+	if i < 0 || i >= length {
+		return nil, raise("IndexError", "index out of range")
+	}
+
+	if text, ok := v.(string); ok {
+		return text[i : i+1], nil
+	}
+
+	return v.([]any)[i], nil //nolint:forcetypeassert
+}
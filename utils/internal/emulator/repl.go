@@ -0,0 +1,234 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package emulator
+
+import (
+	"strconv"
+	"strings"
+)
+
+const (
+	// continuationPrompt is printed by the REPL while a multi-line statement is being entered
+	continuationPrompt = "... "
+
+	// pastePrompt prefixes the lines echoed by the REPL in paste mode
+	pastePrompt = "=== "
+
+	// pasteBanner is printed by the REPL when entering paste mode
+	pasteBanner = "\r\npaste mode; Ctrl-C to cancel, Ctrl-D to finish\r\n" + pastePrompt
+
+	// REPL control characters
+	replInterrupt  = "\x03" // Ctrl-C cancels the statement being entered
+	replPasteEnter = "\x05" // Ctrl-E enters paste mode
+	replPasteEnd   = "\x04" // Ctrl-D ends paste mode, running the pasted block
+)
+
+// replInput accumulates multi-line MicroPython input the way the REPL of the device does, so a compound
+// statement or pasted block is answered as a single request once it is complete. Statements opening a block,
+// e.g. "for i in range(4):", are continued until an empty line, pasted blocks until Ctrl-D.
+type replInput struct {
+	lines   []string // The lines of the statement or block being entered
+	pending string   // The last line read, until its line ending is read
+	paste   bool     // Set while in paste mode
+}
+
+// feed adds the data read from the client, returning the prompts or echo to write back and the requests
+// completed by it. Data that is not part of a multi-line statement is returned as a request unchanged.
+func (r *replInput) feed(data string) (string, []string) {
+	if !r.active() && !strings.Contains(data, replPasteEnter) && !opensBlock(firstLine(data)) {
+		return "", []string{data}
+	}
+
+	var output strings.Builder
+	var requests []string
+
+	// Paste mode data is split at the control characters, the lines in between are handled one at a time
+	for len(data) > 0 {
+		i := strings.IndexAny(data, replInterrupt+replPasteEnter+replPasteEnd)
+		text := data
+		if i >= 0 {
+			text = data[:i]
+		}
+
+		var lines []string
+		lines, r.pending = splitLines(r.pending + text)
+		for _, line := range lines {
+			if request, complete := r.line(line, &output); complete {
+				requests = append(requests, request)
+			}
+		}
+
+		if i < 0 {
+			break
+		}
+
+		switch data[i : i+1] {
+		case replInterrupt:
+			r.reset()
+		case replPasteEnter:
+			r.reset()
+			r.paste = true
+			output.WriteString(pasteBanner)
+		case replPasteEnd:
+			if r.paste {
+				// A last line without a line ending is part of the pasted block
+				if r.pending != "" {
+					r.lines = append(r.lines, r.pending)
+				}
+
+				requests = append(requests, strings.Join(r.lines, "\n"))
+				output.WriteString("\r\n")
+				r.reset()
+			}
+		}
+
+		data = data[i+1:]
+	}
+
+	return output.String(), requests
+}
+
+// line handles a single line of input, returning the complete statement once it ends
+func (r *replInput) line(line string, output *strings.Builder) (string, bool) {
+	if r.paste {
+		r.lines = append(r.lines, line)
+		output.WriteString(line + "\r\n" + pastePrompt)
+		return "", false
+	}
+
+	if len(r.lines) == 0 {
+		if !opensBlock(line) {
+			// A single line statement outside of a block, e.g. following the end of a block in the same read
+			return line, strings.TrimSpace(line) != ""
+		}
+
+		r.lines = append(r.lines, line)
+		output.WriteString(continuationPrompt)
+		return "", false
+	}
+
+	// An empty line ends the block
+	if strings.TrimSpace(line) == "" {
+		request := strings.Join(r.lines, "\n")
+		r.reset()
+		return request, true
+	}
+
+	r.lines = append(r.lines, line)
+	output.WriteString(continuationPrompt)
+	return "", false
+}
+
+// active reports whether a multi-line statement or pasted block is being entered
+func (r *replInput) active() bool {
+	return r.paste || len(r.lines) > 0 || r.pending != ""
+}
+
+func (r *replInput) reset() {
+	r.lines = nil
+	r.pending = ""
+	r.paste = false
+}
+
+// opensBlock reports whether the line starts a compound statement, e.g. "def f():" or "if x: # comment"
+func opensBlock(line string) bool {
+	code, _, _ := strings.Cut(line, "#")
+	return strings.HasSuffix(strings.TrimSpace(code), ":")
+}
+
+// firstLine returns the first line of data
+func firstLine(data string) string {
+	line, _, _ := strings.Cut(strings.ReplaceAll(data, "\r", "\n"), "\n")
+	return line
+}
+
+// splitLines splits text into lines ending with a carriage return, newline or both, along with the last line
+// without a line ending, which the client may still be typing.
+func splitLines(text string) ([]string, string) {
+	text = strings.ReplaceAll(text, "\r\n", "\n")
+	text = strings.ReplaceAll(text, "\r", "\n")
+
+	lines := strings.Split(text, "\n")
+	return lines[:len(lines)-1], lines[len(lines)-1]
+}
+
+// splitStatements splits a single MicroPython line of statements joined with semicolons, e.g. a batch of
+// commands sent by the jumperless library, into one request per statement. Semicolons within strings or
+// brackets don't separate statements. It returns false for lines holding a single statement.
+func splitStatements(request string) ([]string, bool) {
+	if !strings.HasPrefix(request, ">") || strings.Contains(request, "\n") {
+		return nil, false
+	}
+
+	statements := []string{}
+	depth := 0
+	var quote rune
+	start := 1
+
+	for i, c := range request {
+		switch {
+		case quote != 0:
+			if c == quote && request[i-1] != '\\' {
+				quote = 0
+			}
+		case c == '\'' || c == '"':
+			quote = c
+		case c == '(' || c == '[' || c == '{':
+			depth++
+		case c == ')' || c == ']' || c == '}':
+			depth--
+		case c == ';' && depth == 0:
+			statements = append(statements, request[start:i])
+			start = i + 1
+		}
+	}
+	statements = append(statements, request[start:])
+
+	requests := []string{}
+	for _, statement := range statements {
+		if trimmed := strings.TrimSpace(statement); trimmed != "" {
+			requests = append(requests, ">"+trimmed)
+		}
+	}
+
+	return requests, len(requests) > 1
+}
+
+// printedLiteral returns the text printed by a statement printing a string literal, e.g. ">print('done')"
+func printedLiteral(request string) (string, bool) {
+	argument, ok := strings.CutPrefix(request, ">print(")
+	if !ok {
+		return "", false
+	}
+
+	argument, ok = strings.CutSuffix(argument, ")")
+	if !ok {
+		return "", false
+	}
+
+	// Python strings may also be single-quoted
+	if len(argument) >= 2 && argument[0] == '\'' && argument[len(argument)-1] == '\'' {
+		return argument[1 : len(argument)-1], true
+	}
+
+	text, err := strconv.Unquote(argument)
+	if err != nil {
+		return "", false
+	}
+
+	return text, true
+}
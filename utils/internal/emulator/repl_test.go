@@ -0,0 +1,181 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package emulator
+
+import (
+	"context"
+	"log/slog"
+	"path/filepath"
+	"strconv"
+	"testing"
+	"time"
+
+	. "github.com/onsi/gomega"
+
+	"github.com/detiber/k8s-jumperless/jumperless"
+	"github.com/detiber/k8s-jumperless/utils/internal/emulator/config"
+)
+
+func TestReplInput(t *testing.T) {
+	tests := []struct {
+		name     string
+		reads    []string
+		echo     string
+		requests []string
+	}{
+		{
+			name:     "single line",
+			reads:    []string{">dac_get(0)"},
+			requests: []string{">dac_get(0)"},
+		},
+		{
+			name:     "block typed line by line",
+			reads:    []string{"for i in range(2):\r", "    print(i)\r", "\r"},
+			echo:     continuationPrompt + continuationPrompt,
+			requests: []string{"for i in range(2):\n    print(i)"},
+		},
+		{
+			name:     "block in a single read",
+			reads:    []string{"def f(): # comment\r\n    return 1\r\n\r\nf()\r\n"},
+			echo:     continuationPrompt + continuationPrompt,
+			requests: []string{"def f(): # comment\n    return 1", "f()"},
+		},
+		{
+			name:     "line split across reads",
+			reads:    []string{"if True:", "\r  x = 1\r", "\r"},
+			echo:     continuationPrompt + continuationPrompt,
+			requests: []string{"if True:\n  x = 1"},
+		},
+		{
+			name:     "paste mode",
+			reads:    []string{"\x05for i in range(2):\r    print(i)\r\x04"},
+			echo:     pasteBanner + "for i in range(2):\r\n" + pastePrompt + "    print(i)\r\n" + pastePrompt + "\r\n",
+			requests: []string{"for i in range(2):\n    print(i)"},
+		},
+		{
+			name:     "interrupted block",
+			reads:    []string{"while True:\r", "\x03", ">dac_get(1)"},
+			echo:     continuationPrompt,
+			requests: []string{">dac_get(1)"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			repl := replInput{}
+			echo := ""
+			requests := []string{}
+			for _, read := range tt.reads {
+				e, r := repl.feed(read)
+				echo += e
+				requests = append(requests, r...)
+			}
+
+			g.Expect(echo).To(Equal(tt.echo))
+			g.Expect(requests).To(Equal(tt.requests))
+		})
+	}
+}
+
+func TestPasteModeSnippet(t *testing.T) {
+	g := NewWithT(t)
+
+	mappings := config.Mappings{}
+	mappings.AddResponse("?", config.ResponseOption{Chunks: []config.ResponseChunk{
+		{Data: strconv.Quote("Jumperless firmware version: 5.3.1.0\r\n")},
+	}})
+	mappings.AddResponse("for i in range(2):\n    print(i)", config.ResponseOption{Chunks: []config.ResponseChunk{
+		{Data: strconv.Quote("0\r\n1\r\nPython> ")},
+	}})
+
+	c := config.NewDefaultConfig()
+	c.VirtualPort = filepath.Join(t.TempDir(), "jumperless")
+	c.Mappings = mappings
+	c.Seed = 1
+
+	e, err := New(c, slog.New(slog.NewTextHandler(t.Output(), nil)))
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(e.Start(t.Context())).To(Succeed())
+	t.Cleanup(func() { g.Expect(e.Stop()).To(Succeed()) })
+
+	j, err := jumperless.NewJumperless(t.Context(), e.GetPortName(), 0)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(j.OpenPort()).To(Succeed())
+	t.Cleanup(func() { g.Expect(j.ClosePort()).To(Succeed()) })
+
+	ctx, cancel := context.WithTimeout(t.Context(), 5*time.Second)
+	defer cancel()
+
+	output, err := j.ExecPythonPaste(ctx, `
+		for i in range(2):
+		    print(i)
+	`)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(output).To(Equal("0\n1"))
+}
+
+func TestBatch(t *testing.T) {
+	g := NewWithT(t)
+
+	mappings := config.Mappings{}
+	mappings.AddResponse("?", config.ResponseOption{Chunks: []config.ResponseChunk{
+		{Data: strconv.Quote("Jumperless firmware version: 5.3.1.0\r\n")},
+	}})
+	mappings.AddResponse(">print_nets()", config.ResponseOption{Chunks: []config.ResponseChunk{
+		{Data: strconv.Quote("Python> >print_nets()\r\nIndex\tName\r\n1\tGND\r\n")},
+	}})
+
+	c := config.NewDefaultConfig()
+	c.VirtualPort = filepath.Join(t.TempDir(), "jumperless")
+	c.Mappings = mappings
+	c.Engine = EngineJumperless
+	c.Seed = 1
+
+	e, err := New(c, slog.New(slog.NewTextHandler(t.Output(), nil)))
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(e.Start(t.Context())).To(Succeed())
+	t.Cleanup(func() { g.Expect(e.Stop()).To(Succeed()) })
+
+	j, err := jumperless.NewJumperless(t.Context(), e.GetPortName(), 0)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(j.OpenPort()).To(Succeed())
+	t.Cleanup(func() { g.Expect(j.ClosePort()).To(Succeed()) })
+
+	outputs, err := j.ExecPythonBatch([]string{"dac_set(1, 2.5)", "dac_get(1)", "print_nets()"})
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(outputs).To(Equal([]string{"2.50", "2.50", "Index\tName\n1\tGND"}))
+}
+
+func TestSplitStatements(t *testing.T) {
+	g := NewWithT(t)
+
+	statements, ok := splitStatements(`>print("a;b"); dac_get(0); f((1, ";"))`)
+	g.Expect(ok).To(BeTrue())
+	g.Expect(statements).To(Equal([]string{`>print("a;b")`, ">dac_get(0)", `>f((1, ";"))`}))
+
+	_, ok = splitStatements(">dac_get(0)")
+	g.Expect(ok).To(BeFalse())
+
+	text, ok := printedLiteral(`>print("--batch-0")`)
+	g.Expect(ok).To(BeTrue())
+	g.Expect(text).To(Equal("--batch-0"))
+
+	_, ok = printedLiteral(">print(dac_get(0))")
+	g.Expect(ok).To(BeFalse())
+}
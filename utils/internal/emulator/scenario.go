@@ -0,0 +1,206 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package emulator
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/detiber/k8s-jumperless/jumperless/voltage"
+	"github.com/detiber/k8s-jumperless/utils/internal/emulator/config"
+	"github.com/detiber/k8s-jumperless/utils/internal/logging"
+)
+
+var ErrInvalidScenario = errors.New("invalid scenario")
+
+// rampStep is the time between updates of a ramping value
+const rampStep = 100 * time.Millisecond
+
+// waveStep is the time between updates of a wave, short enough for captures sampling every 10ms
+const waveStep = 10 * time.Millisecond
+
+// waveLevel returns the level of the waveform at the phase, both from 0 to 1. Square waves start low like
+// toggles start at the first value, sine waves start in the middle and rise.
+func waveLevel(waveform string, phase float64) float64 {
+	switch waveform {
+	case config.WaveformSquare:
+		if phase < 0.5 {
+			return 0
+		}
+		return 1
+	case config.WaveformTriangle:
+		if phase < 0.5 {
+			return 2 * phase
+		}
+		return 2 - 2*phase
+	case config.WaveformSawtooth:
+		return phase
+	default:
+		return 0.5 + 0.5*math.Sin(2*math.Pi*phase)
+	}
+}
+
+// validateScenario verifies every scenario event can be applied to the engine
+func validateScenario(scenario []config.ScenarioEvent, engine Engine) error {
+	if len(scenario) == 0 {
+		return nil
+	}
+
+	if _, ok := engine.(StateSetter); !ok {
+		return fmt.Errorf("%w: scenario events require an engine that supports setting state", ErrInvalidScenario)
+	}
+
+	for i, event := range scenario {
+		if event.Key == "" {
+			return fmt.Errorf("%w: event %d has no key", ErrInvalidScenario, i)
+		}
+
+		switch event.Action {
+		case config.ActionSet:
+		case config.ActionRamp:
+			if event.Duration <= 0 {
+				return fmt.Errorf("%w: ramp event %d requires a duration", ErrInvalidScenario, i)
+			}
+			for _, v := range []string{event.Value, event.To} {
+				if _, err := voltage.Parse(v); err != nil {
+					return fmt.Errorf("%w: ramp event %d: %w", ErrInvalidScenario, i, err)
+				}
+			}
+		case config.ActionToggle:
+			if event.Interval <= 0 {
+				return fmt.Errorf("%w: toggle event %d requires an interval", ErrInvalidScenario, i)
+			}
+		case config.ActionWave:
+			switch event.Waveform {
+			case "", config.WaveformSine, config.WaveformSquare, config.WaveformTriangle, config.WaveformSawtooth:
+			default:
+				return fmt.Errorf("%w: wave event %d has unknown waveform %q", ErrInvalidScenario, i, event.Waveform)
+			}
+			if event.Interval <= 0 {
+				return fmt.Errorf("%w: wave event %d requires an interval", ErrInvalidScenario, i)
+			}
+			for _, v := range []string{event.Value, event.To} {
+				if _, err := voltage.Parse(v); err != nil {
+					return fmt.Errorf("%w: wave event %d: %w", ErrInvalidScenario, i, err)
+				}
+			}
+		default:
+			return fmt.Errorf("%w: event %d has unknown action %q", ErrInvalidScenario, i, event.Action)
+		}
+	}
+
+	return nil
+}
+
+// startScenario schedules the scenario events relative to now
+func (e *Emulator) startScenario(ctx context.Context) {
+	start := time.Now()
+
+	for _, event := range e.config.Scenario {
+		e.wg.Go(func() { e.runEvent(ctx, start, event) })
+	}
+}
+
+// runEvent waits until the event is due and applies it until it completes or ctx is cancelled
+func (e *Emulator) runEvent(ctx context.Context, start time.Time, event config.ScenarioEvent) {
+	if !sleepUntil(ctx, start.Add(event.At)) {
+		return
+	}
+
+	e.logger.Info("Scenario event started", "action", event.Action, "key", event.Key)
+
+	switch event.Action {
+	case config.ActionSet:
+		e.setState(event.Key, event.Value)
+	case config.ActionRamp:
+		// Values were validated when the emulator was created
+		from, _ := voltage.Parse(event.Value)
+		to, _ := voltage.Parse(event.To)
+		rampStart := time.Now()
+
+		for {
+			progress := min(float64(time.Since(rampStart))/float64(event.Duration), 1)
+			e.setState(event.Key, voltage.Format(from+(to-from)*progress))
+
+			if progress >= 1 || !sleepUntil(ctx, time.Now().Add(rampStep)) {
+				return
+			}
+		}
+	case config.ActionToggle:
+		values := [2]string{event.Value, event.To}
+		next := time.Now()
+
+		for i := 0; event.Count == 0 || i < event.Count; i++ {
+			e.setState(event.Key, values[i%2])
+
+			next = next.Add(event.Interval)
+			if !sleepUntil(ctx, next) {
+				return
+			}
+		}
+	case config.ActionWave:
+		// Values were validated when the emulator was created
+		low, _ := voltage.Parse(event.Value)
+		high, _ := voltage.Parse(event.To)
+		waveStart := time.Now()
+
+		for {
+			elapsed := time.Since(waveStart)
+			if event.Duration > 0 && elapsed >= event.Duration {
+				return
+			}
+
+			phase := float64(elapsed%event.Interval) / float64(event.Interval)
+			e.setState(event.Key, voltage.Format(low+(high-low)*waveLevel(event.Waveform, phase)))
+
+			if !sleepUntil(ctx, time.Now().Add(waveStep)) {
+				return
+			}
+		}
+	}
+}
+
+// setState applies a scenario change to the engine state
+func (e *Emulator) setState(key, value string) {
+	e.engineLock.Lock()
+	defer e.engineLock.Unlock()
+
+	setter, ok := e.engine.(StateSetter)
+	if !ok {
+		return
+	}
+
+	if err := setter.SetState(key, value); err != nil {
+		e.logger.Warn("Failed to apply scenario event", logging.Err(err))
+	}
+}
+
+// sleepUntil waits until t, returning false if ctx is cancelled first
+func sleepUntil(ctx context.Context, t time.Time) bool {
+	timer := time.NewTimer(time.Until(t))
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+		return false
+	case <-timer.C:
+		return true
+	}
+}
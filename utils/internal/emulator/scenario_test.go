@@ -0,0 +1,97 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package emulator
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	. "github.com/onsi/gomega"
+
+	"github.com/detiber/k8s-jumperless/utils/internal/emulator/config"
+)
+
+func TestWaveLevel(t *testing.T) {
+	tests := []struct {
+		waveform string
+		phase    float64
+		want     float64
+	}{
+		{waveform: config.WaveformSine, phase: 0, want: 0.5},
+		{waveform: config.WaveformSine, phase: 0.25, want: 1},
+		{waveform: config.WaveformSine, phase: 0.75, want: 0},
+		{waveform: "", phase: 0.25, want: 1},
+		{waveform: config.WaveformSquare, phase: 0.25, want: 0},
+		{waveform: config.WaveformSquare, phase: 0.5, want: 1},
+		{waveform: config.WaveformTriangle, phase: 0.25, want: 0.5},
+		{waveform: config.WaveformTriangle, phase: 0.5, want: 1},
+		{waveform: config.WaveformTriangle, phase: 0.75, want: 0.5},
+		{waveform: config.WaveformSawtooth, phase: 0.75, want: 0.75},
+	}
+
+	for _, tt := range tests {
+		t.Run(fmt.Sprintf("%s at %v", tt.waveform, tt.phase), func(t *testing.T) {
+			g := NewWithT(t)
+			g.Expect(waveLevel(tt.waveform, tt.phase)).To(BeNumerically("~", tt.want, 1e-9))
+		})
+	}
+}
+
+func TestValidateWaveScenario(t *testing.T) {
+	tests := []struct {
+		name    string
+		event   config.ScenarioEvent
+		wantErr bool
+	}{
+		{
+			name: "sine",
+			event: config.ScenarioEvent{Action: config.ActionWave, Key: "adc2", Value: "0V", To: "3.3V",
+				Interval: 100 * time.Millisecond},
+		},
+		{
+			name: "unknown waveform",
+			event: config.ScenarioEvent{Action: config.ActionWave, Key: "adc2", Value: "0V", To: "3.3V",
+				Interval: 100 * time.Millisecond, Waveform: "noise"},
+			wantErr: true,
+		},
+		{
+			name:    "no period",
+			event:   config.ScenarioEvent{Action: config.ActionWave, Key: "adc2", Value: "0V", To: "3.3V"},
+			wantErr: true,
+		},
+		{
+			name: "invalid voltage",
+			event: config.ScenarioEvent{Action: config.ActionWave, Key: "adc2", Value: "low", To: "3.3V",
+				Interval: 100 * time.Millisecond},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			err := validateScenario([]config.ScenarioEvent{tt.event}, NewJumperlessEngine())
+			if tt.wantErr {
+				g.Expect(err).To(MatchError(ErrInvalidScenario))
+			} else {
+				g.Expect(err).NotTo(HaveOccurred())
+			}
+		})
+	}
+}
@@ -0,0 +1,180 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package emulator
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strings"
+	"time"
+
+	"go.bug.st/serial"
+
+	"github.com/detiber/k8s-jumperless/jumperless"
+	"github.com/detiber/k8s-jumperless/utils/internal/emulator/config"
+	"github.com/detiber/k8s-jumperless/utils/internal/logging"
+)
+
+var ErrSelfTestFailed = errors.New("self-test failed")
+
+const (
+	// selfTestBaudRate is the baud rate used to open the virtual port, it has no effect on a pty
+	selfTestBaudRate = 115200
+
+	// selfTestReadTimeout is the time allowed for a response on top of its configured delays
+	selfTestReadTimeout = time.Second
+)
+
+// SelfTest validates the config, then starts an emulator, connects to its
+// virtual port and exercises every mapping once, verifying the emulator sends the rendered response.
+func SelfTest(ctx context.Context, c *config.EmulatorConfig, logger *slog.Logger) error {
+	findings := Validate(c)
+	failures := []string{}
+	for _, finding := range findings {
+		if finding.Severity == SeverityError {
+			failures = append(failures, finding.String())
+			continue
+		}
+
+		logger.Warn("Questionable config", "finding", finding.String())
+	}
+
+	if len(failures) > 0 {
+		return fmt.Errorf("%w: %s", ErrSelfTestFailed, strings.Join(failures, "; "))
+	}
+
+	// The self-test always runs against its own private virtual port, without injected faults or failures
+	testConfig := *c
+	testConfig.VirtualPort = ""
+	testConfig.Listen = ""
+	testConfig.ExtraPorts = nil
+	testConfig.Exec = ""
+	testConfig.Faults = nil
+	testConfig.Failures = nil
+
+	e, err := New(&testConfig, logger)
+	if err != nil {
+		return fmt.Errorf("failed to create emulator: %w", err)
+	}
+
+	if err := e.Start(ctx); err != nil {
+		return fmt.Errorf("failed to start emulator: %w", err)
+	}
+
+	defer func() {
+		if err := e.Stop(); err != nil {
+			logger.Warn("Failed to stop emulator", logging.Err(err))
+		}
+	}()
+
+	port, err := jumperless.OpenSerialPort(e.GetPortName(), &serial.Mode{BaudRate: selfTestBaudRate})
+	if err != nil {
+		return fmt.Errorf("failed to open virtual port %s: %w", e.GetPortName(), err)
+	}
+
+	defer func() {
+		if err := port.Close(); err != nil {
+			logger.Warn("Failed to close virtual port", logging.Err(err))
+		}
+	}()
+
+	verified := 0
+
+	for i, mapping := range c.Mappings {
+		if err := ctx.Err(); err != nil {
+			return fmt.Errorf("self-test cancelled: %w", err)
+		}
+
+		// Responses of dynamic mappings depend on the request, state or random source, they are only validated
+		if mapping.Match == config.MatchRegex || mapping.Template || mapping.Select == config.SelectRandom {
+			logger.Info("Skipping dynamic mapping", "request", mapping.Request)
+			continue
+		}
+
+		// The emulator answers with the first matching mapping, requests of shadowed mappings never reach them
+		if matched, _ := e.findMapping(mapping.Request); matched != i {
+			logger.Warn("Skipping shadowed mapping", "request", mapping.Request, "shadowedBy", matched)
+			continue
+		}
+
+		// Every mapping receives a single request, which is answered with its first response
+		if err := exerciseMapping(port, mapping.Request, mapping.Responses[0]); err != nil {
+			failures = append(failures, err.Error())
+			continue
+		}
+
+		logger.Info("Verified mapping", "request", mapping.Request)
+		verified++
+	}
+
+	if len(failures) > 0 {
+		return fmt.Errorf("%w: %s", ErrSelfTestFailed, strings.Join(failures, "; "))
+	}
+
+	logger.Info("Self-test passed", "verified", verified, "mappings", len(c.Mappings))
+
+	return nil
+}
+
+// exerciseMapping sends a request to the port and verifies the rendered response is received
+func exerciseMapping(port serial.Port, request string, response config.ResponseOption) error {
+	expected := strings.Builder{}
+	timeout := selfTestReadTimeout
+
+	for _, chunk := range response.Chunks {
+		data, err := RenderChunk(chunk)
+		if err != nil {
+			return fmt.Errorf("%q: %w", request, err)
+		}
+
+		expected.WriteString(data)
+		timeout += chunk.Delay + chunk.JitterMax
+	}
+
+	if err := port.ResetInputBuffer(); err != nil {
+		return fmt.Errorf("%q: failed to reset input buffer: %w", request, err)
+	}
+
+	if _, err := port.Write([]byte(request)); err != nil {
+		return fmt.Errorf("%q: failed to write request: %w", request, err)
+	}
+
+	if err := port.SetReadTimeout(selfTestReadTimeout); err != nil {
+		return fmt.Errorf("%q: failed to set read timeout: %w", request, err)
+	}
+
+	received := strings.Builder{}
+	buffer := make([]byte, max(expected.Len(), 1))
+	deadline := time.Now().Add(timeout)
+
+	for received.Len() < expected.Len() && time.Now().Before(deadline) {
+		n, err := port.Read(buffer)
+		if err != nil {
+			return fmt.Errorf("%q: failed to read response: %w", request, err)
+		}
+
+		received.Write(buffer[:n])
+	}
+
+	if received.String() != expected.String() {
+		return fmt.Errorf("%q: expected response %q, received %q", request, expected.String(), received.String())
+	}
+
+	return nil
+}
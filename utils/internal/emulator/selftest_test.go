@@ -0,0 +1,51 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package emulator
+
+import (
+	"bytes"
+	"log/slog"
+	"strconv"
+	"testing"
+
+	. "github.com/onsi/gomega"
+
+	"github.com/detiber/k8s-jumperless/utils/internal/emulator/config"
+)
+
+func TestSelfTest(t *testing.T) {
+	g := NewWithT(t)
+
+	response := func(data string) []config.ResponseOption {
+		return []config.ResponseOption{{Chunks: []config.ResponseChunk{{Data: strconv.Quote(data)}}}}
+	}
+
+	c := config.NewDefaultConfig()
+	c.Mappings = config.Mappings{
+		{Request: "?", Responses: response("Jumperless firmware version: 5.3.1.0\r\n")},
+		{Request: `dac_get\(\d\)`, Match: config.MatchRegex, Template: true, Responses: response("{{ .Request }}\r\n")},
+		{Request: "dac_get(0)", Responses: response("3.3V\r\n")},
+		{Request: "dac_set(0, 3.3)", Responses: response("3.3V\r\n")},
+	}
+
+	logs := &bytes.Buffer{}
+	g.Expect(SelfTest(t.Context(), c, slog.New(slog.NewTextHandler(logs, nil)))).To(Succeed())
+
+	// The exact mapping answered by the regex mapping is skipped rather than verified against its response
+	g.Expect(logs.String()).To(ContainSubstring(`msg="Skipping shadowed mapping" request=dac_get(0) shadowedBy=1`))
+	g.Expect(logs.String()).To(ContainSubstring("verified=2 mappings=4"))
+}
@@ -0,0 +1,220 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package emulator
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"maps"
+	"os"
+	"path/filepath"
+	"slices"
+	"time"
+
+	"github.com/detiber/k8s-jumperless/jumperless/voltage"
+	"github.com/detiber/k8s-jumperless/utils/internal/logging"
+)
+
+var ErrInvalidStateFile = errors.New("invalid state file")
+
+// stateSaveInterval is the interval at which changes of the state are saved to the state file
+const stateSaveInterval = time.Second
+
+// savedState is the content of a state file, the state of the engine that clients can change. Inputs such as
+// the ADC voltages and the I2C devices are left to the emulator config.
+type savedState struct {
+	// DACs are the voltages of the DAC channels, e.g. "3.30"
+	DACs []string `json:"dacs"`
+
+	// GPIOs are the levels of the GPIO pins that were set
+	GPIOs map[string]bool `json:"gpios,omitempty"`
+
+	// Connections are the connected node pairs, e.g. "5-TOP_R"
+	Connections []string `json:"connections,omitempty"`
+
+	// Config is the device config in the format of the config dump, if a config is loaded
+	Config string `json:"config,omitempty"`
+}
+
+// saveState returns the state of the engine to write to a state file
+func (j *JumperlessEngine) saveState() savedState {
+	state := savedState{
+		DACs:        make([]string, 0, dacCount),
+		GPIOs:       maps.Clone(j.gpios),
+		Connections: slices.Sorted(maps.Keys(j.connections)),
+	}
+
+	for _, v := range j.dacs {
+		state.DACs = append(state.DACs, voltage.FormatValue(v))
+	}
+
+	if j.config != nil {
+		state.Config = j.renderConfig()
+	}
+
+	return state
+}
+
+// restoreState replaces the state of the engine with the state read from a state file
+func (j *JumperlessEngine) restoreState(state savedState) error {
+	if len(state.DACs) > dacCount {
+		return fmt.Errorf("%w: %d DAC channels, the device has %d", ErrInvalidStateFile, len(state.DACs), dacCount)
+	}
+
+	if state.Config != "" {
+		if err := j.loadConfig(state.Config); err != nil {
+			return fmt.Errorf("%w: %w", ErrInvalidStateFile, err)
+		}
+	}
+
+	// The saved voltages take precedence over the voltages saved to the config
+	for channel, value := range state.DACs {
+		v, err := voltage.ParseInRange(value)
+		if err != nil {
+			return fmt.Errorf("%w: DAC %d: %w", ErrInvalidStateFile, channel, err)
+		}
+		j.dacs[channel] = voltage.Round(v)
+	}
+
+	j.gpios = maps.Clone(state.GPIOs)
+	if j.gpios == nil {
+		j.gpios = map[string]bool{}
+	}
+
+	clear(j.connections)
+	for _, connection := range state.Connections {
+		j.connections[connection] = true
+	}
+
+	return nil
+}
+
+// loadStateFile restores the state of the engine from the state file, a missing file leaves the state unchanged
+func loadStateFile(path string, engine Engine) error {
+	jumperlessEngine, ok := engine.(*JumperlessEngine)
+	if !ok {
+		return fmt.Errorf("%w: the state file requires the %s or %s engine", ErrInvalidStateFile, EngineJumperless,
+			EnginePython)
+	}
+
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	} else if err != nil {
+		return fmt.Errorf("failed to read state file: %w", err)
+	}
+
+	var state savedState
+	if err := json.Unmarshal(data, &state); err != nil {
+		return fmt.Errorf("%w: %s: %w", ErrInvalidStateFile, path, err)
+	}
+
+	return jumperlessEngine.restoreState(state)
+}
+
+// marshalState returns the content of the state file for the state of the engine
+func (e *Emulator) marshalState() ([]byte, error) {
+	e.engineLock.Lock()
+	state := e.engine.(*JumperlessEngine).saveState() //nolint:forcetypeassert // checked when the file was loaded
+	e.engineLock.Unlock()
+
+	data, err := json.MarshalIndent(state, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode state: %w", err)
+	}
+
+	return append(data, '\n'), nil
+}
+
+// writeStateFile writes data to the state file, through a temporary file so an interrupted write never leaves
+// a truncated state file behind
+func writeStateFile(path string, data []byte) error {
+	file, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".*.tmp")
+	if err != nil {
+		return fmt.Errorf("failed to create state file: %w", err)
+	}
+	defer func() { _ = os.Remove(file.Name()) }()
+
+	if _, err := file.Write(data); err != nil {
+		_ = file.Close()
+		return fmt.Errorf("failed to write state file: %w", err)
+	}
+
+	if err := file.Sync(); err != nil {
+		_ = file.Close()
+		return fmt.Errorf("failed to write state file: %w", err)
+	}
+
+	if err := file.Close(); err != nil {
+		return fmt.Errorf("failed to write state file: %w", err)
+	}
+
+	if err := os.Rename(file.Name(), path); err != nil {
+		return fmt.Errorf("failed to write state file: %w", err)
+	}
+
+	return nil
+}
+
+// saveStateFile writes the state to the state file if it changed since it was last saved
+func (e *Emulator) saveStateFile() {
+	data, err := e.marshalState()
+	if err != nil {
+		e.logger.Error("Error saving emulated device state", logging.Err(err))
+		return
+	}
+
+	e.stateLock.Lock()
+	defer e.stateLock.Unlock()
+
+	if bytes.Equal(data, e.savedState) {
+		return
+	}
+
+	if err := writeStateFile(e.config.StateFile, data); err != nil {
+		e.logger.Error("Error saving emulated device state", logging.Err(err))
+		return
+	}
+
+	e.savedState = data
+	e.logger.Debug("Saved emulated device state", "file", e.config.StateFile)
+}
+
+// startStatePersistence saves changes of the state to the state file every stateSaveInterval until ctx is
+// cancelled, the state is saved a last time when the emulator stops
+func (e *Emulator) startStatePersistence(ctx context.Context) {
+	if e.config.StateFile == "" {
+		return
+	}
+
+	e.wg.Go(func() {
+		ticker := time.NewTicker(stateSaveInterval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				e.saveStateFile()
+			}
+		}
+	})
+}
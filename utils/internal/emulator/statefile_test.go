@@ -0,0 +1,86 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package emulator
+
+import (
+	"log/slog"
+	"os"
+	"path/filepath"
+	"testing"
+
+	. "github.com/onsi/gomega"
+
+	jumperlessv5alpha1 "github.com/detiber/k8s-jumperless/api/v5alpha1"
+	"github.com/detiber/k8s-jumperless/internal/controller/local"
+	"github.com/detiber/k8s-jumperless/jumperless"
+	"github.com/detiber/k8s-jumperless/utils/internal/emulator/config"
+)
+
+func TestStateFile(t *testing.T) {
+	g := NewWithT(t)
+
+	dir := t.TempDir()
+	c := config.NewDefaultConfig()
+	c.VirtualPort = filepath.Join(dir, "jumperless")
+	c.Engine = EngineJumperless
+	c.Personality = config.PersonalityFactory
+	c.StateFile = filepath.Join(dir, "state.json")
+	c.Hardware = &config.HardwareConfig{DACs: []config.HardwareDAC{{Channel: "DAC0", Voltage: "1V"}}}
+	c.Seed = 1
+
+	logger := slog.New(slog.NewTextHandler(t.Output(), nil))
+
+	e, err := New(c, logger)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(e.Start(t.Context())).To(Succeed())
+
+	j, err := jumperless.NewJumperless(t.Context(), e.GetPortName(), 0)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(j.OpenPort()).To(Succeed())
+
+	g.Expect(local.SetDAC(j, jumperlessv5alpha1.DAC0, 2.5, false)).To(Succeed())
+	g.Expect(local.SetGPIO(j, 3, true)).To(Succeed())
+	g.Expect(local.Connect(j, "UART_Tx", "D1")).To(Succeed())
+	g.Expect(local.SetConfigEntry(j, "display", "led_brightness", "25")).To(Succeed())
+
+	g.Expect(j.ClosePort()).To(Succeed())
+	g.Expect(e.Stop()).To(Succeed())
+
+	// Only the state file is left, without temporary files
+	entries, err := os.ReadDir(dir)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(entries).To(HaveLen(1))
+	g.Expect(entries[0].Name()).To(Equal("state.json"))
+
+	// A restarted emulator restores the state, taking precedence over the hardware config
+	restarted, err := New(c, logger)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(restarted.GetState()).To(And(
+		HaveKeyWithValue("dac0", "2.50"),
+		HaveKeyWithValue("gpio3", "True"),
+		HaveKeyWithValue("connections", "D1-UART_Tx"),
+		HaveKeyWithValue("config.display.led_brightness", "25"),
+	))
+
+	g.Expect(os.WriteFile(c.StateFile, []byte(`{"dacs": ["9.00"]}`), 0o600)).To(Succeed())
+	_, err = New(c, logger)
+	g.Expect(err).To(MatchError(ErrInvalidStateFile))
+
+	c.Engine, c.Personality, c.Hardware = "", "", nil
+	_, err = New(c, logger)
+	g.Expect(err).To(MatchError(ErrInvalidStateFile))
+}
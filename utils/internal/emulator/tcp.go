@@ -0,0 +1,132 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package emulator
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"time"
+
+	"github.com/detiber/k8s-jumperless/utils/internal/emulator/config"
+	"github.com/detiber/k8s-jumperless/utils/internal/logging"
+	"github.com/detiber/k8s-jumperless/utils/internal/rfc2217"
+	"github.com/detiber/k8s-jumperless/utils/internal/server"
+)
+
+var ErrUnsupportedProtocol = errors.New("unsupported listen protocol (use raw or rfc2217)")
+
+// tcpReadTimeout bounds each read from a TCP client so that cancellation is noticed between reads
+const tcpReadTimeout = 100 * time.Millisecond
+
+// startListener starts serving the emulated device over TCP, as a raw byte stream or using RFC2217
+func (e *Emulator) startListener(ctx context.Context) error {
+	switch e.config.Protocol {
+	case "", config.ProtocolRaw, config.ProtocolRFC2217:
+	default:
+		return fmt.Errorf("%w: %q", ErrUnsupportedProtocol, e.config.Protocol)
+	}
+
+	if e.config.VirtualPort != "" || len(e.config.ExtraPorts) > 0 {
+		e.logger.Warn("Ignoring virtual ports, listening instead", "listen", e.config.Listen)
+	}
+
+	address, err := server.ResolveAddr(e.config.Listen, e.config.Bind)
+	if err != nil {
+		return err //nolint:wrapcheck
+	}
+
+	if err := e.creds.CheckExposure(address, false); err != nil {
+		return err //nolint:wrapcheck
+	}
+
+	listener, err := e.creds.Listen(ctx, address)
+	if err != nil {
+		return err //nolint:wrapcheck
+	}
+
+	e.listener = listener
+	e.logger.Info("Listening for clients", "listen", listener.Addr(), "protocol", e.config.Protocol)
+
+	handlerctx, cancel := context.WithCancelCause(ctx)
+	e.cancel = cancel
+	e.wg.Go(func() { e.acceptClients(handlerctx) })
+	e.startScenario(handlerctx)
+	e.startStatePersistence(handlerctx)
+
+	return nil
+}
+
+// acceptClients serves TCP clients concurrently, all clients share the same device state
+func (e *Emulator) acceptClients(ctx context.Context) {
+	for {
+		conn, err := e.listener.Accept()
+		if err != nil {
+			if ctx.Err() != nil || errors.Is(err, net.ErrClosed) {
+				return
+			}
+			e.logger.Error("Error accepting client", logging.Err(err))
+			continue
+		}
+
+		e.logger.Info("Client connected", "client", conn.RemoteAddr())
+		e.wg.Go(func() { e.serveClient(ctx, conn) })
+	}
+}
+
+// serveClient serves a single TCP client until it disconnects
+func (e *Emulator) serveClient(ctx context.Context, conn net.Conn) {
+	client := conn
+	if e.config.Protocol == config.ProtocolRFC2217 {
+		// Port events are handled while reading, so their responses are written to the same session
+		var session *rfc2217.Conn
+		var err error
+		session, err = rfc2217.NewConn(conn, config.DefaultBaudRate, e.logger, func(eventType, value string) {
+			e.handleEvent(session, eventType, value)
+		})
+		if err != nil {
+			e.logger.Error("Error starting RFC2217 session", logging.Err(err))
+			_ = conn.Close()
+			return
+		}
+		client = session
+	}
+
+	if err := e.serve(ctx, &deadlineConn{Conn: client}); errors.Is(err, ErrInjectedDisconnect) {
+		e.logger.Info("Injecting disconnect, closing client connection", "client", conn.RemoteAddr())
+	}
+
+	if err := conn.Close(); err != nil {
+		e.logger.Warn("Failed to close client connection", logging.Err(err))
+	}
+}
+
+// deadlineConn applies a read deadline before every read, surfacing a timeout error
+// the same way the non-blocking pseudo TTY does when no data is available
+type deadlineConn struct {
+	net.Conn
+}
+
+func (c *deadlineConn) Read(b []byte) (int, error) {
+	if err := c.SetReadDeadline(time.Now().Add(tcpReadTimeout)); err != nil {
+		return 0, fmt.Errorf("failed to set read deadline: %w", err)
+	}
+
+	// Errors are returned unwrapped so timeouts and EOF can be detected by the caller
+	return c.Conn.Read(b) //nolint:wrapcheck
+}
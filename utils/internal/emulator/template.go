@@ -0,0 +1,138 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package emulator
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"slices"
+	"strconv"
+	"strings"
+	"text/template"
+
+	"github.com/detiber/k8s-jumperless/jumperless/voltage"
+)
+
+var ErrInvalidNumber = errors.New("invalid number")
+var ErrDivisionByZero = errors.New("division by zero")
+
+// templateFuncs are the functions response templates can use in addition to the built-in functions of
+// text/template. Numbers are float64 values, the functions taking numbers also accept the strings of the
+// state and groups, e.g. "3.30" or "3.3V".
+var templateFuncs = template.FuncMap{ //nolint:gochecknoglobals
+	// num parses a number, e.g. {{if gt (num .State.adc0) 2.5}}
+	"num": templateNumber,
+
+	// add, sub, mul and div apply the arithmetic operators, e.g. {{mul .State.dac0 2}} or {{sub .State.dac0 1}}
+	"add": templateArithmetic(func(a, b float64) (float64, error) { return a + b, nil }),
+	"sub": templateArithmetic(func(a, b float64) (float64, error) { return a - b, nil }),
+	"mul": templateArithmetic(func(a, b float64) (float64, error) { return a * b, nil }),
+	"div": templateArithmetic(func(a, b float64) (float64, error) {
+		if b == 0 {
+			return 0, ErrDivisionByZero
+		}
+		return a / b, nil
+	}),
+
+	// fixed formats a number with a number of decimal places, e.g. {{.State.adc0 | mul 1000 | fixed 0}}
+	"fixed": func(decimals int, v any) (string, error) {
+		n, err := templateNumber(v)
+		if err != nil {
+			return "", err
+		}
+		return strconv.FormatFloat(n, 'f', decimals, 64), nil
+	},
+
+	// volts formats a voltage the way the device prints it, e.g. {{volts (add .State.dac0 .State.dac1)}}
+	"volts": func(v any) (string, error) {
+		n, err := templateNumber(v)
+		if err != nil {
+			return "", err
+		}
+		return voltage.FormatValue(n), nil
+	},
+}
+
+// templateNumber converts a number, or a string holding a number or a voltage, to a float64
+func templateNumber(v any) (float64, error) {
+	switch v := v.(type) {
+	case float64:
+		return v, nil
+	case int:
+		return float64(v), nil
+	case int64:
+		return float64(v), nil
+	case string:
+		if n, err := strconv.ParseFloat(strings.TrimSpace(v), 64); err == nil && !math.IsNaN(n) {
+			return n, nil
+		}
+		if n, err := voltage.Parse(v); err == nil {
+			return n, nil
+		}
+	}
+
+	return 0, fmt.Errorf("%w: %v", ErrInvalidNumber, v)
+}
+
+// templateArithmetic returns a template function applying op to two numbers
+func templateArithmetic(op func(a, b float64) (float64, error)) func(a, b any) (float64, error) {
+	return func(a, b any) (float64, error) {
+		x, err := templateNumber(a)
+		if err != nil {
+			return 0, err
+		}
+
+		y, err := templateNumber(b)
+		if err != nil {
+			return 0, err
+		}
+
+		return op(x, y)
+	}
+}
+
+// parseTemplate parses a response template with the template functions. Every named group of the request
+// pattern is declared as a variable, e.g. $ch holds the group (?P<ch>[0-3]).
+func parseTemplate(text string, groupNames []string) (*template.Template, error) {
+	var declarations strings.Builder
+	for _, name := range groupNames {
+		fmt.Fprintf(&declarations, "{{$%s := index .Groups %q}}", name, name)
+	}
+
+	tmpl, err := template.New("chunk").Funcs(templateFuncs).Option("missingkey=zero").
+		Parse(declarations.String() + text)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse response template %q: %w", text, err)
+	}
+
+	return tmpl, nil
+}
+
+// groupNames returns the names of the named groups, sorted
+func groupNames(groups map[string]string) []string {
+	names := []string{}
+	for name := range groups {
+		if _, err := strconv.Atoi(name); err != nil {
+			names = append(names, name)
+		}
+	}
+
+	slices.Sort(names)
+
+	return names
+}
@@ -0,0 +1,103 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package emulator
+
+import (
+	"regexp"
+	"testing"
+
+	. "github.com/onsi/gomega"
+)
+
+func TestRenderTemplate(t *testing.T) {
+	data := TemplateData{
+		Request: ">dac_get(1)",
+		Groups:  map[string]string{"0": ">dac_get(1)", "1": "1", "ch": "1"},
+		State:   map[string]string{"dac0": "3.30", "dac1": "1.25", "adc0": "2.6V", "gpio3": "True"},
+	}
+
+	tests := []struct {
+		name     string
+		template string
+		want     string
+		err      error
+	}{
+		{
+			name:     "state",
+			template: "{{.State.dac0}}",
+			want:     "3.30",
+		},
+		{
+			name:     "group variable",
+			template: `{{index .State (print "dac" $ch)}}`,
+			want:     "1.25",
+		},
+		{
+			name:     "arithmetic",
+			template: "{{add .State.dac0 .State.dac1 | volts}} {{mul .State.dac1 2}} {{div .State.dac0 2 | fixed 3}}",
+			want:     "4.55 2.5 1.650",
+		},
+		{
+			name:     "precision",
+			template: "{{.State.adc0 | mul 1000 | fixed 0}}mV",
+			want:     "2600mV",
+		},
+		{
+			name:     "conditional",
+			template: `{{if gt (num .State.adc0) 2.5}}HIGH{{else}}LOW{{end}} {{if eq .State.gpio3 "True"}}on{{end}}`,
+			want:     "HIGH on",
+		},
+		{
+			name:     "missing state",
+			template: "[{{.State.adc7}}]",
+			want:     "[]",
+		},
+		{
+			name:     "invalid number",
+			template: "{{mul .State.gpio3 2}}",
+			err:      ErrInvalidNumber,
+		},
+		{
+			name:     "division by zero",
+			template: "{{div .State.dac0 0}}",
+			err:      ErrDivisionByZero,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			rendered, err := RenderTemplate(tt.template, data)
+			if tt.err != nil {
+				g.Expect(err).To(MatchError(tt.err))
+				return
+			}
+
+			g.Expect(err).NotTo(HaveOccurred())
+			g.Expect(rendered).To(Equal(tt.want))
+		})
+	}
+}
+
+func TestValidateTemplateGroupVariables(t *testing.T) {
+	g := NewWithT(t)
+
+	pattern := regexp.MustCompile(`^(?:>dac_get\((?P<ch>[0-3])\))$`)
+	g.Expect(validateTemplate(`{{index .State (print "dac" $ch)}}`, pattern, true, []string{"dac0"})).To(BeEmpty())
+	g.Expect(validateTemplate(`{{$ch}}`, nil, true, nil)).To(ConsistOf(ContainSubstring("undefined variable")))
+}
@@ -0,0 +1,349 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package emulator
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"text/template/parse"
+	"time"
+
+	"github.com/detiber/k8s-jumperless/utils/internal/emulator/config"
+)
+
+var ErrInvalidConfig = errors.New("invalid emulator config")
+
+// maxChunkDelay is the longest delay of a response chunk not reported as suspicious, clients usually
+// time out long before it
+const maxChunkDelay = 10 * time.Second
+
+// Severities of validation findings
+const (
+	SeverityError   = "error"
+	SeverityWarning = "warning"
+)
+
+// Finding is a problem found in an emulator config. Errors prevent the config from being served as
+// intended, warnings point at mappings that are likely not doing what their author meant.
+type Finding struct {
+	Severity string `json:"severity"`
+	// Location is the part of the config the finding is about, e.g. mapping 3 ">dac_get(0)"
+	Location string `json:"location,omitempty"`
+	Message  string `json:"message"`
+}
+
+func (f Finding) String() string {
+	if f.Location == "" {
+		return f.Severity + ": " + f.Message
+	}
+
+	return fmt.Sprintf("%s: %s: %s", f.Severity, f.Location, f.Message)
+}
+
+// Validate checks the config without starting the emulator: every regex compiles, no mapping is shadowed
+// by an earlier one, chunk delays are sane, chunks and templates render and templates only reference
+// state the engine provides and groups the request pattern captures.
+func Validate(c *config.EmulatorConfig) []Finding {
+	findings := []Finding{}
+
+	engine, err := NewEngine(c.Engine)
+	if err != nil {
+		findings = append(findings, Finding{Severity: SeverityError, Location: "engine", Message: err.Error()})
+	}
+
+	if err := applyPersonality(c.Personality, engine); err != nil {
+		findings = append(findings, Finding{Severity: SeverityError, Location: "personality", Message: err.Error()})
+	}
+
+	var stateKeys []string
+	if engine != nil {
+		for key := range engine.State() {
+			stateKeys = append(stateKeys, key)
+		}
+	}
+
+	findings = append(findings, validateMappingList("mapping", c.Mappings, engine != nil, stateKeys)...)
+
+	if c.Passthrough != nil {
+		// The passthrough port has no engine, its templates only have the request and groups
+		findings = append(findings, validateMappingList("passthrough mapping", c.Passthrough.Mappings, false, nil)...)
+	}
+
+	if _, err := newFailureRules(c.Failures); err != nil {
+		findings = append(findings, Finding{Severity: SeverityError, Location: "failures", Message: err.Error()})
+	}
+
+	if c.Faults != nil {
+		if _, err := newFaultInjector(c.Faults, 0, slog.New(slog.DiscardHandler)); err != nil {
+			findings = append(findings, Finding{Severity: SeverityError, Location: "faults", Message: err.Error()})
+		}
+	}
+
+	if err := validateScenario(c.Scenario, engine); err != nil {
+		findings = append(findings, Finding{Severity: SeverityError, Location: "scenario", Message: err.Error()})
+	}
+
+	if err := applyHardware(c.Hardware, engine); err != nil {
+		findings = append(findings, Finding{Severity: SeverityError, Location: "hardware", Message: err.Error()})
+	}
+
+	return findings
+}
+
+// HasErrors returns true if any of the findings is an error
+func HasErrors(findings []Finding) bool {
+	return slices.ContainsFunc(findings, func(f Finding) bool { return f.Severity == SeverityError })
+}
+
+// validateMappingList validates mappings in the order the emulator matches them, the first matching
+// mapping answers a request
+func validateMappingList(kind string, mappings config.Mappings, hasEngine bool, stateKeys []string) []Finding {
+	findings := []Finding{}
+
+	patterns := make([]*regexp.Regexp, len(mappings))
+	for i, mapping := range mappings {
+		location := fmt.Sprintf("%s %d %q", kind, i, mapping.Request)
+		add := func(severity, format string, args ...any) {
+			findings = append(findings, Finding{Severity: severity, Location: location, Message: fmt.Sprintf(format, args...)})
+		}
+
+		request := strings.TrimSpace(mapping.Request)
+		if request == "" {
+			add(SeverityError, "an empty request can never match")
+			continue
+		}
+
+		if len(mapping.Responses) == 0 {
+			add(SeverityError, "%v", ErrNoResponsesConfigured)
+		}
+
+		switch mapping.Select {
+		case "", config.SelectSequential, config.SelectRandom:
+		default:
+			add(SeverityError, "unknown select %q", mapping.Select)
+		}
+
+		switch mapping.Match {
+		case "", config.MatchExact:
+			for j, pattern := range patterns[:i] {
+				if shadowedBy(request, mappings[j], pattern) {
+					add(SeverityWarning, "never matches, requests are answered by %s %d %q first",
+						kind, j, mappings[j].Request)
+					break
+				}
+			}
+		case config.MatchRegex:
+			pattern, err := regexp.Compile(`^(?:` + request + `)$`)
+			if err != nil {
+				add(SeverityError, "invalid regular expression: %v", err)
+				continue
+			}
+			patterns[i] = pattern
+
+			for j := range i {
+				if mappings[j].Match == config.MatchRegex && strings.TrimSpace(mappings[j].Request) == request {
+					add(SeverityWarning, "never matches, the same pattern is answered by %s %d first", kind, j)
+					break
+				}
+			}
+		default:
+			add(SeverityError, "unknown match %q", mapping.Match)
+			continue
+		}
+
+		for r, response := range mapping.Responses {
+			for k, chunk := range response.Chunks {
+				chunkLocation := fmt.Sprintf("response %d chunk %d", r, k)
+
+				if chunk.Delay < 0 || chunk.JitterMax < 0 {
+					add(SeverityError, "%s: delay and jitter must not be negative", chunkLocation)
+				} else if chunk.Delay+chunk.JitterMax > maxChunkDelay {
+					add(SeverityWarning, "%s: delay of up to %s, clients usually time out before it is sent",
+						chunkLocation, chunk.Delay+chunk.JitterMax)
+				}
+
+				text, err := RenderChunk(chunk)
+				if err != nil {
+					add(SeverityError, "%s: %v", chunkLocation, err)
+					continue
+				}
+
+				if !mapping.Template {
+					if strings.Contains(text, "{{") {
+						add(SeverityWarning, "%s: contains {{ but templates are not enabled for the mapping", chunkLocation)
+					}
+					continue
+				}
+
+				for _, message := range validateTemplate(text, patterns[i], hasEngine, stateKeys) {
+					add(SeverityError, "%s: %s", chunkLocation, message)
+				}
+			}
+		}
+	}
+
+	return findings
+}
+
+// shadowedBy returns true if an exact request is answered by an earlier mapping
+func shadowedBy(request string, earlier config.RequestResponse, pattern *regexp.Regexp) bool {
+	if pattern != nil {
+		return pattern.MatchString(request)
+	}
+
+	return earlier.Match != config.MatchRegex && strings.TrimSpace(earlier.Request) == request
+}
+
+// validateTemplate returns a description of every problem with a response template: it must parse, and the
+// state keys and groups it references must exist, since they silently render empty otherwise
+func validateTemplate(text string, pattern *regexp.Regexp, hasEngine bool, stateKeys []string) []string {
+	names := []string{}
+	if pattern != nil {
+		names = slices.DeleteFunc(slices.Clone(pattern.SubexpNames()), func(name string) bool { return name == "" })
+	}
+
+	tmpl, err := parseTemplate(text, names)
+	if err != nil {
+		return []string{errors.Unwrap(err).Error()}
+	}
+
+	problems := []string{}
+	if tmpl.Tree == nil {
+		return problems
+	}
+
+	groups := map[string]bool{}
+	if pattern != nil {
+		for i, name := range pattern.SubexpNames() {
+			groups[strconv.Itoa(i)] = true
+			if name != "" {
+				groups[name] = true
+			}
+		}
+	}
+
+	for _, ref := range templateReferences(tmpl.Tree.Root) {
+		switch ref.field {
+		case "State":
+			switch {
+			case !hasEngine:
+				problems = append(problems, fmt.Sprintf("references state %q but no engine is configured", ref.key))
+			case ref.key != "" && !slices.Contains(stateKeys, ref.key):
+				problems = append(problems, fmt.Sprintf("references unknown state %q", ref.key))
+			}
+		case "Groups":
+			switch {
+			case pattern == nil:
+				problems = append(problems, fmt.Sprintf("references group %q but only regex mappings capture groups",
+					ref.key))
+			case ref.key != "" && !groups[ref.key]:
+				problems = append(problems, fmt.Sprintf("references group %q the pattern does not capture", ref.key))
+			}
+		}
+	}
+
+	return problems
+}
+
+// templateReference is a reference to a key of a map in the template data, e.g. {{.State.dac0}} or
+// {{index .Groups "1"}}. The key is empty if it is only known when the template is rendered.
+type templateReference struct {
+	field string
+	key   string
+}
+
+// templateReferences returns the references to the State and Groups maps in a template
+func templateReferences(node parse.Node) []templateReference {
+	refs := []templateReference{}
+
+	switch n := node.(type) {
+	case *parse.ListNode:
+		if n == nil {
+			return refs
+		}
+		for _, child := range n.Nodes {
+			refs = append(refs, templateReferences(child)...)
+		}
+	case *parse.ActionNode:
+		refs = append(refs, templateReferences(n.Pipe)...)
+	case *parse.IfNode:
+		refs = append(refs, branchReferences(&n.BranchNode)...)
+	case *parse.RangeNode:
+		refs = append(refs, branchReferences(&n.BranchNode)...)
+	case *parse.WithNode:
+		refs = append(refs, branchReferences(&n.BranchNode)...)
+	case *parse.PipeNode:
+		if n == nil {
+			return refs
+		}
+		for _, cmd := range n.Cmds {
+			refs = append(refs, commandReferences(cmd)...)
+		}
+	}
+
+	return refs
+}
+
+func branchReferences(n *parse.BranchNode) []templateReference {
+	refs := templateReferences(n.Pipe)
+	refs = append(refs, templateReferences(n.List)...)
+
+	return append(refs, templateReferences(n.ElseList)...)
+}
+
+// commandReferences returns the references in a command, either a field chain such as .State.dac0
+// or an index call such as index .Groups "1"
+func commandReferences(cmd *parse.CommandNode) []templateReference {
+	refs := []templateReference{}
+
+	if len(cmd.Args) >= 2 {
+		if ident, ok := cmd.Args[0].(*parse.IdentifierNode); ok && ident.Ident == "index" {
+			if field, ok := cmd.Args[1].(*parse.FieldNode); ok && len(field.Ident) == 1 {
+				ref := templateReference{field: field.Ident[0]}
+				if len(cmd.Args) > 2 {
+					if key, ok := cmd.Args[2].(*parse.StringNode); ok {
+						ref.key = key.Text
+					}
+				}
+
+				return append(refs, ref)
+			}
+		}
+	}
+
+	for _, arg := range cmd.Args {
+		switch a := arg.(type) {
+		case *parse.FieldNode:
+			switch len(a.Ident) {
+			case 0:
+			case 1:
+				refs = append(refs, templateReference{field: a.Ident[0]})
+			default:
+				refs = append(refs, templateReference{field: a.Ident[0], key: a.Ident[1]})
+			}
+		case *parse.PipeNode:
+			refs = append(refs, templateReferences(a)...)
+		}
+	}
+
+	return refs
+}
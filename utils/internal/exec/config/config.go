@@ -0,0 +1,104 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package config
+
+import (
+	"time"
+
+	"github.com/spf13/viper"
+)
+
+const (
+	// Default values for the exec configuration
+	DefaultBaudRate = 115200
+	DefaultWait     = 10 * time.Millisecond
+
+	// Flag names for command-line arguments
+	FlagBaudRate = "baud-rate"
+	FlagPort     = "port"
+	FlagRaw      = "raw"
+	FlagWait     = "wait"
+	FlagJSON     = "json"
+	FlagDTRPulse = "dtr-pulse"
+
+	// Viper prefix and keys for configuration
+	ViperPrefix   = "exec"
+	ViperBaudRate = ViperPrefix + "." + FlagBaudRate
+	ViperPort     = ViperPrefix + "." + FlagPort
+	ViperRaw      = ViperPrefix + "." + FlagRaw
+	ViperWait     = ViperPrefix + "." + FlagWait
+	ViperJSON     = ViperPrefix + "." + FlagJSON
+	ViperDTRPulse = ViperPrefix + "." + FlagDTRPulse
+)
+
+// NewDefaultConfig returns an ExecConfig with default values
+func NewDefaultConfig() *ExecConfig {
+	return &ExecConfig{
+		BaudRate: DefaultBaudRate,
+		Port:     "",
+		Raw:      false,
+		Wait:     DefaultWait,
+		JSON:     false,
+	}
+}
+
+// NewFromViper creates an ExecConfig from a viper instance
+func NewFromViper(v *viper.Viper) *ExecConfig {
+	cfg := NewDefaultConfig()
+
+	if v.IsSet(ViperBaudRate) {
+		cfg.BaudRate = v.GetInt(ViperBaudRate)
+	}
+	if v.IsSet(ViperPort) {
+		cfg.Port = v.GetString(ViperPort)
+	}
+	if v.IsSet(ViperRaw) {
+		cfg.Raw = v.GetBool(ViperRaw)
+	}
+	if v.IsSet(ViperWait) {
+		cfg.Wait = v.GetDuration(ViperWait)
+	}
+	if v.IsSet(ViperJSON) {
+		cfg.JSON = v.GetBool(ViperJSON)
+	}
+	if v.IsSet(ViperDTRPulse) {
+		cfg.DTRPulse = v.GetDuration(ViperDTRPulse)
+	}
+
+	return cfg
+}
+
+// ExecConfig represents the exec configuration
+type ExecConfig struct {
+	// BaudRate is the baud rate of the serial port
+	BaudRate int `json:"baudRate" mapstructure:"baud-rate" yaml:"baudRate"`
+
+	// Port is the serial port of the device, a Jumperless device is detected if not set
+	Port string `json:"port" mapstructure:"port" yaml:"port"`
+
+	// Raw sends the command as it is instead of as a Python command
+	Raw bool `json:"raw" mapstructure:"raw" yaml:"raw"`
+
+	// Wait is how long to wait for the device to respond before reading the response
+	Wait time.Duration `json:"wait" mapstructure:"wait" yaml:"wait"`
+
+	// JSON prints the result as JSON
+	JSON bool `json:"json" mapstructure:"json" yaml:"json"`
+
+	// DTRPulse is how long DTR is dropped after opening the port to wake or reset the device, zero disables it
+	DTRPulse time.Duration `json:"dtrPulse" mapstructure:"dtr-pulse" yaml:"dtrPulse"`
+}
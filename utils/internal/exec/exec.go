@@ -0,0 +1,112 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package exec
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"strings"
+
+	"github.com/charmbracelet/x/ansi"
+
+	"github.com/detiber/k8s-jumperless/jumperless"
+	"github.com/detiber/k8s-jumperless/utils/internal/exec/config"
+	"github.com/detiber/k8s-jumperless/utils/internal/logging"
+)
+
+// Result is the result of a command executed on a Jumperless device
+type Result struct {
+	// Port is the serial port of the device
+	Port string `json:"port"`
+
+	// Version is the firmware version reported by the device
+	Version string `json:"version"`
+
+	// Command is the command that was executed
+	Command string `json:"command"`
+
+	// Raw is true if the command was sent as it is instead of as a Python command
+	Raw bool `json:"raw"`
+
+	// Response is the cleaned response of the device
+	Response string `json:"response"`
+
+	// Error is the error executing the command, if any
+	Error string `json:"error,omitempty"`
+}
+
+// Run executes a single command on a Jumperless device, detecting it if no port is configured. The result
+// describes the device even if the command fails, along with the error.
+func Run(ctx context.Context, c *config.ExecConfig, command string, logger *slog.Logger) (Result, error) {
+	if logger == nil {
+		logger = logging.Subsystem(slog.Default(), "exec")
+	}
+
+	result := Result{
+		Port:    c.Port,
+		Command: command,
+		Raw:     c.Raw,
+	}
+
+	if c.Port == "" {
+		logger.Info("No port configured, attempting to detect...")
+	}
+
+	j, err := jumperless.NewJumperless(ctx, c.Port, c.BaudRate)
+	if err != nil {
+		return result, fmt.Errorf("failed to find Jumperless device: %w", err)
+	}
+
+	result.Port = j.GetPort()
+	result.Version = j.GetVersion()
+
+	logger.Info("Using Jumperless port", "port", result.Port, "version", result.Version)
+
+	j.SetConnectDTRPulse(c.DTRPulse)
+
+	if err := j.OpenPort(); err != nil {
+		return result, fmt.Errorf("failed to open port %s: %w", result.Port, err)
+	}
+
+	defer func() {
+		if err := j.ClosePort(); err != nil {
+			logger.Warn("Failed to close port", "port", result.Port, logging.Err(err))
+		}
+	}()
+
+	if c.Raw {
+		response, err := j.ExecRawCommand(command, c.Wait)
+		if err != nil {
+			return result, fmt.Errorf("failed to execute raw command %q: %w", command, err)
+		}
+
+		// Raw responses are cleaned the same way as Python responses, without removing any lines
+		result.Response = strings.TrimSpace(strings.ReplaceAll(ansi.Strip(response), "\r\n", "\n"))
+
+		return result, nil
+	}
+
+	response, err := j.ExecPythonCommand(command, c.Wait)
+	if err != nil {
+		return result, fmt.Errorf("failed to execute Python command %q: %w", command, err)
+	}
+
+	result.Response = response
+
+	return result, nil
+}
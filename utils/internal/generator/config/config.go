@@ -20,6 +20,8 @@ import (
 	"time"
 
 	"github.com/spf13/viper"
+
+	"github.com/detiber/k8s-jumperless/utils/internal/line"
 )
 
 const (
@@ -27,24 +29,52 @@ const (
 	DefaultBaudRate   = 115200
 	DefaultBufferSize = 1024
 
+	// DefaultResponseIdle is the time without data from the device ending a response
+	DefaultResponseIdle = 100 * time.Millisecond
+
+	// Standard request suites, sent before the configured requests
+	SuiteQuick  = "quick"  // firmware query, config dump and nets
+	SuiteAnalog = "analog" // all DAC and ADC reads
+	SuiteFull   = "full"   // the quick and analog suites along with all GPIO reads
+
 	// Flag names for command-line arguments
 	FlagBaudRate   = "baud-rate"
+	FlagDataBits   = line.FlagDataBits
+	FlagParity     = line.FlagParity
+	FlagStopBits   = line.FlagStopBits
 	FlagBufferSize = "buffer-size"
 	FlagPort       = "port"
+	FlagOutput     = "output"
+	FlagIdle       = "response-idle"
+	FlagSuite      = "suite"
 
 	// Viper prefix and keys for configuration
 	ViperPrefix     = "generator"
 	ViperBaudRate   = ViperPrefix + "." + FlagBaudRate
+	ViperDataBits   = ViperPrefix + "." + FlagDataBits
+	ViperParity     = ViperPrefix + "." + FlagParity
+	ViperStopBits   = ViperPrefix + "." + FlagStopBits
+	ViperPorts      = ViperPrefix + "." + line.KeyPorts
 	ViperBufferSize = ViperPrefix + "." + FlagBufferSize
 	ViperPort       = ViperPrefix + "." + FlagPort
+	ViperOutput     = ViperPrefix + "." + FlagOutput
+	ViperIdle       = ViperPrefix + "." + FlagIdle
+	ViperSuite      = ViperPrefix + "." + FlagSuite
 )
 
 func NewDefaultConfig() *GeneratorConfig {
 	return &GeneratorConfig{
-		BaudRate:   DefaultBaudRate,
-		BufferSize: DefaultBufferSize,
-		Port:       "",
-		Requests:   []Request{},
+		BaudRate:     DefaultBaudRate,
+		DataBits:     0,
+		Parity:       "",
+		StopBits:     "",
+		Ports:        []line.Override{},
+		BufferSize:   DefaultBufferSize,
+		Port:         "",
+		Output:       "",
+		ResponseIdle: DefaultResponseIdle,
+		Suite:        "",
+		Requests:     []Request{},
 	}
 }
 
@@ -55,12 +85,36 @@ func NewFromViper(v *viper.Viper) *GeneratorConfig {
 	if v.IsSet(ViperBaudRate) {
 		cfg.BaudRate = v.GetInt(ViperBaudRate)
 	}
+	if v.IsSet(ViperDataBits) {
+		cfg.DataBits = v.GetInt(ViperDataBits)
+	}
+	if v.IsSet(ViperParity) {
+		cfg.Parity = v.GetString(ViperParity)
+	}
+	if v.IsSet(ViperStopBits) {
+		cfg.StopBits = v.GetString(ViperStopBits)
+	}
+	if v.IsSet(ViperPorts) {
+		if err := v.UnmarshalKey(ViperPorts, &cfg.Ports); err != nil {
+			// If unmarshaling fails, return an empty list of overrides
+			cfg.Ports = []line.Override{}
+		}
+	}
 	if v.IsSet(ViperBufferSize) {
 		cfg.BufferSize = v.GetInt(ViperBufferSize)
 	}
 	if v.IsSet(ViperPort) {
 		cfg.Port = v.GetString(ViperPort)
 	}
+	if v.IsSet(ViperOutput) {
+		cfg.Output = v.GetString(ViperOutput)
+	}
+	if v.IsSet(ViperIdle) {
+		cfg.ResponseIdle = v.GetDuration(ViperIdle)
+	}
+	if v.IsSet(ViperSuite) {
+		cfg.Suite = v.GetString(ViperSuite)
+	}
 	if v.IsSet(ViperPrefix + ".requests") {
 		cfg.Requests = []Request{}
 		if err := v.UnmarshalKey(ViperPrefix+".requests", &cfg.Requests); err != nil {
@@ -78,6 +132,33 @@ type GeneratorConfig struct {
 	BufferSize int       `json:"bufferSize" mapstructure:"buffer-size" yaml:"bufferSize"`
 	Port       string    `json:"port"       mapstructure:"port"        yaml:"port"`
 	Requests   []Request `json:"requests"   mapstructure:"requests"    yaml:"requests"`
+
+	// DataBits, Parity and StopBits are the line settings of the serial port, 8N1 unless set
+	DataBits int    `json:"dataBits,omitempty" mapstructure:"data-bits" yaml:"dataBits,omitempty"`
+	Parity   string `json:"parity,omitempty"   mapstructure:"parity"    yaml:"parity,omitempty"`
+	StopBits string `json:"stopBits,omitempty" mapstructure:"stop-bits" yaml:"stopBits,omitempty"`
+
+	// Ports overrides the baud rate and line settings of individual serial ports
+	Ports []line.Override `json:"ports,omitempty" mapstructure:"ports" yaml:"ports,omitempty"`
+
+	// Output is an emulator config file the request/response pairs are written to, replacing it. Disabled if empty.
+	Output string `json:"output" mapstructure:"output" yaml:"output"`
+
+	// ResponseIdle is the time without data from the device ending a response, once its first chunk was read
+	ResponseIdle time.Duration `json:"responseIdle" mapstructure:"response-idle" yaml:"responseIdle"`
+
+	// Suite is a standard request suite sent before the requests, one of quick, analog or full. Disabled if empty.
+	Suite string `json:"suite" mapstructure:"suite" yaml:"suite"`
+}
+
+// LineSettings returns the baud rate and line settings of the serial port, including its override
+func (c *GeneratorConfig) LineSettings() line.Settings {
+	return line.Resolve(c.Port, line.Settings{
+		BaudRate: c.BaudRate,
+		DataBits: c.DataBits,
+		Parity:   c.Parity,
+		StopBits: c.StopBits,
+	}, c.Ports)
 }
 
 type Request struct {
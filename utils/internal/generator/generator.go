@@ -17,14 +17,20 @@ limitations under the License.
 package generator
 
 import (
+	"bytes"
 	"context"
 	"errors"
 	"fmt"
-	"log"
-	"os"
+	"log/slog"
+	"sync"
 
 	"github.com/detiber/k8s-jumperless/jumperless"
+	emulatorConfig "github.com/detiber/k8s-jumperless/utils/internal/emulator/config"
 	"github.com/detiber/k8s-jumperless/utils/internal/generator/config"
+	"github.com/detiber/k8s-jumperless/utils/internal/line"
+	"github.com/detiber/k8s-jumperless/utils/internal/logging"
+	"github.com/detiber/k8s-jumperless/utils/internal/proxy"
+	proxyConfig "github.com/detiber/k8s-jumperless/utils/internal/proxy/config"
 	"go.bug.st/serial"
 )
 
@@ -32,31 +38,65 @@ var ErrNoJumperlessDevice = errors.New("no Jumperless device found")
 
 // generator represents a serial port generator that records communication
 type generator struct {
-	config *config.GeneratorConfig
-	logger *log.Logger
+	config   *config.GeneratorConfig
+	logger   *slog.Logger
+	recorder *proxy.Recorder  // Records the request/response pairs when writing an emulator config
+	requests []config.Request // The suite requests followed by the configured requests
 }
 
 // New creates a new generator instance
-func New(c *config.GeneratorConfig, logger *log.Logger) (*generator, error) {
+func New(c *config.GeneratorConfig, logger *slog.Logger) (*generator, error) {
 	if logger == nil {
-		logger = log.New(os.Stdout, "[generator] ", log.LstdFlags)
+		logger = logging.Subsystem(slog.Default(), "generator")
 	}
 
-	return &generator{
-		config: c,
-		logger: logger,
-	}, nil
+	if err := line.Validate(c.LineSettings(), c.Ports); err != nil {
+		return nil, err //nolint:wrapcheck
+	}
+
+	g := &generator{
+		config:   c,
+		logger:   logger,
+		requests: c.Requests,
+	}
+
+	if c.Suite != "" {
+		suite, err := suiteRequests(c.Suite)
+		if err != nil {
+			return nil, err
+		}
+
+		g.requests = append(suite, c.Requests...)
+	}
+
+	if c.Output != "" {
+		// Every request is written in a single write, so each read of the recorder is a request
+		recorder, err := proxy.NewRecorder(logger, nil, proxyConfig.FramingConfig{Mode: proxyConfig.FramingRead},
+			proxyConfig.FilterConfig{})
+		if err != nil {
+			return nil, fmt.Errorf("failed to create recorder: %w", err)
+		}
+
+		g.recorder = recorder
+	}
+
+	return g, nil
+}
+
+// Recording returns the request/response pairs recorded by Run, or nil unless an output is configured
+func (p *generator) Recording() emulatorConfig.Mappings {
+	if p.recorder == nil {
+		return nil
+	}
+
+	return p.recorder.GetRecording()
 }
 
 // Run starts the generator
 func (p *generator) Run(ctx context.Context) error {
 	// Open real serial port
-	mode := &serial.Mode{
-		BaudRate: p.config.BaudRate,
-	}
-
 	if p.config.Port == "" {
-		p.logger.Printf("No real port configured, attempting to detect...")
+		p.logger.Info("No real port configured, attempting to detect...")
 
 		j, err := jumperless.NewJumperless(ctx, p.config.Port, p.config.BaudRate)
 		if err != nil {
@@ -70,40 +110,67 @@ func (p *generator) Run(ctx context.Context) error {
 		p.config.Port = j.GetPort()
 		version := j.GetVersion()
 
-		p.logger.Printf("Detected Jumperless port: %s (version: %s)", p.config.Port, version)
+		p.logger.Info("Detected Jumperless port", "port", p.config.Port, "version", version)
 	}
 
-	port, err := serial.Open(p.config.Port, mode)
+	lock, err := jumperless.LockDevice(p.config.Port)
+	if err != nil {
+		return fmt.Errorf("failed to lock serial port %s: %w", p.config.Port, err)
+	}
+
+	defer func() {
+		if err := lock.Unlock(); err != nil {
+			p.logger.Warn("Failed to unlock serial port", logging.Err(err))
+		}
+	}()
+
+	// The settings are resolved once the port is known, so overrides apply to detected ports as well
+	mode, err := p.config.LineSettings().Mode()
+	if err != nil {
+		return fmt.Errorf("invalid settings of serial port %s: %w", p.config.Port, err)
+	}
+
+	port, err := jumperless.OpenSerialPort(p.config.Port, mode)
 	if err != nil {
 		return fmt.Errorf("failed to open serial port %s: %w", p.config.Port, err)
 	}
 
 	defer func() {
 		if err := port.Close(); err != nil {
-			p.logger.Printf("Warning: failed to close serial port: %v", err)
+			p.logger.Warn("Failed to close serial port", logging.Err(err))
 		} else {
-			p.logger.Printf("Closed serial port: %s", p.config.Port)
+			p.logger.Debug("Closed serial port", "port", p.config.Port)
 		}
 	}()
 
 	go func() {
 		<-ctx.Done()
 
-		p.logger.Printf("Context done, forcing shutdown by closing port %s", p.config.Port)
+		p.logger.Info("Context done, forcing shutdown by closing port", "port", p.config.Port)
 		if err := port.Close(); err != nil {
-			p.logger.Printf("Warning: failed to close serial port: %v", err)
+			p.logger.Warn("Failed to close serial port", logging.Err(err))
 		} else {
-			p.logger.Printf("Closed serial port: %s", p.config.Port)
+			p.logger.Debug("Closed serial port", "port", p.config.Port)
 		}
 	}()
 
-	p.logger.Printf("Connected to serial port: %s", p.config.Port)
+	p.logger.Info("Connected to serial port", "port", p.config.Port)
+
+	p.logger.Info("Starting generator", "suite", p.config.Suite, "requests", len(p.requests))
 
-	p.logger.Printf("Starting generator with %d requests", len(p.config.Requests))
+	if p.recorder != nil {
+		recorderCtx, cancelRecorder := context.WithCancel(ctx)
+		var wg sync.WaitGroup
+		wg.Go(func() { p.recorder.Run(recorderCtx) })
+
+		// Stop the recorder once all requests are sent, saving the last response
+		defer wg.Wait()
+		defer cancelRecorder()
+	}
 
 	readBuffer := make([]byte, p.config.BufferSize)
 
-	for _, req := range p.config.Requests {
+	for _, req := range p.requests {
 		// Reset buffers
 		if err := port.ResetInputBuffer(); err != nil {
 			return fmt.Errorf("failed to reset input buffer on port %s: %w", p.config.Port, err)
@@ -114,29 +181,61 @@ func (p *generator) Run(ctx context.Context) error {
 		}
 
 		// Send request
-		p.logger.Printf("Sending request: %q", req.Data)
+		p.logger.Debug("Sending request", "request", req.Data)
 		if _, err := port.Write([]byte(req.Data)); err != nil {
 			return fmt.Errorf("error writing to port %s: %w", p.config.Port, err)
 		}
 
+		if p.recorder != nil {
+			p.recorder.RecordRequest([]byte(req.Data))
+		}
+
 		// Drain to ensure all data is sent
 		if err := port.Drain(); err != nil {
-			p.logger.Printf("Error draining real port: %v", err)
+			p.logger.Error("Error draining real port", logging.Err(err))
+		}
+
+		if err := p.readResponse(port, req, readBuffer); err != nil {
+			return err
 		}
+	}
+
+	return nil
+}
+
+// readResponse reads the response to the request, the first chunk within the request timeout and further
+// chunks until the device has been idle for the response idle time
+func (p *generator) readResponse(port serial.Port, req config.Request, readBuffer []byte) error {
+	timeout := serial.NoTimeout
+	if req.Timeout > 0 {
+		timeout = req.Timeout
+	}
 
-		// Read response
-		if req.Timeout > 0 {
-			if err := port.SetReadTimeout(req.Timeout); err != nil {
-				return fmt.Errorf("failed to set read timeout on port %s: %w", p.config.Port, err)
-			}
+	var response []byte
+	for {
+		if err := port.SetReadTimeout(timeout); err != nil {
+			return fmt.Errorf("failed to set read timeout on port %s: %w", p.config.Port, err)
 		}
 
-		if _, err := port.Read(readBuffer); err != nil {
+		n, err := port.Read(readBuffer)
+		if err != nil {
 			return fmt.Errorf("error reading from port %s: %w", p.config.Port, err)
 		}
 
-		p.logger.Printf("Received response: %q", readBuffer)
+		if n == 0 {
+			break
+		}
+
+		chunk := bytes.Clone(readBuffer[:n])
+		response = append(response, chunk...)
+		if p.recorder != nil {
+			p.recorder.RecordResponse(chunk)
+		}
+
+		timeout = p.config.ResponseIdle
 	}
 
+	p.logger.Debug("Received response", "response", response)
+
 	return nil
 }
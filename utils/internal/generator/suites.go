@@ -0,0 +1,83 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package generator
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/detiber/k8s-jumperless/utils/internal/generator/config"
+)
+
+var ErrUnknownSuite = errors.New("unknown request suite (use quick, analog, or full)")
+
+const (
+	// suiteTimeout is the time the suite requests wait for the first chunk of their response
+	suiteTimeout = 500 * time.Millisecond
+
+	// Channels and pins read by the suites
+	dacChannels = 4
+	adcChannels = 5
+	gpioPins    = 8
+)
+
+// suiteRequests returns the requests of the standard suite
+func suiteRequests(suite string) ([]config.Request, error) {
+	switch suite {
+	case config.SuiteQuick:
+		return quickSuite(), nil
+	case config.SuiteAnalog:
+		return analogSuite(), nil
+	case config.SuiteFull:
+		requests := append(quickSuite(), analogSuite()...)
+		for pin := 1; pin <= gpioPins; pin++ {
+			requests = append(requests, pythonRequest(fmt.Sprintf("gpio_get(%d)", pin)))
+		}
+
+		return requests, nil
+	default:
+		return nil, fmt.Errorf("%w: %q", ErrUnknownSuite, suite)
+	}
+}
+
+// quickSuite queries the firmware version, dumps the config and prints the nets
+func quickSuite() []config.Request {
+	return []config.Request{
+		{Data: "?", Timeout: suiteTimeout},
+		{Data: "~", Timeout: suiteTimeout},
+		pythonRequest("print_nets()"),
+	}
+}
+
+// analogSuite reads every DAC and ADC channel
+func analogSuite() []config.Request {
+	requests := []config.Request{}
+	for channel := range dacChannels {
+		requests = append(requests, pythonRequest(fmt.Sprintf("dac_get(%d)", channel)))
+	}
+	for channel := range adcChannels {
+		requests = append(requests, pythonRequest(fmt.Sprintf("adc_get(%d)", channel)))
+	}
+
+	return requests
+}
+
+// pythonRequest returns a request running a single Python command, the way the controller sends them
+func pythonRequest(command string) config.Request {
+	return config.Request{Data: ">" + command, Timeout: suiteTimeout}
+}
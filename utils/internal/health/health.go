@@ -0,0 +1,64 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package health
+
+import (
+	"context"
+	"encoding/json"
+	"log/slog"
+	"net/http"
+
+	"github.com/detiber/k8s-jumperless/utils/internal/server"
+)
+
+// Status is the readiness of a component, reported by /readyz along with its details
+type Status struct {
+	Ready   bool `json:"ready"`
+	Details any  `json:"details,omitempty"`
+}
+
+// StatusFunc returns the current readiness of a component, it is called concurrently with the component
+type StatusFunc func() Status
+
+// Serve serves the /healthz liveness and /readyz readiness endpoints on addr until ctx is done. /healthz
+// succeeds as long as the process serves requests, /readyz succeeds once status reports the component ready.
+// Both return the status as JSON.
+func Serve(ctx context.Context, addr string, status StatusFunc, logger *slog.Logger) error {
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /healthz", func(w http.ResponseWriter, _ *http.Request) {
+		writeStatus(w, http.StatusOK, status())
+	})
+	mux.HandleFunc("GET /readyz", func(w http.ResponseWriter, _ *http.Request) {
+		s := status()
+
+		code := http.StatusOK
+		if !s.Ready {
+			code = http.StatusServiceUnavailable
+		}
+
+		writeStatus(w, code, s)
+	})
+
+	return server.Serve(ctx, addr, "health probes", mux, logger) //nolint:wrapcheck
+}
+
+func writeStatus(w http.ResponseWriter, code int, s Status) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(code)
+
+	_ = json.NewEncoder(w).Encode(s)
+}
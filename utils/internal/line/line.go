@@ -0,0 +1,124 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package line holds the serial line settings shared by the commands opening a real serial port, along with
+// the overrides applied to individual ports, e.g. a device attached to a USB-UART adapter using 7E1.
+package line
+
+import (
+	"fmt"
+
+	"go.bug.st/serial"
+
+	"github.com/detiber/k8s-jumperless/jumperless"
+)
+
+const (
+	// Flag names for command-line arguments
+	FlagDataBits = "data-bits"
+	FlagParity   = "parity"
+	FlagStopBits = "stop-bits"
+
+	// KeyPorts is the key of the per-port overrides below the prefix of a command
+	KeyPorts = "ports"
+)
+
+// Settings are the baud rate and line settings a serial port is opened with
+type Settings struct {
+	// BaudRate is the baud rate of the serial port
+	BaudRate int `json:"baudRate" mapstructure:"baud-rate" yaml:"baudRate"`
+
+	// DataBits is the size of a character, 5 to 8. Defaults to 8.
+	DataBits int `json:"dataBits,omitempty" mapstructure:"data-bits" yaml:"dataBits,omitempty"`
+
+	// Parity is one of none, odd, even, mark or space. Defaults to none.
+	Parity string `json:"parity,omitempty" mapstructure:"parity" yaml:"parity,omitempty"`
+
+	// StopBits is one of 1, 1.5 or 2. Defaults to 1.
+	StopBits string `json:"stopBits,omitempty" mapstructure:"stop-bits" yaml:"stopBits,omitempty"`
+}
+
+// Override is the settings of a single serial port, replacing the settings of the command for that port.
+// Settings left empty keep the settings of the command.
+type Override struct {
+	// Port is the path of the serial port the override applies to
+	Port string `json:"port" mapstructure:"port" yaml:"port"`
+
+	Settings `json:",inline" mapstructure:",squash" yaml:",inline"`
+}
+
+// Resolve returns the settings for port, applying the first override of the port on top of settings
+func Resolve(port string, settings Settings, overrides []Override) Settings {
+	for _, o := range overrides {
+		if o.Port != port {
+			continue
+		}
+
+		if o.BaudRate != 0 {
+			settings.BaudRate = o.BaudRate
+		}
+
+		if o.DataBits != 0 {
+			settings.DataBits = o.DataBits
+		}
+
+		if o.Parity != "" {
+			settings.Parity = o.Parity
+		}
+
+		if o.StopBits != "" {
+			settings.StopBits = o.StopBits
+		}
+
+		break
+	}
+
+	return settings
+}
+
+// Validate returns an error if the settings or any of the overrides can't be set on a serial port
+func Validate(settings Settings, overrides []Override) error {
+	if _, err := settings.Line(); err != nil {
+		return err
+	}
+
+	for _, o := range overrides {
+		if o.Port == "" {
+			return fmt.Errorf("%w: override without a port", jumperless.ErrInvalidLineSettings)
+		}
+
+		if _, err := Resolve(o.Port, settings, overrides).Line(); err != nil {
+			return fmt.Errorf("port %s: %w", o.Port, err)
+		}
+	}
+
+	return nil
+}
+
+// Line returns the line settings of the data bits, parity and stop bits
+func (s Settings) Line() (jumperless.LineSettings, error) {
+	return jumperless.ParseLineSettings(s.DataBits, s.Parity, s.StopBits) //nolint:wrapcheck
+}
+
+// Mode returns the serial port mode of the settings
+func (s Settings) Mode() (*serial.Mode, error) {
+	l, err := s.Line()
+	if err != nil {
+		return nil, err
+	}
+
+	return l.Mode(s.BaudRate), nil
+}
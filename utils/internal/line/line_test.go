@@ -0,0 +1,82 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package line_test
+
+import (
+	"strings"
+	"testing"
+
+	. "github.com/onsi/gomega"
+	"github.com/spf13/viper"
+	"go.bug.st/serial"
+
+	"github.com/detiber/k8s-jumperless/jumperless"
+	generatorConfig "github.com/detiber/k8s-jumperless/utils/internal/generator/config"
+	"github.com/detiber/k8s-jumperless/utils/internal/line"
+	proxyConfig "github.com/detiber/k8s-jumperless/utils/internal/proxy/config"
+)
+
+func TestPortOverrides(t *testing.T) {
+	g := NewWithT(t)
+
+	v := viper.New()
+	v.SetConfigType("yaml")
+	g.Expect(v.ReadConfig(strings.NewReader(`
+proxy:
+  baud-rate: 9600
+  ports:
+    - port: /dev/ttyUSB0
+      parity: even
+      data-bits: 7
+    - port: /dev/ttyUSB1
+      baud-rate: 57600
+`))).To(Succeed())
+
+	cfg := proxyConfig.NewFromViper(v)
+	g.Expect(line.Validate(cfg.LineSettings(), cfg.Ports)).To(Succeed())
+
+	// Ports without an override use the settings of the command
+	g.Expect(cfg.LineSettings()).To(Equal(line.Settings{BaudRate: 9600}))
+
+	cfg.RealPort = "/dev/ttyUSB0"
+	mode, err := cfg.LineSettings().Mode()
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(mode).To(Equal(&serial.Mode{BaudRate: 9600, DataBits: 7, Parity: serial.EvenParity}))
+
+	cfg.RealPort = "/dev/ttyUSB1"
+	mode, err = cfg.LineSettings().Mode()
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(mode).To(Equal(&serial.Mode{BaudRate: 57600, DataBits: 8}))
+}
+
+func TestValidate(t *testing.T) {
+	g := NewWithT(t)
+
+	cfg := generatorConfig.NewDefaultConfig()
+	g.Expect(line.Validate(cfg.LineSettings(), cfg.Ports)).To(Succeed())
+
+	cfg.Parity = "sometimes"
+	g.Expect(line.Validate(cfg.LineSettings(), cfg.Ports)).To(MatchError(jumperless.ErrInvalidLineSettings))
+
+	// Overrides are validated even if they don't apply to the configured port
+	cfg.Parity = ""
+	cfg.Ports = []line.Override{{Port: "/dev/ttyUSB0", Settings: line.Settings{StopBits: "1.5"}}}
+	g.Expect(line.Validate(cfg.LineSettings(), cfg.Ports)).To(MatchError(ContainSubstring("/dev/ttyUSB0")))
+
+	cfg.Ports = []line.Override{{Settings: line.Settings{DataBits: 7}}}
+	g.Expect(line.Validate(cfg.LineSettings(), cfg.Ports)).To(MatchError(jumperless.ErrInvalidLineSettings))
+}
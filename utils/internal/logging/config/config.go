@@ -0,0 +1,98 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package config
+
+import (
+	"github.com/spf13/viper"
+)
+
+const (
+	// Default values for the logging configuration
+	DefaultFormat = FormatText
+	DefaultLevel  = LevelInfo
+	DefaultOutput = OutputStdout
+
+	// Log formats
+	FormatText = "text" // logfmt style key=value pairs
+	FormatJSON = "json" // one JSON object per line
+
+	// Log outputs
+	OutputStdout = "stdout"
+	OutputStderr = "stderr"
+
+	// Log levels
+	LevelDebug = "debug"
+	LevelInfo  = "info"
+	LevelWarn  = "warn"
+	LevelError = "error"
+
+	// Flag names for command-line arguments
+	FlagFormat  = "log-format"
+	FlagLevel   = "log-level"
+	FlagOutput  = "log-output"
+	FlagVerbose = "verbose"
+
+	// Viper keys for configuration, logging is configured for all subcommands so the keys are not prefixed
+	ViperFormat  = FlagFormat
+	ViperLevel   = FlagLevel
+	ViperOutput  = FlagOutput
+	ViperVerbose = FlagVerbose
+)
+
+// NewDefaultConfig returns a LoggingConfig with default values
+func NewDefaultConfig() *LoggingConfig {
+	return &LoggingConfig{
+		Format: DefaultFormat,
+		Level:  DefaultLevel,
+		Output: DefaultOutput,
+	}
+}
+
+// NewFromViper creates a LoggingConfig from a viper instance
+func NewFromViper(v *viper.Viper) *LoggingConfig {
+	cfg := NewDefaultConfig()
+
+	if v.IsSet(ViperFormat) {
+		cfg.Format = v.GetString(ViperFormat)
+	}
+
+	// Verbose logging enables debug logs unless a level is set explicitly
+	if v.GetBool(ViperVerbose) {
+		cfg.Level = LevelDebug
+	}
+	if v.IsSet(ViperLevel) {
+		cfg.Level = v.GetString(ViperLevel)
+	}
+
+	if v.IsSet(ViperOutput) {
+		cfg.Output = v.GetString(ViperOutput)
+	}
+
+	return cfg
+}
+
+// LoggingConfig represents the logging configuration
+type LoggingConfig struct {
+	// Format is the log output format, one of text or json
+	Format string `json:"format" mapstructure:"format" yaml:"format"`
+
+	// Level is the minimum level of the logs written, one of debug, info, warn or error
+	Level string `json:"level" mapstructure:"level" yaml:"level"`
+
+	// Output is where the logs are written, one of stdout or stderr
+	Output string `json:"output" mapstructure:"output" yaml:"output"`
+}
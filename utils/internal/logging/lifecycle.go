@@ -0,0 +1,68 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package logging
+
+import (
+	"log/slog"
+	"time"
+)
+
+const (
+	// KeyLifecycle is the attribute holding the lifecycle event of a process, so scripts chaining the utilities
+	// can follow them in the logs
+	KeyLifecycle = "lifecycle"
+
+	// Lifecycle events
+	LifecycleStart = "start" // the process started
+	LifecycleStop  = "stop"  // the process completed or was stopped by a signal
+	LifecycleAbort = "abort" // the process failed
+
+	// Exit reasons of the stop and abort events
+	ExitCompleted   = "completed"   // the process completed its work
+	ExitInterrupted = "interrupted" // the process was stopped by a signal
+	ExitFailed      = "failed"      // the process failed with an error
+)
+
+// Lifecycle logs machine-readable start, stop and abort events of a process, e.g. a subcommand or a client
+// command it runs. The events carry the command, its pid and, once it ended, its duration and exit reason.
+type Lifecycle struct {
+	logger  *slog.Logger
+	command string
+	pid     int
+	start   time.Time
+}
+
+// StartLifecycle logs the start event of a process and returns the Lifecycle to log its end with
+func StartLifecycle(logger *slog.Logger, command string, pid int) *Lifecycle {
+	l := &Lifecycle{logger: logger, command: command, pid: pid, start: time.Now()}
+
+	l.logger.Info("Process started", KeyLifecycle, LifecycleStart, "command", l.command, "pid", l.pid)
+
+	return l
+}
+
+// Stop logs the stop event of a process that completed or was interrupted
+func (l *Lifecycle) Stop(reason string) {
+	l.logger.Info("Process stopped", KeyLifecycle, LifecycleStop, "command", l.command, "pid", l.pid,
+		"duration", time.Since(l.start), "exitReason", reason, "exitCode", 0)
+}
+
+// Abort logs the abort event of a process that failed with an error and exit code
+func (l *Lifecycle) Abort(err error, exitCode int) {
+	l.logger.Error("Process aborted", KeyLifecycle, LifecycleAbort, "command", l.command, "pid", l.pid,
+		"duration", time.Since(l.start), "exitReason", ExitFailed, "exitCode", exitCode, Err(err))
+}
@@ -0,0 +1,175 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package logging
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"slices"
+	"sync"
+
+	"github.com/detiber/k8s-jumperless/utils/internal/logging/config"
+)
+
+const (
+	// KeySubsystem is the attribute naming the subcommand or part of it that wrote a log
+	KeySubsystem = "subsystem"
+
+	// KeyError is the attribute holding the error of a log
+	KeyError = "error"
+)
+
+var (
+	ErrInvalidFormat = errors.New("invalid log format")
+	ErrInvalidLevel  = errors.New("invalid log level")
+	ErrInvalidOutput = errors.New("invalid log output")
+)
+
+// New creates a logger writing to w using the format and level from the config
+func New(w io.Writer, c *config.LoggingConfig) (*slog.Logger, error) {
+	level := &slog.LevelVar{}
+
+	handler, err := newHandler(w, c, level)
+	if err != nil {
+		return nil, err
+	}
+
+	return slog.New(handler), nil
+}
+
+// Output returns the writer for the output in the config
+func Output(c *config.LoggingConfig) (io.Writer, error) {
+	switch c.Output {
+	case config.OutputStdout:
+		return os.Stdout, nil
+	case config.OutputStderr:
+		return os.Stderr, nil
+	default:
+		return nil, fmt.Errorf("%w %q, must be one of %s or %s", ErrInvalidOutput, c.Output,
+			config.OutputStdout, config.OutputStderr)
+	}
+}
+
+// Subsystem returns a logger adding the subsystem attribute to its logs
+func Subsystem(logger *slog.Logger, name string) *slog.Logger {
+	return logger.With(KeySubsystem, name)
+}
+
+// Err returns the error attribute of a log
+func Err(err error) slog.Attr {
+	return slog.Any(KeyError, err)
+}
+
+// newHandler creates the handler for the format in the config, setting level to the level in the config
+func newHandler(w io.Writer, c *config.LoggingConfig, level *slog.LevelVar) (slog.Handler, error) {
+	var parsed slog.Level
+	if err := parsed.UnmarshalText([]byte(c.Level)); err != nil {
+		return nil, fmt.Errorf("%w %q, must be one of %s, %s, %s or %s", ErrInvalidLevel, c.Level,
+			config.LevelDebug, config.LevelInfo, config.LevelWarn, config.LevelError)
+	}
+
+	level.Set(parsed)
+
+	options := &slog.HandlerOptions{Level: level}
+
+	switch c.Format {
+	case config.FormatText:
+		return slog.NewTextHandler(w, options), nil
+	case config.FormatJSON:
+		return slog.NewJSONHandler(w, options), nil
+	default:
+		return nil, fmt.Errorf("%w %q, must be one of %s or %s", ErrInvalidFormat, c.Format,
+			config.FormatText, config.FormatJSON)
+	}
+}
+
+// Handler is a slog.Handler that is configured after loggers are derived from it, since the subcommands create
+// their loggers before the flags and config file setting the format and level are read
+type Handler struct {
+	state *handlerState
+
+	// ops are the WithAttrs and WithGroup calls to apply to the configured handler, in order
+	ops []func(slog.Handler) slog.Handler
+}
+
+type handlerState struct {
+	mu      sync.RWMutex
+	handler slog.Handler
+	level   slog.LevelVar
+}
+
+// NewHandler creates a Handler writing text logs at the info level to w until it is configured
+func NewHandler(w io.Writer) *Handler {
+	state := &handlerState{}
+	state.handler = slog.NewTextHandler(w, &slog.HandlerOptions{Level: &state.level})
+
+	return &Handler{state: state}
+}
+
+// Configure switches the handler and all loggers derived from it to the format and level from the config
+func (h *Handler) Configure(w io.Writer, c *config.LoggingConfig) error {
+	h.state.mu.Lock()
+	defer h.state.mu.Unlock()
+
+	handler, err := newHandler(w, c, &h.state.level)
+	if err != nil {
+		return err
+	}
+
+	h.state.handler = handler
+
+	return nil
+}
+
+// Enabled implements slog.Handler
+func (h *Handler) Enabled(_ context.Context, level slog.Level) bool {
+	return level >= h.state.level.Level()
+}
+
+// Handle implements slog.Handler
+func (h *Handler) Handle(ctx context.Context, r slog.Record) error {
+	h.state.mu.RLock()
+	handler := h.state.handler
+	h.state.mu.RUnlock()
+
+	for _, op := range h.ops {
+		handler = op(handler)
+	}
+
+	return handler.Handle(ctx, r) //nolint:wrapcheck
+}
+
+// WithAttrs implements slog.Handler
+func (h *Handler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return h.with(func(handler slog.Handler) slog.Handler { return handler.WithAttrs(attrs) })
+}
+
+// WithGroup implements slog.Handler
+func (h *Handler) WithGroup(name string) slog.Handler {
+	return h.with(func(handler slog.Handler) slog.Handler { return handler.WithGroup(name) })
+}
+
+func (h *Handler) with(op func(slog.Handler) slog.Handler) *Handler {
+	return &Handler{
+		state: h.state,
+		ops:   append(slices.Clip(h.ops), op),
+	}
+}
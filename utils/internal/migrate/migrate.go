@@ -0,0 +1,237 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package migrate
+
+import (
+	"errors"
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+
+	"github.com/spf13/viper"
+
+	ctlconfig "github.com/detiber/k8s-jumperless/utils/internal/ctl/config"
+	emulatorconfig "github.com/detiber/k8s-jumperless/utils/internal/emulator/config"
+	execconfig "github.com/detiber/k8s-jumperless/utils/internal/exec/config"
+	generatorconfig "github.com/detiber/k8s-jumperless/utils/internal/generator/config"
+	logconfig "github.com/detiber/k8s-jumperless/utils/internal/logging/config"
+	proxyconfig "github.com/detiber/k8s-jumperless/utils/internal/proxy/config"
+	terminalconfig "github.com/detiber/k8s-jumperless/utils/internal/terminal/config"
+)
+
+const (
+	// CurrentVersion is the version of the config schema written by Migrate
+	CurrentVersion = 1
+
+	// VersionKey is the config key holding the schema version, configs without it are version 0
+	VersionKey = "version"
+)
+
+var ErrUnsupportedVersion = errors.New("unsupported config version")
+
+// Rename is a config key moved to a new key by a migration
+type Rename struct {
+	From string
+	To   string
+}
+
+// Report describes the changes made by Migrate
+type Report struct {
+	FromVersion int
+	ToVersion   int
+
+	// Renamed are the keys moved to their key in the new schema
+	Renamed []Rename
+
+	// Dropped are the keys removed since they have no equivalent in the new schema, or their new key is
+	// already set
+	Dropped []string
+
+	// Unknown are the keys that are not part of the current schema, they are kept as is
+	Unknown []string
+}
+
+// migration upgrades a config from one schema version to the next
+type migration struct {
+	// renames maps old keys to their new key
+	renames map[string]string
+
+	// drops are keys removed along with all keys below them, after the renames are applied
+	drops []string
+}
+
+// migrations returns the migrations indexed by the version they upgrade from
+func migrations() []migration {
+	return []migration{
+		// Version 0 allowed nesting the serial settings of the proxy below proxy.serial, version 1 only has the
+		// flat keys of the proxy section. Version 0 ignored the other serial settings and always used 8N1
+		// framing, so they are dropped rather than applied to configs which never had an effect
+		{
+			renames: map[string]string{
+				"proxy.serial.port":         proxyconfig.ViperRealPort,
+				"proxy.serial.real-port":    proxyconfig.ViperRealPort,
+				"proxy.serial.virtual-port": proxyconfig.ViperVirtualPort,
+				"proxy.serial.baud-rate":    proxyconfig.ViperBaudRate,
+				"proxy.serial.buffer-size":  proxyconfig.ViperBufferSize,
+			},
+			drops: []string{"proxy.serial"},
+		},
+	}
+}
+
+// knownKeys returns the keys of the current schema
+func knownKeys() []string {
+	return []string{
+		VersionKey,
+		logconfig.ViperFormat, logconfig.ViperLevel, logconfig.ViperOutput, logconfig.ViperVerbose,
+		proxyconfig.ViperBaudRate, proxyconfig.ViperBufferSize, proxyconfig.ViperVirtualPort, proxyconfig.ViperRealPort,
+		proxyconfig.ViperListen, proxyconfig.ViperListenGRPC, proxyconfig.ViperOverwrite, proxyconfig.ViperExec,
+		proxyconfig.ViperReplay,
+		proxyconfig.ViperCapture, proxyconfig.ViperFramingMode, proxyconfig.ViperFramingPrompt,
+		proxyconfig.ViperFramingIdle, proxyconfig.ViperInclude, proxyconfig.ViperExclude, proxyconfig.ViperShapeRate,
+		proxyconfig.ViperShapeRTT, proxyconfig.ViperShapeJitter, proxyconfig.ViperShapeRequestRate,
+		proxyconfig.ViperShapeRequestLatency, proxyconfig.ViperShapeRequestJitter, proxyconfig.ViperShapeResponseRate,
+		proxyconfig.ViperShapeResponseLatency, proxyconfig.ViperShapeResponseJitter, proxyconfig.ViperHealthAddr,
+		proxyconfig.ViperMetricsAddr, proxyconfig.ViperBind, proxyconfig.ViperDataBits, proxyconfig.ViperParity,
+		proxyconfig.ViperStopBits,
+		proxyconfig.ViperRecordStdout, proxyconfig.ViperRecordSink, proxyconfig.ViperRecordingMaxSize, proxyconfig.ViperRecordingMaxAge,
+		proxyconfig.ViperRecordingMaxFiles, proxyconfig.ViperRecordingCompression, proxyconfig.ViperObserver,
+		proxyconfig.ViperTLSCertFile, proxyconfig.ViperTLSKeyFile, proxyconfig.ViperTLSCAFile,
+		proxyconfig.ViperTokenFile, proxyconfig.ViperAllowUnauthenticated,
+		emulatorconfig.ViperBufferSize, emulatorconfig.ViperVirtualPort, emulatorconfig.ViperListen,
+		emulatorconfig.ViperExtraPorts, emulatorconfig.ViperEngine, emulatorconfig.ViperExec, emulatorconfig.ViperSeed,
+		emulatorconfig.ViperHealthAddr, emulatorconfig.ViperBind, emulatorconfig.ViperProtocol,
+		emulatorconfig.ViperPersonality, emulatorconfig.ViperStateFile, emulatorconfig.ViperControlAddr,
+		emulatorconfig.ViperTLSCertFile, emulatorconfig.ViperTLSKeyFile, emulatorconfig.ViperTLSCAFile,
+		emulatorconfig.ViperTokenFile, emulatorconfig.ViperAllowUnauthenticated,
+		generatorconfig.ViperBaudRate, generatorconfig.ViperBufferSize, generatorconfig.ViperPort,
+		generatorconfig.ViperOutput, generatorconfig.ViperIdle, generatorconfig.ViperSuite,
+		generatorconfig.ViperDataBits, generatorconfig.ViperParity, generatorconfig.ViperStopBits,
+		terminalconfig.ViperBaudRate, terminalconfig.ViperBufferSize, terminalconfig.ViperPort,
+		terminalconfig.ViperLineEnding, terminalconfig.ViperStripANSI, terminalconfig.ViperHistory,
+		terminalconfig.ViperLog, terminalconfig.ViperDTRPulse,
+		execconfig.ViperBaudRate, execconfig.ViperPort, execconfig.ViperRaw, execconfig.ViperWait, execconfig.ViperJSON,
+		execconfig.ViperDTRPulse,
+		ctlconfig.ViperKubeconfig, ctlconfig.ViperContext, ctlconfig.ViperNamespace, ctlconfig.ViperWait,
+		ctlconfig.ViperTimeout,
+	}
+}
+
+// knownTrees returns the keys of the current schema holding structured values, the keys below them are
+// not checked
+func knownTrees() []string {
+	return []string{
+		proxyconfig.ViperRedact,
+		proxyconfig.ViperSinks,
+		proxyconfig.ViperObservers,
+		proxyconfig.ViperPorts,
+		generatorconfig.ViperPorts,
+		emulatorconfig.ViperPassthrough,
+		emulatorconfig.ViperPrefix + ".scenario",
+		emulatorconfig.ViperPrefix + ".faults",
+		emulatorconfig.ViperPrefix + ".failures",
+		emulatorconfig.ViperPrefix + ".control",
+		emulatorconfig.ViperPrefix + ".hardware",
+		emulatorconfig.ViperPrefix + ".recordings",
+		emulatorconfig.ViperPrefix + ".mappings",
+		generatorconfig.ViperPrefix + ".requests",
+	}
+}
+
+// isBelow returns whether key is prefix or one of the keys below it
+func isBelow(key, prefix string) bool {
+	return key == prefix || strings.HasPrefix(key, prefix+".")
+}
+
+// isKnown returns whether key is part of the current schema. Viper lowercases the keys it reads, while some
+// keys of the schema are camel case.
+func isKnown(key string) bool {
+	return slices.ContainsFunc(knownKeys(), func(known string) bool { return strings.EqualFold(known, key) }) ||
+		slices.ContainsFunc(knownTrees(), func(tree string) bool { return isBelow(key, tree) })
+}
+
+// Migrate upgrades the config read into in to the current schema version, returning the migrated config
+// and a report of the changes. Configs without a version are treated as version 0.
+func Migrate(in *viper.Viper) (*viper.Viper, *Report, error) {
+	version := in.GetInt(VersionKey)
+	if version < 0 || version > CurrentVersion {
+		return nil, nil, fmt.Errorf("%w: %d (supported versions are 0 to %d)", ErrUnsupportedVersion, version,
+			CurrentVersion)
+	}
+
+	report := &Report{
+		FromVersion: version,
+		ToVersion:   CurrentVersion,
+		Renamed:     []Rename{},
+		Dropped:     []string{},
+		Unknown:     []string{},
+	}
+
+	values := map[string]any{}
+	for _, key := range in.AllKeys() {
+		if key != VersionKey {
+			values[key] = in.Get(key)
+		}
+	}
+
+	steps := migrations()
+	for ; version < CurrentVersion; version++ {
+		steps[version].apply(values, report)
+	}
+
+	out := viper.New()
+	for _, key := range slices.Sorted(maps.Keys(values)) {
+		if !isKnown(key) {
+			report.Unknown = append(report.Unknown, key)
+		}
+
+		out.Set(key, values[key])
+	}
+	out.Set(VersionKey, CurrentVersion)
+
+	return out, report, nil
+}
+
+// apply applies the migration to the flattened config values, recording the changes in report
+func (m migration) apply(values map[string]any, report *Report) {
+	for _, key := range slices.Sorted(maps.Keys(values)) {
+		to, ok := m.renames[key]
+		if !ok {
+			continue
+		}
+
+		value := values[key]
+		delete(values, key)
+
+		// A key already set in the new schema takes precedence over its old key
+		if _, exists := values[to]; exists {
+			report.Dropped = append(report.Dropped, key)
+			continue
+		}
+
+		values[to] = value
+		report.Renamed = append(report.Renamed, Rename{From: key, To: to})
+	}
+
+	for _, key := range slices.Sorted(maps.Keys(values)) {
+		if slices.ContainsFunc(m.drops, func(drop string) bool { return isBelow(key, drop) }) {
+			delete(values, key)
+			report.Dropped = append(report.Dropped, key)
+		}
+	}
+}
@@ -0,0 +1,127 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package migrate_test
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	. "github.com/onsi/gomega"
+	"github.com/spf13/viper"
+
+	"github.com/detiber/k8s-jumperless/utils/internal/migrate"
+)
+
+func readConfig(t *testing.T, config string) *viper.Viper {
+	t.Helper()
+
+	v := viper.New()
+	v.SetConfigType("yaml")
+	if err := v.ReadConfig(strings.NewReader(config)); err != nil {
+		t.Fatalf("failed to read config: %v", err)
+	}
+
+	return v
+}
+
+func TestMigrateLegacySerialConfig(t *testing.T) {
+	g := NewWithT(t)
+
+	in := readConfig(t, `
+proxy:
+  serial:
+    port: /dev/ttyACM0
+    virtual-port: /tmp/jumperless
+    baud-rate: 9600
+    parity: none
+  overwrite: true
+emulator:
+  mappings:
+    - request: '?'
+`)
+
+	out, report, err := migrate.Migrate(in)
+	g.Expect(err).NotTo(HaveOccurred())
+
+	g.Expect(out.GetInt(migrate.VersionKey)).To(Equal(migrate.CurrentVersion))
+	g.Expect(out.GetString("proxy.real-port")).To(Equal("/dev/ttyACM0"))
+	g.Expect(out.GetString("proxy.virtual-port")).To(Equal("/tmp/jumperless"))
+	g.Expect(out.GetInt("proxy.baud-rate")).To(Equal(9600))
+	g.Expect(out.GetBool("proxy.overwrite")).To(BeTrue())
+	g.Expect(out.IsSet("proxy.serial")).To(BeFalse())
+	g.Expect(out.Get("emulator.mappings")).To(HaveLen(1))
+
+	g.Expect(report.FromVersion).To(Equal(0))
+	g.Expect(report.ToVersion).To(Equal(migrate.CurrentVersion))
+	g.Expect(report.Renamed).To(ConsistOf(
+		migrate.Rename{From: "proxy.serial.port", To: "proxy.real-port"},
+		migrate.Rename{From: "proxy.serial.virtual-port", To: "proxy.virtual-port"},
+		migrate.Rename{From: "proxy.serial.baud-rate", To: "proxy.baud-rate"},
+	))
+	g.Expect(report.Dropped).To(ConsistOf("proxy.serial.parity"))
+	g.Expect(report.Unknown).To(BeEmpty())
+}
+
+func TestMigrateKeepsNewKeys(t *testing.T) {
+	g := NewWithT(t)
+
+	in := readConfig(t, `
+proxy:
+  real-port: /dev/ttyACM1
+  serial:
+    real-port: /dev/ttyACM0
+`)
+
+	out, report, err := migrate.Migrate(in)
+	g.Expect(err).NotTo(HaveOccurred())
+
+	g.Expect(out.GetString("proxy.real-port")).To(Equal("/dev/ttyACM1"))
+	g.Expect(report.Renamed).To(BeEmpty())
+	g.Expect(report.Dropped).To(ConsistOf("proxy.serial.real-port"))
+}
+
+func TestMigrateReportsUnknownKeys(t *testing.T) {
+	g := NewWithT(t)
+
+	in := readConfig(t, `
+version: 1
+log-level: debug
+proxy:
+  real-port: /dev/ttyACM0
+  colour: blue
+emulator:
+  faults:
+    drop-rate: 0.1
+`)
+
+	out, report, err := migrate.Migrate(in)
+	g.Expect(err).NotTo(HaveOccurred())
+
+	g.Expect(out.GetString("proxy.colour")).To(Equal("blue"))
+	g.Expect(report.FromVersion).To(Equal(1))
+	g.Expect(report.Renamed).To(BeEmpty())
+	g.Expect(report.Dropped).To(BeEmpty())
+	g.Expect(report.Unknown).To(ConsistOf("proxy.colour"))
+}
+
+func TestMigrateUnsupportedVersion(t *testing.T) {
+	g := NewWithT(t)
+
+	_, _, err := migrate.Migrate(readConfig(t, "version: 99\n"))
+	g.Expect(errors.Is(err, migrate.ErrUnsupportedVersion)).To(BeTrue())
+}
@@ -0,0 +1,373 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package optimize post-processes recorded emulator configs. Recordings contain a mapping for every request the
+// client sent, e.g. ">dac_get(0)" and ">dac_get(1)", which optimize merges into regex mappings rendering the
+// response of each request with a template.
+package optimize
+
+import (
+	"fmt"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	emulatorconfig "github.com/detiber/k8s-jumperless/utils/internal/emulator/config"
+)
+
+// numberPattern matches the numeric arguments of a request, but not digits within names such as i2c_scan
+var numberPattern = regexp.MustCompile(`\b\d+(?:\.\d+)?\b`) //nolint:gochecknoglobals
+
+// Markers replacing the echo of the request in normalized responses, private use runes never sent by the device
+const (
+	requestMarker = "\ue000" // The request, e.g. ">dac_get(0)"
+	commandMarker = "\ue001" // The request without the prompt character, e.g. "dac_get(0)"
+)
+
+// Report describes the changes made by Mappings
+type Report struct {
+	// Mappings is the number of mappings before and Optimized the number after optimizing
+	Mappings  int
+	Optimized int
+
+	// DuplicateResponses is the number of responses dropped since the same request had the same response
+	DuplicateResponses int
+
+	// Parameterized are the regex mappings that replaced the mappings of similar requests
+	Parameterized []Parameterized
+}
+
+// Parameterized is a regex mapping that replaced the mappings of similar requests
+type Parameterized struct {
+	Request  string
+	Replaces []string
+}
+
+// Mappings deduplicates and parameterizes recorded mappings. Mappings of the same exact request are merged and
+// their identical responses dropped. Requests that only differ in their numeric arguments, e.g. ">dac_get(0)"
+// and ">dac_get(1)", are replaced by a regex mapping matching the recorded arguments, with the arguments that
+// differ captured as the groups arg0, arg1, ... The response is a template echoing the request with the
+// captured arguments, and choosing the parts of the responses that differ by the arguments. Regex and
+// template mappings, and requests with several different responses, are kept as they are.
+func Mappings(mappings emulatorconfig.Mappings) (emulatorconfig.Mappings, Report) {
+	report := Report{Mappings: len(mappings)}
+
+	merged := emulatorconfig.Mappings{}
+	recorded := map[string]int{}
+	for _, mapping := range mappings {
+		i, ok := recorded[mapping.Request]
+		switch {
+		case !isRecorded(mapping):
+			merged = append(merged, mapping)
+		case ok:
+			merged[i].Responses = append(slices.Clone(merged[i].Responses), mapping.Responses...)
+		default:
+			recorded[mapping.Request] = len(merged)
+			merged = append(merged, mapping)
+		}
+	}
+
+	for i, mapping := range merged {
+		if !isRecorded(mapping) {
+			continue
+		}
+
+		responses := []emulatorconfig.ResponseOption{}
+		for _, response := range mapping.Responses {
+			if slices.ContainsFunc(responses, func(r emulatorconfig.ResponseOption) bool { return sameData(r, response) }) {
+				report.DuplicateResponses++
+				continue
+			}
+			responses = append(responses, response)
+		}
+		merged[i].Responses = responses
+	}
+
+	// Cluster the requests by their shape, the request without its numeric arguments
+	clusters := map[string][]int{}
+	shapes := []string{}
+	for i, mapping := range merged {
+		if !isRecorded(mapping) || len(mapping.Responses) != 1 {
+			continue
+		}
+
+		request := strings.TrimSpace(mapping.Request)
+		if !numberPattern.MatchString(request) {
+			continue
+		}
+
+		shape := numberPattern.ReplaceAllString(request, "\x00")
+		if _, ok := clusters[shape]; !ok {
+			shapes = append(shapes, shape)
+		}
+		clusters[shape] = append(clusters[shape], i)
+	}
+
+	replaced := map[int]emulatorconfig.RequestResponse{}
+	removed := map[int]bool{}
+	for _, shape := range shapes {
+		members := clusters[shape]
+		if len(members) < 2 {
+			continue
+		}
+
+		cluster := make([]emulatorconfig.RequestResponse, 0, len(members))
+		for _, i := range members {
+			cluster = append(cluster, merged[i])
+		}
+
+		mapping, ok := parameterize(cluster)
+		if !ok {
+			continue
+		}
+
+		replaced[members[0]] = mapping
+		for _, i := range members[1:] {
+			removed[i] = true
+		}
+
+		parameterized := Parameterized{Request: mapping.Request}
+		for _, member := range cluster {
+			parameterized.Replaces = append(parameterized.Replaces, member.Request)
+		}
+		report.Parameterized = append(report.Parameterized, parameterized)
+	}
+
+	optimized := emulatorconfig.Mappings{}
+	for i, mapping := range merged {
+		switch {
+		case removed[i]:
+		case replaced[i].Request != "":
+			optimized = append(optimized, replaced[i])
+		default:
+			optimized = append(optimized, mapping)
+		}
+	}
+	report.Optimized = len(optimized)
+
+	return optimized, report
+}
+
+// isRecorded reports whether a mapping is an exact mapping as written by the recorder
+func isRecorded(mapping emulatorconfig.RequestResponse) bool {
+	return (mapping.Match == "" || mapping.Match == emulatorconfig.MatchExact) && !mapping.Template &&
+		mapping.Select == ""
+}
+
+// sameData reports whether two responses send the same data, regardless of their timing
+func sameData(a, b emulatorconfig.ResponseOption) bool {
+	return slices.EqualFunc(a.Chunks, b.Chunks, func(x, y emulatorconfig.ResponseChunk) bool { return x.Data == y.Data })
+}
+
+// parameterize returns a regex mapping answering the requests of the cluster, which have the same shape and a
+// single response each. It returns false if the responses can't be expressed as a template.
+func parameterize(cluster []emulatorconfig.RequestResponse) (emulatorconfig.RequestResponse, bool) {
+	chunks := len(cluster[0].Responses[0].Chunks)
+
+	// The arguments of every request, and the positions of the arguments that differ between the requests
+	args := make([][]string, 0, len(cluster))
+	for _, mapping := range cluster {
+		if len(mapping.Responses[0].Chunks) != chunks {
+			return emulatorconfig.RequestResponse{}, false
+		}
+		args = append(args, numberPattern.FindAllString(strings.TrimSpace(mapping.Request), -1))
+	}
+
+	varying := make([]bool, len(args[0]))
+	for position := range varying {
+		for _, a := range args[1:] {
+			if a[position] != args[0][position] {
+				varying[position] = true
+			}
+		}
+	}
+
+	request := strings.TrimSpace(cluster[0].Request)
+	if strings.Contains(request, "{{") {
+		return emulatorconfig.RequestResponse{}, false
+	}
+	pattern, requestTemplate := requestPattern(request, args, varying)
+
+	// Normalize the responses so the echo of the requests doesn't make them differ
+	normalized := make([][]string, 0, len(cluster))
+	for _, mapping := range cluster {
+		texts := make([]string, 0, chunks)
+		for _, chunk := range mapping.Responses[0].Chunks {
+			text, err := strconv.Unquote(chunk.Data)
+			if err != nil || strings.Contains(text, "{{") || strings.ContainsAny(text, requestMarker+commandMarker) {
+				return emulatorconfig.RequestResponse{}, false
+			}
+			texts = append(texts, normalize(text, strings.TrimSpace(mapping.Request)))
+		}
+		normalized = append(normalized, texts)
+	}
+
+	expand := func(text string) string {
+		text = strings.ReplaceAll(text, requestMarker, requestTemplate)
+		return strings.ReplaceAll(text, commandMarker, strings.TrimPrefix(requestTemplate, ">"))
+	}
+
+	response := emulatorconfig.ResponseOption{}
+	for c, chunk := range cluster[0].Responses[0].Chunks {
+		texts := make([]string, 0, len(normalized))
+		for _, member := range normalized {
+			texts = append(texts, member[c])
+		}
+
+		prefix, middles, suffix := splitCommon(texts)
+
+		var b strings.Builder
+		b.WriteString(expand(prefix))
+		if middles != nil {
+			for m, middle := range middles {
+				if m == 0 {
+					b.WriteString("{{if ")
+				} else {
+					b.WriteString("{{else if ")
+				}
+				b.WriteString(argumentCondition(args[m], varying) + "}}" + expand(middle))
+			}
+			b.WriteString("{{end}}")
+		}
+		b.WriteString(expand(suffix))
+
+		response.Chunks = append(response.Chunks, emulatorconfig.ResponseChunk{
+			Data:      strconv.Quote(b.String()),
+			Delay:     chunk.Delay,
+			JitterMax: chunk.JitterMax,
+		})
+	}
+
+	return emulatorconfig.RequestResponse{
+		Request:   pattern,
+		Match:     emulatorconfig.MatchRegex,
+		Template:  true,
+		Responses: []emulatorconfig.ResponseOption{response},
+	}, true
+}
+
+// requestPattern returns the regular expression matching the requests of a cluster, capturing the arguments
+// that differ as argN, and the template rendering the request from the captured arguments
+func requestPattern(request string, args [][]string, varying []bool) (string, string) {
+	var pattern, requestTemplate strings.Builder
+
+	literals := numberPattern.Split(request, -1)
+	for position, literal := range literals {
+		pattern.WriteString(regexp.QuoteMeta(literal))
+		requestTemplate.WriteString(literal)
+
+		if position == len(varying) {
+			break
+		}
+
+		if !varying[position] {
+			pattern.WriteString(regexp.QuoteMeta(args[0][position]))
+			requestTemplate.WriteString(args[0][position])
+			continue
+		}
+
+		values := []string{}
+		for _, a := range args {
+			values = append(values, regexp.QuoteMeta(a[position]))
+		}
+		slices.Sort(values)
+
+		fmt.Fprintf(&pattern, "(?P<arg%d>%s)", position, strings.Join(slices.Compact(values), "|"))
+		fmt.Fprintf(&requestTemplate, "{{$arg%d}}", position)
+	}
+
+	return pattern.String(), requestTemplate.String()
+}
+
+// normalize replaces the echo of the request in a response with markers
+func normalize(text, request string) string {
+	text = strings.ReplaceAll(text, request, requestMarker)
+	if command, ok := strings.CutPrefix(request, ">"); ok && command != "" {
+		text = strings.ReplaceAll(text, command, commandMarker)
+	}
+
+	return text
+}
+
+// argumentCondition returns the template condition matching the differing arguments of a request
+func argumentCondition(args []string, varying []bool) string {
+	conditions := []string{}
+	for position, arg := range args {
+		if varying[position] {
+			conditions = append(conditions, fmt.Sprintf("eq $arg%d %q", position, arg))
+		}
+	}
+
+	if len(conditions) == 1 {
+		return conditions[0]
+	}
+
+	return "and (" + strings.Join(conditions, ") (") + ")"
+}
+
+// splitCommon splits texts into the prefix and suffix they have in common and the differing middles, which
+// are nil if all texts are the same. The split never cuts a rune, so the markers stay intact, nor a value, so
+// the middles are whole values like "3.30" instead of the digits that differ.
+func splitCommon(texts []string) (string, []string, string) {
+	if !slices.ContainsFunc(texts, func(text string) bool { return text != texts[0] }) {
+		return texts[0], nil, ""
+	}
+
+	prefix := texts[0]
+	for _, text := range texts[1:] {
+		n := 0
+		for n < len(prefix) && n < len(text) && prefix[n] == text[n] {
+			n++
+		}
+		for n > 0 && n < len(prefix) && !utf8.RuneStart(prefix[n]) {
+			n--
+		}
+		for n > 0 && isValueByte(prefix[n-1]) {
+			n--
+		}
+		prefix = prefix[:n]
+	}
+
+	suffix := texts[0][len(prefix):]
+	for _, text := range texts[1:] {
+		rest := text[len(prefix):]
+		n := 0
+		for n < len(suffix) && n < len(rest) && suffix[len(suffix)-1-n] == rest[len(rest)-1-n] {
+			n++
+		}
+		for n > 0 && n < len(suffix) && !utf8.RuneStart(suffix[len(suffix)-n]) {
+			n--
+		}
+		for n > 0 && isValueByte(suffix[len(suffix)-n]) {
+			n--
+		}
+		suffix = suffix[len(suffix)-n:]
+	}
+
+	middles := make([]string, 0, len(texts))
+	for _, text := range texts {
+		middles = append(middles, text[len(prefix):len(text)-len(suffix)])
+	}
+
+	return prefix, middles, suffix
+}
+
+// isValueByte reports whether b is part of a value like a number or a node name
+func isValueByte(b byte) bool {
+	return b >= '0' && b <= '9' || b >= 'a' && b <= 'z' || b >= 'A' && b <= 'Z' || b == '.' || b == '_' || b == '-'
+}
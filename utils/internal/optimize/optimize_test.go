@@ -0,0 +1,180 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package optimize_test
+
+import (
+	"regexp"
+	"strconv"
+	"testing"
+
+	. "github.com/onsi/gomega"
+
+	"github.com/detiber/k8s-jumperless/utils/internal/emulator"
+	emulatorconfig "github.com/detiber/k8s-jumperless/utils/internal/emulator/config"
+	"github.com/detiber/k8s-jumperless/utils/internal/optimize"
+)
+
+// recorded returns a mapping as written by the recorder, with one response sending chunks
+func recorded(request string, chunks ...string) emulatorconfig.RequestResponse {
+	response := emulatorconfig.ResponseOption{}
+	for _, chunk := range chunks {
+		response.Chunks = append(response.Chunks, emulatorconfig.ResponseChunk{Data: strconv.Quote(chunk)})
+	}
+
+	return emulatorconfig.RequestResponse{Request: request, Responses: []emulatorconfig.ResponseOption{response}}
+}
+
+// answer renders the response of a regex template mapping to a request like the emulator does
+func answer(t *testing.T, mapping emulatorconfig.RequestResponse, request string) []string {
+	t.Helper()
+
+	pattern := regexp.MustCompile(`^(?:` + mapping.Request + `)$`)
+	match := pattern.FindStringSubmatch(request)
+	if match == nil {
+		t.Fatalf("pattern %q doesn't match %q", mapping.Request, request)
+	}
+
+	groups := map[string]string{}
+	for i, name := range pattern.SubexpNames() {
+		groups[strconv.Itoa(i)] = match[i]
+		if name != "" {
+			groups[name] = match[i]
+		}
+	}
+
+	rendered := []string{}
+	for _, chunk := range mapping.Responses[0].Chunks {
+		text, err := emulator.RenderChunk(chunk)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		text, err = emulator.RenderTemplate(text, emulator.TemplateData{Request: request, Groups: groups})
+		if err != nil {
+			t.Fatal(err)
+		}
+		rendered = append(rendered, text)
+	}
+
+	return rendered
+}
+
+func TestMappingsParameterizesArguments(t *testing.T) {
+	g := NewWithT(t)
+
+	mappings := emulatorconfig.Mappings{
+		recorded("?", "Jumperless firmware version: 5.3.1.0\r\n"),
+		recorded(">dac_get(0)", "Python> dac_get(0)\r\n", "3.30\r\n"),
+		recorded(">dac_get(1)", "Python> dac_get(1)\r\n", "0.00\r\n"),
+		recorded(">dac_get(2)", "Python> dac_get(2)\r\n", "-1.25\r\n"),
+	}
+
+	optimized, report := optimize.Mappings(mappings)
+
+	g.Expect(optimized).To(HaveLen(2))
+	g.Expect(optimized[0]).To(Equal(mappings[0]))
+
+	mapping := optimized[1]
+	g.Expect(mapping.Match).To(Equal(emulatorconfig.MatchRegex))
+	g.Expect(mapping.Template).To(BeTrue())
+	g.Expect(mapping.Request).To(Equal(`>dac_get\((?P<arg0>0|1|2)\)`))
+
+	for _, original := range mappings[1:] {
+		expected := []string{}
+		for _, chunk := range original.Responses[0].Chunks {
+			text, err := emulator.RenderChunk(chunk)
+			g.Expect(err).NotTo(HaveOccurred())
+			expected = append(expected, text)
+		}
+
+		g.Expect(answer(t, mapping, original.Request)).To(Equal(expected), original.Request)
+	}
+
+	g.Expect(report.Mappings).To(Equal(4))
+	g.Expect(report.Optimized).To(Equal(2))
+	g.Expect(report.Parameterized).To(ConsistOf(optimize.Parameterized{
+		Request:  mapping.Request,
+		Replaces: []string{">dac_get(0)", ">dac_get(1)", ">dac_get(2)"},
+	}))
+}
+
+func TestMappingsParameterizesSeveralArguments(t *testing.T) {
+	g := NewWithT(t)
+
+	mappings := emulatorconfig.Mappings{
+		recorded(">connect(1, 2)", "Python> connect(1, 2)\r\nTrue\r\n"),
+		recorded(">connect(1, 3)", "Python> connect(1, 3)\r\nTrue\r\n"),
+		recorded(">connect(5, 3)", "Python> connect(5, 3)\r\nFalse\r\n"),
+	}
+
+	optimized, _ := optimize.Mappings(mappings)
+	g.Expect(optimized).To(HaveLen(1))
+	g.Expect(optimized[0].Request).To(Equal(`>connect\((?P<arg0>1|5), (?P<arg1>2|3)\)`))
+
+	for _, original := range mappings {
+		text, err := emulator.RenderChunk(original.Responses[0].Chunks[0])
+		g.Expect(err).NotTo(HaveOccurred())
+		g.Expect(answer(t, optimized[0], original.Request)).To(Equal([]string{text}), original.Request)
+	}
+}
+
+func TestMappingsMergesDuplicateRequests(t *testing.T) {
+	g := NewWithT(t)
+
+	slow := recorded("~", "config\r\n")
+	slow.Responses[0].Chunks[0].Delay = 50
+
+	mappings := emulatorconfig.Mappings{
+		recorded("~", "config\r\n"),
+		recorded("?", "Jumperless firmware version: 5.3.1.0\r\n"),
+		slow,
+		recorded("~", "other config\r\n"),
+	}
+
+	optimized, report := optimize.Mappings(mappings)
+
+	g.Expect(optimized).To(HaveLen(2))
+	g.Expect(optimized[0].Request).To(Equal("~"))
+	g.Expect(optimized[0].Responses).To(Equal([]emulatorconfig.ResponseOption{
+		mappings[0].Responses[0], mappings[3].Responses[0],
+	}))
+	g.Expect(optimized[1]).To(Equal(mappings[1]))
+	g.Expect(report.DuplicateResponses).To(Equal(1))
+	g.Expect(report.Parameterized).To(BeEmpty())
+}
+
+func TestMappingsKeepsMappingsThatCantBeParameterized(t *testing.T) {
+	g := NewWithT(t)
+
+	mappings := emulatorconfig.Mappings{
+		{
+			Request:   `>gpio_get\((\d+)\)`,
+			Match:     emulatorconfig.MatchRegex,
+			Responses: recorded("", "HIGH").Responses,
+		},
+		recorded(">oled_print(1)", "Python> oled_print(1)\r\n{{braces}}\r\n"),
+		recorded(">oled_print(2)", "Python> oled_print(2)\r\n"),
+		recorded(">dac_set(0, 1)", "Python> dac_set(0, 1)\r\n", "1.0\r\n"),
+		recorded(">dac_set(0, 2)", "Python> dac_set(0, 2)\r\n2.0\r\n"),
+		recorded(">i2c_scan()", "[]\r\n"),
+	}
+
+	optimized, report := optimize.Mappings(mappings)
+
+	g.Expect(optimized).To(Equal(mappings))
+	g.Expect(report.Parameterized).To(BeEmpty())
+}
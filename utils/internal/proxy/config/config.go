@@ -16,37 +16,202 @@ limitations under the License.
 
 package config
 
-import "github.com/spf13/viper"
+import (
+	"strings"
+	"time"
+
+	"github.com/spf13/viper"
+
+	"github.com/detiber/k8s-jumperless/internal/transport"
+	"github.com/detiber/k8s-jumperless/utils/internal/line"
+	"github.com/detiber/k8s-jumperless/utils/internal/server"
+)
 
 const (
 	// Default values for the proxy configuration
 	DefaultBaudRate   = 115200
 	DefaultBufferSize = 1024
 
+	// Default values for request framing
+	DefaultFraming       = FramingRead
+	DefaultFramingPrompt = "Python> "
+	DefaultFramingIdle   = 100 * time.Millisecond
+
+	// Request framing modes, deciding when the bytes sent by the client form a complete request
+	FramingRead    = "read"    // every read from the virtual port is a request
+	FramingNewline = "newline" // a request ends with a newline or carriage return
+	FramingPrompt  = "prompt"  // a request ends once the device responds with its prompt
+	FramingIdle    = "idle"    // a request ends once the client has been idle for the framing idle time
+
+	// Compression of rotated recording segments
+	CompressionNone = "none"
+	CompressionGzip = "gzip"
+	CompressionZstd = "zstd"
+
+	// Formats the recorded request/response pairs are streamed to stdout in as they happen
+	RecordStdoutNDJSON = "ndjson" // one JSON object per line
+
+	// Types of sinks the recorded request/response pairs are streamed to as they happen
+	SinkStdout = "stdout" // standard output
+	SinkFile   = "file"   // a file the entries are appended to
+	SinkTCP    = "tcp"    // a TCP endpoint, e.g. a bridge to a websocket dashboard
+
+	// Types of observers the proxied traffic is mirrored to
+	ObserverPTY = "pty" // a virtual serial port, e.g. for a terminal program
+	ObserverTCP = "tcp" // a TCP listener, e.g. for netcat
+
 	// Flag names for command-line arguments
-	FlagBaudRate    = "baud-rate"
-	FlagBufferSize  = "buffer-size"
-	FlagVirtualPort = "virtual-port"
-	FlagRealPort    = "real-port"
-	FlagOverwrite   = "overwrite"
+	FlagBaudRate      = "baud-rate"
+	FlagDataBits      = line.FlagDataBits
+	FlagParity        = line.FlagParity
+	FlagStopBits      = line.FlagStopBits
+	FlagBufferSize    = "buffer-size"
+	FlagVirtualPort   = "virtual-port"
+	FlagRealPort      = "real-port"
+	FlagListen        = "listen"
+	FlagListenGRPC    = "listen-grpc"
+	FlagOverwrite     = "overwrite"
+	FlagExec          = "exec"
+	FlagReplay        = "replay"
+	FlagCapture       = "capture"
+	FlagFraming       = "framing"
+	FlagFramingPrompt = "framing-prompt"
+	FlagFramingIdle   = "framing-idle"
+	FlagInclude       = "include"
+	FlagExclude       = "exclude"
+	FlagShapeRate     = "shape-rate"
+	FlagShapeRTT      = "shape-rtt"
+	FlagShapeJitter   = "shape-jitter"
+	FlagHealthAddr    = "health-addr"
+	FlagMetricsAddr   = "metrics-addr"
+	FlagBind          = "bind"
+	FlagRecordStdout  = "record-stdout"
+	FlagRecordSink    = "record-sink"
+	FlagObserver      = "observer"
+
+	FlagShapeRequestRate     = "shape-request-rate"
+	FlagShapeRequestLatency  = "shape-request-latency"
+	FlagShapeResponseRate    = "shape-response-rate"
+	FlagShapeResponseLatency = "shape-response-latency"
+
+	FlagRecordingMaxSize     = "recording-max-size"
+	FlagRecordingMaxAge      = "recording-max-age"
+	FlagRecordingMaxFiles    = "recording-max-files"
+	FlagRecordingCompression = "recording-compression"
+
+	FlagTLSCertFile          = server.FlagTLSCertFile
+	FlagTLSKeyFile           = server.FlagTLSKeyFile
+	FlagTLSCAFile            = server.FlagTLSCAFile
+	FlagTokenFile            = server.FlagTokenFile
+	FlagAllowUnauthenticated = server.FlagAllowUnauthenticated
 
 	// Viper prefix and keys for configuration
-	ViperPrefix      = "proxy"
-	ViperBaudRate    = ViperPrefix + "." + FlagBaudRate
-	ViperBufferSize  = ViperPrefix + "." + FlagBufferSize
-	ViperVirtualPort = ViperPrefix + "." + FlagVirtualPort
-	ViperRealPort    = ViperPrefix + "." + FlagRealPort
-	ViperOverwrite   = ViperPrefix + "." + FlagOverwrite
+	ViperPrefix        = "proxy"
+	ViperBaudRate      = ViperPrefix + "." + FlagBaudRate
+	ViperDataBits      = ViperPrefix + "." + FlagDataBits
+	ViperParity        = ViperPrefix + "." + FlagParity
+	ViperStopBits      = ViperPrefix + "." + FlagStopBits
+	ViperPorts         = ViperPrefix + "." + line.KeyPorts
+	ViperBufferSize    = ViperPrefix + "." + FlagBufferSize
+	ViperVirtualPort   = ViperPrefix + "." + FlagVirtualPort
+	ViperRealPort      = ViperPrefix + "." + FlagRealPort
+	ViperListen        = ViperPrefix + "." + FlagListen
+	ViperListenGRPC    = ViperPrefix + "." + FlagListenGRPC
+	ViperOverwrite     = ViperPrefix + "." + FlagOverwrite
+	ViperExec          = ViperPrefix + "." + FlagExec
+	ViperReplay        = ViperPrefix + "." + FlagReplay
+	ViperCapture       = ViperPrefix + "." + FlagCapture
+	ViperFraming       = ViperPrefix + "." + FlagFraming
+	ViperFramingMode   = ViperFraming + ".mode"
+	ViperFramingPrompt = ViperFraming + ".prompt"
+	ViperFramingIdle   = ViperFraming + ".idle"
+	ViperFilters       = ViperPrefix + ".filters"
+	ViperInclude       = ViperFilters + "." + FlagInclude
+	ViperExclude       = ViperFilters + "." + FlagExclude
+	ViperRedact        = ViperFilters + ".redact"
+	ViperShaping       = ViperPrefix + ".shaping"
+	ViperShapeRate     = ViperShaping + ".rate"
+	ViperShapeRTT      = ViperShaping + ".rtt"
+	ViperShapeJitter   = ViperShaping + ".jitter"
+	ViperHealthAddr    = ViperPrefix + "." + FlagHealthAddr
+	ViperMetricsAddr   = ViperPrefix + "." + FlagMetricsAddr
+	ViperBind          = ViperPrefix + "." + FlagBind
+	ViperRecordStdout  = ViperPrefix + "." + FlagRecordStdout
+	ViperRecordSink    = ViperPrefix + "." + FlagRecordSink
+	ViperSinks         = ViperPrefix + ".sinks"
+	ViperObserver      = ViperPrefix + "." + FlagObserver
+	ViperObservers     = ViperPrefix + ".observers"
+
+	ViperShapeRequests        = ViperShaping + ".requests"
+	ViperShapeRequestRate     = ViperShapeRequests + ".rate"
+	ViperShapeRequestLatency  = ViperShapeRequests + ".latency"
+	ViperShapeRequestJitter   = ViperShapeRequests + ".jitter"
+	ViperShapeResponses       = ViperShaping + ".responses"
+	ViperShapeResponseRate    = ViperShapeResponses + ".rate"
+	ViperShapeResponseLatency = ViperShapeResponses + ".latency"
+	ViperShapeResponseJitter  = ViperShapeResponses + ".jitter"
+
+	ViperRecording            = ViperPrefix + ".recording"
+	ViperRecordingMaxSize     = ViperRecording + ".maxSize"
+	ViperRecordingMaxAge      = ViperRecording + ".maxAge"
+	ViperRecordingMaxFiles    = ViperRecording + ".maxFiles"
+	ViperRecordingCompression = ViperRecording + ".compression"
+
+	ViperSecurity             = ViperPrefix + "." + server.KeySecurity
+	ViperTLSCertFile          = ViperSecurity + ".tlsCertFile"
+	ViperTLSKeyFile           = ViperSecurity + ".tlsKeyFile"
+	ViperTLSCAFile            = ViperSecurity + ".tlsCAFile"
+	ViperTokenFile            = ViperSecurity + ".tokenFile"
+	ViperAllowUnauthenticated = ViperSecurity + ".allowUnauthenticated"
 )
 
 // NewDefaultConfig returns a ProxyConfig with default values
 func NewDefaultConfig() *ProxyConfig {
 	return &ProxyConfig{
 		BaudRate:    DefaultBaudRate,
+		DataBits:    0,
+		Parity:      "",
+		StopBits:    "",
+		Ports:       []line.Override{},
 		BufferSize:  DefaultBufferSize,
 		VirtualPort: "",
 		RealPort:    "",
+		Listen:      "",
+		ListenGRPC:  "",
 		Overwrite:   false,
+		Exec:        "",
+		Replay:      "",
+		Capture:     "",
+		Framing: FramingConfig{
+			Mode:   DefaultFraming,
+			Prompt: DefaultFramingPrompt,
+			Idle:   DefaultFramingIdle,
+		},
+		Filters: FilterConfig{
+			Include: []string{},
+			Exclude: []string{},
+			Redact:  []RedactRule{},
+		},
+		Shaping: ShapingConfig{
+			Rate:      0,
+			RTT:       0,
+			Jitter:    0,
+			Requests:  LinkShapingConfig{},
+			Responses: LinkShapingConfig{},
+		},
+		HealthAddr:   "",
+		MetricsAddr:  "",
+		Bind:         server.DefaultBind,
+		RecordStdout: "",
+		Sinks:        []SinkConfig{},
+		Observers:    []ObserverConfig{},
+		Recording: RecordingConfig{
+			MaxSize:     0,
+			MaxAge:      0,
+			MaxFiles:    0,
+			Compression: CompressionNone,
+		},
+		Security: transport.Config{},
 	}
 }
 
@@ -57,6 +222,21 @@ func NewFromViper(v *viper.Viper) *ProxyConfig {
 	if v.IsSet(ViperBaudRate) {
 		cfg.BaudRate = v.GetInt(ViperBaudRate)
 	}
+	if v.IsSet(ViperDataBits) {
+		cfg.DataBits = v.GetInt(ViperDataBits)
+	}
+	if v.IsSet(ViperParity) {
+		cfg.Parity = v.GetString(ViperParity)
+	}
+	if v.IsSet(ViperStopBits) {
+		cfg.StopBits = v.GetString(ViperStopBits)
+	}
+	if v.IsSet(ViperPorts) {
+		if err := v.UnmarshalKey(ViperPorts, &cfg.Ports); err != nil {
+			// If unmarshaling fails, return an empty list of overrides
+			cfg.Ports = []line.Override{}
+		}
+	}
 	if v.IsSet(ViperBufferSize) {
 		cfg.BufferSize = v.GetInt(ViperBufferSize)
 	}
@@ -66,11 +246,170 @@ func NewFromViper(v *viper.Viper) *ProxyConfig {
 	if v.IsSet(ViperRealPort) {
 		cfg.RealPort = v.GetString(ViperRealPort)
 	}
+	if v.IsSet(ViperListen) {
+		cfg.Listen = v.GetString(ViperListen)
+	}
+	if v.IsSet(ViperListenGRPC) {
+		cfg.ListenGRPC = v.GetString(ViperListenGRPC)
+	}
 
 	if v.IsSet(ViperOverwrite) {
 		cfg.Overwrite = v.GetBool(ViperOverwrite)
 	}
 
+	if v.IsSet(ViperExec) {
+		cfg.Exec = v.GetString(ViperExec)
+	}
+
+	if v.IsSet(ViperReplay) {
+		cfg.Replay = v.GetString(ViperReplay)
+	}
+
+	if v.IsSet(ViperCapture) {
+		cfg.Capture = v.GetString(ViperCapture)
+	}
+
+	if v.IsSet(ViperFramingMode) {
+		cfg.Framing.Mode = v.GetString(ViperFramingMode)
+	}
+
+	if v.IsSet(ViperFramingPrompt) {
+		cfg.Framing.Prompt = v.GetString(ViperFramingPrompt)
+	}
+
+	if v.IsSet(ViperFramingIdle) {
+		cfg.Framing.Idle = v.GetDuration(ViperFramingIdle)
+	}
+
+	if v.IsSet(ViperInclude) {
+		cfg.Filters.Include = v.GetStringSlice(ViperInclude)
+	}
+
+	if v.IsSet(ViperExclude) {
+		cfg.Filters.Exclude = v.GetStringSlice(ViperExclude)
+	}
+
+	if v.IsSet(ViperRedact) {
+		if err := v.UnmarshalKey(ViperRedact, &cfg.Filters.Redact); err != nil {
+			// If unmarshaling fails, return an empty list of redaction rules
+			cfg.Filters.Redact = []RedactRule{}
+		}
+	}
+
+	if v.IsSet(ViperShapeRate) {
+		cfg.Shaping.Rate = v.GetInt(ViperShapeRate)
+	}
+
+	if v.IsSet(ViperShapeRTT) {
+		cfg.Shaping.RTT = v.GetDuration(ViperShapeRTT)
+	}
+
+	if v.IsSet(ViperShapeJitter) {
+		cfg.Shaping.Jitter = v.GetDuration(ViperShapeJitter)
+	}
+
+	if v.IsSet(ViperShapeRequestRate) {
+		cfg.Shaping.Requests.Rate = v.GetInt(ViperShapeRequestRate)
+	}
+
+	if v.IsSet(ViperShapeRequestLatency) {
+		cfg.Shaping.Requests.Latency = v.GetDuration(ViperShapeRequestLatency)
+	}
+
+	if v.IsSet(ViperShapeRequestJitter) {
+		cfg.Shaping.Requests.Jitter = v.GetDuration(ViperShapeRequestJitter)
+	}
+
+	if v.IsSet(ViperShapeResponseRate) {
+		cfg.Shaping.Responses.Rate = v.GetInt(ViperShapeResponseRate)
+	}
+
+	if v.IsSet(ViperShapeResponseLatency) {
+		cfg.Shaping.Responses.Latency = v.GetDuration(ViperShapeResponseLatency)
+	}
+
+	if v.IsSet(ViperShapeResponseJitter) {
+		cfg.Shaping.Responses.Jitter = v.GetDuration(ViperShapeResponseJitter)
+	}
+
+	if v.IsSet(ViperHealthAddr) {
+		cfg.HealthAddr = v.GetString(ViperHealthAddr)
+	}
+
+	if v.IsSet(ViperMetricsAddr) {
+		cfg.MetricsAddr = v.GetString(ViperMetricsAddr)
+	}
+
+	if v.IsSet(ViperBind) {
+		cfg.Bind = v.GetString(ViperBind)
+	}
+
+	if v.IsSet(ViperRecordStdout) {
+		cfg.RecordStdout = v.GetString(ViperRecordStdout)
+	}
+
+	if v.IsSet(ViperSinks) {
+		if err := v.UnmarshalKey(ViperSinks, &cfg.Sinks); err != nil {
+			// If unmarshaling fails, return an empty list of sinks
+			cfg.Sinks = []SinkConfig{}
+		}
+	}
+
+	if v.IsSet(ViperRecordSink) {
+		for _, sink := range v.GetStringSlice(ViperRecordSink) {
+			cfg.Sinks = append(cfg.Sinks, ParseSink(sink))
+		}
+	}
+
+	if v.IsSet(ViperObservers) {
+		if err := v.UnmarshalKey(ViperObservers, &cfg.Observers); err != nil {
+			// If unmarshaling fails, return an empty list of observers
+			cfg.Observers = []ObserverConfig{}
+		}
+	}
+
+	if v.IsSet(ViperObserver) {
+		for _, observer := range v.GetStringSlice(ViperObserver) {
+			cfg.Observers = append(cfg.Observers, ParseObserver(observer))
+		}
+	}
+
+	if v.IsSet(ViperRecordingMaxSize) {
+		cfg.Recording.MaxSize = v.GetInt64(ViperRecordingMaxSize)
+	}
+
+	if v.IsSet(ViperRecordingMaxAge) {
+		cfg.Recording.MaxAge = v.GetDuration(ViperRecordingMaxAge)
+	}
+
+	if v.IsSet(ViperRecordingMaxFiles) {
+		cfg.Recording.MaxFiles = v.GetInt(ViperRecordingMaxFiles)
+	}
+
+	if v.IsSet(ViperRecordingCompression) {
+		cfg.Recording.Compression = v.GetString(ViperRecordingCompression)
+	}
+
+	if v.IsSet(ViperTLSCertFile) {
+		cfg.Security.CertFile = v.GetString(ViperTLSCertFile)
+	}
+
+	if v.IsSet(ViperTLSKeyFile) {
+		cfg.Security.KeyFile = v.GetString(ViperTLSKeyFile)
+	}
+
+	if v.IsSet(ViperTLSCAFile) {
+		cfg.Security.CAFile = v.GetString(ViperTLSCAFile)
+	}
+
+	if v.IsSet(ViperTokenFile) {
+		cfg.Security.TokenFile = v.GetString(ViperTokenFile)
+	}
+
+	if v.IsSet(ViperAllowUnauthenticated) {
+		cfg.Security.AllowUnauthenticated = v.GetBool(ViperAllowUnauthenticated)
+	}
+
 	return cfg
 }
 
@@ -81,4 +420,216 @@ type ProxyConfig struct {
 	VirtualPort string `json:"virtualPort" mapstructure:"virtualPort" yaml:"virtualPort"`
 	RealPort    string `json:"realPort"    mapstructure:"realPort"    yaml:"realPort"`
 	Overwrite   bool   `json:"overwrite"   mapstructure:"overwrite"   yaml:"overwrite"`
+
+	// DataBits, Parity and StopBits are the line settings of the real serial port, 8N1 unless set
+	DataBits int    `json:"dataBits,omitempty" mapstructure:"data-bits" yaml:"dataBits,omitempty"`
+	Parity   string `json:"parity,omitempty"   mapstructure:"parity"    yaml:"parity,omitempty"`
+	StopBits string `json:"stopBits,omitempty" mapstructure:"stop-bits" yaml:"stopBits,omitempty"`
+
+	// Ports overrides the baud rate and line settings of individual real serial ports
+	Ports []line.Override `json:"ports,omitempty" mapstructure:"ports" yaml:"ports,omitempty"`
+
+	// Listen is a TCP address to serve the virtual side on using RFC2217 instead of a virtual serial port
+	Listen string `json:"listen" mapstructure:"listen" yaml:"listen"`
+
+	// ListenGRPC is a TCP address to serve the virtual side on using the Remote gRPC service of the jumperless
+	// package instead of a virtual serial port, so the device can be driven by the operator through the proxy
+	ListenGRPC string `json:"listenGRPC" mapstructure:"listen-grpc" yaml:"listenGRPC"`
+
+	// Exec is a client command to run against the virtual port, the proxy stops when it exits
+	Exec string `json:"exec" mapstructure:"exec" yaml:"exec"`
+
+	// Replay is a recording to serve on the virtual port instead of forwarding to a real serial port
+	Replay string `json:"replay" mapstructure:"replay" yaml:"replay"`
+
+	// Capture is a pcap file to write the raw traffic in both directions to, alongside the recording
+	Capture string `json:"capture" mapstructure:"capture" yaml:"capture"`
+
+	// Framing decides which bytes sent by the client are recorded as a single request
+	Framing FramingConfig `json:"framing" mapstructure:"framing" yaml:"framing"`
+
+	// Filters decides which requests are recorded and redacts sensitive data from the recording
+	Filters FilterConfig `json:"filters" mapstructure:"filters" yaml:"filters"`
+
+	// Shaping slows down the link between the client and the proxy, the recording keeps the device timing
+	Shaping ShapingConfig `json:"shaping" mapstructure:"shaping" yaml:"shaping"`
+
+	// HealthAddr is a TCP address to serve the /healthz and /readyz probes on, disabled if empty
+	HealthAddr string `json:"healthAddr" mapstructure:"health-addr" yaml:"healthAddr"`
+
+	// MetricsAddr is a TCP address to serve the Prometheus /metrics endpoint on while recording, disabled if empty
+	MetricsAddr string `json:"metricsAddr" mapstructure:"metrics-addr" yaml:"metricsAddr"`
+
+	// Bind is the host network listeners without a host are bound to, listening on other interfaces than
+	// the loopback interface requires changing it
+	Bind string `json:"bind" mapstructure:"bind" yaml:"bind"`
+
+	// RecordStdout streams each recorded request/response pair to stdout as it happens in the given format,
+	// only ndjson is supported. Disabled if empty.
+	RecordStdout string `json:"recordStdout" mapstructure:"record-stdout" yaml:"recordStdout"`
+
+	// Sinks stream the recorded request/response pairs and port events as they happen, in addition to the
+	// recording saved when the proxy stops
+	Sinks []SinkConfig `json:"sinks,omitempty" mapstructure:"sinks" yaml:"sinks,omitempty"`
+
+	// Observers receive a copy of the traffic between the client and the device as it passes the proxy, they can
+	// watch the session but anything they write is discarded
+	Observers []ObserverConfig `json:"observers,omitempty" mapstructure:"observers" yaml:"observers,omitempty"`
+
+	// Recording rotates the recording into separate files, so the proxy can run as a passive tap for days
+	Recording RecordingConfig `json:"recording" mapstructure:"recording" yaml:"recording"`
+
+	// Security authenticates the clients of the RFC2217 listener, the Remote gRPC service and the TCP
+	// observers. They are only served on non-loopback addresses if clients have to authenticate.
+	Security transport.Config `json:"security" mapstructure:"security" yaml:"security"`
+}
+
+// SinkConfig is a sink the recorded request/response pairs and port events are streamed to
+type SinkConfig struct {
+	// Type is one of stdout, file or tcp
+	Type string `json:"type" mapstructure:"type" yaml:"type"`
+
+	// Target is the file to append to or the TCP address to connect to, unused for stdout
+	Target string `json:"target,omitempty" mapstructure:"target" yaml:"target,omitempty"`
+
+	// Format of the streamed entries, only ndjson is supported. Defaults to ndjson.
+	Format string `json:"format,omitempty" mapstructure:"format" yaml:"format,omitempty"`
+}
+
+// ObserverConfig is an observer the proxied traffic is mirrored to
+type ObserverConfig struct {
+	// Type is one of pty or tcp
+	Type string `json:"type" mapstructure:"type" yaml:"type"`
+
+	// Target is the path of the virtual serial port to create or the TCP address to listen on
+	Target string `json:"target" mapstructure:"target" yaml:"target"`
+}
+
+// LineSettings returns the baud rate and line settings of the real serial port, including its override
+func (c *ProxyConfig) LineSettings() line.Settings {
+	return line.Resolve(c.RealPort, line.Settings{
+		BaudRate: c.BaudRate,
+		DataBits: c.DataBits,
+		Parity:   c.Parity,
+		StopBits: c.StopBits,
+	}, c.Ports)
+}
+
+// ParseSink parses a sink given as type or type:target, e.g. stdout, file:traffic.ndjson or tcp:localhost:9000
+func ParseSink(s string) SinkConfig {
+	sinkType, target, _ := strings.Cut(s, ":")
+
+	return SinkConfig{Type: sinkType, Target: target}
+}
+
+// ParseObserver parses an observer given as type:target, e.g. pty:/tmp/jumperless-watch or tcp::9100
+func ParseObserver(s string) ObserverConfig {
+	observerType, target, _ := strings.Cut(s, ":")
+
+	return ObserverConfig{Type: observerType, Target: target}
+}
+
+// RecordingConfig rotates the recording into segments once it grows too large or too old. Each segment is
+// written next to the recording file as a recording file of its own, the requests recorded since the last
+// rotation are saved to the recording file when the proxy stops.
+type RecordingConfig struct {
+	// MaxSize rotates the recording once the recorded requests and responses exceed this many bytes,
+	// zero disables size based rotation
+	MaxSize int64 `json:"maxSize" mapstructure:"maxSize" yaml:"maxSize"`
+
+	// MaxAge rotates the recording once its first request is this old, zero disables time based rotation
+	MaxAge time.Duration `json:"maxAge" mapstructure:"maxAge" yaml:"maxAge"`
+
+	// MaxFiles is the number of rotated segments kept, the oldest are removed first. Zero keeps all segments.
+	MaxFiles int `json:"maxFiles" mapstructure:"maxFiles" yaml:"maxFiles"`
+
+	// Compression of the rotated segments, one of none, gzip or zstd
+	Compression string `json:"compression" mapstructure:"compression" yaml:"compression"`
+}
+
+// ShapingConfig emulates a slower link on the virtual side of the proxy, e.g. a device behind ser2net over a WAN
+type ShapingConfig struct {
+	// Rate limits the bandwidth in each direction in bytes per second, zero is unlimited
+	Rate int `json:"rate" mapstructure:"rate" yaml:"rate"`
+
+	// RTT is the round trip time added to the link, half of it delays each direction
+	RTT time.Duration `json:"rtt" mapstructure:"rtt" yaml:"rtt"`
+
+	// Jitter is the maximum random delay added to the data sent in each direction, data is still delivered
+	// in order
+	Jitter time.Duration `json:"jitter" mapstructure:"jitter" yaml:"jitter"`
+
+	// Requests overrides the shaping of the requests sent by the client to the device
+	Requests LinkShapingConfig `json:"requests" mapstructure:"requests" yaml:"requests"`
+
+	// Responses overrides the shaping of the responses sent by the device to the client
+	Responses LinkShapingConfig `json:"responses" mapstructure:"responses" yaml:"responses"`
+}
+
+// LinkShapingConfig shapes one direction of the link, unset fields keep the shaping of both directions
+type LinkShapingConfig struct {
+	// Rate limits the bandwidth in bytes per second, zero keeps the rate of both directions
+	Rate int `json:"rate,omitempty" mapstructure:"rate" yaml:"rate,omitempty"`
+
+	// Latency delays the data, zero keeps half the round trip time
+	Latency time.Duration `json:"latency,omitempty" mapstructure:"latency" yaml:"latency,omitempty"`
+
+	// Jitter is the maximum random delay added to the data, zero keeps the jitter of both directions
+	Jitter time.Duration `json:"jitter,omitempty" mapstructure:"jitter" yaml:"jitter,omitempty"`
+}
+
+// Link returns the effective shaping of one direction of the link, given its overrides
+func (c ShapingConfig) Link(overrides LinkShapingConfig) LinkShapingConfig {
+	link := LinkShapingConfig{
+		Rate:    c.Rate,
+		Latency: c.RTT / 2, //nolint:mnd
+		Jitter:  c.Jitter,
+	}
+
+	if overrides.Rate != 0 {
+		link.Rate = overrides.Rate
+	}
+
+	if overrides.Latency != 0 {
+		link.Latency = overrides.Latency
+	}
+
+	if overrides.Jitter != 0 {
+		link.Jitter = overrides.Jitter
+	}
+
+	return link
+}
+
+// FilterConfig filters and redacts the requests and responses recorded by the proxy
+type FilterConfig struct {
+	// Include are regular expressions, when set only requests matching any of them are recorded
+	Include []string `json:"include,omitempty" mapstructure:"include" yaml:"include,omitempty"`
+
+	// Exclude are regular expressions, requests matching any of them are not recorded
+	Exclude []string `json:"exclude,omitempty" mapstructure:"exclude" yaml:"exclude,omitempty"`
+
+	// Redact rules replace sensitive data in recorded requests and responses
+	Redact []RedactRule `json:"redact,omitempty" mapstructure:"redact" yaml:"redact,omitempty"`
+}
+
+// RedactRule replaces the matches of a regular expression in recorded requests and responses
+type RedactRule struct {
+	// Pattern is the regular expression to replace
+	Pattern string `json:"pattern" mapstructure:"pattern" yaml:"pattern"`
+
+	// Replacement replaces each match, $1 or ${name} refer to capture groups. Defaults to "[REDACTED]".
+	Replacement string `json:"replacement,omitempty" mapstructure:"replacement" yaml:"replacement,omitempty"`
+}
+
+// FramingConfig configures how the recorder splits the bytes sent by the client into requests
+type FramingConfig struct {
+	// Mode is one of read, newline, prompt or idle
+	Mode string `json:"mode" mapstructure:"mode" yaml:"mode"`
+
+	// Prompt is the device prompt ending a request in prompt mode
+	Prompt string `json:"prompt" mapstructure:"prompt" yaml:"prompt"`
+
+	// Idle is the time without client data ending a request in idle mode
+	Idle time.Duration `json:"idle" mapstructure:"idle" yaml:"idle"`
 }
@@ -0,0 +1,158 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package proxy
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"os"
+	"path/filepath"
+	"runtime"
+	"runtime/debug"
+	"strings"
+	"time"
+
+	"go.bug.st/serial/enumerator"
+
+	emulatorConfig "github.com/detiber/k8s-jumperless/utils/internal/emulator/config"
+)
+
+// CaptureEnvironment returns a snapshot of the environment a recording is made in. Details that can't be
+// determined on the host are left empty rather than failing the recording.
+func CaptureEnvironment(realPort, configFile string) emulatorConfig.RecordingMetadata {
+	return emulatorConfig.RecordingMetadata{
+		RecordedAt:   time.Now().UTC().Format(time.RFC3339),
+		OS:           hostOS(),
+		Kernel:       kernelRelease(),
+		SerialDriver: serialDriver(realPort),
+		Port:         realPort,
+		Device:       usbDescriptors(realPort),
+		UtilsVersion: utilsVersion(),
+		ConfigHash:   fileHash(configFile),
+	}
+}
+
+// hostOS returns the operating system and architecture, with the distribution name when available
+func hostOS() string {
+	platform := runtime.GOOS + "/" + runtime.GOARCH
+
+	data, err := os.ReadFile("/etc/os-release")
+	if err != nil {
+		return platform
+	}
+
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for scanner.Scan() {
+		if name, ok := strings.CutPrefix(scanner.Text(), "PRETTY_NAME="); ok {
+			return platform + " (" + strings.Trim(name, `"`) + ")"
+		}
+	}
+
+	return platform
+}
+
+// kernelRelease returns the release of the running Linux kernel
+func kernelRelease() string {
+	data, err := os.ReadFile("/proc/sys/kernel/osrelease")
+	if err != nil {
+		return ""
+	}
+
+	return strings.TrimSpace(string(data))
+}
+
+// serialDriver returns the name of the Linux kernel driver bound to a serial port, e.g. "cdc_acm"
+func serialDriver(portName string) string {
+	resolved, err := filepath.EvalSymlinks(portName)
+	if err != nil {
+		return ""
+	}
+
+	driver, err := filepath.EvalSymlinks(filepath.Join("/sys/class/tty", filepath.Base(resolved), "device", "driver"))
+	if err != nil {
+		return ""
+	}
+
+	return filepath.Base(driver)
+}
+
+// usbDescriptors returns the USB descriptors of the device behind a serial port, or nil if it is not a USB device
+func usbDescriptors(portName string) *emulatorConfig.USBDescriptors {
+	resolved, err := filepath.EvalSymlinks(portName)
+	if err != nil {
+		resolved = portName
+	}
+
+	ports, err := enumerator.GetDetailedPortsList()
+	if err != nil {
+		return nil
+	}
+
+	for _, details := range ports {
+		if (details.Name == portName || details.Name == resolved) && details.IsUSB {
+			return &emulatorConfig.USBDescriptors{
+				VID:          details.VID,
+				PID:          details.PID,
+				SerialNumber: details.SerialNumber,
+				Product:      details.Product,
+			}
+		}
+	}
+
+	return nil
+}
+
+// utilsVersion returns the module version of the running binary. Binaries built from a checkout without
+// module version information report the VCS revision instead.
+func utilsVersion() string {
+	info, ok := debug.ReadBuildInfo()
+	if !ok {
+		return "unknown"
+	}
+
+	if info.Main.Version != "" && info.Main.Version != "(devel)" {
+		return info.Main.Version
+	}
+
+	version := "(devel)"
+	for _, setting := range info.Settings {
+		switch setting.Key {
+		case "vcs.revision":
+			version += " " + setting.Value
+		case "vcs.modified":
+			if setting.Value == "true" {
+				version += "+dirty"
+			}
+		}
+	}
+
+	return version
+}
+
+// fileHash returns the hex encoded SHA-256 hash of a file, or an empty string if it can't be read
+func fileHash(path string) string {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return ""
+	}
+
+	sum := sha256.Sum256(data)
+
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,147 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package proxy
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
+
+	emulatorConfig "github.com/detiber/k8s-jumperless/utils/internal/emulator/config"
+	"github.com/detiber/k8s-jumperless/utils/internal/proxy/config"
+)
+
+var ErrInvalidFilter = errors.New("invalid filter")
+
+// defaultRedactReplacement replaces matches of redaction rules without a replacement
+const defaultRedactReplacement = "[REDACTED]"
+
+// redactRule is a compiled config.RedactRule
+type redactRule struct {
+	pattern     *regexp.Regexp
+	replacement string
+}
+
+// recordingFilter decides which requests are recorded and redacts them before they are saved
+type recordingFilter struct {
+	include []*regexp.Regexp
+	exclude []*regexp.Regexp
+	redact  []redactRule
+}
+
+// newRecordingFilter compiles the regular expressions of the filter config
+func newRecordingFilter(c config.FilterConfig) (*recordingFilter, error) {
+	f := &recordingFilter{}
+
+	var err error
+
+	if f.include, err = compileFilters(config.FlagInclude, c.Include); err != nil {
+		return nil, err
+	}
+
+	if f.exclude, err = compileFilters(config.FlagExclude, c.Exclude); err != nil {
+		return nil, err
+	}
+
+	for i, rule := range c.Redact {
+		pattern, err := regexp.Compile(rule.Pattern)
+		if err != nil {
+			return nil, fmt.Errorf("%w: redact rule %d: %w", ErrInvalidFilter, i, err)
+		}
+
+		replacement := rule.Replacement
+		if replacement == "" {
+			replacement = defaultRedactReplacement
+		}
+
+		f.redact = append(f.redact, redactRule{pattern: pattern, replacement: replacement})
+	}
+
+	return f, nil
+}
+
+func compileFilters(name string, filters []string) ([]*regexp.Regexp, error) {
+	compiled := make([]*regexp.Regexp, 0, len(filters))
+
+	for _, filter := range filters {
+		pattern, err := regexp.Compile(filter)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %s %q: %w", ErrInvalidFilter, name, filter, err)
+		}
+
+		compiled = append(compiled, pattern)
+	}
+
+	return compiled, nil
+}
+
+// records returns true if the request should be recorded, i.e. it matches any of the include filters,
+// if there are any, and none of the exclude filters
+func (f *recordingFilter) records(request string) bool {
+	included := len(f.include) == 0
+
+	for _, pattern := range f.include {
+		if pattern.MatchString(request) {
+			included = true
+			break
+		}
+	}
+
+	if !included {
+		return false
+	}
+
+	for _, pattern := range f.exclude {
+		if pattern.MatchString(request) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// redactText applies every redaction rule to the text
+func (f *recordingFilter) redactText(text string) string {
+	for _, rule := range f.redact {
+		text = rule.pattern.ReplaceAllString(text, rule.replacement)
+	}
+
+	return text
+}
+
+// redactResponse applies every redaction rule to each chunk of the response. Chunks are redacted
+// individually, so a match split across chunks is not redacted.
+func (f *recordingFilter) redactResponse(response emulatorConfig.ResponseOption) emulatorConfig.ResponseOption {
+	if len(f.redact) == 0 {
+		return response
+	}
+
+	redacted := response
+	redacted.Chunks = make([]emulatorConfig.ResponseChunk, 0, len(response.Chunks))
+
+	for _, chunk := range response.Chunks {
+		// Recorded chunk data is always quoted, leave anything else as it is
+		if data, err := strconv.Unquote(chunk.Data); err == nil {
+			chunk.Data = strconv.Quote(f.redactText(data))
+		}
+
+		redacted.Chunks = append(redacted.Chunks, chunk)
+	}
+
+	return redacted
+}
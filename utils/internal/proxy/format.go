@@ -0,0 +1,240 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package proxy
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"time"
+
+	"github.com/go-viper/mapstructure/v2"
+	"github.com/spf13/viper"
+
+	emulatorConfig "github.com/detiber/k8s-jumperless/utils/internal/emulator/config"
+)
+
+var ErrUnsupportedRecordingSchema = errors.New("unsupported recording schema version")
+
+// RecordingSchemaVersion is the version of the recording files written by the proxy. Files without a schema
+// version are emulator configs, or segments rotated by older versions, which only contain mappings.
+const RecordingSchemaVersion = 1
+
+// Keys of the sections of a recording file. The mappings are stored where the emulator config keeps them, so
+// the emulator serves a recording file as it is.
+const (
+	RecordingKeySchemaVersion = "schemaVersion"
+	RecordingKeySession       = "session"
+	RecordingKeyEntries       = "entries"
+	RecordingKeyMappings      = emulatorConfig.ViperPrefix + ".mappings"
+)
+
+// Recording is the content of a recording file. Every section is optional, a file may contain only some of
+// them, e.g. the entries streamed to a sink or the mappings of an emulator config.
+type Recording struct {
+	// SchemaVersion is the version of the format of the file, zero if it wasn't versioned
+	SchemaVersion int `json:"schemaVersion" mapstructure:"schemaVersion" yaml:"schemaVersion"`
+
+	// Session describes the environment the recording was made in and the port events requested by the client
+	Session *emulatorConfig.RecordingMetadata `json:"session,omitempty" mapstructure:"session" yaml:"session,omitempty"`
+
+	// Entries are the raw request/response pairs in the order they were recorded
+	Entries []StreamEntry `json:"entries,omitempty" mapstructure:"entries" yaml:"entries,omitempty"`
+
+	// Emulator is the emulator config embedded in the file, with the mappings derived from the entries
+	Emulator *emulatorConfig.EmulatorConfig `json:"emulator" mapstructure:"emulator" yaml:"emulator"`
+}
+
+// LoadRecording reads a recording file, an emulator config or the ndjson lines written to a recording sink,
+// decompressing them if needed. The emulator mappings are derived from the entries if the file has none.
+func LoadRecording(path string) (*Recording, error) {
+	if recordingType(path) == "ndjson" {
+		return readStreamedRecording(path)
+	}
+
+	v := viper.New()
+	if err := ReadRecording(v, path); err != nil {
+		return nil, err
+	}
+
+	return RecordingFromViper(v)
+}
+
+// RecordingFromViper reads the sections of a recording from v, the emulator mappings are derived from the
+// entries if v has none
+func RecordingFromViper(v *viper.Viper) (*Recording, error) {
+	rec := &Recording{
+		SchemaVersion: v.GetInt(RecordingKeySchemaVersion),
+		Emulator:      emulatorConfig.NewFromViper(v),
+	}
+	if rec.SchemaVersion > RecordingSchemaVersion {
+		return nil, fmt.Errorf("%w: %d (up to %d is supported)", ErrUnsupportedRecordingSchema,
+			rec.SchemaVersion, RecordingSchemaVersion)
+	}
+
+	decodeHook := viper.DecodeHook(mapstructure.ComposeDecodeHookFunc(
+		mapstructure.StringToTimeDurationHookFunc(),
+		mapstructure.StringToTimeHookFunc(time.RFC3339Nano),
+	))
+
+	if v.IsSet(RecordingKeySession) {
+		rec.Session = &emulatorConfig.RecordingMetadata{}
+		if err := v.UnmarshalKey(RecordingKeySession, rec.Session, decodeHook); err != nil {
+			return nil, fmt.Errorf("failed to read recording session: %w", err)
+		}
+	}
+
+	if v.IsSet(RecordingKeyEntries) {
+		if err := v.UnmarshalKey(RecordingKeyEntries, &rec.Entries, decodeHook); err != nil {
+			return nil, fmt.Errorf("failed to read recording entries: %w", err)
+		}
+	}
+
+	// The emulator config ignores invalid mappings, a recording with invalid mappings is an error
+	if v.IsSet(RecordingKeyMappings) {
+		if err := v.UnmarshalKey(RecordingKeyMappings, &rec.Emulator.Mappings, decodeHook); err != nil {
+			return nil, fmt.Errorf("failed to read recording mappings: %w", err)
+		}
+	}
+
+	if len(rec.Emulator.Mappings) == 0 {
+		mappings, err := MappingsFromEntries(rec.Entries)
+		if err != nil {
+			return nil, err
+		}
+
+		rec.Emulator.Mappings = mappings
+	}
+
+	return rec, nil
+}
+
+// MappingsFromEntries derives emulator mappings from raw entries, quoting the chunks like the recorder does
+func MappingsFromEntries(entries []StreamEntry) (emulatorConfig.Mappings, error) {
+	mappings := make(emulatorConfig.Mappings, 0)
+	for _, entry := range entries {
+		response := emulatorConfig.ResponseOption{Chunks: make([]emulatorConfig.ResponseChunk, 0, len(entry.Chunks))}
+		for _, chunk := range entry.Chunks {
+			delay, err := time.ParseDuration(chunk.Delay)
+			if err != nil {
+				return nil, fmt.Errorf("invalid delay of a response chunk of %q: %w", entry.Request, err)
+			}
+
+			response.Chunks = append(response.Chunks, emulatorConfig.ResponseChunk{
+				Data:  strconv.Quote(chunk.Data),
+				Delay: delay,
+			})
+		}
+
+		mappings.AddResponse(entry.Request, response)
+	}
+
+	return mappings, nil
+}
+
+// writeRecording writes the sections of the recording that aren't empty to w as yaml, only the mappings of
+// the emulator config are written
+func writeRecording(w io.Writer, rec Recording) error {
+	v := viper.New()
+	v.SetConfigType("yaml")
+	v.Set(RecordingKeySchemaVersion, RecordingSchemaVersion)
+
+	if rec.Session != nil {
+		v.Set(RecordingKeySession, rec.Session)
+	}
+
+	if len(rec.Entries) > 0 {
+		v.Set(RecordingKeyEntries, rec.Entries)
+	}
+
+	if rec.Emulator != nil {
+		v.Set(RecordingKeyMappings, rec.Emulator.Mappings)
+	}
+
+	return v.WriteConfigTo(w) //nolint:wrapcheck
+}
+
+// readStreamedRecording reads the ndjson lines written to a recording sink, the port events become the events
+// of the session with their offset from the first line
+func readStreamedRecording(path string) (*Recording, error) {
+	r, err := openRecording(path)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = r.Close() }()
+
+	rec := &Recording{Emulator: emulatorConfig.NewDefaultConfig()}
+
+	var start time.Time
+	decoder := json.NewDecoder(r)
+	for {
+		var line json.RawMessage
+		if err := decoder.Decode(&line); errors.Is(err, io.EOF) {
+			break
+		} else if err != nil {
+			return nil, fmt.Errorf("failed to read recording: %w", err)
+		}
+
+		var header struct {
+			Type string    `json:"type"`
+			Time time.Time `json:"time"`
+		}
+		if err := json.Unmarshal(line, &header); err != nil {
+			return nil, fmt.Errorf("failed to read recording: %w", err)
+		}
+
+		if start.IsZero() {
+			start = header.Time
+		}
+
+		switch header.Type {
+		case StreamTypeRequest:
+			var entry StreamEntry
+			if err := json.Unmarshal(line, &entry); err != nil {
+				return nil, fmt.Errorf("failed to read recording entry: %w", err)
+			}
+
+			rec.Entries = append(rec.Entries, entry)
+		case StreamTypeEvent:
+			var event StreamEvent
+			if err := json.Unmarshal(line, &event); err != nil {
+				return nil, fmt.Errorf("failed to read recording event: %w", err)
+			}
+
+			if rec.Session == nil {
+				rec.Session = &emulatorConfig.RecordingMetadata{}
+			}
+
+			rec.Session.Events = append(rec.Session.Events, emulatorConfig.PortEvent{
+				Offset: event.Time.Sub(start),
+				Type:   event.Event,
+				Value:  event.Value,
+			})
+		}
+	}
+
+	mappings, err := MappingsFromEntries(rec.Entries)
+	if err != nil {
+		return nil, err
+	}
+
+	rec.Emulator.Mappings = mappings
+
+	return rec, nil
+}
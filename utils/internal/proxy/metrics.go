@@ -0,0 +1,74 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package proxy
+
+import (
+	"net/http"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+const (
+	// metricsNamespace prefixes the names of all proxy metrics
+	metricsNamespace = "jumperless_proxy"
+
+	// The response latency buckets range from 1ms to about 8s
+	latencyBucketStart = 0.001
+	latencyBucketCount = 14
+
+	// Values of the port label
+	portVirtual = "virtual"
+	portReal    = "real"
+)
+
+// newRegistry registers the metrics of a proxy, reading the counters when scraped
+func newRegistry(c *counters) *prometheus.Registry {
+	registry := prometheus.NewRegistry()
+
+	counter := func(name, help, port string, value func() int64) prometheus.Collector {
+		opts := prometheus.CounterOpts{
+			Namespace: metricsNamespace,
+			Name:      name,
+			Help:      help,
+		}
+		if port != "" {
+			opts.ConstLabels = prometheus.Labels{"port": port}
+		}
+
+		return prometheus.NewCounterFunc(opts, func() float64 { return float64(value()) })
+	}
+
+	registry.MustRegister(
+		counter("read_bytes_total", "Bytes read from a port.", portVirtual, c.virtualRead.Load),
+		counter("read_bytes_total", "Bytes read from a port.", portReal, c.realRead.Load),
+		counter("written_bytes_total", "Bytes written to a port.", portVirtual, c.virtualWritten.Load),
+		counter("written_bytes_total", "Bytes written to a port.", portReal, c.realWritten.Load),
+		counter("read_errors_total", "Errors reading from a port.", portVirtual, c.virtualReadErrors.Load),
+		counter("read_errors_total", "Errors reading from a port.", portReal, c.realReadErrors.Load),
+		counter("disconnects_total", "Clients disconnecting from a port.", portVirtual, c.disconnects.Load),
+		counter("requests_recorded_total", "Requests added to the recording.", "", c.requestsSaved.Load),
+		c.responseLatency,
+	)
+
+	return registry
+}
+
+// MetricsHandler returns the handler serving the Prometheus metrics of the proxy
+func (p *Proxy) MetricsHandler() http.Handler {
+	return promhttp.HandlerFor(p.registry, promhttp.HandlerOpts{})
+}
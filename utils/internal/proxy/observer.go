@@ -0,0 +1,295 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package proxy
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net"
+	"sync"
+
+	"github.com/detiber/k8s-jumperless/internal/transport"
+	"github.com/detiber/k8s-jumperless/utils/internal/logging"
+	"github.com/detiber/k8s-jumperless/utils/internal/proxy/config"
+	"github.com/detiber/k8s-jumperless/utils/internal/server"
+	"github.com/detiber/k8s-jumperless/utils/internal/vport"
+)
+
+var ErrUnsupportedObserver = errors.New("unsupported observer (use pty or tcp)")
+
+// observerBufferSize is the number of reads buffered for each observer, the traffic is dropped once a slow
+// observer falls this far behind so it never holds up the proxy
+const observerBufferSize = 1024
+
+// observers mirror the traffic in both directions to the observers as it passes the proxy, like a tap on the
+// serial link. Observers are read-only, anything they write is discarded.
+type observers struct {
+	logger *slog.Logger
+	wg     sync.WaitGroup
+
+	mu        sync.Mutex // Guards mirrors, listeners and closed
+	mirrors   map[*mirror]struct{}
+	listeners []net.Listener
+	closed    bool
+}
+
+// openObservers opens the configured observers, TCP addresses without a host are bound to bind and
+// authenticated with creds
+func openObservers(ctx context.Context, configs []config.ObserverConfig, bind string, creds *transport.Credentials,
+	logger *slog.Logger) (*observers, error) {
+	o := &observers{
+		logger:  logger,
+		mirrors: map[*mirror]struct{}{},
+	}
+
+	for _, c := range configs {
+		var err error
+		switch c.Type {
+		case config.ObserverPTY:
+			err = o.openPTY(c.Target)
+		case config.ObserverTCP:
+			err = o.listenTCP(ctx, c.Target, bind, creds)
+		default:
+			err = fmt.Errorf("%w: %q", ErrUnsupportedObserver, c.Type)
+		}
+
+		if err != nil {
+			o.Close()
+			return nil, err
+		}
+	}
+
+	return o, nil
+}
+
+// openPTY creates a virtual serial port mirroring the traffic, e.g. for a terminal program
+func (o *observers) openPTY(name string) error {
+	port, err := vport.Open(name)
+	if err != nil {
+		return fmt.Errorf("failed to create observer port: %w", err)
+	}
+
+	o.add(newMirror(port, config.ObserverPTY+":"+port.Name(), o.logger))
+
+	// Whatever the observer writes to the port is discarded, until the port is closed
+	o.wg.Go(func() { _, _ = io.Copy(io.Discard, port) })
+
+	if port.Name() != port.Device() {
+		o.logger.Info("Created observer port", "link", port.Name(), "port", port.Device())
+	} else {
+		o.logger.Info("Created observer port", "port", port.Name())
+	}
+
+	return nil
+}
+
+// listenTCP listens for observers connecting over TCP, e.g. with netcat or openssl s_client if a certificate
+// is configured
+func (o *observers) listenTCP(ctx context.Context, addr, bind string, creds *transport.Credentials) error {
+	address, err := server.ResolveAddr(addr, bind)
+	if err != nil {
+		return err //nolint:wrapcheck
+	}
+
+	if err := creds.CheckExposure(address, false); err != nil {
+		return fmt.Errorf("unable to serve observers: %w", err)
+	}
+
+	listener, err := creds.Listen(ctx, address)
+	if err != nil {
+		return fmt.Errorf("failed to listen for observers: %w", err)
+	}
+
+	o.mu.Lock()
+	o.listeners = append(o.listeners, listener)
+	o.mu.Unlock()
+
+	o.logger.Info("Listening for observers", "listen", listener.Addr())
+
+	o.wg.Go(func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return // The listener was closed
+			}
+
+			name := config.ObserverTCP + ":" + conn.RemoteAddr().String()
+			m := newMirror(conn, name, o.logger)
+			if !o.add(m) {
+				m.Close()
+				return
+			}
+
+			o.logger.Info("Observer connected", "observer", name)
+
+			// Whatever the observer writes is discarded, until it disconnects or the observers are closed
+			o.wg.Go(func() {
+				_, _ = io.Copy(io.Discard, conn)
+				if o.remove(m) {
+					o.logger.Info("Observer disconnected", "observer", name)
+					m.Close()
+				}
+			})
+		}
+	})
+
+	return nil
+}
+
+// add starts mirroring the traffic to m, returning false if the observers are closed already
+func (o *observers) add(m *mirror) bool {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	if o.closed {
+		return false
+	}
+
+	o.mirrors[m] = struct{}{}
+
+	return true
+}
+
+// remove stops mirroring the traffic to m, returning false if it was removed already
+func (o *observers) remove(m *mirror) bool {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	if _, ok := o.mirrors[m]; !ok {
+		return false
+	}
+
+	delete(o.mirrors, m)
+
+	return true
+}
+
+// write mirrors data to all observers, data must not be modified afterwards
+func (o *observers) write(data []byte) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	for m := range o.mirrors {
+		m.enqueue(data)
+	}
+}
+
+// Close stops listening for observers and disconnects all of them
+func (o *observers) Close() {
+	o.mu.Lock()
+	listeners := o.listeners
+	mirrors := o.mirrors
+	o.listeners = nil
+	o.mirrors = map[*mirror]struct{}{}
+	o.closed = true
+	o.mu.Unlock()
+
+	for _, listener := range listeners {
+		if err := listener.Close(); err != nil {
+			o.logger.Warn("Failed to close observer listener", logging.Err(err))
+		}
+	}
+
+	for m := range mirrors {
+		m.Close()
+	}
+
+	o.wg.Wait()
+}
+
+// mirror writes the traffic to an observer from a goroutine of its own
+type mirror struct {
+	w      io.WriteCloser
+	name   string
+	logger *slog.Logger
+	writes chan []byte
+	done   chan struct{}
+
+	mu      sync.Mutex // Guards closed and dropped
+	closed  bool
+	dropped int
+}
+
+func newMirror(w io.WriteCloser, name string, logger *slog.Logger) *mirror {
+	m := &mirror{
+		w:      w,
+		name:   name,
+		logger: logger,
+		writes: make(chan []byte, observerBufferSize),
+		done:   make(chan struct{}),
+	}
+
+	go func() {
+		defer close(m.done)
+
+		failed := false
+		for data := range m.writes {
+			if failed {
+				continue
+			}
+
+			// The observer is disconnected once writing fails, the remaining traffic is discarded
+			if _, err := m.w.Write(data); err != nil {
+				m.logger.Debug("Failed to write to observer", logging.Err(err), "observer", m.name)
+				failed = true
+			}
+		}
+	}()
+
+	return m
+}
+
+// enqueue queues data, dropping it if the observer fell too far behind or is closed
+func (m *mirror) enqueue(data []byte) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if m.closed {
+		return
+	}
+
+	select {
+	case m.writes <- data:
+	default:
+		m.dropped++
+		if m.dropped == 1 {
+			m.logger.Warn("Observer is falling behind, dropping traffic", "observer", m.name)
+		}
+	}
+}
+
+// Close closes the observer, discarding the traffic that wasn't written yet
+func (m *mirror) Close() {
+	m.mu.Lock()
+	m.closed = true
+	close(m.writes)
+	m.mu.Unlock()
+
+	// Closing the observer unblocks a pending write, e.g. to a port nobody reads
+	if err := m.w.Close(); err != nil && !errors.Is(err, net.ErrClosed) {
+		m.logger.Warn("Failed to close observer", logging.Err(err), "observer", m.name)
+	}
+
+	<-m.done
+
+	if m.dropped > 0 {
+		m.logger.Warn("Dropped traffic of a slow observer", "observer", m.name, "dropped", m.dropped)
+	}
+}
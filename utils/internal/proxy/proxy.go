@@ -22,111 +22,207 @@ import (
 	"errors"
 	"fmt"
 	"io"
-	"log"
+	"log/slog"
 	"os"
+	"strconv"
+	"strings"
 	"sync"
-	"syscall"
+	"sync/atomic"
 	"time"
 
-	"github.com/creack/pty"
+	"github.com/detiber/k8s-jumperless/internal/transport"
 	"github.com/detiber/k8s-jumperless/jumperless"
+	"github.com/detiber/k8s-jumperless/utils/internal/capture"
+	"github.com/detiber/k8s-jumperless/utils/internal/client"
 	emulatorConfig "github.com/detiber/k8s-jumperless/utils/internal/emulator/config"
+	"github.com/detiber/k8s-jumperless/utils/internal/health"
+	"github.com/detiber/k8s-jumperless/utils/internal/line"
+	"github.com/detiber/k8s-jumperless/utils/internal/logging"
 	"github.com/detiber/k8s-jumperless/utils/internal/proxy/config"
+	"github.com/detiber/k8s-jumperless/utils/internal/server"
+	"github.com/detiber/k8s-jumperless/utils/internal/termios"
+	"github.com/detiber/k8s-jumperless/utils/internal/vport"
+	"github.com/prometheus/client_golang/prometheus"
 	"go.bug.st/serial"
 )
 
 var ErrNoJumperlessDevice = errors.New("no Jumperless device found")
+var ErrClientExited = errors.New("client command exited")
+
+const (
+	// termiosPollInterval is the interval the line settings of the virtual serial port are polled at
+	termiosPollInterval = 100 * time.Millisecond
+
+	// breakDuration is the duration of breaks sent to the real port
+	breakDuration = 250 * time.Millisecond
+)
 
 // Proxy represents a serial port proxy that records communication
 type Proxy struct {
 	config     *config.ProxyConfig
-	logger     *log.Logger
+	logger     *slog.Logger
 	recorder   *Recorder
-	pseudoTTY  *os.File // This is what we listen on for user input
-	virtualTTY *os.File // This is what we return to the user as the virtual port
+	counters   *counters
+	virtual    io.ReadWriteCloser     // This is what we listen on for user input, a virtual port or a listener
+	virtualTTY *vport.Port            // This is what we return to the user as the virtual port
+	listener   *rfc2217Port           // Used instead of the virtual TTY when listening on TCP
+	remote     *remotePort            // Used instead of the virtual TTY when serving the Remote gRPC service
+	creds      *transport.Credentials // Authentication of the clients of the network listeners
 	realPort   serial.Port
+	capture    *capture.Writer // Optional raw capture of the traffic in both directions
+	observers  *observers      // Optional observers the traffic in both directions is mirrored to
+
+	requestShaper  *linkShaper // Optional shaping of requests from the virtual side
+	responseShaper *linkShaper // Optional shaping of responses to the virtual side
+
+	ready       atomic.Bool // Set while both sides are connected and the data is proxied
+	virtualName string      // The name of the virtual side reported by Status, set before ready
+
+	registry *prometheus.Registry // Metrics of the proxy, exported by MetricsHandler
+}
+
+// Status is the readiness reported by the health probes
+type Status struct {
+	VirtualPort string `json:"virtualPort,omitempty"`
+	RealPort    string `json:"realPort,omitempty"`
+	Stats       Stats  `json:"stats"`
 }
 
 // New creates a new proxy instance
-func New(c *config.ProxyConfig, logger *log.Logger) (*Proxy, error) {
+func New(c *config.ProxyConfig, logger *slog.Logger) (*Proxy, error) {
 	if logger == nil {
-		logger = log.New(os.Stdout, "[proxy] ", log.LstdFlags)
+		logger = logging.Subsystem(slog.Default(), "proxy")
+	}
+
+	if err := line.Validate(c.LineSettings(), c.Ports); err != nil {
+		return nil, err //nolint:wrapcheck
+	}
+
+	creds, err := c.Security.Load()
+	if err != nil {
+		return nil, fmt.Errorf("failed to load credentials: %w", err)
+	}
+
+	counters := newCounters()
+
+	recorder, err := NewRecorder(logger, counters, c.Framing, c.Filters)
+	if err != nil {
+		return nil, err
+	}
+
+	requestShaper, err := newLinkShaper(c.Shaping, c.Shaping.Requests)
+	if err != nil {
+		return nil, err
+	}
+
+	responseShaper, err := newLinkShaper(c.Shaping, c.Shaping.Responses)
+	if err != nil {
+		return nil, err
+	}
+
+	if c.RecordStdout != "" {
+		stream, err := newEntryStream(os.Stdout, c.RecordStdout, nil)
+		if err != nil {
+			return nil, err
+		}
+
+		recorder.AddSink(config.SinkStdout, stream)
+	}
+
+	for _, sinkConfig := range c.Sinks {
+		sink, err := NewSink(sinkConfig)
+		if err != nil {
+			_ = recorder.Close()
+			return nil, err
+		}
+
+		recorder.AddSink(strings.TrimSuffix(sinkConfig.Type+":"+sinkConfig.Target, ":"), sink)
 	}
 
 	return &Proxy{
-		config:   c,
-		logger:   logger,
-		recorder: NewRecorder(logger),
+		config:         c,
+		logger:         logger,
+		recorder:       recorder,
+		counters:       counters,
+		creds:          creds,
+		requestShaper:  requestShaper,
+		responseShaper: responseShaper,
+		registry:       newRegistry(counters),
 	}, nil
 }
 
-// Run the proxy
-// The Run method will block until the context is cancelled or an error occurs
-func (p *Proxy) Run(ctx context.Context) (emulatorConfig.Mappings, error) {
-	// Create virtual serial port (pty)
-	pseudoTTY, virtualTTY, err := pty.Open()
+// RotateRecording rotates the recording into segment files named after the recording file as configured,
+// must be called before Run
+func (p *Proxy) RotateRecording(path string) error {
+	rotator, err := newRotator(p.config.Recording, path, p.logger)
 	if err != nil {
-		return nil, fmt.Errorf("failed to create pty: %w", err)
+		return err
 	}
 
-	defer func() {
-		if err := pseudoTTY.Close(); err != nil {
-			p.logger.Printf("Warning: failed to close pseudo TTY: %v", err)
-		} else {
-			p.logger.Printf("Closed pseudo TTY: %s", pseudoTTY.Name())
+	if rotator != nil {
+		// The real port is only known once the proxy runs, so the environment is captured with each segment
+		rotator.session = func() emulatorConfig.RecordingMetadata {
+			return CaptureEnvironment(p.config.RealPort, path)
 		}
+	}
 
-		if err := virtualTTY.Close(); err != nil {
-			p.logger.Printf("Warning: failed to close virtual TTY: %v", err)
-		} else {
-			p.logger.Printf("Closed virtual TTY: %s", virtualTTY.Name())
+	p.recorder.memory.rotator = rotator
+
+	return nil
+}
+
+// Run the proxy
+// The Run method will block until the context is cancelled or an error occurs.
+// If a client command is configured, Run also returns once the client exits, along with
+// the recording and any error from the client.
+func (p *Proxy) Run(ctx context.Context) (emulatorConfig.Mappings, error) {
+	// Flush the recording sinks once everything stopped
+	defer func() {
+		if err := p.recorder.Close(); err != nil {
+			p.logger.Warn("Failed to close recording sinks", logging.Err(err))
 		}
 	}()
 
-	// Ensure non-blocking reads on pseudo TTY, this allows us to implement read timeouts
-	fd := pseudoTTY.Fd()
-	if err := syscall.SetNonblock(int(fd), true); err != nil {
-		return nil, fmt.Errorf("failed to set pseudo TTY to non-blocking: %w", err)
+	// Create the virtual side of the proxy, either a virtual serial port, an RFC2217 listener or the Remote
+	// gRPC service
+	var cleanupVirtual func()
+	var err error
+	switch {
+	case p.config.ListenGRPC != "":
+		cleanupVirtual, err = p.listenGRPC(ctx)
+	case p.config.Listen != "":
+		cleanupVirtual, err = p.listen(ctx)
+	default:
+		cleanupVirtual, err = p.openVirtualPort()
+	}
+	if err != nil {
+		return nil, err
 	}
 
-	p.pseudoTTY = pseudoTTY
-	p.virtualTTY = virtualTTY
+	defer cleanupVirtual()
 
-	// Create symlink to the configured virtual port name if specified
-	if p.config.VirtualPort != "" && p.config.VirtualPort != virtualTTY.Name() {
-		// Remove existing symlink if it exists
-		if err := os.Remove(p.config.VirtualPort); err != nil && !os.IsNotExist(err) {
-			return nil, fmt.Errorf("failed to remove existing virtual port %s: %w", p.config.VirtualPort, err)
+	if p.config.Capture != "" {
+		closeCapture, err := p.openCapture()
+		if err != nil {
+			return nil, err
 		}
 
-		// Create symlink
-		if err := os.Symlink(virtualTTY.Name(), p.config.VirtualPort); err != nil {
-			return nil, fmt.Errorf("failed to create symlink %s -> %s: %w", p.config.VirtualPort, virtualTTY.Name(), err)
-		}
+		defer closeCapture()
+	}
 
-		defer func() {
-			// Remove symlink if it was created
-			if p.config.VirtualPort != "" {
-				if err := os.Remove(p.config.VirtualPort); err != nil && !os.IsNotExist(err) {
-					p.logger.Printf("Warning: failed to remove virtual port %s: %v", p.config.VirtualPort, err)
-				} else {
-					p.logger.Printf("Removed virtual port symlink: %s", p.config.VirtualPort)
-				}
-			}
-		}()
+	if len(p.config.Observers) > 0 {
+		observers, err := openObservers(ctx, p.config.Observers, p.config.Bind, p.creds, p.logger)
+		if err != nil {
+			return nil, err
+		}
 
-		p.logger.Printf("Created virtual serial port: %s -> %s", p.config.VirtualPort, virtualTTY.Name())
-	} else {
-		p.logger.Printf("Created virtual serial port: %s", virtualTTY.Name())
+		p.observers = observers
+		defer observers.Close()
 	}
 
 	// Open real serial port
-	mode := &serial.Mode{
-		BaudRate: p.config.BaudRate,
-	}
-
 	if p.config.RealPort == "" {
-		p.logger.Printf("No real port configured, attempting to detect...")
+		p.logger.Info("No real port configured, attempting to detect...")
 
 		j, err := jumperless.NewJumperless(ctx, p.config.RealPort, p.config.BaudRate)
 		if err != nil {
@@ -140,19 +236,41 @@ func (p *Proxy) Run(ctx context.Context) (emulatorConfig.Mappings, error) {
 		p.config.RealPort = j.GetPort()
 		version := j.GetVersion()
 
-		p.logger.Printf("Detected Jumperless port: %s (version: %s)", p.config.RealPort, version)
+		p.logger.Info("Detected Jumperless port", "port", p.config.RealPort, "version", version)
 	}
 
-	realPort, err := serial.Open(p.config.RealPort, mode)
+	lock, err := jumperless.LockDevice(p.config.RealPort)
+	if err != nil {
+		return nil, fmt.Errorf("failed to lock serial port %s: %w", p.config.RealPort, err)
+	}
+
+	defer func() {
+		if err := lock.Unlock(); err != nil {
+			p.logger.Warn("Failed to unlock serial port", logging.Err(err))
+		}
+	}()
+
+	// The settings are resolved once the port is known, so overrides apply to detected ports as well
+	settings := p.config.LineSettings()
+
+	lineSettings, err := settings.Line()
+	if err != nil {
+		return nil, fmt.Errorf("invalid settings of real serial port %s: %w", p.config.RealPort, err)
+	}
+
+	p.logger.Debug("Opening real serial port", "port", p.config.RealPort, "baudRate", settings.BaudRate,
+		"line", lineSettings.String())
+
+	realPort, err := jumperless.OpenSerialPort(p.config.RealPort, lineSettings.Mode(settings.BaudRate))
 	if err != nil {
 		return nil, fmt.Errorf("failed to open real serial port %s: %w", p.config.RealPort, err)
 	}
 
 	defer func() {
 		if err := realPort.Close(); err != nil {
-			p.logger.Printf("Warning: failed to close real serial port: %v", err)
+			p.logger.Warn("Failed to close real serial port", logging.Err(err))
 		} else {
-			p.logger.Printf("Closed real serial port: %s", p.config.RealPort)
+			p.logger.Debug("Closed real serial port", "port", p.config.RealPort)
 		}
 	}()
 
@@ -164,10 +282,14 @@ func (p *Proxy) Run(ctx context.Context) (emulatorConfig.Mappings, error) {
 	}
 
 	p.realPort = realPort
-	p.logger.Printf("Connected to real serial port: %s", p.config.RealPort)
+	p.logger.Info("Connected to real serial port", "port", p.config.RealPort)
 
 	wg := sync.WaitGroup{}
 
+	// Stop the proxy when either the parent context is done or the client command exits
+	runCtx, cancelRun := context.WithCancelCause(ctx)
+	defer cancelRun(nil)
+
 	// Start recorder and proxy goroutines
 	recorderctx, cancelRecorder := context.WithCancelCause(ctx)
 	wg.Go(func() { p.recorder.Run(recorderctx) })
@@ -178,12 +300,47 @@ func (p *Proxy) Run(ctx context.Context) (emulatorConfig.Mappings, error) {
 	r2vctx, cancelR2V := context.WithCancelCause(ctx)
 	wg.Go(func() { p.proxyRealToVirtual(r2vctx) })
 
-	p.logger.Printf("Proxy started. Virtual serial port: %s", p.GetVirtualPortName())
-	p.logger.Printf("Press Ctrl+C to stop")
+	// Clients of a virtual serial port change its line settings with termios, which has to be polled
+	if p.virtualTTY != nil {
+		wg.Go(func() {
+			termios.WatchBaudRate(v2rctx, p.virtualTTY.TTY, termiosPollInterval,
+				func(baudRate int) { p.handleEvent(emulatorConfig.EventBaudRate, strconv.Itoa(baudRate)) })
+		})
+	}
 
-	// Wait for context cancellation
-	<-ctx.Done()
-	p.logger.Printf("Context done, shutting down proxy")
+	// Shaped requests are delivered to the real port and shaped responses to the virtual side, so the
+	// shapers stop along with the proxy goroutine reading from the other side
+	if p.requestShaper != nil {
+		link := p.config.Shaping.Link(p.config.Shaping.Requests)
+		p.logger.Info("Shaping requests on the virtual side link",
+			"rate", link.Rate, "latency", link.Latency, "jitter", link.Jitter)
+		wg.Go(func() { p.requestShaper.run(r2vctx, p.forwardRequest) })
+	}
+	if p.responseShaper != nil {
+		link := p.config.Shaping.Link(p.config.Shaping.Responses)
+		p.logger.Info("Shaping responses on the virtual side link",
+			"rate", link.Rate, "latency", link.Latency, "jitter", link.Jitter)
+		wg.Go(func() { p.responseShaper.run(v2rctx, p.forwardResponse) })
+	}
+
+	p.virtualName = p.GetVirtualPortName()
+	p.ready.Store(true)
+	p.logger.Info("Proxy started", "port", p.virtualName)
+
+	var clientErr error
+	if p.config.Exec != "" {
+		wg.Go(func() {
+			clientErr = client.Run(runCtx, p.logger, p.config.Exec, p.GetVirtualPortName())
+			cancelRun(ErrClientExited)
+		})
+	} else {
+		p.logger.Info("Press Ctrl+C to stop")
+	}
+
+	// Wait for context cancellation or the client to exit
+	<-runCtx.Done()
+	p.ready.Store(false)
+	p.logger.Info("Context done, shutting down proxy", "cause", context.Cause(runCtx))
 
 	// Cancel all goroutines
 	cancelV2R(nil)
@@ -191,11 +348,11 @@ func (p *Proxy) Run(ctx context.Context) (emulatorConfig.Mappings, error) {
 	// Give some time for an active read/write to finish
 	time.Sleep(100 * time.Millisecond)
 
-	// Force close the pseudo TTY to unblock any active reads
-	if err := p.pseudoTTY.Close(); err != nil {
-		p.logger.Printf("Warning: failed to close pseudo TTY: %v", err)
+	// Force close the virtual side to unblock any active reads
+	if err := p.virtual.Close(); err != nil {
+		p.logger.Warn("Failed to close virtual port", logging.Err(err))
 	} else {
-		p.logger.Printf("Closed pseudo TTY: %s", p.pseudoTTY.Name())
+		p.logger.Debug("Closed virtual port", "port", p.GetVirtualPortName())
 	}
 
 	cancelR2V(nil)
@@ -205,9 +362,9 @@ func (p *Proxy) Run(ctx context.Context) (emulatorConfig.Mappings, error) {
 
 	// Force close the real port to unblock any active reads
 	if err := p.realPort.Close(); err != nil {
-		p.logger.Printf("Warning: failed to close real serial port: %v", err)
+		p.logger.Warn("Failed to close real serial port", logging.Err(err))
 	} else {
-		p.logger.Printf("Closed real serial port: %s", p.config.RealPort)
+		p.logger.Debug("Closed real serial port", "port", p.config.RealPort)
 	}
 
 	cancelRecorder(nil)
@@ -215,79 +372,204 @@ func (p *Proxy) Run(ctx context.Context) (emulatorConfig.Mappings, error) {
 	// Wait for all goroutines to finish
 	wg.Wait()
 
+	p.checkIntegrity()
+
 	recording := p.recorder.GetRecording()
 	if len(recording) > 0 {
-		p.logger.Printf("Recorded %d request/response pairs", len(recording))
+		p.logger.Info("Recorded request/response pairs", "pairs", len(recording))
 	} else {
-		p.logger.Printf("No requests/responses recorded")
+		p.logger.Info("No requests/responses recorded")
+	}
+
+	if clientErr != nil {
+		return recording, fmt.Errorf("proxy client error: %w", clientErr)
 	}
 
 	return recording, nil
 }
 
+// openVirtualPort creates the virtual serial port clients connect to, a pty on Unix or a named pipe on Windows,
+// returning a function to clean it up
+func (p *Proxy) openVirtualPort() (func(), error) {
+	virtualTTY, err := vport.Open(p.config.VirtualPort)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create virtual serial port: %w", err)
+	}
+
+	p.virtual = virtualTTY
+	p.virtualTTY = virtualTTY
+
+	cleanup := func() {
+		// The served side is closed already when the proxy stops
+		if err := virtualTTY.Close(); err != nil && !errors.Is(err, os.ErrClosed) {
+			p.logger.Warn("Failed to close virtual serial port", "port", virtualTTY.Name(), logging.Err(err))
+		} else {
+			p.logger.Debug("Closed virtual serial port", "port", virtualTTY.Name())
+		}
+	}
+
+	if virtualTTY.Name() != virtualTTY.Device() {
+		p.logger.Info("Created virtual serial port", "link", virtualTTY.Name(), "port", virtualTTY.Device())
+	} else {
+		p.logger.Info("Created virtual serial port", "port", virtualTTY.Name())
+	}
+
+	return cleanup, nil
+}
+
+// listen starts the RFC2217 listener clients connect to, returning a function to clean it up
+func (p *Proxy) listen(ctx context.Context) (func(), error) {
+	if p.config.VirtualPort != "" {
+		p.logger.Warn("Ignoring virtual port, listening instead", "link", p.config.VirtualPort, "listen", p.config.Listen)
+	}
+
+	address, err := server.ResolveAddr(p.config.Listen, p.config.Bind)
+	if err != nil {
+		return nil, err //nolint:wrapcheck
+	}
+
+	if err := p.creds.CheckExposure(address, false); err != nil {
+		return nil, err //nolint:wrapcheck
+	}
+
+	listener, err := listenRFC2217(ctx, address, p.config.BaudRate, p.creds.ServerTLS(), p.logger, p.handleEvent)
+	if err != nil {
+		return nil, err
+	}
+
+	p.virtual = listener
+	p.listener = listener
+	p.logger.Info("Listening for RFC2217 clients", "listen", listener.Addr())
+
+	cleanup := func() {
+		if err := listener.Close(); err != nil {
+			p.logger.Warn("Failed to close RFC2217 listener", logging.Err(err))
+		} else {
+			p.logger.Debug("Closed RFC2217 listener", "listen", listener.Addr())
+		}
+	}
+
+	return cleanup, nil
+}
+
+// listenGRPC starts serving the Remote gRPC service clients open the real port with, returning a function to
+// clean it up
+func (p *Proxy) listenGRPC(ctx context.Context) (func(), error) {
+	if p.config.VirtualPort != "" || p.config.Listen != "" {
+		p.logger.Warn("Ignoring virtual port and RFC2217 listener, serving the Remote gRPC service instead",
+			"link", p.config.VirtualPort, "listen", p.config.Listen, "listenGRPC", p.config.ListenGRPC)
+	}
+
+	address, err := server.ResolveAddr(p.config.ListenGRPC, p.config.Bind)
+	if err != nil {
+		return nil, err //nolint:wrapcheck
+	}
+
+	if err := p.creds.CheckExposure(address, true); err != nil {
+		return nil, err //nolint:wrapcheck
+	}
+
+	remote, err := listenRemote(ctx, address, p)
+	if err != nil {
+		return nil, err
+	}
+
+	p.virtual = remote
+	p.remote = remote
+	p.logger.Info("Serving the Remote gRPC service", "listen", remote.Addr())
+
+	cleanup := func() {
+		if err := remote.Close(); err != nil {
+			p.logger.Warn("Failed to close Remote gRPC service", logging.Err(err))
+		} else {
+			p.logger.Debug("Closed Remote gRPC service", "listen", remote.Addr())
+		}
+	}
+
+	return cleanup, nil
+}
+
 // proxyVirtualToReal forwards data from virtual port to real port (requests)
 func (p *Proxy) proxyVirtualToReal(ctx context.Context) {
-	p.logger.Printf("Starting to proxy data from virtual port %s to real port %s", p.virtualTTY.Name(), p.config.RealPort)
+	p.logger.Debug("Starting to proxy data from virtual port to real port",
+		"virtualPort", p.GetVirtualPortName(), "realPort", p.config.RealPort)
 	buffer := make([]byte, p.config.BufferSize)
 
 	defer func() {
-		p.logger.Printf("Stopped proxying data from virtual port to real port")
+		p.logger.Debug("Stopped proxying data from virtual port to real port")
 	}()
 
 	for {
 		select {
 		case <-ctx.Done():
-			p.logger.Printf("Context done, stopping proxyVirtualToReal")
+			p.logger.Debug("Context done, stopping proxyVirtualToReal")
 			return
 		default:
-			n, err := p.pseudoTTY.Read(buffer)
+			n, err := p.virtual.Read(buffer)
 			if err != nil {
 				if os.IsTimeout(err) {
 					continue // Timeout is expected
 				}
 				if errors.Is(err, io.EOF) {
-					p.logger.Printf("Virtual port client disconnected")
+					p.logger.Info("Virtual port client disconnected")
+					p.counters.disconnects.Add(1)
 					continue
 				}
-				p.logger.Printf("Error reading from virtual port: %v", err)
+				p.logger.Error("Error reading from virtual port", logging.Err(err))
+				p.counters.virtualReadErrors.Add(1)
 				continue
 			}
 
 			if n > 0 {
-				data := buffer[:n]
-
-				// // Record request
-				p.recorder.RecordRequest(bytes.Clone(data))
-
-				// Forward to real port
-				if _, err := p.realPort.Write(bytes.Clone(data)); err != nil {
-					p.logger.Printf("Error writing to real port: %v", err)
-				}
+				data := bytes.Clone(buffer[:n])
+				p.counters.virtualRead.Add(int64(n))
 
-				p.logger.Printf("Request: %q", data)
-
-				if err := p.realPort.Drain(); err != nil {
-					p.logger.Printf("Error draining real port: %v", err)
+				if p.requestShaper != nil {
+					p.requestShaper.send(ctx, data)
+				} else {
+					p.forwardRequest(data)
 				}
 			}
 		}
 	}
 }
 
+// forwardRequest records a request and forwards it to the real port. Requests are recorded as they
+// reach the real port, so the recorded response timing isn't affected by link shaping.
+func (p *Proxy) forwardRequest(data []byte) {
+	p.recorder.RecordRequest(bytes.Clone(data))
+	p.captureFrame(capture.DirectionRequest, data)
+	p.observe(data)
+
+	// Forward to real port
+	written, err := p.realPort.Write(data)
+	p.counters.realWritten.Add(int64(written))
+	if err != nil {
+		p.logger.Error("Error writing to real port", logging.Err(err))
+	}
+
+	p.logger.Debug("Request", "data", data)
+
+	if err := p.realPort.Drain(); err != nil {
+		p.logger.Error("Error draining real port", logging.Err(err))
+	}
+}
+
 // proxyRealToVirtual forwards data from real port to virtual port (responses)
 func (p *Proxy) proxyRealToVirtual(ctx context.Context) {
-	p.logger.Printf("Starting to proxy data from real port %s to virtual port %s", p.config.RealPort, p.virtualTTY.Name())
+	p.logger.Debug("Starting to proxy data from real port to virtual port",
+		"realPort", p.config.RealPort, "virtualPort", p.GetVirtualPortName())
 
 	buffer := make([]byte, p.config.BufferSize)
 
 	defer func() {
-		p.logger.Printf("Stopped proxying data from real port to virtual port")
+		p.logger.Debug("Stopped proxying data from real port to virtual port")
 	}()
 
 	for {
 		select {
 		case <-ctx.Done():
-			p.logger.Printf("Context done, stopping proxyRealToVirtual")
+			p.logger.Debug("Context done, stopping proxyRealToVirtual")
 			return
 		default:
 			n, err := p.realPort.Read(buffer)
@@ -295,33 +577,162 @@ func (p *Proxy) proxyRealToVirtual(ctx context.Context) {
 				if os.IsTimeout(err) {
 					continue // Timeout is expected
 				}
-				p.logger.Printf("Error reading from real port: %v", err)
+				p.logger.Error("Error reading from real port", logging.Err(err))
+				p.counters.realReadErrors.Add(1)
 				continue
 			}
 
 			if n > 0 {
-				data := buffer[:n]
+				data := bytes.Clone(buffer[:n])
+				p.counters.realRead.Add(int64(n))
 
 				p.recorder.RecordResponse(bytes.Clone(data))
+				p.captureFrame(capture.DirectionResponse, data)
+				p.observe(data)
 
-				// Forward to virtual port
-				if _, err := p.pseudoTTY.Write(bytes.Clone(data)); err != nil {
-					p.logger.Printf("Error writing to virtual port: %v", err)
+				if p.responseShaper != nil {
+					p.responseShaper.send(ctx, data)
+				} else {
+					p.forwardResponse(data)
 				}
-
-				p.logger.Printf("Response: %q", data)
 			}
 		}
 	}
 }
 
-// GetVirtualPortName returns the virtual port name
+// handleEvent records a port event requested by the client and applies DTR, RTS and break changes to the
+// real port. Baud rate changes are only recorded, the real port is a USB CDC device where the line settings
+// don't affect the connection and some rates such as 1200 baud reboot the device into its bootloader.
+func (p *Proxy) handleEvent(eventType, value string) {
+	p.logger.Info("Port event", "event", eventType, "value", value)
+	p.recorder.RecordEvent(eventType, value)
+
+	var err error
+	switch eventType {
+	case emulatorConfig.EventDTR:
+		err = p.realPort.SetDTR(value == emulatorConfig.EventOn)
+	case emulatorConfig.EventRTS:
+		err = p.realPort.SetRTS(value == emulatorConfig.EventOn)
+	case emulatorConfig.EventBreak:
+		// The serial port only supports breaks of a fixed duration, a break is sent when it starts
+		if value != emulatorConfig.EventOff {
+			err = p.realPort.Break(breakDuration)
+		}
+	}
+
+	if err != nil {
+		p.logger.Error("Error applying port event to real port", "event", eventType, logging.Err(err))
+	}
+}
+
+// Events returns the port events requested by the client so far
+func (p *Proxy) Events() []emulatorConfig.PortEvent {
+	return p.recorder.GetEvents()
+}
+
+// forwardResponse forwards a response to the virtual port
+func (p *Proxy) forwardResponse(data []byte) {
+	written, err := p.virtual.Write(data)
+	p.counters.virtualWritten.Add(int64(written))
+	if err != nil {
+		p.logger.Error("Error writing to virtual port", logging.Err(err))
+	}
+
+	p.logger.Debug("Response", "data", data)
+}
+
+// openCapture creates the raw capture file, returning a function to close it
+func (p *Proxy) openCapture() (func(), error) {
+	file, err := os.Create(p.config.Capture)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create capture file %s: %w", p.config.Capture, err)
+	}
+
+	writer, err := capture.NewWriter(file)
+	if err != nil {
+		_ = file.Close()
+		return nil, fmt.Errorf("failed to write capture file %s: %w", p.config.Capture, err)
+	}
+
+	p.capture = writer
+	p.logger.Info("Capturing raw traffic", "file", p.config.Capture)
+
+	return func() {
+		if err := file.Close(); err != nil {
+			p.logger.Warn("Failed to close capture file", logging.Err(err))
+		} else {
+			p.logger.Debug("Closed capture file", "file", p.config.Capture)
+		}
+	}, nil
+}
+
+// captureFrame writes data to the raw capture, if enabled
+func (p *Proxy) captureFrame(direction capture.Direction, data []byte) {
+	if p.capture == nil {
+		return
+	}
+
+	if err := p.capture.WriteFrame(capture.Frame{Time: time.Now(), Direction: direction, Data: data}); err != nil {
+		p.logger.Error("Error capturing traffic", "direction", direction, logging.Err(err))
+	}
+}
+
+// observe mirrors data to the observers, if any
+func (p *Proxy) observe(data []byte) {
+	if p.observers == nil {
+		return
+	}
+
+	p.observers.write(data)
+}
+
+// Stats returns a snapshot of the bytes read, forwarded and recorded in each direction
+func (p *Proxy) Stats() Stats {
+	return p.counters.snapshot()
+}
+
+// Status reports the proxy ready once the virtual side is created and the real port connected, along with the
+// bytes proxied so far
+func (p *Proxy) Status() health.Status {
+	if !p.ready.Load() {
+		return health.Status{Ready: false, Details: Status{Stats: p.Stats()}}
+	}
+
+	return health.Status{
+		Ready: true,
+		Details: Status{
+			VirtualPort: p.virtualName,
+			RealPort:    p.config.RealPort,
+			Stats:       p.Stats(),
+		},
+	}
+}
+
+// checkIntegrity reports any mismatch between the bytes read, forwarded and recorded
+func (p *Proxy) checkIntegrity() {
+	stats := p.Stats()
+
+	p.logger.Info("Requests",
+		"read", stats.VirtualRead, "forwarded", stats.RealWritten, "recorded", stats.RequestsRecorded)
+	p.logger.Info("Responses",
+		"read", stats.RealRead, "forwarded", stats.VirtualWritten, "recorded", stats.ResponsesRecorded)
+
+	for _, discrepancy := range stats.Discrepancies() {
+		p.logger.Warn("Integrity check failed", "discrepancy", discrepancy)
+	}
+}
+
+// GetVirtualPortName returns the virtual port name, or the listen address when serving RFC2217 or the Remote
+// gRPC service
 func (p *Proxy) GetVirtualPortName() string {
-	if p.config.VirtualPort != "" {
-		return p.config.VirtualPort
+	if p.remote != nil {
+		return p.remote.Addr()
+	}
+	if p.listener != nil {
+		return p.listener.Addr()
 	}
 	if p.virtualTTY != nil {
 		return p.virtualTTY.Name()
 	}
-	return ""
+	return p.config.VirtualPort
 }
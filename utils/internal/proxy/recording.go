@@ -17,90 +17,242 @@ limitations under the License.
 package proxy
 
 import (
+	"bytes"
 	"context"
 	"errors"
-	"log"
+	"fmt"
+	"log/slog"
+	"slices"
 	"strconv"
+	"sync"
 	"time"
 
 	emulatorConfig "github.com/detiber/k8s-jumperless/utils/internal/emulator/config"
+	"github.com/detiber/k8s-jumperless/utils/internal/logging"
+	"github.com/detiber/k8s-jumperless/utils/internal/proxy/config"
 )
 
 var (
 	ErrResponseWithoutRequest      = errors.New("received response without preceding request")
 	ErrUnsupportedOutputFormat     = errors.New("unsupported output format (use yaml, json, or log)")
 	ErrUnsupportedConfigFileFormat = errors.New("unsupported config file format (use .yaml, .yml, or .json)")
+	ErrUnsupportedFraming          = errors.New("unsupported request framing (use read, newline, prompt, or idle)")
 )
 
 // Recorder handles recording of serial port interactions
 type Recorder struct {
-	logger   *log.Logger
-	requests emulatorConfig.Mappings
+	logger   *slog.Logger
+	counters *counters
+	framing  config.FramingConfig
+	filter   *recordingFilter
+	memory   *MemorySink // The recording saved to the config file when the proxy stops
+	sinks    []Sink      // Sinks the saved request/response pairs are written to, including memory
 	reqChan  chan []byte
 	resChan  chan []byte
+
+	start      time.Time // Port event offsets are relative to the creation of the recorder
+	eventsLock sync.Mutex
+	events     []emulatorConfig.PortEvent
 }
 
-// NewRecorder creates a new Recorder instance
-func NewRecorder(logger *log.Logger) *Recorder {
+// NewRecorder creates a new Recorder instance, framing and filtering requests as configured
+func NewRecorder(
+	logger *slog.Logger,
+	c *counters,
+	framing config.FramingConfig,
+	filters config.FilterConfig,
+) (*Recorder, error) {
+	if c == nil {
+		c = newCounters()
+	}
+
+	switch framing.Mode {
+	case config.FramingRead, config.FramingNewline:
+	case config.FramingPrompt:
+		if framing.Prompt == "" {
+			return nil, fmt.Errorf("%w: prompt framing requires a prompt", ErrUnsupportedFraming)
+		}
+	case config.FramingIdle:
+		if framing.Idle <= 0 {
+			return nil, fmt.Errorf("%w: idle framing requires an idle time", ErrUnsupportedFraming)
+		}
+	default:
+		return nil, fmt.Errorf("%w: %q", ErrUnsupportedFraming, framing.Mode)
+	}
+
+	filter, err := newRecordingFilter(filters)
+	if err != nil {
+		return nil, err
+	}
+
+	memory := NewMemorySink(logger)
+
 	return &Recorder{
 		logger:   logger,
-		requests: make(emulatorConfig.Mappings, 0),
+		counters: c,
+		framing:  framing,
+		filter:   filter,
+		memory:   memory,
+		sinks:    []Sink{memory},
 		reqChan:  make(chan []byte),
 		resChan:  make(chan []byte),
-	}
+		start:    time.Now(),
+	}, nil
 }
 
 func (r *Recorder) RecordRequest(req []byte) {
-	r.logger.Printf("Recording request: %q", req)
+	r.logger.Debug("Recording request", "request", req)
 	r.reqChan <- req
 }
 
 func (r *Recorder) RecordResponse(res []byte) {
-	r.logger.Printf("Recording response chunk: %q", res)
+	r.logger.Debug("Recording response chunk", "response", res)
 	r.resChan <- res
 }
 
 func (r *Recorder) GetRecording() emulatorConfig.Mappings {
-	return r.requests
+	return r.memory.Mappings()
+}
+
+// AddSink streams the saved request/response pairs and port events to a sink as well, from a goroutine of its
+// own so a slow sink doesn't hold up the recording. Must be called before Run, the sink is closed by Close.
+func (r *Recorder) AddSink(name string, sink Sink) {
+	r.sinks = append(r.sinks, newAsyncSink(sink, name, r.logger))
+}
+
+// Close closes the sinks once the recorder stopped, waiting for the entries queued for them to be written
+func (r *Recorder) Close() error {
+	errs := make([]error, 0, len(r.sinks))
+	for _, sink := range r.sinks {
+		errs = append(errs, sink.Close())
+	}
+
+	return errors.Join(errs...)
+}
+
+// RecordEvent records a port event requested by the client, such as a baud rate change or a DTR toggle
+func (r *Recorder) RecordEvent(eventType, value string) {
+	now := time.Now()
+	event := emulatorConfig.PortEvent{Offset: now.Sub(r.start), Type: eventType, Value: value}
+
+	r.logger.Debug("Recording port event", "event", eventType, "value", value)
+
+	r.eventsLock.Lock()
+	r.events = append(r.events, event)
+	r.eventsLock.Unlock()
+
+	for _, sink := range r.sinks {
+		if err := sink.WriteEvent(now, event); err != nil {
+			r.logger.Warn("Failed to write recorded port event", logging.Err(err), "event", eventType)
+		}
+	}
+}
+
+// GetEvents returns the port events recorded so far
+func (r *Recorder) GetEvents() []emulatorConfig.PortEvent {
+	r.eventsLock.Lock()
+	defer r.eventsLock.Unlock()
+
+	return slices.Clone(r.events)
+}
+
+// save writes the request sent at start and its response to the sinks, unless the request is filtered out.
+// Redaction rules are applied to both before they are written.
+func (r *Recorder) save(start time.Time, request []byte, response emulatorConfig.ResponseOption) {
+	if !r.filter.records(string(request)) {
+		r.logger.Debug("Filtered out request", "request", request)
+		return
+	}
+
+	redactedRequest := r.filter.redactText(string(request))
+	redactedResponse := r.filter.redactResponse(response)
+
+	r.counters.requestsSaved.Add(1)
+
+	for _, sink := range r.sinks {
+		if err := sink.WriteEntry(start, redactedRequest, redactedResponse); err != nil {
+			r.logger.Warn("Failed to write recorded request", logging.Err(err), "request", redactedRequest)
+		}
+	}
 }
 
 // Run the Recorder
 // The Recorder will run until the context is cancelled
 func (r *Recorder) Run(ctx context.Context) {
-	var currentRequest string
+	var currentRequest []byte
 	var currentResponse *emulatorConfig.ResponseOption
 	var currentRequestTime time.Time
+	var currentRequestStart time.Time
+
+	// requestComplete is set once the framing decides the current request is complete, the next request
+	// bytes then start a new request. Until then request bytes are appended to the current request.
+	var requestComplete bool
+
+	// idle fires once the client has been idle for the framing idle time, in idle framing only
+	var idle <-chan time.Time
+
+	// rotation checks whether the recording is due for time based rotation while no requests are recorded
+	var rotation <-chan time.Time
+	if rotator := r.memory.rotator; rotator != nil && rotator.config.MaxAge > 0 {
+		ticker := time.NewTicker(max(rotator.config.MaxAge/rotationChecks, time.Second))
+		defer ticker.Stop()
+		rotation = ticker.C
+	}
 
 	defer (func() {
 		// Ensure that we finalize the last recording if needed
-		if currentRequest != "" && currentResponse != nil {
-			r.logger.Printf("Finalizing recording for request: %s", currentRequest)
-			r.requests.AddResponse(currentRequest, *currentResponse)
+		if currentRequest != nil && currentResponse != nil {
+			r.logger.Debug("Finalizing recording for request", "request", currentRequest)
+			r.save(currentRequestStart, currentRequest, *currentResponse)
 		}
 	})()
 
 	for {
 		select {
 		case <-ctx.Done():
-			r.logger.Println("Recorder stopping")
+			r.logger.Debug("Recorder stopping")
 			return
+		case <-idle:
+			idle = nil
+			requestComplete = true
+		case <-rotation:
+			r.memory.checkRotation()
 		case req := <-r.reqChan:
-			r.logger.Printf("Received request to record: %s", req)
+			r.logger.Debug("Received request to record", "request", req)
 
-			if currentRequest != "" && currentResponse != nil {
-				r.logger.Printf("Saving recording for previous request: %s", currentRequest)
-				r.requests.AddResponse(currentRequest, *currentResponse)
+			r.counters.requestsRecorded.Add(int64(len(req)))
+
+			if currentRequest == nil || requestComplete {
+				if currentRequest != nil && currentResponse != nil {
+					r.logger.Debug("Saving recording for previous request", "request", currentRequest)
+					r.save(currentRequestStart, currentRequest, *currentResponse)
+				}
+
+				currentRequest = nil
+				currentResponse = new(emulatorConfig.ResponseOption)
+				currentRequestStart = time.Now()
+				requestComplete = false
 			}
 
 			currentRequestTime = time.Now()
-			currentRequest = string(req)
-			currentResponse = new(emulatorConfig.ResponseOption)
+			currentRequest = append(currentRequest, req...)
+
+			switch r.framing.Mode {
+			case config.FramingRead:
+				requestComplete = true
+			case config.FramingNewline:
+				requestComplete = bytes.ContainsAny(req, "\r\n")
+			case config.FramingIdle:
+				idle = time.After(r.framing.Idle)
+			}
 		case res := <-r.resChan:
 			if currentResponse == nil {
-				r.logger.Printf("Warning: %v: %s", ErrResponseWithoutRequest, res)
+				r.logger.Warn("Dropping response", logging.Err(ErrResponseWithoutRequest), "response", res)
 				continue
 			}
 
+			r.counters.responsesRecorded.Add(int64(len(res)))
+
 			chunk := emulatorConfig.ResponseChunk{
 				Data: strconv.Quote(string(res)),
 			}
@@ -108,10 +260,17 @@ func (r *Recorder) Run(ctx context.Context) {
 			// Set the delay based on the time since the request was recorded
 			chunk.Delay = time.Since(currentRequestTime)
 			chunk.JitterMax = chunk.Delay / 10 // 10% of the delay
+			if len(currentResponse.Chunks) == 0 {
+				r.counters.responseLatency.Observe(chunk.Delay.Seconds())
+			}
 			currentResponse.Chunks = append(currentResponse.Chunks, chunk)
 
 			// Update the request time for the next chunk
 			currentRequestTime = time.Now()
+
+			if r.framing.Mode == config.FramingPrompt && bytes.Contains(res, []byte(r.framing.Prompt)) {
+				requestComplete = true
+			}
 		}
 	}
 }
@@ -0,0 +1,464 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package proxy
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"strconv"
+	"sync"
+	"time"
+
+	"go.bug.st/serial"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/detiber/k8s-jumperless/jumperless"
+	"github.com/detiber/k8s-jumperless/jumperless/remotepb"
+	emulatorConfig "github.com/detiber/k8s-jumperless/utils/internal/emulator/config"
+	"github.com/detiber/k8s-jumperless/utils/internal/logging"
+)
+
+// RemoteIdentityKind is the kind of server the proxy reports with the Remote service
+const RemoteIdentityKind = "proxy"
+
+// remoteReadTimeout bounds each read of the data sent by a client so that cancellation is noticed
+const remoteReadTimeout = 100 * time.Millisecond
+
+var ErrRemoteSessionClosed = errors.New("remote session closed")
+
+// remotePort exposes the virtual side of the proxy over the Remote gRPC service of the jumperless package,
+// so the operator can drive the device through the proxy. Like the RFC2217 listener it serves one client at
+// a time: a client opening the port takes it over from the previous one, reads wait for a client to send
+// data, and writes without a connected client are discarded. Line changes of the client are applied as port
+// events of the proxy.
+type remotePort struct {
+	proxy    *Proxy
+	listener net.Listener
+	server   *grpc.Server
+
+	requests chan []byte   // Data sent by clients, nil when a client disconnects
+	done     chan struct{} // Closed once the port is closed
+	pending  []byte        // The part of a request that didn't fit into the last read
+
+	mu       sync.Mutex
+	session  *remoteSession
+	closed   bool
+	closeErr error
+}
+
+var _ jumperless.RemoteBackend = &remotePort{}
+
+func listenRemote(ctx context.Context, address string, proxy *Proxy) (*remotePort, error) {
+	var lc net.ListenConfig
+	listener, err := lc.Listen(ctx, "tcp", address)
+	if err != nil {
+		return nil, fmt.Errorf("failed to listen on %s: %w", address, err)
+	}
+
+	p := &remotePort{
+		proxy:    proxy,
+		listener: listener,
+		server:   grpc.NewServer(proxy.creds.ServerOptions()...),
+		requests: make(chan []byte),
+		done:     make(chan struct{}),
+	}
+
+	jumperless.NewRemoteServer(p).Register(p.server)
+
+	go func() {
+		if err := p.server.Serve(listener); err != nil && !errors.Is(err, grpc.ErrServerStopped) {
+			p.proxy.logger.Error("Remote gRPC service stopped", "listen", p.Addr(), logging.Err(err))
+		}
+	}()
+
+	return p, nil
+}
+
+// Addr returns the address clients connect to
+func (p *remotePort) Addr() string {
+	return p.listener.Addr().String()
+}
+
+// Identity implements jumperless.RemoteBackend, describing the host of the proxy and the real port once the
+// proxy is connected to it
+func (p *remotePort) Identity(_ context.Context) (*remotepb.Identity, error) {
+	hostname, _ := os.Hostname()
+	identity := &remotepb.Identity{Name: hostname, Kind: RemoteIdentityKind, Version: utilsVersion()}
+
+	if p.proxy.ready.Load() {
+		device := &remotepb.Device{Port: p.proxy.config.RealPort}
+		if usb := usbDescriptors(p.proxy.config.RealPort); usb != nil {
+			device.SerialNumber = usb.SerialNumber
+			device.Vid = usb.VID
+			device.Pid = usb.PID
+		}
+
+		identity.Devices = append(identity.Devices, device)
+	}
+
+	return identity, nil
+}
+
+// OpenPort implements jumperless.RemoteBackend, opening the real port for a client, which takes the port over
+// from the previous client. The real port is opened if name is empty.
+func (p *remotePort) OpenPort(_ context.Context, name string, mode *serial.Mode) (string, serial.Port, error) {
+	if !p.proxy.ready.Load() {
+		return "", nil, status.Error(codes.Unavailable, "the proxy is not connected to the device yet")
+	}
+
+	realPort := p.proxy.config.RealPort
+	if name != "" && name != realPort {
+		return "", nil, status.Errorf(codes.NotFound, "the proxy only serves port %s", realPort)
+	}
+
+	session := &remoteSession{
+		port:        p,
+		readTimeout: serial.NoTimeout,
+		notify:      make(chan struct{}, 1),
+	}
+
+	p.mu.Lock()
+	if p.closed {
+		p.mu.Unlock()
+		return "", nil, status.Error(codes.Unavailable, "the proxy is stopping")
+	}
+
+	previous := p.session
+	p.session = session
+	p.mu.Unlock()
+
+	if previous != nil {
+		p.proxy.logger.Info("Remote client took over the port from the previous client")
+		previous.close()
+		p.disconnected()
+	}
+
+	p.proxy.logger.Info("Remote client connected", "port", realPort)
+
+	if mode != nil && mode.BaudRate > 0 {
+		p.proxy.handleEvent(emulatorConfig.EventBaudRate, strconv.Itoa(mode.BaudRate))
+	}
+
+	return realPort, session, nil
+}
+
+// Read reads data sent by the connected client, a timeout error is returned when no data is available and
+// io.EOF once when a client disconnects
+func (p *remotePort) Read(b []byte) (int, error) {
+	if len(p.pending) == 0 {
+		select {
+		case data := <-p.requests:
+			if data == nil {
+				return 0, io.EOF
+			}
+
+			p.pending = data
+		case <-p.done:
+			return 0, net.ErrClosed
+		case <-time.After(remoteReadTimeout):
+			return 0, os.ErrDeadlineExceeded
+		}
+	}
+
+	n := copy(b, p.pending)
+	p.pending = p.pending[n:]
+
+	return n, nil
+}
+
+// Write sends data to the connected client
+func (p *remotePort) Write(b []byte) (int, error) {
+	p.mu.Lock()
+	session := p.session
+	p.mu.Unlock()
+
+	if session != nil {
+		session.deliver(b)
+	}
+
+	return len(b), nil
+}
+
+// Close stops the service, closing the session of the connected client
+func (p *remotePort) Close() error {
+	p.mu.Lock()
+	if p.closed {
+		p.mu.Unlock()
+		return p.closeErr
+	}
+
+	p.closed = true
+	session := p.session
+	p.session = nil
+	close(p.done)
+	p.mu.Unlock()
+
+	if session != nil {
+		session.close()
+	}
+
+	p.server.Stop()
+
+	// Stop closes the listener once it served, closing it again only fails if it was never served
+	if err := p.listener.Close(); err != nil && !errors.Is(err, net.ErrClosed) {
+		p.closeErr = fmt.Errorf("failed to close listener: %w", err)
+	}
+
+	return p.closeErr
+}
+
+// send passes data sent by a client to the proxy, unless the port is closed
+func (p *remotePort) send(data []byte) error {
+	select {
+	case p.requests <- data:
+		return nil
+	case <-p.done:
+		return ErrRemoteSessionClosed
+	}
+}
+
+// disconnected reports the disconnect of a client to the proxy
+func (p *remotePort) disconnected() {
+	_ = p.send(nil)
+}
+
+// release ends the session of a client if it is still connected
+func (p *remotePort) release(session *remoteSession) {
+	p.mu.Lock()
+	current := p.session == session
+	if current {
+		p.session = nil
+	}
+	p.mu.Unlock()
+
+	if current {
+		p.proxy.logger.Info("Remote client disconnected")
+		p.disconnected()
+	}
+}
+
+// remoteSession is the port opened by a client of the Remote service. Writes are forwarded to the real port
+// by the proxy, and the responses of the real port are buffered until the client reads them.
+type remoteSession struct {
+	port   *remotePort
+	notify chan struct{} // Signalled when data was delivered or the session was closed
+
+	mu          sync.Mutex
+	buffer      []byte
+	readTimeout time.Duration
+	closed      bool
+}
+
+var _ serial.Port = &remoteSession{}
+
+// deliver buffers data for the client
+func (s *remoteSession) deliver(data []byte) {
+	s.mu.Lock()
+	s.buffer = append(s.buffer, data...)
+	s.mu.Unlock()
+
+	s.signal()
+}
+
+// signal wakes up a pending read
+func (s *remoteSession) signal() {
+	select {
+	case s.notify <- struct{}{}:
+	default:
+	}
+}
+
+// close closes the session without releasing the port
+func (s *remoteSession) close() {
+	s.mu.Lock()
+	s.closed = true
+	s.buffer = nil
+	s.mu.Unlock()
+
+	s.signal()
+}
+
+// check returns an error once the session was closed
+func (s *remoteSession) check() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.closed {
+		return ErrRemoteSessionClosed
+	}
+
+	return nil
+}
+
+// SetMode implements serial.Port, recording the baud rate like the proxy does for other clients
+func (s *remoteSession) SetMode(mode *serial.Mode) error {
+	if err := s.check(); err != nil {
+		return err
+	}
+
+	s.port.proxy.handleEvent(emulatorConfig.EventBaudRate, strconv.Itoa(mode.BaudRate))
+
+	return nil
+}
+
+// Read implements serial.Port, returning the buffered responses of the real port
+func (s *remoteSession) Read(b []byte) (int, error) {
+	s.mu.Lock()
+	timeout := s.readTimeout
+	s.mu.Unlock()
+
+	var expired <-chan time.Time
+	if timeout != serial.NoTimeout {
+		timer := time.NewTimer(timeout)
+		defer timer.Stop()
+
+		expired = timer.C
+	}
+
+	for {
+		s.mu.Lock()
+		if s.closed {
+			s.mu.Unlock()
+			return 0, ErrRemoteSessionClosed
+		}
+
+		if len(s.buffer) > 0 {
+			n := copy(b, s.buffer)
+			s.buffer = s.buffer[n:]
+			s.mu.Unlock()
+
+			return n, nil
+		}
+		s.mu.Unlock()
+
+		select {
+		case <-s.notify:
+		case <-expired:
+			return 0, nil
+		}
+	}
+}
+
+// Write implements serial.Port, passing the data to the proxy
+func (s *remoteSession) Write(b []byte) (int, error) {
+	if err := s.check(); err != nil {
+		return 0, err
+	}
+
+	if err := s.port.send(bytes.Clone(b)); err != nil {
+		return 0, err
+	}
+
+	return len(b), nil
+}
+
+// Drain implements serial.Port, the proxy drains the real port after every request
+func (s *remoteSession) Drain() error {
+	return s.check()
+}
+
+// ResetInputBuffer implements serial.Port, discarding the buffered responses
+func (s *remoteSession) ResetInputBuffer() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.closed {
+		return ErrRemoteSessionClosed
+	}
+
+	s.buffer = nil
+
+	return nil
+}
+
+// ResetOutputBuffer implements serial.Port, requests are passed to the proxy as they are written
+func (s *remoteSession) ResetOutputBuffer() error {
+	return s.check()
+}
+
+// SetDTR implements serial.Port
+func (s *remoteSession) SetDTR(dtr bool) error {
+	return s.event(emulatorConfig.EventDTR, dtr)
+}
+
+// SetRTS implements serial.Port
+func (s *remoteSession) SetRTS(rts bool) error {
+	return s.event(emulatorConfig.EventRTS, rts)
+}
+
+// GetModemStatusBits implements serial.Port, reporting the modem status of the real port
+func (s *remoteSession) GetModemStatusBits() (*serial.ModemStatusBits, error) {
+	if err := s.check(); err != nil {
+		return nil, err
+	}
+
+	return s.port.proxy.realPort.GetModemStatusBits() //nolint:wrapcheck
+}
+
+// SetReadTimeout implements serial.Port
+func (s *remoteSession) SetReadTimeout(t time.Duration) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.closed {
+		return ErrRemoteSessionClosed
+	}
+
+	s.readTimeout = t
+
+	return nil
+}
+
+// Close implements serial.Port, disconnecting the client from the proxy
+func (s *remoteSession) Close() error {
+	if err := s.check(); err != nil {
+		return err
+	}
+
+	s.close()
+	s.port.release(s)
+
+	return nil
+}
+
+// Break implements serial.Port, the proxy sends breaks of a fixed duration to the real port
+func (s *remoteSession) Break(_ time.Duration) error {
+	return s.event(emulatorConfig.EventBreak, true)
+}
+
+// event applies a port event of the client to the real port
+func (s *remoteSession) event(eventType string, on bool) error {
+	if err := s.check(); err != nil {
+		return err
+	}
+
+	value := emulatorConfig.EventOff
+	if on {
+		value = emulatorConfig.EventOn
+	}
+
+	s.port.proxy.handleEvent(eventType, value)
+
+	return nil
+}
@@ -0,0 +1,215 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package proxy
+
+import (
+	"context"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net"
+	"sync"
+	"time"
+
+	"github.com/detiber/k8s-jumperless/utils/internal/logging"
+	"github.com/detiber/k8s-jumperless/utils/internal/rfc2217"
+)
+
+const (
+	// rfc2217AcceptTimeout bounds waiting for a client so that cancellation is noticed
+	rfc2217AcceptTimeout = 100 * time.Millisecond
+
+	// rfc2217ReadTimeout bounds each read from a client so that cancellation is noticed
+	rfc2217ReadTimeout = 100 * time.Millisecond
+
+	// rfc2217HandshakeTimeout bounds the TLS handshake of a client, so a stalled client doesn't block the port
+	rfc2217HandshakeTimeout = 5 * time.Second
+)
+
+// rfc2217Port exposes the virtual side of the proxy over TCP using the telnet based RFC 2217
+// serial port protocol. Like a pty it outlives its clients, serving one client at a time:
+// reads wait for a client to connect, and writes without a connected client are discarded.
+// Port events requested by the client are reported to onEvent. Clients connect with TLS if tlsConfig is set.
+type rfc2217Port struct {
+	listener  *net.TCPListener
+	tlsConfig *tls.Config
+	logger    *slog.Logger
+	baudRate  int
+	onEvent   rfc2217.EventHandler
+
+	connLock sync.Mutex
+	conn     net.Conn
+	session  *rfc2217.Session // The telnet session of the connected client
+}
+
+func listenRFC2217(
+	ctx context.Context,
+	address string,
+	baudRate int,
+	tlsConfig *tls.Config,
+	logger *slog.Logger,
+	onEvent rfc2217.EventHandler,
+) (*rfc2217Port, error) {
+	var lc net.ListenConfig
+	listener, err := lc.Listen(ctx, "tcp", address)
+	if err != nil {
+		return nil, fmt.Errorf("failed to listen on %s: %w", address, err)
+	}
+
+	return &rfc2217Port{
+		listener:  listener.(*net.TCPListener), //nolint:forcetypeassert
+		tlsConfig: tlsConfig,
+		logger:    logger,
+		baudRate:  baudRate,
+		onEvent:   onEvent,
+	}, nil
+}
+
+// Addr returns the address clients connect to
+func (p *rfc2217Port) Addr() string {
+	return p.listener.Addr().String()
+}
+
+// Read reads data sent by the client with telnet commands removed, a timeout error
+// is returned when no data is available
+func (p *rfc2217Port) Read(b []byte) (int, error) {
+	conn, session, err := p.accept()
+	if err != nil {
+		return 0, err
+	}
+
+	if err := conn.SetReadDeadline(time.Now().Add(rfc2217ReadTimeout)); err != nil {
+		return 0, fmt.Errorf("failed to set read deadline: %w", err)
+	}
+
+	raw := make([]byte, len(b))
+	n, err := conn.Read(raw)
+	if err != nil {
+		if errors.Is(err, io.EOF) || errors.Is(err, net.ErrClosed) {
+			p.disconnect(conn)
+			return 0, io.EOF
+		}
+
+		// Timeouts are returned as is so they can be detected by the caller
+		return 0, err //nolint:wrapcheck
+	}
+
+	return session.Decode(raw[:n], b), nil
+}
+
+// Write sends data to the client, escaping any bytes that would be interpreted as telnet commands
+func (p *rfc2217Port) Write(b []byte) (int, error) {
+	p.connLock.Lock()
+	session := p.session
+	p.connLock.Unlock()
+
+	if session == nil {
+		return len(b), nil
+	}
+
+	return session.Write(b) //nolint:wrapcheck
+}
+
+// Close closes the listener and any connected client
+func (p *rfc2217Port) Close() error {
+	p.connLock.Lock()
+	conn := p.conn
+	p.conn = nil
+	p.session = nil
+	p.connLock.Unlock()
+
+	var errs []error
+	if conn != nil {
+		if err := conn.Close(); err != nil && !errors.Is(err, net.ErrClosed) {
+			errs = append(errs, fmt.Errorf("failed to close client connection: %w", err))
+		}
+	}
+
+	if err := p.listener.Close(); err != nil && !errors.Is(err, net.ErrClosed) {
+		errs = append(errs, fmt.Errorf("failed to close listener: %w", err))
+	}
+
+	return errors.Join(errs...)
+}
+
+// accept returns the connected client and its session, waiting briefly for a new client if none is connected
+func (p *rfc2217Port) accept() (net.Conn, *rfc2217.Session, error) {
+	p.connLock.Lock()
+	conn, session := p.conn, p.session
+	p.connLock.Unlock()
+
+	if conn != nil {
+		return conn, session, nil
+	}
+
+	if err := p.listener.SetDeadline(time.Now().Add(rfc2217AcceptTimeout)); err != nil {
+		return nil, nil, fmt.Errorf("failed to set accept deadline: %w", err)
+	}
+
+	conn, err := p.listener.Accept()
+	if err != nil {
+		// Timeouts are returned as is so they can be detected by the caller
+		return nil, nil, err //nolint:wrapcheck
+	}
+
+	if p.tlsConfig != nil {
+		tlsConn := tls.Server(conn, p.tlsConfig)
+
+		ctx, cancel := context.WithTimeout(context.Background(), rfc2217HandshakeTimeout)
+		err := tlsConn.HandshakeContext(ctx)
+		cancel()
+
+		if err != nil {
+			_ = conn.Close()
+			return nil, nil, fmt.Errorf("TLS handshake with RFC2217 client %s failed: %w", conn.RemoteAddr(), err)
+		}
+
+		conn = tlsConn
+	}
+
+	p.logger.Info("RFC2217 client connected", "client", conn.RemoteAddr())
+
+	session = rfc2217.NewSession(conn, p.baudRate, p.logger, p.onEvent)
+
+	p.connLock.Lock()
+	p.conn, p.session = conn, session
+	p.connLock.Unlock()
+
+	if err := session.Offer(); err != nil {
+		p.disconnect(conn)
+		return nil, nil, err //nolint:wrapcheck
+	}
+
+	return conn, session, nil
+}
+
+func (p *rfc2217Port) disconnect(conn net.Conn) {
+	p.connLock.Lock()
+	if p.conn == conn {
+		p.conn = nil
+		p.session = nil
+	}
+	p.connLock.Unlock()
+
+	if err := conn.Close(); err != nil && !errors.Is(err, net.ErrClosed) {
+		p.logger.Warn("Failed to close RFC2217 client connection", logging.Err(err))
+	}
+
+	p.logger.Info("RFC2217 client disconnected", "client", conn.RemoteAddr())
+}
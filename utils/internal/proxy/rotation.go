@@ -0,0 +1,277 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package proxy
+
+import (
+	"bufio"
+	"compress/gzip"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/klauspost/compress/zstd"
+	"github.com/spf13/viper"
+
+	emulatorConfig "github.com/detiber/k8s-jumperless/utils/internal/emulator/config"
+	"github.com/detiber/k8s-jumperless/utils/internal/proxy/config"
+)
+
+var ErrUnsupportedCompression = errors.New("unsupported recording compression (use none, gzip, or zstd)")
+
+const (
+	// segmentTimeFormat is the time the first request of a segment was recorded at in its file name, which
+	// sorts the segments from oldest to newest
+	segmentTimeFormat = "20060102T150405.000Z"
+
+	// chunkOverhead approximates the bytes a response chunk adds to a recording besides its data, its delay,
+	// jitter and yaml keys
+	chunkOverhead = 64
+
+	// rotationChecks is how often the recording is checked for time based rotation during its maximum age
+	rotationChecks = 10
+)
+
+// compressionExtensions are the file extensions of the compressed segments
+var compressionExtensions = map[string]string{ //nolint:gochecknoglobals
+	config.CompressionNone: "",
+	config.CompressionGzip: ".gz",
+	config.CompressionZstd: ".zst",
+}
+
+// rotator writes the recording to segment files once it grows too large or too old
+type rotator struct {
+	config config.RecordingConfig
+	path   string // the recording file, segments are written next to it
+	logger *slog.Logger
+
+	// session describes the environment of the recording in each segment, optional
+	session func() emulatorConfig.RecordingMetadata
+}
+
+// newRotator creates a rotator writing segments named after the recording file, or returns nil if the config
+// doesn't rotate the recording
+func newRotator(c config.RecordingConfig, path string, logger *slog.Logger) (*rotator, error) {
+	if _, ok := compressionExtensions[c.Compression]; !ok {
+		return nil, fmt.Errorf("%w: %q", ErrUnsupportedCompression, c.Compression)
+	}
+
+	if c.MaxSize <= 0 && c.MaxAge <= 0 {
+		return nil, nil //nolint:nilnil
+	}
+
+	return &rotator{config: c, path: path, logger: logger}, nil
+}
+
+// due returns true if a recording of size bytes started at start has to be rotated
+func (r *rotator) due(size int64, start time.Time) bool {
+	return (r.config.MaxSize > 0 && size >= r.config.MaxSize) ||
+		(r.config.MaxAge > 0 && time.Since(start) >= r.config.MaxAge)
+}
+
+// write writes the recording since start to a new segment and removes the oldest segments beyond the
+// configured number of files
+func (r *rotator) write(rec Recording, start time.Time) error {
+	base := strings.TrimSuffix(r.path, filepath.Ext(r.path))
+	name := fmt.Sprintf("%s-%s.yml%s", base, start.UTC().Format(segmentTimeFormat),
+		compressionExtensions[r.config.Compression])
+
+	if r.session != nil {
+		session := r.session()
+		if rec.Session != nil {
+			session.Events = rec.Session.Events
+		}
+		rec.Session = &session
+	}
+
+	// Write to a temporary file first, so an interrupted write never leaves a truncated segment behind
+	file, err := os.CreateTemp(filepath.Dir(name), filepath.Base(name)+".*.tmp")
+	if err != nil {
+		return fmt.Errorf("failed to create recording segment: %w", err)
+	}
+	defer func() { _ = os.Remove(file.Name()) }()
+
+	w, err := compressWriter(file, r.config.Compression)
+	if err != nil {
+		_ = file.Close()
+		return err
+	}
+
+	if err := writeRecording(w, rec); err != nil {
+		_ = file.Close()
+		return fmt.Errorf("failed to write recording segment: %w", err)
+	}
+
+	if err := w.Close(); err != nil {
+		_ = file.Close()
+		return fmt.Errorf("failed to write recording segment: %w", err)
+	}
+
+	if err := file.Close(); err != nil {
+		return fmt.Errorf("failed to write recording segment: %w", err)
+	}
+
+	if err := os.Rename(file.Name(), name); err != nil {
+		return fmt.Errorf("failed to write recording segment: %w", err)
+	}
+
+	r.logger.Info("Rotated recording", "file", name, "pairs", len(rec.Emulator.Mappings))
+
+	return r.prune(base)
+}
+
+// prune removes the oldest segments beyond the configured number of files
+func (r *rotator) prune(base string) error {
+	if r.config.MaxFiles <= 0 {
+		return nil
+	}
+
+	segments, err := filepath.Glob(base + "-*.yml*")
+	if err != nil {
+		return fmt.Errorf("failed to list recording segments: %w", err)
+	}
+
+	segments = slices.DeleteFunc(segments, func(name string) bool { return strings.HasSuffix(name, ".tmp") })
+	slices.Sort(segments)
+
+	for len(segments) > r.config.MaxFiles {
+		if err := os.Remove(segments[0]); err != nil && !errors.Is(err, os.ErrNotExist) {
+			return fmt.Errorf("failed to remove recording segment: %w", err)
+		}
+
+		r.logger.Info("Removed old recording segment", "file", segments[0])
+		segments = segments[1:]
+	}
+
+	return nil
+}
+
+// mappingSize approximates the bytes a recorded request and its response add to the recording
+func mappingSize(request string, response emulatorConfig.ResponseOption) int64 {
+	size := int64(len(request))
+	for _, chunk := range response.Chunks {
+		size += int64(len(chunk.Data)) + chunkOverhead
+	}
+
+	return size
+}
+
+// nopWriteCloser adds a Close method doing nothing to an uncompressed segment writer
+type nopWriteCloser struct {
+	io.Writer
+}
+
+func (nopWriteCloser) Close() error { return nil }
+
+// compressWriter wraps w in a writer compressing with the given compression
+func compressWriter(w io.Writer, compression string) (io.WriteCloser, error) {
+	switch compression {
+	case config.CompressionGzip:
+		return gzip.NewWriter(w), nil
+	case config.CompressionZstd:
+		zw, err := zstd.NewWriter(w)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create zstd writer: %w", err)
+		}
+
+		return zw, nil
+	default:
+		return nopWriteCloser{Writer: w}, nil
+	}
+}
+
+// recordingReader closes both the decompressing reader and the file it reads
+type recordingReader struct {
+	io.Reader
+	closers []func() error
+}
+
+func (r *recordingReader) Close() error {
+	errs := make([]error, 0, len(r.closers))
+	for _, closer := range r.closers {
+		errs = append(errs, closer())
+	}
+
+	return errors.Join(errs...)
+}
+
+// openRecording opens a recording, decompressing segments compressed with gzip or zstd by their extension
+func openRecording(path string) (io.ReadCloser, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open recording: %w", err)
+	}
+
+	switch filepath.Ext(path) {
+	case compressionExtensions[config.CompressionGzip]:
+		gr, err := gzip.NewReader(bufio.NewReader(file))
+		if err != nil {
+			_ = file.Close()
+			return nil, fmt.Errorf("failed to decompress recording: %w", err)
+		}
+
+		return &recordingReader{Reader: gr, closers: []func() error{gr.Close, file.Close}}, nil
+	case compressionExtensions[config.CompressionZstd]:
+		zr, err := zstd.NewReader(file)
+		if err != nil {
+			_ = file.Close()
+			return nil, fmt.Errorf("failed to decompress recording: %w", err)
+		}
+
+		return &recordingReader{Reader: zr, closers: []func() error{
+			func() error { zr.Close(); return nil },
+			file.Close,
+		}}, nil
+	default:
+		return file, nil
+	}
+}
+
+// ReadRecording reads a recording into v, decompressing segments compressed with gzip or zstd
+func ReadRecording(v *viper.Viper, path string) error {
+	r, err := openRecording(path)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = r.Close() }()
+
+	v.SetConfigType(recordingType(path))
+
+	if err := v.ReadConfig(r); err != nil {
+		return fmt.Errorf("failed to read recording: %w", err)
+	}
+
+	return nil
+}
+
+// recordingType returns the type of a recording file, the extension before the compression extension, e.g.
+// yml for a .yml.gz segment
+func recordingType(path string) string {
+	name := path
+	for _, ext := range compressionExtensions {
+		if ext != "" {
+			name = strings.TrimSuffix(name, ext)
+		}
+	}
+
+	return strings.TrimPrefix(filepath.Ext(name), ".")
+}
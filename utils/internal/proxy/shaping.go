@@ -0,0 +1,125 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package proxy
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/rand"
+	"time"
+
+	"github.com/detiber/k8s-jumperless/utils/internal/proxy/config"
+)
+
+var ErrInvalidShaping = errors.New("invalid link shaping")
+
+// shapedQueueSize is the number of chunks in flight on a shaped link before reads from it block
+const shapedQueueSize = 1024
+
+// shapedChunk is data in flight on a shaped link, delivered once the link would have carried it
+type shapedChunk struct {
+	data      []byte
+	deliverAt time.Time
+}
+
+// linkShaper delays the data sent in one direction of a link as if it was carried by a slower link.
+// Data is serialized at the configured rate and delivered after the latency and a random jitter, in order.
+type linkShaper struct {
+	rate        int
+	delay       time.Duration
+	jitter      time.Duration
+	rand        *rand.Rand
+	freeAt      time.Time // When the link has finished transmitting the data sent so far
+	deliveredAt time.Time // When the data sent so far is delivered, later data is never delivered before it
+	queue       chan shapedChunk
+}
+
+// newLinkShaper returns a shaper for one direction of the link given its overrides, or nil if the link
+// isn't shaped
+func newLinkShaper(c config.ShapingConfig, overrides config.LinkShapingConfig) (*linkShaper, error) {
+	if c.Rate < 0 || c.RTT < 0 || c.Jitter < 0 {
+		return nil, fmt.Errorf("%w: rate %d, RTT %s and jitter %s must not be negative",
+			ErrInvalidShaping, c.Rate, c.RTT, c.Jitter)
+	}
+
+	if overrides.Rate < 0 || overrides.Latency < 0 || overrides.Jitter < 0 {
+		return nil, fmt.Errorf("%w: rate %d, latency %s and jitter %s of a direction must not be negative",
+			ErrInvalidShaping, overrides.Rate, overrides.Latency, overrides.Jitter)
+	}
+
+	link := c.Link(overrides)
+	if link.Rate == 0 && link.Latency == 0 && link.Jitter == 0 {
+		return nil, nil //nolint:nilnil
+	}
+
+	return &linkShaper{
+		rate:   link.Rate,
+		delay:  link.Latency,
+		jitter: link.Jitter,
+		rand:   rand.New(rand.NewSource(time.Now().UnixNano())), //nolint:gosec
+		queue:  make(chan shapedChunk, shapedQueueSize),
+	}, nil
+}
+
+// send queues data on the link, it must only be called from a single goroutine
+func (s *linkShaper) send(ctx context.Context, data []byte) {
+	now := time.Now()
+	if s.freeAt.Before(now) {
+		s.freeAt = now
+	}
+
+	if s.rate > 0 {
+		s.freeAt = s.freeAt.Add(time.Duration(len(data)) * time.Second / time.Duration(s.rate))
+	}
+
+	deliverAt := s.freeAt.Add(s.delay)
+	if s.jitter > 0 {
+		deliverAt = deliverAt.Add(time.Duration(s.rand.Int63n(int64(s.jitter))))
+	}
+
+	// Jitter must not reorder the data, so data never overtakes the data sent before it
+	if deliverAt.Before(s.deliveredAt) {
+		deliverAt = s.deliveredAt
+	}
+	s.deliveredAt = deliverAt
+
+	select {
+	case <-ctx.Done():
+	case s.queue <- shapedChunk{data: data, deliverAt: deliverAt}:
+	}
+}
+
+// run delivers the queued data when it is due until ctx is done, data still in flight is dropped
+func (s *linkShaper) run(ctx context.Context, deliver func([]byte)) {
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case chunk := <-s.queue:
+			timer := time.NewTimer(time.Until(chunk.deliverAt))
+
+			select {
+			case <-ctx.Done():
+				timer.Stop()
+				return
+			case <-timer.C:
+				deliver(chunk.data)
+			}
+		}
+	}
+}
@@ -0,0 +1,263 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package proxy
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"net"
+	"os"
+	"sync"
+	"time"
+
+	emulatorConfig "github.com/detiber/k8s-jumperless/utils/internal/emulator/config"
+	"github.com/detiber/k8s-jumperless/utils/internal/logging"
+	"github.com/detiber/k8s-jumperless/utils/internal/proxy/config"
+)
+
+var ErrUnsupportedSink = errors.New("unsupported recording sink (use stdout, file, or tcp)")
+
+// sinkBufferSize is the number of entries buffered for each streaming sink, entries are dropped once a slow
+// sink falls this far behind so it never holds up the recording
+const sinkBufferSize = 1024
+
+// Sink receives the request/response pairs and port events recorded by the proxy as they are saved, after
+// filtering and redaction. Port events are written while requests are recorded, so sinks must be safe for
+// concurrent use.
+type Sink interface {
+	// WriteEntry writes a request sent at start and its complete response
+	WriteEntry(start time.Time, request string, response emulatorConfig.ResponseOption) error
+
+	// WriteEvent writes a port event requested by the client at the given time
+	WriteEvent(at time.Time, event emulatorConfig.PortEvent) error
+
+	// Close flushes and releases the sink once the recording is complete
+	Close() error
+}
+
+// MemorySink keeps the recorded request/response pairs as emulator mappings, which are saved to the config
+// file when the proxy stops. It optionally rotates them into segment files as they grow, along with the raw
+// entries and port events since the last rotation.
+type MemorySink struct {
+	mu       sync.Mutex
+	mappings emulatorConfig.Mappings
+	rotator  *rotator // Optional rotation of the mappings into segment files
+	logger   *slog.Logger
+
+	entries []StreamEntry              // Raw entries since the last rotation, only kept when rotating
+	events  []emulatorConfig.PortEvent // Port events since the last rotation, only kept when rotating
+
+	segmentStart time.Time // When the first request of the mappings since the last rotation was sent
+	segmentSize  int64     // Approximate size of the mappings since the last rotation
+}
+
+// NewMemorySink creates an empty MemorySink
+func NewMemorySink(logger *slog.Logger) *MemorySink {
+	return &MemorySink{
+		mappings: make(emulatorConfig.Mappings, 0),
+		logger:   logger,
+	}
+}
+
+// WriteEntry implements Sink
+func (s *MemorySink) WriteEntry(start time.Time, request string, response emulatorConfig.ResponseOption) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if len(s.mappings) == 0 {
+		s.segmentStart = start
+	}
+
+	s.mappings.AddResponse(request, response)
+	s.segmentSize += mappingSize(request, response)
+	if s.rotator != nil {
+		s.entries = append(s.entries, newStreamEntry(start, request, response))
+	}
+
+	s.rotate()
+
+	return nil
+}
+
+// WriteEvent implements Sink, port events are only kept for the segments, the recorder keeps all of them
+func (s *MemorySink) WriteEvent(_ time.Time, event emulatorConfig.PortEvent) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.rotator != nil {
+		s.events = append(s.events, event)
+	}
+
+	return nil
+}
+
+// Close implements Sink
+func (s *MemorySink) Close() error {
+	return nil
+}
+
+// Mappings returns the mappings recorded since the last rotation
+func (s *MemorySink) Mappings() emulatorConfig.Mappings {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.mappings
+}
+
+// checkRotation rotates the mappings if they are due for time based rotation
+func (s *MemorySink) checkRotation() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.rotate()
+}
+
+// rotate writes the mappings to a segment file and starts over once they are due for rotation. A failed
+// rotation keeps the mappings, it is tried again with the next request. Must be called with mu held.
+func (s *MemorySink) rotate() {
+	if s.rotator == nil || len(s.mappings) == 0 || !s.rotator.due(s.segmentSize, s.segmentStart) {
+		return
+	}
+
+	segment := Recording{Entries: s.entries, Emulator: &emulatorConfig.EmulatorConfig{Mappings: s.mappings}}
+	if len(s.events) > 0 {
+		segment.Session = &emulatorConfig.RecordingMetadata{Events: s.events}
+	}
+
+	if err := s.rotator.write(segment, s.segmentStart); err != nil {
+		s.logger.Warn("Failed to rotate recording", logging.Err(err))
+		return
+	}
+
+	s.mappings = make(emulatorConfig.Mappings, 0)
+	s.entries = nil
+	s.events = nil
+	s.segmentSize = 0
+}
+
+// NewSink creates a streaming sink as configured
+func NewSink(c config.SinkConfig) (Sink, error) {
+	format := c.Format
+	if format == "" {
+		format = config.RecordStdoutNDJSON
+	}
+
+	switch c.Type {
+	case config.SinkStdout:
+		return newEntryStream(os.Stdout, format, nil)
+	case config.SinkFile:
+		file, err := os.OpenFile(c.Target, os.O_WRONLY|os.O_CREATE|os.O_APPEND, 0o600)
+		if err != nil {
+			return nil, fmt.Errorf("failed to open recording sink %s: %w", c.Target, err)
+		}
+
+		return newEntryStream(file, format, file)
+	case config.SinkTCP:
+		conn, err := net.Dial("tcp", c.Target)
+		if err != nil {
+			return nil, fmt.Errorf("failed to connect recording sink %s: %w", c.Target, err)
+		}
+
+		return newEntryStream(conn, format, conn)
+	default:
+		return nil, fmt.Errorf("%w: %q", ErrUnsupportedSink, c.Type)
+	}
+}
+
+// asyncSink writes to a sink from a goroutine of its own, so a slow sink such as a remote dashboard doesn't
+// hold up the recording or the other sinks
+type asyncSink struct {
+	sink   Sink
+	name   string
+	logger *slog.Logger
+	writes chan func(Sink) error
+	done   chan struct{}
+
+	mu      sync.Mutex // Guards closed and dropped
+	closed  bool
+	dropped int
+}
+
+func newAsyncSink(sink Sink, name string, logger *slog.Logger) *asyncSink {
+	s := &asyncSink{
+		sink:   sink,
+		name:   name,
+		logger: logger,
+		writes: make(chan func(Sink) error, sinkBufferSize),
+		done:   make(chan struct{}),
+	}
+
+	go func() {
+		defer close(s.done)
+
+		for write := range s.writes {
+			if err := write(s.sink); err != nil {
+				s.logger.Warn("Failed to write to recording sink", logging.Err(err), "sink", s.name)
+			}
+		}
+	}()
+
+	return s
+}
+
+// enqueue queues a write, dropping it if the sink fell too far behind or is closed
+func (s *asyncSink) enqueue(write func(Sink) error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.closed {
+		return
+	}
+
+	select {
+	case s.writes <- write:
+	default:
+		s.dropped++
+		if s.dropped == 1 {
+			s.logger.Warn("Recording sink is falling behind, dropping entries", "sink", s.name)
+		}
+	}
+}
+
+// WriteEntry implements Sink
+func (s *asyncSink) WriteEntry(start time.Time, request string, response emulatorConfig.ResponseOption) error {
+	s.enqueue(func(sink Sink) error { return sink.WriteEntry(start, request, response) })
+	return nil
+}
+
+// WriteEvent implements Sink
+func (s *asyncSink) WriteEvent(at time.Time, event emulatorConfig.PortEvent) error {
+	s.enqueue(func(sink Sink) error { return sink.WriteEvent(at, event) })
+	return nil
+}
+
+// Close implements Sink, writing the queued entries before closing the sink
+func (s *asyncSink) Close() error {
+	s.mu.Lock()
+	s.closed = true
+	close(s.writes)
+	s.mu.Unlock()
+
+	<-s.done
+
+	if s.dropped > 0 {
+		s.logger.Warn("Dropped entries of a slow recording sink", "sink", s.name, "dropped", s.dropped)
+	}
+
+	return s.sink.Close() //nolint:wrapcheck
+}
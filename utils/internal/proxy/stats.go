@@ -0,0 +1,101 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package proxy
+
+import (
+	"fmt"
+	"sync/atomic"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// Stats is a snapshot of the byte counters for each direction of the proxy
+type Stats struct {
+	// Requests (virtual port -> real port)
+	VirtualRead      int64 `json:"virtualRead"`
+	RealWritten      int64 `json:"realWritten"`
+	RequestsRecorded int64 `json:"requestsRecorded"`
+
+	// Responses (real port -> virtual port)
+	RealRead          int64 `json:"realRead"`
+	VirtualWritten    int64 `json:"virtualWritten"`
+	ResponsesRecorded int64 `json:"responsesRecorded"`
+}
+
+// Discrepancies returns a description of each mismatch between the bytes read, forwarded and recorded.
+// An empty result means every byte read was both forwarded and recorded.
+func (s Stats) Discrepancies() []string {
+	discrepancies := []string{}
+
+	if s.RealWritten != s.VirtualRead {
+		discrepancies = append(discrepancies, fmt.Sprintf(
+			"requests: read %d bytes from virtual port but forwarded %d bytes to real port", s.VirtualRead, s.RealWritten))
+	}
+	if s.RequestsRecorded != s.VirtualRead {
+		discrepancies = append(discrepancies, fmt.Sprintf(
+			"requests: read %d bytes from virtual port but recorded %d bytes", s.VirtualRead, s.RequestsRecorded))
+	}
+	if s.VirtualWritten != s.RealRead {
+		discrepancies = append(discrepancies, fmt.Sprintf(
+			"responses: read %d bytes from real port but forwarded %d bytes to virtual port", s.RealRead, s.VirtualWritten))
+	}
+	if s.ResponsesRecorded != s.RealRead {
+		discrepancies = append(discrepancies, fmt.Sprintf(
+			"responses: read %d bytes from real port but recorded %d bytes", s.RealRead, s.ResponsesRecorded))
+	}
+
+	return discrepancies
+}
+
+// counters tracks the bytes read, written and recorded by the proxy, along with the events only
+// exported as metrics
+type counters struct {
+	virtualRead       atomic.Int64
+	realWritten       atomic.Int64
+	requestsRecorded  atomic.Int64
+	realRead          atomic.Int64
+	virtualWritten    atomic.Int64
+	responsesRecorded atomic.Int64
+
+	requestsSaved     atomic.Int64 // requests added to the recording, after framing and filtering
+	disconnects       atomic.Int64 // virtual side clients disconnecting
+	virtualReadErrors atomic.Int64
+	realReadErrors    atomic.Int64
+	responseLatency   prometheus.Histogram // time from a request to the first chunk of its response
+}
+
+func newCounters() *counters {
+	return &counters{
+		responseLatency: prometheus.NewHistogram(prometheus.HistogramOpts{
+			Namespace: metricsNamespace,
+			Name:      "response_latency_seconds",
+			Help:      "Time from a request to the first chunk of its response from the device.",
+			Buckets:   prometheus.ExponentialBuckets(latencyBucketStart, 2, latencyBucketCount),
+		}),
+	}
+}
+
+func (c *counters) snapshot() Stats {
+	return Stats{
+		VirtualRead:       c.virtualRead.Load(),
+		RealWritten:       c.realWritten.Load(),
+		RequestsRecorded:  c.requestsRecorded.Load(),
+		RealRead:          c.realRead.Load(),
+		VirtualWritten:    c.virtualWritten.Load(),
+		ResponsesRecorded: c.responsesRecorded.Load(),
+	}
+}
@@ -0,0 +1,164 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package proxy
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	emulatorConfig "github.com/detiber/k8s-jumperless/utils/internal/emulator/config"
+	"github.com/detiber/k8s-jumperless/utils/internal/proxy/config"
+)
+
+var ErrUnsupportedRecordStdout = errors.New("unsupported recording stream format (use ndjson)")
+
+// Types of streamed entries
+const (
+	StreamTypeRequest = "request"
+	StreamTypeEvent   = "event"
+)
+
+// StreamEntry is a recorded request/response pair as streamed by --record-stdout, and as kept in the entries
+// of a recording file
+type StreamEntry struct {
+	// Type is StreamTypeRequest
+	Type string `json:"type" mapstructure:"type" yaml:"type"`
+
+	// Time is when the request was sent by the client
+	Time time.Time `json:"time" mapstructure:"time" yaml:"time"`
+
+	// Request is the request sent by the client, after redaction
+	Request string `json:"request" mapstructure:"request" yaml:"request"`
+
+	// Response is the complete response of the device, after redaction
+	Response string `json:"response" mapstructure:"response" yaml:"response"`
+
+	// Chunks are the reads the response was received in
+	Chunks []StreamChunk `json:"chunks" mapstructure:"chunks" yaml:"chunks"`
+}
+
+// StreamEvent is a recorded port event as streamed by --record-stdout
+type StreamEvent struct {
+	// Type is StreamTypeEvent
+	Type string `json:"type"`
+
+	// Time is when the client requested the change
+	Time time.Time `json:"time"`
+
+	// Event is the event type, e.g. dtr or baud-rate
+	Event string `json:"event"`
+
+	// Value is the event value, e.g. off or 1200
+	Value string `json:"value,omitempty"`
+}
+
+// StreamChunk is a single read of a streamed response
+type StreamChunk struct {
+	// Data is the unquoted data of the read
+	Data string `json:"data" mapstructure:"data" yaml:"data"`
+
+	// Delay is the time since the request or the previous read, as a Go duration string
+	Delay string `json:"delay" mapstructure:"delay" yaml:"delay"`
+}
+
+// entryStream is a Sink writing recorded request/response pairs to a writer as they are saved
+type entryStream struct {
+	mu      sync.Mutex
+	encoder *json.Encoder
+	closer  io.Closer // Optional closer of the writer
+}
+
+// newEntryStream creates an entryStream writing in the format, one of the config.RecordStdout formats, and
+// closing the closer, if any, when it is closed
+func newEntryStream(w io.Writer, format string, closer io.Closer) (*entryStream, error) {
+	if format != config.RecordStdoutNDJSON {
+		if closer != nil {
+			_ = closer.Close()
+		}
+
+		return nil, fmt.Errorf("%w: %q", ErrUnsupportedRecordStdout, format)
+	}
+
+	encoder := json.NewEncoder(w)
+	encoder.SetEscapeHTML(false)
+
+	return &entryStream{encoder: encoder, closer: closer}, nil
+}
+
+// newStreamEntry returns the entry of a request sent at start and its recorded response, unquoting the chunks
+func newStreamEntry(start time.Time, request string, response emulatorConfig.ResponseOption) StreamEntry {
+	entry := StreamEntry{
+		Type:    StreamTypeRequest,
+		Time:    start,
+		Request: request,
+		Chunks:  make([]StreamChunk, 0, len(response.Chunks)),
+	}
+
+	var full strings.Builder
+	for _, chunk := range response.Chunks {
+		data, err := strconv.Unquote(chunk.Data)
+		if err != nil {
+			// Recorded chunk data is always quoted, leave anything else as it is
+			data = chunk.Data
+		}
+
+		full.WriteString(data)
+		entry.Chunks = append(entry.Chunks, StreamChunk{Data: data, Delay: chunk.Delay.String()})
+	}
+
+	entry.Response = full.String()
+
+	return entry
+}
+
+// WriteEntry implements Sink, writing the request and its response as a single line
+func (s *entryStream) WriteEntry(start time.Time, request string, response emulatorConfig.ResponseOption) error {
+	entry := newStreamEntry(start, request, response)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.encoder.Encode(entry) //nolint:wrapcheck
+}
+
+// WriteEvent implements Sink, writing a port event as a single line
+func (s *entryStream) WriteEvent(at time.Time, event emulatorConfig.PortEvent) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.encoder.Encode(StreamEvent{ //nolint:wrapcheck
+		Type:  StreamTypeEvent,
+		Time:  at,
+		Event: event.Type,
+		Value: event.Value,
+	})
+}
+
+// Close implements Sink
+func (s *entryStream) Close() error {
+	if s.closer == nil {
+		return nil
+	}
+
+	return s.closer.Close() //nolint:wrapcheck
+}
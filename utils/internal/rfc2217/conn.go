@@ -0,0 +1,54 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package rfc2217
+
+import (
+	"log/slog"
+	"net"
+)
+
+// Conn serves a serial port to a client over RFC 2217. Reads return the data sent by the client with
+// telnet commands removed, and writes escape the data sent to the client.
+type Conn struct {
+	net.Conn
+
+	session *Session
+}
+
+// NewConn starts a session with a connected client, see NewSession
+func NewConn(conn net.Conn, baudRate int, logger *slog.Logger, onEvent EventHandler) (*Conn, error) {
+	session := NewSession(conn, baudRate, logger, onEvent)
+	if err := session.Offer(); err != nil {
+		return nil, err
+	}
+
+	return &Conn{Conn: conn, session: session}, nil
+}
+
+// Read reads data sent by the client, a read of only telnet commands returns no data
+func (c *Conn) Read(b []byte) (int, error) {
+	raw := make([]byte, len(b))
+	n, err := c.Conn.Read(raw)
+
+	// Errors are returned unwrapped so timeouts and EOF can be detected by the caller
+	return c.session.Decode(raw[:n], b), err //nolint:wrapcheck
+}
+
+// Write sends data to the client
+func (c *Conn) Write(b []byte) (int, error) {
+	return c.session.Write(b)
+}
@@ -0,0 +1,343 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package rfc2217
+
+import (
+	"encoding/binary"
+	"fmt"
+	"log/slog"
+	"net"
+	"strconv"
+	"sync"
+
+	emulatorConfig "github.com/detiber/k8s-jumperless/utils/internal/emulator/config"
+	"github.com/detiber/k8s-jumperless/utils/internal/logging"
+)
+
+// Telnet commands and options (RFC 854, RFC 856, RFC 858) and the COM-PORT-OPTION (RFC 2217)
+const (
+	telnetSE    = 240
+	telnetBreak = 243
+	telnetSB    = 250
+	telnetWILL  = 251
+	telnetWONT  = 252
+	telnetDO    = 253
+	telnetDONT  = 254
+	telnetIAC   = 255
+
+	optionBinary          = 0
+	optionSuppressGoAhead = 3
+	optionComPort         = 44
+
+	// Server replies to COM-PORT-OPTION commands use the client command code plus this offset
+	comPortServerOffset = 100
+
+	comPortSetBaudRate = 1
+	comPortSetDataSize = 2
+	comPortSetParity   = 3
+	comPortSetStopSize = 4
+	comPortSetControl  = 5
+
+	comPortBaudRateSize   = 4
+	comPortDataSize8      = 8
+	comPortParityNone     = 1
+	comPortStopSize1      = 1
+	comPortControlRequest = 0
+	comPortNoFlowControl  = 1
+	comPortBreakOn        = 5
+	comPortBreakOff       = 6
+	comPortDTROn          = 8
+	comPortDTROff         = 9
+	comPortRTSOn          = 11
+	comPortRTSOff         = 12
+
+	// maxSubnegotiation limits the subnegotiation payload buffered from a client
+	maxSubnegotiation = 64
+)
+
+// telnet decoder states
+const (
+	stateData = iota
+	stateIAC
+	stateOption
+	stateSubnegotiation
+	stateSubnegotiationIAC
+)
+
+// EventHandler is called with the port events requested by a client, using the event types and values
+// of the emulator config
+type EventHandler func(eventType, value string)
+
+// Session is the server side of a telnet based RFC 2217 serial port session with a single client.
+// Line settings are acknowledged and queries report the configured baud rate, changes of the baud
+// rate, the DTR and RTS lines and breaks are reported to the event handler.
+type Session struct {
+	conn     net.Conn
+	logger   *slog.Logger
+	baudRate int
+	onEvent  EventHandler
+
+	writeLock sync.Mutex
+
+	// decoder and option state, only used by Decode
+	state  int
+	verb   byte
+	sb     []byte
+	local  map[byte]bool // options performed by the server
+	remote map[byte]bool // options performed by the client
+}
+
+// NewSession creates a session for a connected client, onEvent may be nil to ignore port events
+func NewSession(conn net.Conn, baudRate int, logger *slog.Logger, onEvent EventHandler) *Session {
+	return &Session{
+		conn:     conn,
+		logger:   logger,
+		baudRate: baudRate,
+		onEvent:  onEvent,
+		state:    stateData,
+		local:    map[byte]bool{optionBinary: true, optionSuppressGoAhead: true, optionComPort: true},
+		remote:   map[byte]bool{optionBinary: true},
+	}
+}
+
+// Offer offers the client a binary, character at a time session with serial port control
+func (s *Session) Offer() error {
+	return s.send(
+		telnetIAC, telnetWILL, optionBinary,
+		telnetIAC, telnetDO, optionBinary,
+		telnetIAC, telnetWILL, optionSuppressGoAhead,
+		telnetIAC, telnetWILL, optionComPort,
+	)
+}
+
+// Write sends data to the client, escaping any bytes that would be interpreted as telnet commands
+func (s *Session) Write(b []byte) (int, error) {
+	escaped := make([]byte, 0, len(b))
+	for _, c := range b {
+		if c == telnetIAC {
+			escaped = append(escaped, telnetIAC)
+		}
+		escaped = append(escaped, c)
+	}
+
+	if err := s.send(escaped...); err != nil {
+		return 0, err
+	}
+
+	return len(b), nil
+}
+
+func (s *Session) send(data ...byte) error {
+	s.writeLock.Lock()
+	defer s.writeLock.Unlock()
+
+	if _, err := s.conn.Write(data); err != nil {
+		return fmt.Errorf("failed to write to RFC2217 client: %w", err)
+	}
+
+	return nil
+}
+
+// event reports a port event to the event handler
+func (s *Session) event(eventType, value string) {
+	s.logger.Debug("RFC2217 port event", "event", eventType, "value", value)
+
+	if s.onEvent != nil {
+		s.onEvent(eventType, value)
+	}
+}
+
+// Decode copies the data bytes from raw into b, handling any telnet commands, and returns the number of data bytes.
+// b must be at least as long as raw.
+func (s *Session) Decode(raw, b []byte) int {
+	n := 0
+
+	for _, c := range raw {
+		switch s.state {
+		case stateData:
+			if c == telnetIAC {
+				s.state = stateIAC
+				continue
+			}
+			b[n] = c
+			n++
+		case stateIAC:
+			switch c {
+			case telnetIAC:
+				b[n] = c
+				n++
+				s.state = stateData
+			case telnetWILL, telnetWONT, telnetDO, telnetDONT:
+				s.verb = c
+				s.state = stateOption
+			case telnetSB:
+				s.sb = s.sb[:0]
+				s.state = stateSubnegotiation
+			case telnetBreak:
+				s.event(emulatorConfig.EventBreak, "")
+				s.state = stateData
+			default:
+				// Other commands such as NOP have no meaning for a serial port
+				s.state = stateData
+			}
+		case stateOption:
+			s.negotiate(s.verb, c)
+			s.state = stateData
+		case stateSubnegotiation:
+			if c == telnetIAC {
+				s.state = stateSubnegotiationIAC
+				continue
+			}
+			if len(s.sb) < maxSubnegotiation {
+				s.sb = append(s.sb, c)
+			}
+		case stateSubnegotiationIAC:
+			switch c {
+			case telnetSE:
+				s.subnegotiate(s.sb)
+				s.state = stateData
+			case telnetIAC:
+				if len(s.sb) < maxSubnegotiation {
+					s.sb = append(s.sb, c)
+				}
+				s.state = stateSubnegotiation
+			default:
+				s.state = stateSubnegotiation
+			}
+		}
+	}
+
+	return n
+}
+
+// negotiate answers option requests from the client. Options are tracked separately for each side,
+// the server performs binary, suppress go ahead and COM-PORT-OPTION and accepts the client performing
+// binary and suppress go ahead. Requests matching the current state are not answered to avoid loops.
+func (s *Session) negotiate(verb, option byte) {
+	var reply byte
+
+	switch verb {
+	case telnetDO:
+		switch {
+		case s.local[option]:
+			return
+		case option == optionBinary || option == optionSuppressGoAhead || option == optionComPort:
+			s.local[option] = true
+			reply = telnetWILL
+		default:
+			reply = telnetWONT
+		}
+	case telnetDONT:
+		if !s.local[option] {
+			return
+		}
+		s.local[option] = false
+		reply = telnetWONT
+	case telnetWILL:
+		switch {
+		case s.remote[option]:
+			return
+		case option == optionBinary || option == optionSuppressGoAhead:
+			s.remote[option] = true
+			reply = telnetDO
+		default:
+			reply = telnetDONT
+		}
+	case telnetWONT:
+		if !s.remote[option] {
+			return
+		}
+		s.remote[option] = false
+		reply = telnetDONT
+	}
+
+	if err := s.send(telnetIAC, reply, option); err != nil {
+		s.logger.Warn("Failed to negotiate telnet option", "option", option, logging.Err(err))
+	}
+}
+
+// subnegotiate answers COM-PORT-OPTION commands. Settings are acknowledged and queries report the
+// configured line settings, baud rate and control line changes are reported as port events.
+func (s *Session) subnegotiate(payload []byte) {
+	if len(payload) < 2 || payload[0] != optionComPort {
+		return
+	}
+
+	command := payload[1]
+	value := payload[2:]
+
+	switch command {
+	case comPortSetBaudRate:
+		if len(value) == comPortBaudRateSize {
+			if baudRate := binary.BigEndian.Uint32(value); baudRate != 0 {
+				s.event(emulatorConfig.EventBaudRate, strconv.FormatUint(uint64(baudRate), 10))
+			} else {
+				value = binary.BigEndian.AppendUint32(nil, uint32(s.baudRate)) //nolint:gosec
+			}
+		}
+	case comPortSetDataSize:
+		if len(value) == 1 && value[0] == 0 {
+			value = []byte{comPortDataSize8}
+		}
+	case comPortSetParity:
+		if len(value) == 1 && value[0] == 0 {
+			value = []byte{comPortParityNone}
+		}
+	case comPortSetStopSize:
+		if len(value) == 1 && value[0] == 0 {
+			value = []byte{comPortStopSize1}
+		}
+	case comPortSetControl:
+		if len(value) == 1 {
+			s.control(value[0])
+			if value[0] == comPortControlRequest {
+				value = []byte{comPortNoFlowControl}
+			}
+		}
+	}
+
+	reply := []byte{telnetIAC, telnetSB, optionComPort, command + comPortServerOffset}
+	for _, c := range value {
+		if c == telnetIAC {
+			reply = append(reply, telnetIAC)
+		}
+		reply = append(reply, c)
+	}
+	reply = append(reply, telnetIAC, telnetSE)
+
+	if err := s.send(reply...); err != nil {
+		s.logger.Warn("Failed to answer COM-PORT-OPTION command", "command", command, logging.Err(err))
+	}
+}
+
+// control reports the SET-CONTROL values changing the break state or the DTR and RTS lines
+func (s *Session) control(value byte) {
+	switch value {
+	case comPortBreakOn:
+		s.event(emulatorConfig.EventBreak, emulatorConfig.EventOn)
+	case comPortBreakOff:
+		s.event(emulatorConfig.EventBreak, emulatorConfig.EventOff)
+	case comPortDTROn:
+		s.event(emulatorConfig.EventDTR, emulatorConfig.EventOn)
+	case comPortDTROff:
+		s.event(emulatorConfig.EventDTR, emulatorConfig.EventOff)
+	case comPortRTSOn:
+		s.event(emulatorConfig.EventRTS, emulatorConfig.EventOn)
+	case comPortRTSOff:
+		s.event(emulatorConfig.EventRTS, emulatorConfig.EventOff)
+	}
+}
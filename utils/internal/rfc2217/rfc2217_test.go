@@ -0,0 +1,111 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package rfc2217
+
+import (
+	"io"
+	"log/slog"
+	"net"
+	"testing"
+
+	. "github.com/onsi/gomega"
+
+	emulatorConfig "github.com/detiber/k8s-jumperless/utils/internal/emulator/config"
+)
+
+func TestDecode(t *testing.T) {
+	tests := []struct {
+		name   string
+		raw    []byte
+		data   string
+		events [][2]string
+	}{
+		{
+			name: "data",
+			raw:  []byte("?\r\n"),
+			data: "?\r\n",
+		},
+		{
+			name: "escaped IAC",
+			raw:  []byte{'a', telnetIAC, telnetIAC, 'b'},
+			data: "a\xffb",
+		},
+		{
+			name: "set baud rate",
+			raw:  []byte{telnetIAC, telnetSB, optionComPort, comPortSetBaudRate, 0, 0, 4, 176, telnetIAC, telnetSE},
+			events: [][2]string{
+				{emulatorConfig.EventBaudRate, "1200"},
+			},
+		},
+		{
+			name: "query baud rate",
+			raw:  []byte{telnetIAC, telnetSB, optionComPort, comPortSetBaudRate, 0, 0, 0, 0, telnetIAC, telnetSE},
+		},
+		{
+			name: "DTR and RTS toggles between data",
+			raw: []byte{
+				'a',
+				telnetIAC, telnetSB, optionComPort, comPortSetControl, comPortDTROff, telnetIAC, telnetSE,
+				telnetIAC, telnetSB, optionComPort, comPortSetControl, comPortRTSOn, telnetIAC, telnetSE,
+				'b',
+			},
+			data: "ab",
+			events: [][2]string{
+				{emulatorConfig.EventDTR, emulatorConfig.EventOff},
+				{emulatorConfig.EventRTS, emulatorConfig.EventOn},
+			},
+		},
+		{
+			name: "break state and telnet break",
+			raw: []byte{
+				telnetIAC, telnetSB, optionComPort, comPortSetControl, comPortBreakOn, telnetIAC, telnetSE,
+				telnetIAC, telnetSB, optionComPort, comPortSetControl, comPortBreakOff, telnetIAC, telnetSE,
+				telnetIAC, telnetBreak,
+			},
+			events: [][2]string{
+				{emulatorConfig.EventBreak, emulatorConfig.EventOn},
+				{emulatorConfig.EventBreak, emulatorConfig.EventOff},
+				{emulatorConfig.EventBreak, ""},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			server, client := net.Pipe()
+			t.Cleanup(func() {
+				_ = server.Close()
+				_ = client.Close()
+			})
+
+			// Discard the replies to the client
+			go func() { _, _ = io.Copy(io.Discard, client) }()
+
+			events := [][2]string{}
+			session := NewSession(server, emulatorConfig.DefaultBaudRate, slog.New(slog.DiscardHandler),
+				func(eventType, value string) { events = append(events, [2]string{eventType, value}) })
+
+			b := make([]byte, len(tt.raw))
+			n := session.Decode(tt.raw, b)
+
+			g.Expect(string(b[:n])).To(Equal(tt.data))
+			g.Expect(events).To(Equal(append([][2]string{}, tt.events...)))
+		})
+	}
+}
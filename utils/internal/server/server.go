@@ -0,0 +1,149 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package server
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net"
+	"net/http"
+	"time"
+
+	"github.com/detiber/k8s-jumperless/internal/transport"
+	"github.com/detiber/k8s-jumperless/utils/internal/logging"
+)
+
+var ErrBindRequired = errors.New("listening on a non-loopback address requires --bind")
+
+const (
+	// DefaultBind keeps network listeners on the local machine unless another address is bound explicitly,
+	// since lab machines often sit on shared networks
+	DefaultBind = "127.0.0.1"
+
+	// readHeaderTimeout limits slow clients, the endpoints only serve small requests
+	readHeaderTimeout = 5 * time.Second
+
+	// shutdownTimeout is how long in-flight requests are given to finish when the server stops
+	shutdownTimeout = time.Second
+
+	// Flag names of the authentication of network listeners serving serial ports, see transport.Config
+	FlagTLSCertFile          = "tls-cert-file"
+	FlagTLSKeyFile           = "tls-key-file"
+	FlagTLSCAFile            = "tls-ca-file"
+	FlagTokenFile            = "token-file"
+	FlagAllowUnauthenticated = "allow-unauthenticated"
+
+	// KeySecurity is the key of the authentication settings below the prefix of a command
+	KeySecurity = "security"
+)
+
+// ResolveAddr returns the TCP address to listen on for addr, which is a port or a host and port.
+// Addresses without a host are bound to bind, and addresses with a host are only accepted if the host
+// is a loopback address or bind is not, so listening on other interfaces always requires changing bind.
+func ResolveAddr(addr, bind string) (string, error) {
+	if bind == "" {
+		bind = DefaultBind
+	}
+
+	host, port, err := net.SplitHostPort(addr)
+	if err != nil {
+		// A bare port, e.g. 7332
+		host, port = "", addr
+	}
+
+	if host == "" {
+		return net.JoinHostPort(bind, port), nil
+	}
+
+	if !isLoopback(host) && isLoopback(bind) {
+		return "", fmt.Errorf("%w: %s", ErrBindRequired, addr)
+	}
+
+	return net.JoinHostPort(host, port), nil
+}
+
+// isLoopback returns true if host only accepts connections from the local machine
+func isLoopback(host string) bool {
+	if host == "localhost" {
+		return true
+	}
+
+	ip := net.ParseIP(host)
+
+	return ip != nil && ip.IsLoopback()
+}
+
+// Serve serves handler over HTTP on addr until ctx is done. The listener is created before Serve
+// returns, so address errors are reported to the caller. name describes the endpoints in logs.
+func Serve(ctx context.Context, addr, name string, handler http.Handler, logger *slog.Logger) error {
+	listener, err := (&net.ListenConfig{}).Listen(ctx, "tcp", addr)
+	if err != nil {
+		return fmt.Errorf("failed to listen for %s on %s: %w", name, addr, err)
+	}
+
+	serve(ctx, listener, name, handler, logger)
+
+	return nil
+}
+
+// ServeSecured serves handler like Serve, over HTTPS if creds has a certificate and only to requests with the
+// bearer token if creds has a token. Serving on a non-loopback address without authentication is refused,
+// unless creds allows it.
+func ServeSecured(ctx context.Context, addr, name string, handler http.Handler, creds *transport.Credentials,
+	logger *slog.Logger) error {
+	if err := creds.CheckExposure(addr, true); err != nil {
+		return fmt.Errorf("unable to serve %s: %w", name, err)
+	}
+
+	listener, err := creds.Listen(ctx, addr)
+	if err != nil {
+		return fmt.Errorf("failed to listen for %s: %w", name, err)
+	}
+
+	serve(ctx, listener, name, creds.Handler(handler), logger)
+
+	return nil
+}
+
+// serve serves handler on listener until ctx is done
+func serve(ctx context.Context, listener net.Listener, name string, handler http.Handler, logger *slog.Logger) {
+	server := &http.Server{
+		Handler:           handler,
+		ReadHeaderTimeout: readHeaderTimeout,
+	}
+
+	go func() {
+		<-ctx.Done()
+
+		shutdownCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), shutdownTimeout)
+		defer cancel()
+
+		if err := server.Shutdown(shutdownCtx); err != nil {
+			logger.Warn("Failed to stop server", "server", name, logging.Err(err))
+		}
+	}()
+
+	go func() {
+		if err := server.Serve(listener); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			logger.Error("Error serving", "server", name, logging.Err(err))
+		}
+	}()
+
+	logger.Info("Serving "+name, "listen", listener.Addr())
+}
@@ -0,0 +1,107 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package server_test
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"net"
+	"net/http"
+	"testing"
+
+	. "github.com/onsi/gomega"
+
+	emulatorConfig "github.com/detiber/k8s-jumperless/utils/internal/emulator/config"
+	proxyConfig "github.com/detiber/k8s-jumperless/utils/internal/proxy/config"
+	"github.com/detiber/k8s-jumperless/utils/internal/server"
+)
+
+func TestResolveAddr(t *testing.T) {
+	tests := []struct {
+		name string
+		addr string
+		bind string
+		want string
+		err  error
+	}{
+		{name: "port only", addr: ":7332", bind: server.DefaultBind, want: "127.0.0.1:7332"},
+		{name: "bare port", addr: "7332", bind: server.DefaultBind, want: "127.0.0.1:7332"},
+		{name: "empty bind", addr: ":7332", bind: "", want: "127.0.0.1:7332"},
+		{name: "localhost", addr: "localhost:7332", bind: server.DefaultBind, want: "localhost:7332"},
+		{name: "ipv6 loopback", addr: "[::1]:7332", bind: server.DefaultBind, want: "[::1]:7332"},
+		{name: "all interfaces", addr: "0.0.0.0:7332", bind: server.DefaultBind, err: server.ErrBindRequired},
+		{name: "other interface", addr: "192.168.1.10:7332", bind: "::1", err: server.ErrBindRequired},
+		{name: "bound to all interfaces", addr: ":7332", bind: "0.0.0.0", want: "0.0.0.0:7332"},
+		{name: "explicit host with bind", addr: "192.168.1.10:7332", bind: "0.0.0.0", want: "192.168.1.10:7332"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			got, err := server.ResolveAddr(tt.addr, tt.bind)
+			if tt.err != nil {
+				g.Expect(errors.Is(err, tt.err)).To(BeTrue())
+				return
+			}
+
+			g.Expect(err).NotTo(HaveOccurred())
+			g.Expect(got).To(Equal(tt.want))
+		})
+	}
+}
+
+// The network listeners of the emulator and the proxy must only be reachable from the local machine by default
+func TestDefaultBindIsLoopback(t *testing.T) {
+	g := NewWithT(t)
+
+	for _, bind := range []string{emulatorConfig.NewDefaultConfig().Bind, proxyConfig.NewDefaultConfig().Bind} {
+		addr, err := server.ResolveAddr(":0", bind)
+		g.Expect(err).NotTo(HaveOccurred())
+
+		host, _, err := net.SplitHostPort(addr)
+		g.Expect(err).NotTo(HaveOccurred())
+		g.Expect(net.ParseIP(host).IsLoopback()).To(BeTrue())
+	}
+}
+
+func TestServe(t *testing.T) {
+	g := NewWithT(t)
+
+	ctx, cancel := context.WithCancel(t.Context())
+	defer cancel()
+
+	// Find a free port on the loopback interface
+	listener, err := (&net.ListenConfig{}).Listen(ctx, "tcp", "127.0.0.1:0")
+	g.Expect(err).NotTo(HaveOccurred())
+	addr := listener.Addr().String()
+	g.Expect(listener.Close()).To(Succeed())
+
+	handler := http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusNoContent)
+	})
+	g.Expect(server.Serve(ctx, addr, "test", handler, slog.New(slog.DiscardHandler))).To(Succeed())
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, "http://"+addr+"/", nil)
+	g.Expect(err).NotTo(HaveOccurred())
+
+	res, err := http.DefaultClient.Do(req)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(res.Body.Close()).To(Succeed())
+	g.Expect(res.StatusCode).To(Equal(http.StatusNoContent))
+}
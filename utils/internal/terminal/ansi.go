@@ -0,0 +1,74 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package terminal
+
+import (
+	"bytes"
+
+	"github.com/charmbracelet/x/ansi"
+)
+
+const (
+	esc = 0x1b
+	bel = 0x07
+
+	// maxPendingEscape is the longest incomplete escape sequence held back, longer ones are passed on as they are
+	maxPendingEscape = 64
+)
+
+// ansiStripper removes ANSI escape sequences from a stream of output. Sequences split across reads from
+// the serial port are held back until they are complete, so they are removed as a whole.
+type ansiStripper struct {
+	pending []byte
+}
+
+// Strip returns the data without ANSI escape sequences, holding back a trailing incomplete sequence
+func (s *ansiStripper) Strip(data []byte) []byte {
+	buf := append(s.pending, data...) //nolint:gocritic
+	s.pending = nil
+
+	if i := bytes.LastIndexByte(buf, esc); i >= 0 && len(buf)-i < maxPendingEscape && !escapeComplete(buf[i:]) {
+		s.pending = bytes.Clone(buf[i:])
+		buf = buf[:i]
+	}
+
+	return []byte(ansi.Strip(string(buf)))
+}
+
+// escapeComplete returns true if the escape sequence at the start of seq is complete
+func escapeComplete(seq []byte) bool {
+	if len(seq) < 2 { //nolint:mnd
+		return false
+	}
+
+	switch seq[1] {
+	case '[':
+		// CSI sequences end with a final byte between 0x40 and 0x7e
+		for _, b := range seq[2:] {
+			if b >= 0x40 && b <= 0x7e {
+				return true
+			}
+		}
+
+		return false
+	case ']':
+		// OSC sequences end with BEL or ST (ESC \)
+		return bytes.IndexByte(seq[2:], bel) >= 0 || bytes.Contains(seq[2:], []byte{esc, '\\'})
+	default:
+		return true
+	}
+}
@@ -0,0 +1,130 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package config
+
+import (
+	"time"
+
+	"github.com/spf13/viper"
+)
+
+const (
+	// Default values for the terminal configuration
+	DefaultBaudRate   = 115200
+	DefaultBufferSize = 1024
+	DefaultLineEnding = LineEndingCR
+
+	// Line endings sent after each line entered
+	LineEndingCR   = "cr"
+	LineEndingLF   = "lf"
+	LineEndingCRLF = "crlf"
+	LineEndingNone = "none"
+
+	// Flag names for command-line arguments
+	FlagBaudRate   = "baud-rate"
+	FlagBufferSize = "buffer-size"
+	FlagPort       = "port"
+	FlagLineEnding = "line-ending"
+	FlagStripANSI  = "strip-ansi"
+	FlagHistory    = "history"
+	FlagLog        = "log"
+	FlagDTRPulse   = "dtr-pulse"
+
+	// Viper prefix and keys for configuration
+	ViperPrefix     = "terminal"
+	ViperBaudRate   = ViperPrefix + "." + FlagBaudRate
+	ViperBufferSize = ViperPrefix + "." + FlagBufferSize
+	ViperPort       = ViperPrefix + "." + FlagPort
+	ViperLineEnding = ViperPrefix + "." + FlagLineEnding
+	ViperStripANSI  = ViperPrefix + "." + FlagStripANSI
+	ViperHistory    = ViperPrefix + "." + FlagHistory
+	ViperLog        = ViperPrefix + "." + FlagLog
+	ViperDTRPulse   = ViperPrefix + "." + FlagDTRPulse
+)
+
+// NewDefaultConfig returns a TerminalConfig with default values
+func NewDefaultConfig() *TerminalConfig {
+	return &TerminalConfig{
+		BaudRate:   DefaultBaudRate,
+		BufferSize: DefaultBufferSize,
+		Port:       "",
+		LineEnding: DefaultLineEnding,
+		StripANSI:  false,
+		History:    "",
+		Log:        "",
+	}
+}
+
+// NewFromViper creates a TerminalConfig from a viper instance
+func NewFromViper(v *viper.Viper) *TerminalConfig {
+	cfg := NewDefaultConfig()
+
+	if v.IsSet(ViperBaudRate) {
+		cfg.BaudRate = v.GetInt(ViperBaudRate)
+	}
+	if v.IsSet(ViperBufferSize) {
+		cfg.BufferSize = v.GetInt(ViperBufferSize)
+	}
+	if v.IsSet(ViperPort) {
+		cfg.Port = v.GetString(ViperPort)
+	}
+	if v.IsSet(ViperLineEnding) {
+		cfg.LineEnding = v.GetString(ViperLineEnding)
+	}
+	if v.IsSet(ViperStripANSI) {
+		cfg.StripANSI = v.GetBool(ViperStripANSI)
+	}
+	if v.IsSet(ViperHistory) {
+		cfg.History = v.GetString(ViperHistory)
+	}
+	if v.IsSet(ViperLog) {
+		cfg.Log = v.GetString(ViperLog)
+	}
+	if v.IsSet(ViperDTRPulse) {
+		cfg.DTRPulse = v.GetDuration(ViperDTRPulse)
+	}
+
+	return cfg
+}
+
+// TerminalConfig represents the terminal configuration
+type TerminalConfig struct {
+	// BaudRate is the baud rate of the serial port
+	BaudRate int `json:"baudRate" mapstructure:"baud-rate" yaml:"baudRate"`
+
+	// BufferSize is the size of the buffer for reading from the serial port
+	BufferSize int `json:"bufferSize" mapstructure:"buffer-size" yaml:"bufferSize"`
+
+	// Port is the serial port to open, e.g. an emulator virtual port. A Jumperless device is detected if not set.
+	Port string `json:"port" mapstructure:"port" yaml:"port"`
+
+	// LineEnding is sent after each line entered, LineEndingCR (the default), LineEndingLF, LineEndingCRLF
+	// or LineEndingNone
+	LineEnding string `json:"lineEnding" mapstructure:"line-ending" yaml:"lineEnding"`
+
+	// StripANSI removes ANSI escape sequences from the device output, for terminals that can't display them
+	StripANSI bool `json:"stripANSI" mapstructure:"strip-ansi" yaml:"stripANSI"`
+
+	// History is a file the command history is loaded from and saved to, the history is not saved if not set
+	History string `json:"history" mapstructure:"history" yaml:"history"`
+
+	// Log is a file the session output is appended to without ANSI escape sequences, if set
+	Log string `json:"log" mapstructure:"log" yaml:"log"`
+
+	// DTRPulse is how long DTR is dropped after opening the port to wake or reset the device, zero disables it
+	DTRPulse time.Duration `json:"dtrPulse" mapstructure:"dtr-pulse" yaml:"dtrPulse"`
+}
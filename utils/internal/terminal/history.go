@@ -0,0 +1,109 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package terminal
+
+import (
+	"bufio"
+	"fmt"
+	"log/slog"
+	"os"
+	"strings"
+
+	"github.com/detiber/k8s-jumperless/utils/internal/logging"
+)
+
+// maxHistory is the number of history entries kept
+const maxHistory = 1000
+
+// history is the command history of the line editor, optionally saved to a file as lines are entered
+type history struct {
+	logger  *slog.Logger
+	entries []string // Oldest entry first
+	file    *os.File
+}
+
+// newHistory loads the history from the file and appends new entries to it, if a file is given
+func newHistory(path string, logger *slog.Logger) (*history, error) {
+	h := &history{logger: logger}
+
+	if path == "" {
+		return h, nil
+	}
+
+	file, err := os.OpenFile(path, os.O_CREATE|os.O_RDWR|os.O_APPEND, 0o600) //nolint:mnd
+	if err != nil {
+		return nil, fmt.Errorf("failed to open history file %s: %w", path, err)
+	}
+
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		h.add(scanner.Text())
+	}
+
+	if err := scanner.Err(); err != nil {
+		_ = file.Close()
+		return nil, fmt.Errorf("failed to read history file %s: %w", path, err)
+	}
+
+	h.file = file
+
+	return h, nil
+}
+
+// add adds an entry to the history, returning false if it is empty or repeats the previous entry
+func (h *history) add(entry string) bool {
+	if entry == "" || (len(h.entries) > 0 && h.entries[len(h.entries)-1] == entry) {
+		return false
+	}
+
+	h.entries = append(h.entries, entry)
+	if len(h.entries) > maxHistory {
+		h.entries = h.entries[len(h.entries)-maxHistory:]
+	}
+
+	return true
+}
+
+// Add adds an entry to the history, saving it to the history file
+func (h *history) Add(entry string) {
+	if !h.add(strings.TrimSpace(entry)) || h.file == nil {
+		return
+	}
+
+	if _, err := fmt.Fprintln(h.file, strings.TrimSpace(entry)); err != nil {
+		h.logger.Warn("Failed to save history", logging.Err(err))
+	}
+}
+
+// Len returns the number of entries in the history
+func (h *history) Len() int {
+	return len(h.entries)
+}
+
+// At returns an entry of the history, index 0 is the most recent entry
+func (h *history) At(idx int) string {
+	return h.entries[len(h.entries)-1-idx]
+}
+
+// Close closes the history file
+func (h *history) Close() error {
+	if h.file == nil {
+		return nil
+	}
+
+	return h.file.Close() //nolint:wrapcheck
+}
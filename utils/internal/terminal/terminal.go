@@ -0,0 +1,314 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package terminal
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"time"
+
+	"go.bug.st/serial"
+	"golang.org/x/term"
+
+	"github.com/detiber/k8s-jumperless/jumperless"
+	"github.com/detiber/k8s-jumperless/utils/internal/logging"
+	"github.com/detiber/k8s-jumperless/utils/internal/terminal/config"
+)
+
+var ErrNoJumperlessDevice = errors.New("no Jumperless device found")
+var ErrUnsupportedLineEnding = errors.New("unsupported line ending (use cr, lf, crlf, or none)")
+
+// drainTimeout is how long output is still copied once a non-interactive input ends
+const drainTimeout = 500 * time.Millisecond
+
+// lineEndings maps the configurable line endings to the data sent
+var lineEndings = map[string]string{ //nolint:gochecknoglobals
+	config.LineEndingCR:   "\r",
+	config.LineEndingLF:   "\n",
+	config.LineEndingCRLF: "\r\n",
+	config.LineEndingNone: "",
+}
+
+// Terminal is an interactive session with a Jumperless device over a serial port
+type Terminal struct {
+	config     *config.TerminalConfig
+	logger     *slog.Logger
+	lineEnding string
+	stdin      *os.File
+	stdout     io.Writer
+}
+
+// New creates a new terminal instance
+func New(c *config.TerminalConfig, logger *slog.Logger) (*Terminal, error) {
+	if logger == nil {
+		logger = logging.Subsystem(slog.Default(), "terminal")
+	}
+
+	lineEnding, ok := lineEndings[c.LineEnding]
+	if !ok {
+		return nil, fmt.Errorf("%w: %q", ErrUnsupportedLineEnding, c.LineEnding)
+	}
+
+	return &Terminal{
+		config:     c,
+		logger:     logger,
+		lineEnding: lineEnding,
+		stdin:      os.Stdin,
+		stdout:     os.Stdout,
+	}, nil
+}
+
+// Run opens the serial port and runs the session until the input ends, the port is closed or ctx is done
+func (t *Terminal) Run(ctx context.Context) error {
+	if t.config.Port == "" {
+		t.logger.Info("No port configured, attempting to detect...")
+
+		j, err := jumperless.NewJumperless(ctx, t.config.Port, t.config.BaudRate)
+		if err != nil {
+			return fmt.Errorf("failed to create Jumperless instance for port detection: %w", err)
+		}
+
+		if j == nil {
+			return ErrNoJumperlessDevice
+		}
+
+		t.config.Port = j.GetPort()
+
+		t.logger.Info("Detected Jumperless port", "port", t.config.Port, "version", j.GetVersion())
+	}
+
+	lock, err := jumperless.LockDevice(t.config.Port)
+	if err != nil {
+		return fmt.Errorf("failed to lock serial port %s: %w", t.config.Port, err)
+	}
+
+	defer func() {
+		if err := lock.Unlock(); err != nil {
+			t.logger.Warn("Failed to unlock serial port", logging.Err(err))
+		}
+	}()
+
+	port, err := jumperless.OpenSerialPort(t.config.Port, &serial.Mode{BaudRate: t.config.BaudRate})
+	if err != nil {
+		return fmt.Errorf("failed to open serial port %s: %w", t.config.Port, err)
+	}
+
+	defer func() {
+		if err := port.Close(); err != nil {
+			t.logger.Warn("Failed to close serial port", logging.Err(err))
+		}
+	}()
+
+	if t.config.DTRPulse > 0 {
+		t.logger.Info("Pulsing DTR", "duration", t.config.DTRPulse)
+		if err := jumperless.PulseDTR(port, t.config.DTRPulse); err != nil {
+			return fmt.Errorf("failed to pulse DTR on serial port %s: %w", t.config.Port, err)
+		}
+	}
+
+	var sessionLog io.Writer
+	if t.config.Log != "" {
+		file, err := t.openLog()
+		if err != nil {
+			return err
+		}
+
+		defer func() {
+			if err := file.Close(); err != nil {
+				t.logger.Warn("Failed to close session log", logging.Err(err))
+			}
+		}()
+
+		sessionLog = file
+	}
+
+	hist, err := newHistory(t.config.History, t.logger)
+	if err != nil {
+		return err
+	}
+
+	defer func() {
+		if err := hist.Close(); err != nil {
+			t.logger.Warn("Failed to close history file", logging.Err(err))
+		}
+	}()
+
+	// Line editing is only available when the input is a terminal, otherwise lines are read as they are
+	var editor *term.Terminal
+	output := t.stdout
+
+	if fd := int(t.stdin.Fd()); term.IsTerminal(fd) {
+		state, err := term.MakeRaw(fd)
+		if err != nil {
+			return fmt.Errorf("failed to put terminal into raw mode: %w", err)
+		}
+
+		defer func() {
+			if err := term.Restore(fd, state); err != nil {
+				t.logger.Warn("Failed to restore terminal", logging.Err(err))
+			}
+		}()
+
+		editor = term.NewTerminal(struct {
+			io.Reader
+			io.Writer
+		}{t.stdin, t.stdout}, "")
+		editor.History = hist
+
+		if width, height, err := term.GetSize(fd); err == nil && width > 0 {
+			_ = editor.SetSize(width, height)
+		}
+
+		output = editor
+	}
+
+	t.logger.Info("Connected, press Ctrl+D or Ctrl+C to exit", "port", t.config.Port)
+
+	readErr := make(chan error, 1)
+	go func() {
+		readErr <- t.copyOutput(port, output, sessionLog)
+	}()
+
+	inputErr := make(chan error, 1)
+	go func() {
+		if editor != nil {
+			inputErr <- t.editInput(port, editor)
+		} else {
+			inputErr <- t.copyInput(port, hist)
+		}
+	}()
+
+	select {
+	case <-ctx.Done():
+		return nil
+	case err := <-readErr:
+		return err
+	case err := <-inputErr:
+		if err != nil || editor != nil {
+			return err
+		}
+
+		// Give the device time to answer the last line of a non-interactive input
+		select {
+		case <-ctx.Done():
+		case <-time.After(drainTimeout):
+		}
+
+		return nil
+	}
+}
+
+// openLog opens the session log for appending
+func (t *Terminal) openLog() (*os.File, error) {
+	file, err := os.OpenFile(t.config.Log, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o600) //nolint:mnd
+	if err != nil {
+		return nil, fmt.Errorf("failed to open session log %s: %w", t.config.Log, err)
+	}
+
+	if _, err := fmt.Fprintf(file, "--- Session on %s started at %s ---\n",
+		t.config.Port, time.Now().Format(time.RFC3339)); err != nil {
+		_ = file.Close()
+		return nil, fmt.Errorf("failed to write session log %s: %w", t.config.Log, err)
+	}
+
+	return file, nil
+}
+
+// copyOutput copies the device output to the output and session log until the port is closed
+func (t *Terminal) copyOutput(port serial.Port, output io.Writer, sessionLog io.Writer) error {
+	buffer := make([]byte, t.config.BufferSize)
+	outputStripper := &ansiStripper{}
+	logStripper := &ansiStripper{}
+
+	for {
+		n, err := port.Read(buffer)
+		if err != nil {
+			return fmt.Errorf("failed to read from serial port %s: %w", t.config.Port, err)
+		}
+
+		if n == 0 {
+			continue
+		}
+
+		data := buffer[:n]
+
+		if sessionLog != nil {
+			if _, err := sessionLog.Write(logStripper.Strip(data)); err != nil {
+				t.logger.Warn("Failed to write session log", logging.Err(err))
+			}
+		}
+
+		if t.config.StripANSI {
+			data = outputStripper.Strip(data)
+		}
+
+		if _, err := output.Write(data); err != nil {
+			return fmt.Errorf("failed to write output: %w", err)
+		}
+	}
+}
+
+// editInput sends the lines entered in the line editor until it is closed with Ctrl+D or Ctrl+C
+func (t *Terminal) editInput(port serial.Port, editor *term.Terminal) error {
+	for {
+		line, err := editor.ReadLine()
+		switch {
+		case errors.Is(err, io.EOF):
+			return nil
+		case errors.Is(err, term.ErrPasteIndicator):
+			// Pasted lines are sent like typed lines
+		case err != nil:
+			return fmt.Errorf("failed to read input: %w", err)
+		}
+
+		if err := t.send(port, line); err != nil {
+			return err
+		}
+	}
+}
+
+// copyInput sends the lines read from a non-interactive input until it ends
+func (t *Terminal) copyInput(port serial.Port, hist *history) error {
+	scanner := bufio.NewScanner(t.stdin)
+	for scanner.Scan() {
+		hist.Add(scanner.Text())
+
+		if err := t.send(port, scanner.Text()); err != nil {
+			return err
+		}
+	}
+
+	if err := scanner.Err(); err != nil {
+		return fmt.Errorf("failed to read input: %w", err)
+	}
+
+	return nil
+}
+
+// send writes a line to the serial port followed by the line ending
+func (t *Terminal) send(port serial.Port, line string) error {
+	if _, err := port.Write([]byte(line + t.lineEnding)); err != nil {
+		return fmt.Errorf("failed to write to serial port %s: %w", t.config.Port, err)
+	}
+
+	return nil
+}
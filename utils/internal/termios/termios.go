@@ -0,0 +1,57 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package termios reads the line settings of terminals, so the baud rate a client sets on a virtual serial
+// port can be observed on the pseudo terminal side
+package termios
+
+import (
+	"context"
+	"errors"
+	"os"
+	"time"
+)
+
+var ErrUnsupported = errors.New("reading terminal settings is not supported on this platform")
+
+// WatchBaudRate polls the baud rate of the terminal returned by file every interval until ctx is done, calling
+// onChange whenever it changes. The file is looked up again for every poll, so terminals that are replaced
+// while watching are followed. Nothing is watched if the platform doesn't support reading the baud rate.
+func WatchBaudRate(ctx context.Context, file func() *os.File, interval time.Duration, onChange func(baudRate int)) {
+	last, err := BaudRate(file())
+	if errors.Is(err, ErrUnsupported) {
+		return
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			baudRate, err := BaudRate(file())
+			if err != nil || baudRate == last {
+				// The terminal may be closed while it is replaced, try again on the next poll
+				continue
+			}
+
+			last = baudRate
+			onChange(baudRate)
+		}
+	}
+}
@@ -0,0 +1,86 @@
+//go:build linux
+
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package termios
+
+import (
+	"fmt"
+	"os"
+
+	"golang.org/x/sys/unix"
+)
+
+// baudRates maps the CBAUD bits of the control flags to baud rates
+var baudRates = map[uint32]int{ //nolint:gochecknoglobals
+	unix.B50:      50,
+	unix.B75:      75,
+	unix.B110:     110,
+	unix.B134:     134,
+	unix.B150:     150,
+	unix.B200:     200,
+	unix.B300:     300,
+	unix.B600:     600,
+	unix.B1200:    1200,
+	unix.B1800:    1800,
+	unix.B2400:    2400,
+	unix.B4800:    4800,
+	unix.B9600:    9600,
+	unix.B19200:   19200,
+	unix.B38400:   38400,
+	unix.B57600:   57600,
+	unix.B115200:  115200,
+	unix.B230400:  230400,
+	unix.B460800:  460800,
+	unix.B500000:  500000,
+	unix.B576000:  576000,
+	unix.B921600:  921600,
+	unix.B1000000: 1000000,
+	unix.B1152000: 1152000,
+	unix.B1500000: 1500000,
+	unix.B2000000: 2000000,
+	unix.B2500000: 2500000,
+	unix.B3000000: 3000000,
+	unix.B3500000: 3500000,
+	unix.B4000000: 4000000,
+}
+
+// BaudRate returns the output baud rate of a terminal, zero if the line is hung up. For a pseudo terminal the
+// settings of the other side can be read from either side.
+func BaudRate(f *os.File) (int, error) {
+	if f == nil {
+		return 0, os.ErrClosed
+	}
+
+	rc, err := f.SyscallConn()
+	if err != nil {
+		return 0, fmt.Errorf("failed to access terminal: %w", err)
+	}
+
+	var termios *unix.Termios
+	var ioctlErr error
+	if err := rc.Control(func(fd uintptr) {
+		termios, ioctlErr = unix.IoctlGetTermios(int(fd), unix.TCGETS) //nolint:gosec
+	}); err != nil {
+		return 0, fmt.Errorf("failed to access terminal: %w", err)
+	}
+	if ioctlErr != nil {
+		return 0, fmt.Errorf("failed to read terminal settings: %w", ioctlErr)
+	}
+
+	return baudRates[termios.Cflag&unix.CBAUD], nil
+}
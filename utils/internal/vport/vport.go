@@ -0,0 +1,77 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package vport creates the virtual serial ports served by the emulator and the proxy. On Unix a virtual port is a
+// pseudo terminal, optionally linked to a configured name. On Windows it is a named pipe, e.g. \\.\pipe\jumperless,
+// which the jumperless library opens like a serial port.
+package vport
+
+import (
+	"errors"
+	"io"
+	"os"
+)
+
+// Port is a virtual serial port. Reading and writing it serves the client that opened the port by its name.
+type Port struct {
+	served io.ReadWriteCloser // The side served by the emulator or proxy
+	tty    *os.File           // The served pseudo terminal, nil if the platform has none
+	closer func() error       // Closes the client side and removes the link, if any
+	name   string             // The name clients open the port by
+	device string             // The device backing the port, e.g. /dev/pts/3
+}
+
+// Read reads the requests of the client
+func (p *Port) Read(b []byte) (int, error) {
+	return p.served.Read(b) //nolint:wrapcheck
+}
+
+// Write writes to the client
+func (p *Port) Write(b []byte) (int, error) {
+	return p.served.Write(b) //nolint:wrapcheck
+}
+
+// Name returns the name clients open the port by, the configured name if it was linked
+func (p *Port) Name() string {
+	return p.name
+}
+
+// Device returns the device backing the port, e.g. the pseudo terminal a configured name links to
+func (p *Port) Device() string {
+	return p.device
+}
+
+// TTY returns the served pseudo terminal, whose line settings are set by the client, or nil if the platform
+// has none
+func (p *Port) TTY() *os.File {
+	return p.tty
+}
+
+// CloseServed closes the served side only, unblocking pending reads
+func (p *Port) CloseServed() error {
+	return p.served.Close() //nolint:wrapcheck
+}
+
+// Close closes both sides of the port and removes its link. Closing a port whose served side was closed already
+// by CloseServed is not an error.
+func (p *Port) Close() error {
+	err := p.served.Close()
+	if errors.Is(err, os.ErrClosed) {
+		err = nil
+	}
+
+	return errors.Join(err, p.closer())
+}
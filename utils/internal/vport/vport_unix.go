@@ -0,0 +1,79 @@
+//go:build !windows
+
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vport
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"syscall"
+
+	"github.com/creack/pty"
+)
+
+// Open creates a pseudo terminal and links it to name, replacing an existing link. The pseudo terminal is
+// not linked if name is empty, clients open it by its device name instead.
+func Open(name string) (*Port, error) {
+	pseudoTTY, virtualTTY, err := pty.Open()
+	if err != nil {
+		return nil, fmt.Errorf("failed to create pty: %w", err)
+	}
+
+	port := &Port{
+		served: pseudoTTY,
+		tty:    pseudoTTY,
+		closer: virtualTTY.Close,
+		name:   virtualTTY.Name(),
+		device: virtualTTY.Name(),
+	}
+
+	// Ensure non-blocking reads on pseudo TTY, this allows us to implement read timeouts
+	fd := pseudoTTY.Fd()
+	if err := syscall.SetNonblock(int(fd), true); err != nil {
+		_ = port.Close()
+		return nil, fmt.Errorf("failed to set pseudo TTY to non-blocking: %w", err)
+	}
+
+	if name == "" || name == virtualTTY.Name() {
+		return port, nil
+	}
+
+	// Remove existing symlink if it exists
+	if err := os.Remove(name); err != nil && !os.IsNotExist(err) {
+		_ = port.Close()
+		return nil, fmt.Errorf("failed to remove existing virtual port %s: %w", name, err)
+	}
+
+	if err := os.Symlink(virtualTTY.Name(), name); err != nil {
+		_ = port.Close()
+		return nil, fmt.Errorf("failed to create symlink %s -> %s: %w", name, virtualTTY.Name(), err)
+	}
+
+	port.name = name
+	port.closer = func() error {
+		closeErr := virtualTTY.Close()
+		if err := os.Remove(name); err != nil && !os.IsNotExist(err) {
+			return errors.Join(closeErr, fmt.Errorf("failed to remove virtual port %s: %w", name, err))
+		}
+
+		return closeErr
+	}
+
+	return port, nil
+}
@@ -0,0 +1,280 @@
+//go:build windows
+
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vport
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"golang.org/x/sys/windows"
+)
+
+const (
+	// namedPipePrefix is the prefix of the names of local named pipes
+	namedPipePrefix = `\\.\pipe\`
+
+	// pipeBufferSize is the size of the input and output buffers of a named pipe
+	pipeBufferSize = 4096
+
+	// pipeReadTimeout is how long a read waits for a client or its data, so serving can be cancelled
+	pipeReadTimeout = 100 * time.Millisecond
+)
+
+// pipeCounter numbers the named pipes created without a configured name
+var pipeCounter atomic.Int64 //nolint:gochecknoglobals
+
+// Open creates a named pipe served like a virtual serial port. Names that aren't named pipes, such as
+// /tmp/jumperless, are turned into one named after their last element, e.g. \\.\pipe\jumperless, and a pipe
+// named after the process is created if name is empty.
+func Open(name string) (*Port, error) {
+	name = pipeName(name)
+
+	server := &pipeServer{name: name}
+	if err := server.listen(); err != nil {
+		return nil, err
+	}
+
+	return &Port{
+		served: server,
+		closer: func() error { return nil },
+		name:   name,
+		device: name,
+	}, nil
+}
+
+// pipeName returns the named pipe for a configured port name
+func pipeName(name string) string {
+	switch {
+	case strings.HasPrefix(strings.ToLower(name), namedPipePrefix):
+		return name
+	case name == "":
+		return fmt.Sprintf("%sjumperless-%d-%d", namedPipePrefix, os.Getpid(), pipeCounter.Add(1))
+	default:
+		return namedPipePrefix + filepath.Base(name)
+	}
+}
+
+// pipeServer serves a named pipe to one client at a time. Once the client disconnects, a new instance of the
+// pipe is created for the next client. Reads without a connected client time out, writes are discarded.
+type pipeServer struct {
+	name string
+
+	mu         sync.Mutex
+	listening  windows.Handle // The instance waiting for a client, zero once connected
+	event      windows.Handle // Signaled once a client connects to the listening instance
+	overlapped windows.Overlapped
+	client     *os.File // The instance a client is connected to
+	closed     bool
+}
+
+// listen creates an instance of the pipe and waits for a client to connect to it. Must be called with mu held,
+// or before the server is used.
+func (s *pipeServer) listen() error {
+	name, err := windows.UTF16PtrFromString(s.name)
+	if err != nil {
+		return fmt.Errorf("invalid named pipe %s: %w", s.name, err)
+	}
+
+	handle, err := windows.CreateNamedPipe(name,
+		windows.PIPE_ACCESS_DUPLEX|windows.FILE_FLAG_OVERLAPPED|windows.FILE_FLAG_FIRST_PIPE_INSTANCE,
+		windows.PIPE_TYPE_BYTE|windows.PIPE_READMODE_BYTE|windows.PIPE_WAIT|windows.PIPE_REJECT_REMOTE_CLIENTS,
+		1, pipeBufferSize, pipeBufferSize, 0, nil)
+	if err != nil {
+		return fmt.Errorf("failed to create named pipe %s: %w", s.name, err)
+	}
+
+	if s.event == 0 {
+		event, err := windows.CreateEvent(nil, 1, 0, nil)
+		if err != nil {
+			_ = windows.CloseHandle(handle)
+			return fmt.Errorf("failed to create named pipe %s: %w", s.name, err)
+		}
+
+		s.event = event
+	}
+
+	if err := windows.ResetEvent(s.event); err != nil {
+		_ = windows.CloseHandle(handle)
+		return fmt.Errorf("failed to create named pipe %s: %w", s.name, err)
+	}
+
+	s.overlapped = windows.Overlapped{HEvent: s.event}
+	err = windows.ConnectNamedPipe(handle, &s.overlapped)
+	switch {
+	case err == nil, errors.Is(err, windows.ERROR_PIPE_CONNECTED):
+		// A client connected before the pipe waited for it
+		if err := windows.SetEvent(s.event); err != nil {
+			_ = windows.CloseHandle(handle)
+			return fmt.Errorf("failed to connect named pipe %s: %w", s.name, err)
+		}
+	case errors.Is(err, windows.ERROR_IO_PENDING):
+	default:
+		_ = windows.CloseHandle(handle)
+		return fmt.Errorf("failed to connect named pipe %s: %w", s.name, err)
+	}
+
+	s.listening = handle
+
+	return nil
+}
+
+// connected returns the instance a client is connected to, waiting up to the read timeout for a client to
+// connect, or nil if none connected
+func (s *pipeServer) connected() (*os.File, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.closed {
+		return nil, os.ErrClosed
+	}
+
+	if s.client != nil {
+		return s.client, nil
+	}
+
+	if s.listening == 0 {
+		if err := s.listen(); err != nil {
+			return nil, err
+		}
+	}
+
+	event, err := windows.WaitForSingleObject(s.event, uint32(pipeReadTimeout.Milliseconds()))
+	if err != nil {
+		return nil, fmt.Errorf("failed to wait for named pipe client: %w", err)
+	}
+
+	if event == uint32(windows.WAIT_TIMEOUT) {
+		return nil, nil
+	}
+
+	var transferred uint32
+	if err := windows.GetOverlappedResult(s.listening, &s.overlapped, &transferred, false); err != nil &&
+		!errors.Is(err, windows.ERROR_PIPE_CONNECTED) {
+		// The client went away before it was accepted, wait for the next one
+		_ = windows.CloseHandle(s.listening)
+		s.listening = 0
+
+		return nil, nil
+	}
+
+	// The file takes over the handle, which supports deadlines since it was opened for overlapped I/O
+	s.client = os.NewFile(uintptr(s.listening), s.name)
+	s.listening = 0
+
+	return s.client, nil
+}
+
+// disconnect closes the instance of a client that disconnected, the next read creates a new one
+func (s *pipeServer) disconnect(client *os.File) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.client == client {
+		_ = client.Close()
+		s.client = nil
+	}
+}
+
+// Read implements io.Reader, returning io.EOF once the client disconnects and a timeout error if no data was
+// received within the read timeout
+func (s *pipeServer) Read(b []byte) (int, error) {
+	client, err := s.connected()
+	if err != nil {
+		return 0, err
+	}
+
+	if client == nil {
+		return 0, os.ErrDeadlineExceeded
+	}
+
+	if err := client.SetReadDeadline(time.Now().Add(pipeReadTimeout)); err != nil {
+		return 0, fmt.Errorf("failed to set named pipe read deadline: %w", err)
+	}
+
+	n, err := client.Read(b)
+	if isDisconnect(err) {
+		s.disconnect(client)
+		return n, io.EOF
+	}
+
+	return n, err //nolint:wrapcheck
+}
+
+// Write implements io.Writer, discarding the data if no client is connected like a serial port without a reader
+func (s *pipeServer) Write(b []byte) (int, error) {
+	s.mu.Lock()
+	client := s.client
+	s.mu.Unlock()
+
+	if client == nil {
+		return len(b), nil
+	}
+
+	n, err := client.Write(b)
+	if isDisconnect(err) {
+		s.disconnect(client)
+		return len(b), nil
+	}
+
+	return n, err //nolint:wrapcheck
+}
+
+// Close implements io.Closer, closing the pipe and any connected client
+func (s *pipeServer) Close() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.closed {
+		return os.ErrClosed
+	}
+
+	s.closed = true
+
+	errs := []error{}
+	if s.client != nil {
+		errs = append(errs, s.client.Close())
+		s.client = nil
+	}
+
+	if s.listening != 0 {
+		_ = windows.CancelIoEx(s.listening, &s.overlapped)
+		errs = append(errs, windows.CloseHandle(s.listening))
+		s.listening = 0
+	}
+
+	if s.event != 0 {
+		errs = append(errs, windows.CloseHandle(s.event))
+		s.event = 0
+	}
+
+	return errors.Join(errs...)
+}
+
+// isDisconnect returns true if err means that the client closed its end of the pipe
+func isDisconnect(err error) bool {
+	return errors.Is(err, io.EOF) || errors.Is(err, windows.ERROR_BROKEN_PIPE) ||
+		errors.Is(err, windows.ERROR_PIPE_NOT_CONNECTED) || errors.Is(err, windows.ERROR_NO_DATA)
+}
@@ -0,0 +1,71 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller_test
+
+import (
+	"testing"
+
+	. "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/ptr"
+
+	jumperlessv5alpha1 "github.com/detiber/k8s-jumperless/api/v5alpha1"
+)
+
+func TestReconcileBootstrapsFactoryFreshJumperless(t *testing.T) {
+	g := NewWithT(t)
+
+	h := newHarness(t, factoryFreshConfig(t))
+
+	j := &jumperlessv5alpha1.Jumperless{
+		ObjectMeta: metav1.ObjectMeta{Name: "factory-fresh", Namespace: "default"},
+		Spec: jumperlessv5alpha1.JumperlessSpec{
+			Host: jumperlessv5alpha1.JumperlessHost{
+				Local: &jumperlessv5alpha1.JumperlessHostLocal{Port: ptr.To(h.emulator.GetPortName())},
+			},
+			Bootstrap: &jumperlessv5alpha1.Bootstrap{
+				Profile: "lab-baseline",
+				DACS:    []jumperlessv5alpha1.DAC{{Channel: "TOP_RAIL", Voltage: "5V", Save: ptr.To(true)}},
+				Display: &jumperlessv5alpha1.Display{Brightness: ptr.To[int32](25)},
+			},
+		},
+	}
+	h.create(j)
+
+	current := h.reconcileUntilReady(j)
+	g.Expect(current.Status.Bootstrap).NotTo(BeNil())
+	g.Expect(current.Status.Bootstrap.Profile).To(Equal("lab-baseline"))
+	g.Expect(current.Status.Bootstrap.Result).To(Equal(jumperlessv5alpha1.BootstrapApplied))
+
+	// The baseline was written to the device config rather than only reported in the status
+	g.Expect(h.emulator.GetState()).To(And(
+		HaveKeyWithValue("config.dacs.top_rail", "5.00"),
+		HaveKeyWithValue("config.display.led_brightness", "25"),
+	))
+
+	// A second profile finds the device provisioned and leaves it alone
+	current.Spec.Bootstrap.Profile = "lab-baseline-v2"
+	current.Spec.Bootstrap.DACS[0].Voltage = "3.3V"
+	g.Expect(h.client.Update(t.Context(), current)).To(Succeed())
+
+	g.Eventually(func(g Gomega) {
+		current = h.reconcileUntilReady(j)
+		g.Expect(current.Status.Bootstrap.Profile).To(Equal("lab-baseline-v2"))
+	}).Should(Succeed())
+	g.Expect(current.Status.Bootstrap.Result).To(Equal(jumperlessv5alpha1.BootstrapSkipped))
+	g.Expect(h.emulator.GetState()).To(HaveKeyWithValue("config.dacs.top_rail", "5.00"))
+}
@@ -0,0 +1,213 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller_test
+
+import (
+	"context"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	. "github.com/onsi/gomega"
+	"github.com/spf13/viper"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/runtime"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/envtest"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	jumperlessv5alpha1 "github.com/detiber/k8s-jumperless/api/v5alpha1"
+	"github.com/detiber/k8s-jumperless/internal/controller"
+	"github.com/detiber/k8s-jumperless/jumperless"
+	"github.com/detiber/k8s-jumperless/utils/internal/emulator"
+	emulatorConfig "github.com/detiber/k8s-jumperless/utils/internal/emulator/config"
+)
+
+// rootDir is the root of the repository, relative to this package
+var rootDir = filepath.Join("..", "..", "..") //nolint:gochecknoglobals
+
+// harness runs the Jumperless reconciler under envtest against an emulator started in the test process,
+// exercising the controller the way it drives real hardware
+type harness struct {
+	t          *testing.T
+	client     client.Client
+	reconciler *controller.JumperlessReconciler
+	recorder   *record.FakeRecorder
+	emulator   *emulator.Emulator
+}
+
+// newHarness starts envtest with the CRDs installed and an emulator serving the config on a virtual serial
+// port, both are stopped when the test ends. The test is skipped if the envtest binaries are not installed.
+func newHarness(t *testing.T, emuConfig *emulatorConfig.EmulatorConfig) *harness {
+	t.Helper()
+	g := NewWithT(t)
+
+	assets := envtestAssets()
+	if assets == "" {
+		t.Skip("envtest binaries not found, run make setup-envtest or set KUBEBUILDER_ASSETS")
+	}
+
+	scheme := runtime.NewScheme()
+	g.Expect(clientgoscheme.AddToScheme(scheme)).To(Succeed())
+	g.Expect(jumperlessv5alpha1.AddToScheme(scheme)).To(Succeed())
+
+	testEnv := &envtest.Environment{
+		CRDDirectoryPaths:     []string{filepath.Join(rootDir, "config", "crd", "bases")},
+		ErrorIfCRDPathMissing: true,
+		BinaryAssetsDirectory: assets,
+		Scheme:                scheme,
+	}
+
+	cfg, err := testEnv.Start()
+	g.Expect(err).NotTo(HaveOccurred())
+	t.Cleanup(func() {
+		if err := testEnv.Stop(); err != nil {
+			t.Errorf("failed to stop envtest: %v", err)
+		}
+	})
+
+	k8sClient, err := client.New(cfg, client.Options{Scheme: scheme})
+	g.Expect(err).NotTo(HaveOccurred())
+
+	e, err := emulator.New(emuConfig, slog.New(slog.NewTextHandler(t.Output(), nil)))
+	g.Expect(err).NotTo(HaveOccurred())
+
+	ctx, cancel := context.WithCancel(t.Context())
+	g.Expect(e.Start(ctx)).To(Succeed())
+	t.Cleanup(func() {
+		cancel()
+		if err := e.Stop(); err != nil {
+			t.Errorf("failed to stop emulator: %v", err)
+		}
+	})
+
+	recorder := record.NewFakeRecorder(100)
+
+	return &harness{
+		t:      t,
+		client: k8sClient,
+		reconciler: &controller.JumperlessReconciler{
+			Client:   k8sClient,
+			Scheme:   scheme,
+			Ports:    jumperless.NewPortManager(),
+			Recorder: recorder,
+		},
+		recorder: recorder,
+		emulator: e,
+	}
+}
+
+// envtestAssets returns the directory of the envtest binaries, from KUBEBUILDER_ASSETS or installed by
+// make setup-envtest, or an empty string if they are not installed
+func envtestAssets() string {
+	if assets := os.Getenv("KUBEBUILDER_ASSETS"); assets != "" {
+		return assets
+	}
+
+	basePath := filepath.Join(rootDir, "bin", "k8s")
+	entries, err := os.ReadDir(basePath)
+	if err != nil {
+		return ""
+	}
+	for _, entry := range entries {
+		if entry.IsDir() {
+			return filepath.Join(basePath, entry.Name())
+		}
+	}
+
+	return ""
+}
+
+// deviceConfig returns an emulator config answering the firmware query, config dump and nets with the
+// recorded responses of the example config, and the DAC reads with the jumperless engine in the given state
+func deviceConfig(t *testing.T, hardware *emulatorConfig.HardwareConfig) *emulatorConfig.EmulatorConfig {
+	t.Helper()
+	g := NewWithT(t)
+
+	v := viper.New()
+	v.SetConfigFile(filepath.Join(rootDir, "examples", "jumperless-utils.yml"))
+	g.Expect(v.ReadInConfig()).To(Succeed())
+
+	c := emulatorConfig.NewFromViper(v)
+	c.VirtualPort = filepath.Join(t.TempDir(), "jumperless")
+	c.ExtraPorts = nil
+	c.Listen = ""
+	c.Exec = ""
+	c.Seed = 1
+	c.Engine = emulator.EngineJumperless
+	c.Hardware = hardware
+
+	// The engine answers the DAC reads from the hardware state instead of the recorded responses
+	c.Mappings = slices.DeleteFunc(c.Mappings, func(m emulatorConfig.RequestResponse) bool {
+		return strings.HasPrefix(m.Request, ">dac_")
+	})
+
+	return c
+}
+
+// factoryFreshConfig returns the emulator config of the factory-fresh fixture, emulating a device that was never
+// provisioned
+func factoryFreshConfig(t *testing.T) *emulatorConfig.EmulatorConfig {
+	t.Helper()
+	g := NewWithT(t)
+
+	v := viper.New()
+	v.SetConfigFile(filepath.Join(rootDir, "examples", "emulator-factory-fresh.yml"))
+	g.Expect(v.ReadInConfig()).To(Succeed())
+
+	c := emulatorConfig.NewFromViper(v)
+	c.VirtualPort = filepath.Join(t.TempDir(), "jumperless")
+
+	return c
+}
+
+// create creates the Jumperless resource, deleting it when the test ends
+func (h *harness) create(j *jumperlessv5alpha1.Jumperless) {
+	h.t.Helper()
+	g := NewWithT(h.t)
+
+	g.Expect(h.client.Create(h.t.Context(), j)).To(Succeed())
+	h.t.Cleanup(func() {
+		if err := h.client.Delete(context.WithoutCancel(h.t.Context()), j); client.IgnoreNotFound(err) != nil {
+			h.t.Errorf("failed to delete Jumperless: %v", err)
+		}
+	})
+}
+
+// reconcileUntilReady reconciles the resource until it is ready, since the first reconciles only initialize
+// the conditions, and returns the resource
+func (h *harness) reconcileUntilReady(j *jumperlessv5alpha1.Jumperless) *jumperlessv5alpha1.Jumperless {
+	h.t.Helper()
+	g := NewWithT(h.t)
+
+	current := &jumperlessv5alpha1.Jumperless{}
+	g.Eventually(func(g Gomega) {
+		_, err := h.reconciler.Reconcile(h.t.Context(), reconcile.Request{NamespacedName: client.ObjectKeyFromObject(j)})
+		g.Expect(err).NotTo(HaveOccurred())
+
+		g.Expect(h.client.Get(h.t.Context(), client.ObjectKeyFromObject(j), current)).To(Succeed())
+		g.Expect(meta.IsStatusConditionTrue(current.Status.Conditions, jumperlessv5alpha1.ConditionReady)).To(BeTrue())
+	}).WithTimeout(time.Minute).WithPolling(100 * time.Millisecond).Should(Succeed())
+
+	return current
+}
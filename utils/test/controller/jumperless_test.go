@@ -0,0 +1,107 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller_test
+
+import (
+	"testing"
+
+	. "github.com/onsi/gomega"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/ptr"
+
+	jumperlessv5alpha1 "github.com/detiber/k8s-jumperless/api/v5alpha1"
+	emulatorConfig "github.com/detiber/k8s-jumperless/utils/internal/emulator/config"
+)
+
+func TestReconcileLocalJumperless(t *testing.T) {
+	g := NewWithT(t)
+
+	h := newHarness(t, deviceConfig(t, &emulatorConfig.HardwareConfig{
+		DACs: []emulatorConfig.HardwareDAC{
+			{Channel: "DAC0", Voltage: "3.3V"},
+			{Channel: "DAC1", Voltage: "-1.5V"},
+			{Channel: "TOP_RAIL", Voltage: "5V"},
+		},
+	}))
+
+	j := &jumperlessv5alpha1.Jumperless{
+		ObjectMeta: metav1.ObjectMeta{Name: "emulated", Namespace: "default"},
+		Spec: jumperlessv5alpha1.JumperlessSpec{
+			Host: jumperlessv5alpha1.JumperlessHost{
+				Local: &jumperlessv5alpha1.JumperlessHostLocal{Port: ptr.To(h.emulator.GetPortName())},
+			},
+		},
+	}
+	h.create(j)
+
+	current := h.reconcileUntilReady(j)
+	status := current.Status
+
+	g.Expect(status.LocalPort).To(Equal(ptr.To(h.emulator.GetPortName())))
+	g.Expect(status.FirmwareVersion).To(Equal(ptr.To("5.3.1.0")))
+	g.Expect(current.GetLabels()).To(HaveKeyWithValue(jumperlessv5alpha1.FirmwareVersionLabel, "5.3.1.0"))
+
+	g.Expect(status.DACS).To(ConsistOf(
+		jumperlessv5alpha1.DACStatus{Channel: "DAC0", Voltage: "3.30V", Millivolts: ptr.To[int32](3300)},
+		jumperlessv5alpha1.DACStatus{Channel: "DAC1", Voltage: "-1.50V", Millivolts: ptr.To[int32](-1500)},
+		jumperlessv5alpha1.DACStatus{Channel: "TOP_RAIL", Voltage: "5.00V", Millivolts: ptr.To[int32](5000)},
+		jumperlessv5alpha1.DACStatus{Channel: "BOTTOM_RAIL", Voltage: "0.00V", Millivolts: ptr.To[int32](0)},
+	))
+
+	g.Expect(status.Nets).NotTo(BeEmpty())
+
+	generation, ok := status.GetConfigEntry("hardware", "generation")
+	g.Expect(ok).To(BeTrue())
+	g.Expect(generation).To(Equal("5"))
+	g.Expect(status.Device).NotTo(BeNil())
+	g.Expect(status.Device.Generation).To(Equal(ptr.To("5")))
+
+	g.Expect(meta.IsStatusConditionFalse(status.Conditions, jumperlessv5alpha1.ConditionDegraded)).To(BeTrue())
+	g.Expect(meta.IsStatusConditionFalse(status.Conditions,
+		jumperlessv5alpha1.ConditionConfigSchemaChanged)).To(BeTrue())
+	g.Expect(status.ConfigSchemaHash).NotTo(BeNil())
+}
+
+func TestReconcileLocalJumperlessWritesDACs(t *testing.T) {
+	g := NewWithT(t)
+
+	h := newHarness(t, deviceConfig(t, &emulatorConfig.HardwareConfig{
+		DACs: []emulatorConfig.HardwareDAC{{Channel: "DAC0", Voltage: "1V"}},
+	}))
+
+	j := &jumperlessv5alpha1.Jumperless{
+		ObjectMeta: metav1.ObjectMeta{Name: "emulated-dacs", Namespace: "default"},
+		Spec: jumperlessv5alpha1.JumperlessSpec{
+			Host: jumperlessv5alpha1.JumperlessHost{
+				Local: &jumperlessv5alpha1.JumperlessHostLocal{Port: ptr.To(h.emulator.GetPortName())},
+			},
+			DACS: []jumperlessv5alpha1.DAC{{Channel: "DAC0", Voltage: "2.5V", Save: ptr.To(false)}},
+		},
+	}
+	h.create(j)
+
+	current := h.reconcileUntilReady(j)
+	g.Expect(current.Status.DACS).To(ContainElement(jumperlessv5alpha1.DACStatus{
+		Channel:    "DAC0",
+		Voltage:    "2.50V",
+		Millivolts: ptr.To[int32](2500),
+	}))
+
+	// The voltage was written to the device rather than only reported in the status
+	g.Expect(h.emulator.GetState()).To(HaveKeyWithValue("dac0", "2.50"))
+}